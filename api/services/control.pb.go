@@ -81,7 +81,67 @@ type BuildRequest struct {
 	// encrypted indicated the build args are encrypted
 	Encrypted bool `protobuf:"varint,13,opt,name=encrypted,proto3" json:"encrypted,omitempty"`
 	// format is the built image format
-	Format               string   `protobuf:"bytes,14,opt,name=format,proto3" json:"format,omitempty"`
+	Format string `protobuf:"bytes,14,opt,name=format,proto3" json:"format,omitempty"`
+	// resumeBuildID is the buildID of an interrupted build to resume from
+	ResumeBuildID string `protobuf:"bytes,15,opt,name=resumeBuildID,proto3" json:"resumeBuildID,omitempty"`
+	// builder is the name of the builder instance carrying build defaults
+	Builder string `protobuf:"bytes,16,opt,name=builder,proto3" json:"builder,omitempty"`
+	// logLevel is the log verbosity requested for this session only
+	LogLevel string `protobuf:"bytes,17,opt,name=logLevel,proto3" json:"logLevel,omitempty"`
+	// graphFormat requests the build graph in "json" or "dot" format
+	GraphFormat string `protobuf:"bytes,18,opt,name=graphFormat,proto3" json:"graphFormat,omitempty"`
+	// platform is the target platform of the build, like linux/arm64
+	Platform string `protobuf:"bytes,19,opt,name=platform,proto3" json:"platform,omitempty"`
+	// noCache skips build cache lookups, the cache is still repopulated
+	NoCache bool `protobuf:"varint,20,opt,name=noCache,proto3" json:"noCache,omitempty"`
+	// cacheFrom are images or cache repositories consulted as cache sources
+	CacheFrom []string `protobuf:"bytes,21,rep,name=cacheFrom,proto3" json:"cacheFrom,omitempty"`
+	// cacheTo is the repository the cache entries of this build are pushed to
+	CacheTo string `protobuf:"bytes,22,opt,name=cacheTo,proto3" json:"cacheTo,omitempty"`
+	// validateOutput checks the built image against the OCI image spec
+	ValidateOutput bool `protobuf:"varint,23,opt,name=validateOutput,proto3" json:"validateOutput,omitempty"`
+	// sensitiveArgs are the build-arg names whose values are redacted
+	// from logs, the status stream and persisted build records
+	SensitiveArgs []string `protobuf:"bytes,24,rep,name=sensitiveArgs,proto3" json:"sensitiveArgs,omitempty"`
+	// compression is the layer compression of exported images, one of
+	// gzip, zstd, zstd:chunked or none, empty keeps the default
+	Compression string `protobuf:"bytes,25,opt,name=compression,proto3" json:"compression,omitempty"`
+	// parallelCopy runs consecutive independent COPY/ADD commands of a
+	// stage concurrently
+	ParallelCopy bool `protobuf:"varint,26,opt,name=parallelCopy,proto3" json:"parallelCopy,omitempty"`
+	// pullPolicyStrict rejects base images the signature policy would
+	// accept without any signature check
+	PullPolicyStrict bool `protobuf:"varint,27,opt,name=pullPolicyStrict,proto3" json:"pullPolicyStrict,omitempty"`
+	// logTimestamps prefixes each log line with a timestamp and
+	// reports the duration of each finished step
+	LogTimestamps bool `protobuf:"varint,28,opt,name=logTimestamps,proto3" json:"logTimestamps,omitempty"`
+	// userns runs RUN commands in a user namespace, "auto" derives the
+	// id maps from the subordinate ranges of user "containers"
+	Userns string `protobuf:"bytes,29,opt,name=userns,proto3" json:"userns,omitempty"`
+	// usernsUidMap are containerID:hostID:size triples mapping uids
+	// into the user namespace
+	UsernsUidMap []string `protobuf:"bytes,30,rep,name=usernsUidMap,proto3" json:"usernsUidMap,omitempty"`
+	// usernsGidMap are containerID:hostID:size triples mapping gids
+	// into the user namespace, the uid map is reused when empty
+	UsernsGidMap []string `protobuf:"bytes,31,rep,name=usernsGidMap,proto3" json:"usernsGidMap,omitempty"`
+	// memory bounds the memory of each RUN container in bytes, zero
+	// keeps the container unlimited
+	Memory int64 `protobuf:"varint,32,opt,name=memory,proto3" json:"memory,omitempty"`
+	// cpuQuota is the cpu quota of each RUN container in microseconds
+	// per scheduler period, zero keeps the container unlimited
+	CpuQuota int64 `protobuf:"varint,33,opt,name=cpuQuota,proto3" json:"cpuQuota,omitempty"`
+	// cpus bounds each RUN container to that many cpus, zero keeps
+	// the container unlimited
+	Cpus float64 `protobuf:"fixed64,34,opt,name=cpus,proto3" json:"cpus,omitempty"`
+	// pidsLimit bounds the processes of each RUN container, zero
+	// keeps the container unlimited
+	PidsLimit int64 `protobuf:"varint,35,opt,name=pidsLimit,proto3" json:"pidsLimit,omitempty"`
+	// storageOpts are per-build storage driver options like size=20G,
+	// bounding the read-write layer via the filesystem project quota
+	StorageOpts []string `protobuf:"bytes,36,rep,name=storageOpts,proto3" json:"storageOpts,omitempty"`
+	// timeout fails the build after that long, a duration like 1h30m,
+	// empty uses the daemon default and zero is rejected
+	Timeout              string   `protobuf:"bytes,37,opt,name=timeout,proto3" json:"timeout,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -209,6 +269,167 @@ func (m *BuildRequest) GetFormat() string {
 	return ""
 }
 
+func (m *BuildRequest) GetResumeBuildID() string {
+	if m != nil {
+		return m.ResumeBuildID
+	}
+	return ""
+}
+
+func (m *BuildRequest) GetBuilder() string {
+	if m != nil {
+		return m.Builder
+	}
+	return ""
+}
+
+func (m *BuildRequest) GetLogLevel() string {
+	if m != nil {
+		return m.LogLevel
+	}
+	return ""
+}
+
+func (m *BuildRequest) GetGraphFormat() string {
+	if m != nil {
+		return m.GraphFormat
+	}
+	return ""
+}
+
+func (m *BuildRequest) GetPlatform() string {
+	if m != nil {
+		return m.Platform
+	}
+	return ""
+}
+
+func (m *BuildRequest) GetNoCache() bool {
+	if m != nil {
+		return m.NoCache
+	}
+	return false
+}
+
+func (m *BuildRequest) GetCacheFrom() []string {
+	if m != nil {
+		return m.CacheFrom
+	}
+	return nil
+}
+
+func (m *BuildRequest) GetCacheTo() string {
+	if m != nil {
+		return m.CacheTo
+	}
+	return ""
+}
+
+func (m *BuildRequest) GetValidateOutput() bool {
+	if m != nil {
+		return m.ValidateOutput
+	}
+	return false
+}
+
+func (m *BuildRequest) GetSensitiveArgs() []string {
+	if m != nil {
+		return m.SensitiveArgs
+	}
+	return nil
+}
+
+func (m *BuildRequest) GetCompression() string {
+	if m != nil {
+		return m.Compression
+	}
+	return ""
+}
+
+func (m *BuildRequest) GetParallelCopy() bool {
+	if m != nil {
+		return m.ParallelCopy
+	}
+	return false
+}
+
+func (m *BuildRequest) GetPullPolicyStrict() bool {
+	if m != nil {
+		return m.PullPolicyStrict
+	}
+	return false
+}
+
+func (m *BuildRequest) GetLogTimestamps() bool {
+	if m != nil {
+		return m.LogTimestamps
+	}
+	return false
+}
+
+func (m *BuildRequest) GetUserns() string {
+	if m != nil {
+		return m.Userns
+	}
+	return ""
+}
+
+func (m *BuildRequest) GetUsernsUidMap() []string {
+	if m != nil {
+		return m.UsernsUidMap
+	}
+	return nil
+}
+
+func (m *BuildRequest) GetUsernsGidMap() []string {
+	if m != nil {
+		return m.UsernsGidMap
+	}
+	return nil
+}
+
+func (m *BuildRequest) GetMemory() int64 {
+	if m != nil {
+		return m.Memory
+	}
+	return 0
+}
+
+func (m *BuildRequest) GetCpuQuota() int64 {
+	if m != nil {
+		return m.CpuQuota
+	}
+	return 0
+}
+
+func (m *BuildRequest) GetCpus() float64 {
+	if m != nil {
+		return m.Cpus
+	}
+	return 0
+}
+
+func (m *BuildRequest) GetPidsLimit() int64 {
+	if m != nil {
+		return m.PidsLimit
+	}
+	return 0
+}
+
+func (m *BuildRequest) GetStorageOpts() []string {
+	if m != nil {
+		return m.StorageOpts
+	}
+	return nil
+}
+
+func (m *BuildRequest) GetTimeout() string {
+	if m != nil {
+		return m.Timeout
+	}
+	return ""
+}
+
 type ImportRequest struct {
 	// importID is the unique ID for each time save
 	// also is the part to construct tempory path to
@@ -348,7 +569,9 @@ func (m *BuildStatic) GetBuildTime() *types.Timestamp {
 
 type BuildResponse struct {
 	// imageID is the ID of built image
-	ImageID              string   `protobuf:"bytes,1,opt,name=imageID,proto3" json:"imageID,omitempty"`
+	ImageID string `protobuf:"bytes,1,opt,name=imageID,proto3" json:"imageID,omitempty"`
+	// buildGraph is the rendered build graph when requested
+	BuildGraph           string   `protobuf:"bytes,2,opt,name=buildGraph,proto3" json:"buildGraph,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -385,9 +608,22 @@ func (m *BuildResponse) GetImageID() string {
 	return ""
 }
 
+func (m *BuildResponse) GetBuildGraph() string {
+	if m != nil {
+		return m.BuildGraph
+	}
+	return ""
+}
+
 type StatusRequest struct {
 	// buildID is an unique id for this building process, same with BuildRequest
-	BuildID              string   `protobuf:"bytes,1,opt,name=buildID,proto3" json:"buildID,omitempty"`
+	BuildID string `protobuf:"bytes,1,opt,name=buildID,proto3" json:"buildID,omitempty"`
+	// startOffset is the number of log lines the client already
+	// consumed, a reconnecting client resumes the stream from there
+	StartOffset int64 `protobuf:"varint,2,opt,name=startOffset,proto3" json:"startOffset,omitempty"`
+	// follow keeps the stream open for new lines until the build
+	// finishes, off sends the buffered lines and ends the stream
+	Follow               bool     `protobuf:"varint,3,opt,name=follow,proto3" json:"follow,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -424,6 +660,20 @@ func (m *StatusRequest) GetBuildID() string {
 	return ""
 }
 
+func (m *StatusRequest) GetStartOffset() int64 {
+	if m != nil {
+		return m.StartOffset
+	}
+	return 0
+}
+
+func (m *StatusRequest) GetFollow() bool {
+	if m != nil {
+		return m.Follow
+	}
+	return false
+}
+
 type StatusResponse struct {
 	// content pipes the image building process log back to client
 	Content              string   `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
@@ -465,7 +715,22 @@ func (m *StatusResponse) GetContent() string {
 
 type ListRequest struct {
 	// imageName lists specific images with imageName
-	ImageName            string   `protobuf:"bytes,1,opt,name=imageName,proto3" json:"imageName,omitempty"`
+	ImageName string `protobuf:"bytes,1,opt,name=imageName,proto3" json:"imageName,omitempty"`
+	// pageSize is the maximum number of entries of one response, zero
+	// returns everything in a single response
+	PageSize int32 `protobuf:"varint,2,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	// pageToken is the offset to continue a paginated listing from,
+	// taken from the nextPageToken of the previous response
+	PageToken int32 `protobuf:"varint,3,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	// sortBy orders the listing by created, name or size, empty sorts
+	// by created
+	SortBy string `protobuf:"bytes,4,opt,name=sortBy,proto3" json:"sortBy,omitempty"`
+	// exactTime asks for RFC3339 creation times instead of the relative
+	// times shown by default
+	ExactTime bool `protobuf:"varint,5,opt,name=exactTime,proto3" json:"exactTime,omitempty"`
+	// filter restricts the listing to matching images, each entry looks
+	// like meta.<key>=<value> and matches the user metadata of an image
+	Filter               []string `protobuf:"bytes,6,rep,name=filter,proto3" json:"filter,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -502,6 +767,41 @@ func (m *ListRequest) GetImageName() string {
 	return ""
 }
 
+func (m *ListRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListRequest) GetPageToken() int32 {
+	if m != nil {
+		return m.PageToken
+	}
+	return 0
+}
+
+func (m *ListRequest) GetSortBy() string {
+	if m != nil {
+		return m.SortBy
+	}
+	return ""
+}
+
+func (m *ListRequest) GetExactTime() bool {
+	if m != nil {
+		return m.ExactTime
+	}
+	return false
+}
+
+func (m *ListRequest) GetFilter() []string {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
 type TagRequest struct {
 	// image refers to the image to be tagged
 	Image string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
@@ -552,10 +852,13 @@ func (m *TagRequest) GetTag() string {
 
 type ListResponse struct {
 	// ImageInfo carries the basic info of an image
-	Images               []*ListResponse_ImageInfo `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
-	XXX_unrecognized     []byte                    `json:"-"`
-	XXX_sizecache        int32                     `json:"-"`
+	Images []*ListResponse_ImageInfo `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+	// nextPageToken continues a paginated listing when passed as the
+	// pageToken of the next request, zero means the listing is complete
+	NextPageToken        int32    `protobuf:"varint,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ListResponse) Reset()         { *m = ListResponse{} }
@@ -589,12 +892,22 @@ func (m *ListResponse) GetImages() []*ListResponse_ImageInfo {
 	return nil
 }
 
+func (m *ListResponse) GetNextPageToken() int32 {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return 0
+}
+
 type ListResponse_ImageInfo struct {
 	Repository           string   `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
 	Tag                  string   `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
 	Id                   string   `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
 	Created              string   `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
 	Size_                string   `protobuf:"bytes,5,opt,name=size,proto3" json:"size,omitempty"`
+	UniqueSize           string   `protobuf:"bytes,6,opt,name=uniqueSize,proto3" json:"uniqueSize,omitempty"`
+	SharedSize           string   `protobuf:"bytes,7,opt,name=sharedSize,proto3" json:"sharedSize,omitempty"`
+	Digest               string   `protobuf:"bytes,8,opt,name=digest,proto3" json:"digest,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -659,6 +972,27 @@ func (m *ListResponse_ImageInfo) GetSize_() string {
 	return ""
 }
 
+func (m *ListResponse_ImageInfo) GetUniqueSize() string {
+	if m != nil {
+		return m.UniqueSize
+	}
+	return ""
+}
+
+func (m *ListResponse_ImageInfo) GetSharedSize() string {
+	if m != nil {
+		return m.SharedSize
+	}
+	return ""
+}
+
+func (m *ListResponse_ImageInfo) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
 type VersionResponse struct {
 	// version is isula-builder version
 	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
@@ -832,10 +1166,12 @@ func (m *RemoveResponse) GetLayerMessage() string {
 
 type HealthCheckResponse struct {
 	// status is the health status of isula-builder
-	Status               HealthCheckResponse_ServingStatus `protobuf:"varint,1,opt,name=status,proto3,enum=isula.build.v1.HealthCheckResponse_ServingStatus" json:"status,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                          `json:"-"`
-	XXX_unrecognized     []byte                            `json:"-"`
-	XXX_sizecache        int32                             `json:"-"`
+	Status HealthCheckResponse_ServingStatus `protobuf:"varint,1,opt,name=status,proto3,enum=isula.build.v1.HealthCheckResponse_ServingStatus" json:"status,omitempty"`
+	// components carries the readiness of the daemon components
+	Components           []*HealthCheckResponse_ComponentStatus `protobuf:"bytes,2,rep,name=components,proto3" json:"components,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                               `json:"-"`
+	XXX_unrecognized     []byte                                 `json:"-"`
+	XXX_sizecache        int32                                  `json:"-"`
 }
 
 func (m *HealthCheckResponse) Reset()         { *m = HealthCheckResponse{} }
@@ -869,6 +1205,70 @@ func (m *HealthCheckResponse) GetStatus() HealthCheckResponse_ServingStatus {
 	return HealthCheckResponse_UNKNOWN
 }
 
+func (m *HealthCheckResponse) GetComponents() []*HealthCheckResponse_ComponentStatus {
+	if m != nil {
+		return m.Components
+	}
+	return nil
+}
+
+type HealthCheckResponse_ComponentStatus struct {
+	// name is the name of the daemon component
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// ready tells whether the component is ready to serve
+	Ready bool `protobuf:"varint,2,opt,name=ready,proto3" json:"ready,omitempty"`
+	// detail carries a human readable state description
+	Detail               string   `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HealthCheckResponse_ComponentStatus) Reset()         { *m = HealthCheckResponse_ComponentStatus{} }
+func (m *HealthCheckResponse_ComponentStatus) String() string { return proto.CompactTextString(m) }
+func (*HealthCheckResponse_ComponentStatus) ProtoMessage()    {}
+func (*HealthCheckResponse_ComponentStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{13, 0}
+}
+func (m *HealthCheckResponse_ComponentStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HealthCheckResponse_ComponentStatus.Unmarshal(m, b)
+}
+func (m *HealthCheckResponse_ComponentStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HealthCheckResponse_ComponentStatus.Marshal(b, m, deterministic)
+}
+func (m *HealthCheckResponse_ComponentStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HealthCheckResponse_ComponentStatus.Merge(m, src)
+}
+func (m *HealthCheckResponse_ComponentStatus) XXX_Size() int {
+	return xxx_messageInfo_HealthCheckResponse_ComponentStatus.Size(m)
+}
+func (m *HealthCheckResponse_ComponentStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_HealthCheckResponse_ComponentStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HealthCheckResponse_ComponentStatus proto.InternalMessageInfo
+
+func (m *HealthCheckResponse_ComponentStatus) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *HealthCheckResponse_ComponentStatus) GetReady() bool {
+	if m != nil {
+		return m.Ready
+	}
+	return false
+}
+
+func (m *HealthCheckResponse_ComponentStatus) GetDetail() string {
+	if m != nil {
+		return m.Detail
+	}
+	return ""
+}
+
 type LoginRequest struct {
 	// server is registry address will login
 	Server string `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
@@ -1060,10 +1460,16 @@ type LoadRequest struct {
 	// store transport file
 	LoadID string `protobuf:"bytes,2,opt,name=loadID,proto3" json:"loadID,omitempty"`
 	// SeparatorLoad is the info to load separated image
-	Sep                  *SeparatorLoad `protobuf:"bytes,3,opt,name=sep,proto3" json:"sep,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	Sep *SeparatorLoad `protobuf:"bytes,3,opt,name=sep,proto3" json:"sep,omitempty"`
+	// keepPartial keeps the already loaded images when loading
+	// an archive with several images fails halfway
+	KeepPartial bool `protobuf:"varint,4,opt,name=keepPartial,proto3" json:"keepPartial,omitempty"`
+	// name renames the loaded image, only an archive holding exactly
+	// one image can be renamed
+	Name                 string   `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *LoadRequest) Reset()         { *m = LoadRequest{} }
@@ -1111,6 +1517,20 @@ func (m *LoadRequest) GetSep() *SeparatorLoad {
 	return nil
 }
 
+func (m *LoadRequest) GetKeepPartial() bool {
+	if m != nil {
+		return m.KeepPartial
+	}
+	return false
+}
+
+func (m *LoadRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
 type SeparatorLoad struct {
 	// app is application image name
 	App string `protobuf:"bytes,1,opt,name=app,proto3" json:"app,omitempty"`
@@ -1240,7 +1660,11 @@ type PushRequest struct {
 	// imageName contains repository and tag
 	ImageName string `protobuf:"bytes,2,opt,name=imageName,proto3" json:"imageName,omitempty"`
 	// format is the format of image that pushed to registry, for now support docker and oci
-	Format               string   `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	Format string `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	// attach is the path of an artifact (SBOM, signature...) attached to the pushed image
+	Attach string `protobuf:"bytes,4,opt,name=attach,proto3" json:"attach,omitempty"`
+	// artifactType is the OCI artifact type of the attached artifact
+	ArtifactType         string   `protobuf:"bytes,5,opt,name=artifactType,proto3" json:"artifactType,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1291,6 +1715,20 @@ func (m *PushRequest) GetFormat() string {
 	return ""
 }
 
+func (m *PushRequest) GetAttach() string {
+	if m != nil {
+		return m.Attach
+	}
+	return ""
+}
+
+func (m *PushRequest) GetArtifactType() string {
+	if m != nil {
+		return m.ArtifactType
+	}
+	return ""
+}
+
 type PushResponse struct {
 	// response is server's response to client push request
 	Response             string   `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
@@ -1429,10 +1867,24 @@ type SaveRequest struct {
 	// format is the format of image saved to archive file, such as docker-archive, oci-archive
 	Format string `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`
 	// SeparatorSave is the info to save separated image
-	Sep                  *SeparatorSave `protobuf:"bytes,5,opt,name=sep,proto3" json:"sep,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	Sep *SeparatorSave `protobuf:"bytes,5,opt,name=sep,proto3" json:"sep,omitempty"`
+	// annotations are key=value pairs added to the saved manifest, oci format only
+	Annotations []string `protobuf:"bytes,6,rep,name=annotations,proto3" json:"annotations,omitempty"`
+	// os overrides the operating system recorded in the saved image config
+	Os string `protobuf:"bytes,7,opt,name=os,proto3" json:"os,omitempty"`
+	// arch overrides the architecture recorded in the saved image config
+	Arch string `protobuf:"bytes,8,opt,name=arch,proto3" json:"arch,omitempty"`
+	// variant overrides the architecture variant recorded in the saved image config
+	Variant string `protobuf:"bytes,9,opt,name=variant,proto3" json:"variant,omitempty"`
+	// compression is the layer compression of the saved image, one of
+	// gzip, zstd, zstd:chunked or none, empty keeps the default
+	Compression string `protobuf:"bytes,10,opt,name=compression,proto3" json:"compression,omitempty"`
+	// compressionLevel is the level of the stream compression a
+	// .tar.gz or .tar.zst output path asks for, 0 uses the default
+	CompressionLevel     int32    `protobuf:"varint,11,opt,name=compressionLevel,proto3" json:"compressionLevel,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *SaveRequest) Reset()         { *m = SaveRequest{} }
@@ -1494,21 +1946,63 @@ func (m *SaveRequest) GetSep() *SeparatorSave {
 	return nil
 }
 
-type SeparatorSave struct {
-	// base is base image name
-	Base string `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
-	// lib is library image name
-	Lib string `protobuf:"bytes,2,opt,name=lib,proto3" json:"lib,omitempty"`
-	// rename is rename json file
-	Rename string `protobuf:"bytes,3,opt,name=rename,proto3" json:"rename,omitempty"`
-	// dest is destination file directory
-	Dest string `protobuf:"bytes,4,opt,name=dest,proto3" json:"dest,omitempty"`
-	// enabled is flag to indicate the separator function enabled or not
-	Enabled              bool     `protobuf:"varint,5,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+func (m *SaveRequest) GetAnnotations() []string {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+func (m *SaveRequest) GetOs() string {
+	if m != nil {
+		return m.Os
+	}
+	return ""
+}
+
+func (m *SaveRequest) GetArch() string {
+	if m != nil {
+		return m.Arch
+	}
+	return ""
+}
+
+func (m *SaveRequest) GetVariant() string {
+	if m != nil {
+		return m.Variant
+	}
+	return ""
+}
+
+func (m *SaveRequest) GetCompression() string {
+	if m != nil {
+		return m.Compression
+	}
+	return ""
+}
+
+func (m *SaveRequest) GetCompressionLevel() int32 {
+	if m != nil {
+		return m.CompressionLevel
+	}
+	return 0
+}
+
+type SeparatorSave struct {
+	// base is base image name
+	Base string `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	// lib is library image name
+	Lib string `protobuf:"bytes,2,opt,name=lib,proto3" json:"lib,omitempty"`
+	// rename is rename json file
+	Rename string `protobuf:"bytes,3,opt,name=rename,proto3" json:"rename,omitempty"`
+	// dest is destination file directory
+	Dest string `protobuf:"bytes,4,opt,name=dest,proto3" json:"dest,omitempty"`
+	// enabled is flag to indicate the separator function enabled or not
+	Enabled              bool     `protobuf:"varint,5,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
 
 func (m *SeparatorSave) Reset()         { *m = SeparatorSave{} }
 func (m *SeparatorSave) String() string { return proto.CompactTextString(m) }
@@ -1921,10 +2415,12 @@ type InfoResponse struct {
 	// memstat is memory information counted by runtime
 	MemStat *MemStat `protobuf:"bytes,9,opt,name=memStat,proto3" json:"memStat,omitempty"`
 	// experimental indicates whether experimental feature is enabled
-	Experimental         bool     `protobuf:"varint,10,opt,name=experimental,proto3" json:"experimental,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Experimental bool `protobuf:"varint,10,opt,name=experimental,proto3" json:"experimental,omitempty"`
+	// buildResources lists the resource limits of the running builds
+	BuildResources       []*BuildResources `protobuf:"bytes,11,rep,name=buildResources,proto3" json:"buildResources,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *InfoResponse) Reset()         { *m = InfoResponse{} }
@@ -2021,6 +2517,409 @@ func (m *InfoResponse) GetExperimental() bool {
 	return false
 }
 
+func (m *InfoResponse) GetBuildResources() []*BuildResources {
+	if m != nil {
+		return m.BuildResources
+	}
+	return nil
+}
+
+type BuildResources struct {
+	// buildID is the build the limits apply to
+	BuildID string `protobuf:"bytes,1,opt,name=buildID,proto3" json:"buildID,omitempty"`
+	// memory bounds the memory of each RUN container in bytes
+	Memory int64 `protobuf:"varint,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	// cpuQuota is the cpu quota of each RUN container in microseconds
+	// per scheduler period
+	CpuQuota int64 `protobuf:"varint,3,opt,name=cpuQuota,proto3" json:"cpuQuota,omitempty"`
+	// cpus bounds each RUN container to that many cpus
+	Cpus float64 `protobuf:"fixed64,4,opt,name=cpus,proto3" json:"cpus,omitempty"`
+	// pidsLimit bounds the processes of each RUN container
+	PidsLimit            int64    `protobuf:"varint,5,opt,name=pidsLimit,proto3" json:"pidsLimit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuildResources) Reset()         { *m = BuildResources{} }
+func (m *BuildResources) String() string { return proto.CompactTextString(m) }
+func (*BuildResources) ProtoMessage()    {}
+func (*BuildResources) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{83}
+}
+func (m *BuildResources) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuildResources.Unmarshal(m, b)
+}
+func (m *BuildResources) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuildResources.Marshal(b, m, deterministic)
+}
+func (m *BuildResources) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildResources.Merge(m, src)
+}
+func (m *BuildResources) XXX_Size() int {
+	return xxx_messageInfo_BuildResources.Size(m)
+}
+func (m *BuildResources) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildResources.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuildResources proto.InternalMessageInfo
+
+func (m *BuildResources) GetBuildID() string {
+	if m != nil {
+		return m.BuildID
+	}
+	return ""
+}
+
+func (m *BuildResources) GetMemory() int64 {
+	if m != nil {
+		return m.Memory
+	}
+	return 0
+}
+
+func (m *BuildResources) GetCpuQuota() int64 {
+	if m != nil {
+		return m.CpuQuota
+	}
+	return 0
+}
+
+func (m *BuildResources) GetCpus() float64 {
+	if m != nil {
+		return m.Cpus
+	}
+	return 0
+}
+
+func (m *BuildResources) GetPidsLimit() int64 {
+	if m != nil {
+		return m.PidsLimit
+	}
+	return 0
+}
+
+type MetaKV struct {
+	// key is the metadata key
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// value is the metadata value
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MetaKV) Reset()         { *m = MetaKV{} }
+func (m *MetaKV) String() string { return proto.CompactTextString(m) }
+func (*MetaKV) ProtoMessage()    {}
+func (*MetaKV) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{84}
+}
+func (m *MetaKV) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MetaKV.Unmarshal(m, b)
+}
+func (m *MetaKV) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MetaKV.Marshal(b, m, deterministic)
+}
+func (m *MetaKV) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetaKV.Merge(m, src)
+}
+func (m *MetaKV) XXX_Size() int {
+	return xxx_messageInfo_MetaKV.Size(m)
+}
+func (m *MetaKV) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetaKV.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetaKV proto.InternalMessageInfo
+
+func (m *MetaKV) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *MetaKV) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type MetaSetRequest struct {
+	// image is the name or ID of the image to annotate
+	Image string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	// metadata lists the key/values to set, an entry with an empty
+	// value removes the key
+	Metadata             []*MetaKV `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *MetaSetRequest) Reset()         { *m = MetaSetRequest{} }
+func (m *MetaSetRequest) String() string { return proto.CompactTextString(m) }
+func (*MetaSetRequest) ProtoMessage()    {}
+func (*MetaSetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{85}
+}
+func (m *MetaSetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MetaSetRequest.Unmarshal(m, b)
+}
+func (m *MetaSetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MetaSetRequest.Marshal(b, m, deterministic)
+}
+func (m *MetaSetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetaSetRequest.Merge(m, src)
+}
+func (m *MetaSetRequest) XXX_Size() int {
+	return xxx_messageInfo_MetaSetRequest.Size(m)
+}
+func (m *MetaSetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetaSetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetaSetRequest proto.InternalMessageInfo
+
+func (m *MetaSetRequest) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *MetaSetRequest) GetMetadata() []*MetaKV {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type MetaGetRequest struct {
+	// image is the name or ID of the image to query
+	Image                string   `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MetaGetRequest) Reset()         { *m = MetaGetRequest{} }
+func (m *MetaGetRequest) String() string { return proto.CompactTextString(m) }
+func (*MetaGetRequest) ProtoMessage()    {}
+func (*MetaGetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{86}
+}
+func (m *MetaGetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MetaGetRequest.Unmarshal(m, b)
+}
+func (m *MetaGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MetaGetRequest.Marshal(b, m, deterministic)
+}
+func (m *MetaGetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetaGetRequest.Merge(m, src)
+}
+func (m *MetaGetRequest) XXX_Size() int {
+	return xxx_messageInfo_MetaGetRequest.Size(m)
+}
+func (m *MetaGetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetaGetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetaGetRequest proto.InternalMessageInfo
+
+func (m *MetaGetRequest) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+type MetaGetResponse struct {
+	// metadata lists the stored key/values ordered by key
+	Metadata             []*MetaKV `protobuf:"bytes,1,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *MetaGetResponse) Reset()         { *m = MetaGetResponse{} }
+func (m *MetaGetResponse) String() string { return proto.CompactTextString(m) }
+func (*MetaGetResponse) ProtoMessage()    {}
+func (*MetaGetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{87}
+}
+func (m *MetaGetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MetaGetResponse.Unmarshal(m, b)
+}
+func (m *MetaGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MetaGetResponse.Marshal(b, m, deterministic)
+}
+func (m *MetaGetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetaGetResponse.Merge(m, src)
+}
+func (m *MetaGetResponse) XXX_Size() int {
+	return xxx_messageInfo_MetaGetResponse.Size(m)
+}
+func (m *MetaGetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetaGetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetaGetResponse proto.InternalMessageInfo
+
+func (m *MetaGetResponse) GetMetadata() []*MetaKV {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type DfRequest struct {
+	// byOwner groups the report by the owning user instead of by team
+	ByOwner              bool     `protobuf:"varint,1,opt,name=byOwner,proto3" json:"byOwner,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DfRequest) Reset()         { *m = DfRequest{} }
+func (m *DfRequest) String() string { return proto.CompactTextString(m) }
+func (*DfRequest) ProtoMessage()    {}
+func (*DfRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{88}
+}
+func (m *DfRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DfRequest.Unmarshal(m, b)
+}
+func (m *DfRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DfRequest.Marshal(b, m, deterministic)
+}
+func (m *DfRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DfRequest.Merge(m, src)
+}
+func (m *DfRequest) XXX_Size() int {
+	return xxx_messageInfo_DfRequest.Size(m)
+}
+func (m *DfRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DfRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DfRequest proto.InternalMessageInfo
+
+func (m *DfRequest) GetByOwner() bool {
+	if m != nil {
+		return m.ByOwner
+	}
+	return false
+}
+
+type DfEntry struct {
+	// owner is the team or user the entry accounts for, images without
+	// a recorded owner show under <none>
+	Owner string `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	// images is how many images the owner holds
+	Images int64 `protobuf:"varint,2,opt,name=images,proto3" json:"images,omitempty"`
+	// size is the total virtual size of those images in bytes
+	Size_ int64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// quota is the configured storage quota in bytes, zero is unlimited
+	Quota                int64    `protobuf:"varint,4,opt,name=quota,proto3" json:"quota,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DfEntry) Reset()         { *m = DfEntry{} }
+func (m *DfEntry) String() string { return proto.CompactTextString(m) }
+func (*DfEntry) ProtoMessage()    {}
+func (*DfEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{89}
+}
+func (m *DfEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DfEntry.Unmarshal(m, b)
+}
+func (m *DfEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DfEntry.Marshal(b, m, deterministic)
+}
+func (m *DfEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DfEntry.Merge(m, src)
+}
+func (m *DfEntry) XXX_Size() int {
+	return xxx_messageInfo_DfEntry.Size(m)
+}
+func (m *DfEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_DfEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DfEntry proto.InternalMessageInfo
+
+func (m *DfEntry) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *DfEntry) GetImages() int64 {
+	if m != nil {
+		return m.Images
+	}
+	return 0
+}
+
+func (m *DfEntry) GetSize_() int64 {
+	if m != nil {
+		return m.Size_
+	}
+	return 0
+}
+
+func (m *DfEntry) GetQuota() int64 {
+	if m != nil {
+		return m.Quota
+	}
+	return 0
+}
+
+type DfResponse struct {
+	// entries carries one row per team or owner ordered by name
+	Entries              []*DfEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *DfResponse) Reset()         { *m = DfResponse{} }
+func (m *DfResponse) String() string { return proto.CompactTextString(m) }
+func (*DfResponse) ProtoMessage()    {}
+func (*DfResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{90}
+}
+func (m *DfResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DfResponse.Unmarshal(m, b)
+}
+func (m *DfResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DfResponse.Marshal(b, m, deterministic)
+}
+func (m *DfResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DfResponse.Merge(m, src)
+}
+func (m *DfResponse) XXX_Size() int {
+	return xxx_messageInfo_DfResponse.Size(m)
+}
+func (m *DfResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DfResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DfResponse proto.InternalMessageInfo
+
+func (m *DfResponse) GetEntries() []*DfEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
 type ManifestCreateRequest struct {
 	ManifestList         string   `protobuf:"bytes,1,opt,name=manifestList,proto3" json:"manifestList,omitempty"`
 	Manifests            []string `protobuf:"bytes,2,rep,name=manifests,proto3" json:"manifests,omitempty"`
@@ -2343,23 +3242,2348 @@ func (m *ManifestPushResponse) GetResult() string {
 	return ""
 }
 
-func init() {
-	proto.RegisterEnum("isula.build.v1.HealthCheckResponse_ServingStatus", HealthCheckResponse_ServingStatus_name, HealthCheckResponse_ServingStatus_value)
-	proto.RegisterType((*BuildRequest)(nil), "isula.build.v1.BuildRequest")
-	proto.RegisterType((*ImportRequest)(nil), "isula.build.v1.ImportRequest")
-	proto.RegisterType((*ImportResponse)(nil), "isula.build.v1.ImportResponse")
-	proto.RegisterType((*BuildStatic)(nil), "isula.build.v1.BuildStatic")
-	proto.RegisterType((*BuildResponse)(nil), "isula.build.v1.BuildResponse")
-	proto.RegisterType((*StatusRequest)(nil), "isula.build.v1.StatusRequest")
-	proto.RegisterType((*StatusResponse)(nil), "isula.build.v1.StatusResponse")
-	proto.RegisterType((*ListRequest)(nil), "isula.build.v1.ListRequest")
-	proto.RegisterType((*TagRequest)(nil), "isula.build.v1.TagRequest")
-	proto.RegisterType((*ListResponse)(nil), "isula.build.v1.ListResponse")
-	proto.RegisterType((*ListResponse_ImageInfo)(nil), "isula.build.v1.ListResponse.ImageInfo")
+type CacheListRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CacheListRequest) Reset()         { *m = CacheListRequest{} }
+func (m *CacheListRequest) String() string { return proto.CompactTextString(m) }
+func (*CacheListRequest) ProtoMessage()    {}
+func (*CacheListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{41}
+}
+func (m *CacheListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CacheListRequest.Unmarshal(m, b)
+}
+func (m *CacheListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CacheListRequest.Marshal(b, m, deterministic)
+}
+func (m *CacheListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CacheListRequest.Merge(m, src)
+}
+func (m *CacheListRequest) XXX_Size() int {
+	return xxx_messageInfo_CacheListRequest.Size(m)
+}
+func (m *CacheListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CacheListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CacheListRequest proto.InternalMessageInfo
+
+type CacheListResponse struct {
+	// CacheInfo carries the basic info of a cache entry
+	Caches               []*CacheListResponse_CacheInfo `protobuf:"bytes,1,rep,name=caches,proto3" json:"caches,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
+	XXX_unrecognized     []byte                         `json:"-"`
+	XXX_sizecache        int32                          `json:"-"`
+}
+
+func (m *CacheListResponse) Reset()         { *m = CacheListResponse{} }
+func (m *CacheListResponse) String() string { return proto.CompactTextString(m) }
+func (*CacheListResponse) ProtoMessage()    {}
+func (*CacheListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{42}
+}
+func (m *CacheListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CacheListResponse.Unmarshal(m, b)
+}
+func (m *CacheListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CacheListResponse.Marshal(b, m, deterministic)
+}
+func (m *CacheListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CacheListResponse.Merge(m, src)
+}
+func (m *CacheListResponse) XXX_Size() int {
+	return xxx_messageInfo_CacheListResponse.Size(m)
+}
+func (m *CacheListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CacheListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CacheListResponse proto.InternalMessageInfo
+
+func (m *CacheListResponse) GetCaches() []*CacheListResponse_CacheInfo {
+	if m != nil {
+		return m.Caches
+	}
+	return nil
+}
+
+type CacheListResponse_CacheInfo struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Url                  string   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Size_                string   `protobuf:"bytes,3,opt,name=size,proto3" json:"size,omitempty"`
+	Created              string   `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
+	LastUsed             string   `protobuf:"bytes,5,opt,name=lastUsed,proto3" json:"lastUsed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CacheListResponse_CacheInfo) Reset()         { *m = CacheListResponse_CacheInfo{} }
+func (m *CacheListResponse_CacheInfo) String() string { return proto.CompactTextString(m) }
+func (*CacheListResponse_CacheInfo) ProtoMessage()    {}
+func (*CacheListResponse_CacheInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{42, 0}
+}
+func (m *CacheListResponse_CacheInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CacheListResponse_CacheInfo.Unmarshal(m, b)
+}
+func (m *CacheListResponse_CacheInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CacheListResponse_CacheInfo.Marshal(b, m, deterministic)
+}
+func (m *CacheListResponse_CacheInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CacheListResponse_CacheInfo.Merge(m, src)
+}
+func (m *CacheListResponse_CacheInfo) XXX_Size() int {
+	return xxx_messageInfo_CacheListResponse_CacheInfo.Size(m)
+}
+func (m *CacheListResponse_CacheInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_CacheListResponse_CacheInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CacheListResponse_CacheInfo proto.InternalMessageInfo
+
+func (m *CacheListResponse_CacheInfo) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *CacheListResponse_CacheInfo) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *CacheListResponse_CacheInfo) GetSize_() string {
+	if m != nil {
+		return m.Size_
+	}
+	return ""
+}
+
+func (m *CacheListResponse_CacheInfo) GetCreated() string {
+	if m != nil {
+		return m.Created
+	}
+	return ""
+}
+
+func (m *CacheListResponse_CacheInfo) GetLastUsed() string {
+	if m != nil {
+		return m.LastUsed
+	}
+	return ""
+}
+
+type CacheRemoveRequest struct {
+	// keys are the cache entries to be deleted
+	Keys []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	// all tells isula-builder to delete all cache entries
+	All                  bool     `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CacheRemoveRequest) Reset()         { *m = CacheRemoveRequest{} }
+func (m *CacheRemoveRequest) String() string { return proto.CompactTextString(m) }
+func (*CacheRemoveRequest) ProtoMessage()    {}
+func (*CacheRemoveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{43}
+}
+func (m *CacheRemoveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CacheRemoveRequest.Unmarshal(m, b)
+}
+func (m *CacheRemoveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CacheRemoveRequest.Marshal(b, m, deterministic)
+}
+func (m *CacheRemoveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CacheRemoveRequest.Merge(m, src)
+}
+func (m *CacheRemoveRequest) XXX_Size() int {
+	return xxx_messageInfo_CacheRemoveRequest.Size(m)
+}
+func (m *CacheRemoveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CacheRemoveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CacheRemoveRequest proto.InternalMessageInfo
+
+func (m *CacheRemoveRequest) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *CacheRemoveRequest) GetAll() bool {
+	if m != nil {
+		return m.All
+	}
+	return false
+}
+
+type CacheRemoveResponse struct {
+	// result is remove response sent to front-end
+	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CacheRemoveResponse) Reset()         { *m = CacheRemoveResponse{} }
+func (m *CacheRemoveResponse) String() string { return proto.CompactTextString(m) }
+func (*CacheRemoveResponse) ProtoMessage()    {}
+func (*CacheRemoveResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{44}
+}
+func (m *CacheRemoveResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CacheRemoveResponse.Unmarshal(m, b)
+}
+func (m *CacheRemoveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CacheRemoveResponse.Marshal(b, m, deterministic)
+}
+func (m *CacheRemoveResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CacheRemoveResponse.Merge(m, src)
+}
+func (m *CacheRemoveResponse) XXX_Size() int {
+	return xxx_messageInfo_CacheRemoveResponse.Size(m)
+}
+func (m *CacheRemoveResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CacheRemoveResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CacheRemoveResponse proto.InternalMessageInfo
+
+func (m *CacheRemoveResponse) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+type BuilderCreateRequest struct {
+	// name is the name of the builder instance
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// buildArgs are the default args for buildings with this builder
+	BuildArgs []string `protobuf:"bytes,2,rep,name=buildArgs,proto3" json:"buildArgs,omitempty"`
+	// capAddList indicates the default Linux capabilities for RUN command
+	CapAddList []string `protobuf:"bytes,3,rep,name=capAddList,proto3" json:"capAddList,omitempty"`
+	// proxy marks for whether inherit proxy environments from host
+	Proxy                bool     `protobuf:"varint,4,opt,name=proxy,proto3" json:"proxy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuilderCreateRequest) Reset()         { *m = BuilderCreateRequest{} }
+func (m *BuilderCreateRequest) String() string { return proto.CompactTextString(m) }
+func (*BuilderCreateRequest) ProtoMessage()    {}
+func (*BuilderCreateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{45}
+}
+func (m *BuilderCreateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuilderCreateRequest.Unmarshal(m, b)
+}
+func (m *BuilderCreateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuilderCreateRequest.Marshal(b, m, deterministic)
+}
+func (m *BuilderCreateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuilderCreateRequest.Merge(m, src)
+}
+func (m *BuilderCreateRequest) XXX_Size() int {
+	return xxx_messageInfo_BuilderCreateRequest.Size(m)
+}
+func (m *BuilderCreateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuilderCreateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuilderCreateRequest proto.InternalMessageInfo
+
+func (m *BuilderCreateRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *BuilderCreateRequest) GetBuildArgs() []string {
+	if m != nil {
+		return m.BuildArgs
+	}
+	return nil
+}
+
+func (m *BuilderCreateRequest) GetCapAddList() []string {
+	if m != nil {
+		return m.CapAddList
+	}
+	return nil
+}
+
+func (m *BuilderCreateRequest) GetProxy() bool {
+	if m != nil {
+		return m.Proxy
+	}
+	return false
+}
+
+type BuilderCreateResponse struct {
+	// name is the name of the created builder instance
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuilderCreateResponse) Reset()         { *m = BuilderCreateResponse{} }
+func (m *BuilderCreateResponse) String() string { return proto.CompactTextString(m) }
+func (*BuilderCreateResponse) ProtoMessage()    {}
+func (*BuilderCreateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{46}
+}
+func (m *BuilderCreateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuilderCreateResponse.Unmarshal(m, b)
+}
+func (m *BuilderCreateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuilderCreateResponse.Marshal(b, m, deterministic)
+}
+func (m *BuilderCreateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuilderCreateResponse.Merge(m, src)
+}
+func (m *BuilderCreateResponse) XXX_Size() int {
+	return xxx_messageInfo_BuilderCreateResponse.Size(m)
+}
+func (m *BuilderCreateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuilderCreateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuilderCreateResponse proto.InternalMessageInfo
+
+func (m *BuilderCreateResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type BuilderListRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuilderListRequest) Reset()         { *m = BuilderListRequest{} }
+func (m *BuilderListRequest) String() string { return proto.CompactTextString(m) }
+func (*BuilderListRequest) ProtoMessage()    {}
+func (*BuilderListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{47}
+}
+func (m *BuilderListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuilderListRequest.Unmarshal(m, b)
+}
+func (m *BuilderListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuilderListRequest.Marshal(b, m, deterministic)
+}
+func (m *BuilderListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuilderListRequest.Merge(m, src)
+}
+func (m *BuilderListRequest) XXX_Size() int {
+	return xxx_messageInfo_BuilderListRequest.Size(m)
+}
+func (m *BuilderListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuilderListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuilderListRequest proto.InternalMessageInfo
+
+type BuilderListResponse struct {
+	// builders carries the basic info of the builder instances
+	Builders             []*BuilderListResponse_BuilderInfo `protobuf:"bytes,1,rep,name=builders,proto3" json:"builders,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                           `json:"-"`
+	XXX_unrecognized     []byte                             `json:"-"`
+	XXX_sizecache        int32                              `json:"-"`
+}
+
+func (m *BuilderListResponse) Reset()         { *m = BuilderListResponse{} }
+func (m *BuilderListResponse) String() string { return proto.CompactTextString(m) }
+func (*BuilderListResponse) ProtoMessage()    {}
+func (*BuilderListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{48}
+}
+func (m *BuilderListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuilderListResponse.Unmarshal(m, b)
+}
+func (m *BuilderListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuilderListResponse.Marshal(b, m, deterministic)
+}
+func (m *BuilderListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuilderListResponse.Merge(m, src)
+}
+func (m *BuilderListResponse) XXX_Size() int {
+	return xxx_messageInfo_BuilderListResponse.Size(m)
+}
+func (m *BuilderListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuilderListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuilderListResponse proto.InternalMessageInfo
+
+func (m *BuilderListResponse) GetBuilders() []*BuilderListResponse_BuilderInfo {
+	if m != nil {
+		return m.Builders
+	}
+	return nil
+}
+
+type BuilderListResponse_BuilderInfo struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	BuildArgs            []string `protobuf:"bytes,2,rep,name=buildArgs,proto3" json:"buildArgs,omitempty"`
+	CapAddList           []string `protobuf:"bytes,3,rep,name=capAddList,proto3" json:"capAddList,omitempty"`
+	Proxy                bool     `protobuf:"varint,4,opt,name=proxy,proto3" json:"proxy,omitempty"`
+	Created              string   `protobuf:"bytes,5,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuilderListResponse_BuilderInfo) Reset()         { *m = BuilderListResponse_BuilderInfo{} }
+func (m *BuilderListResponse_BuilderInfo) String() string { return proto.CompactTextString(m) }
+func (*BuilderListResponse_BuilderInfo) ProtoMessage()    {}
+func (*BuilderListResponse_BuilderInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{48, 0}
+}
+func (m *BuilderListResponse_BuilderInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuilderListResponse_BuilderInfo.Unmarshal(m, b)
+}
+func (m *BuilderListResponse_BuilderInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuilderListResponse_BuilderInfo.Marshal(b, m, deterministic)
+}
+func (m *BuilderListResponse_BuilderInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuilderListResponse_BuilderInfo.Merge(m, src)
+}
+func (m *BuilderListResponse_BuilderInfo) XXX_Size() int {
+	return xxx_messageInfo_BuilderListResponse_BuilderInfo.Size(m)
+}
+func (m *BuilderListResponse_BuilderInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuilderListResponse_BuilderInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuilderListResponse_BuilderInfo proto.InternalMessageInfo
+
+func (m *BuilderListResponse_BuilderInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *BuilderListResponse_BuilderInfo) GetBuildArgs() []string {
+	if m != nil {
+		return m.BuildArgs
+	}
+	return nil
+}
+
+func (m *BuilderListResponse_BuilderInfo) GetCapAddList() []string {
+	if m != nil {
+		return m.CapAddList
+	}
+	return nil
+}
+
+func (m *BuilderListResponse_BuilderInfo) GetProxy() bool {
+	if m != nil {
+		return m.Proxy
+	}
+	return false
+}
+
+func (m *BuilderListResponse_BuilderInfo) GetCreated() string {
+	if m != nil {
+		return m.Created
+	}
+	return ""
+}
+
+type BuilderRemoveRequest struct {
+	// name is the name of the builder instance to be deleted
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuilderRemoveRequest) Reset()         { *m = BuilderRemoveRequest{} }
+func (m *BuilderRemoveRequest) String() string { return proto.CompactTextString(m) }
+func (*BuilderRemoveRequest) ProtoMessage()    {}
+func (*BuilderRemoveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{49}
+}
+func (m *BuilderRemoveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuilderRemoveRequest.Unmarshal(m, b)
+}
+func (m *BuilderRemoveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuilderRemoveRequest.Marshal(b, m, deterministic)
+}
+func (m *BuilderRemoveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuilderRemoveRequest.Merge(m, src)
+}
+func (m *BuilderRemoveRequest) XXX_Size() int {
+	return xxx_messageInfo_BuilderRemoveRequest.Size(m)
+}
+func (m *BuilderRemoveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuilderRemoveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuilderRemoveRequest proto.InternalMessageInfo
+
+func (m *BuilderRemoveRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type BuilderRemoveResponse struct {
+	// name is the name of the deleted builder instance
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuilderRemoveResponse) Reset()         { *m = BuilderRemoveResponse{} }
+func (m *BuilderRemoveResponse) String() string { return proto.CompactTextString(m) }
+func (*BuilderRemoveResponse) ProtoMessage()    {}
+func (*BuilderRemoveResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{50}
+}
+func (m *BuilderRemoveResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuilderRemoveResponse.Unmarshal(m, b)
+}
+func (m *BuilderRemoveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuilderRemoveResponse.Marshal(b, m, deterministic)
+}
+func (m *BuilderRemoveResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuilderRemoveResponse.Merge(m, src)
+}
+func (m *BuilderRemoveResponse) XXX_Size() int {
+	return xxx_messageInfo_BuilderRemoveResponse.Size(m)
+}
+func (m *BuilderRemoveResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuilderRemoveResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuilderRemoveResponse proto.InternalMessageInfo
+
+func (m *BuilderRemoveResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type NegotiateRequest struct {
+	// version is the release version of the client
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// apiVersion is the API version spoken by the client
+	ApiVersion string `protobuf:"bytes,2,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
+	// capabilities are the API capabilities the client intends to use
+	Capabilities         []string `protobuf:"bytes,3,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NegotiateRequest) Reset()         { *m = NegotiateRequest{} }
+func (m *NegotiateRequest) String() string { return proto.CompactTextString(m) }
+func (*NegotiateRequest) ProtoMessage()    {}
+func (*NegotiateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{51}
+}
+func (m *NegotiateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NegotiateRequest.Unmarshal(m, b)
+}
+func (m *NegotiateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NegotiateRequest.Marshal(b, m, deterministic)
+}
+func (m *NegotiateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NegotiateRequest.Merge(m, src)
+}
+func (m *NegotiateRequest) XXX_Size() int {
+	return xxx_messageInfo_NegotiateRequest.Size(m)
+}
+func (m *NegotiateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NegotiateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NegotiateRequest proto.InternalMessageInfo
+
+func (m *NegotiateRequest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *NegotiateRequest) GetApiVersion() string {
+	if m != nil {
+		return m.ApiVersion
+	}
+	return ""
+}
+
+func (m *NegotiateRequest) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+type NegotiateResponse struct {
+	// version is the release version of the daemon
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// apiVersion is the API version spoken by the daemon
+	ApiVersion string `protobuf:"bytes,2,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
+	// capabilities are the API capabilities supported by the daemon
+	Capabilities         []string `protobuf:"bytes,3,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NegotiateResponse) Reset()         { *m = NegotiateResponse{} }
+func (m *NegotiateResponse) String() string { return proto.CompactTextString(m) }
+func (*NegotiateResponse) ProtoMessage()    {}
+func (*NegotiateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{52}
+}
+func (m *NegotiateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NegotiateResponse.Unmarshal(m, b)
+}
+func (m *NegotiateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NegotiateResponse.Marshal(b, m, deterministic)
+}
+func (m *NegotiateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NegotiateResponse.Merge(m, src)
+}
+func (m *NegotiateResponse) XXX_Size() int {
+	return xxx_messageInfo_NegotiateResponse.Size(m)
+}
+func (m *NegotiateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NegotiateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NegotiateResponse proto.InternalMessageInfo
+
+func (m *NegotiateResponse) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *NegotiateResponse) GetApiVersion() string {
+	if m != nil {
+		return m.ApiVersion
+	}
+	return ""
+}
+
+func (m *NegotiateResponse) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+type BuildsListRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuildsListRequest) Reset()         { *m = BuildsListRequest{} }
+func (m *BuildsListRequest) String() string { return proto.CompactTextString(m) }
+func (*BuildsListRequest) ProtoMessage()    {}
+func (*BuildsListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{53}
+}
+func (m *BuildsListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuildsListRequest.Unmarshal(m, b)
+}
+func (m *BuildsListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuildsListRequest.Marshal(b, m, deterministic)
+}
+func (m *BuildsListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildsListRequest.Merge(m, src)
+}
+func (m *BuildsListRequest) XXX_Size() int {
+	return xxx_messageInfo_BuildsListRequest.Size(m)
+}
+func (m *BuildsListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildsListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuildsListRequest proto.InternalMessageInfo
+
+type BuildsListResponse struct {
+	// builds carries the info of the currently running builds
+	Builds []*BuildsListResponse_BuildInfo `protobuf:"bytes,1,rep,name=builds,proto3" json:"builds,omitempty"`
+	// containers carries the ephemeral build containers in the store
+	Containers           []*BuildsListResponse_ContainerInfo `protobuf:"bytes,2,rep,name=containers,proto3" json:"containers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                            `json:"-"`
+	XXX_unrecognized     []byte                              `json:"-"`
+	XXX_sizecache        int32                               `json:"-"`
+}
+
+func (m *BuildsListResponse) Reset()         { *m = BuildsListResponse{} }
+func (m *BuildsListResponse) String() string { return proto.CompactTextString(m) }
+func (*BuildsListResponse) ProtoMessage()    {}
+func (*BuildsListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{54}
+}
+func (m *BuildsListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuildsListResponse.Unmarshal(m, b)
+}
+func (m *BuildsListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuildsListResponse.Marshal(b, m, deterministic)
+}
+func (m *BuildsListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildsListResponse.Merge(m, src)
+}
+func (m *BuildsListResponse) XXX_Size() int {
+	return xxx_messageInfo_BuildsListResponse.Size(m)
+}
+func (m *BuildsListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildsListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuildsListResponse proto.InternalMessageInfo
+
+func (m *BuildsListResponse) GetBuilds() []*BuildsListResponse_BuildInfo {
+	if m != nil {
+		return m.Builds
+	}
+	return nil
+}
+
+func (m *BuildsListResponse) GetContainers() []*BuildsListResponse_ContainerInfo {
+	if m != nil {
+		return m.Containers
+	}
+	return nil
+}
+
+type BuildsListResponse_BuildInfo struct {
+	// buildID is the ID of the running build
+	BuildID string `protobuf:"bytes,1,opt,name=buildID,proto3" json:"buildID,omitempty"`
+	// startTime is when the build started
+	StartTime string `protobuf:"bytes,2,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	// duration is how long the build is running already
+	Duration string `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	// stage shows the stage the build is working on, e.g. "2/3"
+	Stage string `protobuf:"bytes,4,opt,name=stage,proto3" json:"stage,omitempty"`
+	// tmpSize is the temporary space the build currently takes
+	TmpSize              string   `protobuf:"bytes,5,opt,name=tmpSize,proto3" json:"tmpSize,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuildsListResponse_BuildInfo) Reset()         { *m = BuildsListResponse_BuildInfo{} }
+func (m *BuildsListResponse_BuildInfo) String() string { return proto.CompactTextString(m) }
+func (*BuildsListResponse_BuildInfo) ProtoMessage()    {}
+func (*BuildsListResponse_BuildInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{54, 0}
+}
+func (m *BuildsListResponse_BuildInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuildsListResponse_BuildInfo.Unmarshal(m, b)
+}
+func (m *BuildsListResponse_BuildInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuildsListResponse_BuildInfo.Marshal(b, m, deterministic)
+}
+func (m *BuildsListResponse_BuildInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildsListResponse_BuildInfo.Merge(m, src)
+}
+func (m *BuildsListResponse_BuildInfo) XXX_Size() int {
+	return xxx_messageInfo_BuildsListResponse_BuildInfo.Size(m)
+}
+func (m *BuildsListResponse_BuildInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildsListResponse_BuildInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuildsListResponse_BuildInfo proto.InternalMessageInfo
+
+func (m *BuildsListResponse_BuildInfo) GetBuildID() string {
+	if m != nil {
+		return m.BuildID
+	}
+	return ""
+}
+
+func (m *BuildsListResponse_BuildInfo) GetStartTime() string {
+	if m != nil {
+		return m.StartTime
+	}
+	return ""
+}
+
+func (m *BuildsListResponse_BuildInfo) GetDuration() string {
+	if m != nil {
+		return m.Duration
+	}
+	return ""
+}
+
+func (m *BuildsListResponse_BuildInfo) GetStage() string {
+	if m != nil {
+		return m.Stage
+	}
+	return ""
+}
+
+func (m *BuildsListResponse_BuildInfo) GetTmpSize() string {
+	if m != nil {
+		return m.TmpSize
+	}
+	return ""
+}
+
+type BuildsListResponse_ContainerInfo struct {
+	// name is the name of the ephemeral build container
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// id is the ID of the ephemeral build container
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// image is the ID of the image the container was created from
+	Image string `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	// created is when the container was created
+	Created              string   `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuildsListResponse_ContainerInfo) Reset()         { *m = BuildsListResponse_ContainerInfo{} }
+func (m *BuildsListResponse_ContainerInfo) String() string { return proto.CompactTextString(m) }
+func (*BuildsListResponse_ContainerInfo) ProtoMessage()    {}
+func (*BuildsListResponse_ContainerInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{54, 1}
+}
+func (m *BuildsListResponse_ContainerInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuildsListResponse_ContainerInfo.Unmarshal(m, b)
+}
+func (m *BuildsListResponse_ContainerInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuildsListResponse_ContainerInfo.Marshal(b, m, deterministic)
+}
+func (m *BuildsListResponse_ContainerInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildsListResponse_ContainerInfo.Merge(m, src)
+}
+func (m *BuildsListResponse_ContainerInfo) XXX_Size() int {
+	return xxx_messageInfo_BuildsListResponse_ContainerInfo.Size(m)
+}
+func (m *BuildsListResponse_ContainerInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildsListResponse_ContainerInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuildsListResponse_ContainerInfo proto.InternalMessageInfo
+
+func (m *BuildsListResponse_ContainerInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *BuildsListResponse_ContainerInfo) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *BuildsListResponse_ContainerInfo) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *BuildsListResponse_ContainerInfo) GetCreated() string {
+	if m != nil {
+		return m.Created
+	}
+	return ""
+}
+
+type ConvertRequest struct {
+	// convertID is an unique id for this converting process
+	ConvertID string `protobuf:"bytes,1,opt,name=convertID,proto3" json:"convertID,omitempty"`
+	// source is the tarball or OCI layout dir to convert from
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	// destination is the path the converted image is written to
+	Destination string `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	// format is the destination transport, docker-archive, oci-archive or oci
+	Format               string   `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConvertRequest) Reset()         { *m = ConvertRequest{} }
+func (m *ConvertRequest) String() string { return proto.CompactTextString(m) }
+func (*ConvertRequest) ProtoMessage()    {}
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{55}
+}
+func (m *ConvertRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConvertRequest.Unmarshal(m, b)
+}
+func (m *ConvertRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConvertRequest.Marshal(b, m, deterministic)
+}
+func (m *ConvertRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConvertRequest.Merge(m, src)
+}
+func (m *ConvertRequest) XXX_Size() int {
+	return xxx_messageInfo_ConvertRequest.Size(m)
+}
+func (m *ConvertRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConvertRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConvertRequest proto.InternalMessageInfo
+
+func (m *ConvertRequest) GetConvertID() string {
+	if m != nil {
+		return m.ConvertID
+	}
+	return ""
+}
+
+func (m *ConvertRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *ConvertRequest) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+func (m *ConvertRequest) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+type ConvertResponse struct {
+	// log pipes the image converting process log back to client
+	Log                  string   `protobuf:"bytes,1,opt,name=log,proto3" json:"log,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConvertResponse) Reset()         { *m = ConvertResponse{} }
+func (m *ConvertResponse) String() string { return proto.CompactTextString(m) }
+func (*ConvertResponse) ProtoMessage()    {}
+func (*ConvertResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{56}
+}
+func (m *ConvertResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConvertResponse.Unmarshal(m, b)
+}
+func (m *ConvertResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConvertResponse.Marshal(b, m, deterministic)
+}
+func (m *ConvertResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConvertResponse.Merge(m, src)
+}
+func (m *ConvertResponse) XXX_Size() int {
+	return xxx_messageInfo_ConvertResponse.Size(m)
+}
+func (m *ConvertResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConvertResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConvertResponse proto.InternalMessageInfo
+
+func (m *ConvertResponse) GetLog() string {
+	if m != nil {
+		return m.Log
+	}
+	return ""
+}
+
+type RemoteInspectRequest struct {
+	// imageName is the remote image reference to inspect
+	ImageName            string   `protobuf:"bytes,1,opt,name=imageName,proto3" json:"imageName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoteInspectRequest) Reset()         { *m = RemoteInspectRequest{} }
+func (m *RemoteInspectRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoteInspectRequest) ProtoMessage()    {}
+func (*RemoteInspectRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{57}
+}
+func (m *RemoteInspectRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoteInspectRequest.Unmarshal(m, b)
+}
+func (m *RemoteInspectRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoteInspectRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoteInspectRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoteInspectRequest.Merge(m, src)
+}
+func (m *RemoteInspectRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoteInspectRequest.Size(m)
+}
+func (m *RemoteInspectRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoteInspectRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoteInspectRequest proto.InternalMessageInfo
+
+func (m *RemoteInspectRequest) GetImageName() string {
+	if m != nil {
+		return m.ImageName
+	}
+	return ""
+}
+
+type RemoteInspectResponse struct {
+	// data is the json encoded inspect result
+	Data                 string   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoteInspectResponse) Reset()         { *m = RemoteInspectResponse{} }
+func (m *RemoteInspectResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoteInspectResponse) ProtoMessage()    {}
+func (*RemoteInspectResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{58}
+}
+func (m *RemoteInspectResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoteInspectResponse.Unmarshal(m, b)
+}
+func (m *RemoteInspectResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoteInspectResponse.Marshal(b, m, deterministic)
+}
+func (m *RemoteInspectResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoteInspectResponse.Merge(m, src)
+}
+func (m *RemoteInspectResponse) XXX_Size() int {
+	return xxx_messageInfo_RemoteInspectResponse.Size(m)
+}
+func (m *RemoteInspectResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoteInspectResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoteInspectResponse proto.InternalMessageInfo
+
+func (m *RemoteInspectResponse) GetData() string {
+	if m != nil {
+		return m.Data
+	}
+	return ""
+}
+
+type PruneRequest struct {
+	// partial clears the partially downloaded data of inactive sessions
+	Partial bool `protobuf:"varint,1,opt,name=partial,proto3" json:"partial,omitempty"`
+	// cacheMounts clears the cache directories of RUN --mount cache mounts
+	CacheMounts          bool     `protobuf:"varint,2,opt,name=cacheMounts,proto3" json:"cacheMounts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PruneRequest) Reset()         { *m = PruneRequest{} }
+func (m *PruneRequest) String() string { return proto.CompactTextString(m) }
+func (*PruneRequest) ProtoMessage()    {}
+func (*PruneRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{59}
+}
+func (m *PruneRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PruneRequest.Unmarshal(m, b)
+}
+func (m *PruneRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PruneRequest.Marshal(b, m, deterministic)
+}
+func (m *PruneRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PruneRequest.Merge(m, src)
+}
+func (m *PruneRequest) XXX_Size() int {
+	return xxx_messageInfo_PruneRequest.Size(m)
+}
+func (m *PruneRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PruneRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PruneRequest proto.InternalMessageInfo
+
+func (m *PruneRequest) GetPartial() bool {
+	if m != nil {
+		return m.Partial
+	}
+	return false
+}
+
+func (m *PruneRequest) GetCacheMounts() bool {
+	if m != nil {
+		return m.CacheMounts
+	}
+	return false
+}
+
+type PruneResponse struct {
+	// removed lists the pruned entries
+	Removed []string `protobuf:"bytes,1,rep,name=removed,proto3" json:"removed,omitempty"`
+	// reclaimedBytes is the total size of the pruned entries
+	ReclaimedBytes       int64    `protobuf:"varint,2,opt,name=reclaimedBytes,proto3" json:"reclaimedBytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PruneResponse) Reset()         { *m = PruneResponse{} }
+func (m *PruneResponse) String() string { return proto.CompactTextString(m) }
+func (*PruneResponse) ProtoMessage()    {}
+func (*PruneResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{60}
+}
+func (m *PruneResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PruneResponse.Unmarshal(m, b)
+}
+func (m *PruneResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PruneResponse.Marshal(b, m, deterministic)
+}
+func (m *PruneResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PruneResponse.Merge(m, src)
+}
+func (m *PruneResponse) XXX_Size() int {
+	return xxx_messageInfo_PruneResponse.Size(m)
+}
+func (m *PruneResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PruneResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PruneResponse proto.InternalMessageInfo
+
+func (m *PruneResponse) GetRemoved() []string {
+	if m != nil {
+		return m.Removed
+	}
+	return nil
+}
+
+func (m *PruneResponse) GetReclaimedBytes() int64 {
+	if m != nil {
+		return m.ReclaimedBytes
+	}
+	return 0
+}
+
+type VerifyRemoteRequest struct {
+	// localImage is the image in the local store to verify
+	LocalImage string `protobuf:"bytes,1,opt,name=localImage,proto3" json:"localImage,omitempty"`
+	// remoteRef is the registry copy to compare against
+	RemoteRef            string   `protobuf:"bytes,2,opt,name=remoteRef,proto3" json:"remoteRef,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyRemoteRequest) Reset()         { *m = VerifyRemoteRequest{} }
+func (m *VerifyRemoteRequest) String() string { return proto.CompactTextString(m) }
+func (*VerifyRemoteRequest) ProtoMessage()    {}
+func (*VerifyRemoteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{61}
+}
+func (m *VerifyRemoteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyRemoteRequest.Unmarshal(m, b)
+}
+func (m *VerifyRemoteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyRemoteRequest.Marshal(b, m, deterministic)
+}
+func (m *VerifyRemoteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyRemoteRequest.Merge(m, src)
+}
+func (m *VerifyRemoteRequest) XXX_Size() int {
+	return xxx_messageInfo_VerifyRemoteRequest.Size(m)
+}
+func (m *VerifyRemoteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyRemoteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyRemoteRequest proto.InternalMessageInfo
+
+func (m *VerifyRemoteRequest) GetLocalImage() string {
+	if m != nil {
+		return m.LocalImage
+	}
+	return ""
+}
+
+func (m *VerifyRemoteRequest) GetRemoteRef() string {
+	if m != nil {
+		return m.RemoteRef
+	}
+	return ""
+}
+
+type VerifyRemoteResponse struct {
+	// data is the json encoded per-layer match report
+	Data                 string   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyRemoteResponse) Reset()         { *m = VerifyRemoteResponse{} }
+func (m *VerifyRemoteResponse) String() string { return proto.CompactTextString(m) }
+func (*VerifyRemoteResponse) ProtoMessage()    {}
+func (*VerifyRemoteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{62}
+}
+func (m *VerifyRemoteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyRemoteResponse.Unmarshal(m, b)
+}
+func (m *VerifyRemoteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyRemoteResponse.Marshal(b, m, deterministic)
+}
+func (m *VerifyRemoteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyRemoteResponse.Merge(m, src)
+}
+func (m *VerifyRemoteResponse) XXX_Size() int {
+	return xxx_messageInfo_VerifyRemoteResponse.Size(m)
+}
+func (m *VerifyRemoteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyRemoteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyRemoteResponse proto.InternalMessageInfo
+
+func (m *VerifyRemoteResponse) GetData() string {
+	if m != nil {
+		return m.Data
+	}
+	return ""
+}
+
+type ImagePruneRequest struct {
+	// all removes every unused image, not only the dangling ones
+	All bool `protobuf:"varint,1,opt,name=all,proto3" json:"all,omitempty"`
+	// untilSeconds only prunes entries older than this age, 0 disables the filter
+	UntilSeconds int64 `protobuf:"varint,2,opt,name=untilSeconds,proto3" json:"untilSeconds,omitempty"`
+	// dryRun only reports what would be removed
+	DryRun               bool     `protobuf:"varint,3,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImagePruneRequest) Reset()         { *m = ImagePruneRequest{} }
+func (m *ImagePruneRequest) String() string { return proto.CompactTextString(m) }
+func (*ImagePruneRequest) ProtoMessage()    {}
+func (*ImagePruneRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{63}
+}
+func (m *ImagePruneRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImagePruneRequest.Unmarshal(m, b)
+}
+func (m *ImagePruneRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImagePruneRequest.Marshal(b, m, deterministic)
+}
+func (m *ImagePruneRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImagePruneRequest.Merge(m, src)
+}
+func (m *ImagePruneRequest) XXX_Size() int {
+	return xxx_messageInfo_ImagePruneRequest.Size(m)
+}
+func (m *ImagePruneRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImagePruneRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImagePruneRequest proto.InternalMessageInfo
+
+func (m *ImagePruneRequest) GetAll() bool {
+	if m != nil {
+		return m.All
+	}
+	return false
+}
+
+func (m *ImagePruneRequest) GetUntilSeconds() int64 {
+	if m != nil {
+		return m.UntilSeconds
+	}
+	return 0
+}
+
+func (m *ImagePruneRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type ImagePruneResponse struct {
+	// removed lists the pruned entries
+	Removed []string `protobuf:"bytes,1,rep,name=removed,proto3" json:"removed,omitempty"`
+	// reclaimedBytes is the total size of the pruned entries
+	ReclaimedBytes       int64    `protobuf:"varint,2,opt,name=reclaimedBytes,proto3" json:"reclaimedBytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImagePruneResponse) Reset()         { *m = ImagePruneResponse{} }
+func (m *ImagePruneResponse) String() string { return proto.CompactTextString(m) }
+func (*ImagePruneResponse) ProtoMessage()    {}
+func (*ImagePruneResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{64}
+}
+func (m *ImagePruneResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImagePruneResponse.Unmarshal(m, b)
+}
+func (m *ImagePruneResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImagePruneResponse.Marshal(b, m, deterministic)
+}
+func (m *ImagePruneResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImagePruneResponse.Merge(m, src)
+}
+func (m *ImagePruneResponse) XXX_Size() int {
+	return xxx_messageInfo_ImagePruneResponse.Size(m)
+}
+func (m *ImagePruneResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImagePruneResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImagePruneResponse proto.InternalMessageInfo
+
+func (m *ImagePruneResponse) GetRemoved() []string {
+	if m != nil {
+		return m.Removed
+	}
+	return nil
+}
+
+func (m *ImagePruneResponse) GetReclaimedBytes() int64 {
+	if m != nil {
+		return m.ReclaimedBytes
+	}
+	return 0
+}
+
+type BackupRequest struct {
+	// backupID is an unique id for this backup process
+	BackupID string `protobuf:"bytes,1,opt,name=backupID,proto3" json:"backupID,omitempty"`
+	// path is where the backup archive is written to
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// images selects the images to back up, empty means all tagged images
+	Images               []string `protobuf:"bytes,3,rep,name=images,proto3" json:"images,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackupRequest) Reset()         { *m = BackupRequest{} }
+func (m *BackupRequest) String() string { return proto.CompactTextString(m) }
+func (*BackupRequest) ProtoMessage()    {}
+func (*BackupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{65}
+}
+func (m *BackupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackupRequest.Unmarshal(m, b)
+}
+func (m *BackupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackupRequest.Marshal(b, m, deterministic)
+}
+func (m *BackupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackupRequest.Merge(m, src)
+}
+func (m *BackupRequest) XXX_Size() int {
+	return xxx_messageInfo_BackupRequest.Size(m)
+}
+func (m *BackupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackupRequest proto.InternalMessageInfo
+
+func (m *BackupRequest) GetBackupID() string {
+	if m != nil {
+		return m.BackupID
+	}
+	return ""
+}
+
+func (m *BackupRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *BackupRequest) GetImages() []string {
+	if m != nil {
+		return m.Images
+	}
+	return nil
+}
+
+type BackupResponse struct {
+	// images lists the backed up images
+	Images               []string `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackupResponse) Reset()         { *m = BackupResponse{} }
+func (m *BackupResponse) String() string { return proto.CompactTextString(m) }
+func (*BackupResponse) ProtoMessage()    {}
+func (*BackupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{66}
+}
+func (m *BackupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackupResponse.Unmarshal(m, b)
+}
+func (m *BackupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackupResponse.Marshal(b, m, deterministic)
+}
+func (m *BackupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackupResponse.Merge(m, src)
+}
+func (m *BackupResponse) XXX_Size() int {
+	return xxx_messageInfo_BackupResponse.Size(m)
+}
+func (m *BackupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackupResponse proto.InternalMessageInfo
+
+func (m *BackupResponse) GetImages() []string {
+	if m != nil {
+		return m.Images
+	}
+	return nil
+}
+
+type RestoreRequest struct {
+	// restoreID is an unique id for this restore process
+	RestoreID string `protobuf:"bytes,1,opt,name=restoreID,proto3" json:"restoreID,omitempty"`
+	// path is the backup archive to restore from
+	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreRequest) Reset()         { *m = RestoreRequest{} }
+func (m *RestoreRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreRequest) ProtoMessage()    {}
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{67}
+}
+func (m *RestoreRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreRequest.Unmarshal(m, b)
+}
+func (m *RestoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreRequest.Merge(m, src)
+}
+func (m *RestoreRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreRequest.Size(m)
+}
+func (m *RestoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreRequest proto.InternalMessageInfo
+
+func (m *RestoreRequest) GetRestoreID() string {
+	if m != nil {
+		return m.RestoreID
+	}
+	return ""
+}
+
+func (m *RestoreRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type RestoreResponse struct {
+	// images lists the restored image IDs
+	Images               []string `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreResponse) Reset()         { *m = RestoreResponse{} }
+func (m *RestoreResponse) String() string { return proto.CompactTextString(m) }
+func (*RestoreResponse) ProtoMessage()    {}
+func (*RestoreResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{68}
+}
+func (m *RestoreResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreResponse.Unmarshal(m, b)
+}
+func (m *RestoreResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreResponse.Marshal(b, m, deterministic)
+}
+func (m *RestoreResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreResponse.Merge(m, src)
+}
+func (m *RestoreResponse) XXX_Size() int {
+	return xxx_messageInfo_RestoreResponse.Size(m)
+}
+func (m *RestoreResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreResponse proto.InternalMessageInfo
+
+func (m *RestoreResponse) GetImages() []string {
+	if m != nil {
+		return m.Images
+	}
+	return nil
+}
+
+type RecordsExportRequest struct {
+	// exportID is an unique id for this export process
+	ExportID string `protobuf:"bytes,1,opt,name=exportID,proto3" json:"exportID,omitempty"`
+	// path is where the record bundle is written to
+	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecordsExportRequest) Reset()         { *m = RecordsExportRequest{} }
+func (m *RecordsExportRequest) String() string { return proto.CompactTextString(m) }
+func (*RecordsExportRequest) ProtoMessage()    {}
+func (*RecordsExportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{69}
+}
+func (m *RecordsExportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecordsExportRequest.Unmarshal(m, b)
+}
+func (m *RecordsExportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecordsExportRequest.Marshal(b, m, deterministic)
+}
+func (m *RecordsExportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecordsExportRequest.Merge(m, src)
+}
+func (m *RecordsExportRequest) XXX_Size() int {
+	return xxx_messageInfo_RecordsExportRequest.Size(m)
+}
+func (m *RecordsExportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecordsExportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecordsExportRequest proto.InternalMessageInfo
+
+func (m *RecordsExportRequest) GetExportID() string {
+	if m != nil {
+		return m.ExportID
+	}
+	return ""
+}
+
+func (m *RecordsExportRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type RecordsExportResponse struct {
+	// records lists the exported build record IDs
+	Records              []string `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecordsExportResponse) Reset()         { *m = RecordsExportResponse{} }
+func (m *RecordsExportResponse) String() string { return proto.CompactTextString(m) }
+func (*RecordsExportResponse) ProtoMessage()    {}
+func (*RecordsExportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{70}
+}
+func (m *RecordsExportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecordsExportResponse.Unmarshal(m, b)
+}
+func (m *RecordsExportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecordsExportResponse.Marshal(b, m, deterministic)
+}
+func (m *RecordsExportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecordsExportResponse.Merge(m, src)
+}
+func (m *RecordsExportResponse) XXX_Size() int {
+	return xxx_messageInfo_RecordsExportResponse.Size(m)
+}
+func (m *RecordsExportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecordsExportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecordsExportResponse proto.InternalMessageInfo
+
+func (m *RecordsExportResponse) GetRecords() []string {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+type RecordsImportRequest struct {
+	// importID is an unique id for this import process
+	ImportID string `protobuf:"bytes,1,opt,name=importID,proto3" json:"importID,omitempty"`
+	// path is the record bundle to import from
+	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecordsImportRequest) Reset()         { *m = RecordsImportRequest{} }
+func (m *RecordsImportRequest) String() string { return proto.CompactTextString(m) }
+func (*RecordsImportRequest) ProtoMessage()    {}
+func (*RecordsImportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{71}
+}
+func (m *RecordsImportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecordsImportRequest.Unmarshal(m, b)
+}
+func (m *RecordsImportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecordsImportRequest.Marshal(b, m, deterministic)
+}
+func (m *RecordsImportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecordsImportRequest.Merge(m, src)
+}
+func (m *RecordsImportRequest) XXX_Size() int {
+	return xxx_messageInfo_RecordsImportRequest.Size(m)
+}
+func (m *RecordsImportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecordsImportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecordsImportRequest proto.InternalMessageInfo
+
+func (m *RecordsImportRequest) GetImportID() string {
+	if m != nil {
+		return m.ImportID
+	}
+	return ""
+}
+
+func (m *RecordsImportRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type RecordsImportResponse struct {
+	// records lists the imported build record IDs
+	Records              []string `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecordsImportResponse) Reset()         { *m = RecordsImportResponse{} }
+func (m *RecordsImportResponse) String() string { return proto.CompactTextString(m) }
+func (*RecordsImportResponse) ProtoMessage()    {}
+func (*RecordsImportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{72}
+}
+func (m *RecordsImportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecordsImportResponse.Unmarshal(m, b)
+}
+func (m *RecordsImportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecordsImportResponse.Marshal(b, m, deterministic)
+}
+func (m *RecordsImportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecordsImportResponse.Merge(m, src)
+}
+func (m *RecordsImportResponse) XXX_Size() int {
+	return xxx_messageInfo_RecordsImportResponse.Size(m)
+}
+func (m *RecordsImportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecordsImportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecordsImportResponse proto.InternalMessageInfo
+
+func (m *RecordsImportResponse) GetRecords() []string {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+type InspectRequest struct {
+	// imageName is the local image to inspect
+	ImageName            string   `protobuf:"bytes,1,opt,name=imageName,proto3" json:"imageName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InspectRequest) Reset()         { *m = InspectRequest{} }
+func (m *InspectRequest) String() string { return proto.CompactTextString(m) }
+func (*InspectRequest) ProtoMessage()    {}
+func (*InspectRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{73}
+}
+func (m *InspectRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InspectRequest.Unmarshal(m, b)
+}
+func (m *InspectRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InspectRequest.Marshal(b, m, deterministic)
+}
+func (m *InspectRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InspectRequest.Merge(m, src)
+}
+func (m *InspectRequest) XXX_Size() int {
+	return xxx_messageInfo_InspectRequest.Size(m)
+}
+func (m *InspectRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InspectRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InspectRequest proto.InternalMessageInfo
+
+func (m *InspectRequest) GetImageName() string {
+	if m != nil {
+		return m.ImageName
+	}
+	return ""
+}
+
+type InspectResponse struct {
+	// data is the JSON encoded inspect result
+	Data                 string   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InspectResponse) Reset()         { *m = InspectResponse{} }
+func (m *InspectResponse) String() string { return proto.CompactTextString(m) }
+func (*InspectResponse) ProtoMessage()    {}
+func (*InspectResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{74}
+}
+func (m *InspectResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InspectResponse.Unmarshal(m, b)
+}
+func (m *InspectResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InspectResponse.Marshal(b, m, deterministic)
+}
+func (m *InspectResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InspectResponse.Merge(m, src)
+}
+func (m *InspectResponse) XXX_Size() int {
+	return xxx_messageInfo_InspectResponse.Size(m)
+}
+func (m *InspectResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InspectResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InspectResponse proto.InternalMessageInfo
+
+func (m *InspectResponse) GetData() string {
+	if m != nil {
+		return m.Data
+	}
+	return ""
+}
+
+type PromoteRequest struct {
+	// promoteID is an unique id for this promote process
+	PromoteID string `protobuf:"bytes,1,opt,name=promoteID,proto3" json:"promoteID,omitempty"`
+	// image is the repository and tag promoted between the environments
+	Image string `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	// from is the logical environment the image currently lives in
+	From string `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	// to is the logical environment the image is promoted to
+	To                   string   `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PromoteRequest) Reset()         { *m = PromoteRequest{} }
+func (m *PromoteRequest) String() string { return proto.CompactTextString(m) }
+func (*PromoteRequest) ProtoMessage()    {}
+func (*PromoteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{75}
+}
+func (m *PromoteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PromoteRequest.Unmarshal(m, b)
+}
+func (m *PromoteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PromoteRequest.Marshal(b, m, deterministic)
+}
+func (m *PromoteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PromoteRequest.Merge(m, src)
+}
+func (m *PromoteRequest) XXX_Size() int {
+	return xxx_messageInfo_PromoteRequest.Size(m)
+}
+func (m *PromoteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PromoteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PromoteRequest proto.InternalMessageInfo
+
+func (m *PromoteRequest) GetPromoteID() string {
+	if m != nil {
+		return m.PromoteID
+	}
+	return ""
+}
+
+func (m *PromoteRequest) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *PromoteRequest) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *PromoteRequest) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+type PromoteResponse struct {
+	// source is the resolved source reference
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	// target is the pushed target reference
+	Target               string   `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PromoteResponse) Reset()         { *m = PromoteResponse{} }
+func (m *PromoteResponse) String() string { return proto.CompactTextString(m) }
+func (*PromoteResponse) ProtoMessage()    {}
+func (*PromoteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{76}
+}
+func (m *PromoteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PromoteResponse.Unmarshal(m, b)
+}
+func (m *PromoteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PromoteResponse.Marshal(b, m, deterministic)
+}
+func (m *PromoteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PromoteResponse.Merge(m, src)
+}
+func (m *PromoteResponse) XXX_Size() int {
+	return xxx_messageInfo_PromoteResponse.Size(m)
+}
+func (m *PromoteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PromoteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PromoteResponse proto.InternalMessageInfo
+
+func (m *PromoteResponse) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *PromoteResponse) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+type DiffRequest struct {
+	// imageA is the base image of the comparison
+	ImageA string `protobuf:"bytes,1,opt,name=imageA,proto3" json:"imageA,omitempty"`
+	// imageB is the image compared against imageA, empty compares
+	// imageA against its own base layer
+	ImageB               string   `protobuf:"bytes,2,opt,name=imageB,proto3" json:"imageB,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiffRequest) Reset()         { *m = DiffRequest{} }
+func (m *DiffRequest) String() string { return proto.CompactTextString(m) }
+func (*DiffRequest) ProtoMessage()    {}
+func (*DiffRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{77}
+}
+func (m *DiffRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffRequest.Unmarshal(m, b)
+}
+func (m *DiffRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffRequest.Marshal(b, m, deterministic)
+}
+func (m *DiffRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffRequest.Merge(m, src)
+}
+func (m *DiffRequest) XXX_Size() int {
+	return xxx_messageInfo_DiffRequest.Size(m)
+}
+func (m *DiffRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffRequest proto.InternalMessageInfo
+
+func (m *DiffRequest) GetImageA() string {
+	if m != nil {
+		return m.ImageA
+	}
+	return ""
+}
+
+func (m *DiffRequest) GetImageB() string {
+	if m != nil {
+		return m.ImageB
+	}
+	return ""
+}
+
+type DiffResponse struct {
+	// added lists the files only present in imageB
+	Added []string `protobuf:"bytes,1,rep,name=added,proto3" json:"added,omitempty"`
+	// changed lists the files differing between the images
+	Changed []string `protobuf:"bytes,2,rep,name=changed,proto3" json:"changed,omitempty"`
+	// deleted lists the files removed in imageB
+	Deleted              []string `protobuf:"bytes,3,rep,name=deleted,proto3" json:"deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiffResponse) Reset()         { *m = DiffResponse{} }
+func (m *DiffResponse) String() string { return proto.CompactTextString(m) }
+func (*DiffResponse) ProtoMessage()    {}
+func (*DiffResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{78}
+}
+func (m *DiffResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffResponse.Unmarshal(m, b)
+}
+func (m *DiffResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffResponse.Marshal(b, m, deterministic)
+}
+func (m *DiffResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffResponse.Merge(m, src)
+}
+func (m *DiffResponse) XXX_Size() int {
+	return xxx_messageInfo_DiffResponse.Size(m)
+}
+func (m *DiffResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffResponse proto.InternalMessageInfo
+
+func (m *DiffResponse) GetAdded() []string {
+	if m != nil {
+		return m.Added
+	}
+	return nil
+}
+
+func (m *DiffResponse) GetChanged() []string {
+	if m != nil {
+		return m.Changed
+	}
+	return nil
+}
+
+func (m *DiffResponse) GetDeleted() []string {
+	if m != nil {
+		return m.Deleted
+	}
+	return nil
+}
+
+type LoginListRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LoginListRequest) Reset()         { *m = LoginListRequest{} }
+func (m *LoginListRequest) String() string { return proto.CompactTextString(m) }
+func (*LoginListRequest) ProtoMessage()    {}
+func (*LoginListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{79}
+}
+func (m *LoginListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoginListRequest.Unmarshal(m, b)
+}
+func (m *LoginListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoginListRequest.Marshal(b, m, deterministic)
+}
+func (m *LoginListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoginListRequest.Merge(m, src)
+}
+func (m *LoginListRequest) XXX_Size() int {
+	return xxx_messageInfo_LoginListRequest.Size(m)
+}
+func (m *LoginListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoginListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoginListRequest proto.InternalMessageInfo
+
+type LoginListResponse struct {
+	// logins carries one entry per registry with stored credentials
+	Logins               []*LoginListResponse_LoginInfo `protobuf:"bytes,1,rep,name=logins,proto3" json:"logins,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
+	XXX_unrecognized     []byte                         `json:"-"`
+	XXX_sizecache        int32                          `json:"-"`
+}
+
+func (m *LoginListResponse) Reset()         { *m = LoginListResponse{} }
+func (m *LoginListResponse) String() string { return proto.CompactTextString(m) }
+func (*LoginListResponse) ProtoMessage()    {}
+func (*LoginListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{80}
+}
+func (m *LoginListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoginListResponse.Unmarshal(m, b)
+}
+func (m *LoginListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoginListResponse.Marshal(b, m, deterministic)
+}
+func (m *LoginListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoginListResponse.Merge(m, src)
+}
+func (m *LoginListResponse) XXX_Size() int {
+	return xxx_messageInfo_LoginListResponse.Size(m)
+}
+func (m *LoginListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoginListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoginListResponse proto.InternalMessageInfo
+
+func (m *LoginListResponse) GetLogins() []*LoginListResponse_LoginInfo {
+	if m != nil {
+		return m.Logins
+	}
+	return nil
+}
+
+type LoginListResponse_LoginInfo struct {
+	Registry             string   `protobuf:"bytes,1,opt,name=registry,proto3" json:"registry,omitempty"`
+	Username             string   `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	AuthFile             string   `protobuf:"bytes,3,opt,name=authFile,proto3" json:"authFile,omitempty"`
+	TokenExpiry          string   `protobuf:"bytes,4,opt,name=tokenExpiry,proto3" json:"tokenExpiry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LoginListResponse_LoginInfo) Reset()         { *m = LoginListResponse_LoginInfo{} }
+func (m *LoginListResponse_LoginInfo) String() string { return proto.CompactTextString(m) }
+func (*LoginListResponse_LoginInfo) ProtoMessage()    {}
+func (*LoginListResponse_LoginInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{80, 0}
+}
+func (m *LoginListResponse_LoginInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoginListResponse_LoginInfo.Unmarshal(m, b)
+}
+func (m *LoginListResponse_LoginInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoginListResponse_LoginInfo.Marshal(b, m, deterministic)
+}
+func (m *LoginListResponse_LoginInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoginListResponse_LoginInfo.Merge(m, src)
+}
+func (m *LoginListResponse_LoginInfo) XXX_Size() int {
+	return xxx_messageInfo_LoginListResponse_LoginInfo.Size(m)
+}
+func (m *LoginListResponse_LoginInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoginListResponse_LoginInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoginListResponse_LoginInfo proto.InternalMessageInfo
+
+func (m *LoginListResponse_LoginInfo) GetRegistry() string {
+	if m != nil {
+		return m.Registry
+	}
+	return ""
+}
+
+func (m *LoginListResponse_LoginInfo) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *LoginListResponse_LoginInfo) GetAuthFile() string {
+	if m != nil {
+		return m.AuthFile
+	}
+	return ""
+}
+
+func (m *LoginListResponse_LoginInfo) GetTokenExpiry() string {
+	if m != nil {
+		return m.TokenExpiry
+	}
+	return ""
+}
+
+type TreeRequest struct {
+	// imageName restricts the tree to one image, empty covers all local images
+	ImageName            string   `protobuf:"bytes,1,opt,name=imageName,proto3" json:"imageName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TreeRequest) Reset()         { *m = TreeRequest{} }
+func (m *TreeRequest) String() string { return proto.CompactTextString(m) }
+func (*TreeRequest) ProtoMessage()    {}
+func (*TreeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{81}
+}
+func (m *TreeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TreeRequest.Unmarshal(m, b)
+}
+func (m *TreeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TreeRequest.Marshal(b, m, deterministic)
+}
+func (m *TreeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TreeRequest.Merge(m, src)
+}
+func (m *TreeRequest) XXX_Size() int {
+	return xxx_messageInfo_TreeRequest.Size(m)
+}
+func (m *TreeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TreeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TreeRequest proto.InternalMessageInfo
+
+func (m *TreeRequest) GetImageName() string {
+	if m != nil {
+		return m.ImageName
+	}
+	return ""
+}
+
+type TreeResponse struct {
+	// images lists the local images with their layer chains
+	Images               []*TreeResponse_Image `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *TreeResponse) Reset()         { *m = TreeResponse{} }
+func (m *TreeResponse) String() string { return proto.CompactTextString(m) }
+func (*TreeResponse) ProtoMessage()    {}
+func (*TreeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{82}
+}
+func (m *TreeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TreeResponse.Unmarshal(m, b)
+}
+func (m *TreeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TreeResponse.Marshal(b, m, deterministic)
+}
+func (m *TreeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TreeResponse.Merge(m, src)
+}
+func (m *TreeResponse) XXX_Size() int {
+	return xxx_messageInfo_TreeResponse.Size(m)
+}
+func (m *TreeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TreeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TreeResponse proto.InternalMessageInfo
+
+func (m *TreeResponse) GetImages() []*TreeResponse_Image {
+	if m != nil {
+		return m.Images
+	}
+	return nil
+}
+
+type TreeResponse_Layer struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Size_                string   `protobuf:"bytes,2,opt,name=size,proto3" json:"size,omitempty"`
+	RefCount             int32    `protobuf:"varint,3,opt,name=refCount,proto3" json:"refCount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TreeResponse_Layer) Reset()         { *m = TreeResponse_Layer{} }
+func (m *TreeResponse_Layer) String() string { return proto.CompactTextString(m) }
+func (*TreeResponse_Layer) ProtoMessage()    {}
+func (*TreeResponse_Layer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{82, 0}
+}
+func (m *TreeResponse_Layer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TreeResponse_Layer.Unmarshal(m, b)
+}
+func (m *TreeResponse_Layer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TreeResponse_Layer.Marshal(b, m, deterministic)
+}
+func (m *TreeResponse_Layer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TreeResponse_Layer.Merge(m, src)
+}
+func (m *TreeResponse_Layer) XXX_Size() int {
+	return xxx_messageInfo_TreeResponse_Layer.Size(m)
+}
+func (m *TreeResponse_Layer) XXX_DiscardUnknown() {
+	xxx_messageInfo_TreeResponse_Layer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TreeResponse_Layer proto.InternalMessageInfo
+
+func (m *TreeResponse_Layer) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *TreeResponse_Layer) GetSize_() string {
+	if m != nil {
+		return m.Size_
+	}
+	return ""
+}
+
+func (m *TreeResponse_Layer) GetRefCount() int32 {
+	if m != nil {
+		return m.RefCount
+	}
+	return 0
+}
+
+type TreeResponse_Image struct {
+	RepoTags             []string              `protobuf:"bytes,1,rep,name=repoTags,proto3" json:"repoTags,omitempty"`
+	Id                   string                `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Layers               []*TreeResponse_Layer `protobuf:"bytes,3,rep,name=layers,proto3" json:"layers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *TreeResponse_Image) Reset()         { *m = TreeResponse_Image{} }
+func (m *TreeResponse_Image) String() string { return proto.CompactTextString(m) }
+func (*TreeResponse_Image) ProtoMessage()    {}
+func (*TreeResponse_Image) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d71ef680555cb937, []int{82, 1}
+}
+func (m *TreeResponse_Image) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TreeResponse_Image.Unmarshal(m, b)
+}
+func (m *TreeResponse_Image) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TreeResponse_Image.Marshal(b, m, deterministic)
+}
+func (m *TreeResponse_Image) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TreeResponse_Image.Merge(m, src)
+}
+func (m *TreeResponse_Image) XXX_Size() int {
+	return xxx_messageInfo_TreeResponse_Image.Size(m)
+}
+func (m *TreeResponse_Image) XXX_DiscardUnknown() {
+	xxx_messageInfo_TreeResponse_Image.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TreeResponse_Image proto.InternalMessageInfo
+
+func (m *TreeResponse_Image) GetRepoTags() []string {
+	if m != nil {
+		return m.RepoTags
+	}
+	return nil
+}
+
+func (m *TreeResponse_Image) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *TreeResponse_Image) GetLayers() []*TreeResponse_Layer {
+	if m != nil {
+		return m.Layers
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("isula.build.v1.HealthCheckResponse_ServingStatus", HealthCheckResponse_ServingStatus_name, HealthCheckResponse_ServingStatus_value)
+	proto.RegisterType((*BuildRequest)(nil), "isula.build.v1.BuildRequest")
+	proto.RegisterType((*ImportRequest)(nil), "isula.build.v1.ImportRequest")
+	proto.RegisterType((*ImportResponse)(nil), "isula.build.v1.ImportResponse")
+	proto.RegisterType((*BuildStatic)(nil), "isula.build.v1.BuildStatic")
+	proto.RegisterType((*BuildResponse)(nil), "isula.build.v1.BuildResponse")
+	proto.RegisterType((*StatusRequest)(nil), "isula.build.v1.StatusRequest")
+	proto.RegisterType((*StatusResponse)(nil), "isula.build.v1.StatusResponse")
+	proto.RegisterType((*ListRequest)(nil), "isula.build.v1.ListRequest")
+	proto.RegisterType((*TagRequest)(nil), "isula.build.v1.TagRequest")
+	proto.RegisterType((*ListResponse)(nil), "isula.build.v1.ListResponse")
+	proto.RegisterType((*ListResponse_ImageInfo)(nil), "isula.build.v1.ListResponse.ImageInfo")
 	proto.RegisterType((*VersionResponse)(nil), "isula.build.v1.VersionResponse")
 	proto.RegisterType((*RemoveRequest)(nil), "isula.build.v1.RemoveRequest")
 	proto.RegisterType((*RemoveResponse)(nil), "isula.build.v1.RemoveResponse")
 	proto.RegisterType((*HealthCheckResponse)(nil), "isula.build.v1.HealthCheckResponse")
+	proto.RegisterType((*HealthCheckResponse_ComponentStatus)(nil), "isula.build.v1.HealthCheckResponse.ComponentStatus")
 	proto.RegisterType((*LoginRequest)(nil), "isula.build.v1.LoginRequest")
 	proto.RegisterType((*LoginResponse)(nil), "isula.build.v1.LoginResponse")
 	proto.RegisterType((*LogoutRequest)(nil), "isula.build.v1.LogoutRequest")
@@ -2387,1029 +5611,1928 @@ func init() {
 	proto.RegisterType((*ManifestInspectResponse)(nil), "isula.build.v1.ManifestInspectResponse")
 	proto.RegisterType((*ManifestPushRequest)(nil), "isula.build.v1.ManifestPushRequest")
 	proto.RegisterType((*ManifestPushResponse)(nil), "isula.build.v1.ManifestPushResponse")
+	proto.RegisterType((*CacheListRequest)(nil), "isula.build.v1.CacheListRequest")
+	proto.RegisterType((*CacheListResponse)(nil), "isula.build.v1.CacheListResponse")
+	proto.RegisterType((*CacheListResponse_CacheInfo)(nil), "isula.build.v1.CacheListResponse.CacheInfo")
+	proto.RegisterType((*CacheRemoveRequest)(nil), "isula.build.v1.CacheRemoveRequest")
+	proto.RegisterType((*CacheRemoveResponse)(nil), "isula.build.v1.CacheRemoveResponse")
+	proto.RegisterType((*BuilderCreateRequest)(nil), "isula.build.v1.BuilderCreateRequest")
+	proto.RegisterType((*BuilderCreateResponse)(nil), "isula.build.v1.BuilderCreateResponse")
+	proto.RegisterType((*BuilderListRequest)(nil), "isula.build.v1.BuilderListRequest")
+	proto.RegisterType((*BuilderListResponse)(nil), "isula.build.v1.BuilderListResponse")
+	proto.RegisterType((*BuilderListResponse_BuilderInfo)(nil), "isula.build.v1.BuilderListResponse.BuilderInfo")
+	proto.RegisterType((*BuilderRemoveRequest)(nil), "isula.build.v1.BuilderRemoveRequest")
+	proto.RegisterType((*BuilderRemoveResponse)(nil), "isula.build.v1.BuilderRemoveResponse")
+	proto.RegisterType((*NegotiateRequest)(nil), "isula.build.v1.NegotiateRequest")
+	proto.RegisterType((*NegotiateResponse)(nil), "isula.build.v1.NegotiateResponse")
+	proto.RegisterType((*BuildsListRequest)(nil), "isula.build.v1.BuildsListRequest")
+	proto.RegisterType((*BuildsListResponse)(nil), "isula.build.v1.BuildsListResponse")
+	proto.RegisterType((*BuildsListResponse_BuildInfo)(nil), "isula.build.v1.BuildsListResponse.BuildInfo")
+	proto.RegisterType((*BuildsListResponse_ContainerInfo)(nil), "isula.build.v1.BuildsListResponse.ContainerInfo")
+	proto.RegisterType((*ConvertRequest)(nil), "isula.build.v1.ConvertRequest")
+	proto.RegisterType((*ConvertResponse)(nil), "isula.build.v1.ConvertResponse")
+	proto.RegisterType((*RemoteInspectRequest)(nil), "isula.build.v1.RemoteInspectRequest")
+	proto.RegisterType((*RemoteInspectResponse)(nil), "isula.build.v1.RemoteInspectResponse")
+	proto.RegisterType((*PruneRequest)(nil), "isula.build.v1.PruneRequest")
+	proto.RegisterType((*PruneResponse)(nil), "isula.build.v1.PruneResponse")
+	proto.RegisterType((*VerifyRemoteRequest)(nil), "isula.build.v1.VerifyRemoteRequest")
+	proto.RegisterType((*VerifyRemoteResponse)(nil), "isula.build.v1.VerifyRemoteResponse")
+	proto.RegisterType((*ImagePruneRequest)(nil), "isula.build.v1.ImagePruneRequest")
+	proto.RegisterType((*ImagePruneResponse)(nil), "isula.build.v1.ImagePruneResponse")
+	proto.RegisterType((*BackupRequest)(nil), "isula.build.v1.BackupRequest")
+	proto.RegisterType((*BackupResponse)(nil), "isula.build.v1.BackupResponse")
+	proto.RegisterType((*RestoreRequest)(nil), "isula.build.v1.RestoreRequest")
+	proto.RegisterType((*RestoreResponse)(nil), "isula.build.v1.RestoreResponse")
+	proto.RegisterType((*RecordsExportRequest)(nil), "isula.build.v1.RecordsExportRequest")
+	proto.RegisterType((*RecordsExportResponse)(nil), "isula.build.v1.RecordsExportResponse")
+	proto.RegisterType((*RecordsImportRequest)(nil), "isula.build.v1.RecordsImportRequest")
+	proto.RegisterType((*RecordsImportResponse)(nil), "isula.build.v1.RecordsImportResponse")
+	proto.RegisterType((*InspectRequest)(nil), "isula.build.v1.InspectRequest")
+	proto.RegisterType((*InspectResponse)(nil), "isula.build.v1.InspectResponse")
+	proto.RegisterType((*PromoteRequest)(nil), "isula.build.v1.PromoteRequest")
+	proto.RegisterType((*PromoteResponse)(nil), "isula.build.v1.PromoteResponse")
+	proto.RegisterType((*DiffRequest)(nil), "isula.build.v1.DiffRequest")
+	proto.RegisterType((*DiffResponse)(nil), "isula.build.v1.DiffResponse")
+	proto.RegisterType((*LoginListRequest)(nil), "isula.build.v1.LoginListRequest")
+	proto.RegisterType((*LoginListResponse)(nil), "isula.build.v1.LoginListResponse")
+	proto.RegisterType((*LoginListResponse_LoginInfo)(nil), "isula.build.v1.LoginListResponse.LoginInfo")
+	proto.RegisterType((*TreeRequest)(nil), "isula.build.v1.TreeRequest")
+	proto.RegisterType((*TreeResponse)(nil), "isula.build.v1.TreeResponse")
+	proto.RegisterType((*TreeResponse_Layer)(nil), "isula.build.v1.TreeResponse.Layer")
+	proto.RegisterType((*TreeResponse_Image)(nil), "isula.build.v1.TreeResponse.Image")
+	proto.RegisterType((*BuildResources)(nil), "isula.build.v1.BuildResources")
+	proto.RegisterType((*MetaKV)(nil), "isula.build.v1.MetaKV")
+	proto.RegisterType((*MetaSetRequest)(nil), "isula.build.v1.MetaSetRequest")
+	proto.RegisterType((*MetaGetRequest)(nil), "isula.build.v1.MetaGetRequest")
+	proto.RegisterType((*MetaGetResponse)(nil), "isula.build.v1.MetaGetResponse")
+	proto.RegisterType((*DfRequest)(nil), "isula.build.v1.DfRequest")
+	proto.RegisterType((*DfEntry)(nil), "isula.build.v1.DfEntry")
+	proto.RegisterType((*DfResponse)(nil), "isula.build.v1.DfResponse")
+}
+
+func init() { proto.RegisterFile("api/services/control.proto", fileDescriptor_d71ef680555cb937) }
+
+var fileDescriptor_d71ef680555cb937 = []byte{
+	// 2001 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x58, 0xdd, 0x73, 0x1c, 0x47,
+	0x11, 0x67, 0xef, 0x4e, 0x5f, 0x7d, 0xa7, 0xb3, 0x6a, 0x63, 0x9c, 0xab, 0xb3, 0x92, 0xa8, 0x36,
+	0x26, 0x16, 0xa6, 0x38, 0xc9, 0x82, 0x07, 0x42, 0x01, 0x85, 0x3e, 0x62, 0x73, 0x60, 0xcb, 0xb0,
+	0x52, 0x42, 0x51, 0xa9, 0xc2, 0x35, 0xba, 0x1b, 0x9d, 0x16, 0xef, 0xee, 0x2c, 0x33, 0xb3, 0x8a,
+	0x8f, 0xfc, 0x15, 0x54, 0xf1, 0xc4, 0xc7, 0xdf, 0xc0, 0x63, 0xfe, 0x02, 0x9e, 0xf8, 0xa7, 0x52,
+	0xdd, 0x33, 0xb3, 0x1f, 0xb7, 0x27, 0xc9, 0x79, 0xdb, 0xfe, 0x98, 0x9e, 0x9e, 0xee, 0xdf, 0x74,
+	0x4f, 0x2f, 0x0c, 0x59, 0x16, 0xed, 0x29, 0x2e, 0xaf, 0xa3, 0x09, 0x57, 0x7b, 0x13, 0x91, 0x6a,
+	0x29, 0xe2, 0x51, 0x26, 0x85, 0x16, 0x7e, 0x3f, 0x52, 0x79, 0xcc, 0x46, 0x17, 0x79, 0x14, 0x4f,
+	0x47, 0xd7, 0x4f, 0x87, 0x0f, 0x67, 0x42, 0xcc, 0x62, 0xbe, 0x47, 0xd2, 0x8b, 0xfc, 0x72, 0x8f,
+	0x27, 0x99, 0x9e, 0x1b, 0xe5, 0xe1, 0x47, 0x8b, 0x42, 0x1d, 0x25, 0x5c, 0x69, 0x96, 0x64, 0x46,
+	0x21, 0xf8, 0x5f, 0x1b, 0x7a, 0x47, 0x68, 0x2a, 0xe4, 0x7f, 0xcd, 0xb9, 0xd2, 0xfe, 0x00, 0xd6,
+	0xc8, 0xf4, 0xf8, 0x64, 0xe0, 0xed, 0x78, 0xbb, 0x1b, 0xa1, 0x23, 0xfd, 0x6d, 0xd8, 0xa0, 0xcf,
+	0xf3, 0x79, 0xc6, 0x07, 0x2d, 0x92, 0x95, 0x0c, 0xff, 0x43, 0x00, 0xf4, 0x93, 0xbf, 0xd5, 0x27,
+	0x91, 0x1c, 0xb4, 0x49, 0x5c, 0xe1, 0xf8, 0x3b, 0xd0, 0xbd, 0x8c, 0x62, 0x7e, 0x8c, 0x9c, 0x54,
+	0x0f, 0x3a, 0xa4, 0x50, 0x65, 0xf9, 0x0f, 0x60, 0x55, 0xe4, 0x3a, 0xcb, 0xf5, 0x60, 0x85, 0x84,
+	0x96, 0x2a, 0xf6, 0x3d, 0x94, 0x33, 0x35, 0x58, 0xdd, 0x69, 0x17, 0xfb, 0x22, 0xc3, 0xbf, 0x0f,
+	0x2b, 0x99, 0x14, 0x6f, 0xe7, 0x83, 0xb5, 0x1d, 0x6f, 0x77, 0x3d, 0x34, 0x04, 0x9e, 0x22, 0x8a,
+	0xa6, 0x68, 0x7d, 0xb0, 0x6e, 0x4e, 0x61, 0x49, 0xff, 0x97, 0xd0, 0xa5, 0xc5, 0x67, 0x9a, 0xe9,
+	0x68, 0x32, 0xd8, 0xd8, 0xf1, 0x76, 0xbb, 0x07, 0x0f, 0x47, 0xf5, 0xa0, 0x8e, 0x8e, 0x4a, 0x95,
+	0xb0, 0xaa, 0xef, 0x3f, 0x82, 0x4d, 0x36, 0x9d, 0x46, 0x3a, 0x12, 0x29, 0x8b, 0xcf, 0xd9, 0x6c,
+	0x00, 0x64, 0xbe, 0xce, 0xa4, 0x60, 0xb0, 0xec, 0x70, 0x3a, 0x7d, 0x11, 0x29, 0x3d, 0xe8, 0x92,
+	0xcf, 0x15, 0x8e, 0x3f, 0x84, 0x75, 0x9e, 0xea, 0x48, 0xcf, 0xc7, 0x27, 0x83, 0x1e, 0x19, 0x28,
+	0x68, 0x3c, 0x2e, 0x4f, 0x27, 0x72, 0x9e, 0x69, 0x3e, 0x1d, 0x6c, 0xd2, 0xa1, 0x4a, 0x06, 0x06,
+	0xe9, 0x52, 0xc8, 0x84, 0xe9, 0x41, 0xdf, 0x04, 0xc9, 0x50, 0x01, 0x83, 0xcd, 0x71, 0x92, 0x09,
+	0xa9, 0x5d, 0x1e, 0x87, 0xb0, 0x1e, 0x11, 0xa3, 0x48, 0x64, 0x41, 0xa3, 0x11, 0x25, 0x72, 0x39,
+	0x71, 0x69, 0xb4, 0x14, 0x6e, 0x2d, 0xf9, 0x25, 0x97, 0x3c, 0x9d, 0x70, 0x9b, 0xc2, 0x92, 0x11,
+	0x04, 0xd0, 0x77, 0x5b, 0xa8, 0x4c, 0xa4, 0x8a, 0xfb, 0x5b, 0xd0, 0x8e, 0xc5, 0xcc, 0x9a, 0xc7,
+	0xcf, 0xe0, 0x39, 0x74, 0x2b, 0xa1, 0xf3, 0x7f, 0xe6, 0x20, 0x13, 0x25, 0x9c, 0xd4, 0xba, 0x07,
+	0xc3, 0x91, 0x81, 0xe4, 0xc8, 0x41, 0x72, 0x74, 0xee, 0x20, 0x19, 0x96, 0xca, 0xc1, 0x0f, 0x61,
+	0xd3, 0xc2, 0xd2, 0xee, 0x85, 0x19, 0x4d, 0xd8, 0x8c, 0x97, 0xb8, 0xb4, 0x24, 0xaa, 0xe2, 0x76,
+	0xb9, 0xba, 0x13, 0xc2, 0xc1, 0x13, 0xe8, 0x3b, 0xd5, 0xd2, 0xec, 0xc4, 0x42, 0xd2, 0xea, 0x5a,
+	0x32, 0xf8, 0x11, 0x74, 0x31, 0x57, 0xce, 0xe8, 0x36, 0x6c, 0xd0, 0x86, 0xa7, 0xcc, 0x1e, 0x65,
+	0x23, 0x2c, 0x19, 0xc1, 0x4f, 0x01, 0xce, 0xd9, 0xcc, 0xe9, 0xde, 0x87, 0x15, 0x12, 0x59, 0x3d,
+	0x43, 0x60, 0xb4, 0x34, 0x9b, 0xd9, 0x90, 0xe3, 0x67, 0xf0, 0x7f, 0x0f, 0x7a, 0x66, 0x0f, 0xeb,
+	0xcd, 0xaf, 0x60, 0x95, 0x74, 0xd5, 0xc0, 0xdb, 0x69, 0xef, 0x76, 0x0f, 0x3e, 0x59, 0xc4, 0x65,
+	0x55, 0x7b, 0x34, 0xa6, 0x00, 0xa4, 0x97, 0x22, 0xb4, 0xab, 0x86, 0x5f, 0xc3, 0x46, 0xc1, 0x44,
+	0x10, 0x4a, 0x9e, 0x09, 0x15, 0x69, 0x21, 0xe7, 0xd6, 0x95, 0x0a, 0xa7, 0xe9, 0x8f, 0xdf, 0x87,
+	0x56, 0x34, 0xb5, 0x89, 0x6f, 0x45, 0x53, 0x0a, 0x8e, 0xe4, 0x0c, 0x81, 0xd8, 0xb1, 0xc1, 0x31,
+	0xa4, 0xef, 0x43, 0x47, 0x45, 0x7f, 0xe3, 0xf6, 0xa6, 0xd2, 0x77, 0xf0, 0x1f, 0x0f, 0xee, 0x7d,
+	0xc1, 0xa5, 0x8a, 0x44, 0x5a, 0x0d, 0xef, 0xb5, 0x61, 0xb9, 0xf0, 0x5a, 0x12, 0xe3, 0x39, 0x13,
+	0x56, 0xdd, 0x55, 0x93, 0x82, 0x41, 0xd2, 0x48, 0x1f, 0x8b, 0x24, 0x89, 0xb4, 0x43, 0x62, 0xc1,
+	0x28, 0x2b, 0x11, 0xc2, 0xaa, 0x53, 0xad, 0x44, 0x51, 0xc2, 0xa9, 0x8e, 0xa8, 0x43, 0x39, 0xb9,
+	0x2a, 0xea, 0x08, 0x51, 0xc1, 0x1f, 0x60, 0x33, 0xe4, 0x89, 0xb8, 0xe6, 0x15, 0x9c, 0x94, 0x90,
+	0x6a, 0x57, 0x20, 0x85, 0xa1, 0x61, 0x71, 0x4c, 0x6e, 0xad, 0x87, 0xf8, 0x69, 0xca, 0x4c, 0x9e,
+	0x9a, 0x6b, 0x41, 0x65, 0x26, 0x4f, 0x31, 0xed, 0x7d, 0x67, 0xd2, 0x1e, 0x38, 0x80, 0x5e, 0xcc,
+	0xe6, 0x5c, 0xbe, 0xe4, 0x4a, 0x95, 0x08, 0xa8, 0xf1, 0x82, 0x7f, 0x7b, 0xf0, 0xde, 0x6f, 0x38,
+	0x8b, 0xf5, 0xd5, 0xf1, 0x15, 0x9f, 0xbc, 0x29, 0xd6, 0x8e, 0x61, 0x55, 0x11, 0x3a, 0x69, 0x55,
+	0xff, 0xe0, 0xe9, 0x62, 0xf6, 0x97, 0x2c, 0x1a, 0x9d, 0x61, 0x97, 0x48, 0x67, 0x16, 0xd6, 0xd6,
+	0x40, 0xf0, 0x73, 0xd8, 0xac, 0x09, 0xfc, 0x2e, 0xac, 0x7d, 0x7e, 0xfa, 0xbb, 0xd3, 0x57, 0x7f,
+	0x3c, 0xdd, 0xfa, 0x1e, 0x12, 0x67, 0x9f, 0x85, 0x5f, 0x8c, 0x4f, 0x9f, 0x6f, 0x79, 0xfe, 0x3d,
+	0xe8, 0x9e, 0xbe, 0x3a, 0x7f, 0xed, 0x18, 0xad, 0xe0, 0xcf, 0xd0, 0x7b, 0x21, 0x66, 0x51, 0xea,
+	0xc2, 0x84, 0xd5, 0x82, 0xcb, 0x6b, 0x2e, 0xed, 0x61, 0x2c, 0x85, 0x15, 0x26, 0x57, 0x5c, 0xa6,
+	0x78, 0x21, 0x4c, 0x02, 0x0b, 0x1a, 0x65, 0x19, 0x53, 0xea, 0x2b, 0x21, 0x1d, 0x9e, 0x0a, 0x1a,
+	0xef, 0xab, 0xb5, 0x7f, 0xe7, 0x1d, 0xfc, 0x94, 0x54, 0x45, 0xae, 0xef, 0xf2, 0xa5, 0x91, 0xb0,
+	0x60, 0x17, 0xfa, 0x6e, 0xa9, 0xdd, 0xe6, 0x01, 0xac, 0x4a, 0xae, 0xf2, 0xd8, 0xed, 0x62, 0xa9,
+	0xe0, 0x2f, 0xd0, 0x7d, 0x21, 0x58, 0xd1, 0x00, 0x7d, 0xe8, 0x64, 0x4c, 0x5f, 0x59, 0x25, 0xfa,
+	0xc6, 0xa5, 0xb1, 0x60, 0x58, 0x50, 0x6c, 0xc1, 0x34, 0x94, 0xbf, 0x07, 0x6d, 0xc5, 0x33, 0x3a,
+	0x61, 0xf7, 0xe0, 0x83, 0xc5, 0x74, 0x9d, 0xf1, 0x8c, 0x49, 0xa6, 0x85, 0x24, 0xf3, 0xa8, 0x19,
+	0xfc, 0xdd, 0xc3, 0xc4, 0x54, 0xd8, 0xe4, 0x79, 0x96, 0xb9, 0x1a, 0xca, 0xb2, 0x0c, 0x39, 0xd3,
+	0x48, 0xba, 0x7b, 0x39, 0x8d, 0x24, 0xba, 0x74, 0xc1, 0x94, 0x2b, 0xc9, 0xf4, 0x4d, 0xb5, 0x37,
+	0xba, 0xb0, 0xe8, 0xc7, 0x4f, 0xbc, 0x15, 0xea, 0x4d, 0x94, 0x11, 0x3c, 0x08, 0xfa, 0xeb, 0x61,
+	0xc9, 0xc0, 0x20, 0xf3, 0x94, 0x5d, 0xc4, 0x7c, 0x3a, 0x58, 0x25, 0x99, 0x23, 0x83, 0x1d, 0xcc,
+	0x37, 0x9b, 0xde, 0x52, 0xd5, 0xbf, 0x84, 0xee, 0xef, 0x73, 0x75, 0x55, 0x49, 0x42, 0x96, 0xab,
+	0xab, 0xa2, 0xbc, 0x5a, 0xaa, 0x5e, 0x22, 0x5b, 0x0b, 0x25, 0xb2, 0xd2, 0xb9, 0xda, 0xb5, 0xce,
+	0xf5, 0x04, 0x7a, 0xc6, 0xb8, 0xdd, 0x7e, 0x08, 0xeb, 0xd2, 0x7e, 0xbb, 0xc6, 0xe5, 0xe8, 0xe0,
+	0x18, 0x1d, 0x89, 0xe3, 0x9a, 0x23, 0x71, 0x5c, 0x75, 0x04, 0xa9, 0xdb, 0x1d, 0x31, 0x1b, 0xa2,
+	0x91, 0x77, 0xd8, 0xf0, 0x9f, 0x1e, 0x74, 0xcf, 0x58, 0x59, 0x32, 0x10, 0x7f, 0xec, 0xba, 0x6c,
+	0x42, 0x96, 0x42, 0xbe, 0x2d, 0xdc, 0x2d, 0xaa, 0x24, 0x96, 0x2a, 0xc0, 0xd4, 0xae, 0x83, 0xc9,
+	0x06, 0xa2, 0x53, 0x0d, 0x84, 0x03, 0xd3, 0xca, 0x1d, 0x60, 0x22, 0x77, 0x08, 0x4c, 0x5f, 0x57,
+	0xb0, 0x84, 0xdc, 0x02, 0x27, 0x5e, 0x13, 0x27, 0xad, 0x12, 0x27, 0x74, 0x0f, 0xe8, 0xd6, 0xb6,
+	0xdd, 0x3d, 0xa0, 0x3b, 0xeb, 0x43, 0x67, 0xca, 0x95, 0xf3, 0x8a, 0xbe, 0xab, 0xa8, 0x59, 0x69,
+	0xa0, 0xc6, 0x04, 0xe6, 0x46, 0xd4, 0xcc, 0x61, 0xed, 0x25, 0x4f, 0x4e, 0x98, 0x66, 0x18, 0xe2,
+	0x84, 0x27, 0xe7, 0x42, 0xb3, 0x98, 0x34, 0xda, 0x61, 0x41, 0xe3, 0x16, 0x09, 0x4f, 0x9e, 0x49,
+	0x6e, 0x52, 0xd5, 0x0e, 0x1d, 0x49, 0x80, 0xfe, 0x8a, 0x65, 0x66, 0x59, 0x9b, 0x64, 0x25, 0x03,
+	0x6d, 0x22, 0x41, 0x0b, 0x3b, 0xc6, 0xa6, 0xa3, 0x83, 0x6f, 0x3c, 0xda, 0x1b, 0x6b, 0x1f, 0x1e,
+	0x37, 0xe1, 0xc9, 0xd9, 0xdc, 0x54, 0xd5, 0x4e, 0x68, 0x29, 0xdc, 0xf7, 0x8a, 0xb3, 0x0c, 0x05,
+	0x2d, 0x12, 0x38, 0x12, 0xf7, 0xc5, 0xcf, 0xc3, 0x38, 0x16, 0x13, 0xda, 0xb7, 0x13, 0x96, 0x0c,
+	0x27, 0x1d, 0xa7, 0x9f, 0x2b, 0xb3, 0xb1, 0x95, 0x12, 0x03, 0xbd, 0x22, 0x62, 0x1a, 0x9b, 0xe6,
+	0xd8, 0x09, 0x0b, 0x1a, 0x7b, 0x03, 0x7e, 0x87, 0x3c, 0xe6, 0x4c, 0xd9, 0x7b, 0xd8, 0x09, 0x6b,
+	0xbc, 0xe0, 0x35, 0x74, 0xcf, 0xb4, 0x90, 0x6c, 0xc6, 0x29, 0x70, 0x8f, 0x60, 0x53, 0x59, 0x52,
+	0x46, 0x65, 0xd9, 0xab, 0x33, 0xfd, 0x27, 0xb0, 0x65, 0x19, 0x47, 0x6c, 0xf2, 0x26, 0x4a, 0x67,
+	0xcf, 0x94, 0x4d, 0x78, 0x83, 0x1f, 0xfc, 0xc3, 0x83, 0x5e, 0xc8, 0x67, 0x91, 0xd2, 0x72, 0x4e,
+	0x5b, 0x3c, 0x81, 0x2d, 0x69, 0xe8, 0x88, 0xab, 0x33, 0xce, 0xb0, 0x71, 0x9a, 0x76, 0xd8, 0xe0,
+	0xfb, 0x23, 0xf0, 0x4b, 0xde, 0x38, 0x55, 0x7c, 0x92, 0x4b, 0x6e, 0x21, 0xbf, 0x44, 0xe2, 0xef,
+	0xc2, 0xbd, 0x92, 0x7b, 0x14, 0x8b, 0xc9, 0x9b, 0x41, 0x9b, 0x94, 0x17, 0xd9, 0xc1, 0x63, 0xe8,
+	0xd2, 0x4b, 0xa6, 0x6c, 0xcd, 0xd7, 0x5c, 0x5e, 0x08, 0x0b, 0xe6, 0xf5, 0xd0, 0x91, 0xc1, 0x7f,
+	0xdb, 0xd0, 0x33, 0x9a, 0x16, 0x78, 0x4f, 0x09, 0x3f, 0xc8, 0xb2, 0x2f, 0xcc, 0xf7, 0x17, 0xaf,
+	0x8e, 0x45, 0x61, 0xe8, 0xf4, 0x70, 0x06, 0xb0, 0x71, 0xa1, 0x65, 0xad, 0xe5, 0x33, 0x40, 0x25,
+	0x0f, 0x61, 0x55, 0xdf, 0xff, 0x35, 0xf4, 0xac, 0xfb, 0x73, 0x5a, 0x6f, 0xca, 0xff, 0xf6, 0xe2,
+	0xfa, 0x6a, 0x94, 0xc3, 0xda, 0x0a, 0x44, 0xc9, 0x14, 0xb9, 0x42, 0xb8, 0xeb, 0x56, 0xd0, 0x78,
+	0x74, 0x99, 0xa7, 0x24, 0x32, 0xef, 0x17, 0x47, 0xe2, 0x83, 0xee, 0xd5, 0xf1, 0x38, 0xcc, 0x53,
+	0x1c, 0xe2, 0x08, 0x3d, 0x1b, 0x61, 0x85, 0x83, 0x72, 0xda, 0x9c, 0xcb, 0xd3, 0x3c, 0xa1, 0x79,
+	0xa8, 0x1d, 0x56, 0x38, 0x28, 0x9f, 0x89, 0x50, 0xe4, 0x3a, 0x4a, 0xb9, 0xa2, 0xb9, 0xa8, 0x1d,
+	0x56, 0x38, 0x36, 0x92, 0x78, 0x69, 0xec, 0x58, 0xb4, 0x2c, 0x92, 0x28, 0x0e, 0x9d, 0x1e, 0x42,
+	0x9a, 0xbf, 0xcd, 0xb8, 0x8c, 0x12, 0x9e, 0xe2, 0x2d, 0x05, 0x4a, 0x56, 0x8d, 0x17, 0xfc, 0x09,
+	0xbe, 0xff, 0x92, 0xa5, 0xd1, 0x25, 0x57, 0xfa, 0x98, 0x9e, 0x8f, 0x2e, 0xc9, 0x01, 0xf4, 0x12,
+	0x2b, 0xa0, 0x39, 0xc9, 0xbe, 0x95, 0xaa, 0x3c, 0xbc, 0x6d, 0x8e, 0x76, 0xb5, 0xb5, 0x64, 0x04,
+	0x07, 0xf0, 0x60, 0xd1, 0xf4, 0x9d, 0xe3, 0xc2, 0x37, 0x1e, 0xbc, 0xef, 0x16, 0x1d, 0xa6, 0xa9,
+	0xd0, 0xdf, 0xd1, 0x23, 0xac, 0x65, 0x96, 0x76, 0xcf, 0x1e, 0x47, 0x63, 0x09, 0xa5, 0xfb, 0x63,
+	0xcb, 0x3d, 0xdd, 0x99, 0x3e, 0xb4, 0x84, 0xb2, 0x59, 0x6e, 0x09, 0x85, 0x59, 0x10, 0xea, 0x19,
+	0x67, 0x3a, 0x97, 0x5c, 0x0d, 0x56, 0xcc, 0x6c, 0x58, 0x72, 0x08, 0xfa, 0x4c, 0x46, 0x2c, 0xd5,
+	0x36, 0xc5, 0x8e, 0x0c, 0x7e, 0x51, 0x9e, 0x76, 0x9c, 0xaa, 0x8c, 0x4f, 0xf4, 0x77, 0xf0, 0x3b,
+	0xf8, 0x71, 0x79, 0xec, 0x62, 0xb5, 0x0d, 0x16, 0x56, 0x7e, 0xa6, 0x19, 0x2d, 0xeb, 0x85, 0xf4,
+	0x1d, 0xbc, 0x84, 0xf7, 0x9c, 0x7a, 0xb5, 0xf7, 0xbf, 0x4b, 0x84, 0x5c, 0x23, 0x69, 0x95, 0x8d,
+	0x24, 0x18, 0xc1, 0xfd, 0xba, 0xb9, 0xdb, 0x1f, 0x65, 0x07, 0xff, 0xea, 0xc2, 0xda, 0xb1, 0xf9,
+	0xef, 0xe1, 0x9f, 0xc0, 0x0a, 0xcd, 0x82, 0xfe, 0xf6, 0xd2, 0x31, 0xdd, 0xba, 0x36, 0xfc, 0xe0,
+	0x06, 0x69, 0xf9, 0xba, 0xb6, 0x6f, 0xe1, 0x66, 0x6f, 0xad, 0x8e, 0x8f, 0xc3, 0x0f, 0x6f, 0x12,
+	0x1b, 0x43, 0xfb, 0x9e, 0x7f, 0x08, 0x1d, 0x3a, 0xe8, 0xc3, 0xe5, 0xe3, 0x99, 0x31, 0xb3, 0x7d,
+	0xdb, 0xec, 0xe6, 0x1f, 0xc1, 0x9a, 0x9b, 0x75, 0x1e, 0x34, 0x26, 0xe2, 0xcf, 0x92, 0x4c, 0xcf,
+	0x87, 0x1f, 0x2d, 0x1a, 0x58, 0x1c, 0xae, 0x8e, 0xa1, 0x83, 0xb1, 0x6c, 0xba, 0x51, 0x49, 0x58,
+	0xd3, 0x8d, 0x6a, 0xf8, 0xf7, 0x3d, 0x63, 0x24, 0x8e, 0x97, 0x19, 0x29, 0x1e, 0x5a, 0xcb, 0x8c,
+	0x94, 0x0f, 0xa8, 0x7d, 0x0f, 0x63, 0x6b, 0xe6, 0xa0, 0x66, 0x6c, 0x6b, 0x23, 0x57, 0x33, 0xb6,
+	0xf5, 0xf1, 0x69, 0xdf, 0xf3, 0x7f, 0x0b, 0xdd, 0xca, 0x98, 0x73, 0x63, 0x70, 0x3e, 0x7e, 0x87,
+	0xd9, 0x08, 0x81, 0x43, 0x93, 0x46, 0x13, 0x38, 0xd5, 0x01, 0xa7, 0x09, 0x9c, 0xfa, 0x78, 0xf2,
+	0x1c, 0x56, 0xcd, 0x24, 0xe1, 0x2f, 0x53, 0x2c, 0x87, 0x93, 0xe6, 0xe1, 0x16, 0x06, 0x90, 0x63,
+	0xe8, 0xd0, 0x93, 0xbf, 0x09, 0x9b, 0x72, 0xfc, 0x58, 0x02, 0x9b, 0xca, 0xdb, 0xdc, 0x84, 0xda,
+	0xfc, 0x85, 0x69, 0x7a, 0x53, 0xfb, 0x01, 0xd4, 0xf4, 0xa6, 0xfe, 0xf3, 0x66, 0xdf, 0xf3, 0x3f,
+	0x85, 0xf6, 0x39, 0x9b, 0xf9, 0xc3, 0x45, 0xc5, 0xf2, 0x4f, 0xc6, 0xf0, 0x86, 0xf0, 0xe3, 0x51,
+	0xe8, 0xc5, 0xd9, 0x6c, 0x9a, 0xe5, 0x63, 0xb9, 0x79, 0x94, 0xea, 0x83, 0xd1, 0x5c, 0x23, 0xea,
+	0x86, 0x0d, 0x23, 0x95, 0x97, 0x40, 0xd3, 0x48, 0xad, 0xf9, 0xbf, 0x86, 0x7e, 0xbd, 0x01, 0xf8,
+	0x3f, 0x68, 0xf4, 0xac, 0x65, 0xbd, 0x67, 0xf8, 0xc9, 0x5d, 0x6a, 0x76, 0x83, 0x33, 0xd8, 0x5a,
+	0x6c, 0x16, 0xfe, 0xe3, 0x9b, 0xd6, 0x2e, 0xb4, 0x93, 0x1b, 0xa3, 0x77, 0x01, 0xf7, 0x16, 0x4a,
+	0xb1, 0x7f, 0xa3, 0x3f, 0xf5, 0x4a, 0x3f, 0x7c, 0x7c, 0xa7, 0x9e, 0x75, 0xfc, 0x4b, 0xe8, 0x55,
+	0x0b, 0xae, 0xff, 0xf1, 0x4d, 0x0b, 0xab, 0xc5, 0xe2, 0xd1, 0xed, 0x4a, 0x2e, 0x73, 0x17, 0xab,
+	0x74, 0xa0, 0x9f, 0x7c, 0x1b, 0x00, 0x00, 0xff, 0xff, 0x24, 0x62, 0xe9, 0xd4, 0xa8, 0x16, 0x00,
+	0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ControlClient is the client API for Control service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ControlClient interface {
+	// Build requests a new image building
+	Build(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*BuildResponse, error)
+	// Status pipes the image building process log back to client
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (Control_StatusClient, error)
+	// List lists all images in isula-builder
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	// Version requests version information of isula-builder
+	Version(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*VersionResponse, error)
+	// Push pushes image to remote repository
+	Push(ctx context.Context, in *PushRequest, opts ...grpc.CallOption) (Control_PushClient, error)
+	// Pull pulls image from remote repository
+	Pull(ctx context.Context, in *PullRequest, opts ...grpc.CallOption) (Control_PullClient, error)
+	// Remove sends an image remove request to isula-builder
+	Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (Control_RemoveClient, error)
+	// HealthCheck requests a health checking in isula-builder
+	HealthCheck(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*HealthCheckResponse, error)
+	// Login requests to access image registry with username and password
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	// Logout requests to logout registry and delete any credentials
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
+	// Load requests an image tar load
+	Load(ctx context.Context, in *LoadRequest, opts ...grpc.CallOption) (Control_LoadClient, error)
+	// Import requests import a new image
+	Import(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (Control_ImportClient, error)
+	// Convert requests converting an image between archive formats
+	Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (Control_ConvertClient, error)
+	// Tag requests to tag an image
+	Tag(ctx context.Context, in *TagRequest, opts ...grpc.CallOption) (*types.Empty, error)
+	// Save saves the image to tarball
+	Save(ctx context.Context, in *SaveRequest, opts ...grpc.CallOption) (Control_SaveClient, error)
+	// Info requests isula-build system information
+	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error)
+	// ManifestCreate requests to create manifest list
+	ManifestCreate(ctx context.Context, in *ManifestCreateRequest, opts ...grpc.CallOption) (*ManifestCreateResponse, error)
+	// ManifestAnnotate requests to annotate manifest list
+	ManifestAnnotate(ctx context.Context, in *ManifestAnnotateRequest, opts ...grpc.CallOption) (*types.Empty, error)
+	// ManifestInspect requests to inspect manifest list
+	ManifestInspect(ctx context.Context, in *ManifestInspectRequest, opts ...grpc.CallOption) (*ManifestInspectResponse, error)
+	// ManifestPush requests to push manifest list
+	ManifestPush(ctx context.Context, in *ManifestPushRequest, opts ...grpc.CallOption) (Control_ManifestPushClient, error)
+	// CacheList lists the entries in the daemon url download cache
+	CacheList(ctx context.Context, in *CacheListRequest, opts ...grpc.CallOption) (*CacheListResponse, error)
+	// CacheRemove removes entries from the daemon url download cache
+	CacheRemove(ctx context.Context, in *CacheRemoveRequest, opts ...grpc.CallOption) (*CacheRemoveResponse, error)
+	// BuilderCreate creates a named builder instance carrying build defaults
+	BuilderCreate(ctx context.Context, in *BuilderCreateRequest, opts ...grpc.CallOption) (*BuilderCreateResponse, error)
+	// BuilderList lists the named builder instances
+	BuilderList(ctx context.Context, in *BuilderListRequest, opts ...grpc.CallOption) (*BuilderListResponse, error)
+	// BuilderRemove removes a named builder instance
+	BuilderRemove(ctx context.Context, in *BuilderRemoveRequest, opts ...grpc.CallOption) (*BuilderRemoveResponse, error)
+	// Negotiate exchanges the API version and capabilities between client and daemon
+	Negotiate(ctx context.Context, in *NegotiateRequest, opts ...grpc.CallOption) (*NegotiateResponse, error)
+	// BuildsList lists the builds currently running in the daemon
+	BuildsList(ctx context.Context, in *BuildsListRequest, opts ...grpc.CallOption) (*BuildsListResponse, error)
+	// RemoteInspect fetches only the manifest and config of a remote image
+	RemoteInspect(ctx context.Context, in *RemoteInspectRequest, opts ...grpc.CallOption) (*RemoteInspectResponse, error)
+	// Prune clears accumulated data left behind by interrupted sessions
+	Prune(ctx context.Context, in *PruneRequest, opts ...grpc.CallOption) (*PruneResponse, error)
+	// VerifyRemote compares a local image with its copy in a registry
+	VerifyRemote(ctx context.Context, in *VerifyRemoteRequest, opts ...grpc.CallOption) (*VerifyRemoteResponse, error)
+	// ImagePrune removes dangling images, unreferenced layers and leftover build containers
+	ImagePrune(ctx context.Context, in *ImagePruneRequest, opts ...grpc.CallOption) (*ImagePruneResponse, error)
+	// Backup snapshots the image store into a portable archive
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*BackupResponse, error)
+	// Restore loads the images of a backup archive into the store
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error)
+	// RecordsExport bundles the persisted build records into a signed archive
+	RecordsExport(ctx context.Context, in *RecordsExportRequest, opts ...grpc.CallOption) (*RecordsExportResponse, error)
+	// RecordsImport verifies a signed record bundle and stores its records
+	RecordsImport(ctx context.Context, in *RecordsImportRequest, opts ...grpc.CallOption) (*RecordsImportResponse, error)
+	// Inspect returns the config, manifest and layer digests of a local image
+	Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error)
+	// Promote retags and pushes an image from one logical environment to another
+	Promote(ctx context.Context, in *PromoteRequest, opts ...grpc.CallOption) (*PromoteResponse, error)
+	// Diff lists the file changes between two local images
+	Diff(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error)
+	// LoginList lists the registries with stored credentials
+	LoginList(ctx context.Context, in *LoginListRequest, opts ...grpc.CallOption) (*LoginListResponse, error)
+	// Tree shows the layer sharing between local images
+	Tree(ctx context.Context, in *TreeRequest, opts ...grpc.CallOption) (*TreeResponse, error)
+	// MetaSet attaches user metadata key/values to a local image
+	MetaSet(ctx context.Context, in *MetaSetRequest, opts ...grpc.CallOption) (*types.Empty, error)
+	// MetaGet returns the user metadata of a local image
+	MetaGet(ctx context.Context, in *MetaGetRequest, opts ...grpc.CallOption) (*MetaGetResponse, error)
+	// Df reports the image storage usage grouped by team or by owner
+	Df(ctx context.Context, in *DfRequest, opts ...grpc.CallOption) (*DfResponse, error)
+}
+
+type controlClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewControlClient(cc *grpc.ClientConn) ControlClient {
+	return &controlClient{cc}
+}
+
+func (c *controlClient) Build(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*BuildResponse, error) {
+	out := new(BuildResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Build", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (Control_StatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[0], "/isula.build.v1.Control/Status", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_StatusClient interface {
+	Recv() (*StatusResponse, error)
+	grpc.ClientStream
+}
+
+type controlStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlStatusClient) Recv() (*StatusResponse, error) {
+	m := new(StatusResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Version(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*VersionResponse, error) {
+	out := new(VersionResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Version", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Push(ctx context.Context, in *PushRequest, opts ...grpc.CallOption) (Control_PushClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[1], "/isula.build.v1.Control/Push", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlPushClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_PushClient interface {
+	Recv() (*PushResponse, error)
+	grpc.ClientStream
+}
+
+type controlPushClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlPushClient) Recv() (*PushResponse, error) {
+	m := new(PushResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlClient) Pull(ctx context.Context, in *PullRequest, opts ...grpc.CallOption) (Control_PullClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[2], "/isula.build.v1.Control/Pull", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlPullClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_PullClient interface {
+	Recv() (*PullResponse, error)
+	grpc.ClientStream
+}
+
+type controlPullClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlPullClient) Recv() (*PullResponse, error) {
+	m := new(PullResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlClient) Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (Control_RemoveClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[3], "/isula.build.v1.Control/Remove", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlRemoveClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_RemoveClient interface {
+	Recv() (*RemoveResponse, error)
+	grpc.ClientStream
+}
+
+type controlRemoveClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlRemoveClient) Recv() (*RemoveResponse, error) {
+	m := new(RemoveResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlClient) HealthCheck(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
+	out := new(HealthCheckResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/HealthCheck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Login", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error) {
+	out := new(LogoutResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Logout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Load(ctx context.Context, in *LoadRequest, opts ...grpc.CallOption) (Control_LoadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[4], "/isula.build.v1.Control/Load", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlLoadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_LoadClient interface {
+	Recv() (*LoadResponse, error)
+	grpc.ClientStream
+}
+
+type controlLoadClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlLoadClient) Recv() (*LoadResponse, error) {
+	m := new(LoadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlClient) Import(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (Control_ImportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[5], "/isula.build.v1.Control/Import", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlImportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_ImportClient interface {
+	Recv() (*ImportResponse, error)
+	grpc.ClientStream
+}
+
+type controlImportClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlImportClient) Recv() (*ImportResponse, error) {
+	m := new(ImportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlClient) Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (Control_ConvertClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[8], "/isula.build.v1.Control/Convert", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlConvertClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_ConvertClient interface {
+	Recv() (*ConvertResponse, error)
+	grpc.ClientStream
+}
+
+type controlConvertClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlConvertClient) Recv() (*ConvertResponse, error) {
+	m := new(ConvertResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlClient) Tag(ctx context.Context, in *TagRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	out := new(types.Empty)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Tag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Save(ctx context.Context, in *SaveRequest, opts ...grpc.CallOption) (Control_SaveClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[6], "/isula.build.v1.Control/Save", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlSaveClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_SaveClient interface {
+	Recv() (*SaveResponse, error)
+	grpc.ClientStream
+}
+
+type controlSaveClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlSaveClient) Recv() (*SaveResponse, error) {
+	m := new(SaveResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlClient) Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error) {
+	out := new(InfoResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Info", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) ManifestCreate(ctx context.Context, in *ManifestCreateRequest, opts ...grpc.CallOption) (*ManifestCreateResponse, error) {
+	out := new(ManifestCreateResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/ManifestCreate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) ManifestAnnotate(ctx context.Context, in *ManifestAnnotateRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	out := new(types.Empty)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/ManifestAnnotate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) ManifestInspect(ctx context.Context, in *ManifestInspectRequest, opts ...grpc.CallOption) (*ManifestInspectResponse, error) {
+	out := new(ManifestInspectResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/ManifestInspect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) ManifestPush(ctx context.Context, in *ManifestPushRequest, opts ...grpc.CallOption) (Control_ManifestPushClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[7], "/isula.build.v1.Control/ManifestPush", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlManifestPushClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_ManifestPushClient interface {
+	Recv() (*ManifestPushResponse, error)
+	grpc.ClientStream
+}
+
+type controlManifestPushClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlManifestPushClient) Recv() (*ManifestPushResponse, error) {
+	m := new(ManifestPushResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlClient) CacheList(ctx context.Context, in *CacheListRequest, opts ...grpc.CallOption) (*CacheListResponse, error) {
+	out := new(CacheListResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/CacheList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) BuilderCreate(ctx context.Context, in *BuilderCreateRequest, opts ...grpc.CallOption) (*BuilderCreateResponse, error) {
+	out := new(BuilderCreateResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/BuilderCreate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) BuilderList(ctx context.Context, in *BuilderListRequest, opts ...grpc.CallOption) (*BuilderListResponse, error) {
+	out := new(BuilderListResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/BuilderList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) BuilderRemove(ctx context.Context, in *BuilderRemoveRequest, opts ...grpc.CallOption) (*BuilderRemoveResponse, error) {
+	out := new(BuilderRemoveResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/BuilderRemove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) CacheRemove(ctx context.Context, in *CacheRemoveRequest, opts ...grpc.CallOption) (*CacheRemoveResponse, error) {
+	out := new(CacheRemoveResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/CacheRemove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Negotiate(ctx context.Context, in *NegotiateRequest, opts ...grpc.CallOption) (*NegotiateResponse, error) {
+	out := new(NegotiateResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Negotiate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) BuildsList(ctx context.Context, in *BuildsListRequest, opts ...grpc.CallOption) (*BuildsListResponse, error) {
+	out := new(BuildsListResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/BuildsList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) RemoteInspect(ctx context.Context, in *RemoteInspectRequest, opts ...grpc.CallOption) (*RemoteInspectResponse, error) {
+	out := new(RemoteInspectResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/RemoteInspect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Prune(ctx context.Context, in *PruneRequest, opts ...grpc.CallOption) (*PruneResponse, error) {
+	out := new(PruneResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Prune", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) VerifyRemote(ctx context.Context, in *VerifyRemoteRequest, opts ...grpc.CallOption) (*VerifyRemoteResponse, error) {
+	out := new(VerifyRemoteResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/VerifyRemote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) ImagePrune(ctx context.Context, in *ImagePruneRequest, opts ...grpc.CallOption) (*ImagePruneResponse, error) {
+	out := new(ImagePruneResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/ImagePrune", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*BackupResponse, error) {
+	out := new(BackupResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Backup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error) {
+	out := new(RestoreResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) RecordsExport(ctx context.Context, in *RecordsExportRequest, opts ...grpc.CallOption) (*RecordsExportResponse, error) {
+	out := new(RecordsExportResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/RecordsExport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) RecordsImport(ctx context.Context, in *RecordsImportRequest, opts ...grpc.CallOption) (*RecordsImportResponse, error) {
+	out := new(RecordsImportResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/RecordsImport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error) {
+	out := new(InspectResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Inspect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Promote(ctx context.Context, in *PromoteRequest, opts ...grpc.CallOption) (*PromoteResponse, error) {
+	out := new(PromoteResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Promote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Diff(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error) {
+	out := new(DiffResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Diff", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) LoginList(ctx context.Context, in *LoginListRequest, opts ...grpc.CallOption) (*LoginListResponse, error) {
+	out := new(LoginListResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/LoginList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Tree(ctx context.Context, in *TreeRequest, opts ...grpc.CallOption) (*TreeResponse, error) {
+	out := new(TreeResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Tree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) MetaSet(ctx context.Context, in *MetaSetRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	out := new(types.Empty)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/MetaSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) MetaGet(ctx context.Context, in *MetaGetRequest, opts ...grpc.CallOption) (*MetaGetResponse, error) {
+	out := new(MetaGetResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/MetaGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Df(ctx context.Context, in *DfRequest, opts ...grpc.CallOption) (*DfResponse, error) {
+	out := new(DfResponse)
+	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Df", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ControlServer is the server API for Control service.
+type ControlServer interface {
+	// Build requests a new image building
+	Build(context.Context, *BuildRequest) (*BuildResponse, error)
+	// Status pipes the image building process log back to client
+	Status(*StatusRequest, Control_StatusServer) error
+	// List lists all images in isula-builder
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	// Version requests version information of isula-builder
+	Version(context.Context, *types.Empty) (*VersionResponse, error)
+	// Push pushes image to remote repository
+	Push(*PushRequest, Control_PushServer) error
+	// Pull pulls image from remote repository
+	Pull(*PullRequest, Control_PullServer) error
+	// Remove sends an image remove request to isula-builder
+	Remove(*RemoveRequest, Control_RemoveServer) error
+	// HealthCheck requests a health checking in isula-builder
+	HealthCheck(context.Context, *types.Empty) (*HealthCheckResponse, error)
+	// Login requests to access image registry with username and password
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	// Logout requests to logout registry and delete any credentials
+	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+	// Load requests an image tar load
+	Load(*LoadRequest, Control_LoadServer) error
+	// Import requests import a new image
+	Import(*ImportRequest, Control_ImportServer) error
+	// Convert requests converting an image between archive formats
+	Convert(*ConvertRequest, Control_ConvertServer) error
+	// Tag requests to tag an image
+	Tag(context.Context, *TagRequest) (*types.Empty, error)
+	// Save saves the image to tarball
+	Save(*SaveRequest, Control_SaveServer) error
+	// Info requests isula-build system information
+	Info(context.Context, *InfoRequest) (*InfoResponse, error)
+	// ManifestCreate requests to create manifest list
+	ManifestCreate(context.Context, *ManifestCreateRequest) (*ManifestCreateResponse, error)
+	// ManifestAnnotate requests to annotate manifest list
+	ManifestAnnotate(context.Context, *ManifestAnnotateRequest) (*types.Empty, error)
+	// ManifestInspect requests to inspect manifest list
+	ManifestInspect(context.Context, *ManifestInspectRequest) (*ManifestInspectResponse, error)
+	// ManifestPush requests to push manifest list
+	ManifestPush(*ManifestPushRequest, Control_ManifestPushServer) error
+	// CacheList lists the entries in the daemon url download cache
+	CacheList(context.Context, *CacheListRequest) (*CacheListResponse, error)
+	// CacheRemove removes entries from the daemon url download cache
+	CacheRemove(context.Context, *CacheRemoveRequest) (*CacheRemoveResponse, error)
+	// BuilderCreate creates a named builder instance carrying build defaults
+	BuilderCreate(context.Context, *BuilderCreateRequest) (*BuilderCreateResponse, error)
+	// BuilderList lists the named builder instances
+	BuilderList(context.Context, *BuilderListRequest) (*BuilderListResponse, error)
+	// BuilderRemove removes a named builder instance
+	BuilderRemove(context.Context, *BuilderRemoveRequest) (*BuilderRemoveResponse, error)
+	// Negotiate exchanges the API version and capabilities between client and daemon
+	Negotiate(context.Context, *NegotiateRequest) (*NegotiateResponse, error)
+	// BuildsList lists the builds currently running in the daemon
+	BuildsList(context.Context, *BuildsListRequest) (*BuildsListResponse, error)
+	// RemoteInspect fetches only the manifest and config of a remote image
+	RemoteInspect(context.Context, *RemoteInspectRequest) (*RemoteInspectResponse, error)
+	// Prune clears accumulated data left behind by interrupted sessions
+	Prune(context.Context, *PruneRequest) (*PruneResponse, error)
+	// VerifyRemote compares a local image with its copy in a registry
+	VerifyRemote(context.Context, *VerifyRemoteRequest) (*VerifyRemoteResponse, error)
+	// ImagePrune removes dangling images, unreferenced layers and leftover build containers
+	ImagePrune(context.Context, *ImagePruneRequest) (*ImagePruneResponse, error)
+	// Backup snapshots the image store into a portable archive
+	Backup(context.Context, *BackupRequest) (*BackupResponse, error)
+	// Restore loads the images of a backup archive into the store
+	Restore(context.Context, *RestoreRequest) (*RestoreResponse, error)
+	// RecordsExport bundles the persisted build records into a signed archive
+	RecordsExport(context.Context, *RecordsExportRequest) (*RecordsExportResponse, error)
+	// RecordsImport verifies a signed record bundle and stores its records
+	RecordsImport(context.Context, *RecordsImportRequest) (*RecordsImportResponse, error)
+	// Inspect returns the config, manifest and layer digests of a local image
+	Inspect(context.Context, *InspectRequest) (*InspectResponse, error)
+	// Promote retags and pushes an image from one logical environment to another
+	Promote(context.Context, *PromoteRequest) (*PromoteResponse, error)
+	// Diff lists the file changes between two local images
+	Diff(context.Context, *DiffRequest) (*DiffResponse, error)
+	// LoginList lists the registries with stored credentials
+	LoginList(context.Context, *LoginListRequest) (*LoginListResponse, error)
+	// Tree shows the layer sharing between local images
+	Tree(context.Context, *TreeRequest) (*TreeResponse, error)
+	// MetaSet attaches user metadata key/values to a local image
+	MetaSet(context.Context, *MetaSetRequest) (*types.Empty, error)
+	// MetaGet returns the user metadata of a local image
+	MetaGet(context.Context, *MetaGetRequest) (*MetaGetResponse, error)
+	// Df reports the image storage usage grouped by team or by owner
+	Df(context.Context, *DfRequest) (*DfResponse, error)
 }
 
-func init() { proto.RegisterFile("api/services/control.proto", fileDescriptor_d71ef680555cb937) }
+// UnimplementedControlServer can be embedded to have forward compatible implementations.
+type UnimplementedControlServer struct {
+}
 
-var fileDescriptor_d71ef680555cb937 = []byte{
-	// 2001 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x58, 0xdd, 0x73, 0x1c, 0x47,
-	0x11, 0x67, 0xef, 0x4e, 0x5f, 0x7d, 0xa7, 0xb3, 0x6a, 0x63, 0x9c, 0xab, 0xb3, 0x92, 0xa8, 0x36,
-	0x26, 0x16, 0xa6, 0x38, 0xc9, 0x82, 0x07, 0x42, 0x01, 0x85, 0x3e, 0x62, 0x73, 0x60, 0xcb, 0xb0,
-	0x52, 0x42, 0x51, 0xa9, 0xc2, 0x35, 0xba, 0x1b, 0x9d, 0x16, 0xef, 0xee, 0x2c, 0x33, 0xb3, 0x8a,
-	0x8f, 0xfc, 0x15, 0x54, 0xf1, 0xc4, 0xc7, 0xdf, 0xc0, 0x63, 0xfe, 0x02, 0x9e, 0xf8, 0xa7, 0x52,
-	0xdd, 0x33, 0xb3, 0x1f, 0xb7, 0x27, 0xc9, 0x79, 0xdb, 0xfe, 0x98, 0x9e, 0x9e, 0xee, 0xdf, 0x74,
-	0x4f, 0x2f, 0x0c, 0x59, 0x16, 0xed, 0x29, 0x2e, 0xaf, 0xa3, 0x09, 0x57, 0x7b, 0x13, 0x91, 0x6a,
-	0x29, 0xe2, 0x51, 0x26, 0x85, 0x16, 0x7e, 0x3f, 0x52, 0x79, 0xcc, 0x46, 0x17, 0x79, 0x14, 0x4f,
-	0x47, 0xd7, 0x4f, 0x87, 0x0f, 0x67, 0x42, 0xcc, 0x62, 0xbe, 0x47, 0xd2, 0x8b, 0xfc, 0x72, 0x8f,
-	0x27, 0x99, 0x9e, 0x1b, 0xe5, 0xe1, 0x47, 0x8b, 0x42, 0x1d, 0x25, 0x5c, 0x69, 0x96, 0x64, 0x46,
-	0x21, 0xf8, 0x5f, 0x1b, 0x7a, 0x47, 0x68, 0x2a, 0xe4, 0x7f, 0xcd, 0xb9, 0xd2, 0xfe, 0x00, 0xd6,
-	0xc8, 0xf4, 0xf8, 0x64, 0xe0, 0xed, 0x78, 0xbb, 0x1b, 0xa1, 0x23, 0xfd, 0x6d, 0xd8, 0xa0, 0xcf,
-	0xf3, 0x79, 0xc6, 0x07, 0x2d, 0x92, 0x95, 0x0c, 0xff, 0x43, 0x00, 0xf4, 0x93, 0xbf, 0xd5, 0x27,
-	0x91, 0x1c, 0xb4, 0x49, 0x5c, 0xe1, 0xf8, 0x3b, 0xd0, 0xbd, 0x8c, 0x62, 0x7e, 0x8c, 0x9c, 0x54,
-	0x0f, 0x3a, 0xa4, 0x50, 0x65, 0xf9, 0x0f, 0x60, 0x55, 0xe4, 0x3a, 0xcb, 0xf5, 0x60, 0x85, 0x84,
-	0x96, 0x2a, 0xf6, 0x3d, 0x94, 0x33, 0x35, 0x58, 0xdd, 0x69, 0x17, 0xfb, 0x22, 0xc3, 0xbf, 0x0f,
-	0x2b, 0x99, 0x14, 0x6f, 0xe7, 0x83, 0xb5, 0x1d, 0x6f, 0x77, 0x3d, 0x34, 0x04, 0x9e, 0x22, 0x8a,
-	0xa6, 0x68, 0x7d, 0xb0, 0x6e, 0x4e, 0x61, 0x49, 0xff, 0x97, 0xd0, 0xa5, 0xc5, 0x67, 0x9a, 0xe9,
-	0x68, 0x32, 0xd8, 0xd8, 0xf1, 0x76, 0xbb, 0x07, 0x0f, 0x47, 0xf5, 0xa0, 0x8e, 0x8e, 0x4a, 0x95,
-	0xb0, 0xaa, 0xef, 0x3f, 0x82, 0x4d, 0x36, 0x9d, 0x46, 0x3a, 0x12, 0x29, 0x8b, 0xcf, 0xd9, 0x6c,
-	0x00, 0x64, 0xbe, 0xce, 0xa4, 0x60, 0xb0, 0xec, 0x70, 0x3a, 0x7d, 0x11, 0x29, 0x3d, 0xe8, 0x92,
-	0xcf, 0x15, 0x8e, 0x3f, 0x84, 0x75, 0x9e, 0xea, 0x48, 0xcf, 0xc7, 0x27, 0x83, 0x1e, 0x19, 0x28,
-	0x68, 0x3c, 0x2e, 0x4f, 0x27, 0x72, 0x9e, 0x69, 0x3e, 0x1d, 0x6c, 0xd2, 0xa1, 0x4a, 0x06, 0x06,
-	0xe9, 0x52, 0xc8, 0x84, 0xe9, 0x41, 0xdf, 0x04, 0xc9, 0x50, 0x01, 0x83, 0xcd, 0x71, 0x92, 0x09,
-	0xa9, 0x5d, 0x1e, 0x87, 0xb0, 0x1e, 0x11, 0xa3, 0x48, 0x64, 0x41, 0xa3, 0x11, 0x25, 0x72, 0x39,
-	0x71, 0x69, 0xb4, 0x14, 0x6e, 0x2d, 0xf9, 0x25, 0x97, 0x3c, 0x9d, 0x70, 0x9b, 0xc2, 0x92, 0x11,
-	0x04, 0xd0, 0x77, 0x5b, 0xa8, 0x4c, 0xa4, 0x8a, 0xfb, 0x5b, 0xd0, 0x8e, 0xc5, 0xcc, 0x9a, 0xc7,
-	0xcf, 0xe0, 0x39, 0x74, 0x2b, 0xa1, 0xf3, 0x7f, 0xe6, 0x20, 0x13, 0x25, 0x9c, 0xd4, 0xba, 0x07,
-	0xc3, 0x91, 0x81, 0xe4, 0xc8, 0x41, 0x72, 0x74, 0xee, 0x20, 0x19, 0x96, 0xca, 0xc1, 0x0f, 0x61,
-	0xd3, 0xc2, 0xd2, 0xee, 0x85, 0x19, 0x4d, 0xd8, 0x8c, 0x97, 0xb8, 0xb4, 0x24, 0xaa, 0xe2, 0x76,
-	0xb9, 0xba, 0x13, 0xc2, 0xc1, 0x13, 0xe8, 0x3b, 0xd5, 0xd2, 0xec, 0xc4, 0x42, 0xd2, 0xea, 0x5a,
-	0x32, 0xf8, 0x11, 0x74, 0x31, 0x57, 0xce, 0xe8, 0x36, 0x6c, 0xd0, 0x86, 0xa7, 0xcc, 0x1e, 0x65,
-	0x23, 0x2c, 0x19, 0xc1, 0x4f, 0x01, 0xce, 0xd9, 0xcc, 0xe9, 0xde, 0x87, 0x15, 0x12, 0x59, 0x3d,
-	0x43, 0x60, 0xb4, 0x34, 0x9b, 0xd9, 0x90, 0xe3, 0x67, 0xf0, 0x7f, 0x0f, 0x7a, 0x66, 0x0f, 0xeb,
-	0xcd, 0xaf, 0x60, 0x95, 0x74, 0xd5, 0xc0, 0xdb, 0x69, 0xef, 0x76, 0x0f, 0x3e, 0x59, 0xc4, 0x65,
-	0x55, 0x7b, 0x34, 0xa6, 0x00, 0xa4, 0x97, 0x22, 0xb4, 0xab, 0x86, 0x5f, 0xc3, 0x46, 0xc1, 0x44,
-	0x10, 0x4a, 0x9e, 0x09, 0x15, 0x69, 0x21, 0xe7, 0xd6, 0x95, 0x0a, 0xa7, 0xe9, 0x8f, 0xdf, 0x87,
-	0x56, 0x34, 0xb5, 0x89, 0x6f, 0x45, 0x53, 0x0a, 0x8e, 0xe4, 0x0c, 0x81, 0xd8, 0xb1, 0xc1, 0x31,
-	0xa4, 0xef, 0x43, 0x47, 0x45, 0x7f, 0xe3, 0xf6, 0xa6, 0xd2, 0x77, 0xf0, 0x1f, 0x0f, 0xee, 0x7d,
-	0xc1, 0xa5, 0x8a, 0x44, 0x5a, 0x0d, 0xef, 0xb5, 0x61, 0xb9, 0xf0, 0x5a, 0x12, 0xe3, 0x39, 0x13,
-	0x56, 0xdd, 0x55, 0x93, 0x82, 0x41, 0xd2, 0x48, 0x1f, 0x8b, 0x24, 0x89, 0xb4, 0x43, 0x62, 0xc1,
-	0x28, 0x2b, 0x11, 0xc2, 0xaa, 0x53, 0xad, 0x44, 0x51, 0xc2, 0xa9, 0x8e, 0xa8, 0x43, 0x39, 0xb9,
-	0x2a, 0xea, 0x08, 0x51, 0xc1, 0x1f, 0x60, 0x33, 0xe4, 0x89, 0xb8, 0xe6, 0x15, 0x9c, 0x94, 0x90,
-	0x6a, 0x57, 0x20, 0x85, 0xa1, 0x61, 0x71, 0x4c, 0x6e, 0xad, 0x87, 0xf8, 0x69, 0xca, 0x4c, 0x9e,
-	0x9a, 0x6b, 0x41, 0x65, 0x26, 0x4f, 0x31, 0xed, 0x7d, 0x67, 0xd2, 0x1e, 0x38, 0x80, 0x5e, 0xcc,
-	0xe6, 0x5c, 0xbe, 0xe4, 0x4a, 0x95, 0x08, 0xa8, 0xf1, 0x82, 0x7f, 0x7b, 0xf0, 0xde, 0x6f, 0x38,
-	0x8b, 0xf5, 0xd5, 0xf1, 0x15, 0x9f, 0xbc, 0x29, 0xd6, 0x8e, 0x61, 0x55, 0x11, 0x3a, 0x69, 0x55,
-	0xff, 0xe0, 0xe9, 0x62, 0xf6, 0x97, 0x2c, 0x1a, 0x9d, 0x61, 0x97, 0x48, 0x67, 0x16, 0xd6, 0xd6,
-	0x40, 0xf0, 0x73, 0xd8, 0xac, 0x09, 0xfc, 0x2e, 0xac, 0x7d, 0x7e, 0xfa, 0xbb, 0xd3, 0x57, 0x7f,
-	0x3c, 0xdd, 0xfa, 0x1e, 0x12, 0x67, 0x9f, 0x85, 0x5f, 0x8c, 0x4f, 0x9f, 0x6f, 0x79, 0xfe, 0x3d,
-	0xe8, 0x9e, 0xbe, 0x3a, 0x7f, 0xed, 0x18, 0xad, 0xe0, 0xcf, 0xd0, 0x7b, 0x21, 0x66, 0x51, 0xea,
-	0xc2, 0x84, 0xd5, 0x82, 0xcb, 0x6b, 0x2e, 0xed, 0x61, 0x2c, 0x85, 0x15, 0x26, 0x57, 0x5c, 0xa6,
-	0x78, 0x21, 0x4c, 0x02, 0x0b, 0x1a, 0x65, 0x19, 0x53, 0xea, 0x2b, 0x21, 0x1d, 0x9e, 0x0a, 0x1a,
-	0xef, 0xab, 0xb5, 0x7f, 0xe7, 0x1d, 0xfc, 0x94, 0x54, 0x45, 0xae, 0xef, 0xf2, 0xa5, 0x91, 0xb0,
-	0x60, 0x17, 0xfa, 0x6e, 0xa9, 0xdd, 0xe6, 0x01, 0xac, 0x4a, 0xae, 0xf2, 0xd8, 0xed, 0x62, 0xa9,
-	0xe0, 0x2f, 0xd0, 0x7d, 0x21, 0x58, 0xd1, 0x00, 0x7d, 0xe8, 0x64, 0x4c, 0x5f, 0x59, 0x25, 0xfa,
-	0xc6, 0xa5, 0xb1, 0x60, 0x58, 0x50, 0x6c, 0xc1, 0x34, 0x94, 0xbf, 0x07, 0x6d, 0xc5, 0x33, 0x3a,
-	0x61, 0xf7, 0xe0, 0x83, 0xc5, 0x74, 0x9d, 0xf1, 0x8c, 0x49, 0xa6, 0x85, 0x24, 0xf3, 0xa8, 0x19,
-	0xfc, 0xdd, 0xc3, 0xc4, 0x54, 0xd8, 0xe4, 0x79, 0x96, 0xb9, 0x1a, 0xca, 0xb2, 0x0c, 0x39, 0xd3,
-	0x48, 0xba, 0x7b, 0x39, 0x8d, 0x24, 0xba, 0x74, 0xc1, 0x94, 0x2b, 0xc9, 0xf4, 0x4d, 0xb5, 0x37,
-	0xba, 0xb0, 0xe8, 0xc7, 0x4f, 0xbc, 0x15, 0xea, 0x4d, 0x94, 0x11, 0x3c, 0x08, 0xfa, 0xeb, 0x61,
-	0xc9, 0xc0, 0x20, 0xf3, 0x94, 0x5d, 0xc4, 0x7c, 0x3a, 0x58, 0x25, 0x99, 0x23, 0x83, 0x1d, 0xcc,
-	0x37, 0x9b, 0xde, 0x52, 0xd5, 0xbf, 0x84, 0xee, 0xef, 0x73, 0x75, 0x55, 0x49, 0x42, 0x96, 0xab,
-	0xab, 0xa2, 0xbc, 0x5a, 0xaa, 0x5e, 0x22, 0x5b, 0x0b, 0x25, 0xb2, 0xd2, 0xb9, 0xda, 0xb5, 0xce,
-	0xf5, 0x04, 0x7a, 0xc6, 0xb8, 0xdd, 0x7e, 0x08, 0xeb, 0xd2, 0x7e, 0xbb, 0xc6, 0xe5, 0xe8, 0xe0,
-	0x18, 0x1d, 0x89, 0xe3, 0x9a, 0x23, 0x71, 0x5c, 0x75, 0x04, 0xa9, 0xdb, 0x1d, 0x31, 0x1b, 0xa2,
-	0x91, 0x77, 0xd8, 0xf0, 0x9f, 0x1e, 0x74, 0xcf, 0x58, 0x59, 0x32, 0x10, 0x7f, 0xec, 0xba, 0x6c,
-	0x42, 0x96, 0x42, 0xbe, 0x2d, 0xdc, 0x2d, 0xaa, 0x24, 0x96, 0x2a, 0xc0, 0xd4, 0xae, 0x83, 0xc9,
-	0x06, 0xa2, 0x53, 0x0d, 0x84, 0x03, 0xd3, 0xca, 0x1d, 0x60, 0x22, 0x77, 0x08, 0x4c, 0x5f, 0x57,
-	0xb0, 0x84, 0xdc, 0x02, 0x27, 0x5e, 0x13, 0x27, 0xad, 0x12, 0x27, 0x74, 0x0f, 0xe8, 0xd6, 0xb6,
-	0xdd, 0x3d, 0xa0, 0x3b, 0xeb, 0x43, 0x67, 0xca, 0x95, 0xf3, 0x8a, 0xbe, 0xab, 0xa8, 0x59, 0x69,
-	0xa0, 0xc6, 0x04, 0xe6, 0x46, 0xd4, 0xcc, 0x61, 0xed, 0x25, 0x4f, 0x4e, 0x98, 0x66, 0x18, 0xe2,
-	0x84, 0x27, 0xe7, 0x42, 0xb3, 0x98, 0x34, 0xda, 0x61, 0x41, 0xe3, 0x16, 0x09, 0x4f, 0x9e, 0x49,
-	0x6e, 0x52, 0xd5, 0x0e, 0x1d, 0x49, 0x80, 0xfe, 0x8a, 0x65, 0x66, 0x59, 0x9b, 0x64, 0x25, 0x03,
-	0x6d, 0x22, 0x41, 0x0b, 0x3b, 0xc6, 0xa6, 0xa3, 0x83, 0x6f, 0x3c, 0xda, 0x1b, 0x6b, 0x1f, 0x1e,
-	0x37, 0xe1, 0xc9, 0xd9, 0xdc, 0x54, 0xd5, 0x4e, 0x68, 0x29, 0xdc, 0xf7, 0x8a, 0xb3, 0x0c, 0x05,
-	0x2d, 0x12, 0x38, 0x12, 0xf7, 0xc5, 0xcf, 0xc3, 0x38, 0x16, 0x13, 0xda, 0xb7, 0x13, 0x96, 0x0c,
-	0x27, 0x1d, 0xa7, 0x9f, 0x2b, 0xb3, 0xb1, 0x95, 0x12, 0x03, 0xbd, 0x22, 0x62, 0x1a, 0x9b, 0xe6,
-	0xd8, 0x09, 0x0b, 0x1a, 0x7b, 0x03, 0x7e, 0x87, 0x3c, 0xe6, 0x4c, 0xd9, 0x7b, 0xd8, 0x09, 0x6b,
-	0xbc, 0xe0, 0x35, 0x74, 0xcf, 0xb4, 0x90, 0x6c, 0xc6, 0x29, 0x70, 0x8f, 0x60, 0x53, 0x59, 0x52,
-	0x46, 0x65, 0xd9, 0xab, 0x33, 0xfd, 0x27, 0xb0, 0x65, 0x19, 0x47, 0x6c, 0xf2, 0x26, 0x4a, 0x67,
-	0xcf, 0x94, 0x4d, 0x78, 0x83, 0x1f, 0xfc, 0xc3, 0x83, 0x5e, 0xc8, 0x67, 0x91, 0xd2, 0x72, 0x4e,
-	0x5b, 0x3c, 0x81, 0x2d, 0x69, 0xe8, 0x88, 0xab, 0x33, 0xce, 0xb0, 0x71, 0x9a, 0x76, 0xd8, 0xe0,
-	0xfb, 0x23, 0xf0, 0x4b, 0xde, 0x38, 0x55, 0x7c, 0x92, 0x4b, 0x6e, 0x21, 0xbf, 0x44, 0xe2, 0xef,
-	0xc2, 0xbd, 0x92, 0x7b, 0x14, 0x8b, 0xc9, 0x9b, 0x41, 0x9b, 0x94, 0x17, 0xd9, 0xc1, 0x63, 0xe8,
-	0xd2, 0x4b, 0xa6, 0x6c, 0xcd, 0xd7, 0x5c, 0x5e, 0x08, 0x0b, 0xe6, 0xf5, 0xd0, 0x91, 0xc1, 0x7f,
-	0xdb, 0xd0, 0x33, 0x9a, 0x16, 0x78, 0x4f, 0x09, 0x3f, 0xc8, 0xb2, 0x2f, 0xcc, 0xf7, 0x17, 0xaf,
-	0x8e, 0x45, 0x61, 0xe8, 0xf4, 0x70, 0x06, 0xb0, 0x71, 0xa1, 0x65, 0xad, 0xe5, 0x33, 0x40, 0x25,
-	0x0f, 0x61, 0x55, 0xdf, 0xff, 0x35, 0xf4, 0xac, 0xfb, 0x73, 0x5a, 0x6f, 0xca, 0xff, 0xf6, 0xe2,
-	0xfa, 0x6a, 0x94, 0xc3, 0xda, 0x0a, 0x44, 0xc9, 0x14, 0xb9, 0x42, 0xb8, 0xeb, 0x56, 0xd0, 0x78,
-	0x74, 0x99, 0xa7, 0x24, 0x32, 0xef, 0x17, 0x47, 0xe2, 0x83, 0xee, 0xd5, 0xf1, 0x38, 0xcc, 0x53,
-	0x1c, 0xe2, 0x08, 0x3d, 0x1b, 0x61, 0x85, 0x83, 0x72, 0xda, 0x9c, 0xcb, 0xd3, 0x3c, 0xa1, 0x79,
-	0xa8, 0x1d, 0x56, 0x38, 0x28, 0x9f, 0x89, 0x50, 0xe4, 0x3a, 0x4a, 0xb9, 0xa2, 0xb9, 0xa8, 0x1d,
-	0x56, 0x38, 0x36, 0x92, 0x78, 0x69, 0xec, 0x58, 0xb4, 0x2c, 0x92, 0x28, 0x0e, 0x9d, 0x1e, 0x42,
-	0x9a, 0xbf, 0xcd, 0xb8, 0x8c, 0x12, 0x9e, 0xe2, 0x2d, 0x05, 0x4a, 0x56, 0x8d, 0x17, 0xfc, 0x09,
-	0xbe, 0xff, 0x92, 0xa5, 0xd1, 0x25, 0x57, 0xfa, 0x98, 0x9e, 0x8f, 0x2e, 0xc9, 0x01, 0xf4, 0x12,
-	0x2b, 0xa0, 0x39, 0xc9, 0xbe, 0x95, 0xaa, 0x3c, 0xbc, 0x6d, 0x8e, 0x76, 0xb5, 0xb5, 0x64, 0x04,
-	0x07, 0xf0, 0x60, 0xd1, 0xf4, 0x9d, 0xe3, 0xc2, 0x37, 0x1e, 0xbc, 0xef, 0x16, 0x1d, 0xa6, 0xa9,
-	0xd0, 0xdf, 0xd1, 0x23, 0xac, 0x65, 0x96, 0x76, 0xcf, 0x1e, 0x47, 0x63, 0x09, 0xa5, 0xfb, 0x63,
-	0xcb, 0x3d, 0xdd, 0x99, 0x3e, 0xb4, 0x84, 0xb2, 0x59, 0x6e, 0x09, 0x85, 0x59, 0x10, 0xea, 0x19,
-	0x67, 0x3a, 0x97, 0x5c, 0x0d, 0x56, 0xcc, 0x6c, 0x58, 0x72, 0x08, 0xfa, 0x4c, 0x46, 0x2c, 0xd5,
-	0x36, 0xc5, 0x8e, 0x0c, 0x7e, 0x51, 0x9e, 0x76, 0x9c, 0xaa, 0x8c, 0x4f, 0xf4, 0x77, 0xf0, 0x3b,
-	0xf8, 0x71, 0x79, 0xec, 0x62, 0xb5, 0x0d, 0x16, 0x56, 0x7e, 0xa6, 0x19, 0x2d, 0xeb, 0x85, 0xf4,
-	0x1d, 0xbc, 0x84, 0xf7, 0x9c, 0x7a, 0xb5, 0xf7, 0xbf, 0x4b, 0x84, 0x5c, 0x23, 0x69, 0x95, 0x8d,
-	0x24, 0x18, 0xc1, 0xfd, 0xba, 0xb9, 0xdb, 0x1f, 0x65, 0x07, 0xff, 0xea, 0xc2, 0xda, 0xb1, 0xf9,
-	0xef, 0xe1, 0x9f, 0xc0, 0x0a, 0xcd, 0x82, 0xfe, 0xf6, 0xd2, 0x31, 0xdd, 0xba, 0x36, 0xfc, 0xe0,
-	0x06, 0x69, 0xf9, 0xba, 0xb6, 0x6f, 0xe1, 0x66, 0x6f, 0xad, 0x8e, 0x8f, 0xc3, 0x0f, 0x6f, 0x12,
-	0x1b, 0x43, 0xfb, 0x9e, 0x7f, 0x08, 0x1d, 0x3a, 0xe8, 0xc3, 0xe5, 0xe3, 0x99, 0x31, 0xb3, 0x7d,
-	0xdb, 0xec, 0xe6, 0x1f, 0xc1, 0x9a, 0x9b, 0x75, 0x1e, 0x34, 0x26, 0xe2, 0xcf, 0x92, 0x4c, 0xcf,
-	0x87, 0x1f, 0x2d, 0x1a, 0x58, 0x1c, 0xae, 0x8e, 0xa1, 0x83, 0xb1, 0x6c, 0xba, 0x51, 0x49, 0x58,
-	0xd3, 0x8d, 0x6a, 0xf8, 0xf7, 0x3d, 0x63, 0x24, 0x8e, 0x97, 0x19, 0x29, 0x1e, 0x5a, 0xcb, 0x8c,
-	0x94, 0x0f, 0xa8, 0x7d, 0x0f, 0x63, 0x6b, 0xe6, 0xa0, 0x66, 0x6c, 0x6b, 0x23, 0x57, 0x33, 0xb6,
-	0xf5, 0xf1, 0x69, 0xdf, 0xf3, 0x7f, 0x0b, 0xdd, 0xca, 0x98, 0x73, 0x63, 0x70, 0x3e, 0x7e, 0x87,
-	0xd9, 0x08, 0x81, 0x43, 0x93, 0x46, 0x13, 0x38, 0xd5, 0x01, 0xa7, 0x09, 0x9c, 0xfa, 0x78, 0xf2,
-	0x1c, 0x56, 0xcd, 0x24, 0xe1, 0x2f, 0x53, 0x2c, 0x87, 0x93, 0xe6, 0xe1, 0x16, 0x06, 0x90, 0x63,
-	0xe8, 0xd0, 0x93, 0xbf, 0x09, 0x9b, 0x72, 0xfc, 0x58, 0x02, 0x9b, 0xca, 0xdb, 0xdc, 0x84, 0xda,
-	0xfc, 0x85, 0x69, 0x7a, 0x53, 0xfb, 0x01, 0xd4, 0xf4, 0xa6, 0xfe, 0xf3, 0x66, 0xdf, 0xf3, 0x3f,
-	0x85, 0xf6, 0x39, 0x9b, 0xf9, 0xc3, 0x45, 0xc5, 0xf2, 0x4f, 0xc6, 0xf0, 0x86, 0xf0, 0xe3, 0x51,
-	0xe8, 0xc5, 0xd9, 0x6c, 0x9a, 0xe5, 0x63, 0xb9, 0x79, 0x94, 0xea, 0x83, 0xd1, 0x5c, 0x23, 0xea,
-	0x86, 0x0d, 0x23, 0x95, 0x97, 0x40, 0xd3, 0x48, 0xad, 0xf9, 0xbf, 0x86, 0x7e, 0xbd, 0x01, 0xf8,
-	0x3f, 0x68, 0xf4, 0xac, 0x65, 0xbd, 0x67, 0xf8, 0xc9, 0x5d, 0x6a, 0x76, 0x83, 0x33, 0xd8, 0x5a,
-	0x6c, 0x16, 0xfe, 0xe3, 0x9b, 0xd6, 0x2e, 0xb4, 0x93, 0x1b, 0xa3, 0x77, 0x01, 0xf7, 0x16, 0x4a,
-	0xb1, 0x7f, 0xa3, 0x3f, 0xf5, 0x4a, 0x3f, 0x7c, 0x7c, 0xa7, 0x9e, 0x75, 0xfc, 0x4b, 0xe8, 0x55,
-	0x0b, 0xae, 0xff, 0xf1, 0x4d, 0x0b, 0xab, 0xc5, 0xe2, 0xd1, 0xed, 0x4a, 0x2e, 0x73, 0x17, 0xab,
-	0x74, 0xa0, 0x9f, 0x7c, 0x1b, 0x00, 0x00, 0xff, 0xff, 0x24, 0x62, 0xe9, 0xd4, 0xa8, 0x16, 0x00,
-	0x00,
+func (*UnimplementedControlServer) Build(ctx context.Context, req *BuildRequest) (*BuildResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Build not implemented")
+}
+func (*UnimplementedControlServer) Status(req *StatusRequest, srv Control_StatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (*UnimplementedControlServer) List(ctx context.Context, req *ListRequest) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (*UnimplementedControlServer) Version(ctx context.Context, req *types.Empty) (*VersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
+}
+func (*UnimplementedControlServer) Push(req *PushRequest, srv Control_PushServer) error {
+	return status.Errorf(codes.Unimplemented, "method Push not implemented")
+}
+func (*UnimplementedControlServer) Pull(req *PullRequest, srv Control_PullServer) error {
+	return status.Errorf(codes.Unimplemented, "method Pull not implemented")
+}
+func (*UnimplementedControlServer) Remove(req *RemoveRequest, srv Control_RemoveServer) error {
+	return status.Errorf(codes.Unimplemented, "method Remove not implemented")
+}
+func (*UnimplementedControlServer) HealthCheck(ctx context.Context, req *types.Empty) (*HealthCheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
+}
+func (*UnimplementedControlServer) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (*UnimplementedControlServer) Logout(ctx context.Context, req *LogoutRequest) (*LogoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (*UnimplementedControlServer) Load(req *LoadRequest, srv Control_LoadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Load not implemented")
+}
+func (*UnimplementedControlServer) Convert(req *ConvertRequest, srv Control_ConvertServer) error {
+	return status.Errorf(codes.Unimplemented, "method Convert not implemented")
+}
+func (*UnimplementedControlServer) Import(req *ImportRequest, srv Control_ImportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Import not implemented")
+}
+func (*UnimplementedControlServer) Tag(ctx context.Context, req *TagRequest) (*types.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Tag not implemented")
+}
+func (*UnimplementedControlServer) Save(req *SaveRequest, srv Control_SaveServer) error {
+	return status.Errorf(codes.Unimplemented, "method Save not implemented")
+}
+func (*UnimplementedControlServer) Info(ctx context.Context, req *InfoRequest) (*InfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Info not implemented")
+}
+func (*UnimplementedControlServer) ManifestCreate(ctx context.Context, req *ManifestCreateRequest) (*ManifestCreateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ManifestCreate not implemented")
+}
+func (*UnimplementedControlServer) ManifestAnnotate(ctx context.Context, req *ManifestAnnotateRequest) (*types.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ManifestAnnotate not implemented")
+}
+func (*UnimplementedControlServer) ManifestInspect(ctx context.Context, req *ManifestInspectRequest) (*ManifestInspectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ManifestInspect not implemented")
+}
+func (*UnimplementedControlServer) ManifestPush(req *ManifestPushRequest, srv Control_ManifestPushServer) error {
+	return status.Errorf(codes.Unimplemented, "method ManifestPush not implemented")
+}
+func (*UnimplementedControlServer) CacheList(ctx context.Context, req *CacheListRequest) (*CacheListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CacheList not implemented")
+}
+func (*UnimplementedControlServer) CacheRemove(ctx context.Context, req *CacheRemoveRequest) (*CacheRemoveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CacheRemove not implemented")
+}
+func (*UnimplementedControlServer) BuilderCreate(ctx context.Context, req *BuilderCreateRequest) (*BuilderCreateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuilderCreate not implemented")
+}
+func (*UnimplementedControlServer) BuilderList(ctx context.Context, req *BuilderListRequest) (*BuilderListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuilderList not implemented")
+}
+func (*UnimplementedControlServer) Negotiate(ctx context.Context, req *NegotiateRequest) (*NegotiateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Negotiate not implemented")
+}
+func (*UnimplementedControlServer) BuilderRemove(ctx context.Context, req *BuilderRemoveRequest) (*BuilderRemoveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuilderRemove not implemented")
+}
+func (*UnimplementedControlServer) BuildsList(ctx context.Context, req *BuildsListRequest) (*BuildsListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuildsList not implemented")
+}
+func (*UnimplementedControlServer) RemoteInspect(ctx context.Context, req *RemoteInspectRequest) (*RemoteInspectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoteInspect not implemented")
+}
+func (*UnimplementedControlServer) Prune(ctx context.Context, req *PruneRequest) (*PruneResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Prune not implemented")
+}
+func (*UnimplementedControlServer) VerifyRemote(ctx context.Context, req *VerifyRemoteRequest) (*VerifyRemoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyRemote not implemented")
+}
+func (*UnimplementedControlServer) ImagePrune(ctx context.Context, req *ImagePruneRequest) (*ImagePruneResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImagePrune not implemented")
+}
+func (*UnimplementedControlServer) Backup(ctx context.Context, req *BackupRequest) (*BackupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Backup not implemented")
+}
+func (*UnimplementedControlServer) Restore(ctx context.Context, req *RestoreRequest) (*RestoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (*UnimplementedControlServer) RecordsExport(ctx context.Context, req *RecordsExportRequest) (*RecordsExportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordsExport not implemented")
+}
+func (*UnimplementedControlServer) RecordsImport(ctx context.Context, req *RecordsImportRequest) (*RecordsImportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordsImport not implemented")
+}
+func (*UnimplementedControlServer) Inspect(ctx context.Context, req *InspectRequest) (*InspectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Inspect not implemented")
+}
+func (*UnimplementedControlServer) Promote(ctx context.Context, req *PromoteRequest) (*PromoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Promote not implemented")
+}
+func (*UnimplementedControlServer) Diff(ctx context.Context, req *DiffRequest) (*DiffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Diff not implemented")
+}
+func (*UnimplementedControlServer) LoginList(ctx context.Context, req *LoginListRequest) (*LoginListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoginList not implemented")
+}
+func (*UnimplementedControlServer) Tree(ctx context.Context, req *TreeRequest) (*TreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Tree not implemented")
+}
+func (*UnimplementedControlServer) MetaSet(ctx context.Context, req *MetaSetRequest) (*types.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MetaSet not implemented")
+}
+func (*UnimplementedControlServer) MetaGet(ctx context.Context, req *MetaGetRequest) (*MetaGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MetaGet not implemented")
+}
+func (*UnimplementedControlServer) Df(ctx context.Context, req *DfRequest) (*DfResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Df not implemented")
+}
+
+func RegisterControlServer(s *grpc.Server, srv ControlServer) {
+	s.RegisterService(&_Control_serviceDesc, srv)
+}
+
+func _Control_Build_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).Build(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/Build",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Build(ctx, req.(*BuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func _Control_Status_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).Status(m, &controlStatusServer{stream})
+}
 
-// ControlClient is the client API for Control service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ControlClient interface {
-	// Build requests a new image building
-	Build(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*BuildResponse, error)
-	// Status pipes the image building process log back to client
-	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (Control_StatusClient, error)
-	// List lists all images in isula-builder
-	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
-	// Version requests version information of isula-builder
-	Version(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*VersionResponse, error)
-	// Push pushes image to remote repository
-	Push(ctx context.Context, in *PushRequest, opts ...grpc.CallOption) (Control_PushClient, error)
-	// Pull pulls image from remote repository
-	Pull(ctx context.Context, in *PullRequest, opts ...grpc.CallOption) (Control_PullClient, error)
-	// Remove sends an image remove request to isula-builder
-	Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (Control_RemoveClient, error)
-	// HealthCheck requests a health checking in isula-builder
-	HealthCheck(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*HealthCheckResponse, error)
-	// Login requests to access image registry with username and password
-	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
-	// Logout requests to logout registry and delete any credentials
-	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
-	// Load requests an image tar load
-	Load(ctx context.Context, in *LoadRequest, opts ...grpc.CallOption) (Control_LoadClient, error)
-	// Import requests import a new image
-	Import(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (Control_ImportClient, error)
-	// Tag requests to tag an image
-	Tag(ctx context.Context, in *TagRequest, opts ...grpc.CallOption) (*types.Empty, error)
-	// Save saves the image to tarball
-	Save(ctx context.Context, in *SaveRequest, opts ...grpc.CallOption) (Control_SaveClient, error)
-	// Info requests isula-build system information
-	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error)
-	// ManifestCreate requests to create manifest list
-	ManifestCreate(ctx context.Context, in *ManifestCreateRequest, opts ...grpc.CallOption) (*ManifestCreateResponse, error)
-	// ManifestAnnotate requests to annotate manifest list
-	ManifestAnnotate(ctx context.Context, in *ManifestAnnotateRequest, opts ...grpc.CallOption) (*types.Empty, error)
-	// ManifestInspect requests to inspect manifest list
-	ManifestInspect(ctx context.Context, in *ManifestInspectRequest, opts ...grpc.CallOption) (*ManifestInspectResponse, error)
-	// ManifestPush requests to push manifest list
-	ManifestPush(ctx context.Context, in *ManifestPushRequest, opts ...grpc.CallOption) (Control_ManifestPushClient, error)
+type Control_StatusServer interface {
+	Send(*StatusResponse) error
+	grpc.ServerStream
 }
 
-type controlClient struct {
-	cc *grpc.ClientConn
+type controlStatusServer struct {
+	grpc.ServerStream
 }
 
-func NewControlClient(cc *grpc.ClientConn) ControlClient {
-	return &controlClient{cc}
+func (x *controlStatusServer) Send(m *StatusResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *controlClient) Build(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*BuildResponse, error) {
-	out := new(BuildResponse)
-	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Build", in, out, opts...)
-	if err != nil {
+func _Control_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ControlServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *controlClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (Control_StatusClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[0], "/isula.build.v1.Control/Status", opts...)
-	if err != nil {
+func _Control_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(types.Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &controlStatusClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(ControlServer).Version(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/Version",
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Version(ctx, req.(*types.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Control_StatusClient interface {
-	Recv() (*StatusResponse, error)
-	grpc.ClientStream
+func _Control_Push_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PushRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).Push(m, &controlPushServer{stream})
 }
 
-type controlStatusClient struct {
-	grpc.ClientStream
+type Control_PushServer interface {
+	Send(*PushResponse) error
+	grpc.ServerStream
 }
 
-func (x *controlStatusClient) Recv() (*StatusResponse, error) {
-	m := new(StatusResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+type controlPushServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlPushServer) Send(m *PushResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Control_Pull_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PullRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return m, nil
+	return srv.(ControlServer).Pull(m, &controlPullServer{stream})
 }
 
-func (c *controlClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
-	out := new(ListResponse)
-	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/List", in, out, opts...)
-	if err != nil {
-		return nil, err
+type Control_PullServer interface {
+	Send(*PullResponse) error
+	grpc.ServerStream
+}
+
+type controlPullServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlPullServer) Send(m *PullResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Control_Remove_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RemoveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return out, nil
+	return srv.(ControlServer).Remove(m, &controlRemoveServer{stream})
 }
 
-func (c *controlClient) Version(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*VersionResponse, error) {
-	out := new(VersionResponse)
-	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Version", in, out, opts...)
-	if err != nil {
+type Control_RemoveServer interface {
+	Send(*RemoveResponse) error
+	grpc.ServerStream
+}
+
+type controlRemoveServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlRemoveServer) Send(m *RemoveResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Control_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(types.Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ControlServer).HealthCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/HealthCheck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).HealthCheck(ctx, req.(*types.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *controlClient) Push(ctx context.Context, in *PushRequest, opts ...grpc.CallOption) (Control_PushClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[1], "/isula.build.v1.Control/Push", opts...)
-	if err != nil {
+func _Control_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &controlPushClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(ControlServer).Login(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return x, nil
+	if interceptor == nil {
+		return srv.(ControlServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/Logout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_Load_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LoadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).Load(m, &controlLoadServer{stream})
+}
+
+type Control_LoadServer interface {
+	Send(*LoadResponse) error
+	grpc.ServerStream
+}
+
+type controlLoadServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlLoadServer) Send(m *LoadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Control_Import_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ImportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).Import(m, &controlImportServer{stream})
 }
 
-type Control_PushClient interface {
-	Recv() (*PushResponse, error)
-	grpc.ClientStream
+type Control_ImportServer interface {
+	Send(*ImportResponse) error
+	grpc.ServerStream
 }
 
-type controlPushClient struct {
-	grpc.ClientStream
+type controlImportServer struct {
+	grpc.ServerStream
 }
 
-func (x *controlPushClient) Recv() (*PushResponse, error) {
-	m := new(PushResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+func (x *controlImportServer) Send(m *ImportResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *controlClient) Pull(ctx context.Context, in *PullRequest, opts ...grpc.CallOption) (Control_PullClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[2], "/isula.build.v1.Control/Pull", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &controlPullClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func _Control_Convert_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ConvertRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return x, nil
+	return srv.(ControlServer).Convert(m, &controlConvertServer{stream})
 }
 
-type Control_PullClient interface {
-	Recv() (*PullResponse, error)
-	grpc.ClientStream
+type Control_ConvertServer interface {
+	Send(*ConvertResponse) error
+	grpc.ServerStream
 }
 
-type controlPullClient struct {
-	grpc.ClientStream
+type controlConvertServer struct {
+	grpc.ServerStream
 }
 
-func (x *controlPullClient) Recv() (*PullResponse, error) {
-	m := new(PullResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+func (x *controlConvertServer) Send(m *ConvertResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *controlClient) Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (Control_RemoveClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[3], "/isula.build.v1.Control/Remove", opts...)
-	if err != nil {
+func _Control_Tag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &controlRemoveClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(ControlServer).Tag(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/Tag",
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Tag(ctx, req.(*TagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Control_RemoveClient interface {
-	Recv() (*RemoveResponse, error)
-	grpc.ClientStream
+func _Control_Save_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SaveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).Save(m, &controlSaveServer{stream})
 }
 
-type controlRemoveClient struct {
-	grpc.ClientStream
+type Control_SaveServer interface {
+	Send(*SaveResponse) error
+	grpc.ServerStream
 }
 
-func (x *controlRemoveClient) Recv() (*RemoveResponse, error) {
-	m := new(RemoveResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+type controlSaveServer struct {
+	grpc.ServerStream
 }
 
-func (c *controlClient) HealthCheck(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
-	out := new(HealthCheckResponse)
-	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/HealthCheck", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (x *controlSaveServer) Send(m *SaveResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *controlClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
-	out := new(LoginResponse)
-	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Login", in, out, opts...)
-	if err != nil {
+func _Control_Info_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InfoRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-func (c *controlClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error) {
-	out := new(LogoutResponse)
-	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Logout", in, out, opts...)
-	if err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(ControlServer).Info(ctx, in)
 	}
-	return out, nil
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/Info",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Info(ctx, req.(*InfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *controlClient) Load(ctx context.Context, in *LoadRequest, opts ...grpc.CallOption) (Control_LoadClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[4], "/isula.build.v1.Control/Load", opts...)
-	if err != nil {
+func _Control_ManifestCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ManifestCreateRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &controlLoadClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(ControlServer).ManifestCreate(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/ManifestCreate",
 	}
-	return x, nil
-}
-
-type Control_LoadClient interface {
-	Recv() (*LoadResponse, error)
-	grpc.ClientStream
-}
-
-type controlLoadClient struct {
-	grpc.ClientStream
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ManifestCreate(ctx, req.(*ManifestCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *controlLoadClient) Recv() (*LoadResponse, error) {
-	m := new(LoadResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _Control_ManifestAnnotate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ManifestAnnotateRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(ControlServer).ManifestAnnotate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/ManifestAnnotate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ManifestAnnotate(ctx, req.(*ManifestAnnotateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *controlClient) Import(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (Control_ImportClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[5], "/isula.build.v1.Control/Import", opts...)
-	if err != nil {
+func _Control_ManifestInspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ManifestInspectRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &controlImportClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(ControlServer).ManifestInspect(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/ManifestInspect",
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ManifestInspect(ctx, req.(*ManifestInspectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Control_ImportClient interface {
-	Recv() (*ImportResponse, error)
-	grpc.ClientStream
+func _Control_ManifestPush_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ManifestPushRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).ManifestPush(m, &controlManifestPushServer{stream})
 }
 
-type controlImportClient struct {
-	grpc.ClientStream
+type Control_ManifestPushServer interface {
+	Send(*ManifestPushResponse) error
+	grpc.ServerStream
 }
 
-func (x *controlImportClient) Recv() (*ImportResponse, error) {
-	m := new(ImportResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+type controlManifestPushServer struct {
+	grpc.ServerStream
 }
 
-func (c *controlClient) Tag(ctx context.Context, in *TagRequest, opts ...grpc.CallOption) (*types.Empty, error) {
-	out := new(types.Empty)
-	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Tag", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (x *controlManifestPushServer) Send(m *ManifestPushResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *controlClient) Save(ctx context.Context, in *SaveRequest, opts ...grpc.CallOption) (Control_SaveClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[6], "/isula.build.v1.Control/Save", opts...)
-	if err != nil {
+func _Control_CacheList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CacheListRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &controlSaveClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(ControlServer).CacheList(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/CacheList",
 	}
-	return x, nil
-}
-
-type Control_SaveClient interface {
-	Recv() (*SaveResponse, error)
-	grpc.ClientStream
-}
-
-type controlSaveClient struct {
-	grpc.ClientStream
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).CacheList(ctx, req.(*CacheListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *controlSaveClient) Recv() (*SaveResponse, error) {
-	m := new(SaveResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _Control_CacheRemove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CacheRemoveRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(ControlServer).CacheRemove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/CacheRemove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).CacheRemove(ctx, req.(*CacheRemoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *controlClient) Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error) {
-	out := new(InfoResponse)
-	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/Info", in, out, opts...)
-	if err != nil {
+func _Control_BuilderCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuilderCreateRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ControlServer).BuilderCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/BuilderCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).BuilderCreate(ctx, req.(*BuilderCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *controlClient) ManifestCreate(ctx context.Context, in *ManifestCreateRequest, opts ...grpc.CallOption) (*ManifestCreateResponse, error) {
-	out := new(ManifestCreateResponse)
-	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/ManifestCreate", in, out, opts...)
-	if err != nil {
+func _Control_BuilderList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuilderListRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ControlServer).BuilderList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/BuilderList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).BuilderList(ctx, req.(*BuilderListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *controlClient) ManifestAnnotate(ctx context.Context, in *ManifestAnnotateRequest, opts ...grpc.CallOption) (*types.Empty, error) {
-	out := new(types.Empty)
-	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/ManifestAnnotate", in, out, opts...)
-	if err != nil {
+func _Control_BuilderRemove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuilderRemoveRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ControlServer).BuilderRemove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/BuilderRemove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).BuilderRemove(ctx, req.(*BuilderRemoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *controlClient) ManifestInspect(ctx context.Context, in *ManifestInspectRequest, opts ...grpc.CallOption) (*ManifestInspectResponse, error) {
-	out := new(ManifestInspectResponse)
-	err := c.cc.Invoke(ctx, "/isula.build.v1.Control/ManifestInspect", in, out, opts...)
-	if err != nil {
+func _Control_Negotiate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NegotiateRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ControlServer).Negotiate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/Negotiate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Negotiate(ctx, req.(*NegotiateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *controlClient) ManifestPush(ctx context.Context, in *ManifestPushRequest, opts ...grpc.CallOption) (Control_ManifestPushClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[7], "/isula.build.v1.Control/ManifestPush", opts...)
-	if err != nil {
+func _Control_BuildsList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildsListRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &controlManifestPushClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(ControlServer).BuildsList(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/BuildsList",
 	}
-	return x, nil
-}
-
-type Control_ManifestPushClient interface {
-	Recv() (*ManifestPushResponse, error)
-	grpc.ClientStream
-}
-
-type controlManifestPushClient struct {
-	grpc.ClientStream
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).BuildsList(ctx, req.(*BuildsListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *controlManifestPushClient) Recv() (*ManifestPushResponse, error) {
-	m := new(ManifestPushResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _Control_RemoteInspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoteInspectRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
-}
-
-// ControlServer is the server API for Control service.
-type ControlServer interface {
-	// Build requests a new image building
-	Build(context.Context, *BuildRequest) (*BuildResponse, error)
-	// Status pipes the image building process log back to client
-	Status(*StatusRequest, Control_StatusServer) error
-	// List lists all images in isula-builder
-	List(context.Context, *ListRequest) (*ListResponse, error)
-	// Version requests version information of isula-builder
-	Version(context.Context, *types.Empty) (*VersionResponse, error)
-	// Push pushes image to remote repository
-	Push(*PushRequest, Control_PushServer) error
-	// Pull pulls image from remote repository
-	Pull(*PullRequest, Control_PullServer) error
-	// Remove sends an image remove request to isula-builder
-	Remove(*RemoveRequest, Control_RemoveServer) error
-	// HealthCheck requests a health checking in isula-builder
-	HealthCheck(context.Context, *types.Empty) (*HealthCheckResponse, error)
-	// Login requests to access image registry with username and password
-	Login(context.Context, *LoginRequest) (*LoginResponse, error)
-	// Logout requests to logout registry and delete any credentials
-	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
-	// Load requests an image tar load
-	Load(*LoadRequest, Control_LoadServer) error
-	// Import requests import a new image
-	Import(*ImportRequest, Control_ImportServer) error
-	// Tag requests to tag an image
-	Tag(context.Context, *TagRequest) (*types.Empty, error)
-	// Save saves the image to tarball
-	Save(*SaveRequest, Control_SaveServer) error
-	// Info requests isula-build system information
-	Info(context.Context, *InfoRequest) (*InfoResponse, error)
-	// ManifestCreate requests to create manifest list
-	ManifestCreate(context.Context, *ManifestCreateRequest) (*ManifestCreateResponse, error)
-	// ManifestAnnotate requests to annotate manifest list
-	ManifestAnnotate(context.Context, *ManifestAnnotateRequest) (*types.Empty, error)
-	// ManifestInspect requests to inspect manifest list
-	ManifestInspect(context.Context, *ManifestInspectRequest) (*ManifestInspectResponse, error)
-	// ManifestPush requests to push manifest list
-	ManifestPush(*ManifestPushRequest, Control_ManifestPushServer) error
-}
-
-// UnimplementedControlServer can be embedded to have forward compatible implementations.
-type UnimplementedControlServer struct {
-}
-
-func (*UnimplementedControlServer) Build(ctx context.Context, req *BuildRequest) (*BuildResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Build not implemented")
-}
-func (*UnimplementedControlServer) Status(req *StatusRequest, srv Control_StatusServer) error {
-	return status.Errorf(codes.Unimplemented, "method Status not implemented")
-}
-func (*UnimplementedControlServer) List(ctx context.Context, req *ListRequest) (*ListResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
-}
-func (*UnimplementedControlServer) Version(ctx context.Context, req *types.Empty) (*VersionResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
-}
-func (*UnimplementedControlServer) Push(req *PushRequest, srv Control_PushServer) error {
-	return status.Errorf(codes.Unimplemented, "method Push not implemented")
-}
-func (*UnimplementedControlServer) Pull(req *PullRequest, srv Control_PullServer) error {
-	return status.Errorf(codes.Unimplemented, "method Pull not implemented")
-}
-func (*UnimplementedControlServer) Remove(req *RemoveRequest, srv Control_RemoveServer) error {
-	return status.Errorf(codes.Unimplemented, "method Remove not implemented")
-}
-func (*UnimplementedControlServer) HealthCheck(ctx context.Context, req *types.Empty) (*HealthCheckResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
-}
-func (*UnimplementedControlServer) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
-}
-func (*UnimplementedControlServer) Logout(ctx context.Context, req *LogoutRequest) (*LogoutResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
-}
-func (*UnimplementedControlServer) Load(req *LoadRequest, srv Control_LoadServer) error {
-	return status.Errorf(codes.Unimplemented, "method Load not implemented")
-}
-func (*UnimplementedControlServer) Import(req *ImportRequest, srv Control_ImportServer) error {
-	return status.Errorf(codes.Unimplemented, "method Import not implemented")
-}
-func (*UnimplementedControlServer) Tag(ctx context.Context, req *TagRequest) (*types.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Tag not implemented")
-}
-func (*UnimplementedControlServer) Save(req *SaveRequest, srv Control_SaveServer) error {
-	return status.Errorf(codes.Unimplemented, "method Save not implemented")
-}
-func (*UnimplementedControlServer) Info(ctx context.Context, req *InfoRequest) (*InfoResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Info not implemented")
-}
-func (*UnimplementedControlServer) ManifestCreate(ctx context.Context, req *ManifestCreateRequest) (*ManifestCreateResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ManifestCreate not implemented")
-}
-func (*UnimplementedControlServer) ManifestAnnotate(ctx context.Context, req *ManifestAnnotateRequest) (*types.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ManifestAnnotate not implemented")
-}
-func (*UnimplementedControlServer) ManifestInspect(ctx context.Context, req *ManifestInspectRequest) (*ManifestInspectResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ManifestInspect not implemented")
-}
-func (*UnimplementedControlServer) ManifestPush(req *ManifestPushRequest, srv Control_ManifestPushServer) error {
-	return status.Errorf(codes.Unimplemented, "method ManifestPush not implemented")
-}
-
-func RegisterControlServer(s *grpc.Server, srv ControlServer) {
-	s.RegisterService(&_Control_serviceDesc, srv)
+	if interceptor == nil {
+		return srv.(ControlServer).RemoteInspect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/RemoteInspect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).RemoteInspect(ctx, req.(*RemoteInspectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_Build_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(BuildRequest)
+func _Control_Prune_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServer).Build(ctx, in)
+		return srv.(ControlServer).Prune(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/isula.build.v1.Control/Build",
+		FullMethod: "/isula.build.v1.Control/Prune",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServer).Build(ctx, req.(*BuildRequest))
+		return srv.(ControlServer).Prune(ctx, req.(*PruneRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_Status_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(StatusRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Control_VerifyRemote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRemoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(ControlServer).Status(m, &controlStatusServer{stream})
-}
-
-type Control_StatusServer interface {
-	Send(*StatusResponse) error
-	grpc.ServerStream
-}
-
-type controlStatusServer struct {
-	grpc.ServerStream
+	if interceptor == nil {
+		return srv.(ControlServer).VerifyRemote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/VerifyRemote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).VerifyRemote(ctx, req.(*VerifyRemoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *controlStatusServer) Send(m *StatusResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _Control_ImagePrune_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImagePruneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).ImagePrune(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/ImagePrune",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ImagePrune(ctx, req.(*ImagePruneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListRequest)
+func _Control_Backup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServer).List(ctx, in)
+		return srv.(ControlServer).Backup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/isula.build.v1.Control/List",
+		FullMethod: "/isula.build.v1.Control/Backup",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServer).List(ctx, req.(*ListRequest))
+		return srv.(ControlServer).Backup(ctx, req.(*BackupRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(types.Empty)
+func _Control_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServer).Version(ctx, in)
+		return srv.(ControlServer).Restore(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/isula.build.v1.Control/Version",
+		FullMethod: "/isula.build.v1.Control/Restore",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServer).Version(ctx, req.(*types.Empty))
+		return srv.(ControlServer).Restore(ctx, req.(*RestoreRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_Push_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(PushRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Control_RecordsExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordsExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(ControlServer).Push(m, &controlPushServer{stream})
-}
-
-type Control_PushServer interface {
-	Send(*PushResponse) error
-	grpc.ServerStream
-}
-
-type controlPushServer struct {
-	grpc.ServerStream
-}
-
-func (x *controlPushServer) Send(m *PushResponse) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Control_Pull_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(PullRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+	if interceptor == nil {
+		return srv.(ControlServer).RecordsExport(ctx, in)
 	}
-	return srv.(ControlServer).Pull(m, &controlPullServer{stream})
-}
-
-type Control_PullServer interface {
-	Send(*PullResponse) error
-	grpc.ServerStream
-}
-
-type controlPullServer struct {
-	grpc.ServerStream
-}
-
-func (x *controlPullServer) Send(m *PullResponse) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Control_Remove_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(RemoveRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/RecordsExport",
 	}
-	return srv.(ControlServer).Remove(m, &controlRemoveServer{stream})
-}
-
-type Control_RemoveServer interface {
-	Send(*RemoveResponse) error
-	grpc.ServerStream
-}
-
-type controlRemoveServer struct {
-	grpc.ServerStream
-}
-
-func (x *controlRemoveServer) Send(m *RemoveResponse) error {
-	return x.ServerStream.SendMsg(m)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).RecordsExport(ctx, req.(*RecordsExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(types.Empty)
+func _Control_RecordsImport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordsImportRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServer).HealthCheck(ctx, in)
+		return srv.(ControlServer).RecordsImport(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/isula.build.v1.Control/HealthCheck",
+		FullMethod: "/isula.build.v1.Control/RecordsImport",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServer).HealthCheck(ctx, req.(*types.Empty))
+		return srv.(ControlServer).RecordsImport(ctx, req.(*RecordsImportRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoginRequest)
+func _Control_Inspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServer).Login(ctx, in)
+		return srv.(ControlServer).Inspect(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/isula.build.v1.Control/Login",
+		FullMethod: "/isula.build.v1.Control/Inspect",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServer).Login(ctx, req.(*LoginRequest))
+		return srv.(ControlServer).Inspect(ctx, req.(*InspectRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LogoutRequest)
+func _Control_Promote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromoteRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServer).Logout(ctx, in)
+		return srv.(ControlServer).Promote(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/isula.build.v1.Control/Logout",
+		FullMethod: "/isula.build.v1.Control/Promote",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServer).Logout(ctx, req.(*LogoutRequest))
+		return srv.(ControlServer).Promote(ctx, req.(*PromoteRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_Load_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(LoadRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Control_Diff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(ControlServer).Load(m, &controlLoadServer{stream})
-}
-
-type Control_LoadServer interface {
-	Send(*LoadResponse) error
-	grpc.ServerStream
-}
-
-type controlLoadServer struct {
-	grpc.ServerStream
-}
-
-func (x *controlLoadServer) Send(m *LoadResponse) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Control_Import_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ImportRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+	if interceptor == nil {
+		return srv.(ControlServer).Diff(ctx, in)
 	}
-	return srv.(ControlServer).Import(m, &controlImportServer{stream})
-}
-
-type Control_ImportServer interface {
-	Send(*ImportResponse) error
-	grpc.ServerStream
-}
-
-type controlImportServer struct {
-	grpc.ServerStream
-}
-
-func (x *controlImportServer) Send(m *ImportResponse) error {
-	return x.ServerStream.SendMsg(m)
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/isula.build.v1.Control/Diff",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Diff(ctx, req.(*DiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_Tag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TagRequest)
+func _Control_LoginList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginListRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServer).Tag(ctx, in)
+		return srv.(ControlServer).LoginList(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/isula.build.v1.Control/Tag",
+		FullMethod: "/isula.build.v1.Control/LoginList",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServer).Tag(ctx, req.(*TagRequest))
+		return srv.(ControlServer).LoginList(ctx, req.(*LoginListRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_Save_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SaveRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(ControlServer).Save(m, &controlSaveServer{stream})
-}
-
-type Control_SaveServer interface {
-	Send(*SaveResponse) error
-	grpc.ServerStream
-}
-
-type controlSaveServer struct {
-	grpc.ServerStream
-}
-
-func (x *controlSaveServer) Send(m *SaveResponse) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Control_Info_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(InfoRequest)
+func _Control_Tree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TreeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServer).Info(ctx, in)
+		return srv.(ControlServer).Tree(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/isula.build.v1.Control/Info",
+		FullMethod: "/isula.build.v1.Control/Tree",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServer).Info(ctx, req.(*InfoRequest))
+		return srv.(ControlServer).Tree(ctx, req.(*TreeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_ManifestCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ManifestCreateRequest)
+func _Control_MetaSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MetaSetRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServer).ManifestCreate(ctx, in)
+		return srv.(ControlServer).MetaSet(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/isula.build.v1.Control/ManifestCreate",
+		FullMethod: "/isula.build.v1.Control/MetaSet",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServer).ManifestCreate(ctx, req.(*ManifestCreateRequest))
+		return srv.(ControlServer).MetaSet(ctx, req.(*MetaSetRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_ManifestAnnotate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ManifestAnnotateRequest)
+func _Control_MetaGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MetaGetRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServer).ManifestAnnotate(ctx, in)
+		return srv.(ControlServer).MetaGet(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/isula.build.v1.Control/ManifestAnnotate",
+		FullMethod: "/isula.build.v1.Control/MetaGet",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServer).ManifestAnnotate(ctx, req.(*ManifestAnnotateRequest))
+		return srv.(ControlServer).MetaGet(ctx, req.(*MetaGetRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_ManifestInspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ManifestInspectRequest)
+func _Control_Df_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DfRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServer).ManifestInspect(ctx, in)
+		return srv.(ControlServer).Df(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/isula.build.v1.Control/ManifestInspect",
+		FullMethod: "/isula.build.v1.Control/Df",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServer).ManifestInspect(ctx, req.(*ManifestInspectRequest))
+		return srv.(ControlServer).Df(ctx, req.(*DfRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Control_ManifestPush_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ManifestPushRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(ControlServer).ManifestPush(m, &controlManifestPushServer{stream})
-}
-
-type Control_ManifestPushServer interface {
-	Send(*ManifestPushResponse) error
-	grpc.ServerStream
-}
-
-type controlManifestPushServer struct {
-	grpc.ServerStream
-}
-
-func (x *controlManifestPushServer) Send(m *ManifestPushResponse) error {
-	return x.ServerStream.SendMsg(m)
-}
-
 var _Control_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "isula.build.v1.Control",
 	HandlerType: (*ControlServer)(nil),
@@ -3458,6 +7581,98 @@ var _Control_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ManifestInspect",
 			Handler:    _Control_ManifestInspect_Handler,
 		},
+		{
+			MethodName: "CacheList",
+			Handler:    _Control_CacheList_Handler,
+		},
+		{
+			MethodName: "CacheRemove",
+			Handler:    _Control_CacheRemove_Handler,
+		},
+		{
+			MethodName: "BuilderCreate",
+			Handler:    _Control_BuilderCreate_Handler,
+		},
+		{
+			MethodName: "BuilderList",
+			Handler:    _Control_BuilderList_Handler,
+		},
+		{
+			MethodName: "BuilderRemove",
+			Handler:    _Control_BuilderRemove_Handler,
+		},
+		{
+			MethodName: "Negotiate",
+			Handler:    _Control_Negotiate_Handler,
+		},
+		{
+			MethodName: "BuildsList",
+			Handler:    _Control_BuildsList_Handler,
+		},
+		{
+			MethodName: "RemoteInspect",
+			Handler:    _Control_RemoteInspect_Handler,
+		},
+		{
+			MethodName: "Prune",
+			Handler:    _Control_Prune_Handler,
+		},
+		{
+			MethodName: "VerifyRemote",
+			Handler:    _Control_VerifyRemote_Handler,
+		},
+		{
+			MethodName: "ImagePrune",
+			Handler:    _Control_ImagePrune_Handler,
+		},
+		{
+			MethodName: "Backup",
+			Handler:    _Control_Backup_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _Control_Restore_Handler,
+		},
+		{
+			MethodName: "RecordsExport",
+			Handler:    _Control_RecordsExport_Handler,
+		},
+		{
+			MethodName: "RecordsImport",
+			Handler:    _Control_RecordsImport_Handler,
+		},
+		{
+			MethodName: "Inspect",
+			Handler:    _Control_Inspect_Handler,
+		},
+		{
+			MethodName: "Promote",
+			Handler:    _Control_Promote_Handler,
+		},
+		{
+			MethodName: "Diff",
+			Handler:    _Control_Diff_Handler,
+		},
+		{
+			MethodName: "LoginList",
+			Handler:    _Control_LoginList_Handler,
+		},
+		{
+			MethodName: "Tree",
+			Handler:    _Control_Tree_Handler,
+		},
+		{
+			MethodName: "MetaSet",
+			Handler:    _Control_MetaSet_Handler,
+		},
+		{
+			MethodName: "MetaGet",
+			Handler:    _Control_MetaGet_Handler,
+		},
+		{
+			MethodName: "Df",
+			Handler:    _Control_Df_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -3500,6 +7715,11 @@ var _Control_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Control_ManifestPush_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Convert",
+			Handler:       _Control_Convert_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api/services/control.proto",
 }
@@ -32,6 +32,9 @@ type Builder interface {
 	StatusChan() <-chan string
 	CleanResources() error
 	EntityID() string
+	Progress() (currentStage, totalStages int)
+	Graph(format string) ([]byte, error)
+	Resources() *pb.BuildResources
 }
 
 // NewBuilder init a builder
@@ -29,6 +29,7 @@ import (
 
 	constant "isula.org/isula-build"
 	"isula.org/isula-build/image"
+	"isula.org/isula-build/pkg/urlcache"
 	"isula.org/isula-build/util"
 )
 
@@ -149,12 +150,17 @@ func (c *cmdBuilder) getCopyContextDir(from string) (string, func(), error) {
 	}
 
 	// "from" is neither name nor index of stage, consider that "from" is image description
+	if mountpoint, ok := c.stage.builder.cachedCopyFromMount(from); ok {
+		logrus.Debugf("Reuse mounted COPY from image %q, context dir %q", from, mountpoint)
+		return mountpoint, nil, nil
+	}
 	imgDesc, err := prepareImage(&image.PrepareImageOptions{
 		Ctx:           c.ctx,
 		FromImage:     from,
 		SystemContext: c.stage.buildOpt.systemContext,
 		Store:         c.stage.localStore,
 		Reporter:      c.stage.builder.cliLog,
+		ContainerName: image.ContainerNameOptions{BuildID: c.stage.builder.buildID, Stage: c.stage.name},
 	})
 	if err != nil {
 		return "", nil, err
@@ -165,8 +171,11 @@ func (c *cmdBuilder) getCopyContextDir(from string) (string, func(), error) {
 			logrus.Warnf("Clean layer[%s] for COPY from[%s] failed: %v", imgDesc.ContainerDesc.ContainerID, from, cerr)
 		}
 	}
+	// keep the mount for the rest of the build, later COPY instructions
+	// from the same image reuse it and the cleanup runs when it ends
+	c.stage.builder.storeCopyFromMount(from, imgDesc.ContainerDesc.Mountpoint, cleanup)
 
-	return imgDesc.ContainerDesc.Mountpoint, cleanup, nil
+	return imgDesc.ContainerDesc.Mountpoint, nil, nil
 }
 
 func (c *cmdBuilder) doCopy(opt *copyOptions) error {
@@ -233,6 +242,17 @@ func (c *cmdBuilder) executeAddAndCopy(isAdd bool) error {
 				cleanup()
 			}
 		}()
+	} else if tarCtx := c.stage.builder.tarContext; tarCtx != nil {
+		// an indexed tar context materializes the requested sources
+		// into the context dir only when an instruction needs them
+		for _, src := range args[:len(args)-1] {
+			if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+				continue
+			}
+			if err = tarCtx.Materialize(src, contextDir); err != nil {
+				return errors.Wrapf(err, "materialize %q from the context tarball failed", src)
+			}
+		}
 	}
 
 	// 4. get all of secure sources
@@ -325,10 +345,37 @@ func addFile(realSrc, globFile, dest string, opt *addOptions) error {
 	return extractArchive(realSrc, dest)
 }
 
+// addURL adds a single file downloaded from src URL, the downloaded
+// content is kept in the daemon url cache and shared across builds
+func (c *cmdBuilder) addURL(src, dest string, opt *addOptions) error {
+	if strings.HasSuffix(dest, string(os.PathSeparator)) || util.IsDirectory(dest) {
+		base := filepath.Base(strings.TrimRight(src, "/"))
+		if base == "" || base == "." || base == string(os.PathSeparator) || strings.Contains(base, "?") {
+			return errors.Errorf("unable to determine filename from URL %q, please specify the destination filename", src)
+		}
+		dest = filepath.Join(dest, base)
+	}
+
+	cache := urlcache.Default()
+	if cache == nil {
+		// no daemon managed cache here, download into dest directly
+		logrus.Debugf("URL cache is not initialized, directly downloading %q", src)
+		return util.CopyURLResource(c.ctx, src, dest, opt.chownPair.UID, opt.chownPair.GID)
+	}
+
+	cachedPath, err := cache.Fetch(c.ctx, src)
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("Copying url resource from %q to %q", cachedPath, dest)
+
+	return util.CopyFile(cachedPath, dest, opt.chownPair)
+}
+
 func (c *cmdBuilder) add(src, dest string, opt *addOptions) error {
 	// the src is URL
 	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
-		return errors.New("URL is not support yet")
+		return c.addURL(src, dest, opt)
 	}
 
 	globFiles, err := filepath.Glob(src)
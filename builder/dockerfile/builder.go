@@ -25,11 +25,12 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/containers/image/v5/manifest"
+	"github.com/containers/storage/pkg/idtools"
 	securejoin "github.com/cyphar/filepath-securejoin"
-	"github.com/opencontainers/go-digest"
 	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -42,6 +43,7 @@ import (
 	"isula.org/isula-build/image"
 	"isula.org/isula-build/pkg/logger"
 	"isula.org/isula-build/pkg/parser"
+	"isula.org/isula-build/pkg/tarindex"
 	"isula.org/isula-build/store"
 	"isula.org/isula-build/util"
 )
@@ -58,6 +60,27 @@ type BuildOptions struct {
 	Tag           string
 	AdditionalTag string
 	Format        string
+	// Platform selects the os/arch[/variant] of the base images, empty
+	// builds for the host platform
+	Platform string
+	// Compression is the layer compression of exported images, one of
+	// gzip, zstd, zstd:chunked or none, empty keeps the default
+	Compression string
+	// ParallelCopy runs consecutive independent COPY/ADD commands of a
+	// stage concurrently
+	ParallelCopy bool
+	// Memory bounds the memory of each RUN container in bytes, zero
+	// keeps the container unlimited
+	Memory int64
+	// CPUQuota is the cpu quota of each RUN container in microseconds
+	// per scheduler period, zero keeps the container unlimited
+	CPUQuota int64
+	// CPUs bounds each RUN container to that many cpus, zero keeps
+	// the container unlimited
+	CPUs float64
+	// PidsLimit bounds the processes of each RUN container, zero
+	// keeps the container unlimited
+	PidsLimit int64
 }
 
 // Builder is the object to build a Dockerfile
@@ -85,45 +108,155 @@ type Builder struct {
 	rsaKey             *rsa.PrivateKey
 	manifestType       string
 	outputManifestType []string
+	// checkpointDir is where the per-stage build checkpoints are persisted
+	checkpointDir string
+	// resumeBuildID is the buildID of the interrupted build to resume from
+	resumeBuildID string
+	checkpoint    buildCheckpoint
+	resumeStages  map[string]stageCheckpoint
+	resumeActive  bool
+	// cacheDir is where the shared build cache state is persisted
+	cacheDir string
+	// noCache skips cache lookups, the cache is still repopulated
+	noCache bool
+	// pullPolicyStrict rejects base images the signature policy would
+	// accept without any signature check
+	pullPolicyStrict bool
+	// cacheFrom are images or cache repositories consulted as cache sources
+	cacheFrom    []string
+	cacheRecords map[string]cacheRecord
+	// cacheTo is the repository the cache entries of this build are pushed to
+	cacheTo string
+	// cacheRefs maps cache keys to remote references found in an
+	// imported cache manifest
+	cacheRefs map[string]string
+	// cacheExports are the entries produced by this build, pushed to
+	// cacheTo after a successful build
+	cacheExports []cacheRecord
+	// validateOutput checks the built image against the OCI image spec
+	// before it is exported
+	validateOutput bool
+	// progressMutex guards the stage counters updated during Build and
+	// read by Progress from other goroutines
+	progressMutex sync.Mutex
+	currentStage  int
+	totalStages   int
+	// graph collects the resolved stage DAG with cache and timing
+	// annotations while the build runs
+	graph buildGraph
+	// copyFromMounts caches the mounted layers of COPY --from image
+	// sources for the duration of the build, so repeated COPY
+	// instructions from the same image reuse one mount
+	copyFromMounts map[string]copyFromMount
+	copyFromMutex  sync.Mutex
+	// tarContext is the indexed context tarball of a remote tar
+	// context, COPY and ADD materialize their sources from it on
+	// demand, nil when the context is a plain directory
+	tarContext *tarindex.Index
+	// usernsMappings remap ownership in the RW layers and run RUN
+	// commands in a user namespace, nil keeps the host namespace
+	usernsMappings *idtools.IDMappings
+	// storageOpt is passed to the graph driver when the RW layer of a
+	// stage is created, e.g. the project quota bounding its size
+	storageOpt map[string]string
+}
+
+// copyFromMount is one mounted COPY --from image source kept until
+// the build finishes
+type copyFromMount struct {
+	mountpoint string
+	cleanup    func()
 }
 
 // NewBuilder init a builder
 func NewBuilder(ctx context.Context, store *store.Store, req *pb.BuildRequest, runtimePath, buildDir, runDir string, key *rsa.PrivateKey) (*Builder, error) {
 	b := &Builder{
-		ctx:          ctx,
-		buildID:      req.BuildID,
-		entityID:     req.EntityID,
-		cliLog:       logger.NewCliLogger(constant.CliLogBufferLen),
-		unusedArgs:   make(map[string]string),
-		headingArgs:  make(map[string]string),
-		reservedArgs: make(map[string]string),
-		localStore:   store,
-		runtimePath:  runtimePath,
-		dataDir:      buildDir,
-		runDir:       runDir,
-		rsaKey:       key,
-	}
+		ctx:            ctx,
+		buildID:        req.BuildID,
+		entityID:       req.EntityID,
+		cliLog:         logger.NewCliLogger(constant.CliLogBufferLen),
+		unusedArgs:     make(map[string]string),
+		headingArgs:    make(map[string]string),
+		reservedArgs:   make(map[string]string),
+		localStore:     store,
+		runtimePath:    runtimePath,
+		dataDir:        buildDir,
+		runDir:         runDir,
+		rsaKey:         key,
+		copyFromMounts: make(map[string]copyFromMount),
+	}
+
+	if checkpointDir, ok := ctx.Value(util.CheckpointDirKey(util.CheckpointDir)).(string); ok {
+		b.checkpointDir = checkpointDir
+	}
+	if cacheDir, ok := ctx.Value(util.BuildCacheDirKey(util.BuildCacheDir)).(string); ok {
+		b.cacheDir = cacheDir
+	}
+	if tarCtx, ok := ctx.Value(util.TarContextKey(util.TarContext)).(*tarindex.Index); ok {
+		b.tarContext = tarCtx
+	}
+	b.resumeBuildID = req.GetResumeBuildID()
+	b.checkpoint = buildCheckpoint{BuildID: req.BuildID}
+	b.noCache = req.GetNoCache()
+	b.pullPolicyStrict = req.GetPullPolicyStrict()
+	b.cliLog.SetTimestamps(req.GetLogTimestamps())
+	b.cacheFrom = req.GetCacheFrom()
+	b.cacheTo = req.GetCacheTo()
+	b.validateOutput = req.GetValidateOutput()
 
 	args, err := b.parseBuildArgs(req.GetBuildArgs(), req.GetEncrypted())
 	if err != nil {
 		return nil, errors.Wrap(err, "parse build-arg failed")
 	}
 
+	// values of build-args marked sensitive never reach the status
+	// stream, every message is filtered through the logger
+	var redactions []string
+	for _, name := range req.GetSensitiveArgs() {
+		if v, ok := args[name]; ok && v != "" {
+			redactions = append(redactions, v)
+		}
+	}
+	if len(redactions) != 0 {
+		b.cliLog.SetRedactions(redactions)
+	}
+
 	for _, c := range req.GetCapAddList() {
 		if !util.CheckCap(c) {
 			return nil, errors.Errorf("cap %v is invalid", c)
 		}
 	}
 
+	if b.usernsMappings, err = resolveUsernsMappings(req.GetUserns(), req.GetUsernsUidMap(), req.GetUsernsGidMap()); err != nil {
+		return nil, err
+	}
+
+	if b.storageOpt, err = parseStorageOpts(req.GetStorageOpts()); err != nil {
+		return nil, err
+	}
+
 	b.buildOpts = BuildOptions{
-		ContextDir: req.GetContextDir(),
-		File:       req.GetFileContent(),
-		BuildArgs:  args,
-		CapAddList: req.GetCapAddList(),
-		ProxyFlag:  req.GetProxy(),
-		Iidfile:    req.GetIidfile(),
-		Output:     []string{req.GetOutput()},
-		Format:     req.GetFormat(),
+		ContextDir:   req.GetContextDir(),
+		File:         req.GetFileContent(),
+		BuildArgs:    args,
+		CapAddList:   req.GetCapAddList(),
+		ProxyFlag:    req.GetProxy(),
+		Iidfile:      req.GetIidfile(),
+		Output:       []string{req.GetOutput()},
+		Format:       req.GetFormat(),
+		Platform:     req.GetPlatform(),
+		Compression:  req.GetCompression(),
+		ParallelCopy: req.GetParallelCopy(),
+		Memory:       req.GetMemory(),
+		CPUQuota:     req.GetCpuQuota(),
+		CPUs:         req.GetCpus(),
+		PidsLimit:    req.GetPidsLimit(),
+	}
+	if err := checkRunResources(&b.buildOpts); err != nil {
+		return nil, err
+	}
+	if err := util.CheckCompressionFormat(b.buildOpts.Compression); err != nil {
+		return nil, err
 	}
 	b.parseStaticBuildOpts(req)
 	tag, additionalTag, err := parseTag(req.Output, req.AdditionalTag)
@@ -256,7 +389,7 @@ func (b *Builder) parseFiles() error {
 		return errors.Wrap(err, "create parser failed")
 	}
 
-	srcHasher := digest.Canonical.Digester()
+	srcHasher := util.DigestAlgorithm().Digester()
 	rc := bytes.NewBufferString(b.buildOpts.File)
 	reader := io.TeeReader(rc, srcHasher.Hash())
 	playbook, err := p.Parse(reader, false)
@@ -312,6 +445,18 @@ func (b *Builder) newStageBuilders() error {
 			return err
 		}
 
+		// a requested platform steers the manifest list selection of
+		// the base images towards the target architecture
+		if b.buildOpts.Platform != "" {
+			osChoice, archChoice, variant, pErr := util.ParsePlatform(b.buildOpts.Platform)
+			if pErr != nil {
+				return pErr
+			}
+			sb.buildOpt.systemContext.OSChoice = osChoice
+			sb.buildOpt.systemContext.ArchitectureChoice = archChoice
+			sb.buildOpt.systemContext.VariantChoice = variant
+		}
+
 		b.stageBuilders = append(b.stageBuilders, sb)
 	}
 
@@ -462,8 +607,59 @@ func (b *Builder) Build() (string, error) {
 		return "", err
 	}
 
+	// report the bytes to be pulled for base images missing locally, so
+	// users on metered links can abort before the transfer starts
+	b.estimatePullSize(b.ctx)
+
+	b.checkpoint.DockerfileDigest = b.dockerfileDigest
+	b.resumeActive = true
+	b.loadResumeCheckpoint()
+	b.loadBuildCache()
+	b.prepareCacheFrom(b.ctx)
+	b.setProgress(0, len(b.stageBuilders))
+	b.graph = buildGraph{BuildID: b.buildID, Stages: make([]graphStage, 0, len(b.stageBuilders))}
+
 	// 3. loop StageBuilders for building
+	stageKeys := make([]string, len(b.stageBuilders))
+	contentKeys := make([]string, len(b.stageBuilders))
 	for _, stage := range b.stageBuilders {
+		// the client may have canceled the build, stop before the next stage
+		if err = b.ctx.Err(); err != nil {
+			return "", errors.Wrap(err, "context finished")
+		}
+		b.setProgress(stage.position+1, len(b.stageBuilders))
+		stageKeys[stage.position] = b.stageCacheKey(stage, stageKeys)
+		contentKeys[stage.position] = b.stageContentKey(stage, contentKeys)
+		if resumedID, ok := b.resumeStage(stage, stageKeys[stage.position]); ok {
+			stage.imageID = resumedID
+			imageID = resumedID
+			displayID := resumedID
+			if len(displayID) > constant.DefaultIDLen {
+				displayID = displayID[:constant.DefaultIDLen]
+			}
+			b.cliLog.Print("Resuming stage %d from checkpointed image %s\n", stage.position, displayID)
+			b.recordStageCheckpoint(stage, stageKeys[stage.position])
+			b.recordGraphStage(stage, true, 0)
+			continue
+		}
+		cachedID, ok := b.cachedStage(contentKeys[stage.position])
+		if !ok {
+			cachedID, ok = b.remoteCachedStage(contentKeys[stage.position])
+		}
+		if ok {
+			stage.imageID = cachedID
+			imageID = cachedID
+			displayID := cachedID
+			if len(displayID) > constant.DefaultIDLen {
+				displayID = displayID[:constant.DefaultIDLen]
+			}
+			b.cliLog.Print("Stage %d using cached image %s\n", stage.position, displayID)
+			b.recordStageCheckpoint(stage, stageKeys[stage.position])
+			b.recordGraphStage(stage, true, 0)
+			continue
+		}
+
+		stageStart := time.Now()
 		stageTimer := b.cliLog.StartTimer(fmt.Sprintf("Stage %d", stage.position))
 		// update FROM from name to imageID if it is based on previous stage
 		if idx := stage.fromStageIdx; idx != -1 {
@@ -477,23 +673,69 @@ func (b *Builder) Build() (string, error) {
 			b.Logger().Errorf("Builder[%s] build for stage[%s] failed for: %v", b.buildID, stage.name, err)
 			return "", errors.Wrapf(err, "building image for stage[%s] failed", stage.name)
 		}
+		b.recordStageCheckpoint(stage, stageKeys[stage.position])
+		b.recordCachedStage(stage, contentKeys[stage.position])
+		b.recordGraphStage(stage, false, time.Since(stageStart))
+		if err = b.checkSessionDirQuota(); err != nil {
+			return "", err
+		}
+	}
+
+	if b.validateOutput {
+		if err = b.validateOutputImage(imageID); err != nil {
+			return "", errors.Wrap(err, "output validation failed")
+		}
 	}
 
 	// 4. export images
 	if err = b.export(imageID); err != nil {
 		return "", errors.Wrapf(err, "exporting images failed")
 	}
+	b.exportBuildCache()
 
 	// 5. output imageID
 	if err = b.writeImageID(imageID); err != nil {
 		return "", errors.Wrapf(err, "writing image ID failed")
 	}
+	// checkpoints are only kept for interrupted builds
+	b.removeCheckpoints()
 
 	b.cliLog.StopTimer(executeTimer)
 	b.Logger().Debugf("Time Cost:\n%s", b.cliLog.Summary())
 	return imageID, nil
 }
 
+// cachedCopyFromMount returns the cached mountpoint of a COPY --from
+// image source of this build
+func (b *Builder) cachedCopyFromMount(from string) (string, bool) {
+	b.copyFromMutex.Lock()
+	defer b.copyFromMutex.Unlock()
+	mount, ok := b.copyFromMounts[from]
+	return mount.mountpoint, ok
+}
+
+// storeCopyFromMount keeps the mounted layer of a COPY --from image
+// source until the build finishes
+func (b *Builder) storeCopyFromMount(from, mountpoint string, cleanup func()) {
+	b.copyFromMutex.Lock()
+	defer b.copyFromMutex.Unlock()
+	if b.copyFromMounts == nil {
+		b.copyFromMounts = make(map[string]copyFromMount)
+	}
+	b.copyFromMounts[from] = copyFromMount{mountpoint: mountpoint, cleanup: cleanup}
+}
+
+// releaseCopyFromMounts unmounts the COPY --from image sources kept
+// mounted for reuse during the build
+func (b *Builder) releaseCopyFromMounts() {
+	b.copyFromMutex.Lock()
+	defer b.copyFromMutex.Unlock()
+	for from, mount := range b.copyFromMounts {
+		mount.cleanup()
+		delete(b.copyFromMounts, from)
+	}
+}
+
 func (b *Builder) cleanup() {
 	// 1. warn user about the unused build-args if has
 	if len(b.unusedArgs) != 0 {
@@ -511,6 +753,7 @@ func (b *Builder) cleanup() {
 			b.Logger().Warnf("Failed to cleanup stage resources for stage %q: %v", stage.name, err)
 		}
 	}
+	b.releaseCopyFromMounts()
 
 	// 3. close channel for status
 	b.cliLog.CloseContent()
@@ -537,12 +780,13 @@ func (b *Builder) export(imageID string) error {
 			continue
 		}
 		exOpts := exporter.ExportOptions{
-			Ctx:           b.ctx,
-			SystemContext: image.GetSystemContext(),
-			ReportWriter:  b.cliLog,
-			ExportID:      b.buildID,
-			DataDir:       b.dataDir,
-			ManifestType:  b.outputManifestType[i],
+			Ctx:               b.ctx,
+			SystemContext:     image.GetSystemContext(),
+			ReportWriter:      b.cliLog,
+			ExportID:          b.buildID,
+			DataDir:           b.dataDir,
+			ManifestType:      b.outputManifestType[i],
+			CompressionFormat: b.buildOpts.Compression,
 		}
 		if exErr := exporter.Export(imageID, o, exOpts, b.localStore); exErr != nil {
 			b.Logger().Errorf("Image %s output to %s failed with: %v", imageID, o, exErr)
@@ -593,11 +837,23 @@ func (b *Builder) StatusChan() <-chan string {
 	return b.cliLog.GetContent()
 }
 
+// checkSessionDirQuota checks the session working directories against the
+// temporary space a single build may take, so one build filling up the disk
+// fails itself instead of starving the other sessions
+func (b *Builder) checkSessionDirQuota() error {
+	for _, dir := range []string{b.dataDir, b.runDir} {
+		if err := util.CheckDirSizeLimit(dir, constant.MaxSessionDirSize); err != nil {
+			return errors.Wrapf(err, "temporary space quota for build %s exceeded", b.buildID)
+		}
+	}
+	return nil
+}
+
 // CleanResources removes data dir and run dir of builder, and returns the last removing error
 func (b *Builder) CleanResources() error {
 	var err error
 	for _, dir := range []string{b.dataDir, b.runDir} {
-		if rerr := os.RemoveAll(dir); rerr != nil {
+		if rerr := util.ForceRemoveAll(dir); rerr != nil {
 			b.Logger().Errorf("Removing working dir %q failed: %v", dir, rerr)
 			err = rerr
 		}
@@ -610,6 +866,20 @@ func (b *Builder) EntityID() string {
 	return b.entityID
 }
 
+func (b *Builder) setProgress(current, total int) {
+	b.progressMutex.Lock()
+	b.currentStage, b.totalStages = current, total
+	b.progressMutex.Unlock()
+}
+
+// Progress returns the stage the build is currently working on and the
+// total number of stages
+func (b *Builder) Progress() (int, int) {
+	b.progressMutex.Lock()
+	defer b.progressMutex.Unlock()
+	return b.currentStage, b.totalStages
+}
+
 func parseOutputTag(output string) string {
 	outputFields := strings.Split(output, ":")
 	const archiveOutputWithoutTagLen = 2
@@ -618,6 +888,24 @@ func parseOutputTag(output string) string {
 	switch {
 	case (outputFields[0] == constant.DockerDaemonTransport || outputFields[0] == constant.IsuladTransport) && len(outputFields) > 1:
 		tag = strings.Join(outputFields[1:], ":")
+	case outputFields[0] == constant.ContainersStorageTransport && len(outputFields) > 1:
+		tag = strings.Join(outputFields[1:], ":")
+		// strip the optional [driver@graphroot+runroot] storage specifier
+		// before the image name
+		if strings.HasPrefix(tag, "[") {
+			if idx := strings.Index(tag, "]"); idx != -1 {
+				tag = tag[idx+1:]
+			}
+		}
+	case outputFields[0] == constant.ContainerdTransport && len(outputFields) > 1:
+		// a containerd output with four fields carries the namespace
+		// between the transport and the image name
+		const containerdOutputWithNamespaceLen = 4
+		if len(outputFields) == containerdOutputWithNamespaceLen {
+			tag = strings.Join(outputFields[2:], ":")
+			break
+		}
+		tag = strings.Join(outputFields[1:], ":")
 	case exporter.CheckArchiveFormat(outputFields[0]) == nil && len(outputFields) > archiveOutputWithoutTagLen:
 		tag = strings.Join(outputFields[archiveOutputWithoutTagLen:], ":")
 	case util.CheckImageFormat(outputFields[0]) == nil && len(outputFields) > 1:
@@ -1278,6 +1278,26 @@ func TestParseTag(t *testing.T) {
 			output: "isulad:isula/test:latest",
 			tag:    "isula/test:latest",
 		},
+		{
+			name:   "containerd output",
+			output: "containerd:isula/test:latest",
+			tag:    "isula/test:latest",
+		},
+		{
+			name:   "containers-storage output",
+			output: "containers-storage:isula/test:latest",
+			tag:    "isula/test:latest",
+		},
+		{
+			name:   "containers-storage output with storage specifier",
+			output: "containers-storage:[overlay@/var/lib/containers/storage+/run/containers/storage]isula/test:latest",
+			tag:    "isula/test:latest",
+		},
+		{
+			name:   "containerd output with namespace",
+			output: "containerd:k8s.io:isula/test:latest",
+			tag:    "isula/test:latest",
+		},
 		{
 			name:   "docker-archive output",
 			output: "docker-archive:./isula.tar:isula:latest",
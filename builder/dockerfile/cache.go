@@ -0,0 +1,341 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-06-20
+// Description: build cache related functions
+
+package dockerfile
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containers/image/v5/manifest"
+	securejoin "github.com/cyphar/filepath-securejoin"
+
+	constant "isula.org/isula-build"
+	dockerfile "isula.org/isula-build/builder/dockerfile/parser"
+	"isula.org/isula-build/exporter"
+	"isula.org/isula-build/image"
+	"isula.org/isula-build/pkg/parser"
+	"isula.org/isula-build/util"
+)
+
+// buildCacheFileName is the state file under the daemon build cache
+// directory which is shared by all builds
+const buildCacheFileName = "build_cache.json"
+
+// cacheRecord maps one stage content key to the image committed for it
+type cacheRecord struct {
+	// CacheKey is the content key of the stage instructions and context files
+	CacheKey string `json:"cacheKey"`
+	// ImageID is the image committed for the stage
+	ImageID string `json:"imageID"`
+	// CreatedAt is the time the record was written
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// buildCacheState is the persisted build cache, unlike the checkpoints
+// it is keyed purely on content and shared across builds
+type buildCacheState struct {
+	Records []cacheRecord `json:"records"`
+}
+
+// cacheStatePath returns the build cache state file path
+func (b *Builder) cacheStatePath() string {
+	return filepath.Join(b.cacheDir, buildCacheFileName)
+}
+
+// loadBuildCache reads the shared cache state, a missing or damaged
+// state file just means building without cache hits
+func (b *Builder) loadBuildCache() {
+	b.cacheRecords = make(map[string]cacheRecord)
+	b.cacheRefs = make(map[string]string)
+	if b.cacheDir == "" {
+		return
+	}
+
+	data, err := ioutil.ReadFile(filepath.Clean(b.cacheStatePath()))
+	if err == nil {
+		var state buildCacheState
+		if uErr := json.Unmarshal(data, &state); uErr != nil {
+			b.Logger().Warnf("Parse build cache state failed: %v", uErr)
+		} else {
+			for _, record := range state.Records {
+				b.cacheRecords[record.CacheKey] = record
+			}
+		}
+	}
+
+	// a cache manifest dropped into the cache dir, for example restored
+	// by a CI runner, contributes remote references for its cache keys
+	data, err = ioutil.ReadFile(filepath.Clean(filepath.Join(b.cacheDir, exporter.CacheManifestFileName)))
+	if err != nil {
+		return
+	}
+	cacheManifest, err := exporter.ParseCacheManifest(data)
+	if err != nil {
+		b.Logger().Warnf("Parse cache manifest failed: %v", err)
+		return
+	}
+	for _, record := range cacheManifest.Records {
+		b.cacheRefs[record.CacheKey] = record.Reference
+	}
+}
+
+// saveBuildCache persists the shared cache state. Failing to write it
+// never fails the build, the cache entries are just lost
+func (b *Builder) saveBuildCache() {
+	if b.cacheDir == "" {
+		return
+	}
+	if err := os.MkdirAll(b.cacheDir, constant.DefaultRootDirMode); err != nil {
+		b.Logger().Warnf("Create build cache dir failed: %v", err)
+		return
+	}
+
+	state := buildCacheState{Records: make([]cacheRecord, 0, len(b.cacheRecords))}
+	for _, record := range b.cacheRecords {
+		state.Records = append(state.Records, record)
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		b.Logger().Warnf("Marshal build cache state failed: %v", err)
+		return
+	}
+	if err := ioutil.WriteFile(b.cacheStatePath(), data, constant.DefaultRootFileMode); err != nil {
+		b.Logger().Warnf("Save build cache state failed: %v", err)
+	}
+}
+
+// stageContentKey extends the stage cache key with the checksums of the
+// context files referenced by ADD and COPY, so a changed context file
+// invalidates the cache even when the Dockerfile is unchanged
+func (b *Builder) stageContentKey(s *stageBuilder, prevKeys []string) string {
+	h := sha256.New()
+	io.WriteString(h, b.stageCacheKey(s, prevKeys))
+
+	for _, line := range s.rawStage.Lines {
+		if line.Command != dockerfile.Add && line.Command != dockerfile.Copy {
+			continue
+		}
+		// sources with --from come from another stage or image, the
+		// chained stage key and the raw line already cover them
+		if _, ok := line.Flags["from"]; ok {
+			continue
+		}
+		for _, checksum := range b.contextChecksums(line) {
+			io.WriteString(h, checksum)
+		}
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// contextChecksums calculates the checksums of the context files matched
+// by one ADD or COPY line. Sources which cannot be resolved are skipped
+// here, executing the command will report them properly
+func (b *Builder) contextChecksums(line *parser.Line) []string {
+	var checksums []string
+	if len(line.Cells) < 2 {
+		return checksums
+	}
+
+	for _, cell := range line.Cells[:len(line.Cells)-1] {
+		src := cell.Value
+		// remote sources are fetched at execution time, their URL is
+		// already part of the hashed raw line
+		if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+			continue
+		}
+		secureSrc, err := securejoin.SecureJoin(b.buildOpts.ContextDir, src)
+		if err != nil {
+			continue
+		}
+		globFiles, err := filepath.Glob(secureSrc)
+		if err != nil {
+			continue
+		}
+		for _, file := range globFiles {
+			checksum, hErr := util.SHA256Sum(file)
+			if hErr != nil {
+				continue
+			}
+			checksums = append(checksums, checksum)
+		}
+	}
+
+	return checksums
+}
+
+// cachedStage looks the stage content key up in the shared cache and
+// returns the recorded imageID when the image still exists locally
+func (b *Builder) cachedStage(contentKey string) (string, bool) {
+	if b.noCache {
+		return "", false
+	}
+
+	record, ok := b.cacheRecords[contentKey]
+	if !ok {
+		return "", false
+	}
+	if _, err := b.localStore.Image(record.ImageID); err != nil {
+		b.Logger().Debugf("Cached image %s no longer exists, dropping the record", record.ImageID)
+		delete(b.cacheRecords, contentKey)
+		return "", false
+	}
+
+	return record.ImageID, true
+}
+
+// recordCachedStage adds the committed stage to the shared cache. Builds
+// with --no-cache skip the lookup but still repopulate the cache here
+func (b *Builder) recordCachedStage(s *stageBuilder, contentKey string) {
+	if b.cacheDir == "" || s.imageID == "" {
+		return
+	}
+
+	record := cacheRecord{
+		CacheKey:  contentKey,
+		ImageID:   s.imageID,
+		CreatedAt: time.Now(),
+	}
+	b.cacheRecords[contentKey] = record
+	b.cacheExports = append(b.cacheExports, record)
+	b.saveBuildCache()
+}
+
+// prepareCacheFrom pulls the images given by --cache-from when they are
+// not present locally, so cache records pointing at them become valid
+// again. Entries without a tag or digest are cache repositories, they
+// are consulted lazily per cache key instead
+func (b *Builder) prepareCacheFrom(ctx context.Context) {
+	for _, from := range b.cacheFrom {
+		if isCacheRepository(from) {
+			continue
+		}
+		if _, err := b.localStore.Image(from); err == nil {
+			continue
+		}
+		b.cliLog.Print("Pulling cache source %s\n", from)
+		if _, _, err := image.ResolveFromImage(&image.PrepareImageOptions{
+			Ctx:           ctx,
+			FromImage:     from,
+			SystemContext: image.GetSystemContext(),
+			Store:         b.localStore,
+			Reporter:      b.cliLog,
+		}); err != nil {
+			b.cliLog.Print("[Warning] Pull cache source %s failed: %v\n", from, err)
+		}
+	}
+}
+
+// isCacheRepository reports whether the cache-from entry names a bare
+// repository instead of a concrete image
+func isCacheRepository(ref string) bool {
+	lastSegment := ref
+	if idx := strings.LastIndex(ref, "/"); idx != -1 {
+		lastSegment = ref[idx+1:]
+	}
+	return !strings.ContainsAny(lastSegment, ":@")
+}
+
+// remoteCachedStage looks the stage content key up in the imported cache
+// manifest and in the cache repositories given by --cache-from, pulling
+// the cached image on a hit
+func (b *Builder) remoteCachedStage(contentKey string) (string, bool) {
+	if b.noCache {
+		return "", false
+	}
+
+	refs := make([]string, 0, len(b.cacheFrom)+1)
+	if ref, ok := b.cacheRefs[contentKey]; ok {
+		refs = append(refs, ref)
+	}
+	for _, from := range b.cacheFrom {
+		if isCacheRepository(from) {
+			refs = append(refs, exporter.CacheReference(from, contentKey))
+		}
+	}
+
+	for _, ref := range refs {
+		_, si, err := image.ResolveFromImage(&image.PrepareImageOptions{
+			Ctx:           b.ctx,
+			FromImage:     ref,
+			SystemContext: image.GetSystemContext(),
+			Store:         b.localStore,
+			Reporter:      b.cliLog,
+		})
+		if err != nil {
+			b.Logger().Debugf("No remote cache entry at %s: %v", ref, err)
+			continue
+		}
+		b.cacheRecords[contentKey] = cacheRecord{
+			CacheKey:  contentKey,
+			ImageID:   si.ID,
+			CreatedAt: time.Now(),
+		}
+		b.saveBuildCache()
+		return si.ID, true
+	}
+
+	return "", false
+}
+
+// exportBuildCache pushes the cache entries produced by this build to
+// the repository given by --cache-to and writes the cache manifest next
+// to the cache state. A failed cache export never fails the build
+func (b *Builder) exportBuildCache() {
+	if b.cacheTo == "" || len(b.cacheExports) == 0 {
+		return
+	}
+
+	cacheManifest := exporter.CacheManifest{Records: make([]exporter.CacheManifestRecord, 0, len(b.cacheExports))}
+	for _, record := range b.cacheExports {
+		ref := exporter.CacheReference(b.cacheTo, record.CacheKey)
+		exOpts := exporter.ExportOptions{
+			Ctx:           b.ctx,
+			SystemContext: image.GetSystemContext(),
+			ReportWriter:  b.cliLog,
+			ExportID:      b.buildID,
+			DataDir:       b.dataDir,
+			ManifestType:  manifest.DockerV2Schema2MediaType,
+		}
+		if err := exporter.Export(record.ImageID, constant.DockerTransport+"://"+ref, exOpts, b.localStore); err != nil {
+			b.cliLog.Print("[Warning] Push cache entry %s failed: %v\n", ref, err)
+			continue
+		}
+		cacheManifest.Records = append(cacheManifest.Records, exporter.CacheManifestRecord{
+			CacheKey:  record.CacheKey,
+			Reference: ref,
+			CreatedAt: record.CreatedAt,
+		})
+	}
+	if len(cacheManifest.Records) == 0 {
+		return
+	}
+
+	data, err := cacheManifest.Marshal()
+	if err != nil {
+		b.Logger().Warnf("Marshal cache manifest failed: %v", err)
+		return
+	}
+	if err := ioutil.WriteFile(filepath.Join(b.cacheDir, exporter.CacheManifestFileName), data, constant.DefaultRootFileMode); err != nil {
+		b.Logger().Warnf("Save cache manifest failed: %v", err)
+	}
+}
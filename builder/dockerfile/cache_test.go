@@ -0,0 +1,106 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-06-20
+// Description: build cache tests
+
+package dockerfile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/pkg/logger"
+)
+
+const cacheDockerfile = `
+FROM alpine AS base
+COPY app.conf /etc/app.conf
+
+FROM base
+RUN touch /tmp/final
+`
+
+func newCacheTestBuilder(t *testing.T, cacheDir, contextDir, content string) *Builder {
+	b := &Builder{
+		buildID: "cache-build",
+		buildOpts: BuildOptions{
+			File:       content,
+			ContextDir: contextDir,
+		},
+		cliLog:   logger.NewCliLogger(constant.CliLogBufferLen),
+		ctx:      context.Background(),
+		cacheDir: cacheDir,
+	}
+
+	assert.NilError(t, b.parseFiles())
+	assert.NilError(t, b.newStageBuilders())
+	b.loadBuildCache()
+
+	return b
+}
+
+func stageContentKeys(b *Builder) []string {
+	keys := make([]string, len(b.stageBuilders))
+	for _, stage := range b.stageBuilders {
+		keys[stage.position] = b.stageContentKey(stage, keys)
+	}
+	return keys
+}
+
+func TestStageContentKey(t *testing.T) {
+	tmpDir := fs.NewDir(t, t.Name())
+	defer tmpDir.Remove()
+	contextDir := fs.NewDir(t, t.Name(), fs.WithFile("app.conf", "port=80\n"))
+	defer contextDir.Remove()
+
+	b1 := newCacheTestBuilder(t, tmpDir.Path(), contextDir.Path(), cacheDockerfile)
+	keys1 := stageContentKeys(b1)
+	assert.Equal(t, len(keys1), 2)
+
+	// same instructions and context files produce the same keys
+	b2 := newCacheTestBuilder(t, tmpDir.Path(), contextDir.Path(), cacheDockerfile)
+	assert.DeepEqual(t, keys1, stageContentKeys(b2))
+
+	// a changed context file invalidates the keys, the Dockerfile is unchanged
+	changedCtx := fs.NewDir(t, t.Name(), fs.WithFile("app.conf", "port=8080\n"))
+	defer changedCtx.Remove()
+	b3 := newCacheTestBuilder(t, tmpDir.Path(), changedCtx.Path(), cacheDockerfile)
+	keys3 := stageContentKeys(b3)
+	assert.Assert(t, keys1[0] != keys3[0])
+	assert.Assert(t, keys1[1] != keys3[1])
+}
+
+func TestBuildCacheRoundTrip(t *testing.T) {
+	tmpDir := fs.NewDir(t, t.Name())
+	defer tmpDir.Remove()
+	contextDir := fs.NewDir(t, t.Name(), fs.WithFile("app.conf", "port=80\n"))
+	defer contextDir.Remove()
+
+	b := newCacheTestBuilder(t, tmpDir.Path(), contextDir.Path(), cacheDockerfile)
+	b.cacheRecords["key1"] = cacheRecord{CacheKey: "key1", ImageID: "image1", CreatedAt: time.Now()}
+	b.saveBuildCache()
+
+	// a new builder sharing the cache dir sees the record
+	reloaded := newCacheTestBuilder(t, tmpDir.Path(), contextDir.Path(), cacheDockerfile)
+	record, ok := reloaded.cacheRecords["key1"]
+	assert.Assert(t, ok)
+	assert.Equal(t, record.ImageID, "image1")
+
+	// --no-cache skips the lookup even when a record exists
+	reloaded.noCache = true
+	_, ok = reloaded.cachedStage("key1")
+	assert.Assert(t, !ok)
+}
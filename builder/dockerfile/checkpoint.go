@@ -0,0 +1,192 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-02-15
+// Description: build checkpoint related functions
+
+package dockerfile
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	constant "isula.org/isula-build"
+)
+
+// stageCheckpoint records one committed stage of a build, so a later
+// resumed build can reuse the committed image instead of rebuilding it
+type stageCheckpoint struct {
+	// Position is the index of the stage in the Dockerfile
+	Position int `json:"position"`
+	// Name is the stage name
+	Name string `json:"name"`
+	// CacheKey is the cache key of the stage instructions
+	CacheKey string `json:"cacheKey"`
+	// ImageID is the image committed for the stage
+	ImageID string `json:"imageID"`
+	// CreatedAt is the time the stage was committed
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// buildCheckpoint is the persisted progress of one build, stored as
+// one json file per buildID under the daemon checkpoint directory
+type buildCheckpoint struct {
+	BuildID          string            `json:"buildID"`
+	DockerfileDigest string            `json:"dockerfileDigest"`
+	Stages           []stageCheckpoint `json:"stages"`
+}
+
+// checkpointPath returns the checkpoint file path for the given buildID
+func (b *Builder) checkpointPath(buildID string) string {
+	return filepath.Join(b.checkpointDir, buildID+".json")
+}
+
+// stageCacheKey calculates the cache key of one stage from its base
+// image, its raw instructions and the build-args of this build. When the
+// stage is based on a previous stage, the key of that stage is chained in
+// so that a changed earlier stage invalidates the later ones
+func (b *Builder) stageCacheKey(s *stageBuilder, prevKeys []string) string {
+	h := sha256.New()
+	if s.fromStageIdx != -1 && s.fromStageIdx < len(prevKeys) {
+		io.WriteString(h, prevKeys[s.fromStageIdx])
+	} else {
+		io.WriteString(h, s.fromImage)
+	}
+
+	for _, line := range s.rawStage.Lines {
+		io.WriteString(h, line.Command)
+		io.WriteString(h, line.Raw)
+	}
+
+	args := make([]string, 0, len(b.buildOpts.BuildArgs))
+	for k, v := range b.buildOpts.BuildArgs {
+		args = append(args, k+"="+v)
+	}
+	sort.Strings(args)
+	for _, arg := range args {
+		io.WriteString(h, arg)
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// loadResumeCheckpoint loads the checkpoint of the build specified by
+// --resume and keeps the records for the stage loop. A missing or
+// mismatched checkpoint is not fatal, the build just starts over
+func (b *Builder) loadResumeCheckpoint() {
+	if b.resumeBuildID == "" || b.checkpointDir == "" {
+		return
+	}
+
+	data, err := ioutil.ReadFile(filepath.Clean(b.checkpointPath(b.resumeBuildID)))
+	if err != nil {
+		b.cliLog.Print("[Warning] No checkpoint found for build %s, starting over\n", b.resumeBuildID)
+		return
+	}
+
+	var cp buildCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		b.Logger().Warnf("Parse checkpoint of build %s failed: %v", b.resumeBuildID, err)
+		return
+	}
+	if cp.DockerfileDigest != b.dockerfileDigest {
+		b.cliLog.Print("[Warning] Dockerfile changed since build %s, starting over\n", b.resumeBuildID)
+		return
+	}
+
+	b.resumeStages = make(map[string]stageCheckpoint, len(cp.Stages))
+	for _, stage := range cp.Stages {
+		b.resumeStages[stage.CacheKey] = stage
+	}
+}
+
+// resumeStage checks whether the stage can be taken over from the resumed
+// checkpoint and returns the committed imageID when it can. Stages are only
+// resumed as a prefix of the stage list, once one stage has to be rebuilt
+// all the following ones are rebuilt as well
+func (b *Builder) resumeStage(s *stageBuilder, cacheKey string) (string, bool) {
+	if !b.resumeActive || len(b.resumeStages) == 0 {
+		return "", false
+	}
+
+	record, ok := b.resumeStages[cacheKey]
+	if !ok {
+		b.resumeActive = false
+		return "", false
+	}
+	if _, err := b.localStore.Image(record.ImageID); err != nil {
+		b.Logger().Warnf("Checkpointed image %s for stage %s no longer exists", record.ImageID, s.name)
+		b.resumeActive = false
+		return "", false
+	}
+
+	return record.ImageID, true
+}
+
+// recordStageCheckpoint persists the committed stage to the checkpoint
+// file of this build. Failing to write the checkpoint never fails the
+// build, resuming it later is just not possible from this stage
+func (b *Builder) recordStageCheckpoint(s *stageBuilder, cacheKey string) {
+	if b.checkpointDir == "" {
+		return
+	}
+
+	b.checkpoint.Stages = append(b.checkpoint.Stages, stageCheckpoint{
+		Position:  s.position,
+		Name:      s.name,
+		CacheKey:  cacheKey,
+		ImageID:   s.imageID,
+		CreatedAt: time.Now(),
+	})
+
+	if err := b.saveCheckpoint(); err != nil {
+		b.Logger().Warnf("Save checkpoint for build %s failed: %v", b.buildID, err)
+	}
+}
+
+func (b *Builder) saveCheckpoint() error {
+	if err := os.MkdirAll(b.checkpointDir, constant.DefaultRootDirMode); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(b.checkpoint)
+	if err != nil {
+		return errors.Wrap(err, "marshal checkpoint failed")
+	}
+
+	return ioutil.WriteFile(b.checkpointPath(b.buildID), data, constant.DefaultRootFileMode)
+}
+
+// removeCheckpoints deletes the checkpoint files of this build and of the
+// resumed build after the build finished successfully
+func (b *Builder) removeCheckpoints() {
+	if b.checkpointDir == "" {
+		return
+	}
+
+	buildIDs := []string{b.buildID}
+	if b.resumeBuildID != "" {
+		buildIDs = append(buildIDs, b.resumeBuildID)
+	}
+	for _, buildID := range buildIDs {
+		if err := os.Remove(b.checkpointPath(buildID)); err != nil && !os.IsNotExist(err) {
+			b.Logger().Warnf("Remove checkpoint of build %s failed: %v", buildID, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-02-15
+// Description: build checkpoint tests
+
+package dockerfile
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/pkg/logger"
+)
+
+const checkpointDockerfile = `
+FROM alpine AS base
+RUN touch /tmp/base
+
+FROM base
+RUN touch /tmp/final
+`
+
+func newCheckpointTestBuilder(t *testing.T, buildID, checkpointDir, content string) *Builder {
+	b := &Builder{
+		buildID: buildID,
+		buildOpts: BuildOptions{
+			File: content,
+		},
+		cliLog:        logger.NewCliLogger(constant.CliLogBufferLen),
+		ctx:           context.Background(),
+		checkpointDir: checkpointDir,
+	}
+	b.checkpoint = buildCheckpoint{BuildID: buildID}
+
+	assert.NilError(t, b.parseFiles())
+	assert.NilError(t, b.newStageBuilders())
+	b.checkpoint.DockerfileDigest = b.dockerfileDigest
+
+	return b
+}
+
+func stageCacheKeys(b *Builder) []string {
+	keys := make([]string, len(b.stageBuilders))
+	for _, stage := range b.stageBuilders {
+		keys[stage.position] = b.stageCacheKey(stage, keys)
+	}
+	return keys
+}
+
+func TestStageCacheKey(t *testing.T) {
+	tmpDir := fs.NewDir(t, t.Name())
+	defer tmpDir.Remove()
+
+	b1 := newCheckpointTestBuilder(t, "build1", tmpDir.Path(), checkpointDockerfile)
+	b2 := newCheckpointTestBuilder(t, "build2", tmpDir.Path(), checkpointDockerfile)
+
+	keys1 := stageCacheKeys(b1)
+	keys2 := stageCacheKeys(b2)
+	assert.Equal(t, len(keys1), 2)
+	// same instructions produce the same keys
+	assert.DeepEqual(t, keys1, keys2)
+
+	// a changed first stage invalidates the second stage as well
+	changed := newCheckpointTestBuilder(t, "build3", tmpDir.Path(),
+		"\nFROM alpine AS base\nRUN touch /tmp/changed\n\nFROM base\nRUN touch /tmp/final\n")
+	keys3 := stageCacheKeys(changed)
+	assert.Assert(t, keys1[0] != keys3[0])
+	assert.Assert(t, keys1[1] != keys3[1])
+
+	// different build-args produce different keys
+	withArgs := newCheckpointTestBuilder(t, "build4", tmpDir.Path(), checkpointDockerfile)
+	withArgs.buildOpts.BuildArgs = map[string]string{"http_proxy": "http://proxy.example.com"}
+	keys4 := stageCacheKeys(withArgs)
+	assert.Assert(t, keys1[0] != keys4[0])
+}
+
+func TestCheckpointSaveLoadRemove(t *testing.T) {
+	tmpDir := fs.NewDir(t, t.Name())
+	defer tmpDir.Remove()
+
+	b1 := newCheckpointTestBuilder(t, "interrupted", tmpDir.Path(), checkpointDockerfile)
+	keys := stageCacheKeys(b1)
+	for _, stage := range b1.stageBuilders {
+		stage.imageID = "image-" + stage.name
+		b1.recordStageCheckpoint(stage, keys[stage.position])
+	}
+
+	// resuming build loads the records of the interrupted one
+	b2 := newCheckpointTestBuilder(t, "resumed", tmpDir.Path(), checkpointDockerfile)
+	b2.resumeBuildID = "interrupted"
+	b2.loadResumeCheckpoint()
+	assert.Equal(t, len(b2.resumeStages), 2)
+	record, ok := b2.resumeStages[keys[0]]
+	assert.Equal(t, ok, true)
+	assert.Equal(t, record.ImageID, "image-"+b1.stageBuilders[0].name)
+
+	// a stage missing from the checkpoint deactivates resuming
+	b2.resumeActive = true
+	_, ok = b2.resumeStage(b2.stageBuilders[0], "no-such-key")
+	assert.Equal(t, ok, false)
+	assert.Equal(t, b2.resumeActive, false)
+	_, ok = b2.resumeStage(b2.stageBuilders[0], keys[0])
+	assert.Equal(t, ok, false)
+
+	// checkpoint of a changed Dockerfile is not taken
+	b3 := newCheckpointTestBuilder(t, "changed", tmpDir.Path(),
+		"\nFROM alpine AS base\nRUN touch /tmp/changed\n\nFROM base\nRUN touch /tmp/final\n")
+	b3.resumeBuildID = "interrupted"
+	b3.loadResumeCheckpoint()
+	assert.Equal(t, len(b3.resumeStages), 0)
+
+	// a finished build removes its own and the resumed checkpoint
+	b2.removeCheckpoints()
+	b2.resumeStages = nil
+	b2.loadResumeCheckpoint()
+	assert.Equal(t, len(b2.resumeStages), 0)
+}
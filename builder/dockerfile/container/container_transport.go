@@ -26,7 +26,6 @@ import (
 	is "github.com/containers/image/v5/storage"
 	"github.com/containers/image/v5/types"
 	"github.com/containers/storage/pkg/archive"
-	"github.com/opencontainers/go-digest"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 
@@ -152,8 +151,9 @@ func (ref *Reference) NewImageSource(ctx context.Context, sc *types.SystemContex
 		dmanifest.Layers = append(dmanifest.Layers, dlayerDescriptor)
 	}
 
-	// 4. build history in the image
+	// 4. build history in the image, normalized for fixed-time builds
 	ref.appendHistory(&dimage)
+	ref.normalizeHistory(&dimage)
 
 	// 5. ensure that not just create a mismatch between non-empty layers in the history and the number of diffIDs
 	cnt := countDockerImageEmptyLayers(dimage)
@@ -188,7 +188,7 @@ func (ref *Reference) newImageSource(path string, dimage docker.Image, dmanifest
 		layerID:      ref.layerID,
 		compression:  ref.compression,
 		config:       config,
-		configDigest: digest.Canonical.FromBytes(config),
+		configDigest: util.DigestAlgorithm().FromBytes(config),
 		manifest:     manifest,
 		manifestType: manifestType,
 		exporting:    ref.exporting,
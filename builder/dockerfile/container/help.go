@@ -18,6 +18,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 
 	"github.com/containers/storage"
 	"github.com/containers/storage/pkg/archive"
@@ -243,3 +244,21 @@ func (ref *Reference) appendHistory(dimage *docker.Image) {
 	dimage.History = append(dimage.History, dnews)
 	appendHistory(dimage, ref.postEmptyLayers)
 }
+
+// hostPathPattern matches absolute paths below the host temporary
+// directories, which differ between build hosts
+var hostPathPattern = regexp.MustCompile(`/(?:var/)?tmp/[^\s"']+`)
+
+// normalizeHistory rewrites the image history for a fixed-time build:
+// every entry gets the fixed build time, host-specific temporary paths
+// in created_by are replaced by a stable marker and unset empty_layer
+// markers are kept false, so two hosts produce identical image IDs
+func (ref *Reference) normalizeHistory(dimage *docker.Image) {
+	if !ref.fixed {
+		return
+	}
+	for i := range dimage.History {
+		dimage.History[i].Created = ref.created
+		dimage.History[i].CreatedBy = hostPathPattern.ReplaceAllString(dimage.History[i].CreatedBy, "<tmp>")
+	}
+}
@@ -163,3 +163,25 @@ func TestPrepareRWLayers(t *testing.T) {
 	_, err := containerRef.getContainerLayers()
 	assert.ErrorContains(t, err, "unable to read layer")
 }
+
+func TestNormalizeHistory(t *testing.T) {
+	created := time.Date(2020, 6, 30, 15, 5, 5, 0, time.UTC)
+	dimage := docker.Image{
+		History: []docker.History{
+			{Created: time.Now(), CreatedBy: "/bin/sh -c cp /tmp/isula-build-context-123/app /app"},
+			{Created: time.Now(), CreatedBy: "/bin/sh -c echo done", EmptyLayer: true},
+		},
+	}
+
+	// without a fixed build time the history stays untouched
+	ref := &Reference{created: created}
+	ref.normalizeHistory(&dimage)
+	assert.Assert(t, !dimage.History[0].Created.Equal(created))
+
+	ref.fixed = true
+	ref.normalizeHistory(&dimage)
+	assert.Assert(t, dimage.History[0].Created.Equal(created))
+	assert.Equal(t, dimage.History[0].CreatedBy, "/bin/sh -c cp <tmp> /app")
+	assert.Assert(t, dimage.History[1].Created.Equal(created))
+	assert.Equal(t, dimage.History[1].EmptyLayer, true)
+}
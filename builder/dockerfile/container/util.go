@@ -21,7 +21,6 @@ import (
 	"time"
 
 	"github.com/containers/storage/pkg/archive"
-	"github.com/opencontainers/go-digest"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -29,6 +28,7 @@ import (
 
 	mimetypes "isula.org/isula-build/image"
 	"isula.org/isula-build/pkg/docker"
+	"isula.org/isula-build/util"
 )
 
 func countDockerImageEmptyLayers(image docker.Image) int {
@@ -71,7 +71,7 @@ func encodeConfigsAndManifests(dimage docker.Image, dmanifest docker.Manifest, m
 	}
 
 	// add the configuration blob to the manifest and encode it
-	dmanifest.Config.Digest = digest.Canonical.FromBytes(dimagebytes)
+	dmanifest.Config.Digest = util.DigestAlgorithm().FromBytes(dimagebytes)
 	dmanifest.Config.Size = int64(len(dimagebytes))
 	dmanifest.Config.MediaType = mimetypes.DockerV2Schema2ConfigMediaType
 	dmanifestbytes, err := json.Marshal(&dmanifest)
@@ -0,0 +1,126 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-05-05
+// Description: estimate the bytes pulled for unresolved FROM images
+
+package dockerfile
+
+import (
+	"context"
+
+	ci "github.com/containers/image/v5/image"
+	"github.com/containers/image/v5/transports/alltransports"
+	"github.com/containers/image/v5/types"
+	"github.com/pkg/errors"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/exporter"
+	"isula.org/isula-build/image"
+	"isula.org/isula-build/util"
+)
+
+// decimalPrefixBase formats the estimated size with powers of 1000
+const decimalPrefixBase = 1000
+
+// estimatePullSize reports the total bytes the build will have to pull
+// for FROM images that are not in the local store, computed from the
+// remote manifests only. The estimation is best effort: any failure is
+// logged and skipped so an offline build is not broken by it.
+func (b *Builder) estimatePullSize(ctx context.Context) {
+	var total int64
+	visited := make(map[string]bool, len(b.stageBuilders))
+	for _, stage := range b.stageBuilders {
+		name := stage.fromImage
+		if stage.fromStageIdx != -1 || name == noBaseImage || visited[name] {
+			continue
+		}
+		visited[name] = true
+
+		// images found locally need no pulling at all
+		if _, _, err := image.FindImage(b.localStore, name); err == nil {
+			continue
+		}
+
+		size, err := b.remotePullSize(ctx, name, stage.buildOpt.systemContext)
+		if err != nil {
+			b.Logger().Debugf("Estimating pull size of %q failed: %v", name, err)
+			continue
+		}
+		b.cliLog.Print("Estimated pull size of image %s: %s\n", name, util.FormatSize(float64(size), decimalPrefixBase))
+		total += size
+	}
+
+	if total > 0 {
+		b.cliLog.Print("Estimated total pull size: %s\n", util.FormatSize(float64(total), decimalPrefixBase))
+	}
+}
+
+// remotePullSize sums the layer sizes of the remote manifest of name,
+// skipping the blobs already present in the local store
+func (b *Builder) remotePullSize(ctx context.Context, name string, sysCtx *types.SystemContext) (int64, error) {
+	candidates, transport, err := image.ResolveName(name, sysCtx, b.localStore)
+	if err != nil {
+		return 0, err
+	}
+	if transport != constant.DockerTransport {
+		return 0, errors.Errorf("the size of image %q with transport %q can not be estimated", name, transport)
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		size, sErr := b.candidatePullSize(ctx, candidate, sysCtx)
+		if sErr != nil {
+			lastErr = sErr
+			continue
+		}
+		return size, nil
+	}
+
+	return 0, lastErr
+}
+
+// candidatePullSize fetches the manifest of one fully qualified
+// candidate and sums the sizes of the layers missing locally
+func (b *Builder) candidatePullSize(ctx context.Context, candidate string, sysCtx *types.SystemContext) (_ int64, err error) {
+	ref, err := alltransports.ParseImageName(exporter.FormatTransport(constant.DockerTransport, candidate))
+	if err != nil {
+		return 0, err
+	}
+	src, err := ref.NewImageSource(ctx, sysCtx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if cErr := src.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+	}()
+
+	// a manifest list resolves to the instance of the build platform
+	img, err := ci.FromUnparsedImage(ctx, sysCtx, ci.UnparsedInstance(src, nil))
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	for _, layer := range img.LayerInfos() {
+		if layer.Size < 0 {
+			continue
+		}
+		// blobs already present locally will not be transferred
+		if layers, lErr := b.localStore.LayersByCompressedDigest(layer.Digest); lErr == nil && len(layers) > 0 {
+			continue
+		}
+		size += layer.Size
+	}
+
+	return size, nil
+}
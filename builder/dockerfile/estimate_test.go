@@ -0,0 +1,49 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-05-05
+// Description: estimate pull size test
+
+package dockerfile
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/pkg/logger"
+	"isula.org/isula-build/store"
+)
+
+func TestEstimatePullSizeSkipsResolvedStages(t *testing.T) {
+	localStore, err := store.GetStore()
+	assert.NilError(t, err)
+
+	b := &Builder{
+		ctx:        context.Background(),
+		cliLog:     logger.NewCliLogger(constant.CliLogBufferLen),
+		localStore: &localStore,
+	}
+	// scratch and previous-stage FROMs need no pulling, so the
+	// estimation must finish without touching any registry
+	b.stageBuilders = []*stageBuilder{
+		{fromImage: noBaseImage, fromStageIdx: -1},
+		{fromImage: "builder-stage-image", fromStageIdx: 0},
+	}
+
+	b.estimatePullSize(context.Background())
+
+	b.cliLog.CloseContent()
+	for content := range b.cliLog.GetContent() {
+		assert.Equal(t, content, "")
+	}
+}
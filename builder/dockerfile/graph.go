@@ -0,0 +1,111 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-25
+// Description: build graph rendering in json and dot format
+
+package dockerfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// GraphFormatJSON renders the build graph as indented json
+	GraphFormatJSON = "json"
+	// GraphFormatDot renders the build graph in graphviz dot format
+	GraphFormatDot = "dot"
+)
+
+// graphInstruction is one Dockerfile instruction of a stage
+type graphInstruction struct {
+	Command string `json:"command"`
+	Begin   int    `json:"begin"`
+	End     int    `json:"end"`
+}
+
+// graphStage is one resolved stage with its cache and timing annotations
+type graphStage struct {
+	Name         string             `json:"name"`
+	Position     int                `json:"position"`
+	From         string             `json:"from"`
+	DependsOn    []int              `json:"dependsOn,omitempty"`
+	CacheHit     bool               `json:"cacheHit"`
+	Duration     string             `json:"duration"`
+	Instructions []graphInstruction `json:"instructions"`
+}
+
+// buildGraph is the resolved stage and instruction DAG of one build
+type buildGraph struct {
+	BuildID string       `json:"buildID"`
+	Stages  []graphStage `json:"stages"`
+}
+
+// recordGraphStage appends the stage to the build graph
+func (b *Builder) recordGraphStage(stage *stageBuilder, cacheHit bool, duration time.Duration) {
+	node := graphStage{
+		Name:         stage.name,
+		Position:     stage.position,
+		From:         stage.fromImage,
+		CacheHit:     cacheHit,
+		Duration:     duration.Round(time.Millisecond).String(),
+		Instructions: make([]graphInstruction, 0, len(stage.rawStage.Lines)),
+	}
+	if stage.fromStageIdx != -1 {
+		node.DependsOn = append(node.DependsOn, stage.fromStageIdx)
+	}
+	for _, line := range stage.rawStage.Lines {
+		node.Instructions = append(node.Instructions, graphInstruction{
+			Command: line.Command,
+			Begin:   line.Begin,
+			End:     line.End,
+		})
+	}
+	b.graph.Stages = append(b.graph.Stages, node)
+}
+
+// Graph renders the build graph in the requested format
+func (b *Builder) Graph(format string) ([]byte, error) {
+	switch format {
+	case GraphFormatJSON:
+		return json.MarshalIndent(&b.graph, "", "  ")
+	case GraphFormatDot:
+		return b.graph.dot(), nil
+	default:
+		return nil, errors.Errorf("the build graph format %q is not supported, choose %q or %q", format, GraphFormatJSON, GraphFormatDot)
+	}
+}
+
+// dot renders the graph in graphviz dot format, stages are the nodes and
+// the FROM references between stages are the edges
+func (g *buildGraph) dot() []byte {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("digraph %q {\n", g.BuildID))
+	sb.WriteString("    rankdir=LR;\n")
+	for _, stage := range g.Stages {
+		label := fmt.Sprintf("%d: %s\\n%d instructions\\n%s", stage.Position, stage.Name, len(stage.Instructions), stage.Duration)
+		attrs := fmt.Sprintf(`label="%s"`, label)
+		if stage.CacheHit {
+			attrs += " style=filled fillcolor=lightgrey"
+		}
+		sb.WriteString(fmt.Sprintf("    stage%d [%s];\n", stage.Position, attrs))
+		for _, dep := range stage.DependsOn {
+			sb.WriteString(fmt.Sprintf("    stage%d -> stage%d;\n", dep, stage.Position))
+		}
+	}
+	sb.WriteString("}\n")
+
+	return []byte(sb.String())
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-25
+// Description: build graph rendering related tests
+
+package dockerfile
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBuildGraphRender(t *testing.T) {
+	b := &Builder{
+		graph: buildGraph{
+			BuildID: "buildid1",
+			Stages: []graphStage{
+				{
+					Name:     "base",
+					Position: 0,
+					From:     "alpine:latest",
+					CacheHit: true,
+					Duration: "0s",
+					Instructions: []graphInstruction{
+						{Command: "FROM", Begin: 1, End: 1},
+						{Command: "RUN", Begin: 2, End: 2},
+					},
+				},
+				{
+					Name:      "app",
+					Position:  1,
+					From:      "base",
+					DependsOn: []int{0},
+					Duration:  "2.5s",
+					Instructions: []graphInstruction{
+						{Command: "FROM", Begin: 4, End: 4},
+						{Command: "COPY", Begin: 5, End: 5},
+					},
+				},
+			},
+		},
+	}
+
+	jsonOut, err := b.Graph(GraphFormatJSON)
+	assert.NilError(t, err)
+	var decoded buildGraph
+	assert.NilError(t, json.Unmarshal(jsonOut, &decoded))
+	assert.Equal(t, decoded.BuildID, "buildid1")
+	assert.Equal(t, len(decoded.Stages), 2)
+	assert.Equal(t, decoded.Stages[0].CacheHit, true)
+	assert.Equal(t, decoded.Stages[1].DependsOn[0], 0)
+
+	dotOut, err := b.Graph(GraphFormatDot)
+	assert.NilError(t, err)
+	dot := string(dotOut)
+	assert.Assert(t, strings.Contains(dot, `digraph "buildid1" {`))
+	assert.Assert(t, strings.Contains(dot, "stage0 -> stage1;"))
+	assert.Assert(t, strings.Contains(dot, "fillcolor=lightgrey"))
+
+	_, err = b.Graph("yaml")
+	assert.ErrorContains(t, err, `the build graph format "yaml" is not supported`)
+}
@@ -0,0 +1,155 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-01
+// Description: RUN cache mount related functions
+
+package dockerfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/util"
+)
+
+// cacheMountType is the only mount type supported by RUN --mount
+const cacheMountType = "cache"
+
+// runMount is one parsed RUN --mount flag
+type runMount struct {
+	// target is the mount point inside the container
+	target string
+	// id names the host directory backing the mount, mounts with the
+	// same id share their content across builds
+	id string
+	// readonly mounts the cache directory read-only
+	readonly bool
+}
+
+// parseRunMounts parses the value of the RUN --mount flag, several
+// mounts are separated by a space
+func parseRunMounts(flagValue string) ([]*runMount, error) {
+	var mounts []*runMount
+	targets := make(map[string]bool)
+	for _, spec := range strings.Fields(flagValue) {
+		m, err := parseRunMount(spec)
+		if err != nil {
+			return nil, err
+		}
+		if targets[m.target] {
+			return nil, errors.Errorf("duplicated cache mount target %q", m.target)
+		}
+		targets[m.target] = true
+		mounts = append(mounts, m)
+	}
+
+	return mounts, nil
+}
+
+// parseRunMount parses one mount spec of the form
+// type=cache,target=<path>[,id=<name>][,readonly]
+func parseRunMount(spec string) (*runMount, error) {
+	m := &runMount{}
+	var mountType string
+	for _, option := range strings.Split(spec, ",") {
+		kv := strings.SplitN(option, "=", 2)
+		switch kv[0] {
+		case "type":
+			if len(kv) != 2 {
+				return nil, errors.Errorf("mount option %q requires a value in %q", kv[0], spec)
+			}
+			mountType = kv[1]
+		case "target", "dst", "destination":
+			if len(kv) != 2 {
+				return nil, errors.Errorf("mount option %q requires a value in %q", kv[0], spec)
+			}
+			m.target = kv[1]
+		case "id":
+			if len(kv) != 2 {
+				return nil, errors.Errorf("mount option %q requires a value in %q", kv[0], spec)
+			}
+			m.id = kv[1]
+		case "readonly", "ro":
+			if len(kv) == 2 && kv[1] != "true" {
+				return nil, errors.Errorf("invalid value %q for mount option %q in %q", kv[1], kv[0], spec)
+			}
+			m.readonly = true
+		default:
+			return nil, errors.Errorf("unknown mount option %q in %q", kv[0], spec)
+		}
+	}
+
+	if mountType != cacheMountType {
+		return nil, errors.Errorf("only mounts of type %q are supported, got %q in %q", cacheMountType, mountType, spec)
+	}
+	if !filepath.IsAbs(m.target) {
+		return nil, errors.Errorf("cache mount target must be an absolute path, got %q in %q", m.target, spec)
+	}
+	if m.id == "" {
+		m.id = strings.Trim(strings.ReplaceAll(m.target, "/", "-"), "-")
+	}
+	if strings.Contains(m.id, "/") {
+		return nil, errors.Errorf("cache mount id %q must not contain %q", m.id, "/")
+	}
+
+	return m, nil
+}
+
+// setupCacheMounts creates the host directories backing the cache
+// mounts of the current line and returns the corresponding bind mounts
+func (c *cmdBuilder) setupCacheMounts() ([]specs.Mount, error) {
+	flagValue, ok := c.line.Flags["mount"]
+	if !ok {
+		return nil, nil
+	}
+
+	runMounts, err := parseRunMounts(flagValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.stage.builder.cacheDir == "" {
+		return nil, errors.New("cache mounts are only available when building through the daemon")
+	}
+	mountsDir, err := securejoin.SecureJoin(c.stage.builder.cacheDir, util.CacheMountsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var mounts []specs.Mount
+	for _, rm := range runMounts {
+		dir, sErr := securejoin.SecureJoin(mountsDir, rm.id)
+		if sErr != nil {
+			return nil, sErr
+		}
+		if mErr := os.MkdirAll(dir, constant.DefaultRootDirMode); mErr != nil {
+			return nil, errors.Wrapf(mErr, "create cache mount dir %q failed", dir)
+		}
+		options := []string{"rbind", "rw"}
+		if rm.readonly {
+			options = []string{"rbind", "ro"}
+		}
+		mounts = append(mounts, specs.Mount{
+			Source:      dir,
+			Destination: rm.target,
+			Type:        "bind",
+			Options:     options,
+		})
+	}
+
+	return mounts, nil
+}
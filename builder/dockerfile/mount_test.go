@@ -0,0 +1,95 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-01
+// Description: RUN cache mount related tests
+
+package dockerfile
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseRunMounts(t *testing.T) {
+	type testcase struct {
+		name      string
+		flagValue string
+		mounts    []*runMount
+		isErr     bool
+	}
+	var testcases = []testcase{
+		{
+			name:      "single cache mount",
+			flagValue: "type=cache,target=/var/cache/apt",
+			mounts: []*runMount{
+				{target: "/var/cache/apt", id: "var-cache-apt"},
+			},
+		},
+		{
+			name:      "explicit id and readonly",
+			flagValue: "type=cache,target=/go/pkg/mod,id=gomod,readonly",
+			mounts: []*runMount{
+				{target: "/go/pkg/mod", id: "gomod", readonly: true},
+			},
+		},
+		{
+			name:      "several mounts",
+			flagValue: "type=cache,target=/go/pkg/mod type=cache,dst=/root/.cache,ro",
+			mounts: []*runMount{
+				{target: "/go/pkg/mod", id: "go-pkg-mod"},
+				{target: "/root/.cache", id: "root-.cache", readonly: true},
+			},
+		},
+		{
+			name:      "unsupported mount type",
+			flagValue: "type=bind,target=/data",
+			isErr:     true,
+		},
+		{
+			name:      "missing type",
+			flagValue: "target=/data",
+			isErr:     true,
+		},
+		{
+			name:      "relative target",
+			flagValue: "type=cache,target=data",
+			isErr:     true,
+		},
+		{
+			name:      "unknown option",
+			flagValue: "type=cache,target=/data,uid=0",
+			isErr:     true,
+		},
+		{
+			name:      "duplicated target",
+			flagValue: "type=cache,target=/data type=cache,target=/data,id=other",
+			isErr:     true,
+		},
+		{
+			name:      "id with path separator",
+			flagValue: "type=cache,target=/data,id=../escape",
+			isErr:     true,
+		},
+	}
+
+	for _, tc := range testcases {
+		mounts, err := parseRunMounts(tc.flagValue)
+		assert.Equal(t, err != nil, tc.isErr, "Failed at [%s], err: %v", tc.name, err)
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, len(mounts), len(tc.mounts), "Failed at [%s]", tc.name)
+		for i, want := range tc.mounts {
+			assert.Equal(t, *mounts[i], *want, "Failed at [%s] mount %d", tc.name, i)
+		}
+	}
+}
@@ -239,7 +239,49 @@ func parseMaintainer(line *parser.Line) error {
 }
 
 func parseRun(line *parser.Line) error {
-	return parseCmdEntrypointRun(line)
+	remainder, err := extractRunMounts(line)
+	if err != nil {
+		return err
+	}
+
+	// the mount flags stay visible in Raw for logs and cache keys, but
+	// the executed command is built from the remainder only
+	savedRaw := line.Raw
+	line.Raw = remainder
+	err = parseCmdEntrypointRun(line)
+	line.Raw = savedRaw
+
+	return err
+}
+
+// extractRunMounts collects the leading --mount flags of a RUN line into
+// line.Flags["mount"] and returns the line content without them. Several
+// mounts are separated by a space in the flag value
+func extractRunMounts(line *parser.Line) (string, error) {
+	var mounts []string
+	rest := line.Raw
+	for {
+		trimmed := strings.TrimLeft(rest, " \t")
+		if !strings.HasPrefix(trimmed, "--mount=") {
+			if len(mounts) == 0 {
+				// keep the raw content untouched when there is no mount flag
+				return line.Raw, nil
+			}
+			line.Flags["mount"] = strings.Join(mounts, " ")
+			return trimmed, nil
+		}
+
+		token := trimmed
+		rest = ""
+		if idx := strings.IndexAny(trimmed, " \t"); idx != -1 {
+			token, rest = trimmed[:idx], trimmed[idx:]
+		}
+		value := strings.TrimPrefix(token, "--mount=")
+		if value == "" {
+			return "", errors.Errorf("empty --mount flag in line: %s %s", line.Command, line.Raw)
+		}
+		mounts = append(mounts, value)
+	}
 }
 
 func parseShell(line *parser.Line) error {
@@ -612,6 +612,21 @@ func TestParseRun(t *testing.T) {
 			str:   `["/bin/sh", "-c", "sleep", 1]`,
 			isErr: true,
 		},
+		{
+			name:   "ParseRun test 8",
+			str:    `--mount=type=cache,target=/var/cache/apt apt-get update`,
+			expect: 1,
+		},
+		{
+			name:   "ParseRun test 9",
+			str:    `--mount=type=cache,target=/root/.cache/go-build ["go", "build"]`,
+			expect: 2,
+		},
+		{
+			name:  "ParseRun test 10",
+			str:   `--mount= echo ok`,
+			isErr: true,
+		},
 	}
 	for _, tc := range testcases {
 		line := &parser.Line{
@@ -627,6 +642,32 @@ func TestParseRun(t *testing.T) {
 	}
 }
 
+func TestExtractRunMounts(t *testing.T) {
+	line := &parser.Line{
+		Command: Run,
+		Raw:     `--mount=type=cache,target=/go/pkg/mod --mount=type=cache,target=/root/.cache go build ./...`,
+		Flags:   make(map[string]string),
+	}
+	remainder, err := extractRunMounts(line)
+	assert.NilError(t, err)
+	assert.Equal(t, remainder, "go build ./...")
+	assert.Equal(t, line.Flags["mount"], "type=cache,target=/go/pkg/mod type=cache,target=/root/.cache")
+
+	// the raw content keeps the flags for logging and cache keys
+	assert.Equal(t, line.Raw, `--mount=type=cache,target=/go/pkg/mod --mount=type=cache,target=/root/.cache go build ./...`)
+
+	noFlags := &parser.Line{
+		Command: Run,
+		Raw:     `echo "a  b"`,
+		Flags:   make(map[string]string),
+	}
+	remainder, err = extractRunMounts(noFlags)
+	assert.NilError(t, err)
+	assert.Equal(t, remainder, `echo "a  b"`)
+	_, ok := noFlags.Flags["mount"]
+	assert.Equal(t, ok, false)
+}
+
 func TestParseShell(t *testing.T) {
 	type testcase struct {
 		name   string
@@ -0,0 +1,26 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-15
+// Description: go-fuzz entry point for the Dockerfile parser
+
+//go:build gofuzz
+// +build gofuzz
+
+package dockerfile
+
+// Fuzz is the go-fuzz entry point, run it with
+// go-fuzz-build -tags gofuzz isula.org/isula-build/builder/dockerfile/parser
+func Fuzz(data []byte) int {
+	if _, err := ParseContent(data, false); err != nil {
+		return 0
+	}
+	return 1
+}
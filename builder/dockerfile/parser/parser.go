@@ -17,6 +17,7 @@ import (
 	"bufio"
 	"bytes"
 	"io"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -37,6 +38,16 @@ var (
 	regComment  = regexp.MustCompile(`^(\s*|)#.*$`)
 )
 
+const (
+	// maxParseSize limits the total size of a Dockerfile, the daemon
+	// parses untrusted input
+	maxParseSize = constant.MaxFileSize
+	// maxLineLength limits one physical line of a Dockerfile
+	maxLineLength = 64 * 1024
+	// maxLines limits the physical lines of a Dockerfile
+	maxLines = 8192
+)
+
 type rowLine struct {
 	lineNum int
 	content string
@@ -45,20 +56,32 @@ type rowLine struct {
 type dockerfile struct {
 }
 
+// ParseContent parses the given Dockerfile content and returns a
+// PlayBook. It touches nothing outside its input, which makes it
+// usable as a fuzzing target
+func ParseContent(content []byte, onbuild bool) (*parser.PlayBook, error) {
+	df := &dockerfile{}
+	return df.Parse(bytes.NewReader(content), onbuild)
+}
+
 // Parse the given Dockerfile and return a PlayBook
 func (df *dockerfile) Parse(r io.Reader, onbuild bool) (*parser.PlayBook, error) {
-	var (
-		buf bytes.Buffer
-		d   *directive
-		err error
-	)
-	tee := io.TeeReader(r, &buf)
+	content, err := ioutil.ReadAll(io.LimitReader(r, maxParseSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "read Dockerfile content failed")
+	}
+	if len(content) > maxParseSize {
+		return nil, errors.Errorf("Dockerfile is bigger than the %d bytes limit", maxParseSize)
+	}
 
 	// 1. scan each line, trim comment and space line and load to a rowLine
-	rowLines := preProcess(tee)
+	rowLines, err := preProcess(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
 
 	// 2. init directive and it need to behand the full scan after preprocess
-	d, err = newDirective(bytes.NewReader(buf.Bytes()))
+	d, err := newDirective(bytes.NewReader(content))
 	if err != nil {
 		return nil, err
 	}
@@ -108,9 +131,10 @@ func newRowLine(num int, content string) *rowLine {
 }
 
 // preprocess the Dockerfile and get the effective physical line
-func preProcess(r io.Reader) []*rowLine {
+func preProcess(r io.Reader) ([]*rowLine, error) {
 	rowLines := make([]*rowLine, 0)
 	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, bufio.MaxScanTokenSize), maxLineLength)
 	lineNum := 1
 	for scanner.Scan() {
 		bytes := regComment.ReplaceAll(scanner.Bytes(), []byte{})
@@ -120,8 +144,17 @@ func preProcess(r io.Reader) []*rowLine {
 		}
 		lineNum++
 	}
+	if err := scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			return nil, errors.Errorf("line %d is longer than the %d bytes limit", lineNum, maxLineLength)
+		}
+		return nil, errors.Wrap(err, "scan Dockerfile content failed")
+	}
+	if lineNum-1 > maxLines {
+		return nil, errors.Errorf("Dockerfile has more than the %d lines limit", maxLines)
+	}
 
-	return rowLines
+	return rowLines, nil
 }
 
 // trim continue char and format it into parser.Line
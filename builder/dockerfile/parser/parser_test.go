@@ -61,7 +61,8 @@ func TestPreProcess(t *testing.T) {
 			r, err := os.Open(file)
 			assert.NilError(t, err)
 			defer r.Close()
-			lines := preProcess(r)
+			lines, err := preProcess(r)
+			assert.NilError(t, err)
 			assert.Equal(t, len(lines), tc.expect)
 		})
 	}
@@ -124,7 +125,8 @@ func TestFormat(t *testing.T) {
 			defer r.Close()
 			var buf bytes.Buffer
 			tee := io.TeeReader(r, &buf)
-			rows := preProcess(tee)
+			rows, err := preProcess(tee)
+			assert.NilError(t, err)
 			d, err := newDirective(bytes.NewReader(buf.Bytes()))
 			assert.NilError(t, err)
 			lines, err := format(rows, d)
@@ -159,7 +161,8 @@ func TestFormatWithSpacesAfterEscapeToken(t *testing.T) {
 			defer r.Close()
 			var buf bytes.Buffer
 			tee := io.TeeReader(r, &buf)
-			rows := preProcess(tee)
+			rows, err := preProcess(tee)
+			assert.NilError(t, err)
 			d, err := newDirective(bytes.NewReader(buf.Bytes()))
 			assert.NilError(t, err)
 			lines, err := format(rows, d)
@@ -533,3 +536,20 @@ func TestParserParseOnBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestParseContent(t *testing.T) {
+	playbook, err := ParseContent([]byte("FROM busybox\nRUN echo ok\n"), false)
+	assert.NilError(t, err)
+	assert.Equal(t, len(playbook.Pages), 1)
+
+	_, err = ParseContent(bytes.Repeat([]byte("a"), maxParseSize+1), false)
+	assert.ErrorContains(t, err, "bytes limit")
+
+	longLine := append([]byte("RUN echo "), bytes.Repeat([]byte("a"), maxLineLength)...)
+	_, err = ParseContent(append([]byte("FROM busybox\n"), longLine...), false)
+	assert.ErrorContains(t, err, "longer than")
+
+	manyLines := append([]byte("FROM busybox\n"), bytes.Repeat([]byte("RUN true\n"), maxLines)...)
+	_, err = ParseContent(manyLines, false)
+	assert.ErrorContains(t, err, "lines limit")
+}
@@ -39,6 +39,9 @@ const (
 	bashModifier1 = ":-"
 	// form ${variable:+word}: if variable defined, return word; otherwise return ""
 	bashModifier2 = ":+"
+	// maxResolvedLen limits the length of a resolved parameter, the
+	// substituted args may blow up the input far beyond the line limits
+	maxResolvedLen = 64 * 1024
 )
 
 type resolver struct {
@@ -98,6 +101,9 @@ func ResolveParam(s string, strict bool, resolveArg func(string) string) (string
 			return "", err
 		}
 	}
+	if len(r.resolved) > maxResolvedLen {
+		return "", errors.Errorf("resolved parameter is longer than the %d bytes limit", maxResolvedLen)
+	}
 	return r.resolved, nil
 }
 
@@ -507,3 +507,12 @@ func TestResolveParam(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveParamTooLong(t *testing.T) {
+	huge := func(string) string {
+		return string(make([]byte, maxResolvedLen))
+	}
+	if _, err := ResolveParam("$a$b", false, huge); err == nil {
+		t.Errorf("ResolveParam() should fail when the resolved parameter exceeds %d bytes", maxResolvedLen)
+	}
+}
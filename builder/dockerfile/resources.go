@@ -0,0 +1,83 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: RUN container resource limit related functions
+
+package dockerfile
+
+import (
+	"github.com/opencontainers/runtime-tools/generate"
+	"github.com/pkg/errors"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+const (
+	// minMemoryLimit is the smallest workable RUN memory limit, the
+	// same floor the container engines enforce
+	minMemoryLimit = 4 * 1024 * 1024
+	// defaultCPUPeriod is the scheduler period backing the --cpus and
+	// --cpu-quota limits, in microseconds
+	defaultCPUPeriod = 100000
+)
+
+// checkRunResources validates the resource limits applied to the RUN
+// containers of a build
+func checkRunResources(opts *BuildOptions) error {
+	if opts.Memory < 0 || (opts.Memory > 0 && opts.Memory < minMemoryLimit) {
+		return errors.Errorf("invalid memory limit %d, at least %d bytes are needed", opts.Memory, minMemoryLimit)
+	}
+	if opts.CPUQuota < 0 {
+		return errors.Errorf("invalid cpu quota %d, it must not be negative", opts.CPUQuota)
+	}
+	if opts.CPUs < 0 {
+		return errors.Errorf("invalid cpus %v, it must not be negative", opts.CPUs)
+	}
+	if opts.CPUQuota != 0 && opts.CPUs != 0 {
+		return errors.New("conflict flags between --cpus and --cpu-quota")
+	}
+	if opts.PidsLimit < 0 {
+		return errors.Errorf("invalid pids limit %d, it must not be negative", opts.PidsLimit)
+	}
+
+	return nil
+}
+
+// applyRunResources turns the limits of the build into the cgroup
+// resources of one RUN container spec
+func applyRunResources(g generate.Generator, opts *BuildOptions) {
+	if opts.Memory > 0 {
+		g.SetLinuxResourcesMemoryLimit(opts.Memory)
+	}
+	if opts.CPUs > 0 {
+		g.SetLinuxResourcesCPUPeriod(defaultCPUPeriod)
+		g.SetLinuxResourcesCPUQuota(int64(opts.CPUs * defaultCPUPeriod))
+	}
+	if opts.CPUQuota > 0 {
+		g.SetLinuxResourcesCPUPeriod(defaultCPUPeriod)
+		g.SetLinuxResourcesCPUQuota(opts.CPUQuota)
+	}
+	if opts.PidsLimit > 0 {
+		g.SetLinuxResourcesPidsLimit(opts.PidsLimit)
+	}
+}
+
+// Resources reports the resource limits the RUN containers of the
+// build run with, shown by the info request
+func (b *Builder) Resources() *pb.BuildResources {
+	return &pb.BuildResources{
+		BuildID:   b.buildID,
+		Memory:    b.buildOpts.Memory,
+		CpuQuota:  b.buildOpts.CPUQuota,
+		Cpus:      b.buildOpts.CPUs,
+		PidsLimit: b.buildOpts.PidsLimit,
+	}
+}
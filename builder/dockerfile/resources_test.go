@@ -0,0 +1,94 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: RUN container resource limit related functions tests
+
+package dockerfile
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-tools/generate"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckRunResources(t *testing.T) {
+	testcases := []struct {
+		name      string
+		opts      BuildOptions
+		wantErr   bool
+		errString string
+	}{
+		{
+			name: "TC1 no limits",
+		},
+		{
+			name: "TC2 valid limits",
+			opts: BuildOptions{Memory: 512 * 1024 * 1024, CPUs: 1.5, PidsLimit: 100},
+		},
+		{
+			name:      "TC3 memory below the floor",
+			opts:      BuildOptions{Memory: 1024},
+			wantErr:   true,
+			errString: "invalid memory limit",
+		},
+		{
+			name:      "TC4 negative cpu quota",
+			opts:      BuildOptions{CPUQuota: -1},
+			wantErr:   true,
+			errString: "invalid cpu quota",
+		},
+		{
+			name:      "TC5 cpus conflicts with cpu quota",
+			opts:      BuildOptions{CPUQuota: 50000, CPUs: 2},
+			wantErr:   true,
+			errString: "conflict flags",
+		},
+		{
+			name:      "TC6 negative pids limit",
+			opts:      BuildOptions{PidsLimit: -1},
+			wantErr:   true,
+			errString: "invalid pids limit",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkRunResources(&tc.opts)
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestApplyRunResources(t *testing.T) {
+	g, err := generate.New("linux")
+	assert.NilError(t, err)
+
+	opts := BuildOptions{Memory: 512 * 1024 * 1024, CPUs: 2, PidsLimit: 100}
+	applyRunResources(g, &opts)
+
+	resources := g.Config.Linux.Resources
+	assert.Equal(t, *resources.Memory.Limit, opts.Memory)
+	assert.Equal(t, *resources.CPU.Period, uint64(defaultCPUPeriod))
+	assert.Equal(t, *resources.CPU.Quota, int64(2*defaultCPUPeriod))
+	assert.Equal(t, resources.Pids.Limit, opts.PidsLimit)
+
+	// no limits leave the spec resources untouched
+	g2, err := generate.New("linux")
+	assert.NilError(t, err)
+	applyRunResources(g2, &BuildOptions{})
+	resources = g2.Config.Linux.Resources
+	assert.Assert(t, resources == nil || resources.Memory == nil)
+}
@@ -57,6 +57,14 @@ func (c *cmdBuilder) Run(command []string) error {
 		return err
 	}
 
+	// setup cache mounts requested with RUN --mount, they take
+	// precedence over the default spec mounts on the same destination
+	cacheMounts, err := c.setupCacheMounts()
+	if err != nil {
+		return err
+	}
+	spec.Mounts = append(cacheMounts, spec.Mounts...)
+
 	// setup all mounts
 	setupMounts(spec, bindFiles)
 
@@ -148,6 +156,24 @@ func (c *cmdBuilder) setupRuntimeSpec(command []string) (*specs.Spec, error) {
 		}
 	}
 
+	// the resource limits of the build bound the cgroup the runtime
+	// creates for the RUN container
+	applyRunResources(g, &c.stage.builder.buildOpts)
+
+	// a user namespace build runs the command with the same id maps the
+	// RW layer of the stage was created with
+	if mappings := c.stage.builder.usernsMappings; mappings != nil {
+		if aerr := g.AddOrReplaceLinuxNamespace(string(specs.UserNamespace), ""); aerr != nil {
+			return nil, errors.Wrap(aerr, "runner: add user namespace failed")
+		}
+		for _, m := range mappings.UIDs() {
+			g.AddLinuxUIDMapping(uint32(m.HostID), uint32(m.ContainerID), uint32(m.Size))
+		}
+		for _, m := range mappings.GIDs() {
+			g.AddLinuxGIDMapping(uint32(m.HostID), uint32(m.ContainerID), uint32(m.Size))
+		}
+	}
+
 	return g.Config, nil
 }
 
@@ -227,6 +253,16 @@ func setupMounts(spec *specs.Spec, bindFiles map[string]string) {
 		Type:        "cgroup",
 		Options:     []string{"nosuid", "noexec", "nodev", "relatime", "ro"},
 	}}
+	if util.IsRootless() {
+		// a rootless runtime may not mount a new cgroup filesystem,
+		// bind the host view read-only instead
+		sysfsMounts = []specs.Mount{{
+			Source:      "/sys/fs/cgroup",
+			Destination: "/sys/fs/cgroup",
+			Type:        "bind",
+			Options:     []string{"rbind", "nosuid", "noexec", "nodev", "ro"},
+		}}
+	}
 
 	// setup bind files mounts
 	var bindFilesMounts []specs.Mount
@@ -24,6 +24,7 @@ import (
 	"github.com/containers/storage"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 
 	dockerfile "isula.org/isula-build/builder/dockerfile/parser"
 	"isula.org/isula-build/image"
@@ -203,9 +204,22 @@ func (s *stageBuilder) stageBuild(ctx context.Context) (string, error) {
 	s.builder.Logger().Debugf("Created mountpoint %s for stage %s", s.mountpoint, s.name)
 
 	// 2. Loop building for commands
-	for _, cmd := range s.commands {
-		if err = cmd.cmdExecutor(); err != nil {
-			return "", errors.Wrapf(err, "handle command %s failed", cmd.line.Command)
+	for i := 0; i < len(s.commands); i++ {
+		// the client may have canceled the build, stop before the next command
+		if err = ctx.Err(); err != nil {
+			return "", errors.Wrap(err, "context finished")
+		}
+		if s.builder.buildOpts.ParallelCopy {
+			if batch := s.parallelCopyBatch(i); len(batch) > 1 {
+				if err = s.runParallelCopies(batch); err != nil {
+					return "", err
+				}
+				i += len(batch) - 1
+				continue
+			}
+		}
+		if err = s.commands[i].cmdExecutor(); err != nil {
+			return "", errors.Wrapf(err, "handle command %s failed", s.commands[i].line.Command)
 		}
 	}
 
@@ -223,6 +237,86 @@ func (s *stageBuilder) stageBuild(ctx context.Context) (string, error) {
 	return s.imageID, nil
 }
 
+// parallelCopyBatch returns the consecutive ADD/COPY commands starting
+// at the given position that are safe to run concurrently: every
+// argument is static and no destination overlaps another one
+func (s *stageBuilder) parallelCopyBatch(start int) []*cmdBuilder {
+	var (
+		batch []*cmdBuilder
+		dests []string
+	)
+	for i := start; i < len(s.commands); i++ {
+		cmd := s.commands[i]
+		if cmd.line.Command != dockerfile.Add && cmd.line.Command != dockerfile.Copy {
+			break
+		}
+		args := make([]string, 0, len(cmd.line.Cells))
+		static := true
+		for _, cell := range cmd.line.Cells {
+			// an argument with a variable is only known after word
+			// expansion, treat the command as a batch boundary
+			if strings.Contains(cell.Value, "$") {
+				static = false
+				break
+			}
+			args = append(args, cell.Value)
+		}
+		if !static || len(args) < 2 {
+			break
+		}
+		dest := args[len(args)-1]
+		if destsOverlap(dests, dest) {
+			break
+		}
+		dests = append(dests, dest)
+		batch = append(batch, cmd)
+	}
+
+	return batch
+}
+
+// destsOverlap reports whether dest equals or nests with any of the
+// already collected destinations, destinations are compared before
+// they are resolved, so mixing relative and absolute ones counts as
+// overlapping to stay on the safe side
+func destsOverlap(dests []string, dest string) bool {
+	for _, existing := range dests {
+		if strings.HasPrefix(existing, "/") != strings.HasPrefix(dest, "/") {
+			return true
+		}
+		if nestedPath(existing, dest) || nestedPath(dest, existing) {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedPath reports whether sub is the parent path itself or lives
+// under it
+func nestedPath(parent, sub string) bool {
+	parent = strings.TrimSuffix(parent, "/")
+	return sub == parent || strings.HasPrefix(sub, parent+"/")
+}
+
+// runParallelCopies executes a batch of independent COPY/ADD commands
+// concurrently, their file trees end up in the same committed layer
+// exactly as if they had run one by one
+func (s *stageBuilder) runParallelCopies(batch []*cmdBuilder) error {
+	s.builder.Logger().Infof("Executing %d COPY/ADD commands concurrently", len(batch))
+	eg := errgroup.Group{}
+	for _, cmd := range batch {
+		cmd := cmd
+		eg.Go(func() error {
+			if err := cmd.cmdExecutor(); err != nil {
+				return errors.Wrapf(err, "handle command %s failed", cmd.line.Command)
+			}
+			return nil
+		})
+	}
+
+	return eg.Wait()
+}
+
 func prepareImage(opt *image.PrepareImageOptions) (*image.Describe, error) {
 	if opt.FromImage == "" {
 		return nil, errors.New("get empty from image")
@@ -245,7 +339,7 @@ func prepareImage(opt *image.PrepareImageOptions) (*image.Describe, error) {
 		topLayID = si.TopLayer
 	}
 
-	layer, err := image.GetRWLayerByImageID(imgID, opt.Store)
+	layer, err := image.GetRWLayerByImageID(opt.Ctx, imgID, opt.Store, opt.ContainerName, opt.IDMappings, opt.StorageOpt)
 	if err != nil {
 		return nil, err
 	}
@@ -276,6 +370,10 @@ func (s *stageBuilder) prepare(ctx context.Context) error {
 		SystemContext: s.buildOpt.systemContext,
 		Store:         s.localStore,
 		Reporter:      s.builder.cliLog,
+		ContainerName: image.ContainerNameOptions{BuildID: s.builder.buildID, Stage: s.name},
+		PolicyStrict:  s.builder.pullPolicyStrict,
+		IDMappings:    s.builder.usernsMappings,
+		StorageOpt:    s.builder.storageOpt,
 	})
 	s.builder.cliLog.StopTimer(logTimer)
 	s.builder.Logger().Debugln(s.builder.cliLog.GetCmdTime(logTimer))
@@ -766,3 +766,54 @@ ENV testEnv=bar`,
 		})
 	}
 }
+
+func TestDestsOverlap(t *testing.T) {
+	tests := []struct {
+		name  string
+		dests []string
+		dest  string
+		want  bool
+	}{
+		{
+			name:  "disjoint absolute destinations",
+			dests: []string{"/app/bin", "/app/lib"},
+			dest:  "/app/etc",
+			want:  false,
+		},
+		{
+			name:  "same destination",
+			dests: []string{"/app/bin"},
+			dest:  "/app/bin",
+			want:  true,
+		},
+		{
+			name:  "nested destination",
+			dests: []string{"/app"},
+			dest:  "/app/bin",
+			want:  true,
+		},
+		{
+			name:  "nested parent with trailing slash",
+			dests: []string{"/app/bin/"},
+			dest:  "/app",
+			want:  true,
+		},
+		{
+			name:  "sibling with common name prefix",
+			dests: []string{"/app"},
+			dest:  "/app2",
+			want:  false,
+		},
+		{
+			name:  "mixed relative and absolute",
+			dests: []string{"bin"},
+			dest:  "/bin",
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, destsOverlap(tt.dests, tt.dest), tt.want)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: per-build storage option related functions
+
+package dockerfile
+
+import (
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+// parseStorageOpts turns the per-build storage options into the map
+// the storage passes to the graph driver when the read-write layer is
+// created, only the project quota "size" is accepted and a driver or
+// filesystem without quota support fails the build before any step runs
+func parseStorageOpts(specs []string) (map[string]string, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	opts := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		kv := strings.SplitN(spec, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, errors.Errorf("invalid storage option %q, expected size=<limit>", spec)
+		}
+		if kv[0] != "size" {
+			return nil, errors.Errorf("unsupported storage option %q, only \"size\" is supported", kv[0])
+		}
+		size, err := units.RAMInBytes(kv[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid storage size %q", kv[1])
+		}
+		if size <= 0 {
+			return nil, errors.Errorf("storage size %q must be positive", kv[1])
+		}
+		opts[kv[0]] = kv[1]
+	}
+
+	return opts, nil
+}
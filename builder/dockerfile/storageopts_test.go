@@ -0,0 +1,82 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: per-build storage option related functions tests
+
+package dockerfile
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseStorageOpts(t *testing.T) {
+	var testcases = []struct {
+		name      string
+		specs     []string
+		want      map[string]string
+		wantErr   bool
+		errString string
+	}{
+		{
+			name:  "TC1 - no options",
+			specs: nil,
+			want:  nil,
+		},
+		{
+			name:  "TC2 - size quota",
+			specs: []string{"size=20G"},
+			want:  map[string]string{"size": "20G"},
+		},
+		{
+			name:      "TC3 - missing value",
+			specs:     []string{"size"},
+			wantErr:   true,
+			errString: "expected size=<limit>",
+		},
+		{
+			name:      "TC4 - empty value",
+			specs:     []string{"size="},
+			wantErr:   true,
+			errString: "expected size=<limit>",
+		},
+		{
+			name:      "TC5 - unsupported option",
+			specs:     []string{"inodes=100"},
+			wantErr:   true,
+			errString: "unsupported storage option",
+		},
+		{
+			name:      "TC6 - malformed size",
+			specs:     []string{"size=abc"},
+			wantErr:   true,
+			errString: "invalid storage size",
+		},
+		{
+			name:      "TC7 - zero size",
+			specs:     []string{"size=0"},
+			wantErr:   true,
+			errString: "must be positive",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseStorageOpts(tc.specs)
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tc.want)
+		})
+	}
+}
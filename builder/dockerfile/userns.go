@@ -0,0 +1,71 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: user namespace remapping related functions
+
+package dockerfile
+
+import (
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/pkg/errors"
+)
+
+const (
+	// usernsAuto derives the id maps from the subordinate ranges of
+	// usernsMappingUser instead of explicit map triples
+	usernsAuto = "auto"
+	// usernsMappingUser is the user whose /etc/subuid and /etc/subgid
+	// entries back --userns=auto
+	usernsMappingUser = "containers"
+)
+
+// resolveUsernsMappings turns the userns options of a build into the
+// id mappings applied to the RW layers and the RUN user namespaces,
+// nil mappings keep the build in the host user namespace
+func resolveUsernsMappings(userns string, uidMapSpec, gidMapSpec []string) (*idtools.IDMappings, error) {
+	if userns == "" && len(uidMapSpec) == 0 && len(gidMapSpec) == 0 {
+		return nil, nil
+	}
+
+	switch userns {
+	case "":
+	case usernsAuto:
+		if len(uidMapSpec) != 0 || len(gidMapSpec) != 0 {
+			return nil, errors.New("--userns=auto conflicts with explicit --userns-uid-map and --userns-gid-map")
+		}
+		mappings, err := idtools.NewIDMappings(usernsMappingUser, usernsMappingUser)
+		if err != nil {
+			return nil, errors.Wrapf(err, "--userns=auto needs /etc/subuid and /etc/subgid ranges for user %q", usernsMappingUser)
+		}
+		return mappings, nil
+	default:
+		return nil, errors.Errorf("invalid userns mode %q, only \"auto\" is supported", userns)
+	}
+
+	if len(uidMapSpec) == 0 {
+		return nil, errors.New("--userns-gid-map needs a --userns-uid-map as well")
+	}
+	uidMap, err := idtools.ParseIDMap(uidMapSpec, "userns-uid-map")
+	if err != nil {
+		return nil, err
+	}
+	// a missing gid map reuses the uid map, the common case maps both
+	// ranges the same way
+	if len(gidMapSpec) == 0 {
+		gidMapSpec = uidMapSpec
+	}
+	gidMap, err := idtools.ParseIDMap(gidMapSpec, "userns-gid-map")
+	if err != nil {
+		return nil, err
+	}
+
+	return idtools.NewIDMappingsFromMaps(uidMap, gidMap), nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: user namespace remapping related functions tests
+
+package dockerfile
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestResolveUsernsMappings(t *testing.T) {
+	testcases := []struct {
+		name      string
+		userns    string
+		uidMap    []string
+		gidMap    []string
+		wantNil   bool
+		wantErr   bool
+		errString string
+	}{
+		{
+			name:    "TC1 no userns options keep the host namespace",
+			wantNil: true,
+		},
+		{
+			name:   "TC2 explicit uid map reused as gid map",
+			uidMap: []string{"0:100000:65536"},
+		},
+		{
+			name:   "TC3 explicit uid and gid maps",
+			uidMap: []string{"0:100000:65536"},
+			gidMap: []string{"0:200000:65536"},
+		},
+		{
+			name:      "TC4 auto conflicts with explicit maps",
+			userns:    "auto",
+			uidMap:    []string{"0:100000:65536"},
+			wantErr:   true,
+			errString: "conflicts with explicit",
+		},
+		{
+			name:      "TC5 unknown userns mode",
+			userns:    "host",
+			wantErr:   true,
+			errString: "invalid userns mode",
+		},
+		{
+			name:      "TC6 gid map without uid map",
+			gidMap:    []string{"0:100000:65536"},
+			wantErr:   true,
+			errString: "needs a --userns-uid-map",
+		},
+		{
+			name:      "TC7 malformed map triple",
+			uidMap:    []string{"0:100000"},
+			wantErr:   true,
+			errString: "userns-uid-map",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			mappings, err := resolveUsernsMappings(tc.userns, tc.uidMap, tc.gidMap)
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+				return
+			}
+			assert.NilError(t, err)
+			if tc.wantNil {
+				assert.Assert(t, mappings == nil)
+				return
+			}
+			assert.Equal(t, len(mappings.UIDs()), len(tc.uidMap))
+			if len(tc.gidMap) == 0 {
+				// the uid map backs the gid map when none is given
+				assert.DeepEqual(t, mappings.GIDs(), mappings.UIDs())
+			} else {
+				assert.Equal(t, len(mappings.GIDs()), len(tc.gidMap))
+			}
+		})
+	}
+}
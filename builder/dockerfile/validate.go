@@ -0,0 +1,67 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-07-18
+// Description: output image validation
+
+package dockerfile
+
+import (
+	ci "github.com/containers/image/v5/image"
+	is "github.com/containers/image/v5/storage"
+	"github.com/pkg/errors"
+
+	"isula.org/isula-build/image"
+	"isula.org/isula-build/pkg/ocicheck"
+)
+
+// validateOutputImage checks the committed image against the OCI image
+// spec before it is exported, so spec violations are caught before a
+// strict registry rejects them
+func (b *Builder) validateOutputImage(imageID string) error {
+	ref, err := is.Transport.ParseStoreReference(b.localStore.Store, imageID)
+	if err != nil {
+		return errors.Wrapf(err, "parse reference of image %q for validation failed", imageID)
+	}
+	src, err := ref.NewImageSource(b.ctx, image.GetSystemContext())
+	if err != nil {
+		return errors.Wrap(err, "open image source for validation failed")
+	}
+	fullImg, err := ci.FromSource(b.ctx, image.GetSystemContext(), src)
+	if err != nil {
+		src.Close() // nolint:errcheck
+		return errors.Wrap(err, "load image for validation failed")
+	}
+	defer func() {
+		if cErr := fullImg.Close(); cErr != nil {
+			b.Logger().Warnf("Close image for validation failed: %v", cErr)
+		}
+	}()
+
+	manifestRaw, _, err := fullImg.Manifest(b.ctx)
+	if err != nil {
+		return errors.Wrap(err, "read manifest for validation failed")
+	}
+	configRaw, err := fullImg.ConfigBlob(b.ctx)
+	if err != nil {
+		return errors.Wrap(err, "read config for validation failed")
+	}
+
+	violations := ocicheck.CheckImage(manifestRaw, configRaw)
+	if len(violations) == 0 {
+		b.cliLog.Print("Output validation passed for image %s\n", imageID)
+		return nil
+	}
+
+	for _, violation := range violations {
+		b.cliLog.Print("Output validation: %s\n", violation.String())
+	}
+	return errors.Errorf("image %s violates the OCI image spec in %d place(s)", imageID, len(violations))
+}
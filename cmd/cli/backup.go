@@ -0,0 +1,159 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for "backup" and "restore" commands
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/util"
+)
+
+const backupExample = `isula-build system backup -o backup.tar
+isula-build system backup busybox:latest alpine:3.9 -o backup.tar`
+
+const restoreExample = `isula-build system restore -i backup.tar`
+
+type backupOptions struct {
+	path string
+}
+
+var backupOpts backupOptions
+
+type restoreOptions struct {
+	path string
+}
+
+var restoreOpts restoreOptions
+
+// NewBackupCmd returns system backup command
+func NewBackupCmd() *cobra.Command {
+	backupCmd := &cobra.Command{
+		Use:     "backup [IMAGE...] FLAGS",
+		Short:   "Back up the image store into a portable archive",
+		RunE:    backupCommand,
+		Example: backupExample,
+	}
+
+	backupCmd.PersistentFlags().StringVarP(&backupOpts.path, "output", "o", "", "Path of the backup archive")
+
+	return backupCmd
+}
+
+func backupCommand(c *cobra.Command, args []string) error {
+	if backupOpts.path == "" {
+		return errors.New("output path(-o) should not be empty")
+	}
+	if strings.Contains(backupOpts.path, ":") {
+		return errors.Errorf("colon in path %q is not supported", backupOpts.path)
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return errors.New("get current path failed")
+	}
+	backupOpts.path = util.MakeAbsolute(backupOpts.path, pwd)
+	if exist, err := util.IsExist(backupOpts.path); err != nil {
+		return errors.Wrap(err, "check output path failed")
+	} else if exist {
+		return errors.Errorf("output file already exist: %q, try to remove or rename it", backupOpts.path)
+	}
+
+	ctx := context.Background()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runBackup(ctx, cli, args)
+}
+
+func runBackup(ctx context.Context, cli Cli, images []string) error {
+	backupID := util.GenerateNonCryptoID()[:constant.DefaultIDLen]
+
+	resp, err := cli.Client().Backup(ctx, &pb.BackupRequest{
+		BackupID: backupID,
+		Path:     backupOpts.path,
+		Images:   images,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Backed up %d images to %s\n", len(resp.Images), backupOpts.path)
+
+	return nil
+}
+
+// NewRestoreCmd returns system restore command
+func NewRestoreCmd() *cobra.Command {
+	restoreCmd := &cobra.Command{
+		Use:     "restore FLAGS",
+		Short:   "Restore the images of a backup archive into the store",
+		RunE:    restoreCommand,
+		Args:    util.NoArgs,
+		Example: restoreExample,
+	}
+
+	restoreCmd.PersistentFlags().StringVarP(&restoreOpts.path, "input", "i", "", "Path of the backup archive")
+
+	return restoreCmd
+}
+
+func restoreCommand(c *cobra.Command, args []string) error {
+	if restoreOpts.path == "" {
+		return errors.New("input path(-i) should not be empty")
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return errors.New("get current path failed")
+	}
+	restoreOpts.path = util.MakeAbsolute(restoreOpts.path, pwd)
+	if err := util.CheckFileInfoAndSize(restoreOpts.path, constant.MaxLoadFileSize); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runRestore(ctx, cli)
+}
+
+func runRestore(ctx context.Context, cli Cli) error {
+	restoreID := util.GenerateNonCryptoID()[:constant.DefaultIDLen]
+
+	resp, err := cli.Client().Restore(ctx, &pb.RestoreRequest{
+		RestoreID: restoreID,
+		Path:      restoreOpts.path,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, imageID := range resp.Images {
+		fmt.Printf("Restored image %s\n", imageID)
+	}
+	fmt.Printf("Restored %d images from %s\n", len(resp.Images), restoreOpts.path)
+
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing backup and restore commands
+
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestBackupCommand(t *testing.T) {
+	backupCmd := NewBackupCmd()
+
+	backupOpts.path = ""
+	err := backupCommand(backupCmd, nil)
+	assert.ErrorContains(t, err, "output path(-o) should not be empty")
+
+	backupOpts.path = "back:up.tar"
+	err = backupCommand(backupCmd, nil)
+	assert.ErrorContains(t, err, "colon in path")
+
+	tmpDir := fs.NewDir(t, t.Name(), fs.WithFile("backup.tar", "content"))
+	defer tmpDir.Remove()
+	backupOpts.path = tmpDir.Join("backup.tar")
+	err = backupCommand(backupCmd, nil)
+	assert.ErrorContains(t, err, "already exist")
+
+	backupOpts.path = ""
+}
+
+func TestRestoreCommand(t *testing.T) {
+	restoreCmd := NewRestoreCmd()
+
+	restoreOpts.path = ""
+	err := restoreCommand(restoreCmd, nil)
+	assert.ErrorContains(t, err, "input path(-i) should not be empty")
+
+	tmpDir := fs.NewDir(t, t.Name())
+	defer tmpDir.Remove()
+	restoreOpts.path = filepath.Join(tmpDir.Path(), "missing.tar")
+	err = restoreCommand(restoreCmd, nil)
+	assert.ErrorContains(t, err, "no such file or directory")
+
+	restoreOpts.path = ""
+}
+
+func TestRunBackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+
+	backupOpts.path = "/tmp/backup.tar"
+	err := runBackup(ctx, &cli, []string{"busybox:latest"})
+	assert.NilError(t, err)
+	backupOpts.path = ""
+
+	restoreOpts.path = "/tmp/backup.tar"
+	err = runRestore(ctx, &cli)
+	assert.NilError(t, err)
+	restoreOpts.path = ""
+}
@@ -25,31 +25,65 @@ import (
 	"strings"
 	"time"
 
+	"github.com/containers/storage/pkg/archive"
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/docker/go-units"
 	"github.com/gogo/protobuf/types"
 	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	constant "isula.org/isula-build"
 	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/pkg/gitcontext"
 	"isula.org/isula-build/pkg/opts"
+	"isula.org/isula-build/pkg/urlcontext"
 	"isula.org/isula-build/util"
 )
 
 type buildOptions struct {
-	file          string
-	format        string
-	output        string
-	buildArgs     []string
-	capAddList    []string
-	contextDir    string
-	buildID       string
-	proxyFlag     bool
-	buildStatic   opts.ListOpts
-	imageIDFile   string
-	additionalTag string
+	file           string
+	format         string
+	output         string
+	buildArgs      []string
+	capAddList     []string
+	contextDir     string
+	buildID        string
+	proxyFlag      bool
+	buildStatic    opts.ListOpts
+	imageIDFile    string
+	additionalTag  string
+	resumeBuildID  string
+	builder        string
+	graphOutFile   string
+	graphFormat    string
+	platform       string
+	noCache        bool
+	cacheFrom      []string
+	cacheTo        string
+	validateOutput bool
+	sensitiveArgs  []string
+	compression    string
+	parallelCopy   bool
+	policyStrict   bool
+	logTimestamps  bool
+	userns         string
+	usernsUIDMap   []string
+	usernsGIDMap   []string
+	memory         string
+	memoryBytes    int64
+	cpuQuota       int64
+	cpus           float64
+	pidsLimit      int64
+	storageOpts    []string
+	timeout        string
+	// stdinContextDir is the temporary directory holding a context
+	// unpacked from stdin, removed when the build finishes
+	stdinContextDir string
 }
 
 const (
@@ -58,9 +92,14 @@ isula-build ctr-img build -f Dockerfile -o docker-archive:name.tar:image:tag .
 isula-build ctr-img build -f Dockerfile -o docker-daemon:image:tag .
 isula-build ctr-img build -f Dockerfile -o docker://registry.example.com/repository:tag .
 isula-build ctr-img build -f Dockerfile -o isulad:image:tag .
-isula-build ctr-img build -f Dockerfile --build-static='build-time=2020-06-30 15:05:05' .`
+isula-build ctr-img build -f Dockerfile -o containerd:k8s.io:image:tag .
+isula-build ctr-img build -f Dockerfile -o containers-storage:image:tag .
+isula-build ctr-img build -f Dockerfile --build-static='build-time=2020-06-30 15:05:05' .
+tar -C ./app -c . | isula-build ctr-img build -`
 	// buildTimeType is an option for static-build
 	buildTimeType = "build-time"
+	// maxStdinContextSize bounds the size of a context tarball piped on stdin
+	maxStdinContextSize = 1024 * 1024 * 1024
 )
 
 var buildOpts buildOptions = buildOptions{
@@ -77,12 +116,23 @@ func NewContainerImageBuildCmd() *cobra.Command {
 		NewBuildCmd(),
 		NewImagesCmd(),
 		NewRemoveCmd(),
+		NewImagePruneCmd(),
 		NewLoadCmd(),
 		NewPullCmd(),
 		NewPushCmd(),
 		NewImportCmd(),
+		NewConvertCmd(),
+		NewInspectCmd(),
+		NewDiffCmd(),
+		NewTreeCmd(),
+		NewRemoteInspectCmd(),
+		NewVerifyRemoteCmd(),
 		NewTagCmd(),
+		NewMetaCmd(),
+		NewPromoteCmd(),
 		NewSaveCmd(),
+		NewDfCmd(),
+		NewCacheCmd(),
 	)
 
 	disableFlags(ctrImgBuildCmd)
@@ -94,7 +144,7 @@ func NewContainerImageBuildCmd() *cobra.Command {
 func NewBuildCmd() *cobra.Command {
 	// buildCmd represents the "build" command
 	buildCmd := &cobra.Command{
-		Use:     "build [FLAGS] PATH",
+		Use:     "build [FLAGS] PATH|-",
 		Short:   "Build container images",
 		Example: buildExample,
 		RunE:    buildCommand,
@@ -113,6 +163,28 @@ func NewBuildCmd() *cobra.Command {
 	buildCmd.PersistentFlags().StringArrayVar(&buildOpts.capAddList, "cap-add", []string{}, "Add Linux capabilities for RUN command")
 	buildCmd.PersistentFlags().StringVar(&buildOpts.imageIDFile, "iidfile", "", "Write image ID to the file")
 	buildCmd.PersistentFlags().StringVarP(&buildOpts.additionalTag, "tag", "t", "", "Add tag to the built image")
+	buildCmd.PersistentFlags().StringVar(&buildOpts.resumeBuildID, "resume", "", "Resume an interrupted build with the given build ID")
+	buildCmd.PersistentFlags().StringVar(&buildOpts.builder, "builder", "", "Name of the builder instance carrying build defaults")
+	buildCmd.PersistentFlags().StringVar(&buildOpts.graphOutFile, "graph-out", "", "Write the build graph to the file, in json or dot format according to its extension")
+	buildCmd.PersistentFlags().StringVar(&buildOpts.platform, "platform", "", "Target platform of the build in os/arch[/variant] format, like linux/arm64")
+	buildCmd.PersistentFlags().BoolVar(&buildOpts.noCache, "no-cache", false, "Do not use the build cache, the cache is still repopulated")
+	buildCmd.PersistentFlags().StringArrayVar(&buildOpts.cacheFrom, "cache-from", []string{}, "Images or cache repositories to use as cache sources")
+	buildCmd.PersistentFlags().StringVar(&buildOpts.cacheTo, "cache-to", "", "Repository to push the cache entries of this build to")
+	buildCmd.PersistentFlags().BoolVar(&buildOpts.validateOutput, "validate-output", false, "Validate the built image against the OCI image spec before exporting")
+	buildCmd.PersistentFlags().StringArrayVar(&buildOpts.sensitiveArgs, "sensitive-arg", []string{}, "Names of build-args whose values are hidden from logs and the status output")
+	buildCmd.PersistentFlags().StringVar(&buildOpts.compression, "compression", "", "Layer compression of exported images (\"gzip\", \"zstd\", \"zstd:chunked\" or \"none\")")
+	buildCmd.PersistentFlags().BoolVar(&buildOpts.parallelCopy, "parallel-copy", false, "Run consecutive independent COPY/ADD commands of a stage concurrently")
+	buildCmd.PersistentFlags().BoolVar(&buildOpts.policyStrict, "pull-policy-strict", false, "Reject base images the signature policy would accept without any signature check")
+	buildCmd.PersistentFlags().BoolVar(&buildOpts.logTimestamps, "log-timestamps", false, "Prefix each log line with a timestamp and report the duration of each finished step")
+	buildCmd.PersistentFlags().StringVar(&buildOpts.userns, "userns", "", "Run RUN commands in a user namespace, \"auto\" derives the id maps from the subordinate ranges of user \"containers\"")
+	buildCmd.PersistentFlags().StringArrayVar(&buildOpts.usernsUIDMap, "userns-uid-map", []string{}, "Map uids into the user namespace, as containerID:hostID:size triples")
+	buildCmd.PersistentFlags().StringArrayVar(&buildOpts.usernsGIDMap, "userns-gid-map", []string{}, "Map gids into the user namespace, as containerID:hostID:size triples, the uid map is reused when empty")
+	buildCmd.PersistentFlags().StringVarP(&buildOpts.memory, "memory", "m", "", "Memory limit of each RUN command, as a size like 512m, empty keeps it unlimited")
+	buildCmd.PersistentFlags().Int64Var(&buildOpts.cpuQuota, "cpu-quota", 0, "CPU quota of each RUN command in microseconds per scheduler period, 0 keeps it unlimited")
+	buildCmd.PersistentFlags().Float64Var(&buildOpts.cpus, "cpus", 0, "Number of CPUs each RUN command may use, 0 keeps it unlimited")
+	buildCmd.PersistentFlags().Int64Var(&buildOpts.pidsLimit, "pids-limit", 0, "Max processes of each RUN command, 0 keeps it unlimited")
+	buildCmd.PersistentFlags().StringArrayVar(&buildOpts.storageOpts, "storage-opt", []string{}, "Storage driver options for the build, such as size=20G to bound the read-write layer")
+	buildCmd.PersistentFlags().StringVar(&buildOpts.timeout, "timeout", "", "Fail the build after this long, a duration like 1h30m, empty uses the daemon default")
 
 	return buildCmd
 }
@@ -121,6 +193,13 @@ func buildCommand(c *cobra.Command, args []string) error {
 	if err := newBuildOptions(args); err != nil {
 		return err
 	}
+	if buildOpts.stdinContextDir != "" {
+		defer func() {
+			if rErr := os.RemoveAll(buildOpts.stdinContextDir); rErr != nil {
+				logrus.Warnf("Removing stdin context dir %q failed: %v", buildOpts.stdinContextDir, rErr)
+			}
+		}()
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -181,6 +260,28 @@ func newBuildOptions(args []string) error {
 		}
 	}
 
+	// a remote context (git or tarball URL) is fetched by the daemon,
+	// nothing to resolve locally
+	if gitcontext.IsGitURL(args[0]) || urlcontext.IsURLContext(args[0]) {
+		buildOpts.contextDir = args[0]
+		return nil
+	}
+
+	// a "-" context unpacks the tar stream piped on stdin into a
+	// temporary directory, removed again when the build finishes
+	if args[0] == stdinSource {
+		if buildOpts.file == stdinSource {
+			return errors.New("the context and the Dockerfile cannot both be read from stdin")
+		}
+		contextDir, err := unpackStdinContext(os.Stdin)
+		if err != nil {
+			return err
+		}
+		buildOpts.contextDir = contextDir
+		buildOpts.stdinContextDir = contextDir
+		return nil
+	}
+
 	// the path may be a symbol link
 	contextDir, err := filepath.Abs(args[0])
 	if err != nil {
@@ -247,7 +348,7 @@ func checkAbsPath(path string) (string, error) {
 func modifyLocalTransporter(transport string, absPath string, segments []string) error {
 	const validIsuladFieldsLen = 3
 	switch transport {
-	case constant.DockerArchiveTransport, constant.OCIArchiveTransport:
+	case constant.DockerArchiveTransport, constant.OCIArchiveTransport, constant.DiskImageTransport, constant.SquashfsTransport, constant.SysrootTransport, constant.LocalTransport:
 		newSeg := util.CopyStrings(segments)
 		newSeg[1] = absPath
 		buildOpts.output = strings.Join(newSeg, ":")
@@ -327,10 +428,32 @@ func runBuild(ctx context.Context, cli Cli) (string, error) {
 	if err = util.CheckImageFormat(buildOpts.format); err != nil {
 		return "", err
 	}
+	if err = util.CheckCompressionFormat(buildOpts.compression); err != nil {
+		return "", err
+	}
 	if err = checkAndProcessOutput(); err != nil {
 		return "", err
 	}
-	if content, digest, err = readDockerfile(); err != nil {
+	if err = checkUsernsOpts(); err != nil {
+		return "", err
+	}
+	if err = checkResourceOpts(); err != nil {
+		return "", err
+	}
+	if err = checkStorageOpts(); err != nil {
+		return "", err
+	}
+	if err = checkTimeoutOpt(); err != nil {
+		return "", err
+	}
+	if gitcontext.IsGitURL(buildOpts.contextDir) || urlcontext.IsURLContext(buildOpts.contextDir) {
+		// the Dockerfile lives in the remote context and is read by the
+		// daemon after the context is fetched
+		if buildOpts.file != "" {
+			return "", errors.New("--filename is not supported with a remote context, the Dockerfile at the context root is used")
+		}
+		digest = remoteContextDigest(buildOpts.contextDir)
+	} else if content, digest, err = readDockerfile(); err != nil {
 		return "", err
 	}
 	if encrypted, err = encryptBuildArgs(util.DefaultRSAKeyPath); err != nil {
@@ -340,6 +463,14 @@ func runBuild(ctx context.Context, cli Cli) (string, error) {
 		return "", err
 	}
 	buildOpts.imageIDFile = imageIDFilePath
+	if err = checkAndProcessGraphOut(); err != nil {
+		return "", err
+	}
+	if buildOpts.platform != "" {
+		if _, _, _, err = util.ParsePlatform(buildOpts.platform); err != nil {
+			return "", err
+		}
+	}
 
 	buildStatic, t, err := parseStaticBuildOpts()
 	if err != nil {
@@ -348,36 +479,189 @@ func runBuild(ctx context.Context, cli Cli) (string, error) {
 	entityID := fmt.Sprintf("%s:%s", digest, t.String())
 
 	buildResp, err := cli.Client().Build(ctx, &pb.BuildRequest{
-		BuildType:     constant.BuildContainerImageType,
-		BuildID:       buildOpts.buildID,
-		EntityID:      entityID,
-		BuildArgs:     buildOpts.buildArgs,
-		CapAddList:    buildOpts.capAddList,
-		ContextDir:    buildOpts.contextDir,
-		FileContent:   content,
-		Output:        buildOpts.output,
-		Proxy:         buildOpts.proxyFlag,
-		BuildStatic:   buildStatic,
-		Iidfile:       buildOpts.imageIDFile,
-		AdditionalTag: buildOpts.additionalTag,
-		Encrypted:     encrypted,
-		Format:        buildOpts.format,
+		BuildType:        constant.BuildContainerImageType,
+		BuildID:          buildOpts.buildID,
+		EntityID:         entityID,
+		BuildArgs:        buildOpts.buildArgs,
+		CapAddList:       buildOpts.capAddList,
+		ContextDir:       buildOpts.contextDir,
+		FileContent:      content,
+		Output:           buildOpts.output,
+		Proxy:            buildOpts.proxyFlag,
+		BuildStatic:      buildStatic,
+		Iidfile:          buildOpts.imageIDFile,
+		AdditionalTag:    buildOpts.additionalTag,
+		Encrypted:        encrypted,
+		Format:           buildOpts.format,
+		ResumeBuildID:    buildOpts.resumeBuildID,
+		Builder:          buildOpts.builder,
+		LogLevel:         cliOpts.LogLevel,
+		GraphFormat:      buildOpts.graphFormat,
+		Platform:         buildOpts.platform,
+		NoCache:          buildOpts.noCache,
+		CacheFrom:        buildOpts.cacheFrom,
+		CacheTo:          buildOpts.cacheTo,
+		ValidateOutput:   buildOpts.validateOutput,
+		SensitiveArgs:    buildOpts.sensitiveArgs,
+		Compression:      buildOpts.compression,
+		ParallelCopy:     buildOpts.parallelCopy,
+		PullPolicyStrict: buildOpts.policyStrict,
+		LogTimestamps:    buildOpts.logTimestamps,
+		Userns:           buildOpts.userns,
+		UsernsUidMap:     buildOpts.usernsUIDMap,
+		UsernsGidMap:     buildOpts.usernsGIDMap,
+		Memory:           buildOpts.memoryBytes,
+		CpuQuota:         buildOpts.cpuQuota,
+		Cpus:             buildOpts.cpus,
+		PidsLimit:        buildOpts.pidsLimit,
+		StorageOpts:      buildOpts.storageOpts,
+		Timeout:          buildOpts.timeout,
 	})
 	if err != nil {
 		return "", err
 	}
 
+	if buildOpts.graphOutFile != "" {
+		if wErr := ioutil.WriteFile(buildOpts.graphOutFile, []byte(buildResp.BuildGraph), constant.DefaultRootFileMode); wErr != nil {
+			return buildResp.ImageID, errors.Wrapf(wErr, "write build graph to %q failed", buildOpts.graphOutFile)
+		}
+	}
+
 	return buildResp.ImageID, err
 }
 
+// checkUsernsOpts rejects malformed userns options before the build
+// request is sent, the daemon resolves the final id maps
+func checkUsernsOpts() error {
+	switch buildOpts.userns {
+	case "":
+	case "auto":
+		if len(buildOpts.usernsUIDMap) != 0 || len(buildOpts.usernsGIDMap) != 0 {
+			return errors.New("--userns=auto conflicts with explicit --userns-uid-map and --userns-gid-map")
+		}
+		return nil
+	default:
+		return errors.Errorf("invalid userns mode %q, only \"auto\" is supported", buildOpts.userns)
+	}
+
+	if len(buildOpts.usernsGIDMap) != 0 && len(buildOpts.usernsUIDMap) == 0 {
+		return errors.New("--userns-gid-map needs a --userns-uid-map as well")
+	}
+	if _, err := idtools.ParseIDMap(buildOpts.usernsUIDMap, "userns-uid-map"); err != nil {
+		return err
+	}
+	if _, err := idtools.ParseIDMap(buildOpts.usernsGIDMap, "userns-gid-map"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkResourceOpts validates the RUN resource limits and parses the
+// memory size into bytes
+func checkResourceOpts() error {
+	buildOpts.memoryBytes = 0
+	if buildOpts.memory != "" {
+		size, err := units.RAMInBytes(buildOpts.memory)
+		if err != nil {
+			return errors.Wrapf(err, "invalid memory limit %q", buildOpts.memory)
+		}
+		buildOpts.memoryBytes = size
+	}
+	if buildOpts.cpuQuota < 0 {
+		return errors.Errorf("invalid cpu quota %d, it must not be negative", buildOpts.cpuQuota)
+	}
+	if buildOpts.cpus < 0 {
+		return errors.Errorf("invalid cpus %v, it must not be negative", buildOpts.cpus)
+	}
+	if buildOpts.cpuQuota != 0 && buildOpts.cpus != 0 {
+		return errors.New("conflict flags between --cpus and --cpu-quota")
+	}
+	if buildOpts.pidsLimit < 0 {
+		return errors.Errorf("invalid pids limit %d, it must not be negative", buildOpts.pidsLimit)
+	}
+
+	return nil
+}
+
+// checkStorageOpts rejects malformed storage options before the build
+// request is sent, the daemon applies them to the read-write layer
+func checkStorageOpts() error {
+	for _, opt := range buildOpts.storageOpts {
+		kv := strings.SplitN(opt, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return errors.Errorf("invalid storage option %q, expected size=<limit>", opt)
+		}
+		if kv[0] != "size" {
+			return errors.Errorf("unsupported storage option %q, only \"size\" is supported", kv[0])
+		}
+		size, err := units.RAMInBytes(kv[1])
+		if err != nil {
+			return errors.Wrapf(err, "invalid storage size %q", kv[1])
+		}
+		if size <= 0 {
+			return errors.Errorf("storage size %q must be positive", kv[1])
+		}
+	}
+
+	return nil
+}
+
+// checkTimeoutOpt rejects a malformed build timeout before the request
+// is sent, empty leaves the daemon default in charge
+func checkTimeoutOpt() error {
+	if buildOpts.timeout == "" {
+		return nil
+	}
+	timeout, err := time.ParseDuration(buildOpts.timeout)
+	if err != nil {
+		return errors.Wrapf(err, "invalid build timeout %q", buildOpts.timeout)
+	}
+	if timeout <= 0 {
+		return errors.Errorf("build timeout %q must be positive", buildOpts.timeout)
+	}
+
+	return nil
+}
+
+// checkAndProcessGraphOut derives the build graph format from the
+// extension of the --graph-out file
+func checkAndProcessGraphOut() error {
+	if buildOpts.graphOutFile == "" {
+		buildOpts.graphFormat = ""
+		return nil
+	}
+
+	graphOutPath, err := getAbsPath(buildOpts.graphOutFile)
+	if err != nil {
+		return err
+	}
+	buildOpts.graphOutFile = graphOutPath
+
+	switch filepath.Ext(graphOutPath) {
+	case ".json":
+		buildOpts.graphFormat = "json"
+	case ".dot":
+		buildOpts.graphFormat = "dot"
+	default:
+		return errors.Errorf("the graph-out file %q must end with .json or .dot", graphOutPath)
+	}
+
+	return nil
+}
+
 // encrypts those sensitive args before transporting via GRPC
 func encryptBuildArgs(path string) (bool, error) {
 	var hasSensiArg bool
+	sensitive := make(map[string]bool, len(buildOpts.sensitiveArgs))
+	for _, name := range buildOpts.sensitiveArgs {
+		sensitive[name] = true
+	}
 	for _, v := range buildOpts.buildArgs {
 		const kvNums = 2
 		var ss = strings.SplitN(v, "=", kvNums)
 		// check whether there is sensitive build-arg, if has, goto encrypt all build-args
-		if constant.ReservedArgs[ss[0]] {
+		if constant.ReservedArgs[ss[0]] || sensitive[ss[0]] {
 			hasSensiArg = true
 			break
 		}
@@ -405,33 +689,119 @@ func encryptBuildArgs(path string) (bool, error) {
 	return hasSensiArg, nil
 }
 
+const (
+	// maxStatusRetries is the number of reconnect attempts in a row
+	// before a broken status stream fails, progress resets the counter
+	maxStatusRetries = 5
+	// statusRetryInterval is the pause before reconnecting the stream
+	statusRetryInterval = time.Second
+)
+
 func runStatus(ctx context.Context, cli Cli) error {
+	var received int64
+	for retries := 0; ; retries++ {
+		progressed, err := streamStatus(ctx, cli, &received)
+		if err == nil {
+			return nil
+		}
+		if progressed {
+			retries = 0
+		}
+		if ctx.Err() != nil || retries >= maxStatusRetries || !isTransientGrpcErr(err) {
+			return err
+		}
+		logrus.Warnf("Status stream broken: %v, reconnecting from line %d", err, received)
+		time.Sleep(statusRetryInterval)
+	}
+}
+
+// streamStatus consumes one status stream from the line offset the
+// previous streams left off, it reports whether any progress was made
+func streamStatus(ctx context.Context, cli Cli, received *int64) (bool, error) {
 	status, err := cli.Client().Status(ctx, &pb.StatusRequest{
-		BuildID: buildOpts.buildID,
+		BuildID:     buildOpts.buildID,
+		StartOffset: *received,
+		Follow:      true,
 	})
 	if err != nil {
-		return err
+		return false, err
 	}
 
+	var progressed bool
 	for {
 		msg, err := status.Recv()
 		if msg != nil {
 			fmt.Print(msg.Content)
+			*received++
+			progressed = true
 		}
 
 		if err != nil {
 			if err == io.EOF {
-				return nil
+				return progressed, nil
 			}
-			return err
+			return progressed, err
+		}
+	}
+}
+
+// isTransientGrpcErr reports whether the stream failed in a way a
+// reconnect can recover from, such as a daemon restart or a reset
+// connection, instead of a definite RPC failure
+func isTransientGrpcErr(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Internal:
+		return true
+	default:
+		return false
+	}
+}
+
+// unpackStdinContext spools the context tarball piped on stdin and
+// unpacks it into a temporary directory serving as build context
+func unpackStdinContext(in io.Reader) (string, error) {
+	tarFile, err := spoolStdin(in, maxStdinContextSize)
+	if tarFile != "" {
+		defer func() {
+			if rErr := os.Remove(tarFile); rErr != nil {
+				logrus.Warnf("Removing spooled context tarball %q failed: %v", tarFile, rErr)
+			}
+		}()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	contextDir, err := ioutil.TempDir("", "isula-build-context-")
+	if err != nil {
+		return "", errors.Wrap(err, "create stdin context dir failed")
+	}
+	if err := util.UnpackFile(tarFile, contextDir, archive.Uncompressed, false); err != nil {
+		if rErr := os.RemoveAll(contextDir); rErr != nil {
+			logrus.Warnf("Removing stdin context dir %q failed: %v", contextDir, rErr)
 		}
+		return "", errors.Wrap(err, "unpack the context from stdin failed")
 	}
+
+	return contextDir, nil
+}
+
+// remoteContextDigest derives the build entity digest from the remote
+// context URL, there is no local Dockerfile to hash
+func remoteContextDigest(url string) string {
+	return digest.Canonical.FromString(url).Encoded()
 }
 
 // readDockerfile validates the --file, opens it and returns its content and sha256sum
 // The possible Dockerfile path should be: filepath or contextDir+filepath
 // or contextDir+Dockerfile if filepath is empty
 func readDockerfile() (string, string, error) {
+	// "-f -" reads the Dockerfile content piped on stdin, the context
+	// stays a normal directory
+	if buildOpts.file == stdinSource {
+		return readStdinDockerfile(os.Stdin)
+	}
+
 	resolvedPath, err := resolveDockerfilePath()
 	if err != nil {
 		return "", "", err
@@ -460,6 +830,23 @@ func readDockerfile() (string, string, error) {
 	return string(buf), parts[1], nil
 }
 
+// readStdinDockerfile reads the Dockerfile content piped on stdin and
+// returns it with its sha256sum, enforcing the usual file size limit
+func readStdinDockerfile(in io.Reader) (string, string, error) {
+	buf, err := ioutil.ReadAll(io.LimitReader(in, constant.MaxFileSize+1))
+	if err != nil {
+		return "", "", errors.Wrap(err, "read Dockerfile from stdin failed")
+	}
+	if int64(len(buf)) > constant.MaxFileSize {
+		return "", "", errors.Errorf("Dockerfile from stdin exceeds the file size limit %d", constant.MaxFileSize)
+	}
+	if len(buf) == 0 {
+		return "", "", errors.New("empty Dockerfile from stdin")
+	}
+
+	return string(buf), digest.Canonical.FromBytes(buf).Encoded(), nil
+}
+
 func resolveDockerfilePath() (string, error) {
 	var resolvedPath = buildOpts.file
 	var err error
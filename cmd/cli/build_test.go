@@ -14,6 +14,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"io/ioutil"
@@ -21,6 +22,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/containers/storage/pkg/archive"
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/fs"
 
@@ -485,6 +487,21 @@ func TestCheckAndProcessOut(t *testing.T) {
 			output: "isulad:isula:latest",
 			isErr:  false,
 		},
+		{
+			name:   "valid containerd transport",
+			output: "containerd:isula:latest",
+			isErr:  false,
+		},
+		{
+			name:   "valid containerd transport with namespace",
+			output: "containerd:k8s.io:isula:latest",
+			isErr:  false,
+		},
+		{
+			name:   "valid containers-storage transport",
+			output: "containers-storage:isula:latest",
+			isErr:  false,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -649,3 +666,305 @@ func TestRunBuildWithCap(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckAndProcessGraphOut(t *testing.T) {
+	defer func() {
+		buildOpts.graphOutFile, buildOpts.graphFormat = "", ""
+	}()
+
+	buildOpts.graphOutFile = ""
+	err := checkAndProcessGraphOut()
+	assert.NilError(t, err)
+	assert.Equal(t, buildOpts.graphFormat, "")
+
+	buildOpts.graphOutFile = "/tmp/build-graph.json"
+	err = checkAndProcessGraphOut()
+	assert.NilError(t, err)
+	assert.Equal(t, buildOpts.graphFormat, "json")
+
+	buildOpts.graphOutFile = "/tmp/build-graph.dot"
+	err = checkAndProcessGraphOut()
+	assert.NilError(t, err)
+	assert.Equal(t, buildOpts.graphFormat, "dot")
+
+	buildOpts.graphOutFile = "/tmp/build-graph.yaml"
+	err = checkAndProcessGraphOut()
+	assert.ErrorContains(t, err, "must end with .json or .dot")
+}
+
+func TestUnpackStdinContext(t *testing.T) {
+	srcDir := fs.NewDir(t, t.Name(), fs.WithFile("Dockerfile", "FROM scratch\n"))
+	defer srcDir.Remove()
+	reader, err := archive.Tar(srcDir.Path(), archive.Uncompressed)
+	assert.NilError(t, err)
+	tarball, err := ioutil.ReadAll(reader)
+	assert.NilError(t, err)
+
+	contextDir, err := unpackStdinContext(bytes.NewReader(tarball))
+	assert.NilError(t, err)
+	defer os.RemoveAll(contextDir)
+	content, err := ioutil.ReadFile(filepath.Join(contextDir, "Dockerfile"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "FROM scratch\n")
+
+	_, err = unpackStdinContext(bytes.NewReader(nil))
+	assert.ErrorContains(t, err, "empty input from stdin")
+}
+
+func TestReadStdinDockerfile(t *testing.T) {
+	content, hash, err := readStdinDockerfile(bytes.NewReader([]byte("FROM scratch\n")))
+	assert.NilError(t, err)
+	assert.Equal(t, content, "FROM scratch\n")
+	assert.Equal(t, len(hash), 64)
+
+	_, _, err = readStdinDockerfile(bytes.NewReader(nil))
+	assert.ErrorContains(t, err, "empty Dockerfile from stdin")
+
+	_, _, err = readStdinDockerfile(bytes.NewReader(bytes.Repeat([]byte("a"), constant.MaxFileSize+1)))
+	assert.ErrorContains(t, err, "exceeds the file size limit")
+}
+
+func TestCheckUsernsOpts(t *testing.T) {
+	testcases := []struct {
+		name      string
+		userns    string
+		uidMap    []string
+		gidMap    []string
+		wantErr   bool
+		errString string
+	}{
+		{
+			name: "TC1 no userns options",
+		},
+		{
+			name:   "TC2 userns auto",
+			userns: "auto",
+		},
+		{
+			name:   "TC3 explicit uid and gid maps",
+			uidMap: []string{"0:100000:65536"},
+			gidMap: []string{"0:200000:65536"},
+		},
+		{
+			name:      "TC4 auto conflicts with explicit maps",
+			userns:    "auto",
+			gidMap:    []string{"0:100000:65536"},
+			wantErr:   true,
+			errString: "conflicts with explicit",
+		},
+		{
+			name:      "TC5 unknown userns mode",
+			userns:    "keep-id",
+			wantErr:   true,
+			errString: "invalid userns mode",
+		},
+		{
+			name:      "TC6 gid map without uid map",
+			gidMap:    []string{"0:100000:65536"},
+			wantErr:   true,
+			errString: "needs a --userns-uid-map",
+		},
+		{
+			name:      "TC7 malformed map triple",
+			uidMap:    []string{"0:abc:65536"},
+			wantErr:   true,
+			errString: "userns-uid-map",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			buildOpts.userns = tc.userns
+			buildOpts.usernsUIDMap = tc.uidMap
+			buildOpts.usernsGIDMap = tc.gidMap
+			defer func() {
+				buildOpts.userns = ""
+				buildOpts.usernsUIDMap = nil
+				buildOpts.usernsGIDMap = nil
+			}()
+
+			err := checkUsernsOpts()
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestCheckResourceOpts(t *testing.T) {
+	testcases := []struct {
+		name      string
+		memory    string
+		cpuQuota  int64
+		cpus      float64
+		pidsLimit int64
+		wantErr   bool
+		errString string
+	}{
+		{
+			name: "TC1 no limits",
+		},
+		{
+			name:   "TC2 valid memory size",
+			memory: "512m",
+			cpus:   1.5,
+		},
+		{
+			name:      "TC3 malformed memory size",
+			memory:    "many",
+			wantErr:   true,
+			errString: "invalid memory limit",
+		},
+		{
+			name:      "TC4 cpus conflicts with cpu quota",
+			cpuQuota:  50000,
+			cpus:      2,
+			wantErr:   true,
+			errString: "conflict flags",
+		},
+		{
+			name:      "TC5 negative pids limit",
+			pidsLimit: -1,
+			wantErr:   true,
+			errString: "invalid pids limit",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			buildOpts.memory = tc.memory
+			buildOpts.cpuQuota = tc.cpuQuota
+			buildOpts.cpus = tc.cpus
+			buildOpts.pidsLimit = tc.pidsLimit
+			defer func() {
+				buildOpts.memory = ""
+				buildOpts.memoryBytes = 0
+				buildOpts.cpuQuota = 0
+				buildOpts.cpus = 0
+				buildOpts.pidsLimit = 0
+			}()
+
+			err := checkResourceOpts()
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+				return
+			}
+			assert.NilError(t, err)
+			if tc.memory != "" {
+				assert.Assert(t, buildOpts.memoryBytes > 0)
+			}
+		})
+	}
+}
+
+func TestCheckStorageOpts(t *testing.T) {
+	oldStorageOpts := buildOpts.storageOpts
+	defer func() {
+		buildOpts.storageOpts = oldStorageOpts
+	}()
+
+	var testcases = []struct {
+		name      string
+		opts      []string
+		wantErr   bool
+		errString string
+	}{
+		{
+			name: "TC1 - no options",
+			opts: nil,
+		},
+		{
+			name: "TC2 - size quota",
+			opts: []string{"size=20G"},
+		},
+		{
+			name:      "TC3 - missing value",
+			opts:      []string{"size"},
+			wantErr:   true,
+			errString: "expected size=<limit>",
+		},
+		{
+			name:      "TC4 - unsupported option",
+			opts:      []string{"inodes=100"},
+			wantErr:   true,
+			errString: "unsupported storage option",
+		},
+		{
+			name:      "TC5 - malformed size",
+			opts:      []string{"size=abc"},
+			wantErr:   true,
+			errString: "invalid storage size",
+		},
+		{
+			name:      "TC6 - zero size",
+			opts:      []string{"size=0"},
+			wantErr:   true,
+			errString: "must be positive",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			buildOpts.storageOpts = tc.opts
+			err := checkStorageOpts()
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestCheckTimeoutOpt(t *testing.T) {
+	oldTimeout := buildOpts.timeout
+	defer func() {
+		buildOpts.timeout = oldTimeout
+	}()
+
+	var testcases = []struct {
+		name      string
+		timeout   string
+		wantErr   bool
+		errString string
+	}{
+		{
+			name:    "TC1 - empty uses the daemon default",
+			timeout: "",
+		},
+		{
+			name:    "TC2 - valid duration",
+			timeout: "1h30m",
+		},
+		{
+			name:      "TC3 - malformed duration",
+			timeout:   "soon",
+			wantErr:   true,
+			errString: "invalid build timeout",
+		},
+		{
+			name:      "TC4 - zero duration",
+			timeout:   "0s",
+			wantErr:   true,
+			errString: "must be positive",
+		},
+		{
+			name:      "TC5 - negative duration",
+			timeout:   "-10m",
+			wantErr:   true,
+			errString: "must be positive",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			buildOpts.timeout = tc.timeout
+			err := checkTimeoutOpt()
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-01
+// Description: This file is used for builder command
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bndr/gotabulate"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+type builderCreateOptions struct {
+	buildArgs  []string
+	capAddList []string
+	proxy      bool
+}
+
+var builderCreateOpts builderCreateOptions
+
+const (
+	builderCreateExample = `isula-build builder create mybuilder
+isula-build builder create mybuilder --build-arg http_proxy=http://proxy.example.com --cap-add CAP_SYS_ADMIN`
+	builderLsExample = `isula-build builder ls`
+	builderRmExample = `isula-build builder rm mybuilder`
+	// when builder list is empty, only print this head
+	emptyBuilderStr = `----   ----------   -------   -----   -------
+NAME   BUILD ARGS   CAP ADD   PROXY   CREATED
+----   ----------   -------   -----   -------`
+)
+
+// NewBuilderCmd returns builder instance operations commands
+func NewBuilderCmd() *cobra.Command {
+	builderCmd := &cobra.Command{
+		Use:   "builder",
+		Short: "Manage named builder instances of isula-builder",
+	}
+	builderCmd.AddCommand(
+		NewBuilderCreateCmd(),
+		NewBuilderListCmd(),
+		NewBuilderRemoveCmd(),
+	)
+
+	return builderCmd
+}
+
+// NewBuilderCreateCmd returns builder create command
+func NewBuilderCreateCmd() *cobra.Command {
+	builderCreateCmd := &cobra.Command{
+		Use:     "create NAME [FLAGS]",
+		Short:   "Create a named builder instance carrying build defaults",
+		Example: builderCreateExample,
+		RunE:    builderCreateCommand,
+	}
+
+	builderCreateCmd.PersistentFlags().StringArrayVar(&builderCreateOpts.buildArgs, "build-arg", []string{}, "Default arguments used during build time")
+	builderCreateCmd.PersistentFlags().StringArrayVar(&builderCreateOpts.capAddList, "cap-add", []string{}, "Default Linux capabilities for RUN command")
+	builderCreateCmd.PersistentFlags().BoolVar(&builderCreateOpts.proxy, "proxy", true, "Inherit proxy environment variables from host")
+
+	return builderCreateCmd
+}
+
+// NewBuilderListCmd returns builder ls command
+func NewBuilderListCmd() *cobra.Command {
+	builderListCmd := &cobra.Command{
+		Use:                   "ls",
+		Short:                 "List named builder instances",
+		Example:               builderLsExample,
+		RunE:                  builderListCommand,
+		DisableFlagsInUseLine: true,
+	}
+
+	return builderListCmd
+}
+
+// NewBuilderRemoveCmd returns builder rm command
+func NewBuilderRemoveCmd() *cobra.Command {
+	builderRemoveCmd := &cobra.Command{
+		Use:                   "rm NAME",
+		Short:                 "Remove a named builder instance",
+		Example:               builderRmExample,
+		RunE:                  builderRemoveCommand,
+		DisableFlagsInUseLine: true,
+	}
+
+	return builderRemoveCmd
+}
+
+func builderCreateCommand(c *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("isula-build builder create requires exactly one builder name")
+	}
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runBuilderCreate(ctx, cli, args[0])
+}
+
+func runBuilderCreate(ctx context.Context, cli Cli, name string) error {
+	resp, err := cli.Client().BuilderCreate(ctx, &pb.BuilderCreateRequest{
+		Name:       name,
+		BuildArgs:  builderCreateOpts.buildArgs,
+		CapAddList: builderCreateOpts.capAddList,
+		Proxy:      builderCreateOpts.proxy,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Builder instance %s created\n", resp.Name)
+
+	return nil
+}
+
+func builderListCommand(c *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return errors.New("isula-build builder ls requires no argument")
+	}
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runBuilderList(ctx, cli)
+}
+
+func runBuilderList(ctx context.Context, cli Cli) error {
+	resp, err := cli.Client().BuilderList(ctx, &pb.BuilderListRequest{})
+	if err != nil {
+		return err
+	}
+
+	lines := make([][]string, 0, len(resp.Builders))
+	title := []string{"NAME", "BUILD ARGS", "CAP ADD", "PROXY", "CREATED"}
+	for _, builder := range resp.Builders {
+		if builder == nil {
+			continue
+		}
+		lines = append(lines, []string{
+			builder.Name,
+			strings.Join(builder.BuildArgs, ","),
+			strings.Join(builder.CapAddList, ","),
+			fmt.Sprintf("%t", builder.Proxy),
+			builder.Created,
+		})
+	}
+	if len(lines) == 0 {
+		fmt.Println(emptyBuilderStr)
+		return nil
+	}
+	tabulate := gotabulate.Create(lines)
+	tabulate.SetHeaders(title)
+	tabulate.SetAlign("left")
+	tabulate.SetDenseMode()
+	fmt.Print(tabulate.Render("simple"))
+
+	return nil
+}
+
+func builderRemoveCommand(c *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("isula-build builder rm requires exactly one builder name")
+	}
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runBuilderRemove(ctx, cli, args[0])
+}
+
+func runBuilderRemove(ctx context.Context, cli Cli, name string) error {
+	resp, err := cli.Client().BuilderRemove(ctx, &pb.BuilderRemoveRequest{
+		Name: name,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Builder instance %s removed\n", resp.Name)
+
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-01
+// Description: This file is used for testing command builder
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBuilderCreateCommand(t *testing.T) {
+	builderCreateCmd := NewBuilderCreateCmd()
+	err := builderCreateCommand(builderCreateCmd, []string{})
+	assert.ErrorContains(t, err, "exactly one builder name")
+
+	err = builderCreateCommand(builderCreateCmd, []string{"mybuilder", "otherbuilder"})
+	assert.ErrorContains(t, err, "exactly one builder name")
+
+	err = builderCreateCommand(builderCreateCmd, []string{"mybuilder"})
+	assert.ErrorContains(t, err, "isula_build.sock")
+}
+
+func TestBuilderListCommand(t *testing.T) {
+	builderListCmd := NewBuilderListCmd()
+	err := builderListCommand(builderListCmd, []string{"abc"})
+	assert.ErrorContains(t, err, "requires no argument")
+
+	err = builderListCommand(builderListCmd, []string{})
+	assert.ErrorContains(t, err, "isula_build.sock")
+}
+
+func TestBuilderRemoveCommand(t *testing.T) {
+	builderRemoveCmd := NewBuilderRemoveCmd()
+	err := builderRemoveCommand(builderRemoveCmd, []string{})
+	assert.ErrorContains(t, err, "exactly one builder name")
+
+	err = builderRemoveCommand(builderRemoveCmd, []string{"mybuilder"})
+	assert.ErrorContains(t, err, "isula_build.sock")
+}
+
+func TestRunBuilderCreate(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	err := runBuilderCreate(ctx, &cli, "mybuilder")
+	assert.NilError(t, err)
+}
+
+func TestRunBuilderList(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	err := runBuilderList(ctx, &cli)
+	assert.NilError(t, err)
+}
+
+func TestRunBuilderRemove(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	err := runBuilderRemove(ctx, &cli, "mybuilder")
+	assert.NilError(t, err)
+}
@@ -0,0 +1,201 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: This file is used for builds command
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/bndr/gotabulate"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+const (
+	buildsLsExample   = `isula-build builds ls`
+	buildsLogsExample = `isula-build builds logs 12345678
+isula-build builds logs 12345678 --follow
+isula-build builds logs 12345678 --since 100`
+	// when builds list is empty, only print this head
+	emptyBuildsStr = `--------   -------   --------   -----   --------
+BUILD ID   STARTED   DURATION   STAGE   TMP SIZE
+--------   -------   --------   -----   --------`
+)
+
+// NewBuildsCmd returns running builds operations commands
+func NewBuildsCmd() *cobra.Command {
+	buildsCmd := &cobra.Command{
+		Use:   "builds",
+		Short: "Manage builds running in isula-builder",
+	}
+	buildsCmd.AddCommand(
+		NewBuildsListCmd(),
+		NewBuildsLogsCmd(),
+		NewRecordsExportCmd(),
+		NewRecordsImportCmd(),
+	)
+
+	return buildsCmd
+}
+
+// NewBuildsListCmd returns builds ls command
+func NewBuildsListCmd() *cobra.Command {
+	buildsListCmd := &cobra.Command{
+		Use:                   "ls",
+		Short:                 "List builds currently running in isula-builder",
+		Example:               buildsLsExample,
+		RunE:                  buildsListCommand,
+		DisableFlagsInUseLine: true,
+	}
+
+	return buildsListCmd
+}
+
+type buildsLogsOptions struct {
+	follow bool
+	since  int64
+}
+
+var buildsLogsOpts buildsLogsOptions
+
+// NewBuildsLogsCmd returns builds logs command
+func NewBuildsLogsCmd() *cobra.Command {
+	buildsLogsCmd := &cobra.Command{
+		Use:     "logs BUILD-ID",
+		Short:   "Print the log of a build running in isula-builder",
+		Example: buildsLogsExample,
+		RunE:    buildsLogsCommand,
+	}
+
+	buildsLogsCmd.PersistentFlags().BoolVarP(&buildsLogsOpts.follow, "follow", "f", false, "Keep streaming new log lines until the build finishes")
+	buildsLogsCmd.PersistentFlags().Int64Var(&buildsLogsOpts.since, "since", 0, "Replay the log from the given line offset, 0 is the beginning")
+
+	return buildsLogsCmd
+}
+
+func buildsLogsCommand(c *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("builds logs requires one argument")
+	}
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runBuildsLogs(ctx, cli, args[0])
+}
+
+func runBuildsLogs(ctx context.Context, cli Cli, buildID string) error {
+	status, err := cli.Client().Status(ctx, &pb.StatusRequest{
+		BuildID:     buildID,
+		StartOffset: buildsLogsOpts.since,
+		Follow:      buildsLogsOpts.follow,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := status.Recv()
+		if msg != nil {
+			fmt.Print(msg.Content)
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func buildsListCommand(c *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return errors.New("isula-build builds ls requires no argument")
+	}
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runBuildsList(ctx, cli)
+}
+
+func runBuildsList(ctx context.Context, cli Cli) error {
+	resp, err := cli.Client().BuildsList(ctx, &pb.BuildsListRequest{})
+	if err != nil {
+		return err
+	}
+
+	lines := make([][]string, 0, len(resp.Builds))
+	title := []string{"BUILD ID", "STARTED", "DURATION", "STAGE", "TMP SIZE"}
+	for _, build := range resp.Builds {
+		if build == nil {
+			continue
+		}
+		lines = append(lines, []string{
+			build.BuildID,
+			build.StartTime,
+			build.Duration,
+			build.Stage,
+			build.TmpSize,
+		})
+	}
+	if len(lines) == 0 {
+		fmt.Println(emptyBuildsStr)
+	} else {
+		tabulate := gotabulate.Create(lines)
+		tabulate.SetHeaders(title)
+		tabulate.SetAlign("left")
+		tabulate.SetDenseMode()
+		fmt.Print(tabulate.Render("simple"))
+	}
+
+	printBuildContainers(resp.Containers)
+
+	return nil
+}
+
+func printBuildContainers(containers []*pb.BuildsListResponse_ContainerInfo) {
+	if len(containers) == 0 {
+		return
+	}
+
+	lines := make([][]string, 0, len(containers))
+	title := []string{"CONTAINER NAME", "CONTAINER ID", "IMAGE ID", "CREATED"}
+	for _, container := range containers {
+		if container == nil {
+			continue
+		}
+		lines = append(lines, []string{
+			container.Name,
+			container.Id,
+			container.Image,
+			container.Created,
+		})
+	}
+	fmt.Println("\nBuild Containers:")
+	tabulate := gotabulate.Create(lines)
+	tabulate.SetHeaders(title)
+	tabulate.SetAlign("left")
+	tabulate.SetDenseMode()
+	fmt.Print(tabulate.Render("simple"))
+}
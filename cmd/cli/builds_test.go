@@ -0,0 +1,53 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: This file is used for testing command builds
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBuildsListCommand(t *testing.T) {
+	buildsListCmd := NewBuildsListCmd()
+	err := buildsListCommand(buildsListCmd, []string{"abc"})
+	assert.ErrorContains(t, err, "requires no argument")
+
+	err = buildsListCommand(buildsListCmd, []string{})
+	assert.ErrorContains(t, err, "isula_build.sock")
+}
+
+func TestRunBuildsList(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	err := runBuildsList(ctx, &cli)
+	assert.NilError(t, err)
+}
+
+func TestBuildsLogsCommand(t *testing.T) {
+	buildsLogsCmd := NewBuildsLogsCmd()
+	err := buildsLogsCommand(buildsLogsCmd, nil)
+	assert.ErrorContains(t, err, "requires one argument")
+
+	err = buildsLogsCommand(buildsLogsCmd, []string{"12345678"})
+	assert.ErrorContains(t, err, "isula_build.sock")
+}
+
+func TestRunBuildsLogs(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	err := runBuildsLogs(ctx, &cli, "12345678")
+	assert.NilError(t, err)
+}
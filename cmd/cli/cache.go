@@ -0,0 +1,158 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-01-10
+// Description: This file is used for cache command
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bndr/gotabulate"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+)
+
+type cacheRemoveOptions struct {
+	all bool
+}
+
+var cacheRemoveOpts cacheRemoveOptions
+
+const (
+	cacheLsExample = `isula-build ctr-img cache ls`
+	cacheRmExample = `isula-build ctr-img cache rm KEY
+isula-build ctr-img cache rm --all`
+	// when cache list is empty, only print this head
+	emptyCacheStr = `----   ----   -----   --------   ----------
+KEY    URL    SIZE    CREATED    LAST USED
+----   ----   -----   --------   ----------`
+)
+
+// NewCacheCmd returns cache operations commands
+func NewCacheCmd() *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage url download cache of isula-builder",
+	}
+	cacheCmd.AddCommand(
+		NewCacheListCmd(),
+		NewCacheRemoveCmd(),
+	)
+
+	disableFlags(cacheCmd)
+
+	return cacheCmd
+}
+
+// NewCacheListCmd returns cache ls command
+func NewCacheListCmd() *cobra.Command {
+	cacheListCmd := &cobra.Command{
+		Use:     "ls",
+		Short:   "List cached url downloads",
+		Example: cacheLsExample,
+		RunE:    cacheListCommand,
+	}
+
+	return cacheListCmd
+}
+
+// NewCacheRemoveCmd returns cache rm command
+func NewCacheRemoveCmd() *cobra.Command {
+	cacheRemoveCmd := &cobra.Command{
+		Use:     "rm [KEY...]",
+		Short:   "Remove cached url downloads",
+		Example: cacheRmExample,
+		RunE:    cacheRemoveCommand,
+	}
+	cacheRemoveCmd.PersistentFlags().BoolVarP(&cacheRemoveOpts.all, "all", "a", false, "Remove all cache entries")
+
+	return cacheRemoveCmd
+}
+
+func cacheListCommand(c *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return errors.New("isula-build cache ls requires no argument")
+	}
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runCacheList(ctx, cli)
+}
+
+func runCacheList(ctx context.Context, cli Cli) error {
+	resp, err := cli.Client().CacheList(ctx, &pb.CacheListRequest{})
+	if err != nil {
+		return err
+	}
+
+	lines := make([][]string, 0, len(resp.Caches))
+	title := []string{"KEY", "URL", "SIZE", "CREATED", "LAST USED"}
+	for _, cache := range resp.Caches {
+		if cache == nil {
+			continue
+		}
+		key := cache.Key
+		if len(key) > constant.DefaultIDLen {
+			key = key[:constant.DefaultIDLen]
+		}
+		lines = append(lines, []string{key, cache.Url, cache.Size_, cache.Created, cache.LastUsed})
+	}
+	if len(lines) == 0 {
+		fmt.Println(emptyCacheStr)
+		return nil
+	}
+	tabulate := gotabulate.Create(lines)
+	tabulate.SetHeaders(title)
+	tabulate.SetAlign("left")
+	tabulate.SetDenseMode()
+	fmt.Print(tabulate.Render("simple"))
+
+	return nil
+}
+
+func cacheRemoveCommand(c *cobra.Command, args []string) error {
+	if len(args) == 0 && !cacheRemoveOpts.all {
+		return errors.New("isula-build cache rm requires at least one key, or --all for removing all the cache entries")
+	}
+	if len(args) != 0 && cacheRemoveOpts.all {
+		return errors.New("isula-build cache rm requires at least one key, or --all for removing all the cache entries")
+	}
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runCacheRemove(ctx, cli, args)
+}
+
+func runCacheRemove(ctx context.Context, cli Cli, keys []string) error {
+	resp, err := cli.Client().CacheRemove(ctx, &pb.CacheRemoveRequest{
+		Keys: keys,
+		All:  cacheRemoveOpts.all,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(resp.Result)
+
+	return nil
+}
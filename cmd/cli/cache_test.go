@@ -0,0 +1,58 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-01-10
+// Description: This file is used for testing command cache
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCacheListCommand(t *testing.T) {
+	cacheListCmd := NewCacheListCmd()
+	err := cacheListCommand(cacheListCmd, []string{"abc"})
+	assert.ErrorContains(t, err, "requires no argument")
+
+	err = cacheListCommand(cacheListCmd, []string{})
+	assert.ErrorContains(t, err, "isula_build.sock")
+}
+
+func TestCacheRemoveCommand(t *testing.T) {
+	cacheRemoveCmd := NewCacheRemoveCmd()
+	err := cacheRemoveCommand(cacheRemoveCmd, []string{})
+	assert.ErrorContains(t, err, "requires at least one key")
+
+	cacheRemoveOpts.all = true
+	err = cacheRemoveCommand(cacheRemoveCmd, []string{"abc"})
+	assert.ErrorContains(t, err, "requires at least one key")
+	cacheRemoveOpts.all = false
+
+	err = cacheRemoveCommand(cacheRemoveCmd, []string{"abc"})
+	assert.ErrorContains(t, err, "isula_build.sock")
+}
+
+func TestRunCacheList(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	err := runCacheList(ctx, &cli)
+	assert.NilError(t, err)
+}
+
+func TestRunCacheRemove(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	err := runCacheRemove(ctx, &cli, []string{"abc"})
+	assert.NilError(t, err)
+}
@@ -0,0 +1,227 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for "config" command
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	constant "isula.org/isula-build"
+)
+
+const (
+	// clientConfigDirName is the client configuration dir under the
+	// user configuration directory
+	clientConfigDirName = "isula-build"
+	// clientConfigFileName stores the per-command flag defaults
+	clientConfigFileName = "config.yaml"
+
+	configExample = `isula-build config set format docker
+isula-build config get format
+isula-build config get`
+)
+
+// clientConfigKeys lists the supported default keys, each one fills
+// the flag of the same name when the command defines it and the flag
+// is not given explicitly
+var clientConfigKeys = []string{"format", "platform", "progress", "registry"}
+
+// clientConfigPath returns the client config path of the calling user
+func clientConfigPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.Wrap(err, "resolve user config directory failed")
+	}
+
+	return filepath.Join(configDir, clientConfigDirName, clientConfigFileName), nil
+}
+
+// loadClientConfig reads the flag defaults, a missing file is an
+// empty configuration
+func loadClientConfig() (map[string]string, error) {
+	path, err := clientConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, errors.Wrapf(err, "read client config %q failed", path)
+	}
+
+	conf := map[string]string{}
+	if err := yaml.Unmarshal(data, &conf); err != nil {
+		return nil, errors.Wrapf(err, "parse client config %q failed", path)
+	}
+
+	return conf, nil
+}
+
+// saveClientConfig writes the flag defaults back to the user config
+func saveClientConfig(conf map[string]string) error {
+	path, err := clientConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), constant.DefaultRootDirMode); err != nil {
+		return errors.Wrapf(err, "create config directory %q failed", filepath.Dir(path))
+	}
+	data, err := yaml.Marshal(conf)
+	if err != nil {
+		return errors.Wrap(err, "marshal client config failed")
+	}
+
+	return ioutil.WriteFile(path, data, constant.DefaultRootFileMode)
+}
+
+// isClientConfigKey reports whether the key is a supported default
+func isClientConfigKey(key string) bool {
+	for _, supported := range clientConfigKeys {
+		if key == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyClientConfig fills the flags of the command with the configured
+// defaults, explicit command line flags keep the higher priority
+func applyClientConfig(cmd *cobra.Command) error {
+	conf, err := loadClientConfig()
+	if err != nil {
+		return err
+	}
+
+	for key, value := range conf {
+		if value == "" || !isClientConfigKey(key) {
+			continue
+		}
+		flag := cmd.Flag(key)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return errors.Wrapf(err, "apply configured default %s=%q failed", key, value)
+		}
+	}
+
+	return nil
+}
+
+// NewConfigCmd returns config operations commands
+func NewConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:     "config",
+		Short:   "Manage per-command flag defaults of the client",
+		Example: configExample,
+	}
+	configCmd.AddCommand(
+		NewConfigSetCmd(),
+		NewConfigGetCmd(),
+	)
+
+	return configCmd
+}
+
+// NewConfigSetCmd returns config set command
+func NewConfigSetCmd() *cobra.Command {
+	configSetCmd := &cobra.Command{
+		Use:                   "set KEY VALUE",
+		Short:                 "Set the default value of a flag",
+		RunE:                  configSetCommand,
+		DisableFlagsInUseLine: true,
+	}
+
+	return configSetCmd
+}
+
+func configSetCommand(c *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return errors.New("config set requires a key and a value")
+	}
+	key, value := args[0], args[1]
+	if !isClientConfigKey(key) {
+		return errors.Errorf("unknown config key %q, supported keys: %v", key, clientConfigKeys)
+	}
+
+	conf, err := loadClientConfig()
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		delete(conf, key)
+	} else {
+		conf[key] = value
+	}
+	if err := saveClientConfig(conf); err != nil {
+		return err
+	}
+
+	fmt.Printf("Set %s to %q\n", key, value)
+
+	return nil
+}
+
+// NewConfigGetCmd returns config get command
+func NewConfigGetCmd() *cobra.Command {
+	configGetCmd := &cobra.Command{
+		Use:                   "get [KEY]",
+		Short:                 "Print the configured flag defaults",
+		RunE:                  configGetCommand,
+		DisableFlagsInUseLine: true,
+	}
+
+	return configGetCmd
+}
+
+func configGetCommand(c *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return errors.New("config get takes at most one argument")
+	}
+
+	conf, err := loadClientConfig()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		key := args[0]
+		if !isClientConfigKey(key) {
+			return errors.Errorf("unknown config key %q, supported keys: %v", key, clientConfigKeys)
+		}
+		fmt.Println(conf[key])
+		return nil
+	}
+
+	keys := make([]string, 0, len(conf))
+	for key := range conf {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("%s = %s\n", key, conf[key])
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing config command
+
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+// withTmpConfigHome points the client config at a temporary directory
+func withTmpConfigHome(t *testing.T) func() {
+	tmpDir := fs.NewDir(t, t.Name())
+	oriConfigHome, hadConfigHome := os.LookupEnv("XDG_CONFIG_HOME")
+	assert.NilError(t, os.Setenv("XDG_CONFIG_HOME", tmpDir.Path()))
+
+	return func() {
+		if hadConfigHome {
+			assert.NilError(t, os.Setenv("XDG_CONFIG_HOME", oriConfigHome))
+		} else {
+			assert.NilError(t, os.Unsetenv("XDG_CONFIG_HOME"))
+		}
+		tmpDir.Remove()
+	}
+}
+
+func TestConfigSetAndGetCommand(t *testing.T) {
+	cleanup := withTmpConfigHome(t)
+	defer cleanup()
+
+	err := configSetCommand(NewConfigSetCmd(), []string{"format"})
+	assert.ErrorContains(t, err, "config set requires a key and a value")
+
+	err = configSetCommand(NewConfigSetCmd(), []string{"color", "red"})
+	assert.ErrorContains(t, err, `unknown config key "color"`)
+
+	assert.NilError(t, configSetCommand(NewConfigSetCmd(), []string{"format", "docker"}))
+
+	conf, err := loadClientConfig()
+	assert.NilError(t, err)
+	assert.Equal(t, conf["format"], "docker")
+
+	err = configGetCommand(NewConfigGetCmd(), []string{"format", "platform"})
+	assert.ErrorContains(t, err, "config get takes at most one argument")
+
+	err = configGetCommand(NewConfigGetCmd(), []string{"color"})
+	assert.ErrorContains(t, err, `unknown config key "color"`)
+
+	assert.NilError(t, configGetCommand(NewConfigGetCmd(), []string{"format"}))
+	assert.NilError(t, configGetCommand(NewConfigGetCmd(), nil))
+
+	// setting the empty value removes the key
+	assert.NilError(t, configSetCommand(NewConfigSetCmd(), []string{"format", ""}))
+	conf, err = loadClientConfig()
+	assert.NilError(t, err)
+	_, ok := conf["format"]
+	assert.Equal(t, ok, false)
+}
+
+func TestApplyClientConfig(t *testing.T) {
+	cleanup := withTmpConfigHome(t)
+	defer cleanup()
+
+	assert.NilError(t, configSetCommand(NewConfigSetCmd(), []string{"format", "docker"}))
+	assert.NilError(t, configSetCommand(NewConfigSetCmd(), []string{"platform", "linux/arm64"}))
+
+	var format, platform string
+	cmd := &cobra.Command{Use: "test"}
+	cmd.PersistentFlags().StringVar(&format, "format", "oci", "")
+	cmd.PersistentFlags().StringVar(&platform, "platform", "", "")
+
+	// the configured defaults fill the unset flags
+	assert.NilError(t, applyClientConfig(cmd))
+	assert.Equal(t, format, "docker")
+	assert.Equal(t, platform, "linux/arm64")
+
+	// explicit flags keep the higher priority
+	assert.NilError(t, cmd.PersistentFlags().Set("format", "oci"))
+	assert.NilError(t, applyClientConfig(cmd))
+	assert.Equal(t, format, "oci")
+
+	// commands without the flag are not touched
+	plain := &cobra.Command{Use: "plain"}
+	assert.NilError(t, applyClientConfig(plain))
+}
@@ -0,0 +1,329 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for "context" command
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bndr/gotabulate"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	constant "isula.org/isula-build"
+)
+
+const (
+	// contextsDirName is the per-user client configuration dir
+	contextsDirName = ".isula-build"
+	// contextsFileName stores the named daemon contexts
+	contextsFileName = "contexts.json"
+
+	contextExample = `isula-build context create remote --addr unix:///var/run/other_build.sock
+isula-build context use remote
+isula-build context ls
+isula-build context rm remote`
+)
+
+// cliContext is the connection setting of one named daemon context
+type cliContext struct {
+	Addr    string `json:"addr"`
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// cliContexts is the persisted context store of the calling user
+type cliContexts struct {
+	Current  string                `json:"current,omitempty"`
+	Contexts map[string]cliContext `json:"contexts,omitempty"`
+}
+
+type contextCreateOptions struct {
+	addr    string
+	timeout string
+}
+
+var contextCreateOpts contextCreateOptions
+
+// contextsFilePath returns the context store path of the calling user
+func contextsFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "resolve home directory failed")
+	}
+
+	return filepath.Join(home, contextsDirName, contextsFileName), nil
+}
+
+// loadContexts reads the context store, a missing file is an empty store
+func loadContexts() (cliContexts, error) {
+	path, err := contextsFilePath()
+	if err != nil {
+		return cliContexts{}, err
+	}
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cliContexts{}, nil
+		}
+		return cliContexts{}, errors.Wrapf(err, "read contexts file %q failed", path)
+	}
+
+	var ctxs cliContexts
+	if err := json.Unmarshal(data, &ctxs); err != nil {
+		return cliContexts{}, errors.Wrapf(err, "parse contexts file %q failed", path)
+	}
+
+	return ctxs, nil
+}
+
+// saveContexts writes the context store back to the user config
+func saveContexts(ctxs cliContexts) error {
+	path, err := contextsFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), constant.DefaultRootDirMode); err != nil {
+		return errors.Wrapf(err, "create config directory %q failed", filepath.Dir(path))
+	}
+	data, err := json.MarshalIndent(ctxs, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "marshal contexts failed")
+	}
+
+	return ioutil.WriteFile(path, data, constant.DefaultRootFileMode)
+}
+
+// applyContext overrides the connection options with the selected
+// context, explicit command line flags keep the higher priority
+func applyContext(cmd *cobra.Command) error {
+	name := cliOpts.Context
+	ctxs, err := loadContexts()
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = ctxs.Current
+	}
+	if name == "" {
+		return nil
+	}
+
+	selected, ok := ctxs.Contexts[name]
+	if !ok {
+		return errors.Errorf("context %q does not exist", name)
+	}
+	if addrFlag := cmd.Flag("addr"); addrFlag != nil && !addrFlag.Changed && selected.Addr != "" {
+		cliOpts.Addr = selected.Addr
+	}
+	if timeoutFlag := cmd.Flag("timeout"); timeoutFlag != nil && !timeoutFlag.Changed && selected.Timeout != "" {
+		cliOpts.Timeout = selected.Timeout
+	}
+
+	return nil
+}
+
+// NewContextCmd returns context operations commands
+func NewContextCmd() *cobra.Command {
+	contextCmd := &cobra.Command{
+		Use:     "context",
+		Short:   "Manage named daemon contexts",
+		Example: contextExample,
+	}
+	contextCmd.AddCommand(
+		NewContextCreateCmd(),
+		NewContextListCmd(),
+		NewContextUseCmd(),
+		NewContextRemoveCmd(),
+	)
+
+	return contextCmd
+}
+
+// NewContextCreateCmd returns context create command
+func NewContextCreateCmd() *cobra.Command {
+	contextCreateCmd := &cobra.Command{
+		Use:   "create NAME FLAGS",
+		Short: "Create a named daemon context",
+		RunE:  contextCreateCommand,
+	}
+
+	contextCreateCmd.PersistentFlags().StringVar(&contextCreateOpts.addr, "addr", "", "Unix socket address of the daemon")
+	contextCreateCmd.PersistentFlags().StringVar(&contextCreateOpts.timeout, "timeout", "", "Timeout for connecting to the daemon")
+
+	return contextCreateCmd
+}
+
+func contextCreateCommand(c *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("context create requires exactly one argument")
+	}
+	name := args[0]
+	if contextCreateOpts.addr == "" {
+		return errors.New("daemon address(--addr) should not be empty")
+	}
+	if !strings.HasPrefix(contextCreateOpts.addr, constant.UnixPrefix) {
+		return errors.Errorf("daemon address %q must be a unix:// socket address", contextCreateOpts.addr)
+	}
+	if contextCreateOpts.timeout != "" {
+		if _, err := getStartTimeout(contextCreateOpts.timeout); err != nil {
+			return err
+		}
+	}
+
+	ctxs, err := loadContexts()
+	if err != nil {
+		return err
+	}
+	if _, ok := ctxs.Contexts[name]; ok {
+		return errors.Errorf("context %q already exists", name)
+	}
+	if ctxs.Contexts == nil {
+		ctxs.Contexts = make(map[string]cliContext, 1)
+	}
+	ctxs.Contexts[name] = cliContext{Addr: contextCreateOpts.addr, Timeout: contextCreateOpts.timeout}
+	if err := saveContexts(ctxs); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created context %s\n", name)
+
+	return nil
+}
+
+// NewContextListCmd returns context ls command
+func NewContextListCmd() *cobra.Command {
+	contextListCmd := &cobra.Command{
+		Use:                   "ls",
+		Short:                 "List the named daemon contexts",
+		RunE:                  contextListCommand,
+		DisableFlagsInUseLine: true,
+	}
+
+	return contextListCmd
+}
+
+func contextListCommand(c *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return errors.New("isula-build context ls requires no argument")
+	}
+
+	ctxs, err := loadContexts()
+	if err != nil {
+		return err
+	}
+	if len(ctxs.Contexts) == 0 {
+		fmt.Println("No context configured")
+		return nil
+	}
+
+	names := make([]string, 0, len(ctxs.Contexts))
+	for name := range ctxs.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([][]string, 0, len(names))
+	for _, name := range names {
+		current := ""
+		if name == ctxs.Current {
+			current = "*"
+		}
+		lines = append(lines, []string{name, current, ctxs.Contexts[name].Addr, ctxs.Contexts[name].Timeout})
+	}
+	tabulate := gotabulate.Create(lines)
+	tabulate.SetHeaders([]string{"NAME", "CURRENT", "ADDR", "TIMEOUT"})
+	tabulate.SetAlign("left")
+	tabulate.SetDenseMode()
+	fmt.Print(tabulate.Render("simple"))
+
+	return nil
+}
+
+// NewContextUseCmd returns context use command
+func NewContextUseCmd() *cobra.Command {
+	contextUseCmd := &cobra.Command{
+		Use:                   "use NAME",
+		Short:                 "Set the current daemon context",
+		RunE:                  contextUseCommand,
+		DisableFlagsInUseLine: true,
+	}
+
+	return contextUseCmd
+}
+
+func contextUseCommand(c *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("context use requires exactly one argument")
+	}
+	name := args[0]
+
+	ctxs, err := loadContexts()
+	if err != nil {
+		return err
+	}
+	if _, ok := ctxs.Contexts[name]; !ok {
+		return errors.Errorf("context %q does not exist", name)
+	}
+	ctxs.Current = name
+	if err := saveContexts(ctxs); err != nil {
+		return err
+	}
+
+	fmt.Printf("Current context is now %s\n", name)
+
+	return nil
+}
+
+// NewContextRemoveCmd returns context rm command
+func NewContextRemoveCmd() *cobra.Command {
+	contextRemoveCmd := &cobra.Command{
+		Use:                   "rm NAME",
+		Short:                 "Remove a named daemon context",
+		RunE:                  contextRemoveCommand,
+		DisableFlagsInUseLine: true,
+	}
+
+	return contextRemoveCmd
+}
+
+func contextRemoveCommand(c *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("context rm requires exactly one argument")
+	}
+	name := args[0]
+
+	ctxs, err := loadContexts()
+	if err != nil {
+		return err
+	}
+	if _, ok := ctxs.Contexts[name]; !ok {
+		return errors.Errorf("context %q does not exist", name)
+	}
+	delete(ctxs.Contexts, name)
+	if ctxs.Current == name {
+		ctxs.Current = ""
+	}
+	if err := saveContexts(ctxs); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed context %s\n", name)
+
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing context command
+
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+// withTmpHome points the context store at a temporary home directory
+func withTmpHome(t *testing.T) func() {
+	tmpDir := fs.NewDir(t, t.Name())
+	oriHome := os.Getenv("HOME")
+	assert.NilError(t, os.Setenv("HOME", tmpDir.Path()))
+
+	return func() {
+		assert.NilError(t, os.Setenv("HOME", oriHome))
+		tmpDir.Remove()
+	}
+}
+
+func TestContextCreateCommand(t *testing.T) {
+	cleanup := withTmpHome(t)
+	defer cleanup()
+	createCmd := NewContextCreateCmd()
+
+	err := contextCreateCommand(createCmd, nil)
+	assert.ErrorContains(t, err, "context create requires exactly one argument")
+
+	contextCreateOpts.addr = ""
+	err = contextCreateCommand(createCmd, []string{"remote"})
+	assert.ErrorContains(t, err, "daemon address(--addr) should not be empty")
+
+	contextCreateOpts.addr = "tcp://127.0.0.1:2375"
+	err = contextCreateCommand(createCmd, []string{"remote"})
+	assert.ErrorContains(t, err, "must be a unix:// socket address")
+
+	contextCreateOpts.addr = "unix:///var/run/other_build.sock"
+	contextCreateOpts.timeout = "1ns"
+	err = contextCreateCommand(createCmd, []string{"remote"})
+	assert.ErrorContains(t, err, "invalid timeout value")
+
+	contextCreateOpts.timeout = ""
+	assert.NilError(t, contextCreateCommand(createCmd, []string{"remote"}))
+	err = contextCreateCommand(createCmd, []string{"remote"})
+	assert.ErrorContains(t, err, `context "remote" already exists`)
+
+	contextCreateOpts.addr = ""
+}
+
+func TestContextUseAndRemoveCommand(t *testing.T) {
+	cleanup := withTmpHome(t)
+	defer cleanup()
+
+	err := contextUseCommand(NewContextUseCmd(), []string{"remote"})
+	assert.ErrorContains(t, err, `context "remote" does not exist`)
+
+	createCmd := NewContextCreateCmd()
+	contextCreateOpts.addr = "unix:///var/run/other_build.sock"
+	assert.NilError(t, contextCreateCommand(createCmd, []string{"remote"}))
+	contextCreateOpts.addr = ""
+
+	assert.NilError(t, contextUseCommand(NewContextUseCmd(), []string{"remote"}))
+	ctxs, err := loadContexts()
+	assert.NilError(t, err)
+	assert.Equal(t, ctxs.Current, "remote")
+
+	assert.NilError(t, contextListCommand(NewContextListCmd(), nil))
+
+	assert.NilError(t, contextRemoveCommand(NewContextRemoveCmd(), []string{"remote"}))
+	ctxs, err = loadContexts()
+	assert.NilError(t, err)
+	assert.Equal(t, ctxs.Current, "")
+	assert.Equal(t, len(ctxs.Contexts), 0)
+
+	err = contextRemoveCommand(NewContextRemoveCmd(), []string{"remote"})
+	assert.ErrorContains(t, err, `context "remote" does not exist`)
+}
+
+func TestApplyContext(t *testing.T) {
+	cleanup := withTmpHome(t)
+	defer cleanup()
+
+	createCmd := NewContextCreateCmd()
+	contextCreateOpts.addr = "unix:///var/run/other_build.sock"
+	contextCreateOpts.timeout = "30s"
+	assert.NilError(t, contextCreateCommand(createCmd, []string{"remote"}))
+	contextCreateOpts.addr = ""
+	contextCreateOpts.timeout = ""
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.PersistentFlags().StringVar(&cliOpts.Addr, "addr", "", "")
+	cmd.PersistentFlags().StringVar(&cliOpts.Timeout, "timeout", "", "")
+
+	// an unknown context is rejected
+	cliOpts.Context = "missing"
+	err := applyContext(cmd)
+	assert.ErrorContains(t, err, `context "missing" does not exist`)
+
+	// the selected context fills the unset connection options
+	cliOpts.Context = "remote"
+	assert.NilError(t, applyContext(cmd))
+	assert.Equal(t, cliOpts.Addr, "unix:///var/run/other_build.sock")
+	assert.Equal(t, cliOpts.Timeout, "30s")
+
+	// explicit flags keep the higher priority
+	assert.NilError(t, cmd.PersistentFlags().Set("addr", "unix:///var/run/isula_build.sock"))
+	assert.NilError(t, applyContext(cmd))
+	assert.Equal(t, cliOpts.Addr, "unix:///var/run/isula_build.sock")
+
+	// without a selected or current context nothing changes
+	cliOpts.Context = ""
+	assert.NilError(t, applyContext(cmd))
+
+	cliOpts.Addr = ""
+	cliOpts.Timeout = ""
+}
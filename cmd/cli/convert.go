@@ -0,0 +1,121 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-04-08
+// Description: This file is used for command convert
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/util"
+)
+
+const (
+	convertExample = `isula-build ctr-img convert busybox.tar --to oci-archive busybox-oci.tar
+isula-build ctr-img convert busybox-oci.tar --to docker-archive busybox.tar
+isula-build ctr-img convert busybox.tar --to oci ./busybox-layout`
+	convertArgsLen = 2
+)
+
+type convertOptions struct {
+	source      string
+	destination string
+	format      string
+	convertID   string
+}
+
+var convertOpts convertOptions
+
+// NewConvertCmd returns convert command
+func NewConvertCmd() *cobra.Command {
+	convertCmd := &cobra.Command{
+		Use:     "convert SRC --to FORMAT DST",
+		Short:   "Convert an image between docker-archive, oci-archive and OCI layout formats",
+		Example: convertExample,
+		RunE:    convertCommand,
+	}
+	convertCmd.PersistentFlags().StringVar(&convertOpts.format, "to", "", "Destination format, one of docker-archive, oci-archive or oci")
+
+	return convertCmd
+}
+
+func convertCommand(c *cobra.Command, args []string) error {
+	if len(args) != convertArgsLen {
+		return errors.New("requires the source and the destination argument")
+	}
+	switch convertOpts.format {
+	case constant.DockerArchiveTransport, constant.OCIArchiveTransport, constant.OCITransport:
+	case "":
+		return errors.New("the destination format must be given with --to")
+	default:
+		return errors.Errorf("the convert format %q is not supported, choose %q, %q or %q",
+			convertOpts.format, constant.DockerArchiveTransport, constant.OCIArchiveTransport, constant.OCITransport)
+	}
+
+	source, err := getAbsPath(args[0])
+	if err != nil {
+		return err
+	}
+	if exist, eErr := util.IsExist(source); eErr != nil {
+		return eErr
+	} else if !exist {
+		return errors.Errorf("the convert source %q is not exist", source)
+	}
+	convertOpts.source = source
+
+	destination, err := checkAbsPath(args[1])
+	if err != nil {
+		return err
+	}
+	convertOpts.destination = destination
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	return runConvert(ctx, cli)
+}
+
+func runConvert(ctx context.Context, cli Cli) error {
+	convertOpts.convertID = util.GenerateNonCryptoID()[:constant.DefaultIDLen]
+
+	stream, err := cli.Client().Convert(ctx, &pb.ConvertRequest{
+		ConvertID:   convertOpts.convertID,
+		Source:      convertOpts.source,
+		Destination: convertOpts.destination,
+		Format:      convertOpts.format,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, rErr := stream.Recv()
+		if msg != nil {
+			fmt.Print(msg.Log)
+		}
+		if rErr != nil {
+			if rErr == io.EOF {
+				return nil
+			}
+			return rErr
+		}
+	}
+}
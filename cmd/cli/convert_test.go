@@ -0,0 +1,91 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-04-08
+// Description: This file is used for convert command test
+
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestConvertCommand(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("src.tar", "tarball"))
+	defer dir.Remove()
+	src := dir.Join("src.tar")
+	dst := dir.Join("dst.tar")
+
+	testcases := []struct {
+		name      string
+		args      []string
+		format    string
+		errString string
+	}{
+		{
+			name:      "TC1 - abnormal case with missing argument",
+			args:      []string{src},
+			format:    "oci-archive",
+			errString: "requires the source and the destination argument",
+		},
+		{
+			name:      "TC2 - abnormal case without format",
+			args:      []string{src, dst},
+			errString: "must be given with --to",
+		},
+		{
+			name:      "TC3 - abnormal case with unsupported format",
+			args:      []string{src, dst},
+			format:    "tar-gz",
+			errString: `the convert format "tar-gz" is not supported`,
+		},
+		{
+			name:      "TC4 - abnormal case with source not exist",
+			args:      []string{dir.Join("none.tar"), dst},
+			format:    "oci-archive",
+			errString: "is not exist",
+		},
+		{
+			name:      "TC5 - abnormal case with destination already exist",
+			args:      []string{src, src},
+			format:    "oci-archive",
+			errString: "already exist",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewConvertCmd()
+			// the flag default resets convertOpts on command creation
+			convertOpts.format = tc.format
+			err := convertCommand(cmd, tc.args)
+			assert.ErrorContains(t, err, tc.errString)
+		})
+	}
+}
+
+func TestRunConvert(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("src.tar", "tarball"))
+	defer dir.Remove()
+
+	convertOpts = convertOptions{
+		source:      dir.Join("src.tar"),
+		destination: filepath.Join(dir.Path(), "dst.tar"),
+		format:      "oci-archive",
+	}
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	err := runConvert(ctx, &cli)
+	assert.NilError(t, err)
+}
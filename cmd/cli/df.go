@@ -0,0 +1,102 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for command df
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bndr/gotabulate"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/util"
+)
+
+const (
+	dfExample = `isula-build ctr-img df
+isula-build ctr-img df --by-owner`
+	// dfSizeBase renders the sizes with decimal prefixes like the
+	// images listing does
+	dfSizeBase = 1000
+	// unlimited marks a df row without a configured quota
+	unlimited = "-"
+)
+
+type dfOptions struct {
+	byOwner bool
+}
+
+var dfOpts dfOptions
+
+// NewDfCmd returns df command
+func NewDfCmd() *cobra.Command {
+	// dfCmd represents the "df" command
+	dfCmd := &cobra.Command{
+		Use:     "df",
+		Short:   "Show the image storage usage per team or per owner",
+		RunE:    dfCommand,
+		Example: dfExample,
+	}
+
+	dfCmd.PersistentFlags().BoolVar(&dfOpts.byOwner, "by-owner", false, "Group the report by the owning user instead of by team")
+
+	return dfCmd
+}
+
+func dfCommand(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return errors.New("df accepts no arguments")
+	}
+
+	ctx := context.Background()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runDf(ctx, cli)
+}
+
+func runDf(ctx context.Context, cli Cli) error {
+	resp, err := cli.Client().Df(ctx, &pb.DfRequest{ByOwner: dfOpts.byOwner})
+	if err != nil {
+		return err
+	}
+	if len(resp.Entries) == 0 {
+		fmt.Println("No images stored")
+		return nil
+	}
+
+	group := "TEAM"
+	if dfOpts.byOwner {
+		group = "OWNER"
+	}
+	lines := make([][]string, 0, len(resp.Entries))
+	for _, entry := range resp.Entries {
+		quota := unlimited
+		if entry.GetQuota() > 0 {
+			quota = util.FormatSize(float64(entry.GetQuota()), dfSizeBase)
+		}
+		lines = append(lines, []string{entry.GetOwner(), fmt.Sprintf("%d", entry.GetImages()),
+			util.FormatSize(float64(entry.GetSize_()), dfSizeBase), quota})
+	}
+	tabulate := gotabulate.Create(lines)
+	tabulate.SetHeaders([]string{group, "IMAGES", "SIZE", "QUOTA"})
+	tabulate.SetAlign("left")
+	fmt.Print(tabulate.Render("simple"))
+
+	return nil
+}
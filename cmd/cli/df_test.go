@@ -0,0 +1,39 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing command df
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDfCommand(t *testing.T) {
+	err := dfCommand(NewDfCmd(), []string{"extra"})
+	assert.ErrorContains(t, err, "df accepts no arguments")
+}
+
+func TestRunDf(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+
+	err := runDf(ctx, &cli)
+	assert.NilError(t, err)
+
+	dfOpts.byOwner = true
+	defer func() { dfOpts.byOwner = false }()
+	err = runDf(ctx, &cli)
+	assert.NilError(t, err)
+}
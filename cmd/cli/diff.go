@@ -0,0 +1,81 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for "diff" command
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+const diffExample = `isula-build ctr-img diff app:v1
+isula-build ctr-img diff app:v1 app:v2`
+
+// NewDiffCmd returns diff command
+func NewDiffCmd() *cobra.Command {
+	diffCmd := &cobra.Command{
+		Use:     "diff IMAGE [IMAGE]",
+		Short:   "List the file changes between two local images",
+		Example: diffExample,
+		RunE:    diffCommand,
+	}
+
+	return diffCmd
+}
+
+func diffCommand(c *cobra.Command, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New("diff requires one or two arguments")
+	}
+
+	imageB := ""
+	if len(args) == 2 {
+		imageB = args[1]
+	}
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runDiff(ctx, cli, args[0], imageB)
+}
+
+func runDiff(ctx context.Context, cli Cli, imageA, imageB string) error {
+	resp, err := cli.Client().Diff(ctx, &pb.DiffRequest{
+		ImageA: imageA,
+		ImageB: imageB,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, path := range resp.Added {
+		fmt.Printf("A %s\n", path)
+	}
+	for _, path := range resp.Changed {
+		fmt.Printf("C %s\n", path)
+	}
+	for _, path := range resp.Deleted {
+		fmt.Printf("D %s\n", path)
+	}
+	fmt.Printf("%d added, %d changed, %d deleted\n", len(resp.Added), len(resp.Changed), len(resp.Deleted))
+
+	return nil
+}
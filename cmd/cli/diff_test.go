@@ -0,0 +1,45 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing diff command
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDiffCommand(t *testing.T) {
+	diffCmd := NewDiffCmd()
+
+	err := diffCommand(diffCmd, nil)
+	assert.ErrorContains(t, err, "diff requires one or two arguments")
+
+	err = diffCommand(diffCmd, []string{"app:v1", "app:v2", "app:v3"})
+	assert.ErrorContains(t, err, "diff requires one or two arguments")
+
+	err = diffCommand(diffCmd, []string{"app:v1"})
+	assert.ErrorContains(t, err, "isula_build.sock")
+}
+
+func TestRunDiff(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+
+	err := runDiff(ctx, &cli, "app:v1", "app:v2")
+	assert.NilError(t, err)
+
+	err = runDiff(ctx, &cli, "app:v1", "")
+	assert.NilError(t, err)
+}
@@ -18,15 +18,23 @@ import (
 	"net"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gogo/protobuf/types"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	constant "isula.org/isula-build"
 	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/pkg/deprecation"
+	"isula.org/isula-build/pkg/version"
 )
 
 const (
@@ -44,8 +52,9 @@ type Cli interface {
 
 // GrpcClient lives in Client, sends GRPC requests to Server
 type GrpcClient struct {
-	conn   *grpc.ClientConn
-	client pb.ControlClient
+	conn         *grpc.ClientConn
+	client       pb.ControlClient
+	capabilities []string
 }
 
 // NewClient returns an instance of grpc client
@@ -56,14 +65,28 @@ func NewClient(ctx context.Context) (*GrpcClient, error) {
 	gopts := []grpc.DialOption{
 		grpc.WithInsecure(),
 		grpc.WithConnectParams(connParams),
-		grpc.WithContextDialer(dialerCtx(ctx, "unix", strings.TrimPrefix(constant.DefaultGRPCAddress, constant.UnixPrefix))),
+		grpc.WithContextDialer(dialerCtx(ctx, "unix", strings.TrimPrefix(daemonAddr(), constant.UnixPrefix))),
+		grpc.WithUnaryInterceptor(deprecationUnaryInterceptor),
+		grpc.WithStreamInterceptor(deprecationStreamInterceptor),
+	}
+	if cliOpts.Keepalive != "" {
+		interval, err := time.ParseDuration(cliOpts.Keepalive)
+		if err != nil || interval <= 0 {
+			return nil, errors.Errorf("invalid keepalive value: %s, expected a positive duration like 30s", cliOpts.Keepalive)
+		}
+		// pinging even without active calls keeps NAT gateways and
+		// proxies from dropping the connection between commands
+		gopts = append(gopts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                interval,
+			PermitWithoutStream: true,
+		}))
 	}
 
 	if !isSocketReady() {
-		return nil, errors.Errorf("invalid socket path: %s", constant.DefaultGRPCAddress)
+		return nil, errors.Errorf("invalid socket path: %s", daemonAddr())
 	}
 
-	conn, err := grpc.DialContext(ctx, constant.DefaultGRPCAddress, gopts...)
+	conn, err := grpc.DialContext(ctx, daemonAddr(), gopts...)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +105,11 @@ func NewClient(ctx context.Context) (*GrpcClient, error) {
 	defer cancel()
 	connected, err := cli.HealthCheck(healthCtx)
 	if !connected || err != nil {
-		return nil, errors.Errorf("Cannot connect to the isula-builder at %s. Is the isula-builder running?\nError: %v", constant.DefaultGRPCAddress, err)
+		return nil, errors.Errorf("Cannot connect to the isula-builder at %s. Is the isula-builder running?\nError: %v", daemonAddr(), err)
+	}
+
+	if err := cli.Negotiate(healthCtx); err != nil {
+		return nil, err
 	}
 
 	return cli, nil
@@ -112,8 +139,17 @@ func getStartTimeout(timeout string) (time.Duration, error) {
 	return timeParse, nil
 }
 
+// daemonAddr returns the unix socket address of the daemon, configured
+// with the --addr flag
+func daemonAddr() string {
+	if cliOpts.Addr == "" {
+		return constant.DefaultGRPCAddress
+	}
+	return cliOpts.Addr
+}
+
 func isSocketReady() bool {
-	path := strings.TrimPrefix(constant.DefaultGRPCAddress, constant.UnixPrefix)
+	path := strings.TrimPrefix(daemonAddr(), constant.UnixPrefix)
 	info, err := os.Stat(path)
 	if err != nil || info.Mode()&os.ModeSocket == 0 {
 		return false
@@ -138,3 +174,73 @@ func (c *GrpcClient) HealthCheck(ctx context.Context) (bool, error) {
 	}
 	return false, err
 }
+
+// Negotiate exchanges API version and capabilities with the daemon
+func (c *GrpcClient) Negotiate(ctx context.Context) error {
+	res, err := c.client.Negotiate(ctx, &pb.NegotiateRequest{
+		Version:    version.Version,
+		ApiVersion: version.APIVersion,
+	})
+	if err != nil {
+		// daemons predating the handshake do not implement it, keep
+		// working with them instead of refusing the connection
+		if status.Code(err) == codes.Unimplemented {
+			logrus.Debugf("Daemon does not support API negotiation, continuing without it")
+			return nil
+		}
+		return err
+	}
+	logrus.Debugf("Negotiated with daemon %s (API %s), capabilities: %v",
+		res.GetVersion(), res.GetApiVersion(), res.GetCapabilities())
+	c.capabilities = res.GetCapabilities()
+	return nil
+}
+
+// HasCapability reports whether the daemon announced the given API capability
+func (c *GrpcClient) HasCapability(name string) bool {
+	for _, capability := range c.capabilities {
+		if capability == name {
+			return true
+		}
+	}
+	return false
+}
+
+// deprecationUnaryInterceptor renders the deprecation notices the daemon
+// attached to the response headers of unary calls
+func deprecationUnaryInterceptor(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var md metadata.MD
+	opts = append(opts, grpc.Header(&md))
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	deprecation.Print(os.Stderr, deprecation.Extract(md))
+	return err
+}
+
+// deprecationStreamInterceptor renders the deprecation notices the daemon
+// attached to the response headers of streaming calls
+func deprecationStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	s, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &deprecationStream{ClientStream: s}, nil
+}
+
+type deprecationStream struct {
+	grpc.ClientStream
+	once sync.Once
+}
+
+// RecvMsg checks the stream header for deprecation notices once the
+// first message arrived, the header is available by then
+func (s *deprecationStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	s.once.Do(func() {
+		if md, hErr := s.Header(); hErr == nil {
+			deprecation.Print(os.Stderr, deprecation.Extract(md))
+		}
+	})
+	return err
+}
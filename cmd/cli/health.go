@@ -0,0 +1,107 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: This file is used for health command
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/util"
+)
+
+const (
+	healthExample = `isula-build health
+isula-build health --wait --timeout 30s`
+	defaultHealthWaitTimeout = 30 * time.Second
+	healthRetryInterval      = time.Second
+)
+
+type healthOptions struct {
+	wait    bool
+	timeout time.Duration
+}
+
+var healthOpts healthOptions
+
+// NewHealthCheckCmd returns health command
+func NewHealthCheckCmd() *cobra.Command {
+	// healthCmd represents the "health" command
+	healthCmd := &cobra.Command{
+		Use:     "health",
+		Short:   "Check the readiness of the isula-builder daemon",
+		RunE:    healthCommand,
+		Args:    util.NoArgs,
+		Example: healthExample,
+	}
+	healthCmd.PersistentFlags().BoolVar(&healthOpts.wait, "wait", false, "Wait until the daemon is ready instead of failing immediately")
+	healthCmd.PersistentFlags().DurationVar(&healthOpts.timeout, "timeout", defaultHealthWaitTimeout, "Give up waiting for the daemon after this duration")
+
+	return healthCmd
+}
+
+func healthCommand(c *cobra.Command, args []string) error {
+	ctx := context.Background()
+	deadline := time.Now().Add(healthOpts.timeout)
+
+	for {
+		err := checkDaemonHealth(ctx)
+		if err == nil {
+			return nil
+		}
+		if !healthOpts.wait || time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(healthRetryInterval)
+	}
+}
+
+func checkDaemonHealth(ctx context.Context) error {
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runHealthCheck(ctx, cli)
+}
+
+func runHealthCheck(ctx context.Context, cli Cli) error {
+	resp, err := cli.Client().HealthCheck(ctx, &types.Empty{})
+	if err != nil {
+		return err
+	}
+
+	printHealth(resp)
+	if resp.GetStatus() != pb.HealthCheckResponse_SERVING {
+		return errors.New("the isula-builder is not ready to serve")
+	}
+
+	return nil
+}
+
+func printHealth(resp *pb.HealthCheckResponse) {
+	fmt.Printf("Status: %s\n", resp.GetStatus())
+	for _, component := range resp.GetComponents() {
+		readiness := "not ready"
+		if component.GetReady() {
+			readiness = "ready"
+		}
+		fmt.Printf("  %-10s %-10s %s\n", component.GetName(), readiness, component.GetDetail())
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: This file is used for testing command health
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+	"google.golang.org/grpc"
+	"gotest.tools/v3/assert"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+func TestHealthCommand(t *testing.T) {
+	healthCmd := NewHealthCheckCmd()
+	var args []string
+	err := healthCommand(healthCmd, args)
+	assert.ErrorContains(t, err, "isula_build.sock")
+}
+
+func TestRunHealthCheck(t *testing.T) {
+	ctx := context.Background()
+
+	servingCli := newMockClient(&mockGrpcClient{
+		healthCheckFunc: func(_ context.Context, _ *types.Empty, _ ...grpc.CallOption) (*pb.HealthCheckResponse, error) {
+			return &pb.HealthCheckResponse{
+				Status: pb.HealthCheckResponse_SERVING,
+				Components: []*pb.HealthCheckResponse_ComponentStatus{
+					{Name: "storage", Ready: true, Detail: "driver overlay"},
+				},
+			}, nil
+		},
+	})
+	assert.NilError(t, runHealthCheck(ctx, &servingCli))
+
+	notServingCli := newMockClient(&mockGrpcClient{
+		healthCheckFunc: func(_ context.Context, _ *types.Empty, _ ...grpc.CallOption) (*pb.HealthCheckResponse, error) {
+			return &pb.HealthCheckResponse{Status: pb.HealthCheckResponse_NOT_SERVING}, nil
+		},
+	})
+	assert.ErrorContains(t, runHealthCheck(ctx, &notServingCli), "not ready")
+}
@@ -35,8 +35,21 @@ REPOSITORY    TAG    IMAGE ID    CREATED
 const (
 	imagesExample = `isula-build ctr-img images
 isula-build ctr-img images <image name>`
+	// listPageSize is how many entries one List response carries at
+	// most, paging keeps responses small when the store holds a huge
+	// number of images
+	listPageSize = 500
 )
 
+type imagesOptions struct {
+	sortBy    string
+	exactTime bool
+	digests   bool
+	filter    []string
+}
+
+var imagesOpts imagesOptions
+
 // NewImagesCmd returns images command
 func NewImagesCmd() *cobra.Command {
 	// imagesCmd represents the "images" command
@@ -47,6 +60,11 @@ func NewImagesCmd() *cobra.Command {
 		RunE:    imagesCommand,
 	}
 
+	imagesCmd.PersistentFlags().StringVar(&imagesOpts.sortBy, "sort", "", "Sort the listing by \"created\", \"name\" or \"size\"")
+	imagesCmd.PersistentFlags().BoolVar(&imagesOpts.exactTime, "exact-time", false, "Show exact RFC3339 creation times instead of relative times")
+	imagesCmd.PersistentFlags().BoolVar(&imagesOpts.digests, "digests", false, "Show the manifest digest of each image")
+	imagesCmd.PersistentFlags().StringArrayVar(&imagesOpts.filter, "filter", nil, "List only images whose user metadata matches, such as meta.team=payments")
+
 	return imagesCmd
 }
 
@@ -72,25 +90,47 @@ func imagesCommand(c *cobra.Command, args []string) error {
 }
 
 func runList(ctx context.Context, cli Cli, image string) error {
-	resp, err := cli.Client().List(ctx, &pb.ListRequest{
-		ImageName: image,
-	})
-	if err != nil {
-		return err
+	var images []*pb.ListResponse_ImageInfo
+	var pageToken int32
+	for {
+		resp, err := cli.Client().List(ctx, &pb.ListRequest{
+			ImageName: image,
+			PageSize:  listPageSize,
+			PageToken: pageToken,
+			SortBy:    imagesOpts.sortBy,
+			ExactTime: imagesOpts.exactTime,
+			Filter:    imagesOpts.filter,
+		})
+		if err != nil {
+			return err
+		}
+		images = append(images, resp.Images...)
+		if resp.NextPageToken == 0 {
+			break
+		}
+		pageToken = resp.NextPageToken
 	}
-	formatAndPrint(resp.Images)
+	formatAndPrint(images)
 
 	return nil
 }
 
 func formatAndPrint(images []*pb.ListResponse_ImageInfo) {
 	lines := make([][]string, 0, len(images))
-	title := []string{"REPOSITORY", "TAG", "IMAGE ID", "CREATED", "SIZE"}
+	title := []string{"REPOSITORY", "TAG", "IMAGE ID", "CREATED", "SIZE", "SHARED SIZE", "UNIQUE SIZE"}
+	if imagesOpts.digests {
+		title = []string{"REPOSITORY", "TAG", "DIGEST", "IMAGE ID", "CREATED", "SIZE", "SHARED SIZE", "UNIQUE SIZE"}
+	}
 	for _, image := range images {
 		if image == nil {
 			continue
 		}
-		line := []string{image.Repository, image.Tag, image.Id[:constant.DefaultIDLen], image.Created, image.Size_}
+		line := []string{image.Repository, image.Tag, image.Id[:constant.DefaultIDLen], image.Created,
+			image.Size_, image.SharedSize, image.UniqueSize}
+		if imagesOpts.digests {
+			line = []string{image.Repository, image.Tag, image.Digest, image.Id[:constant.DefaultIDLen],
+				image.Created, image.Size_, image.SharedSize, image.UniqueSize}
+		}
 		lines = append(lines, line)
 	}
 	if len(lines) == 0 {
@@ -17,10 +17,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
+	"github.com/containers/image/v5/pkg/compression"
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	constant "isula.org/isula-build"
@@ -29,14 +33,24 @@ import (
 )
 
 const (
-	importExample = `isula-build ctr-img import busybox.tar busybox:isula`
+	importExample = `isula-build ctr-img import busybox.tar busybox:isula
+debootstrap --variant=minbase bullseye ./rootfs && tar -C ./rootfs -c . | isula-build ctr-img import - bullseye:minbase`
 	importArgsLen = 1
+	// stdinSource makes import read the tarball piped on stdin
+	stdinSource = "-"
+	// defaultMaxImportSize keeps the former 1G file size limit as the
+	// default for the --max-size flag
+	defaultMaxImportSize = "1G"
+	// importProgressStep is the amount of transferred bytes between two
+	// progress reports while spooling stdin
+	importProgressStep = 100 * 1024 * 1024
 )
 
 type importOptions struct {
 	source    string
 	reference string
 	importID  string
+	maxSize   string
 }
 
 var importOpts importOptions
@@ -44,11 +58,13 @@ var importOpts importOptions
 // NewImportCmd returns import command
 func NewImportCmd() *cobra.Command {
 	importCmd := &cobra.Command{
-		Use:     "import FILE [REPOSITORY[:TAG]]",
+		Use:     "import FILE|- [REPOSITORY[:TAG]]",
 		Short:   "Import the base image from a tarball to the image store",
 		Example: importExample,
 		RunE:    importCommand,
 	}
+	importCmd.PersistentFlags().StringVar(&importOpts.maxSize, "max-size", defaultMaxImportSize, "Max size of the imported tarball")
+
 	return importCmd
 }
 
@@ -56,10 +72,30 @@ func importCommand(c *cobra.Command, args []string) error {
 	if len(args) < importArgsLen {
 		return errors.New("requires at least one argument")
 	}
-	if err := util.CheckFileInfoAndSize(args[0], constant.MaxImportFileSize); err != nil {
-		return err
+	maxSize, err := units.RAMInBytes(importOpts.maxSize)
+	if err != nil {
+		return errors.Wrapf(err, "parse max import size %q failed", importOpts.maxSize)
+	}
+
+	if args[0] == stdinSource {
+		source, sErr := spoolStdin(os.Stdin, maxSize)
+		if source != "" {
+			defer func() {
+				if rErr := os.Remove(source); rErr != nil {
+					logrus.Warnf("Removing spooled stdin tarball %q failed: %v", source, rErr)
+				}
+			}()
+		}
+		if sErr != nil {
+			return sErr
+		}
+		importOpts.source = source
+	} else {
+		if err := util.CheckFileInfoAndSize(args[0], maxSize); err != nil {
+			return err
+		}
+		importOpts.source = args[0]
 	}
-	importOpts.source = args[0]
 	if len(args) > importArgsLen {
 		importOpts.reference = args[1]
 	}
@@ -72,6 +108,72 @@ func importCommand(c *cobra.Command, args []string) error {
 	return runImport(ctx, cli)
 }
 
+// progressReader counts the bytes read through it and reports every
+// step bytes, so a long spooling from stdin shows progress
+type progressReader struct {
+	r          io.Reader
+	total      int64
+	lastReport int64
+	step       int64
+	report     func(total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.total += int64(n)
+	if p.total-p.lastReport >= p.step {
+		p.report(p.total)
+		p.lastReport = p.total
+	}
+	return n, err
+}
+
+// spoolStdin drains a rootfs tarball piped on stdin into a temporary
+// file the daemon can read, decompressing gzip or zstd input on the fly
+// and enforcing the max import size
+func spoolStdin(in io.Reader, maxSize int64) (string, error) {
+	stream, _, err := compression.AutoDecompress(in)
+	if err != nil {
+		return "", errors.Wrap(err, "detect the compression of stdin failed")
+	}
+	defer func() {
+		if cErr := stream.Close(); cErr != nil {
+			logrus.Warnf("Closing the stdin stream failed: %v", cErr)
+		}
+	}()
+
+	tmpFile, err := ioutil.TempFile("", "isula-build-import-")
+	if err != nil {
+		return "", errors.Wrap(err, "create spool file for stdin failed")
+	}
+	defer func() {
+		if cErr := tmpFile.Close(); cErr != nil {
+			logrus.Warnf("Closing spool file %q failed: %v", tmpFile.Name(), cErr)
+		}
+	}()
+
+	reporter := &progressReader{
+		r:    stream,
+		step: importProgressStep,
+		report: func(total int64) {
+			fmt.Printf("Transferred %s from stdin\n", util.FormatSize(float64(total), binaryPrefixBase))
+		},
+	}
+	written, err := io.Copy(tmpFile, io.LimitReader(reporter, maxSize+1))
+	if err != nil {
+		return tmpFile.Name(), errors.Wrap(err, "spool stdin failed")
+	}
+	if written > maxSize {
+		return tmpFile.Name(), errors.Errorf("stdin exceeds the max import size %s", util.FormatSize(float64(maxSize), binaryPrefixBase))
+	}
+	if written == 0 {
+		return tmpFile.Name(), errors.New("empty input from stdin")
+	}
+	fmt.Printf("Transferred %s from stdin\n", util.FormatSize(float64(written), binaryPrefixBase))
+
+	return tmpFile.Name(), nil
+}
+
 func runImport(ctx context.Context, cli Cli) error {
 	if !filepath.IsAbs(importOpts.source) {
 		pwd, err := os.Getwd()
@@ -0,0 +1,70 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-04-05
+// Description: This file is used for import command test
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSpoolStdin(t *testing.T) {
+	content := []byte("some rootfs tarball bytes")
+
+	source, err := spoolStdin(bytes.NewReader(content), int64(len(content)))
+	assert.NilError(t, err)
+	defer os.Remove(source)
+	spooled, err := ioutil.ReadFile(source)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, spooled, content)
+}
+
+func TestSpoolStdinGzip(t *testing.T) {
+	content := []byte("some rootfs tarball bytes")
+	var compressed bytes.Buffer
+	gw := gzip.NewWriter(&compressed)
+	_, err := gw.Write(content)
+	assert.NilError(t, err)
+	assert.NilError(t, gw.Close())
+
+	// the limit counts the decompressed bytes
+	source, err := spoolStdin(&compressed, int64(len(content)))
+	assert.NilError(t, err)
+	defer os.Remove(source)
+	spooled, err := ioutil.ReadFile(source)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, spooled, content)
+}
+
+func TestSpoolStdinExceedsMaxSize(t *testing.T) {
+	content := []byte("some rootfs tarball bytes")
+
+	source, err := spoolStdin(bytes.NewReader(content), int64(len(content))-1)
+	if source != "" {
+		defer os.Remove(source)
+	}
+	assert.ErrorContains(t, err, "exceeds the max import size")
+}
+
+func TestSpoolStdinEmpty(t *testing.T) {
+	source, err := spoolStdin(bytes.NewReader(nil), 1024)
+	if source != "" {
+		defer os.Remove(source)
+	}
+	assert.ErrorContains(t, err, "empty input from stdin")
+}
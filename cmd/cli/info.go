@@ -157,6 +157,16 @@ func printInfo(infoData *pb.InfoResponse) {
 	for _, registry := range infoData.RegistryInfo.RegistriesInsecure {
 		fmt.Println("   ", registry)
 	}
+	if len(infoData.BuildResources) != 0 {
+		fmt.Println("Build Resources:")
+		for _, res := range infoData.BuildResources {
+			fmt.Println("  BuildID:    ", res.BuildID)
+			fmt.Println("    Memory:   ", res.Memory)
+			fmt.Println("    CPUQuota: ", res.CpuQuota)
+			fmt.Println("    CPUs:     ", res.Cpus)
+			fmt.Println("    PidsLimit:", res.PidsLimit)
+		}
+	}
 	if infoOpts.verbose {
 		fmt.Println("Runtime:")
 		fmt.Println("  MemSys:          ", runtimeMem.memSys)
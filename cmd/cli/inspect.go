@@ -0,0 +1,103 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for "inspect" command
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+const inspectExample = `isula-build ctr-img inspect busybox:latest
+isula-build ctr-img inspect -f '{{.Config.Env}}' busybox:latest
+isula-build ctr-img inspect -f '{{range .Layers}}{{println .}}{{end}}' busybox:latest`
+
+type inspectOptions struct {
+	format string
+}
+
+var inspectOpts inspectOptions
+
+// NewInspectCmd returns inspect command
+func NewInspectCmd() *cobra.Command {
+	inspectCmd := &cobra.Command{
+		Use:     "inspect IMAGE [FLAGS]",
+		Short:   "Inspect the config, manifest and layer digests of a local image",
+		Example: inspectExample,
+		RunE:    inspectCommand,
+	}
+
+	inspectCmd.PersistentFlags().StringVarP(&inspectOpts.format, "format", "f", "", "Format the output using a Go template")
+
+	return inspectCmd
+}
+
+func inspectCommand(c *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("inspect requires exactly one argument")
+	}
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runInspect(ctx, cli, args[0])
+}
+
+func runInspect(ctx context.Context, cli Cli, imageName string) error {
+	resp, err := cli.Client().Inspect(ctx, &pb.InspectRequest{
+		ImageName: imageName,
+	})
+	if err != nil {
+		return errors.Errorf("inspect image failed: %v", err)
+	}
+
+	if inspectOpts.format == "" {
+		fmt.Println(resp.Data)
+		return nil
+	}
+
+	return renderInspectData(resp.Data, inspectOpts.format)
+}
+
+// renderInspectData renders the inspect JSON with the format template,
+// the decoded document is the template root so fields are addressed
+// like '{{.Config.Env}}'
+func renderInspectData(data, format string) error {
+	tmpl, err := template.New("inspect").Parse(format)
+	if err != nil {
+		return errors.Wrapf(err, "parse format template %q failed", format)
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		return errors.Wrap(err, "parse inspect result failed")
+	}
+
+	if err := tmpl.Execute(os.Stdout, decoded); err != nil {
+		return errors.Wrap(err, "render format template failed")
+	}
+	fmt.Println()
+
+	return nil
+}
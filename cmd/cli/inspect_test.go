@@ -0,0 +1,64 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing inspect command
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestInspectCommand(t *testing.T) {
+	inspectCmd := NewInspectCmd()
+
+	err := inspectCommand(inspectCmd, nil)
+	assert.ErrorContains(t, err, "inspect requires exactly one argument")
+
+	err = inspectCommand(inspectCmd, []string{"busybox:latest", "alpine:3.9"})
+	assert.ErrorContains(t, err, "inspect requires exactly one argument")
+
+	err = inspectCommand(inspectCmd, []string{"busybox:latest"})
+	assert.ErrorContains(t, err, "isula_build.sock")
+}
+
+func TestRunInspect(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+
+	inspectOpts.format = ""
+	err := runInspect(ctx, &cli, "busybox:latest")
+	assert.NilError(t, err)
+
+	inspectOpts.format = "{{.Config.Env}}"
+	err = runInspect(ctx, &cli, "busybox:latest")
+	assert.NilError(t, err)
+	inspectOpts.format = ""
+}
+
+func TestRenderInspectData(t *testing.T) {
+	data := `{"config":{"Env":["PATH=/usr/bin"]},"layers":["sha256:abc"]}`
+
+	err := renderInspectData(data, "{{.Config.Env}}")
+	assert.NilError(t, err)
+
+	err = renderInspectData(data, "{{range .Layers}}{{println .}}{{end}}")
+	assert.NilError(t, err)
+
+	err = renderInspectData(data, "{{.Config.Env")
+	assert.ErrorContains(t, err, "parse format template")
+
+	err = renderInspectData("not json", "{{.Config}}")
+	assert.ErrorContains(t, err, "parse inspect result failed")
+}
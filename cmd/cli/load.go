@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -39,9 +41,15 @@ type separatorLoadOption struct {
 }
 
 type loadOptions struct {
-	path   string
-	loadID string
-	sep    separatorLoadOption
+	path        string
+	loadID      string
+	keepPartial bool
+	join        bool
+	// name renames the loaded image, needs an archive with one image
+	name string
+	// joinedPath is the tarball joined from split chunks, removed after loading
+	joinedPath string
+	sep        separatorLoadOption
 }
 
 var loadOpts loadOptions
@@ -67,16 +75,45 @@ func NewLoadCmd() *cobra.Command {
 	loadCmd.PersistentFlags().StringVarP(&loadOpts.sep.base, "base", "b", "", "Base image tarball path of separated images")
 	loadCmd.PersistentFlags().StringVarP(&loadOpts.sep.lib, "lib", "l", "", "Library image tarball path of separated images")
 	loadCmd.PersistentFlags().BoolVarP(&loadOpts.sep.skipCheck, "no-check", "", false, "Skip sha256 check sum for legacy separated images loading")
+	loadCmd.PersistentFlags().BoolVarP(&loadOpts.keepPartial, "keep-partial", "", false, "Keep the already loaded images when loading an archive with several images fails")
+	loadCmd.PersistentFlags().BoolVarP(&loadOpts.join, "join", "", false, "Treat the input(-i) as a split manifest written by save --split-size and join its chunks before loading")
+	loadCmd.PersistentFlags().StringVarP(&loadOpts.name, "name", "n", "", "Rename the loaded image, only valid for an archive holding exactly one image")
 
 	return loadCmd
 }
 
 func loadCommand(cmd *cobra.Command, args []string) error {
-	if err := loadOpts.checkLoadOpts(); err != nil {
+	err := loadOpts.checkLoadOpts()
+	// the joined tarball is only a temporary copy of the chunks, remove
+	// it no matter whether the load succeeds
+	defer func() {
+		if loadOpts.joinedPath != "" {
+			if rErr := os.Remove(loadOpts.joinedPath); rErr != nil && !os.IsNotExist(rErr) {
+				fmt.Printf("Remove joined tarball %q failed: %v\n", loadOpts.joinedPath, rErr)
+			}
+		}
+	}()
+	if err != nil {
 		return errors.Wrapf(err, "check load options failed")
 	}
 
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// cancel the load request on client interrupt so that the daemon
+	// can stop copying and clean up the partial layers
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		select {
+		case sig := <-sigCh:
+			fmt.Printf("\nReceived signal %v, canceling load\n", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
 	cli, err := NewClient(ctx)
 	if err != nil {
 		return err
@@ -97,9 +134,11 @@ func runLoad(ctx context.Context, cli Cli) error {
 	}
 
 	resp, err := cli.Client().Load(ctx, &pb.LoadRequest{
-		Path:   loadOpts.path,
-		LoadID: loadOpts.loadID,
-		Sep:    sep,
+		Path:        loadOpts.path,
+		LoadID:      loadOpts.loadID,
+		Sep:         sep,
+		KeepPartial: loadOpts.keepPartial,
+		Name:        loadOpts.name,
 	})
 	if err != nil {
 		return err
@@ -143,6 +182,12 @@ func (opt *loadOptions) checkLoadOpts() error {
 
 	// load separated image
 	if opt.sep.isEnabled() {
+		if opt.join {
+			return errors.New("conflict flags between --join and [-b -l -d]")
+		}
+		if len(opt.name) != 0 {
+			return errors.New("conflict flags between --name and [-b -l -d]")
+		}
 		// Use opt.path as app image name when operating separated images
 		// this can be mark as a switch for handling separated images
 		opt.sep.app = opt.path
@@ -160,6 +205,23 @@ func (opt *loadOptions) checkLoadOpts() error {
 	}
 
 	// normal load
+	if opt.join {
+		if len(opt.path) == 0 {
+			return errors.New("split manifest path(-i) should not be empty")
+		}
+		joined, jErr := util.JoinSplitFile(util.MakeAbsolute(opt.path, pwd))
+		if jErr != nil {
+			return errors.Wrap(jErr, "join split tarball failed")
+		}
+		opt.joinedPath = joined
+		opt.path = joined
+	}
+	// an oci layout directory is sent as-is, the tarball checks do
+	// not apply to it
+	if util.IsOCILayoutDir(util.MakeAbsolute(opt.path, pwd)) {
+		opt.path = util.MakeAbsolute(opt.path, pwd)
+		return nil
+	}
 	path, err := resolveLoadPath(opt.path, pwd)
 	if err != nil {
 		return err
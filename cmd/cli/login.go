@@ -22,6 +22,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/bndr/gotabulate"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
@@ -55,6 +56,7 @@ type loginOptions struct {
 	password  string
 	keyPath   string
 	stdinPass bool
+	list      bool
 }
 
 type passReader func() ([]byte, error)
@@ -71,11 +73,25 @@ func NewLoginCmd() *cobra.Command {
 
 	loginCmd.PersistentFlags().StringVarP(&loginOpts.username, "username", "u", "", "Username to access registry")
 	loginCmd.PersistentFlags().BoolVarP(&loginOpts.stdinPass, "password-stdin", "p", false, "Read password from stdin")
+	loginCmd.PersistentFlags().BoolVarP(&loginOpts.list, "list", "l", false, "List the registries with stored credentials")
 
 	return loginCmd
 }
 
 func loginCommand(c *cobra.Command, args []string) error {
+	if loginOpts.list {
+		if len(args) != 0 {
+			return errors.New("login --list does not take a registry argument")
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cli, err := NewClient(ctx)
+		if err != nil {
+			return err
+		}
+		return runLoginList(ctx, cli)
+	}
 	if len(args) == 0 {
 		return errEmptyRegistry
 	}
@@ -135,6 +151,33 @@ func runLogin(ctx context.Context, cli Cli, c *cobra.Command) (string, error) {
 	return resp.Content, err
 }
 
+func runLoginList(ctx context.Context, cli Cli) error {
+	resp, err := cli.Client().LoginList(ctx, &pb.LoginListRequest{})
+	if err != nil {
+		return err
+	}
+	if len(resp.Logins) == 0 {
+		fmt.Println("No stored credentials")
+		return nil
+	}
+
+	lines := make([][]string, 0, len(resp.Logins))
+	for _, login := range resp.Logins {
+		expiry := login.TokenExpiry
+		if expiry == "" {
+			expiry = "-"
+		}
+		lines = append(lines, []string{login.Registry, login.Username, login.AuthFile, expiry})
+	}
+	tabulate := gotabulate.Create(lines)
+	tabulate.SetHeaders([]string{"REGISTRY", "USERNAME", "AUTH FILE", "TOKEN EXPIRY"})
+	tabulate.SetAlign("left")
+	tabulate.SetDenseMode()
+	fmt.Print(tabulate.Render("simple"))
+
+	return nil
+}
+
 func checkAuthOpt() error {
 	if loginOpts.stdinPass && loginOpts.username == "" {
 		return errLackOfFlags
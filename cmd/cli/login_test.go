@@ -201,3 +201,20 @@ func TestRunLogin(t *testing.T) {
 		}
 	}
 }
+
+func TestRunLoginList(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+
+	err := runLoginList(ctx, &cli)
+	assert.NilError(t, err)
+}
+
+func TestLoginListWithArgs(t *testing.T) {
+	loginCmd := NewLoginCmd()
+	assert.NilError(t, loginCmd.PersistentFlags().Set("list", "true"))
+
+	err := loginCommand(loginCmd, []string{"test.org"})
+	assert.ErrorContains(t, err, "does not take a registry argument")
+	loginOpts.list = false
+}
@@ -27,9 +27,12 @@ import (
 )
 
 type cliOptions struct {
-	Debug    bool
-	LogLevel string
-	Timeout  string
+	Debug     bool
+	LogLevel  string
+	Timeout   string
+	Addr      string
+	Context   string
+	Keepalive string
 }
 
 var cliOpts cliOptions
@@ -80,7 +83,17 @@ func before(cmd *cobra.Command) error {
 		return err
 	}
 
-	return nil
+	// the context and config commands manage the stores themselves and
+	// must keep working when the current configuration is broken
+	if cmd.Parent() != nil && (cmd.Parent().Use == "context" || cmd.Parent().Use == "config") {
+		return nil
+	}
+
+	if err := applyContext(cmd); err != nil {
+		return err
+	}
+
+	return applyClientConfig(cmd)
 }
 
 func main() {
@@ -96,6 +109,9 @@ func setupRootCmd(rootCmd *cobra.Command) {
 	rootCmd.PersistentFlags().StringVar(&cliOpts.LogLevel, "log-level", "error", "Log level to be used. Either \"debug\", \"info\", \"warn\" or \"error\"")
 	rootCmd.PersistentFlags().BoolVarP(&cliOpts.Debug, "debug", "D", false, "Open debug mode")
 	rootCmd.PersistentFlags().StringVar(&cliOpts.Timeout, "timeout", "", "Timeout for connecting to daemon")
+	rootCmd.PersistentFlags().StringVar(&cliOpts.Addr, "addr", constant.DefaultGRPCAddress, "Unix socket address of the daemon")
+	rootCmd.PersistentFlags().StringVar(&cliOpts.Context, "context", "", "Named daemon context to use for this command")
+	rootCmd.PersistentFlags().StringVar(&cliOpts.Keepalive, "keepalive", "", "Interval between keepalive pings to the daemon like 30s, empty disables them")
 	rootCmd.PersistentFlags().BoolP("help", "h", false, "Print usage")
 	rootCmd.PersistentFlags().BoolP("version", "v", false, "Version for isula-build client")
 }
@@ -103,17 +119,20 @@ func setupRootCmd(rootCmd *cobra.Command) {
 func addCommands(cmd *cobra.Command) {
 	cmd.AddCommand(
 		NewContainerImageBuildCmd(),
+		NewBuilderCmd(),
+		NewBuildsCmd(),
+		NewContextCmd(),
+		NewConfigCmd(),
 		NewVersionCmd(),
+		NewHealthCheckCmd(),
 		NewLoginCmd(),
 		NewLogoutCmd(),
 		NewInfoCmd(),
+		NewSystemCmd(),
+		NewServeCmd(),
+		NewManifestCmd(),
 		completionCmd,
 	)
-	if util.CheckCliExperimentalEnabled() {
-		cmd.AddCommand(
-			NewManifestCmd(),
-		)
-	}
 }
 
 // "completion" command to generate bash completion script
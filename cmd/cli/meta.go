@@ -0,0 +1,192 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for command meta
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bndr/gotabulate"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+const (
+	metaSetExample = `isula-build ctr-img meta set busybox:latest team=payments owner=alice
+isula-build ctr-img meta set busybox:latest owner=`
+	metaGetExample = `isula-build ctr-img meta get busybox:latest team`
+	metaLsExample  = `isula-build ctr-img meta ls busybox:latest`
+	// minMetaSetArgsLen is an image plus at least one key=value pair
+	minMetaSetArgsLen   = 2
+	validMetaGetArgsLen = 2
+	validMetaLsArgsLen  = 1
+)
+
+// NewMetaCmd returns meta command and its subcommands
+func NewMetaCmd() *cobra.Command {
+	// metaCmd represents the "meta" command
+	metaCmd := &cobra.Command{
+		Use:   "meta",
+		Short: "Manage user metadata of local images",
+	}
+	metaCmd.AddCommand(
+		newMetaSetCmd(),
+		newMetaGetCmd(),
+		newMetaLsCmd(),
+	)
+
+	disableFlags(metaCmd)
+
+	return metaCmd
+}
+
+func newMetaSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "set IMAGE KEY=VALUE [KEY=VALUE...]",
+		Short:   "Set metadata key/values on an image, an empty value removes the key",
+		RunE:    metaSetCommand,
+		Example: metaSetExample,
+	}
+}
+
+func newMetaGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "get IMAGE KEY",
+		Short:   "Print the value of one metadata key of an image",
+		RunE:    metaGetCommand,
+		Example: metaGetExample,
+	}
+}
+
+func newMetaLsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "ls IMAGE",
+		Short:   "List the metadata key/values of an image",
+		RunE:    metaLsCommand,
+		Example: metaLsExample,
+	}
+}
+
+func metaSetCommand(cmd *cobra.Command, args []string) error {
+	if len(args) < minMetaSetArgsLen {
+		return errors.New("meta set requires an image and at least one KEY=VALUE pair")
+	}
+	metadata, err := parseMetaKeyVals(args[1:])
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runMetaSet(ctx, cli, args[0], metadata)
+}
+
+func metaGetCommand(cmd *cobra.Command, args []string) error {
+	if len(args) != validMetaGetArgsLen {
+		return errors.New("meta get requires an image and one key")
+	}
+
+	ctx := context.Background()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runMetaGet(ctx, cli, args[0], args[1])
+}
+
+func metaLsCommand(cmd *cobra.Command, args []string) error {
+	if len(args) != validMetaLsArgsLen {
+		return errors.New("meta ls requires one image")
+	}
+
+	ctx := context.Background()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runMetaLs(ctx, cli, args[0])
+}
+
+// parseMetaKeyVals turns KEY=VALUE arguments into request entries, a
+// KEY= argument with an empty value asks the daemon to remove the key
+func parseMetaKeyVals(args []string) ([]*pb.MetaKV, error) {
+	metadata := make([]*pb.MetaKV, 0, len(args))
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.Errorf("invalid metadata %q, expected KEY=VALUE", arg)
+		}
+		metadata = append(metadata, &pb.MetaKV{Key: kv[0], Value: kv[1]})
+	}
+
+	return metadata, nil
+}
+
+func runMetaSet(ctx context.Context, cli Cli, image string, metadata []*pb.MetaKV) error {
+	if _, err := cli.Client().MetaSet(ctx, &pb.MetaSetRequest{
+		Image:    image,
+		Metadata: metadata,
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("Set metadata on image %v succeed\n", image)
+
+	return nil
+}
+
+func runMetaGet(ctx context.Context, cli Cli, image, key string) error {
+	resp, err := cli.Client().MetaGet(ctx, &pb.MetaGetRequest{Image: image})
+	if err != nil {
+		return err
+	}
+	for _, kv := range resp.Metadata {
+		if kv.GetKey() == key {
+			fmt.Println(kv.GetValue())
+			return nil
+		}
+	}
+
+	return errors.Errorf("image %q has no metadata key %q", image, key)
+}
+
+func runMetaLs(ctx context.Context, cli Cli, image string) error {
+	resp, err := cli.Client().MetaGet(ctx, &pb.MetaGetRequest{Image: image})
+	if err != nil {
+		return err
+	}
+	if len(resp.Metadata) == 0 {
+		fmt.Printf("Image %v has no metadata\n", image)
+		return nil
+	}
+
+	lines := make([][]string, 0, len(resp.Metadata))
+	for _, kv := range resp.Metadata {
+		lines = append(lines, []string{kv.GetKey(), kv.GetValue()})
+	}
+	tabulate := gotabulate.Create(lines)
+	tabulate.SetHeaders([]string{"KEY", "VALUE"})
+	tabulate.SetAlign("left")
+	fmt.Print(tabulate.Render("simple"))
+
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing command meta
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+func TestMetaCommandArgs(t *testing.T) {
+	err := metaSetCommand(newMetaSetCmd(), []string{"busybox:latest"})
+	assert.ErrorContains(t, err, "at least one KEY=VALUE pair")
+
+	err = metaSetCommand(newMetaSetCmd(), []string{"busybox:latest", "team"})
+	assert.ErrorContains(t, err, "expected KEY=VALUE")
+
+	err = metaSetCommand(newMetaSetCmd(), []string{"busybox:latest", "=payments"})
+	assert.ErrorContains(t, err, "expected KEY=VALUE")
+
+	err = metaGetCommand(newMetaGetCmd(), []string{"busybox:latest"})
+	assert.ErrorContains(t, err, "an image and one key")
+
+	err = metaLsCommand(newMetaLsCmd(), []string{})
+	assert.ErrorContains(t, err, "one image")
+}
+
+func TestParseMetaKeyVals(t *testing.T) {
+	metadata, err := parseMetaKeyVals([]string{"team=payments", "owner="})
+	assert.NilError(t, err)
+	assert.Equal(t, len(metadata), 2)
+	assert.Equal(t, metadata[0].Key, "team")
+	assert.Equal(t, metadata[0].Value, "payments")
+	assert.Equal(t, metadata[1].Key, "owner")
+	assert.Equal(t, metadata[1].Value, "")
+}
+
+func TestRunMetaSet(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	err := runMetaSet(ctx, &cli, "busybox:latest", []*pb.MetaKV{{Key: "team", Value: "payments"}})
+	assert.NilError(t, err)
+}
+
+func TestRunMetaGet(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+
+	err := runMetaGet(ctx, &cli, "busybox:latest", "team")
+	assert.NilError(t, err)
+
+	err = runMetaGet(ctx, &cli, "busybox:latest", "none")
+	assert.ErrorContains(t, err, "no metadata key")
+}
+
+func TestRunMetaLs(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	err := runMetaLs(ctx, &cli, "busybox:latest")
+	assert.NilError(t, err)
+}
@@ -53,6 +53,10 @@ type mockImportClient struct {
 	grpc.ClientStream
 }
 
+type mockConvertClient struct {
+	grpc.ClientStream
+}
+
 type mockStatusClient struct {
 	grpc.ClientStream
 }
@@ -120,6 +124,10 @@ func (gcli *mockGrpcClient) Import(ctx context.Context, in *pb.ImportRequest, op
 	return nil, nil
 }
 
+func (gcli *mockGrpcClient) Convert(ctx context.Context, in *pb.ConvertRequest, opts ...grpc.CallOption) (pb.Control_ConvertClient, error) {
+	return &mockConvertClient{}, nil
+}
+
 func (gcli *mockGrpcClient) Remove(ctx context.Context, in *pb.RemoveRequest, opts ...grpc.CallOption) (pb.Control_RemoveClient, error) {
 	if gcli.removeFunc != nil {
 		return gcli.removeFunc(ctx, in, opts...)
@@ -207,6 +215,12 @@ func (gcli *mockGrpcClient) Login(ctx context.Context, in *pb.LoginRequest, opts
 	return nil, nil
 }
 
+func (gcli *mockGrpcClient) LoginList(ctx context.Context, in *pb.LoginListRequest, opts ...grpc.CallOption) (*pb.LoginListResponse, error) {
+	return &pb.LoginListResponse{Logins: []*pb.LoginListResponse_LoginInfo{
+		{Registry: "registry.example.com", Username: "cooper", AuthFile: "/root/.docker/config.json"},
+	}}, nil
+}
+
 func (gcli *mockGrpcClient) Logout(ctx context.Context, in *pb.LogoutRequest, opts ...grpc.CallOption) (*pb.LogoutResponse, error) {
 	if gcli.logoutFunc != nil {
 		return gcli.logoutFunc(ctx, in, opts...)
@@ -250,6 +264,106 @@ func (gcli *mockGrpcClient) ManifestPush(ctx context.Context, in *pb.ManifestPus
 	return &mockManifestPushClient{}, nil
 }
 
+func (gcli *mockGrpcClient) CacheList(ctx context.Context, in *pb.CacheListRequest, opts ...grpc.CallOption) (*pb.CacheListResponse, error) {
+	return &pb.CacheListResponse{Caches: []*pb.CacheListResponse_CacheInfo{}}, nil
+}
+
+func (gcli *mockGrpcClient) CacheRemove(ctx context.Context, in *pb.CacheRemoveRequest, opts ...grpc.CallOption) (*pb.CacheRemoveResponse, error) {
+	return &pb.CacheRemoveResponse{Result: "Removed 1 cache entries"}, nil
+}
+
+func (gcli *mockGrpcClient) BuilderCreate(ctx context.Context, in *pb.BuilderCreateRequest, opts ...grpc.CallOption) (*pb.BuilderCreateResponse, error) {
+	return &pb.BuilderCreateResponse{Name: in.GetName()}, nil
+}
+
+func (gcli *mockGrpcClient) BuilderList(ctx context.Context, in *pb.BuilderListRequest, opts ...grpc.CallOption) (*pb.BuilderListResponse, error) {
+	return &pb.BuilderListResponse{Builders: []*pb.BuilderListResponse_BuilderInfo{}}, nil
+}
+
+func (gcli *mockGrpcClient) BuilderRemove(ctx context.Context, in *pb.BuilderRemoveRequest, opts ...grpc.CallOption) (*pb.BuilderRemoveResponse, error) {
+	return &pb.BuilderRemoveResponse{Name: in.GetName()}, nil
+}
+
+func (gcli *mockGrpcClient) Negotiate(ctx context.Context, in *pb.NegotiateRequest, opts ...grpc.CallOption) (*pb.NegotiateResponse, error) {
+	return &pb.NegotiateResponse{ApiVersion: in.GetApiVersion()}, nil
+}
+
+func (gcli *mockGrpcClient) BuildsList(ctx context.Context, in *pb.BuildsListRequest, opts ...grpc.CallOption) (*pb.BuildsListResponse, error) {
+	return &pb.BuildsListResponse{Builds: []*pb.BuildsListResponse_BuildInfo{}}, nil
+}
+
+func (gcli *mockGrpcClient) RemoteInspect(ctx context.Context, in *pb.RemoteInspectRequest, opts ...grpc.CallOption) (*pb.RemoteInspectResponse, error) {
+	return &pb.RemoteInspectResponse{Data: "{}"}, nil
+}
+
+func (gcli *mockGrpcClient) Promote(ctx context.Context, in *pb.PromoteRequest, opts ...grpc.CallOption) (*pb.PromoteResponse, error) {
+	return &pb.PromoteResponse{Source: "registry.example.com/dev/" + in.GetImage(), Target: "registry.example.com/staging/" + in.GetImage()}, nil
+}
+
+func (gcli *mockGrpcClient) Diff(ctx context.Context, in *pb.DiffRequest, opts ...grpc.CallOption) (*pb.DiffResponse, error) {
+	return &pb.DiffResponse{Added: []string{"/etc/new.conf"}, Changed: []string{"/etc/app.conf"}}, nil
+}
+
+func (gcli *mockGrpcClient) Tree(ctx context.Context, in *pb.TreeRequest, opts ...grpc.CallOption) (*pb.TreeResponse, error) {
+	return &pb.TreeResponse{Images: []*pb.TreeResponse_Image{{
+		RepoTags: []string{"app:latest"},
+		Id:       "38b993607bcabe01df1dffdf01b329005c6a10a36d4fc6d612e0f19922bd3a02",
+		Layers: []*pb.TreeResponse_Layer{
+			{Id: "9994458b234cdc1hiab81a4750eb512cc3fea73e4a1c398821d9376b342d5dba", Size_: "69.9 MB", RefCount: 2},
+			{Id: "e4cdc1hiab81a4750eb512cc3fea73e4a1c398821d9376b342d5dba9994458b2", Size_: "12.3 MB", RefCount: 1},
+		},
+	}}}, nil
+}
+
+func (gcli *mockGrpcClient) MetaSet(ctx context.Context, in *pb.MetaSetRequest, opts ...grpc.CallOption) (*types.Empty, error) {
+	return &types.Empty{}, nil
+}
+
+func (gcli *mockGrpcClient) MetaGet(ctx context.Context, in *pb.MetaGetRequest, opts ...grpc.CallOption) (*pb.MetaGetResponse, error) {
+	return &pb.MetaGetResponse{Metadata: []*pb.MetaKV{
+		{Key: "owner", Value: "alice"},
+		{Key: "team", Value: "payments"},
+	}}, nil
+}
+
+func (gcli *mockGrpcClient) Df(ctx context.Context, in *pb.DfRequest, opts ...grpc.CallOption) (*pb.DfResponse, error) {
+	return &pb.DfResponse{Entries: []*pb.DfEntry{
+		{Owner: "payments", Images: 2, Size_: 123456789, Quota: 50000000000},
+	}}, nil
+}
+
+func (gcli *mockGrpcClient) Inspect(ctx context.Context, in *pb.InspectRequest, opts ...grpc.CallOption) (*pb.InspectResponse, error) {
+	return &pb.InspectResponse{Data: `{"Id":"abc","Config":{"Env":["PATH=/usr/bin"]},"Layers":["sha256:def"]}`}, nil
+}
+
+func (gcli *mockGrpcClient) Prune(ctx context.Context, in *pb.PruneRequest, opts ...grpc.CallOption) (*pb.PruneResponse, error) {
+	return &pb.PruneResponse{}, nil
+}
+
+func (gcli *mockGrpcClient) VerifyRemote(ctx context.Context, in *pb.VerifyRemoteRequest, opts ...grpc.CallOption) (*pb.VerifyRemoteResponse, error) {
+	return &pb.VerifyRemoteResponse{Data: `{"configMatch":true,"match":true}`}, nil
+}
+
+func (gcli *mockGrpcClient) ImagePrune(ctx context.Context, in *pb.ImagePruneRequest, opts ...grpc.CallOption) (*pb.ImagePruneResponse, error) {
+	return &pb.ImagePruneResponse{}, nil
+}
+
+func (gcli *mockGrpcClient) Backup(ctx context.Context, in *pb.BackupRequest, opts ...grpc.CallOption) (*pb.BackupResponse, error) {
+	return &pb.BackupResponse{Images: in.GetImages()}, nil
+}
+
+func (gcli *mockGrpcClient) Restore(ctx context.Context, in *pb.RestoreRequest, opts ...grpc.CallOption) (*pb.RestoreResponse, error) {
+	return &pb.RestoreResponse{}, nil
+}
+
+func (gcli *mockGrpcClient) RecordsExport(ctx context.Context, in *pb.RecordsExportRequest, opts ...grpc.CallOption) (*pb.RecordsExportResponse, error) {
+	return &pb.RecordsExportResponse{}, nil
+}
+
+func (gcli *mockGrpcClient) RecordsImport(ctx context.Context, in *pb.RecordsImportRequest, opts ...grpc.CallOption) (*pb.RecordsImportResponse, error) {
+	return &pb.RecordsImportResponse{}, nil
+}
+
 func (gcli *mockGrpcClient) Load(ctx context.Context, in *pb.LoadRequest, opts ...grpc.CallOption) (pb.Control_LoadClient, error) {
 	if gcli.loadFunc != nil {
 		return gcli.loadFunc(ctx, in, opts...)
@@ -305,6 +419,10 @@ func (m mockManifestPushClient) Recv() (*pb.ManifestPushResponse, error) {
 	return &pb.ManifestPushResponse{}, io.EOF
 }
 
+func (ccli *mockConvertClient) Recv() (*pb.ConvertResponse, error) {
+	return &pb.ConvertResponse{}, io.EOF
+}
+
 func (cli *mockClient) Client() pb.ControlClient {
 	return cli.client
 }
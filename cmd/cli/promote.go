@@ -0,0 +1,91 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for "promote" command
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/util"
+)
+
+const promoteExample = `isula-build ctr-img promote app:v1 --from dev --to staging`
+
+type promoteOptions struct {
+	from string
+	to   string
+}
+
+var promoteOpts promoteOptions
+
+// NewPromoteCmd returns promote command
+func NewPromoteCmd() *cobra.Command {
+	promoteCmd := &cobra.Command{
+		Use:     "promote IMAGE FLAGS",
+		Short:   "Promote an image from one logical environment to another",
+		Example: promoteExample,
+		RunE:    promoteCommand,
+	}
+
+	promoteCmd.PersistentFlags().StringVar(&promoteOpts.from, "from", "", "Environment the image currently lives in")
+	promoteCmd.PersistentFlags().StringVar(&promoteOpts.to, "to", "", "Environment the image is promoted to")
+
+	return promoteCmd
+}
+
+func promoteCommand(c *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("promote requires exactly one argument")
+	}
+	if promoteOpts.from == "" {
+		return errors.New("source environment(--from) should not be empty")
+	}
+	if promoteOpts.to == "" {
+		return errors.New("target environment(--to) should not be empty")
+	}
+	if promoteOpts.from == promoteOpts.to {
+		return errors.Errorf("source and target environment are both %q", promoteOpts.from)
+	}
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runPromote(ctx, cli, args[0])
+}
+
+func runPromote(ctx context.Context, cli Cli, imageName string) error {
+	promoteID := util.GenerateNonCryptoID()[:constant.DefaultIDLen]
+
+	resp, err := cli.Client().Promote(ctx, &pb.PromoteRequest{
+		PromoteID: promoteID,
+		Image:     imageName,
+		From:      promoteOpts.from,
+		To:        promoteOpts.to,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Promoted %s to %s\n", resp.Source, resp.Target)
+
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing promote command
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPromoteCommand(t *testing.T) {
+	promoteCmd := NewPromoteCmd()
+
+	err := promoteCommand(promoteCmd, nil)
+	assert.ErrorContains(t, err, "promote requires exactly one argument")
+
+	promoteOpts.from = ""
+	promoteOpts.to = ""
+	err = promoteCommand(promoteCmd, []string{"app:v1"})
+	assert.ErrorContains(t, err, "source environment(--from) should not be empty")
+
+	promoteOpts.from = "dev"
+	err = promoteCommand(promoteCmd, []string{"app:v1"})
+	assert.ErrorContains(t, err, "target environment(--to) should not be empty")
+
+	promoteOpts.to = "dev"
+	err = promoteCommand(promoteCmd, []string{"app:v1"})
+	assert.ErrorContains(t, err, "source and target environment are both")
+
+	promoteOpts.to = "staging"
+	err = promoteCommand(promoteCmd, []string{"app:v1"})
+	assert.ErrorContains(t, err, "isula_build.sock")
+
+	promoteOpts.from = ""
+	promoteOpts.to = ""
+}
+
+func TestRunPromote(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+
+	promoteOpts.from = "dev"
+	promoteOpts.to = "staging"
+	err := runPromote(ctx, &cli, "app:v1")
+	assert.NilError(t, err)
+
+	promoteOpts.from = ""
+	promoteOpts.to = ""
+}
@@ -0,0 +1,114 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for image "prune" command
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/util"
+)
+
+const imagePruneExample = `isula-build ctr-img prune
+isula-build ctr-img prune --all --filter until=24h
+isula-build ctr-img prune --dry-run`
+
+type imagePruneOptions struct {
+	all    bool
+	filter string
+	dryRun bool
+}
+
+var imagePruneOpts imagePruneOptions
+
+// NewImagePruneCmd returns image prune command
+func NewImagePruneCmd() *cobra.Command {
+	imagePruneCmd := &cobra.Command{
+		Use:     "prune [FLAGS]",
+		Short:   "Remove dangling images, unreferenced layers and leftover build containers",
+		RunE:    imagePruneCommand,
+		Args:    util.NoArgs,
+		Example: imagePruneExample,
+	}
+
+	imagePruneCmd.PersistentFlags().BoolVarP(&imagePruneOpts.all, "all", "a", false,
+		"Remove all unused images, not only the dangling ones")
+	imagePruneCmd.PersistentFlags().StringVar(&imagePruneOpts.filter, "filter", "",
+		"Only prune entries matching the filter, only until=<duration> is supported")
+	imagePruneCmd.PersistentFlags().BoolVar(&imagePruneOpts.dryRun, "dry-run", false,
+		"Only report what would be removed")
+
+	return imagePruneCmd
+}
+
+func imagePruneCommand(c *cobra.Command, args []string) error {
+	until, err := parsePruneFilter(imagePruneOpts.filter)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runImagePrune(ctx, cli, until)
+}
+
+// parsePruneFilter parses the --filter value, only the until=<duration>
+// form is supported, an empty value disables the filter
+func parsePruneFilter(filter string) (time.Duration, error) {
+	if filter == "" {
+		return 0, nil
+	}
+	kv := strings.SplitN(filter, "=", 2)
+	if len(kv) != 2 || kv[0] != "until" {
+		return 0, errors.Errorf("invalid filter %q, only until=<duration> is supported", filter)
+	}
+	until, err := time.ParseDuration(kv[1])
+	if err != nil || until <= 0 {
+		return 0, errors.Errorf("invalid duration %q in the until filter", kv[1])
+	}
+
+	return until, nil
+}
+
+func runImagePrune(ctx context.Context, cli Cli, until time.Duration) error {
+	resp, err := cli.Client().ImagePrune(ctx, &pb.ImagePruneRequest{
+		All:          imagePruneOpts.all,
+		UntilSeconds: int64(until.Seconds()),
+		DryRun:       imagePruneOpts.dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, removed := range resp.Removed {
+		fmt.Println(removed)
+	}
+	if imagePruneOpts.dryRun {
+		fmt.Printf("Total reclaimable space: %s\n", util.FormatSize(float64(resp.ReclaimedBytes), binaryPrefixBase))
+		return nil
+	}
+	fmt.Printf("Total reclaimed space: %s\n", util.FormatSize(float64(resp.ReclaimedBytes), binaryPrefixBase))
+
+	return nil
+}
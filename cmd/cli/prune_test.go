@@ -0,0 +1,94 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing image prune command
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParsePruneFilter(t *testing.T) {
+	testcases := []struct {
+		name      string
+		filter    string
+		until     time.Duration
+		wantErr   bool
+		errString string
+	}{
+		{
+			name:   "empty filter disables the cutoff",
+			filter: "",
+			until:  0,
+		},
+		{
+			name:   "until filter with hours",
+			filter: "until=24h",
+			until:  24 * time.Hour,
+		},
+		{
+			name:      "unknown filter key",
+			filter:    "label=foo",
+			wantErr:   true,
+			errString: "only until=<duration> is supported",
+		},
+		{
+			name:      "missing value",
+			filter:    "until",
+			wantErr:   true,
+			errString: "only until=<duration> is supported",
+		},
+		{
+			name:      "invalid duration",
+			filter:    "until=abc",
+			wantErr:   true,
+			errString: "invalid duration",
+		},
+		{
+			name:      "negative duration",
+			filter:    "until=-1h",
+			wantErr:   true,
+			errString: "invalid duration",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			until, err := parsePruneFilter(tc.filter)
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, until, tc.until)
+		})
+	}
+}
+
+func TestRunImagePrune(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	err := runImagePrune(ctx, &cli, 24*time.Hour)
+	assert.NilError(t, err)
+}
+
+func TestImagePruneCommand(t *testing.T) {
+	imagePruneCmd := NewImagePruneCmd()
+	imagePruneOpts.filter = "label=foo"
+	err := imagePruneCommand(imagePruneCmd, nil)
+	assert.ErrorContains(t, err, "only until=<duration> is supported")
+	imagePruneOpts.filter = ""
+}
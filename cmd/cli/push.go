@@ -27,13 +27,16 @@ import (
 )
 
 type pushOptions struct {
-	format string
+	format       string
+	attach       string
+	artifactType string
 }
 
 var pushOpts pushOptions
 
 const (
-	pushExample = `isula-build ctr-img push registry.example.com/repository:tag`
+	pushExample = `isula-build ctr-img push registry.example.com/repository:tag
+isula-build ctr-img push registry.example.com/repository:tag --attach sbom.spdx.json --artifact-type application/spdx+json`
 )
 
 // NewPushCmd returns push command
@@ -49,6 +52,8 @@ func NewPushCmd() *cobra.Command {
 	} else {
 		pushOpts.format = constant.DockerTransport
 	}
+	pushCmd.PersistentFlags().StringVar(&pushOpts.attach, "attach", "", "Path of an artifact (SBOM, signature...) attached to the pushed image")
+	pushCmd.PersistentFlags().StringVar(&pushOpts.artifactType, "artifact-type", "", "OCI artifact type of the attached artifact")
 	return pushCmd
 }
 
@@ -61,6 +66,10 @@ func pushCommand(c *cobra.Command, args []string) error {
 		return err
 	}
 
+	if err := pushOpts.checkAttach(); err != nil {
+		return err
+	}
+
 	ctx := context.TODO()
 	cli, err := NewClient(ctx)
 	if err != nil {
@@ -70,13 +79,39 @@ func pushCommand(c *cobra.Command, args []string) error {
 	return runPush(ctx, cli, args[0])
 }
 
+// checkAttach checks the attached artifact flags and resolves the
+// artifact path for the daemon
+func (opt *pushOptions) checkAttach() error {
+	if opt.attach == "" {
+		if opt.artifactType != "" {
+			return errors.New("--artifact-type must be used together with --attach")
+		}
+		return nil
+	}
+
+	path, err := getAbsPath(opt.attach)
+	if err != nil {
+		return err
+	}
+	if exist, iErr := util.IsExist(path); iErr != nil {
+		return errors.Wrap(iErr, "check attached artifact failed")
+	} else if !exist {
+		return errors.Errorf("attached artifact %q is not exist", path)
+	}
+	opt.attach = path
+
+	return nil
+}
+
 func runPush(ctx context.Context, cli Cli, imageName string) error {
 	pushID := util.GenerateNonCryptoID()[:constant.DefaultIDLen]
 
 	pushStream, err := cli.Client().Push(ctx, &pb.PushRequest{
-		PushID:    pushID,
-		ImageName: imageName,
-		Format:    pushOpts.format,
+		PushID:       pushID,
+		ImageName:    imageName,
+		Format:       pushOpts.format,
+		Attach:       pushOpts.attach,
+		ArtifactType: pushOpts.artifactType,
 	})
 	if err != nil {
 		return err
@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
 )
 
 func TestPushCommand(t *testing.T) {
@@ -74,6 +75,52 @@ func TestPushCommand(t *testing.T) {
 	}
 }
 
+func TestCheckAttach(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("sbom.json", "{}"))
+	defer dir.Remove()
+
+	testcases := []struct {
+		name         string
+		attach       string
+		artifactType string
+		wantErr      bool
+		errString    string
+	}{
+		{
+			name: "normal case without attach",
+		},
+		{
+			name:         "normal case with attach and artifact type",
+			attach:       dir.Join("sbom.json"),
+			artifactType: "application/spdx+json",
+		},
+		{
+			name:         "abnormal case with artifact type only",
+			artifactType: "application/spdx+json",
+			wantErr:      true,
+			errString:    "must be used together with --attach",
+		},
+		{
+			name:      "abnormal case with attach not exist",
+			attach:    dir.Join("none.json"),
+			wantErr:   true,
+			errString: "is not exist",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := &pushOptions{attach: tc.attach, artifactType: tc.artifactType}
+			err := opt.checkAttach()
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
 func TestRunPush(t *testing.T) {
 	ctx := context.Background()
 	mockPush := newMockDaemon()
@@ -0,0 +1,158 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for "builds export" and "builds import" commands
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/util"
+)
+
+const recordsExportExample = `isula-build builds export -o records.tar`
+
+const recordsImportExample = `isula-build builds import -i records.tar`
+
+type recordsExportOptions struct {
+	path string
+}
+
+var recordsExportOpts recordsExportOptions
+
+type recordsImportOptions struct {
+	path string
+}
+
+var recordsImportOpts recordsImportOptions
+
+// NewRecordsExportCmd returns builds export command
+func NewRecordsExportCmd() *cobra.Command {
+	recordsExportCmd := &cobra.Command{
+		Use:     "export FLAGS",
+		Short:   "Export the persisted build records as a signed bundle",
+		RunE:    recordsExportCommand,
+		Args:    util.NoArgs,
+		Example: recordsExportExample,
+	}
+
+	recordsExportCmd.PersistentFlags().StringVarP(&recordsExportOpts.path, "output", "o", "", "Path of the record bundle")
+
+	return recordsExportCmd
+}
+
+func recordsExportCommand(c *cobra.Command, args []string) error {
+	if recordsExportOpts.path == "" {
+		return errors.New("output path(-o) should not be empty")
+	}
+	if strings.Contains(recordsExportOpts.path, ":") {
+		return errors.Errorf("colon in path %q is not supported", recordsExportOpts.path)
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return errors.New("get current path failed")
+	}
+	recordsExportOpts.path = util.MakeAbsolute(recordsExportOpts.path, pwd)
+	if exist, err := util.IsExist(recordsExportOpts.path); err != nil {
+		return errors.Wrap(err, "check output path failed")
+	} else if exist {
+		return errors.Errorf("output file already exist: %q, try to remove or rename it", recordsExportOpts.path)
+	}
+
+	ctx := context.Background()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runRecordsExport(ctx, cli)
+}
+
+func runRecordsExport(ctx context.Context, cli Cli) error {
+	exportID := util.GenerateNonCryptoID()[:constant.DefaultIDLen]
+
+	resp, err := cli.Client().RecordsExport(ctx, &pb.RecordsExportRequest{
+		ExportID: exportID,
+		Path:     recordsExportOpts.path,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d build records to %s\n", len(resp.Records), recordsExportOpts.path)
+
+	return nil
+}
+
+// NewRecordsImportCmd returns builds import command
+func NewRecordsImportCmd() *cobra.Command {
+	recordsImportCmd := &cobra.Command{
+		Use:     "import FLAGS",
+		Short:   "Import a signed build record bundle into the daemon",
+		RunE:    recordsImportCommand,
+		Args:    util.NoArgs,
+		Example: recordsImportExample,
+	}
+
+	recordsImportCmd.PersistentFlags().StringVarP(&recordsImportOpts.path, "input", "i", "", "Path of the record bundle")
+
+	return recordsImportCmd
+}
+
+func recordsImportCommand(c *cobra.Command, args []string) error {
+	if recordsImportOpts.path == "" {
+		return errors.New("input path(-i) should not be empty")
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return errors.New("get current path failed")
+	}
+	recordsImportOpts.path = util.MakeAbsolute(recordsImportOpts.path, pwd)
+	if err := util.CheckFileInfoAndSize(recordsImportOpts.path, constant.MaxLoadFileSize); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runRecordsImport(ctx, cli)
+}
+
+func runRecordsImport(ctx context.Context, cli Cli) error {
+	importID := util.GenerateNonCryptoID()[:constant.DefaultIDLen]
+
+	resp, err := cli.Client().RecordsImport(ctx, &pb.RecordsImportRequest{
+		ImportID: importID,
+		Path:     recordsImportOpts.path,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, record := range resp.Records {
+		fmt.Printf("Imported build record %s\n", record)
+	}
+	fmt.Printf("Imported %d build records from %s\n", len(resp.Records), recordsImportOpts.path)
+
+	return nil
+}
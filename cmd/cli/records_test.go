@@ -0,0 +1,74 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing builds export and import commands
+
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestRecordsExportCommand(t *testing.T) {
+	exportCmd := NewRecordsExportCmd()
+
+	recordsExportOpts.path = ""
+	err := recordsExportCommand(exportCmd, nil)
+	assert.ErrorContains(t, err, "output path(-o) should not be empty")
+
+	recordsExportOpts.path = "rec:ords.tar"
+	err = recordsExportCommand(exportCmd, nil)
+	assert.ErrorContains(t, err, "colon in path")
+
+	tmpDir := fs.NewDir(t, t.Name(), fs.WithFile("records.tar", "content"))
+	defer tmpDir.Remove()
+	recordsExportOpts.path = tmpDir.Join("records.tar")
+	err = recordsExportCommand(exportCmd, nil)
+	assert.ErrorContains(t, err, "already exist")
+
+	recordsExportOpts.path = ""
+}
+
+func TestRecordsImportCommand(t *testing.T) {
+	importCmd := NewRecordsImportCmd()
+
+	recordsImportOpts.path = ""
+	err := recordsImportCommand(importCmd, nil)
+	assert.ErrorContains(t, err, "input path(-i) should not be empty")
+
+	tmpDir := fs.NewDir(t, t.Name())
+	defer tmpDir.Remove()
+	recordsImportOpts.path = filepath.Join(tmpDir.Path(), "missing.tar")
+	err = recordsImportCommand(importCmd, nil)
+	assert.ErrorContains(t, err, "no such file or directory")
+
+	recordsImportOpts.path = ""
+}
+
+func TestRunRecordsExportAndImport(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+
+	recordsExportOpts.path = "/tmp/records.tar"
+	err := runRecordsExport(ctx, &cli)
+	assert.NilError(t, err)
+	recordsExportOpts.path = ""
+
+	recordsImportOpts.path = "/tmp/records.tar"
+	err = runRecordsImport(ctx, &cli)
+	assert.NilError(t, err)
+	recordsImportOpts.path = ""
+}
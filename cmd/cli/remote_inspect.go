@@ -0,0 +1,66 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-04-24
+// Description: This file is used for "remote-inspect" command
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+const (
+	remoteInspectExample = `isula-build ctr-img remote-inspect registry.example.com/repository:tag`
+)
+
+// NewRemoteInspectCmd returns remote-inspect command
+func NewRemoteInspectCmd() *cobra.Command {
+	remoteInspectCmd := &cobra.Command{
+		Use:     "remote-inspect REPOSITORY[:TAG]",
+		Short:   "Inspect a remote image without downloading its layers",
+		Example: remoteInspectExample,
+		RunE:    remoteInspectCommand,
+	}
+	return remoteInspectCmd
+}
+
+func remoteInspectCommand(c *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("remote-inspect requires exactly one argument")
+	}
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runRemoteInspect(ctx, cli, args[0])
+}
+
+func runRemoteInspect(ctx context.Context, cli Cli, imageName string) error {
+	resp, err := cli.Client().RemoteInspect(ctx, &pb.RemoteInspectRequest{
+		ImageName: imageName,
+	})
+	if err != nil {
+		return errors.Errorf("inspect remote image failed: %v", err)
+	}
+
+	fmt.Println(resp.Data)
+
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-04-24
+// Description: This file is used for testing command remote-inspect
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRemoteInspectCommand(t *testing.T) {
+	testcases := []struct {
+		name      string
+		args      []string
+		wantErr   bool
+		errString string
+	}{
+		{
+			name:      "abnormal case without argument",
+			args:      []string{},
+			wantErr:   true,
+			errString: "remote-inspect requires exactly one argument",
+		},
+		{
+			name:      "abnormal case with multiple args",
+			args:      []string{"aaa", "bbb"},
+			wantErr:   true,
+			errString: "remote-inspect requires exactly one argument",
+		},
+		{
+			name:      "normal case without daemon running",
+			args:      []string{"registry.example.com/repository:tag"},
+			wantErr:   true,
+			errString: "isula_build.sock",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			remoteInspectCmd := NewRemoteInspectCmd()
+			err := remoteInspectCommand(remoteInspectCmd, tc.args)
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+			}
+		})
+	}
+}
+
+func TestRunRemoteInspect(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	err := runRemoteInspect(ctx, &cli, "registry.example.com/repository:tag")
+	assert.NilError(t, err)
+}
@@ -18,8 +18,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
@@ -37,11 +40,25 @@ type separatorSaveOption struct {
 }
 
 type saveOptions struct {
-	images []string
-	sep    separatorSaveOption
-	path   string
-	saveID string
-	format string
+	images      []string
+	sep         separatorSaveOption
+	path        string
+	saveID      string
+	format      string
+	annotations []string
+	os          string
+	arch        string
+	variant     string
+	compression string
+	// compressionLevel is the level of the stream compression a
+	// .tar.gz or .tar.zst output path asks for, 0 is the default
+	compressionLevel int
+	splitSize        string
+	// splitChunkSize is splitSize parsed into bytes, 0 means no splitting
+	splitChunkSize int64
+	// each saves every named image to its own tarball under the output
+	// directory, concurrently
+	each bool
 }
 
 var saveOpts saveOptions
@@ -51,7 +68,11 @@ const (
 isula-build ctr-img save 21c3e96ac411 -o myimage.tar
 isula-build ctr-img save busybox:latest alpine:3.9 -o all.tar
 isula-build ctr-img save app:latest -b busybox:latest -d Images
-isula-build ctr-img save app:latest app1:latest -d Images -b busybox:latest -l lib:latest -r rename.json`
+isula-build ctr-img save app:latest app1:latest -d Images -b busybox:latest -l lib:latest -r rename.json
+isula-build ctr-img save busybox:latest alpine:3.9 --each -o images/
+isula-build ctr-img save busybox:latest -o busybox.tar.zst --compression-level 19`
+	// maxConcurrentSaves bounds how many images --each saves at once
+	maxConcurrentSaves = 3
 )
 
 // NewSaveCmd cmd for container image saving
@@ -68,6 +89,14 @@ func NewSaveCmd() *cobra.Command {
 	saveCmd.PersistentFlags().StringVarP(&saveOpts.sep.baseImgName, "base", "b", "", "Base image name of separated images")
 	saveCmd.PersistentFlags().StringVarP(&saveOpts.sep.libImageName, "lib", "l", "", "Lib image name of separated images")
 	saveCmd.PersistentFlags().StringVarP(&saveOpts.sep.renameFile, "rename", "r", "", "Rename json file path of separated images")
+	saveCmd.PersistentFlags().StringArrayVar(&saveOpts.annotations, "annotation", nil, "Add key=value annotation to the saved manifest (oci format only)")
+	saveCmd.PersistentFlags().StringVar(&saveOpts.os, "os", "", "Override the OS recorded in the saved image config")
+	saveCmd.PersistentFlags().StringVar(&saveOpts.arch, "arch", "", "Override the architecture recorded in the saved image config")
+	saveCmd.PersistentFlags().StringVar(&saveOpts.variant, "variant", "", "Override the architecture variant recorded in the saved image config")
+	saveCmd.PersistentFlags().StringVar(&saveOpts.compression, "compression", "", "Layer compression of the saved image (\"gzip\", \"zstd\", \"zstd:chunked\" or \"none\")")
+	saveCmd.PersistentFlags().IntVar(&saveOpts.compressionLevel, "compression-level", 0, "Compression level of a .tar.gz or .tar.zst output, 0 uses the default")
+	saveCmd.PersistentFlags().StringVar(&saveOpts.splitSize, "split-size", "", "Split the saved tarball into chunks of at most this size like 4G, joined again by load --join")
+	saveCmd.PersistentFlags().BoolVar(&saveOpts.each, "each", false, "Save each image to its own tarball under the output directory")
 	if util.CheckCliExperimentalEnabled() {
 		saveCmd.PersistentFlags().StringVarP(&saveOpts.format, "format", "f", "oci", "Format of image saving to local tarball")
 	} else {
@@ -137,11 +166,50 @@ func (opt *saveOptions) checkSaveOpts(args []string) error {
 		return errors.New("get current path failed")
 	}
 
+	if err := opt.checkOverrides(); err != nil {
+		return err
+	}
+
+	// per-image save into a directory
+	if opt.each {
+		if opt.sep.isEnabled() {
+			return errors.New("conflict flags between --each and [-b -l -r -d]")
+		}
+		if len(opt.splitSize) != 0 {
+			return errors.New("conflict flags between --each and --split-size")
+		}
+		if len(opt.path) == 0 {
+			return errors.New("output directory(-o) should not be empty with --each")
+		}
+		if err := util.CheckImageFormat(opt.format); err != nil {
+			return err
+		}
+		if err := util.CheckCompressionFormat(opt.compression); err != nil {
+			return err
+		}
+		if opt.compressionLevel != 0 {
+			return errors.New("conflict flags between --each and --compression-level")
+		}
+		opt.path = util.MakeAbsolute(opt.path, pwd)
+		if exist, eErr := util.IsExist(opt.path); eErr != nil {
+			return errors.Wrap(eErr, "check output directory failed")
+		} else if !exist {
+			if mErr := os.MkdirAll(opt.path, constant.DefaultRootDirMode); mErr != nil {
+				return errors.Wrapf(mErr, "create output directory %q failed", opt.path)
+			}
+		}
+
+		return nil
+	}
+
 	// separator save
 	if opt.sep.isEnabled() {
 		if len(opt.path) != 0 {
 			return errors.New("conflict flags between -o and [-b -l -r -d]")
 		}
+		if len(opt.splitSize) != 0 {
+			return errors.New("conflict flags between --split-size and [-b -l -r -d]")
+		}
 		// separate image only support docker image spec
 		opt.format = constant.DockerTransport
 		if cerr := opt.sep.check(pwd); cerr != nil {
@@ -157,12 +225,18 @@ func (opt *saveOptions) checkSaveOpts(args []string) error {
 	if len(opt.path) == 0 {
 		return errors.New("output path(-o) should not be empty")
 	}
-	if opt.format == constant.OCITransport && len(args) >= 2 {
-		return errors.New("oci image format now only supports saving single image")
-	}
 	if err := util.CheckImageFormat(opt.format); err != nil {
 		return err
 	}
+	if err := util.CheckCompressionFormat(opt.compression); err != nil {
+		return err
+	}
+	if err := opt.checkArchiveCompression(); err != nil {
+		return err
+	}
+	if err := opt.checkSplitSize(); err != nil {
+		return err
+	}
 	opt.path = util.MakeAbsolute(opt.path, pwd)
 	if exist, err := util.IsExist(opt.path); err != nil {
 		return errors.Wrap(err, "check output path failed")
@@ -172,7 +246,68 @@ func (opt *saveOptions) checkSaveOpts(args []string) error {
 	return nil
 }
 
+// checkArchiveCompression checks the on the fly compression a
+// .tar.gz or .tar.zst output path asks for against --compression-level
+func (opt *saveOptions) checkArchiveCompression() error {
+	format := util.ArchiveCompression(opt.path)
+	if format == "" {
+		if opt.compressionLevel != 0 {
+			return errors.New("--compression-level needs a .tar.gz or .tar.zst output path")
+		}
+		return nil
+	}
+	if strings.Contains(opt.path, "{{") {
+		return errors.New("compressed output is not supported with a templated output path")
+	}
+
+	return util.CheckArchiveCompressionLevel(format, opt.compressionLevel)
+}
+
+// checkSplitSize parses the --split-size flag into bytes
+func (opt *saveOptions) checkSplitSize() error {
+	if len(opt.splitSize) == 0 {
+		return nil
+	}
+	if strings.Contains(opt.path, "{{") {
+		return errors.New("--split-size is not supported with a templated output path")
+	}
+	size, err := units.RAMInBytes(opt.splitSize)
+	if err != nil || size <= 0 {
+		return errors.Errorf("invalid split size %q, expected a positive size like 4G", opt.splitSize)
+	}
+	opt.splitChunkSize = size
+
+	return nil
+}
+
+// checkOverrides checks the annotation and platform override flags
+func (opt *saveOptions) checkOverrides() error {
+	if len(opt.annotations) != 0 {
+		if opt.format != constant.OCITransport {
+			return errors.New("--annotation is only supported by the oci image format")
+		}
+		for _, annotation := range opt.annotations {
+			if !strings.Contains(annotation, "=") {
+				return errors.Errorf("invalid annotation %q, expected key=value pair", annotation)
+			}
+		}
+	}
+	overridden := util.AnyFlagSet(opt.os, opt.arch, opt.variant)
+	if overridden && opt.sep.isEnabled() {
+		return errors.New("conflict flags between [--os --arch --variant] and [-b -l -r -d]")
+	}
+	if opt.variant != "" && opt.arch == "" {
+		return errors.New("--variant must be used together with --arch")
+	}
+
+	return nil
+}
+
 func runSave(ctx context.Context, cli Cli, args []string) error {
+	if saveOpts.each {
+		return runSaveEach(ctx, cli, args)
+	}
+
 	saveOpts.saveID = util.GenerateNonCryptoID()[:constant.DefaultIDLen]
 	saveOpts.images = args
 
@@ -185,11 +320,17 @@ func runSave(ctx context.Context, cli Cli, args []string) error {
 	}
 
 	saveStream, err := cli.Client().Save(ctx, &pb.SaveRequest{
-		Images: saveOpts.images,
-		Path:   saveOpts.path,
-		SaveID: saveOpts.saveID,
-		Format: saveOpts.format,
-		Sep:    sep,
+		Images:           saveOpts.images,
+		Path:             saveOpts.path,
+		SaveID:           saveOpts.saveID,
+		Format:           saveOpts.format,
+		Sep:              sep,
+		Annotations:      saveOpts.annotations,
+		Os:               saveOpts.os,
+		Arch:             saveOpts.arch,
+		Variant:          saveOpts.variant,
+		Compression:      saveOpts.compression,
+		CompressionLevel: int32(saveOpts.compressionLevel),
 	})
 	if err != nil {
 		return err
@@ -204,6 +345,88 @@ func runSave(ctx context.Context, cli Cli, args []string) error {
 		if err != nil {
 			if err == io.EOF {
 				fmt.Printf("Save success with image: %s\n", saveOpts.images)
+				return splitSavedTarball()
+			}
+			return errors.Errorf("save image failed: %v", err.Error())
+		}
+	}
+}
+
+// runSaveEach saves each named image to its own tarball under the
+// output directory, at most maxConcurrentSaves run at once, the
+// remaining images are still saved when one of them fails
+func runSaveEach(ctx context.Context, cli Cli, images []string) error {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentSaves)
+	errs := make([]error, len(images))
+	paths := make([]string, len(images))
+
+	for i, img := range images {
+		paths[i] = filepath.Join(saveOpts.path, eachTarballName(img))
+		if exist, err := util.IsExist(paths[i]); err != nil {
+			errs[i] = errors.Wrap(err, "check output path failed")
+			continue
+		} else if exist {
+			errs[i] = errors.Errorf("output file already exist: %q, try to remove existing tarball", paths[i])
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, img string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = saveOneImage(ctx, cli, img, paths[i])
+		}(i, img)
+	}
+	wg.Wait()
+
+	var failed int
+	for i, img := range images {
+		if errs[i] != nil {
+			failed++
+			fmt.Printf("Save failed with image %s: %v\n", img, errs[i])
+			continue
+		}
+		fmt.Printf("Save success with image %s to %s\n", img, paths[i])
+	}
+	if failed != 0 {
+		return errors.Errorf("save failed for %d of %d images", failed, len(images))
+	}
+
+	return nil
+}
+
+// eachTarballName names the tarball of one image saved with --each,
+// the separators of the reference become underscores
+func eachTarballName(image string) string {
+	return strings.NewReplacer("/", "_", ":", "_").Replace(image) + ".tar"
+}
+
+// saveOneImage saves a single image to its own tarball, its log lines
+// are dropped since several saves run at once
+func saveOneImage(ctx context.Context, cli Cli, image, path string) error {
+	saveStream, err := cli.Client().Save(ctx, &pb.SaveRequest{
+		Images:           []string{image},
+		Path:             path,
+		SaveID:           util.GenerateNonCryptoID()[:constant.DefaultIDLen],
+		Format:           saveOpts.format,
+		Sep:              &pb.SeparatorSave{},
+		Annotations:      saveOpts.annotations,
+		Os:               saveOpts.os,
+		Arch:             saveOpts.arch,
+		Variant:          saveOpts.variant,
+		Compression:      saveOpts.compression,
+		CompressionLevel: int32(saveOpts.compressionLevel),
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		_, err := saveStream.Recv()
+		if err != nil {
+			if err == io.EOF {
 				return nil
 			}
 			return errors.Errorf("save image failed: %v", err.Error())
@@ -211,6 +434,24 @@ func runSave(ctx context.Context, cli Cli, args []string) error {
 	}
 }
 
+// splitSavedTarball splits the saved tarball into --split-size chunks
+// and replaces it with them and their manifest
+func splitSavedTarball() error {
+	if saveOpts.splitChunkSize <= 0 {
+		return nil
+	}
+	manifestPath, err := util.SplitFile(saveOpts.path, saveOpts.splitChunkSize)
+	if err != nil {
+		return errors.Wrapf(err, "split saved tarball %q failed", saveOpts.path)
+	}
+	if err := os.Remove(saveOpts.path); err != nil {
+		return errors.Wrapf(err, "remove saved tarball %q after splitting failed", saveOpts.path)
+	}
+	fmt.Printf("Split saved tarball into chunks described by manifest: %s\n", manifestPath)
+
+	return nil
+}
+
 func (sep *separatorSaveOption) isEnabled() bool {
 	return util.AnyFlagSet(sep.baseImgName, sep.libImageName, sep.renameFile, sep.destPath)
 }
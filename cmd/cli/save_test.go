@@ -16,6 +16,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
@@ -118,11 +119,11 @@ func TestSaveCommand(t *testing.T) {
 			format:    "docker",
 		},
 		{
-			name:      "TC10 - abnormal case save multiple images with format oci",
+			name:      "TC10 - normal case save multiple images with format oci",
 			path:      tmpDir.Join("test10"),
 			args:      []string{"testImage1", "testImage2"},
 			wantErr:   true,
-			errString: "oci image format now only supports saving single image",
+			errString: "isula_build.sock",
 			format:    "oci",
 		},
 	}
@@ -261,14 +262,14 @@ func TestCheckSaveOpts(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "TC-normal save with oci format",
+			name: "TC-normal save multiple images with oci format",
 			args: args{[]string{"app:latest", "app1:latest"}},
 			fields: fields{
 				images: []string{"app:latest", "app1:latest"},
 				path:   "test.tar",
 				format: constant.OCITransport,
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "TC-normal save with invalid format",
@@ -423,3 +424,108 @@ func TestCheckSaveOpts(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  saveOptions
+		wantErr bool
+	}{
+		{
+			name:   "TC-no override",
+			fields: saveOptions{format: constant.DockerTransport},
+		},
+		{
+			name: "TC-annotation with oci format",
+			fields: saveOptions{
+				format:      constant.OCITransport,
+				annotations: []string{"org.example.key=value"},
+			},
+		},
+		{
+			name: "TC-annotation with docker format",
+			fields: saveOptions{
+				format:      constant.DockerTransport,
+				annotations: []string{"org.example.key=value"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TC-annotation without key=value pair",
+			fields: saveOptions{
+				format:      constant.OCITransport,
+				annotations: []string{"invalid"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TC-platform override",
+			fields: saveOptions{
+				format: constant.DockerTransport,
+				os:     "linux",
+				arch:   "arm",
+			},
+		},
+		{
+			name: "TC-variant without arch",
+			fields: saveOptions{
+				format:  constant.DockerTransport,
+				variant: "v7",
+			},
+			wantErr: true,
+		},
+		{
+			name: "TC-platform override with separated save",
+			fields: saveOptions{
+				format: constant.DockerTransport,
+				arch:   "arm",
+				sep:    separatorSaveOption{baseImgName: "base"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := tt.fields
+			if err := opt.checkOverrides(); (err != nil) != tt.wantErr {
+				t.Errorf("saveOptions.checkOverrides() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEachTarballName(t *testing.T) {
+	assert.Equal(t, eachTarballName("busybox:latest"), "busybox_latest.tar")
+	assert.Equal(t, eachTarballName("registry.example.com/app/web:v1"), "registry.example.com_app_web_v1.tar")
+	assert.Equal(t, eachTarballName("21c3e96ac411"), "21c3e96ac411.tar")
+}
+
+func TestRunSaveEach(t *testing.T) {
+	tmpDir := fs.NewDir(t, t.Name())
+	defer tmpDir.Remove()
+
+	ctx := context.Background()
+	mockSave := newMockDaemon()
+	cli := newMockClient(&mockGrpcClient{saveFunc: mockSave.save})
+
+	saveOpts.each = true
+	saveOpts.path = tmpDir.Path()
+	defer func() {
+		saveOpts.each = false
+		saveOpts.path = ""
+	}()
+
+	err := runSave(ctx, &cli, []string{"testImage1:test", "testImage2:test"})
+	assert.NilError(t, err)
+
+	// one failing image does not stop the others and is summarized
+	err = runSave(ctx, &cli, []string{"testImage1:test", imageID})
+	assert.ErrorContains(t, err, "save failed for 1 of 2 images")
+
+	// an already existing tarball is refused
+	saveOpts.path = tmpDir.Path()
+	err = ioutil.WriteFile(tmpDir.Join("testimage3_test.tar"), []byte("x"), constant.DefaultRootFileMode)
+	assert.NilError(t, err)
+	err = runSaveEach(ctx, &cli, []string{"testimage3:test"})
+	assert.ErrorContains(t, err, "save failed for 1 of 1 images")
+}
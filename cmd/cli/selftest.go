@@ -0,0 +1,184 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-08
+// Description: This file is used for system selftest command
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/pkg/conformance"
+	"isula.org/isula-build/pkg/opts"
+	"isula.org/isula-build/util"
+)
+
+const selftestExample = `isula-build system selftest
+isula-build system selftest --exporter docker-archive --exporter squashfs`
+
+// selftestDockerfile is the reference image every exporter under test
+// has to round-trip
+const selftestDockerfile = `FROM scratch
+COPY selftest.txt /selftest.txt
+LABEL org.isula.selftest="true"
+`
+
+type selftestOptions struct {
+	exporters []string
+}
+
+var selftestOpts selftestOptions
+
+// selftestArtifacts maps an exporter under test to its output spec and
+// the artifact location the conformance check runs against
+var selftestArtifacts = map[string]func(dir string) (string, string){
+	constant.DockerArchiveTransport: func(dir string) (string, string) {
+		path := filepath.Join(dir, "image.tar")
+		return fmt.Sprintf("%s:%s:isula-selftest:latest", constant.DockerArchiveTransport, path), path
+	},
+	constant.OCIArchiveTransport: func(dir string) (string, string) {
+		path := filepath.Join(dir, "image-oci.tar")
+		return fmt.Sprintf("%s:%s:isula-selftest:latest", constant.OCIArchiveTransport, path), path
+	},
+	constant.OCITransport: func(dir string) (string, string) {
+		path := filepath.Join(dir, "oci-layout-dir")
+		return fmt.Sprintf("%s:%s:isula-selftest:latest", constant.OCITransport, path), path
+	},
+	constant.SquashfsTransport: func(dir string) (string, string) {
+		path := filepath.Join(dir, "rootfs.sqfs")
+		return fmt.Sprintf("%s:%s", constant.SquashfsTransport, path), path
+	},
+	constant.SysrootTransport: func(dir string) (string, string) {
+		path := filepath.Join(dir, "sysroot")
+		return fmt.Sprintf("%s:%s", constant.SysrootTransport, path), path
+	},
+}
+
+// NewSelftestCmd returns system selftest command
+func NewSelftestCmd() *cobra.Command {
+	// selftestCmd represents the "selftest" command
+	selftestCmd := &cobra.Command{
+		Use:     "selftest [FLAGS]",
+		Short:   "Build a reference image and verify the exporters round-trip it",
+		RunE:    selftestCommand,
+		Args:    util.NoArgs,
+		Example: selftestExample,
+	}
+
+	selftestCmd.PersistentFlags().StringArrayVar(&selftestOpts.exporters, "exporter",
+		[]string{constant.DockerArchiveTransport, constant.OCIArchiveTransport, constant.OCITransport},
+		"Exporters to test")
+
+	return selftestCmd
+}
+
+func selftestCommand(c *cobra.Command, args []string) error {
+	for _, name := range selftestOpts.exporters {
+		if _, ok := selftestArtifacts[name]; !ok {
+			return errors.Errorf("exporter %q cannot be selftested", name)
+		}
+	}
+
+	tmpDir, err := ioutil.TempDir("", "isula-build-selftest-")
+	if err != nil {
+		return errors.Wrap(err, "create selftest working directory failed")
+	}
+	defer func() {
+		if rErr := os.RemoveAll(tmpDir); rErr != nil {
+			logrus.Warnf("Removing selftest working directory %q failed: %v", tmpDir, rErr)
+		}
+	}()
+
+	dockerfile := filepath.Join(tmpDir, "Dockerfile")
+	if err := ioutil.WriteFile(dockerfile, []byte(selftestDockerfile), constant.DefaultRootFileMode); err != nil {
+		return errors.Wrap(err, "write selftest Dockerfile failed")
+	}
+	content := filepath.Join(tmpDir, "selftest.txt")
+	if err := ioutil.WriteFile(content, []byte("isula-build selftest\n"), constant.DefaultRootFileMode); err != nil {
+		return errors.Wrap(err, "write selftest context file failed")
+	}
+
+	var failures []string
+	for _, name := range selftestOpts.exporters {
+		spec, artifact := selftestArtifacts[name](tmpDir)
+		if err := runSelftestBuild(tmpDir, dockerfile, spec); err != nil {
+			fmt.Printf("Selftest %s: FAIL (%v)\n", name, err)
+			failures = append(failures, name)
+			continue
+		}
+		if err := conformance.CheckArtifact(name, artifact); err != nil {
+			fmt.Printf("Selftest %s: FAIL (%v)\n", name, err)
+			failures = append(failures, name)
+			continue
+		}
+		fmt.Printf("Selftest %s: OK\n", name)
+	}
+
+	if len(failures) != 0 {
+		return errors.Errorf("%d of %d exporter selftests failed: %v",
+			len(failures), len(selftestOpts.exporters), failures)
+	}
+	fmt.Println("All exporter selftests passed")
+
+	return nil
+}
+
+// runSelftestBuild builds the reference image once with the given
+// output spec, reusing the regular build client flow
+func runSelftestBuild(contextDir, dockerfile, output string) error {
+	buildOpts = buildOptions{
+		buildStatic: opts.NewListOpts(opts.OptValidator),
+		format:      constant.DockerTransport,
+		proxyFlag:   true,
+	}
+	if err := newBuildOptions([]string{contextDir}); err != nil {
+		return err
+	}
+	buildOpts.file = dockerfile
+	buildOpts.output = output
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	eg, _ := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		_, err2 := runBuild(ctx, cli)
+		if err2 != nil {
+			cancel()
+		}
+		return err2
+	})
+	eg.Go(func() error {
+		if err2 := runStatus(ctx, cli); err2 != nil {
+			cancel()
+		}
+		// build errors are reported by runBuild, the status stream
+		// errors carry no extra information
+		return nil
+	})
+
+	return eg.Wait()
+}
@@ -0,0 +1,120 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-05-20
+// Description: This file is used for serve command
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/pkg/localregistry"
+	"isula.org/isula-build/store"
+	"isula.org/isula-build/util"
+)
+
+const serveExample = `isula-build serve
+isula-build serve --addr :5000`
+
+type serveOptions struct {
+	addr string
+}
+
+var serveOpts serveOptions
+
+// NewServeCmd returns serve command
+func NewServeCmd() *cobra.Command {
+	// serveCmd represents the "serve" command
+	serveCmd := &cobra.Command{
+		Use:     "serve [FLAGS]",
+		Short:   "Serve locally stored images over a read-only registry API",
+		RunE:    serveCommand,
+		Args:    util.NoArgs,
+		Example: serveExample,
+	}
+
+	// the registry is unauthenticated, so listening beyond loopback
+	// must be asked for explicitly with an address like :5000
+	serveCmd.PersistentFlags().StringVar(&serveOpts.addr, "addr", "127.0.0.1:5000",
+		"Address the registry service listens on, loopback only unless set otherwise")
+
+	return serveCmd
+}
+
+func serveCommand(c *cobra.Command, args []string) error {
+	if err := setupServeStore(); err != nil {
+		return err
+	}
+	localStore, err := store.GetStore()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if _, sErr := localStore.Shutdown(false); sErr != nil {
+			logrus.Warnf("Shutdown local store failed: %v", sErr)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	fmt.Printf("Serving local images on %s\n", serveOpts.addr)
+	return localregistry.New(serveOpts.addr, &localStore).Serve(ctx)
+}
+
+// setupServeStore points the store at the same storage the daemon
+// uses, taking the roots from the storage configuration
+func setupServeStore() error {
+	store.SetStorageConfigFilePath(constant.StorageConfigPath)
+	option, err := store.GetStorageConfigFileOptions()
+	if err != nil {
+		return err
+	}
+
+	dataRoot := option.GraphRoot
+	if dataRoot == "" {
+		dataRoot = constant.DefaultDataRoot
+	}
+	runRoot := option.RunRoot
+	if runRoot == "" {
+		runRoot = constant.DefaultRunRoot
+	}
+	if dataRoot, err = securejoin.SecureJoin(dataRoot, "storage"); err != nil {
+		return err
+	}
+	if runRoot, err = securejoin.SecureJoin(runRoot, "storage"); err != nil {
+		return err
+	}
+
+	store.SetDefaultStoreOptions(store.DaemonStoreOptions{
+		RunRoot:      runRoot,
+		DataRoot:     dataRoot,
+		Driver:       option.GraphDriverName,
+		DriverOption: option.GraphDriverOptions,
+	})
+
+	return nil
+}
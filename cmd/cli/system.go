@@ -0,0 +1,146 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-05-10
+// Description: This file is used for system command
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/pkg/configcheck"
+	"isula.org/isula-build/util"
+)
+
+const pruneExample = `isula-build system prune --partial
+isula-build system prune --cache-mounts`
+
+const validateConfigExample = `isula-build system validate-config
+isula-build system validate-config --file /etc/isula-build/configuration.toml`
+
+type pruneOptions struct {
+	partial     bool
+	cacheMounts bool
+}
+
+var pruneOpts pruneOptions
+
+type validateConfigOptions struct {
+	file string
+}
+
+var validateConfigOpts validateConfigOptions
+
+// NewSystemCmd returns system command
+func NewSystemCmd() *cobra.Command {
+	// systemCmd represents the "system" command
+	systemCmd := &cobra.Command{
+		Use:   "system",
+		Short: "Manage isula-build system resources",
+	}
+
+	systemCmd.AddCommand(
+		NewPruneCmd(),
+		NewBackupCmd(),
+		NewRestoreCmd(),
+		NewSelftestCmd(),
+		NewValidateConfigCmd(),
+	)
+
+	return systemCmd
+}
+
+// NewPruneCmd returns system prune command
+func NewPruneCmd() *cobra.Command {
+	// pruneCmd represents the "prune" command
+	pruneCmd := &cobra.Command{
+		Use:     "prune [FLAGS]",
+		Short:   "Remove partial data left behind by interrupted sessions",
+		RunE:    pruneCommand,
+		Args:    util.NoArgs,
+		Example: pruneExample,
+	}
+
+	pruneCmd.PersistentFlags().BoolVar(&pruneOpts.partial, "partial", false,
+		"Remove partially downloaded data of interrupted pulls and builds")
+	pruneCmd.PersistentFlags().BoolVar(&pruneOpts.cacheMounts, "cache-mounts", false,
+		"Remove the cache directories of RUN --mount cache mounts")
+
+	return pruneCmd
+}
+
+func pruneCommand(c *cobra.Command, args []string) error {
+	if !pruneOpts.partial && !pruneOpts.cacheMounts {
+		return errors.New("nothing to prune, use the --partial or --cache-mounts flag")
+	}
+
+	ctx := context.Background()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runPrune(ctx, cli)
+}
+
+func runPrune(ctx context.Context, cli Cli) error {
+	resp, err := cli.Client().Prune(ctx, &pb.PruneRequest{
+		Partial:     pruneOpts.partial,
+		CacheMounts: pruneOpts.cacheMounts,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, removed := range resp.Removed {
+		fmt.Println(removed)
+	}
+	fmt.Printf("Total reclaimed space: %s\n", util.FormatSize(float64(resp.ReclaimedBytes), binaryPrefixBase))
+
+	return nil
+}
+
+// NewValidateConfigCmd returns system validate-config command
+func NewValidateConfigCmd() *cobra.Command {
+	// validateConfigCmd represents the "validate-config" command
+	validateConfigCmd := &cobra.Command{
+		Use:     "validate-config [FLAGS]",
+		Short:   "Validate the daemon configuration files",
+		RunE:    validateConfigCommand,
+		Args:    util.NoArgs,
+		Example: validateConfigExample,
+	}
+
+	validateConfigCmd.PersistentFlags().StringVar(&validateConfigOpts.file, "file", constant.ConfigurationPath,
+		"Path of the main configuration file to validate")
+
+	return validateConfigCmd
+}
+
+func validateConfigCommand(c *cobra.Command, args []string) error {
+	problems := configcheck.CheckAll(validateConfigOpts.file)
+	if len(problems) == 0 {
+		fmt.Println("The daemon configuration is valid")
+		return nil
+	}
+
+	for _, problem := range problems {
+		fmt.Println(problem.String())
+	}
+
+	return errors.Errorf("found %d problem(s) in the daemon configuration", len(problems))
+}
@@ -0,0 +1,42 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-05-10
+// Description: This file is used for testing command system
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPruneCommand(t *testing.T) {
+	pruneCmd := NewPruneCmd()
+	var args []string
+
+	pruneOpts.partial = false
+	err := pruneCommand(pruneCmd, args)
+	assert.ErrorContains(t, err, "--partial")
+
+	pruneOpts.partial = true
+	err = pruneCommand(pruneCmd, args)
+	assert.ErrorContains(t, err, "isula_build.sock")
+}
+
+func TestRunPrune(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	pruneOpts.partial = true
+	err := runPrune(ctx, &cli)
+	assert.NilError(t, err)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for "tree" command
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+)
+
+const treeExample = `isula-build ctr-img tree
+isula-build ctr-img tree app:latest`
+
+// NewTreeCmd returns tree command
+func NewTreeCmd() *cobra.Command {
+	treeCmd := &cobra.Command{
+		Use:     "tree [REPOSITORY[:TAG]]",
+		Short:   "Show the layer chains of local images and the layers they share",
+		Example: treeExample,
+		RunE:    treeCommand,
+	}
+
+	return treeCmd
+}
+
+func treeCommand(c *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return errors.New("tree requires at most one argument")
+	}
+
+	imageName := ""
+	if len(args) == 1 {
+		imageName = args[0]
+	}
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runTree(ctx, cli, imageName)
+}
+
+func runTree(ctx context.Context, cli Cli, imageName string) error {
+	resp, err := cli.Client().Tree(ctx, &pb.TreeRequest{
+		ImageName: imageName,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, image := range resp.Images {
+		name := strings.Join(image.RepoTags, ", ")
+		if name == "" {
+			name = "<none>"
+		}
+		fmt.Printf("%s (%s)\n", name, image.Id[:constant.DefaultIDLen])
+		for i, layer := range image.Layers {
+			branch := "├─"
+			if i == len(image.Layers)-1 {
+				branch = "└─"
+			}
+			sharing := "unique"
+			if layer.RefCount > 1 {
+				sharing = fmt.Sprintf("shared by %d images", layer.RefCount)
+			}
+			fmt.Printf("%s %s %s (%s)\n", branch, layer.Id[:constant.DefaultIDLen], layer.Size_, sharing)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing tree command
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTreeCommand(t *testing.T) {
+	treeCmd := NewTreeCmd()
+
+	err := treeCommand(treeCmd, []string{"app:v1", "app:v2"})
+	assert.ErrorContains(t, err, "tree requires at most one argument")
+
+	err = treeCommand(treeCmd, nil)
+	assert.ErrorContains(t, err, "isula_build.sock")
+}
+
+func TestRunTree(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+
+	err := runTree(ctx, &cli, "")
+	assert.NilError(t, err)
+
+	err = runTree(ctx, &cli, "app:latest")
+	assert.NilError(t, err)
+}
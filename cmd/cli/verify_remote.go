@@ -0,0 +1,116 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for "verify-remote" command
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+const (
+	verifyRemoteExample = `isula-build ctr-img verify-remote busybox:latest registry.example.com/repository:tag`
+)
+
+// verifyRemoteLayerReport is one layer entry of the match report
+type verifyRemoteLayerReport struct {
+	LocalDiffID  string `json:"localDiffID"`
+	RemoteDiffID string `json:"remoteDiffID"`
+	Match        bool   `json:"match"`
+}
+
+// verifyRemoteReport mirrors the json report assembled by the daemon
+type verifyRemoteReport struct {
+	LocalConfig  string                    `json:"localConfig"`
+	RemoteConfig string                    `json:"remoteConfig"`
+	ConfigMatch  bool                      `json:"configMatch"`
+	Layers       []verifyRemoteLayerReport `json:"layers"`
+	Match        bool                      `json:"match"`
+}
+
+// NewVerifyRemoteCmd returns verify-remote command
+func NewVerifyRemoteCmd() *cobra.Command {
+	verifyRemoteCmd := &cobra.Command{
+		Use:     "verify-remote IMAGE REPOSITORY[:TAG]",
+		Short:   "Check that the copy of an image in a registry matches the local image",
+		Example: verifyRemoteExample,
+		RunE:    verifyRemoteCommand,
+	}
+	return verifyRemoteCmd
+}
+
+func verifyRemoteCommand(c *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return errors.New("verify-remote requires the local image and the remote reference")
+	}
+
+	ctx := context.TODO()
+	cli, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runVerifyRemote(ctx, cli, args[0], args[1])
+}
+
+func runVerifyRemote(ctx context.Context, cli Cli, localImage, remoteRef string) error {
+	resp, err := cli.Client().VerifyRemote(ctx, &pb.VerifyRemoteRequest{
+		LocalImage: localImage,
+		RemoteRef:  remoteRef,
+	})
+	if err != nil {
+		return errors.Errorf("verify remote image failed: %v", err)
+	}
+
+	var report verifyRemoteReport
+	if err := json.Unmarshal([]byte(resp.Data), &report); err != nil {
+		return errors.Wrap(err, "parse verify result failed")
+	}
+	printVerifyRemoteReport(report)
+
+	if !report.Match {
+		return errors.Errorf("image %q does not match the copy in %q", localImage, remoteRef)
+	}
+	fmt.Printf("Image %s matches the copy in %s\n", localImage, remoteRef)
+
+	return nil
+}
+
+func printVerifyRemoteReport(report verifyRemoteReport) {
+	for i, layer := range report.Layers {
+		fmt.Printf("Layer %d: local %s, remote %s: %s\n", i+1,
+			diffIDOrNone(layer.LocalDiffID), diffIDOrNone(layer.RemoteDiffID), matchResult(layer.Match))
+	}
+	fmt.Printf("Config: local %s, remote %s: %s\n",
+		report.LocalConfig, report.RemoteConfig, matchResult(report.ConfigMatch))
+}
+
+func matchResult(match bool) string {
+	if match {
+		return "MATCH"
+	}
+	return "MISMATCH"
+}
+
+func diffIDOrNone(diffID string) string {
+	if diffID == "" {
+		return "<none>"
+	}
+	return diffID
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing command verify-remote
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestVerifyRemoteCommand(t *testing.T) {
+	testcases := []struct {
+		name      string
+		args      []string
+		wantErr   bool
+		errString string
+	}{
+		{
+			name:      "abnormal case without argument",
+			args:      []string{},
+			wantErr:   true,
+			errString: "verify-remote requires the local image and the remote reference",
+		},
+		{
+			name:      "abnormal case with one argument",
+			args:      []string{"busybox:latest"},
+			wantErr:   true,
+			errString: "verify-remote requires the local image and the remote reference",
+		},
+		{
+			name:      "normal case without daemon running",
+			args:      []string{"busybox:latest", "registry.example.com/repository:tag"},
+			wantErr:   true,
+			errString: "isula_build.sock",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			verifyRemoteCmd := NewVerifyRemoteCmd()
+			err := verifyRemoteCommand(verifyRemoteCmd, tc.args)
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+			}
+		})
+	}
+}
+
+func TestRunVerifyRemote(t *testing.T) {
+	ctx := context.Background()
+	cli := newMockClient(&mockGrpcClient{})
+	err := runVerifyRemote(ctx, &cli, "busybox:latest", "registry.example.com/repository:tag")
+	assert.NilError(t, err)
+}
+
+func TestMatchResult(t *testing.T) {
+	assert.Equal(t, matchResult(true), "MATCH")
+	assert.Equal(t, matchResult(false), "MISMATCH")
+	assert.Equal(t, diffIDOrNone(""), "<none>")
+	assert.Equal(t, diffIDOrNone("sha256:abc"), "sha256:abc")
+}
@@ -15,18 +15,25 @@ package main
 
 import (
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
 
 	"github.com/BurntSushi/toml"
 	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	constant "isula.org/isula-build"
 	"isula.org/isula-build/cmd/daemon/config"
+	"isula.org/isula-build/daemon"
 	"isula.org/isula-build/image"
+	"isula.org/isula-build/pkg/configcheck"
+	"isula.org/isula-build/pkg/encrypt"
+	"isula.org/isula-build/pkg/logger"
+	"isula.org/isula-build/pkg/signer"
 	"isula.org/isula-build/store"
 	"isula.org/isula-build/util"
 )
@@ -40,9 +47,21 @@ func before(cmd *cobra.Command) error {
 	logrus.SetOutput(os.Stdout)
 	logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
 
+	// report every configuration problem at once before the merge below
+	// stops at the first one
+	if problems := configcheck.CheckAll(constant.ConfigurationPath); len(problems) != 0 {
+		for _, problem := range problems {
+			logrus.Errorf("Config problem: %s", problem.String())
+		}
+		return errors.Errorf("found %d problem(s) in the daemon configuration", len(problems))
+	}
+
 	if err := validateConfigFileAndMerge(cmd); err != nil {
 		return err
 	}
+	if err := applyRootlessDefaults(cmd); err != nil {
+		return err
+	}
 	if err := setStoreAccordingToDaemonOpts(); err != nil {
 		return err
 	}
@@ -55,6 +74,18 @@ func before(cmd *cobra.Command) error {
 		return err
 	}
 
+	if err := setupP2PProxy(); err != nil {
+		return err
+	}
+
+	if err := util.SetCopyParallelism(daemonOpts.MaxParallelDownloads, daemonOpts.MaxParallelUploads); err != nil {
+		return err
+	}
+
+	if err := util.SetCopyRetry(daemonOpts.CopyRetries, daemonOpts.CopyRetryBackoff); err != nil {
+		return err
+	}
+
 	image.SetSystemContext(daemonOpts.DataRoot)
 
 	return nil
@@ -160,12 +191,24 @@ func mergeConfig(conf config.TomlConfig, cmd *cobra.Command) error {
 	if conf.Experimental && !cmd.Flag("experimental").Changed {
 		daemonOpts.Experimental = true
 	}
+	if conf.Standby && !cmd.Flag("standby").Changed {
+		daemonOpts.Standby = true
+	}
 	if conf.LogLevel != "" && !cmd.Flag("log-level").Changed {
 		daemonOpts.LogLevel = conf.LogLevel
 	}
 	if conf.Group != "" && !cmd.Flag("group").Changed {
 		daemonOpts.Group = conf.Group
 	}
+	if conf.Addr != "" && !cmd.Flag("addr").Changed {
+		daemonOpts.Addr = conf.Addr
+	}
+	if conf.SockMode != "" && !cmd.Flag("sock-mode").Changed {
+		daemonOpts.SockMode = conf.SockMode
+	}
+	if conf.BuildAddr != "" && !cmd.Flag("build-addr").Changed {
+		daemonOpts.BuildAddr = conf.BuildAddr
+	}
 	if conf.Runtime != "" {
 		daemonOpts.RuntimePath = conf.Runtime
 	}
@@ -175,6 +218,142 @@ func mergeConfig(conf config.TomlConfig, cmd *cobra.Command) error {
 	if conf.DataRoot != "" && !cmd.Flag("dataroot").Changed {
 		daemonOpts.DataRoot = conf.DataRoot
 	}
+	if conf.ContainerNameTemplate != "" {
+		if err := image.SetContainerNameTemplate(conf.ContainerNameTemplate); err != nil {
+			return err
+		}
+	}
+	if conf.PullPolicyStrict {
+		image.SetPullPolicyStrict(true)
+	}
+	if conf.CacheRegistry != "" && !cmd.Flag("cache-registry").Changed {
+		daemonOpts.CacheRegistryAddr = conf.CacheRegistry
+	}
+	if conf.P2PProxy != "" && !cmd.Flag("p2p-proxy").Changed {
+		daemonOpts.P2PProxy = conf.P2PProxy
+	}
+	if conf.P2PNoProxy != "" && !cmd.Flag("p2p-no-proxy").Changed {
+		daemonOpts.P2PNoProxy = conf.P2PNoProxy
+	}
+	if conf.RecordRetention != "" && !cmd.Flag("record-retention").Changed {
+		daemonOpts.RecordRetention = conf.RecordRetention
+	}
+	if conf.BuildTimeout != "" && !cmd.Flag("build-timeout").Changed {
+		daemonOpts.BuildTimeout = conf.BuildTimeout
+	}
+	if len(conf.Environments) != 0 {
+		daemonOpts.Environments = make(map[string]daemon.Environment, len(conf.Environments))
+		for name, env := range conf.Environments {
+			daemonOpts.Environments[name] = daemon.Environment{Registry: env.Registry, Prefix: env.Prefix}
+		}
+	}
+	if len(conf.Teams) != 0 {
+		daemonOpts.Teams = make(map[string]daemon.Team, len(conf.Teams))
+		for name, team := range conf.Teams {
+			var quota int64
+			if team.Quota != "" {
+				size, pErr := units.RAMInBytes(team.Quota)
+				if pErr != nil {
+					return errors.Wrapf(pErr, "parse storage quota %q of team %q failed", team.Quota, name)
+				}
+				quota = size
+			}
+			daemonOpts.Teams[name] = daemon.Team{Members: team.Members, Quota: quota}
+		}
+	}
+	if len(conf.Signers) != 0 {
+		signers := make(map[string]signer.Config, len(conf.Signers))
+		for registry, s := range conf.Signers {
+			if err := signer.CheckMethod(s.Method); err != nil {
+				return errors.Wrapf(err, "check signing config for registry %q failed", registry)
+			}
+			if s.Key == "" {
+				return errors.Errorf("signing key for registry %q should not be empty", registry)
+			}
+			signers[registry] = signer.Config{Method: s.Method, Key: s.Key}
+		}
+		signer.Init(signers)
+	}
+	if conf.Encryption.Method != "" || len(conf.Encryption.PublicKeys) != 0 || len(conf.Encryption.PrivateKeys) != 0 {
+		if err := encrypt.CheckMethod(conf.Encryption.Method); err != nil {
+			return errors.Wrap(err, "check encryption config failed")
+		}
+		encrypt.Init(encrypt.Config{
+			Method:      conf.Encryption.Method,
+			PublicKeys:  conf.Encryption.PublicKeys,
+			PrivateKeys: conf.Encryption.PrivateKeys,
+		})
+	}
+	if conf.MaxConcurrentBuilds != 0 && !cmd.Flag("max-concurrent-builds").Changed {
+		daemonOpts.MaxConcurrentBuilds = conf.MaxConcurrentBuilds
+	}
+	if conf.RateLimit != 0 && !cmd.Flag("rate-limit").Changed {
+		daemonOpts.RateLimit = conf.RateLimit
+	}
+	if conf.RateBurst != 0 && !cmd.Flag("rate-burst").Changed {
+		daemonOpts.RateBurst = conf.RateBurst
+	}
+	if conf.MaxRecvMsgSize != 0 && !cmd.Flag("max-recv-msg-size").Changed {
+		daemonOpts.MaxRecvMsgSize = conf.MaxRecvMsgSize
+	}
+	if conf.MaxParallelDownloads != 0 && !cmd.Flag("max-parallel-downloads").Changed {
+		daemonOpts.MaxParallelDownloads = conf.MaxParallelDownloads
+	}
+	if conf.MaxParallelUploads != 0 && !cmd.Flag("max-parallel-uploads").Changed {
+		daemonOpts.MaxParallelUploads = conf.MaxParallelUploads
+	}
+	if conf.CopyRetries != 0 && !cmd.Flag("copy-retries").Changed {
+		daemonOpts.CopyRetries = conf.CopyRetries
+	}
+	if conf.CopyRetryBackoff != "" && !cmd.Flag("copy-retry-backoff").Changed {
+		daemonOpts.CopyRetryBackoff = conf.CopyRetryBackoff
+	}
+	if conf.KeepaliveInterval != "" && !cmd.Flag("keepalive-interval").Changed {
+		daemonOpts.KeepaliveInterval = conf.KeepaliveInterval
+	}
+	if conf.KeepaliveTimeout != "" && !cmd.Flag("keepalive-timeout").Changed {
+		daemonOpts.KeepaliveTimeout = conf.KeepaliveTimeout
+	}
+	if conf.DelegatedPrivileges != "" && !cmd.Flag("delegated-privileges").Changed {
+		daemonOpts.DelegatedPrivileges = conf.DelegatedPrivileges
+	}
+	if conf.DigestAlgorithm != "" {
+		if err := util.SetDigestAlgorithm(conf.DigestAlgorithm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setupP2PProxy routes the registry traffic through the configured
+// P2P distributor endpoint. The registry clients pick the proxy up
+// from the process environment, so large layers travel the P2P
+// network instead of leaving through the upstream registries
+func setupP2PProxy() error {
+	if daemonOpts.P2PProxy == "" {
+		return nil
+	}
+
+	proxyURL, err := url.Parse(daemonOpts.P2PProxy)
+	if err != nil {
+		return errors.Wrapf(err, "parse p2p proxy %q failed", daemonOpts.P2PProxy)
+	}
+	if (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") || proxyURL.Host == "" {
+		return errors.Errorf("p2p proxy %q must be an http(s) URL with a host", daemonOpts.P2PProxy)
+	}
+
+	for _, env := range []string{"HTTP_PROXY", "HTTPS_PROXY"} {
+		if err := os.Setenv(env, daemonOpts.P2PProxy); err != nil {
+			return err
+		}
+	}
+	if daemonOpts.P2PNoProxy != "" {
+		if err := os.Setenv("NO_PROXY", daemonOpts.P2PNoProxy); err != nil {
+			return err
+		}
+	}
+	logrus.Infof("Routing registry traffic through the P2P proxy %s", daemonOpts.P2PProxy)
 
 	return nil
 }
@@ -208,6 +387,10 @@ func initLogging() error {
 	if daemonOpts.Debug {
 		logrus.SetLevel(logrus.DebugLevel)
 	}
+	// from here on the configured level only limits the daemon output,
+	// sessions requesting elevated verbosity get their own log lines
+	// forwarded by the session hook
+	logger.SetupSessionHook(logrus.GetLevel())
 
 	return nil
 }
@@ -240,7 +423,12 @@ func setupWorkingDirectories() error {
 		}
 	}
 
-	// change config root owner as group current defined
+	// change config root owner as group current defined; a rootless
+	// daemon owns nothing under /etc and keeps the directory as is
+	if util.IsRootless() {
+		logrus.Debugf("Rootless mode, keeping the owner of %s", constant.ConfigRoot)
+		return nil
+	}
 	if err := util.ChangeGroup(constant.ConfigRoot, daemonOpts.Group); err != nil {
 		logrus.Errorf("Chown for %s failed: %v", constant.ConfigRoot, err)
 		return err
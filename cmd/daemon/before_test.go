@@ -261,3 +261,27 @@ func TestValidateConfigFileAndMerge(t *testing.T) {
 	assert.Equal(t, storeOptions.GraphRoot, dataRoot.Join("storage"))
 	assert.Equal(t, storeOptions.RunRoot, runRoot.Join("storage"))
 }
+
+func TestSetupP2PProxy(t *testing.T) {
+	defer func() {
+		daemonOpts.P2PProxy = ""
+		daemonOpts.P2PNoProxy = ""
+		os.Unsetenv("HTTP_PROXY")
+		os.Unsetenv("HTTPS_PROXY")
+		os.Unsetenv("NO_PROXY")
+	}()
+
+	daemonOpts.P2PProxy = ""
+	assert.NilError(t, setupP2PProxy())
+	assert.Equal(t, os.Getenv("HTTPS_PROXY"), "")
+
+	daemonOpts.P2PProxy = "unix:///run/dfdaemon.sock"
+	assert.ErrorContains(t, setupP2PProxy(), "http(s) URL")
+
+	daemonOpts.P2PProxy = "http://127.0.0.1:65001"
+	daemonOpts.P2PNoProxy = "localhost,127.0.0.1"
+	assert.NilError(t, setupP2PProxy())
+	assert.Equal(t, os.Getenv("HTTP_PROXY"), "http://127.0.0.1:65001")
+	assert.Equal(t, os.Getenv("HTTPS_PROXY"), "http://127.0.0.1:65001")
+	assert.Equal(t, os.Getenv("NO_PROXY"), "localhost,127.0.0.1")
+}
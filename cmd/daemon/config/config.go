@@ -14,13 +14,85 @@
 // Package config package implements isula-build daemon config
 package config
 
+// Environment defines the registry location of one logical environment
+type Environment struct {
+	Registry string `toml:"registry"`
+	Prefix   string `toml:"prefix"`
+}
+
+// Signer defines how images pushed to one registry are signed
+type Signer struct {
+	// Method is "gpg" for simple signing or "cosign"
+	Method string `toml:"method"`
+	// Key is the gpg key fingerprint or the cosign private key file
+	Key string `toml:"key"`
+}
+
+// Encryption defines the ocicrypt layer encryption of the daemon
+type Encryption struct {
+	// Method is "jwe", "pkcs7" or "keyprovider"
+	Method string `toml:"method"`
+	// PublicKeys are the recipient key files of registry pushes, with
+	// the keyprovider method the raw provider parameters
+	PublicKeys []string `toml:"public_keys"`
+	// PrivateKeys are the key files decrypting pulled layers, with the
+	// keyprovider method the raw provider parameters
+	PrivateKeys []string `toml:"private_keys"`
+}
+
+// Team defines one team of users sharing a storage quota
+type Team struct {
+	// Members lists the usernames or numeric UIDs belonging to the team
+	Members []string `toml:"members"`
+	// Quota bounds the total size of the images the team owns, a size
+	// like "50G", empty keeps the team unlimited
+	Quota string `toml:"quota"`
+}
+
 // TomlConfig defines the configuration of isula-builder
 type TomlConfig struct {
-	Debug        bool   `toml:"debug"`
-	Experimental bool   `toml:"experimental"`
-	Group        string `toml:"group"`
-	LogLevel     string `toml:"loglevel"`
-	Runtime      string `toml:"runtime"`
-	RunRoot      string `toml:"run_root"`
-	DataRoot     string `toml:"data_root"`
+	Debug                 bool   `toml:"debug"`
+	Experimental          bool   `toml:"experimental"`
+	Standby               bool   `toml:"standby"`
+	Group                 string `toml:"group"`
+	Addr                  string `toml:"addr"`
+	SockMode              string `toml:"sock_mode"`
+	BuildAddr             string `toml:"build_addr"`
+	LogLevel              string `toml:"loglevel"`
+	Runtime               string `toml:"runtime"`
+	RunRoot               string `toml:"run_root"`
+	DataRoot              string `toml:"data_root"`
+	ContainerNameTemplate string `toml:"container_name_template"`
+	DigestAlgorithm       string `toml:"digest_algorithm"`
+	CacheRegistry         string `toml:"cache_registry"`
+	P2PProxy              string `toml:"p2p_proxy"`
+	P2PNoProxy            string `toml:"p2p_no_proxy"`
+	RecordRetention       string `toml:"record_retention"`
+	BuildTimeout          string `toml:"build_timeout"`
+	CopyRetryBackoff      string `toml:"copy_retry_backoff"`
+	DelegatedPrivileges   string `toml:"delegated_privileges"`
+	KeepaliveInterval     string `toml:"keepalive_interval"`
+	KeepaliveTimeout      string `toml:"keepalive_timeout"`
+	MaxConcurrentBuilds   int    `toml:"max_concurrent_builds"`
+	RateLimit             int    `toml:"rate_limit"`
+	RateBurst             int    `toml:"rate_burst"`
+	MaxRecvMsgSize        int    `toml:"max_recv_msg_size"`
+	MaxParallelDownloads  int    `toml:"max_parallel_downloads"`
+	MaxParallelUploads    int    `toml:"max_parallel_uploads"`
+	CopyRetries           int    `toml:"copy_retries"`
+	// PullPolicyStrict makes every pull reject base images the
+	// signature policy would accept without any signature check
+	PullPolicyStrict bool `toml:"pull_policy_strict"`
+	// Environments maps logical environment names like "dev" to the
+	// registry location their images live in, used by promote
+	Environments map[string]Environment `toml:"environments"`
+	// Signers maps registries to the signing applied when images are
+	// pushed there, unlisted registries stay unsigned
+	Signers map[string]Signer `toml:"signers"`
+	// Teams maps team names to their members and storage quota, images
+	// built by a member count against the quota of the team
+	Teams map[string]Team `toml:"teams"`
+	// Encryption is the layer encryption applied to registry pushes
+	// and the decryption keys used on pull, empty keeps layers plain
+	Encryption Encryption `toml:"encryption"`
 }
@@ -16,20 +16,28 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/containers/storage/pkg/reexec"
+	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	constant "isula.org/isula-build"
 	"isula.org/isula-build/daemon"
 	_ "isula.org/isula-build/exporter/register"
+	"isula.org/isula-build/pkg/leader"
 	"isula.org/isula-build/pkg/version"
 	"isula.org/isula-build/store"
 	"isula.org/isula-build/util"
 )
 
-const lockFileName = "isula-builder.lock"
+const (
+	lockFileName = "isula-builder.lock"
+	// leaderLockFileName lives on the data root, which is shared by an
+	// active/standby daemon pair, and backs the leader election
+	leaderLockFileName = "isula-builder-leader.lock"
+)
 
 var daemonOpts daemon.Options
 
@@ -48,9 +56,29 @@ func newDaemonCommand() *cobra.Command {
 	}
 	rootCmd.PersistentFlags().BoolVarP(&daemonOpts.Debug, "debug", "D", false, "Open debug mode")
 	rootCmd.PersistentFlags().BoolVarP(&daemonOpts.Experimental, "experimental", "", false, "Enable experimental features")
+	rootCmd.PersistentFlags().BoolVarP(&daemonOpts.Standby, "standby", "", false, "Run as one of an active/standby daemon pair sharing the data root")
 	rootCmd.PersistentFlags().StringVar(&daemonOpts.DataRoot, "dataroot", constant.DefaultDataRoot, "Persistent dir")
 	rootCmd.PersistentFlags().StringVar(&daemonOpts.RunRoot, "runroot", constant.DefaultRunRoot, "Runtime dir")
 	rootCmd.PersistentFlags().StringVar(&daemonOpts.Group, "group", "isula", "User group for unix socket isula-build.sock")
+	rootCmd.PersistentFlags().StringVar(&daemonOpts.Addr, "addr", constant.DefaultGRPCAddress, "Unix socket address the daemon listens on")
+	rootCmd.PersistentFlags().StringVar(&daemonOpts.SockMode, "sock-mode", "", "Octal file mode of the unix socket like 0660, empty uses the default")
+	rootCmd.PersistentFlags().StringVar(&daemonOpts.BuildAddr, "build-addr", "", "Extra unix socket serving only the build RPCs, empty disables it")
+	rootCmd.PersistentFlags().StringVar(&daemonOpts.CacheRegistryAddr, "cache-registry", "", "Address to serve a pull-through cache registry on, such as :5001, empty disables it")
+	rootCmd.PersistentFlags().StringVar(&daemonOpts.P2PProxy, "p2p-proxy", "", "HTTP proxy endpoint of a P2P distributor routing the registry traffic, empty disables it")
+	rootCmd.PersistentFlags().StringVar(&daemonOpts.P2PNoProxy, "p2p-no-proxy", "", "Comma separated hosts accessed directly instead of through the P2P proxy")
+	rootCmd.PersistentFlags().StringVar(&daemonOpts.RecordRetention, "record-retention", "", "How long finished build records are kept, a duration like 720h, empty keeps them forever")
+	rootCmd.PersistentFlags().StringVar(&daemonOpts.BuildTimeout, "build-timeout", "", "Fail every build after this long, a duration like 1h30m, empty lets builds run forever")
+	rootCmd.PersistentFlags().IntVar(&daemonOpts.MaxConcurrentBuilds, "max-concurrent-builds", 0, "Builds allowed to run at once, excess builds wait in a queue, 0 uses the default")
+	rootCmd.PersistentFlags().IntVar(&daemonOpts.RateLimit, "rate-limit", 0, "Requests per second allowed to each client, 0 disables the limit")
+	rootCmd.PersistentFlags().IntVar(&daemonOpts.RateBurst, "rate-burst", 0, "Burst of requests allowed to each client, 0 uses the rate-limit value")
+	rootCmd.PersistentFlags().IntVar(&daemonOpts.MaxRecvMsgSize, "max-recv-msg-size", 0, "Max size of one received gRPC message in bytes, 0 uses the grpc default")
+	rootCmd.PersistentFlags().IntVar(&daemonOpts.MaxParallelDownloads, "max-parallel-downloads", 0, "Layers one pull copies concurrently, 0 uses the library default")
+	rootCmd.PersistentFlags().IntVar(&daemonOpts.MaxParallelUploads, "max-parallel-uploads", 0, "Layers one push copies concurrently, 0 uses the library default")
+	rootCmd.PersistentFlags().IntVar(&daemonOpts.CopyRetries, "copy-retries", 0, "Times a failed registry transfer is retried, 0 disables retrying")
+	rootCmd.PersistentFlags().StringVar(&daemonOpts.CopyRetryBackoff, "copy-retry-backoff", "", "Delay before the first transfer retry like 1s, doubled after every failure")
+	rootCmd.PersistentFlags().StringVar(&daemonOpts.DelegatedPrivileges, "delegated-privileges", "", "Build features non-root callers may request, comma separated like cap-add")
+	rootCmd.PersistentFlags().StringVar(&daemonOpts.KeepaliveInterval, "keepalive-interval", "", "Interval between keepalive pings and idle stream heartbeats like 30s, empty disables them")
+	rootCmd.PersistentFlags().StringVar(&daemonOpts.KeepaliveTimeout, "keepalive-timeout", "", "Time to wait for a keepalive ping ack before closing the connection, empty uses the grpc default")
 	rootCmd.PersistentFlags().StringVar(&daemonOpts.StorageDriver, "storage-driver", "overlay", "Storage-driver")
 	rootCmd.PersistentFlags().StringSliceVar(&daemonOpts.StorageOpts, "storage-opt", []string{}, "Storage driver option")
 	rootCmd.PersistentFlags().StringVar(&daemonOpts.LogLevel, "log-level", "info", "Log level to be used. Either \"debug\", \"info\", \"warn\" or \"error\"")
@@ -78,12 +106,15 @@ var completionCmd = &cobra.Command{
 }
 
 func runDaemon(cmd *cobra.Command, args []string) error {
+	storeStart := time.Now()
 	store, err := store.GetStore()
 	if err != nil {
 		return err
 	}
-	// cleanup the residual container store if it exists
-	store.CleanContainers()
+	logrus.Debugf("Startup: storage initialized in %v", time.Since(storeStart))
+	// cleanup the residual container store if it exists, unwinding a
+	// large store must not delay the daemon readiness
+	store.CleanContainersInBackground()
 	// Ensure we have only one daemon running at the same time
 	lock, err := util.SetDaemonLock(daemonOpts.RunRoot, lockFileName)
 	if err != nil {
@@ -101,6 +132,22 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if daemonOpts.Standby {
+		leaderLockPath, jerr := securejoin.SecureJoin(daemonOpts.DataRoot, leaderLockFileName)
+		if jerr != nil {
+			return jerr
+		}
+		elector := leader.NewElector(leaderLockPath, leader.DefaultCampaignInterval)
+		if _, terr := elector.TryAcquire(); terr != nil {
+			return terr
+		}
+		defer func() {
+			if rerr := elector.Resign(); rerr != nil {
+				logrus.Errorf("Resign leadership on %s failed: %v", elector.Path(), rerr)
+			}
+		}()
+		d.SetLeaderElector(elector)
+	}
 	defer func() {
 		if cerr := d.Cleanup(); cerr != nil {
 			if err == nil {
@@ -118,6 +165,7 @@ func main() {
 	if reexec.Init() {
 		return
 	}
+	maybeRunInUserNamespace()
 
 	cmd := newDaemonCommand()
 	if err := cmd.Execute(); err != nil {
@@ -0,0 +1,116 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: rootless daemon mode
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/containers/storage/pkg/unshare"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/util"
+)
+
+// maybeRunInUserNamespace re-execs the daemon inside a new user
+// namespace mapping the caller's subuid/subgid ranges when it was
+// started by a non-root user, so the storage drivers and the RUN
+// containers see a full range of IDs. The parent waits for the child
+// and exits, the child continues with an effective UID of 0
+func maybeRunInUserNamespace() {
+	if !unshare.IsRootless() {
+		return
+	}
+	unshare.MaybeReexecUsingUserNamespace(false)
+}
+
+// applyRootlessDefaults moves the working directories, the listening
+// socket and the storage driver of a rootless daemon to places a
+// non-root user can write. Paths the user picked explicitly, on the
+// command line or in the configuration, are left alone
+func applyRootlessDefaults(cmd *cobra.Command) error {
+	if !util.IsRootless() {
+		return nil
+	}
+
+	runtimeDir, err := rootlessRuntimeDir()
+	if err != nil {
+		return err
+	}
+	dataHome, err := rootlessDataHome()
+	if err != nil {
+		return err
+	}
+
+	if !cmd.Flag("runroot").Changed && filepath.Clean(daemonOpts.RunRoot) == constant.DefaultRunRoot {
+		daemonOpts.RunRoot = filepath.Join(runtimeDir, "isula-build")
+	}
+	if !cmd.Flag("dataroot").Changed && filepath.Clean(daemonOpts.DataRoot) == constant.DefaultDataRoot {
+		daemonOpts.DataRoot = filepath.Join(dataHome, "isula-build")
+	}
+	if !cmd.Flag("addr").Changed && daemonOpts.Addr == constant.DefaultGRPCAddress {
+		daemonOpts.Addr = constant.UnixPrefix + filepath.Join(runtimeDir, "isula_build.sock")
+	}
+	if !cmd.Flag("storage-driver").Changed && daemonOpts.StorageDriver == "overlay" {
+		if program, lerr := exec.LookPath("fuse-overlayfs"); lerr == nil {
+			if !cmd.Flag("storage-opt").Changed {
+				daemonOpts.StorageOpts = append(daemonOpts.StorageOpts, "overlay.mount_program="+program)
+			}
+		} else {
+			logrus.Infof("No fuse-overlayfs found in PATH, falling back to the vfs storage driver")
+			daemonOpts.StorageDriver = "vfs"
+		}
+	}
+	logrus.Infof("Running in rootless mode as UID %d, dataroot %s, runroot %s",
+		util.RootlessUID(), daemonOpts.DataRoot, daemonOpts.RunRoot)
+
+	return nil
+}
+
+// rootlessRuntimeDir returns the per-user runtime directory, usually
+// /run/user/UID provided by the login session
+func rootlessRuntimeDir() (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir, nil
+	}
+
+	dir := fmt.Sprintf("/run/user/%d", util.RootlessUID())
+	if exist, err := util.IsExist(dir); err != nil {
+		return "", err
+	} else if !exist {
+		return "", errors.Errorf("runtime directory %q is not exist, XDG_RUNTIME_DIR must be set", dir)
+	}
+
+	return dir, nil
+}
+
+// rootlessDataHome returns the per-user persistent data directory,
+// usually ~/.local/share
+func rootlessDataHome() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "resolve home directory failed")
+	}
+
+	return filepath.Join(home, ".local", "share"), nil
+}
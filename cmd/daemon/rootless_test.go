@@ -0,0 +1,49 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: rootless daemon mode related tests
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRootlessRuntimeDir(t *testing.T) {
+	oldRuntimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	defer os.Setenv("XDG_RUNTIME_DIR", oldRuntimeDir)
+
+	os.Setenv("XDG_RUNTIME_DIR", "/tmp/runtime-dir")
+	dir, err := rootlessRuntimeDir()
+	assert.NilError(t, err)
+	assert.Equal(t, dir, "/tmp/runtime-dir")
+}
+
+func TestRootlessDataHome(t *testing.T) {
+	oldDataHome := os.Getenv("XDG_DATA_HOME")
+	defer os.Setenv("XDG_DATA_HOME", oldDataHome)
+
+	os.Setenv("XDG_DATA_HOME", "/tmp/data-home")
+	dir, err := rootlessDataHome()
+	assert.NilError(t, err)
+	assert.Equal(t, dir, "/tmp/data-home")
+
+	os.Unsetenv("XDG_DATA_HOME")
+	home, err := os.UserHomeDir()
+	assert.NilError(t, err)
+	dir, err = rootlessDataHome()
+	assert.NilError(t, err)
+	assert.Equal(t, dir, filepath.Join(home, ".local", "share"))
+}
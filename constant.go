@@ -75,6 +75,8 @@ const (
 	MaxImportFileSize = 1024 * 1024 * 1024
 	// MaxLoadFileSize is the max size of load image file at most 50G
 	MaxLoadFileSize = 50 * 1024 * 1024 * 1024
+	// MaxSessionDirSize is the max size of the per-build session temporary dir at most 50G
+	MaxSessionDirSize = 50 * 1024 * 1024 * 1024
 	// DefaultHTTPTimeout includes the total time of dial, TLS handshake, request, resp headers and body
 	DefaultHTTPTimeout = 3600 * time.Second
 	// DefaultFailedCode is the exit code for most scenes
@@ -99,8 +101,45 @@ const (
 	OCIArchiveTransport = "oci-archive"
 	// IsuladTransport use to export images to isulad
 	IsuladTransport = "isulad"
+	// ContainerdTransport used to import images into a local containerd instance
+	ContainerdTransport = "containerd"
+	// ContainersStorageTransport used to export images into another
+	// containers/storage graph root, e.g. the one CRI-O or podman reads
+	ContainersStorageTransport = "containers-storage"
+	// DefaultContainerdNamespace is the containerd namespace images are
+	// imported into when the output carries none, the one kubernetes consumes
+	DefaultContainerdNamespace = "k8s.io"
 	// ManifestTransport used to export manifest list
 	ManifestTransport = "manifest"
+	// DiskImageTransport used to export the image rootfs to an ext4 or qcow2 disk image
+	DiskImageTransport = "diskimage"
+	// SquashfsTransport used to export the image rootfs to a read-only squashfs image
+	SquashfsTransport = "squashfs"
+	// SysrootTransport used to unpack the image rootfs to a host directory
+	SysrootTransport = "sysroot"
+	// LocalTransport is the buildkit-style name of SysrootTransport
+	LocalTransport = "local"
+	// EStargzTransport used to push the image with layers converted to seekable stargz
+	EStargzTransport = "estargz"
+
+	// SignMethodGPG attaches a simple-signing signature with a gpg key on push
+	SignMethodGPG = "gpg"
+	// SignMethodCosign pushes a cosign signature alongside the manifest
+	SignMethodCosign = "cosign"
+	// EncryptMethodJWE encrypts layers for rsa public key recipients
+	EncryptMethodJWE = "jwe"
+	// EncryptMethodPKCS7 encrypts layers for x509 certificate recipients
+	EncryptMethodPKCS7 = "pkcs7"
+	// EncryptMethodKeyProvider delegates the layer keys to an external key provider
+	EncryptMethodKeyProvider = "keyprovider"
+	// CompressionGzip compresses exported layers with gzip
+	CompressionGzip = "gzip"
+	// CompressionZstd compresses exported layers with zstd
+	CompressionZstd = "zstd"
+	// CompressionZstdChunked compresses exported layers with seekable zstd
+	CompressionZstdChunked = "zstd:chunked"
+	// CompressionNone keeps the transport default layer compression
+	CompressionNone = "none"
 	// DefaultTag is latest
 	DefaultTag = "latest"
 )
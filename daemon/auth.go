@@ -0,0 +1,226 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for gRPC peer credential authorization
+
+package daemon
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	grpcstatus "google.golang.org/grpc/status"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+// adminOnlyMethods lists the RPCs kept for root even when the socket
+// group gives other users access to the daemon
+var adminOnlyMethods = map[string]bool{
+	"/isula.build.v1.Control/Prune":         true,
+	"/isula.build.v1.Control/ImagePrune":    true,
+	"/isula.build.v1.Control/Backup":        true,
+	"/isula.build.v1.Control/Restore":       true,
+	"/isula.build.v1.Control/RecordsExport": true,
+	"/isula.build.v1.Control/RecordsImport": true,
+}
+
+// buildSocketMethods lists the RPCs served on the restricted build
+// socket, everything else stays exclusive to the main socket
+var buildSocketMethods = map[string]bool{
+	"/isula.build.v1.Control/Build":       true,
+	"/isula.build.v1.Control/Status":      true,
+	"/isula.build.v1.Control/List":        true,
+	"/isula.build.v1.Control/Version":     true,
+	"/isula.build.v1.Control/HealthCheck": true,
+	"/isula.build.v1.Control/Negotiate":   true,
+}
+
+// peerCredAuthInfo carries the unix credentials of the calling process
+// and the socket the connection arrived on, a nil ucred means the
+// credentials could not be determined
+type peerCredAuthInfo struct {
+	credentials.CommonAuthInfo
+	ucred     *unix.Ucred
+	localAddr string
+}
+
+// AuthType implements credentials.AuthInfo
+func (peerCredAuthInfo) AuthType() string {
+	return "peercred"
+}
+
+// peerCred is a transport credential harvesting SO_PEERCRED from the
+// unix socket connection, the byte stream itself stays plain
+type peerCred struct{}
+
+// ServerHandshake attaches the peer unix credentials and the local
+// socket address to the connection
+func (peerCred) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	info := peerCredAuthInfo{CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity}}
+	if addr := conn.LocalAddr(); addr != nil {
+		info.localAddr = addr.String()
+	}
+	if unixConn, ok := conn.(*net.UnixConn); ok {
+		if raw, err := unixConn.SyscallConn(); err == nil {
+			var (
+				ucred *unix.Ucred
+				uErr  error
+			)
+			if cErr := raw.Control(func(fd uintptr) {
+				ucred, uErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+			}); cErr == nil && uErr == nil {
+				info.ucred = ucred
+			}
+		}
+	}
+
+	return conn, info, nil
+}
+
+// ClientHandshake implements credentials.TransportCredentials, the
+// daemon never dials out with these credentials
+func (peerCred) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return conn, peerCredAuthInfo{CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity}}, nil
+}
+
+// Info implements credentials.TransportCredentials
+func (peerCred) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "peercred"}
+}
+
+// Clone implements credentials.TransportCredentials
+func (c peerCred) Clone() credentials.TransportCredentials {
+	return c
+}
+
+// OverrideServerName implements credentials.TransportCredentials
+func (peerCred) OverrideServerName(string) error {
+	return nil
+}
+
+// callerIsRoot reports whether the calling process runs as root,
+// unknown credentials are treated as non-root
+func callerIsRoot(ctx context.Context) bool {
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(peerCredAuthInfo); ok && info.ucred != nil && info.ucred.Uid == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkAdminAccess rejects admin-only RPCs for every caller except
+// root, unknown credentials are treated as non-root
+func checkAdminAccess(ctx context.Context, fullMethod string) error {
+	if !adminOnlyMethods[fullMethod] {
+		return nil
+	}
+	if callerIsRoot(ctx) {
+		return nil
+	}
+
+	return grpcstatus.Errorf(codes.PermissionDenied, "only root may call %s", fullMethod)
+}
+
+// knownBuildPrivileges lists the build features the administrator can
+// delegate to non-root callers via delegated_privileges
+var knownBuildPrivileges = map[string]bool{
+	"cap-add": true,
+}
+
+// requestedBuildPrivileges lists the privileged features a build
+// request asks for
+func requestedBuildPrivileges(req *pb.BuildRequest) []string {
+	var privileges []string
+	if len(req.GetCapAddList()) != 0 {
+		privileges = append(privileges, "cap-add")
+	}
+
+	return privileges
+}
+
+// checkBuildPrivileges rejects builds of non-root callers asking for
+// privileged features the administrator has not delegated, naming
+// every denied feature so the client can drop or request them
+func (d *Daemon) checkBuildPrivileges(ctx context.Context, req *pb.BuildRequest) error {
+	if callerIsRoot(ctx) {
+		return nil
+	}
+
+	delegated := make(map[string]bool)
+	for _, privilege := range strings.Split(d.opts.DelegatedPrivileges, ",") {
+		// unknown names in the configuration delegate nothing
+		if privilege = strings.TrimSpace(privilege); knownBuildPrivileges[privilege] {
+			delegated[privilege] = true
+		}
+	}
+
+	var denied []string
+	for _, privilege := range requestedBuildPrivileges(req) {
+		if !delegated[privilege] {
+			denied = append(denied, privilege)
+		}
+	}
+	if len(denied) != 0 {
+		return grpcstatus.Errorf(codes.PermissionDenied,
+			"the build requests privileges not delegated to non-root users: %s", strings.Join(denied, ", "))
+	}
+
+	return nil
+}
+
+// checkSocketAccess rejects RPCs outside the build socket whitelist
+// for connections arriving on the restricted build socket
+func (d *Daemon) checkSocketAccess(ctx context.Context, fullMethod string) error {
+	if d.buildSockPath == "" || buildSocketMethods[fullMethod] {
+		return nil
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(peerCredAuthInfo); ok && info.localAddr == d.buildSockPath {
+			return grpcstatus.Errorf(codes.PermissionDenied, "RPC %s is not available on the build socket", fullMethod)
+		}
+	}
+
+	return nil
+}
+
+// authUnaryInterceptor gates unary RPCs with the access checks
+func (d *Daemon) authUnaryInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := checkAdminAccess(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	if err := d.checkSocketAccess(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authStreamInterceptor gates streaming RPCs with the access checks
+func (d *Daemon) authStreamInterceptor(srv interface{}, ss grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := checkAdminAccess(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	if err := d.checkSocketAccess(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
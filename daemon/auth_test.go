@@ -0,0 +1,118 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: gRPC peer credential authorization tests
+
+package daemon
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/peer"
+	"gotest.tools/v3/assert"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+)
+
+func TestCheckAdminAccess(t *testing.T) {
+	peerCtx := func(ucred *unix.Ucred) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: peerCredAuthInfo{ucred: ucred}})
+	}
+
+	// root may call admin-only RPCs
+	err := checkAdminAccess(peerCtx(&unix.Ucred{Uid: 0}), "/isula.build.v1.Control/Prune")
+	assert.NilError(t, err)
+
+	// group members may not
+	err = checkAdminAccess(peerCtx(&unix.Ucred{Uid: 1000}), "/isula.build.v1.Control/Prune")
+	assert.ErrorContains(t, err, "only root may call")
+
+	// unknown credentials are treated as non-root
+	err = checkAdminAccess(context.Background(), "/isula.build.v1.Control/Prune")
+	assert.ErrorContains(t, err, "only root may call")
+
+	// other RPCs stay open to everyone on the socket
+	err = checkAdminAccess(peerCtx(&unix.Ucred{Uid: 1000}), "/isula.build.v1.Control/Build")
+	assert.NilError(t, err)
+}
+
+func TestCheckSocketAccess(t *testing.T) {
+	peerCtx := func(localAddr string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: peerCredAuthInfo{localAddr: localAddr}})
+	}
+	d := &Daemon{buildSockPath: "/var/run/isula_build_build.sock"}
+
+	// build RPCs are served on the build socket
+	err := d.checkSocketAccess(peerCtx(d.buildSockPath), "/isula.build.v1.Control/Build")
+	assert.NilError(t, err)
+
+	// admin RPCs are not
+	err = d.checkSocketAccess(peerCtx(d.buildSockPath), "/isula.build.v1.Control/Prune")
+	assert.ErrorContains(t, err, "not available on the build socket")
+
+	// the main socket serves everything
+	err = d.checkSocketAccess(peerCtx("/var/run/isula_build.sock"), "/isula.build.v1.Control/Prune")
+	assert.NilError(t, err)
+
+	// without a build socket nothing is restricted
+	err = (&Daemon{}).checkSocketAccess(peerCtx(d.buildSockPath), "/isula.build.v1.Control/Prune")
+	assert.NilError(t, err)
+}
+
+func TestCheckBuildPrivileges(t *testing.T) {
+	peerCtx := func(ucred *unix.Ucred) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: peerCredAuthInfo{ucred: ucred}})
+	}
+	capReq := &pb.BuildRequest{CapAddList: []string{"CAP_SYS_ADMIN"}}
+
+	// root keeps every privilege
+	d := &Daemon{opts: &Options{}}
+	err := d.checkBuildPrivileges(peerCtx(&unix.Ucred{Uid: 0}), capReq)
+	assert.NilError(t, err)
+
+	// non-root callers are denied privileges that are not delegated
+	err = d.checkBuildPrivileges(peerCtx(&unix.Ucred{Uid: 1000}), capReq)
+	assert.ErrorContains(t, err, "privileges not delegated to non-root users: cap-add")
+
+	// builds without privileged features stay open
+	err = d.checkBuildPrivileges(peerCtx(&unix.Ucred{Uid: 1000}), &pb.BuildRequest{})
+	assert.NilError(t, err)
+
+	// delegated privileges pass
+	d = &Daemon{opts: &Options{DelegatedPrivileges: "cap-add"}}
+	err = d.checkBuildPrivileges(peerCtx(&unix.Ucred{Uid: 1000}), capReq)
+	assert.NilError(t, err)
+
+	// unknown names in the configuration delegate nothing
+	d = &Daemon{opts: &Options{DelegatedPrivileges: "everything"}}
+	err = d.checkBuildPrivileges(peerCtx(&unix.Ucred{Uid: 1000}), capReq)
+	assert.ErrorContains(t, err, "cap-add")
+}
+
+func TestParseSockMode(t *testing.T) {
+	mode, err := parseSockMode("")
+	assert.NilError(t, err)
+	assert.Equal(t, mode, os.FileMode(constant.DefaultGroupFileMode))
+
+	mode, err = parseSockMode("0600")
+	assert.NilError(t, err)
+	assert.Equal(t, uint32(mode), uint32(0600))
+
+	_, err = parseSockMode("abc")
+	assert.ErrorContains(t, err, "invalid socket mode")
+
+	_, err = parseSockMode("1777")
+	assert.ErrorContains(t, err, "invalid socket mode")
+}
@@ -0,0 +1,338 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is "backup" and "restore" commands for backend
+
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containers/image/v5/types"
+	"github.com/containers/storage/pkg/archive"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/exporter"
+	"isula.org/isula-build/image"
+	"isula.org/isula-build/pkg/logger"
+	"isula.org/isula-build/pkg/version"
+	"isula.org/isula-build/util"
+)
+
+const (
+	// backupFormatVersion is bumped when the archive layout changes
+	backupFormatVersion = 1
+	// backupMetadataFile carries the store snapshot description
+	backupMetadataFile = "metadata.json"
+	// backupImagesFile is the docker-archive with the selected images
+	backupImagesFile = "images.tar"
+)
+
+// backupImage describes one image recorded in a backup archive
+type backupImage struct {
+	ID      string    `json:"id"`
+	Names   []string  `json:"names,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// backupMetadata describes a backup archive, it travels inside the
+// archive so a restore can validate what it is given
+type backupMetadata struct {
+	Version       int           `json:"version"`
+	Created       time.Time     `json:"created"`
+	DaemonVersion string        `json:"daemonVersion"`
+	Images        []backupImage `json:"images"`
+}
+
+// Backup snapshots the store metadata and the selected images into a
+// portable archive, with no image given all tagged images are taken
+func (b *Backend) Backup(ctx context.Context, req *pb.BackupRequest) (*pb.BackupResponse, error) {
+	logEntry := logrus.WithFields(logrus.Fields{"BackupID": req.GetBackupID()})
+	logEntry.WithField("Path", req.GetPath()).Info("BackupRequest received")
+
+	imgList, err := b.selectBackupImages(req.GetImages())
+	if err != nil {
+		return &pb.BackupResponse{}, err
+	}
+
+	tmpDir, err := securejoin.SecureJoin(b.daemon.opts.DataRoot,
+		filepath.Join(constant.DataRootTmpDirPrefix, req.GetBackupID()))
+	if err != nil {
+		return &pb.BackupResponse{}, err
+	}
+	if err = os.MkdirAll(tmpDir, constant.DefaultRootDirMode); err != nil {
+		return &pb.BackupResponse{}, err
+	}
+	defer func() {
+		if rErr := os.RemoveAll(tmpDir); rErr != nil {
+			logEntry.Warnf("Removing backup tmp directory %q failed: %v", tmpDir, rErr)
+		}
+	}()
+
+	opts := SaveOptions{
+		sysCtx:            image.GetSystemContext(),
+		localStore:        b.daemon.localStore,
+		saveID:            req.GetBackupID(),
+		format:            constant.DockerTransport,
+		oriImgList:        imgList,
+		finalImageOrdered: make([]string, 0),
+		finalImageSet:     make(map[string]*savedImage),
+		outputPath:        filepath.Join(tmpDir, backupImagesFile),
+		logger:            logger.NewCliLogger(constant.CliLogBufferLen),
+		logEntry:          logEntry,
+		platform:          &exporter.PlatformOverride{},
+	}
+	if err = opts.manage(); err != nil {
+		return &pb.BackupResponse{}, errors.Wrap(err, "check backup options failed")
+	}
+
+	ctx = context.WithValue(ctx, util.LogFieldKey(util.LogKeySessionID), req.GetBackupID())
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(exportHandler(egCtx, &opts))
+	eg.Go(func() error {
+		for content := range opts.logger.GetContent() {
+			if content != "" {
+				logEntry.Info(strings.TrimSpace(content))
+			}
+		}
+		return nil
+	})
+	if err = eg.Wait(); err != nil {
+		return &pb.BackupResponse{}, errors.Wrap(err, "export backup images failed")
+	}
+
+	metadata, err := b.backupMetadataFor(opts.finalImageOrdered)
+	if err != nil {
+		return &pb.BackupResponse{}, err
+	}
+	data, err := json.MarshalIndent(metadata, "", "    ")
+	if err != nil {
+		return &pb.BackupResponse{}, errors.Wrap(err, "marshal backup metadata failed")
+	}
+	if err = ioutil.WriteFile(filepath.Join(tmpDir, backupMetadataFile), data, constant.DefaultRootFileMode); err != nil {
+		return &pb.BackupResponse{}, errors.Wrap(err, "write backup metadata failed")
+	}
+
+	if err = util.PackFiles(tmpDir, req.GetPath(), archive.Uncompressed, false); err != nil {
+		return &pb.BackupResponse{}, errors.Wrapf(err, "pack backup archive %q failed", req.GetPath())
+	}
+	logEntry.Infof("Backed up %d images to %q", len(metadata.Images), req.GetPath())
+
+	return &pb.BackupResponse{Images: imgList}, nil
+}
+
+// selectBackupImages resolves the requested selection, with an empty
+// selection every tagged image of the store is taken
+func (b *Backend) selectBackupImages(requested []string) ([]string, error) {
+	if len(requested) != 0 {
+		return requested, nil
+	}
+
+	images, err := b.daemon.localStore.Images()
+	if err != nil {
+		return nil, err
+	}
+	var imgList []string
+	for _, img := range images {
+		imgList = append(imgList, img.Names...)
+	}
+	if len(imgList) == 0 {
+		return nil, errors.New("no tagged image in the store to back up")
+	}
+
+	return imgList, nil
+}
+
+// backupMetadataFor assembles the metadata of the backed up images
+func (b *Backend) backupMetadataFor(imageIDs []string) (backupMetadata, error) {
+	metadata := backupMetadata{
+		Version:       backupFormatVersion,
+		Created:       time.Now().UTC(),
+		DaemonVersion: version.Version,
+	}
+	for _, imageID := range imageIDs {
+		_, img, err := image.FindImage(b.daemon.localStore, imageID)
+		if err != nil {
+			return backupMetadata{}, errors.Wrapf(err, "find image %q for backup metadata failed", imageID)
+		}
+		metadata.Images = append(metadata.Images, backupImage{
+			ID:      img.ID,
+			Names:   img.Names,
+			Created: img.Created,
+		})
+	}
+
+	return metadata, nil
+}
+
+// Restore loads the images of a backup archive into the store, the
+// names recorded at backup time are restored with them
+func (b *Backend) Restore(ctx context.Context, req *pb.RestoreRequest) (*pb.RestoreResponse, error) {
+	logEntry := logrus.WithFields(logrus.Fields{"RestoreID": req.GetRestoreID()})
+	logEntry.WithField("Path", req.GetPath()).Info("RestoreRequest received")
+
+	if err := util.CheckFileInfoAndSize(req.GetPath(), constant.MaxLoadFileSize); err != nil {
+		return &pb.RestoreResponse{}, err
+	}
+
+	tmpDir, err := securejoin.SecureJoin(b.daemon.opts.DataRoot,
+		filepath.Join(constant.DataRootTmpDirPrefix, req.GetRestoreID()))
+	if err != nil {
+		return &pb.RestoreResponse{}, err
+	}
+	if err = os.MkdirAll(tmpDir, constant.DefaultRootDirMode); err != nil {
+		return &pb.RestoreResponse{}, err
+	}
+	defer func() {
+		if rErr := os.RemoveAll(tmpDir); rErr != nil {
+			logEntry.Warnf("Removing restore tmp directory %q failed: %v", tmpDir, rErr)
+		}
+	}()
+
+	if err = util.UnpackFile(req.GetPath(), tmpDir, archive.Uncompressed, false); err != nil {
+		return &pb.RestoreResponse{}, errors.Wrapf(err, "unpack backup archive %q failed", req.GetPath())
+	}
+	metadata, err := readBackupMetadata(tmpDir)
+	if err != nil {
+		return &pb.RestoreResponse{}, err
+	}
+	logEntry.Infof("Restoring backup of %d images created %s by isula-build %s",
+		len(metadata.Images), metadata.Created.Format(constant.LayoutTime), metadata.DaemonVersion)
+
+	imagesPath := filepath.Join(tmpDir, backupImagesFile)
+	opts := LoadOptions{path: imagesPath, LogEntry: logEntry}
+	if err = util.CheckFileInfoAndSize(imagesPath, constant.MaxLoadFileSize); err != nil {
+		return &pb.RestoreResponse{}, errors.Wrap(err, "check backup images archive failed")
+	}
+	imagesInTar, err := tryToParseImageFormatFromTarball(b.daemon.opts.DataRoot, &opts)
+	if err != nil {
+		return &pb.RestoreResponse{}, err
+	}
+
+	restored, err := b.restoreImages(ctx, &opts, imagesInTar)
+	if err != nil {
+		return &pb.RestoreResponse{}, err
+	}
+	logEntry.Infof("Restored %d images from %q", len(restored), req.GetPath())
+
+	return &pb.RestoreResponse{Images: restored}, nil
+}
+
+// readBackupMetadata reads and validates the archive metadata
+func readBackupMetadata(tmpDir string) (backupMetadata, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(filepath.Join(tmpDir, backupMetadataFile)))
+	if err != nil {
+		return backupMetadata{}, errors.Wrap(err, "read backup metadata failed, not a backup archive")
+	}
+	var metadata backupMetadata
+	if err = json.Unmarshal(data, &metadata); err != nil {
+		return backupMetadata{}, errors.Wrap(err, "parse backup metadata failed")
+	}
+	if metadata.Version != backupFormatVersion {
+		return backupMetadata{}, errors.Errorf("unsupported backup format version %d, expected %d",
+			metadata.Version, backupFormatVersion)
+	}
+
+	return metadata, nil
+}
+
+// restoreImages commits the images of the backup into the store, a
+// failure rolls the batch back like an interrupted load
+func (b *Backend) restoreImages(ctx context.Context, opts *LoadOptions, imagesInTar []singleImage) ([]string, error) {
+	layersBefore, err := b.snapshotLayerIDs()
+	if err != nil {
+		return nil, err
+	}
+	imagesBefore, err := b.snapshotImageIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	log := logger.NewCliLogger(constant.CliLogBufferLen)
+	progress := make(chan types.ProgressProperties, constant.CliLogBufferLen)
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		for content := range log.GetContent() {
+			if content != "" {
+				opts.LogEntry.Info(strings.TrimSpace(content))
+			}
+		}
+		return nil
+	})
+	eg.Go(func() error {
+		for range progress {
+		}
+		return nil
+	})
+
+	var restored []string
+	eg.Go(func() error {
+		defer log.CloseContent()
+		defer close(progress)
+
+		var loaded []loadedImage
+		for _, single := range imagesInTar {
+			_, si, rErr := image.ResolveFromImage(&image.PrepareImageOptions{
+				Ctx:              egCtx,
+				FromImage:        exporter.FormatTransport(opts.format, opts.path),
+				ToImage:          single.id,
+				SystemContext:    image.GetSystemContext(),
+				Store:            b.daemon.localStore,
+				Reporter:         log,
+				ManifestIndex:    single.index,
+				Progress:         progress,
+				ProgressInterval: loadProgressInterval,
+			})
+			if rErr != nil {
+				b.rollbackLoadedImages(loaded, opts.LogEntry)
+				b.cleanupPartialLayers(layersBefore, opts.LogEntry)
+				return rErr
+			}
+			originalNames, nErr := b.daemon.localStore.Names(si.ID)
+			if nErr != nil {
+				b.rollbackLoadedImages(loaded, opts.LogEntry)
+				b.cleanupPartialLayers(layersBefore, opts.LogEntry)
+				return nErr
+			}
+			if sErr := b.daemon.localStore.SetNames(si.ID, append(originalNames, single.nameTag...)); sErr != nil {
+				b.rollbackLoadedImages(loaded, opts.LogEntry)
+				b.cleanupPartialLayers(layersBefore, opts.LogEntry)
+				return sErr
+			}
+			loaded = append(loaded, loadedImage{
+				id:            si.ID,
+				existedBefore: imagesBefore[si.ID],
+				originalNames: originalNames,
+			})
+			restored = append(restored, si.ID)
+			opts.LogEntry.Infof("Restored image as %s", si.ID)
+		}
+
+		return nil
+	})
+
+	if err = eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
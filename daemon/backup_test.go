@@ -0,0 +1,62 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: backup and restore backend test
+
+package daemon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestReadBackupMetadata(t *testing.T) {
+	valid := backupMetadata{
+		Version:       backupFormatVersion,
+		Created:       time.Now().UTC(),
+		DaemonVersion: "0.9.6",
+		Images: []backupImage{
+			{ID: "abc", Names: []string{"busybox:latest"}},
+		},
+	}
+	validData, err := json.Marshal(valid)
+	assert.NilError(t, err)
+
+	future := valid
+	future.Version = backupFormatVersion + 1
+	futureData, err := json.Marshal(future)
+	assert.NilError(t, err)
+
+	tmpDir := fs.NewDir(t, t.Name(),
+		fs.WithDir("valid", fs.WithFile(backupMetadataFile, string(validData))),
+		fs.WithDir("future", fs.WithFile(backupMetadataFile, string(futureData))),
+		fs.WithDir("broken", fs.WithFile(backupMetadataFile, "not json")),
+		fs.WithDir("empty"))
+	defer tmpDir.Remove()
+
+	metadata, err := readBackupMetadata(tmpDir.Join("valid"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(metadata.Images), 1)
+	assert.Equal(t, metadata.Images[0].Names[0], "busybox:latest")
+
+	_, err = readBackupMetadata(tmpDir.Join("future"))
+	assert.ErrorContains(t, err, "unsupported backup format version")
+
+	_, err = readBackupMetadata(tmpDir.Join("broken"))
+	assert.ErrorContains(t, err, "parse backup metadata failed")
+
+	_, err = readBackupMetadata(tmpDir.Join("empty"))
+	assert.ErrorContains(t, err, "not a backup archive")
+}
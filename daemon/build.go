@@ -15,24 +15,131 @@ package daemon
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/pkg/deprecation"
+	"isula.org/isula-build/pkg/logger"
 	"isula.org/isula-build/util"
 )
 
+// buildRequestFeatures lists the feature keys a build request uses, the
+// deprecation notices registered for them are sent back to the client
+func buildRequestFeatures(req *pb.BuildRequest) []string {
+	features := []string{
+		"build.format." + req.GetFormat(),
+		"build.output." + strings.SplitN(req.GetOutput(), ":", 2)[0],
+	}
+	if req.GetBuildStatic() != nil {
+		features = append(features, "build.flag.build-static")
+	}
+	return features
+}
+
+// resolveBuildTimeout picks the deadline of one build, the request
+// value overrides the daemon default and empty leaves the build untimed
+func resolveBuildTimeout(reqTimeout, defaultTimeout string) (time.Duration, error) {
+	spec := defaultTimeout
+	if reqTimeout != "" {
+		spec = reqTimeout
+	}
+	if spec == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(spec)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parse build timeout %q failed", spec)
+	}
+	if timeout <= 0 {
+		return 0, errors.Errorf("build timeout %q must be positive", spec)
+	}
+
+	return timeout, nil
+}
+
 // Build receives a build request and build an image
 func (b *Backend) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
 	b.wg.Add(1)
 	defer b.wg.Done()
 	logEntry := logrus.WithFields(logrus.Fields{"BuildType": req.GetBuildType(), "BuildID": req.GetBuildID()})
 	logEntry.Info("BuildRequest received")
+	deprecation.Attach(ctx, buildRequestFeatures(req)...)
 
 	ctx = context.WithValue(ctx, util.LogFieldKey(util.LogKeySessionID), req.BuildID)
+	if lvlStr := req.GetLogLevel(); lvlStr != "" {
+		lvl, pErr := logrus.ParseLevel(lvlStr)
+		if pErr != nil {
+			logEntry.Error(pErr)
+			return &pb.BuildResponse{}, errors.Wrapf(pErr, "parse log level %q failed", lvlStr)
+		}
+		// only sessions requesting more than the daemon's configured
+		// verbosity get the daemon log lines streamed back
+		if lvl >= logrus.DebugLevel {
+			logger.RegisterSession(req.BuildID, lvl)
+			defer logger.DeregisterSession(req.BuildID)
+		}
+	}
+	if aErr := b.applyBuilderInstance(req); aErr != nil {
+		logEntry.Error(aErr)
+		return &pb.BuildResponse{}, aErr
+	}
+	if pErr := b.daemon.checkBuildPrivileges(ctx, req); pErr != nil {
+		logEntry.Error(pErr)
+		return &pb.BuildResponse{}, pErr
+	}
+	// a team at its storage quota is rejected before the build holds
+	// any resources, the error names the quota and the current usage
+	owner := b.daemon.callerOwner(ctx)
+	if qErr := b.daemon.checkTeamQuota(owner); qErr != nil {
+		logEntry.Error(qErr)
+		return &pb.BuildResponse{}, qErr
+	}
+	timeout, tErr := resolveBuildTimeout(req.GetTimeout(), b.daemon.opts.BuildTimeout)
+	if tErr != nil {
+		logEntry.Error(tErr)
+		return &pb.BuildResponse{}, tErr
+	}
+
+	// excess builds wait for a free build slot in FIFO order instead of
+	// all running at once, the queue position is streamed to the client
+	b.syncBuildStatus(req.BuildID) <- struct{}{}
+	b.closeStatusChan(req.BuildID)
+	if qErr := b.daemon.buildSlots().acquire(ctx, req.BuildID, func(position int) {
+		if st := b.getStatus(req.BuildID); st != nil {
+			st.append(fmt.Sprintf("Waiting for a free build slot, queue position %d\n", position))
+		}
+	}); qErr != nil {
+		logEntry.Error(qErr)
+		if st := b.getStatus(req.BuildID); st != nil {
+			st.finish()
+		}
+		b.deleteStatus(req.BuildID)
+		return &pb.BuildResponse{}, qErr
+	}
+	defer b.daemon.buildSlots().release()
+
+	// the deadline starts once the build holds a slot, a stuck RUN step
+	// is killed through the context the runtime command runs under and
+	// the deferred CleanResources rolls the build back
+	if timeout != 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+		defer cancelTimeout()
+	}
+
 	builder, nErr := b.daemon.NewBuilder(ctx, req)
 	if nErr != nil {
 		logEntry.Error(nErr)
+		if st := b.getStatus(req.BuildID); st != nil {
+			st.finish()
+		}
+		b.deleteStatus(req.BuildID)
 		return &pb.BuildResponse{}, nErr
 	}
 
@@ -44,13 +151,43 @@ func (b *Backend) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildRes
 		b.deleteStatus(req.BuildID)
 	}()
 
-	b.syncBuildStatus(req.BuildID) <- struct{}{}
-	b.closeStatusChan(req.BuildID)
+	// the collector owns the builder log channels, Status streams only
+	// read the buffer it fills and may come and go while building
+	go b.collectStatus(req.BuildID, builder)
+	startTime := time.Now()
 	imageID, bErr := builder.Build()
+	if bErr != nil && ctx.Err() == context.DeadlineExceeded {
+		bErr = errors.Wrapf(bErr, "build timed out after %v", timeout)
+	}
+	record := buildRecord{
+		BuildID:    req.GetBuildID(),
+		BuildType:  req.GetBuildType(),
+		ImageID:    imageID,
+		Status:     "success",
+		StartTime:  startTime,
+		FinishTime: time.Now(),
+	}
 	if bErr != nil {
+		record.Status = "failed"
+		record.Error = bErr.Error()
+		b.writeBuildRecord(record)
 		logEntry.Error(bErr)
 		return &pb.BuildResponse{}, bErr
 	}
+	b.writeBuildRecord(record)
+	if oErr := b.daemon.recordImageOwner(imageID, owner); oErr != nil {
+		logEntry.Warnf("Record owner of image %s failed: %v", imageID, oErr)
+	}
+
+	resp := &pb.BuildResponse{ImageID: imageID}
+	if format := req.GetGraphFormat(); format != "" {
+		graph, gErr := builder.Graph(format)
+		if gErr != nil {
+			logEntry.Error(gErr)
+			return resp, gErr
+		}
+		resp.BuildGraph = string(graph)
+	}
 
-	return &pb.BuildResponse{ImageID: imageID}, nil
+	return resp, nil
 }
@@ -0,0 +1,77 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file tests build backend helpers
+
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestResolveBuildTimeout(t *testing.T) {
+	var testcases = []struct {
+		name           string
+		reqTimeout     string
+		defaultTimeout string
+		want           time.Duration
+		wantErr        bool
+		errString      string
+	}{
+		{
+			name: "TC1 - both empty leaves the build untimed",
+			want: 0,
+		},
+		{
+			name:           "TC2 - daemon default applies",
+			defaultTimeout: "2h",
+			want:           2 * time.Hour,
+		},
+		{
+			name:           "TC3 - request overrides the default",
+			reqTimeout:     "30m",
+			defaultTimeout: "2h",
+			want:           30 * time.Minute,
+		},
+		{
+			name:       "TC4 - malformed duration",
+			reqTimeout: "soon",
+			wantErr:    true,
+			errString:  "parse build timeout",
+		},
+		{
+			name:       "TC5 - zero duration",
+			reqTimeout: "0s",
+			wantErr:    true,
+			errString:  "must be positive",
+		},
+		{
+			name:           "TC6 - negative default",
+			defaultTimeout: "-1h",
+			wantErr:        true,
+			errString:      "must be positive",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			timeout, err := resolveBuildTimeout(tc.reqTimeout, tc.defaultTimeout)
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, timeout, tc.want)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: This file is "builds list" command for backend
+
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/sirupsen/logrus"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/util"
+)
+
+// BuildsList lists the builds currently running in the daemon
+func (b *Backend) BuildsList(ctx context.Context, req *pb.BuildsListRequest) (*pb.BuildsListResponse, error) {
+	logrus.Info("BuildsListRequest received")
+
+	builds := b.daemon.reg().list()
+	infos := make([]*pb.BuildsListResponse_BuildInfo, 0, len(builds))
+	for _, build := range builds {
+		currentStage, totalStages := build.builder.Progress()
+		infos = append(infos, &pb.BuildsListResponse_BuildInfo{
+			BuildID:   build.buildID,
+			StartTime: build.startTime.Format(constant.LayoutTime),
+			Duration:  time.Since(build.startTime).Round(time.Second).String(),
+			Stage:     fmt.Sprintf("%d/%d", currentStage, totalStages),
+			TmpSize:   b.buildTmpSize(build.buildID),
+		})
+	}
+
+	return &pb.BuildsListResponse{Builds: infos, Containers: b.buildContainers()}, nil
+}
+
+// buildContainers lists the ephemeral build containers in the store so
+// leaked containers can be attributed to the builds naming them
+func (b *Backend) buildContainers() []*pb.BuildsListResponse_ContainerInfo {
+	containers, err := b.daemon.localStore.Containers()
+	if err != nil {
+		logrus.Warnf("List build containers failed: %v", err)
+		return nil
+	}
+
+	infos := make([]*pb.BuildsListResponse_ContainerInfo, 0, len(containers))
+	for _, container := range containers {
+		var name string
+		if len(container.Names) != 0 {
+			name = container.Names[0]
+		}
+		id := container.ID
+		if len(id) > constant.DefaultIDLen {
+			id = id[:constant.DefaultIDLen]
+		}
+		imageID := container.ImageID
+		if len(imageID) > constant.DefaultIDLen {
+			imageID = imageID[:constant.DefaultIDLen]
+		}
+		infos = append(infos, &pb.BuildsListResponse_ContainerInfo{
+			Name:    name,
+			Id:      id,
+			Image:   imageID,
+			Created: container.Created.Format(constant.LayoutTime),
+		})
+	}
+
+	return infos
+}
+
+// buildTmpSize returns the formatted size of the temporary dir of the
+// build, an empty string when the size can not be determined
+func (b *Backend) buildTmpSize(buildID string) string {
+	tmpDir, err := securejoin.SecureJoin(b.daemon.opts.DataRoot, filepath.Join(constant.DataRootTmpDirPrefix, buildID))
+	if err != nil {
+		logrus.Warnf("Join temporary dir for build %s failed: %v", buildID, err)
+		return ""
+	}
+	size, err := util.GetDirSize(tmpDir)
+	if err != nil {
+		logrus.Warnf("Get temporary dir size for build %s failed: %v", buildID, err)
+		return ""
+	}
+	return util.FormatSize(float64(size), decimalPrefixBase)
+}
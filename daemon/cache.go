@@ -0,0 +1,123 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-01-10
+// Description: This file is "cache" command for backend
+
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/pkg/urlcache"
+	"isula.org/isula-build/util"
+)
+
+// CacheList lists the entries in the daemon url download cache
+func (b *Backend) CacheList(ctx context.Context, req *pb.CacheListRequest) (*pb.CacheListResponse, error) {
+	logrus.Info("CacheListRequest received")
+
+	cache := urlcache.Default()
+	if cache == nil {
+		return nil, errors.New("url cache is not initialized")
+	}
+
+	entries, err := cache.List()
+	if err != nil {
+		return nil, errors.Wrap(err, "list url cache entries failed")
+	}
+
+	result := make([]*pb.CacheListResponse_CacheInfo, 0, len(entries))
+	for i := range entries {
+		result = append(result, &pb.CacheListResponse_CacheInfo{
+			Key:      entries[i].Key,
+			Url:      entries[i].URL,
+			Size_:    util.FormatSize(float64(entries[i].Size), decimalPrefixBase),
+			Created:  entries[i].CreatedAt.Format(constant.LayoutTime),
+			LastUsed: entries[i].LastUsedAt.Format(constant.LayoutTime),
+		})
+	}
+
+	return &pb.CacheListResponse{Caches: result}, nil
+}
+
+// CacheRemove removes entries from the daemon url download cache
+func (b *Backend) CacheRemove(ctx context.Context, req *pb.CacheRemoveRequest) (*pb.CacheRemoveResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"Keys": strings.Join(req.GetKeys(), ", "),
+		"All":  req.GetAll(),
+	}).Info("CacheRemoveRequest received")
+
+	cache := urlcache.Default()
+	if cache == nil {
+		return nil, errors.New("url cache is not initialized")
+	}
+
+	keys := req.GetKeys()
+	if req.GetAll() {
+		entries, err := cache.List()
+		if err != nil {
+			return nil, errors.Wrap(err, "list url cache entries failed")
+		}
+		keys = make([]string, 0, len(entries))
+		for i := range entries {
+			keys = append(keys, entries[i].Key)
+		}
+	}
+	if len(keys) == 0 {
+		return &pb.CacheRemoveResponse{Result: "No cache entry removed"}, nil
+	}
+
+	for _, key := range keys {
+		fullKey, err := b.resolveCacheKey(cache, key)
+		if err != nil {
+			return nil, err
+		}
+		if err := cache.Remove(fullKey); err != nil {
+			return nil, errors.Wrapf(err, "remove url cache entry %q failed", key)
+		}
+	}
+
+	return &pb.CacheRemoveResponse{Result: fmt.Sprintf("Removed %d cache entries", len(keys))}, nil
+}
+
+// resolveCacheKey resolves key to an unique full cache key, the
+// key can be a prefix as shown by "cache ls"
+func (b *Backend) resolveCacheKey(cache *urlcache.Cache, key string) (string, error) {
+	entries, err := cache.List()
+	if err != nil {
+		return "", errors.Wrap(err, "list url cache entries failed")
+	}
+
+	var matched []string
+	for i := range entries {
+		if entries[i].Key == key {
+			return key, nil
+		}
+		if strings.HasPrefix(entries[i].Key, key) {
+			matched = append(matched, entries[i].Key)
+		}
+	}
+	if len(matched) == 0 {
+		return "", errors.Errorf("no cache entry with key %q", key)
+	}
+	if len(matched) > 1 {
+		return "", errors.Errorf("cache key %q is ambiguous", key)
+	}
+
+	return matched[0], nil
+}
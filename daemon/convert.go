@@ -0,0 +1,160 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-04-08
+// Description: This file is "convert" command for backend
+
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+
+	cp "github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/transports/alltransports"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/builder/dockerfile"
+	"isula.org/isula-build/exporter"
+	"isula.org/isula-build/image"
+	"isula.org/isula-build/pkg/logger"
+	"isula.org/isula-build/util"
+)
+
+// convertFormats lists the transports an image can be converted to
+var convertFormats = map[string]bool{
+	constant.DockerArchiveTransport: true,
+	constant.OCIArchiveTransport:    true,
+	constant.OCITransport:           true,
+}
+
+// Convert converts an image between archive formats offline, without
+// committing it to the image store
+func (b *Backend) Convert(req *pb.ConvertRequest, stream pb.Control_ConvertServer) error {
+	var (
+		ctx       = stream.Context()
+		convertID = req.GetConvertID()
+	)
+	logEntry := logrus.WithFields(logrus.Fields{"ConvertID": convertID})
+	logEntry.Info("ConvertRequest received")
+
+	if !convertFormats[req.GetFormat()] {
+		return errors.Errorf("the convert format %q is not supported, choose %q, %q or %q",
+			req.GetFormat(), constant.DockerArchiveTransport, constant.OCIArchiveTransport, constant.OCITransport)
+	}
+	if exist, err := util.IsExist(req.GetDestination()); err != nil {
+		return err
+	} else if exist {
+		return errors.Errorf("the convert destination %q already exists", req.GetDestination())
+	}
+
+	srcFormat, err := detectSourceFormat(b.daemon.opts.DataRoot, req.GetSource())
+	if err != nil {
+		logEntry.Error(err)
+		return err
+	}
+	srcRef, err := alltransports.ParseImageName(exporter.FormatTransport(srcFormat, req.GetSource()))
+	if err != nil {
+		logEntry.Error(err)
+		return err
+	}
+	dstRef, err := alltransports.ParseImageName(exporter.FormatTransport(req.GetFormat(), req.GetDestination()))
+	if err != nil {
+		logEntry.Error(err)
+		return err
+	}
+
+	policyContext, err := dockerfile.GetPolicyContext()
+	if err != nil {
+		logEntry.Error(err)
+		return err
+	}
+	defer func() {
+		if dErr := policyContext.Destroy(); dErr != nil {
+			logEntry.Debugf("Error destroying signature policy context: %v", dErr)
+		}
+	}()
+
+	log := logger.NewCliLogger(constant.CliLogBufferLen)
+	imageCopyOptions := image.NewImageCopyOptions(log)
+	tmpDir, err := securejoin.SecureJoin(b.daemon.opts.DataRoot, filepath.Join(constant.DataRootTmpDirPrefix, convertID))
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(tmpDir, constant.DefaultRootDirMode); err != nil {
+		logEntry.Error(err)
+		return err
+	}
+	defer func() {
+		if rErr := os.RemoveAll(tmpDir); rErr != nil {
+			logEntry.Errorf("Failed to remove convert temporary dir %q, err: %v", tmpDir, rErr)
+		}
+	}()
+	imageCopyOptions.SourceCtx.BigFilesTemporaryDir = tmpDir
+	imageCopyOptions.DestinationCtx.BigFilesTemporaryDir = tmpDir
+
+	eg, _ := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		for c := range log.GetContent() {
+			if sErr := stream.Send(&pb.ConvertResponse{
+				Log: c,
+			}); sErr != nil {
+				return sErr
+			}
+		}
+		return nil
+	})
+
+	eg.Go(func() error {
+		defer log.CloseContent()
+		if _, cErr := cp.Image(ctx, policyContext, dstRef, srcRef, imageCopyOptions); cErr != nil {
+			// remove the partially written destination
+			if rErr := os.RemoveAll(req.GetDestination()); rErr != nil && !os.IsNotExist(rErr) {
+				logEntry.Warnf("Removing partial convert destination %q failed: %v", req.GetDestination(), rErr)
+			}
+			return cErr
+		}
+		log.Print("Convert success from %s to %s\n", srcFormat, req.GetFormat())
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		logEntry.Error(err)
+		return err
+	}
+	logEntry.Infof("Convert success from %q(%s) to %q(%s)", req.GetSource(), srcFormat, req.GetDestination(), req.GetFormat())
+
+	return nil
+}
+
+// detectSourceFormat probes whether the convert source is a docker
+// archive, an oci archive or an OCI layout dir
+func detectSourceFormat(dataRoot, source string) (string, error) {
+	if util.IsDirectory(source) {
+		if exist, err := util.IsExist(filepath.Join(source, "index.json")); err != nil {
+			return "", err
+		} else if !exist {
+			return "", errors.Errorf("the convert source dir %q is not an OCI layout", source)
+		}
+		return constant.OCITransport, nil
+	}
+
+	opts := LoadOptions{path: source}
+	if _, err := tryToParseImageFormatFromTarball(dataRoot, &opts); err != nil {
+		return "", errors.Wrapf(err, "detect the format of convert source %q failed", source)
+	}
+
+	return opts.format, nil
+}
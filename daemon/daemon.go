@@ -17,6 +17,7 @@ package daemon
 import (
 	"context"
 	"crypto/rsa"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sync"
@@ -31,13 +32,35 @@ import (
 	constant "isula.org/isula-build"
 	pb "isula.org/isula-build/api/services"
 	"isula.org/isula-build/builder"
+	"isula.org/isula-build/image"
+	"isula.org/isula-build/pkg/cacheregistry"
 	"isula.org/isula-build/pkg/gc"
+	"isula.org/isula-build/pkg/gitcontext"
+	"isula.org/isula-build/pkg/leader"
+	"isula.org/isula-build/pkg/ratelimit"
 	"isula.org/isula-build/pkg/stack"
 	"isula.org/isula-build/pkg/systemd"
+	"isula.org/isula-build/pkg/urlcache"
+	"isula.org/isula-build/pkg/urlcontext"
 	"isula.org/isula-build/store"
 	"isula.org/isula-build/util"
 )
 
+const (
+	// urlCacheDirName is the dir under DataRoot storing the url download cache
+	urlCacheDirName = "url-cache"
+	// cacheRegistryDirName is the dir under DataRoot storing the pull-through cache registry content
+	cacheRegistryDirName = "registry-cache"
+	// checkpointDirName is the dir under DataRoot storing build checkpoints
+	checkpointDirName = "checkpoints"
+	// buildCacheDirName is the dir under DataRoot storing the shared build cache
+	buildCacheDirName = "build-cache"
+	// gitContextDirName is the dir under the build tmp dir holding a fetched git context
+	gitContextDirName = "git-context"
+	// urlContextDirName is the dir under the build tmp dir holding a fetched url tarball context
+	urlContextDirName = "url-context"
+)
+
 // Options carries the options configured to daemon
 type Options struct {
 	Debug         bool
@@ -49,18 +72,108 @@ type Options struct {
 	StorageDriver string
 	StorageOpts   []string
 	RuntimePath   string
+	Standby       bool
+	// CacheRegistryAddr is the address the embedded pull-through cache
+	// registry listens on, empty keeps the service disabled
+	CacheRegistryAddr string
+	// P2PProxy is the HTTP proxy endpoint of a P2P distributor such as
+	// a dragonfly dfdaemon, empty keeps the direct registry access
+	P2PProxy string
+	// P2PNoProxy lists the hosts excepted from P2PProxy, comma separated
+	P2PNoProxy string
+	// RateLimit allows each client that many requests per second on the
+	// gRPC server, zero keeps the server unlimited
+	RateLimit int
+	// RateBurst allows bursts up to that many requests, zero uses RateLimit
+	RateBurst int
+	// MaxRecvMsgSize bounds the size of one received gRPC message in
+	// bytes, zero keeps the grpc default
+	MaxRecvMsgSize int
+	// MaxConcurrentBuilds is how many builds may run at once, excess
+	// builds wait in FIFO order, zero uses the default limit
+	MaxConcurrentBuilds int
+	// MaxParallelDownloads is how many layers one pull copies
+	// concurrently, zero keeps the copy library default
+	MaxParallelDownloads int
+	// MaxParallelUploads is how many layers one push or export copies
+	// concurrently, zero keeps the copy library default
+	MaxParallelUploads int
+	// CopyRetries is how often a failed registry transfer is retried,
+	// zero keeps transfers single-shot
+	CopyRetries int
+	// CopyRetryBackoff is the delay before the first retry like "1s",
+	// doubled after every failed attempt, empty keeps the default
+	CopyRetryBackoff string
+	// DelegatedPrivileges lists the build features non-root callers may
+	// request, comma separated like "cap-add", empty delegates nothing
+	DelegatedPrivileges string
+	// KeepaliveInterval is the interval between server keepalive pings
+	// and idle log stream heartbeats like "30s", empty disables both
+	KeepaliveInterval string
+	// KeepaliveTimeout is how long the server waits for a keepalive
+	// ping ack before closing the connection, empty keeps the grpc default
+	KeepaliveTimeout string
+	// Addr is the unix:// address the gRPC server listens on, empty
+	// uses the default socket path
+	Addr string
+	// SockMode is the octal file mode of the unix socket like "0660",
+	// empty uses the default group read-write mode
+	SockMode string
+	// BuildAddr is the unix:// address of an extra socket serving only
+	// the build related RPCs, empty keeps the single socket setup
+	BuildAddr string
+	// RecordRetention is how long finished build records are kept, a
+	// duration like "720h", empty keeps the records forever
+	RecordRetention string
+	// BuildTimeout fails every build after that long, a duration like
+	// "1h30m", empty lets builds run forever, per-build timeouts
+	// override it
+	BuildTimeout string
+	// Environments maps logical environment names like "dev" to the
+	// registry location their images live in, used by promote
+	Environments map[string]Environment
+	// Teams maps team names to their members and storage quota, images
+	// built by a member count against the quota of the team
+	Teams map[string]Team
+}
+
+// Team is one team of users sharing a storage quota
+type Team struct {
+	// Members lists the usernames or numeric UIDs belonging to the team
+	Members []string
+	// Quota bounds the total size of the images the team owns in
+	// bytes, zero keeps the team unlimited
+	Quota int64
+}
+
+// Environment is the registry location of one logical environment
+type Environment struct {
+	// Registry is the registry host images of the environment are pushed to
+	Registry string
+	// Prefix is an optional repository prefix under the registry
+	Prefix string
 }
 
 // Daemon struct carries the main contents in daemon
 type Daemon struct {
 	sync.RWMutex
 	opts       *Options
-	builders   map[string]builder.Builder
-	entities   map[string]string
+	registry   *builderRegistry
 	backend    *Backend
 	grpc       *GrpcServer
 	localStore *store.Store
 	key        *rsa.PrivateKey
+	gc         *gc.GarbageCollector
+	elector    *leader.Elector
+	limiter    *ratelimit.KeyedLimiter
+	// buildSockPath is the path of the restricted build socket, empty
+	// when the daemon listens on the main socket only
+	buildSockPath string
+	// queue bounds the concurrently running builds, excess builds
+	// wait in FIFO order
+	queue *buildQueue
+	// imageHolds tracks the images long-running operations are reading
+	imageHolds *imageHolds
 }
 
 // NewDaemon new a daemon instance
@@ -73,25 +186,101 @@ func NewDaemon(opts Options, store *store.Store) (*Daemon, error) {
 		return nil, err
 	}
 
+	maxBuilds := opts.MaxConcurrentBuilds
+	if maxBuilds <= 0 {
+		maxBuilds = defaultMaxBuilders
+	}
+
 	return &Daemon{
 		opts:       &opts,
-		builders:   make(map[string]builder.Builder),
-		entities:   make(map[string]string),
+		registry:   newBuilderRegistry(maxBuilds),
 		localStore: store,
 		key:        rsaKey,
+		queue:      newBuildQueue(maxBuilds),
+		imageHolds: newImageHolds(),
 	}, nil
 }
 
+// holds returns the image hold registry of the daemon, creating it on
+// the first use
+func (d *Daemon) holds() *imageHolds {
+	d.Lock()
+	defer d.Unlock()
+	if d.imageHolds == nil {
+		d.imageHolds = newImageHolds()
+	}
+	return d.imageHolds
+}
+
+// reg returns the builder registry of the daemon, creating it on the
+// first use
+func (d *Daemon) reg() *builderRegistry {
+	d.Lock()
+	defer d.Unlock()
+	if d.registry == nil {
+		d.registry = newBuilderRegistry(defaultMaxBuilders)
+	}
+	return d.registry
+}
+
+// buildSlots returns the build queue of the daemon, creating it on the
+// first use
+func (d *Daemon) buildSlots() *buildQueue {
+	d.Lock()
+	defer d.Unlock()
+	if d.queue == nil {
+		d.queue = newBuildQueue(defaultMaxBuilders)
+	}
+	return d.queue
+}
+
 // Run runs the daemon process
 func (d *Daemon) Run() (err error) {
+	// the per-phase timing breakdown shows in the debug logs where a
+	// slow startup spent its time
+	startupStart := time.Now()
+	phaseStart := startupStart
+	logPhase := func(name string) {
+		logrus.Debugf("Startup: %s took %v", name, time.Since(phaseStart))
+		phaseStart = time.Now()
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	gc := gc.NewGC()
 	gc.StartGC(ctx)
+	d.gc = gc
 
 	if rerr := d.registerSubReaper(gc); rerr != nil {
 		return rerr
 	}
+	logPhase("sub reaper registration")
+
+	if rerr := d.setupURLCache(gc); rerr != nil {
+		return rerr
+	}
+	logPhase("url cache setup")
+
+	if rerr := d.registerStaleBuilderReaper(gc); rerr != nil {
+		return rerr
+	}
+
+	d.sweepStaleSessionDirs()
+	logPhase("stale session sweeping")
+
+	if rerr := d.startCacheRegistry(ctx); rerr != nil {
+		return rerr
+	}
+	logPhase("cache registry startup")
+
+	if d.elector != nil && !d.elector.IsLeader() {
+		logrus.Infof("Leader lock %s is held by the active daemon, serving read-only requests in standby mode", d.elector.Path())
+		go func() {
+			if cErr := d.elector.Campaign(ctx); cErr != nil {
+				logrus.Infof("Campaign for the leadership finished: %v", cErr)
+			}
+		}()
+	}
 
 	logrus.Debugf("Daemon start with option %#v", d.opts)
 
@@ -103,8 +292,10 @@ func (d *Daemon) Run() (err error) {
 		return err
 	}
 	d.backend.Register(d.grpc.server)
+	logPhase("grpc server setup")
 	// after the daemon is done setting up we can notify systemd api
 	systemd.NotifySystemReady()
+	logrus.Debugf("Startup: daemon ready in %v", time.Since(startupStart))
 
 	errCh := make(chan error)
 	if err = d.grpc.Run(ctx, errCh, cancel); err != nil {
@@ -145,52 +336,102 @@ func (d *Daemon) NewBuilder(ctx context.Context, req *pb.BuildRequest) (b builde
 	if err != nil {
 		return nil, err
 	}
+	// checkpointDir keeps the per-stage build checkpoints which survive
+	// the builder working directories, so an interrupted build can be resumed
+	checkpointDir, err := securejoin.SecureJoin(d.opts.DataRoot, checkpointDirName)
+	if err != nil {
+		return nil, err
+	}
+	// buildCacheDir keeps the shared build cache state consulted by every build
+	buildCacheDir, err := securejoin.SecureJoin(d.opts.DataRoot, buildCacheDirName)
+	if err != nil {
+		return nil, err
+	}
+
+	// a remote context (git URL or tarball URL) is fetched into the build
+	// tmp dir first, it is cleaned up together with the other per-build data
+	switch {
+	case gitcontext.IsGitURL(req.GetContextDir()):
+		gitDir, gErr := securejoin.SecureJoin(buildDir, gitContextDirName)
+		if gErr != nil {
+			return nil, gErr
+		}
+		contextDir, fErr := gitcontext.Fetch(ctx, req.GetContextDir(), gitDir)
+		if fErr != nil {
+			return nil, errors.Wrapf(fErr, "fetch git context %q failed", req.GetContextDir())
+		}
+		req.ContextDir = contextDir
+		if err = readRemoteContextDockerfile(req, contextDir); err != nil {
+			return nil, err
+		}
+	case urlcontext.IsURLContext(req.GetContextDir()):
+		urlDir, uErr := securejoin.SecureJoin(buildDir, urlContextDirName)
+		if uErr != nil {
+			return nil, uErr
+		}
+		contextDir, tarCtx, fErr := urlcontext.Fetch(ctx, req.GetContextDir(), urlDir)
+		if fErr != nil {
+			return nil, errors.Wrapf(fErr, "fetch url context %q failed", req.GetContextDir())
+		}
+		req.ContextDir = contextDir
+		if err = readRemoteContextDockerfile(req, contextDir); err != nil {
+			return nil, err
+		}
+		// the builder materializes context files from the index on
+		// demand instead of the whole archive being unpacked here
+		ctx = context.WithValue(ctx, util.TarContextKey(util.TarContext), tarCtx)
+	}
 
 	// this key with BuildDir will be used by exporter to save blob temporary
 	// NOTE: keep it be updated before NewBuilder. ctx will be taken by Builder
 	ctx = context.WithValue(ctx, util.BuildDirKey(util.BuildDir), buildDir)
+	ctx = context.WithValue(ctx, util.CheckpointDirKey(util.CheckpointDir), checkpointDir)
+	ctx = context.WithValue(ctx, util.BuildCacheDirKey(util.BuildCacheDir), buildCacheDir)
 	b, err = builder.NewBuilder(ctx, d.localStore, req, d.opts.RuntimePath, buildDir, runDir, d.key)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to new builder")
 	}
 
-	d.Lock()
-	defer d.Unlock()
-	entityID := b.EntityID()
-	if buildID, exist := d.entities[entityID]; exist {
-		return nil, errors.Errorf("the dockerfile is already on building with static build mode by buildID: %s", buildID)
+	if err = d.reg().add(req.BuildID, b); err != nil {
+		return nil, err
 	}
-	d.entities[entityID] = req.BuildID
-	d.builders[req.BuildID] = b
 
 	return b, nil
 }
 
+// readRemoteContextDockerfile fills req.FileContent from the Dockerfile at
+// the root of a fetched remote context when the client sent no content
+func readRemoteContextDockerfile(req *pb.BuildRequest, contextDir string) error {
+	if req.GetFileContent() != "" {
+		return nil
+	}
+
+	dockerfile := filepath.Join(contextDir, "Dockerfile")
+	if err := util.CheckFileInfoAndSize(dockerfile, constant.MaxFileSize); err != nil {
+		return errors.Wrap(err, "check Dockerfile in remote context failed")
+	}
+	content, err := ioutil.ReadFile(dockerfile)
+	if err != nil {
+		return errors.Wrap(err, "read Dockerfile in remote context failed")
+	}
+	req.FileContent = string(content)
+
+	return nil
+}
+
 // Builder returns an Builder to caller. Caller should check the return value if it is nil
 func (d *Daemon) Builder(buildID string) (builder.Builder, error) {
-	d.RLock()
-	defer d.RUnlock()
-	if _, ok := d.builders[buildID]; !ok {
-		return nil, errors.Errorf("could not find builder with build job %s", buildID)
-	}
-	return d.builders[buildID], nil
+	return d.reg().get(buildID)
 }
 
 // deleteBuilder deletes builder from daemon
 func (d *Daemon) deleteBuilder(buildID string) {
-	d.Lock()
-	builder := d.builders[buildID]
-	delete(d.builders, buildID)
-	delete(d.entities, builder.EntityID())
-	d.Unlock()
+	d.reg().remove(buildID)
 }
 
 // deleteAllBuilders deletes all Builders stored in daemon
 func (d *Daemon) deleteAllBuilders() {
-	d.Lock()
-	d.builders = make(map[string]builder.Builder)
-	d.entities = make(map[string]string)
-	d.Unlock()
+	d.reg().removeAll()
 }
 
 // Cleanup cleans the resource
@@ -207,6 +448,102 @@ func (d *Daemon) Cleanup() error {
 	return err
 }
 
+// sweepStaleSessionDirs removes the session working directories left
+// behind by builds interrupted before their cleanup could run. It runs
+// before the GRPC server comes up, so no session can be active yet
+func (d *Daemon) sweepStaleSessionDirs() {
+	buildTmpDir, err := securejoin.SecureJoin(d.opts.DataRoot, constant.DataRootTmpDirPrefix)
+	if err != nil {
+		logrus.Warnf("Join data root tmp dir failed: %v", err)
+		return
+	}
+
+	sweep := func(root string, dirOnly bool) {
+		entries, rErr := ioutil.ReadDir(root)
+		if rErr != nil {
+			if !os.IsNotExist(rErr) {
+				logrus.Warnf("Read dir %q for session sweeping failed: %v", root, rErr)
+			}
+			return
+		}
+		for _, entry := range entries {
+			// the run root also holds the storage dir and stack dump
+			// files, only the per-session sub directories are swept
+			if dirOnly && (!entry.IsDir() || entry.Name() == "storage") {
+				continue
+			}
+			stale := filepath.Join(root, entry.Name())
+			if rmErr := util.ForceRemoveAll(stale); rmErr != nil {
+				logrus.Warnf("Removing stale session dir %q failed: %v", stale, rmErr)
+				continue
+			}
+			logrus.Infof("Removed stale session dir %q", stale)
+		}
+	}
+
+	sweep(buildTmpDir, false)
+	sweep(d.opts.RunRoot, true)
+}
+
+// setupURLCache initializes the url download cache shared across
+// builds and registers its periodic pruning to the GC
+func (d *Daemon) setupURLCache(g *gc.GarbageCollector) error {
+	cacheRoot, err := securejoin.SecureJoin(d.opts.DataRoot, urlCacheDirName)
+	if err != nil {
+		return err
+	}
+	cache, err := urlcache.Init(cacheRoot)
+	if err != nil {
+		return err
+	}
+
+	pruneURLCache := func(i interface{}) error {
+		cacheTmp := i.(*urlcache.Cache)
+		pruned, perr := cacheTmp.Prune(urlcache.DefaultUnusedThreshold)
+		if perr != nil {
+			logrus.Errorf("Prune url cache error: %v", perr)
+			return perr
+		}
+		if pruned != 0 {
+			logrus.Infof("Pruned %d unused url cache entries", pruned)
+		}
+		return nil
+	}
+
+	opt := &gc.RegisterOption{
+		Name:        "urlCachePruner",
+		Interval:    urlcache.DefaultPruneInterval,
+		RecycleData: cache,
+		RecycleFunc: pruneURLCache,
+	}
+
+	return g.RegisterGC(opt)
+}
+
+// startCacheRegistry brings up the embedded pull-through cache
+// registry when an address is configured for it
+func (d *Daemon) startCacheRegistry(ctx context.Context) error {
+	if d.opts.CacheRegistryAddr == "" {
+		return nil
+	}
+
+	cacheRoot, err := securejoin.SecureJoin(d.opts.DataRoot, cacheRegistryDirName)
+	if err != nil {
+		return err
+	}
+	registry, err := cacheregistry.New(d.opts.CacheRegistryAddr, cacheRoot, image.GetSystemContext())
+	if err != nil {
+		return err
+	}
+	go func() {
+		if sErr := registry.Serve(ctx); sErr != nil {
+			logrus.Errorf("Cache registry stopped with: %v", sErr)
+		}
+	}()
+
+	return nil
+}
+
 func (d *Daemon) registerSubReaper(g *gc.GarbageCollector) error {
 	if err := unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, uintptr(1), 0, 0, 0); err != nil { //nolint, gomod
 		return errors.Errorf("set subreaper failed: %v", err)
@@ -216,11 +553,8 @@ func (d *Daemon) registerSubReaper(g *gc.GarbageCollector) error {
 		var err error
 
 		daemonTmp := i.(*Daemon)
-		daemonTmp.Lock()
-		defer daemonTmp.Unlock()
-
 		// if any of image build process is running, skip reap
-		if len(daemonTmp.builders) != 0 {
+		if daemonTmp.reg().count() != 0 {
 			return nil
 		}
 		if err = reaper.Reap(); err != nil {
@@ -0,0 +1,53 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: This file tests daemon setting functions
+
+package daemon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	constant "isula.org/isula-build"
+)
+
+func TestSweepStaleSessionDirs(t *testing.T) {
+	d := prepare(t)
+	defer tmpClean(d)
+
+	daemon := d.Daemon
+	buildTmpDir := filepath.Join(daemon.opts.DataRoot, constant.DataRootTmpDirPrefix)
+	staleBuildDir := filepath.Join(buildTmpDir, "12345678")
+	staleRunDir := filepath.Join(daemon.opts.RunRoot, "12345678")
+	storageDir := filepath.Join(daemon.opts.RunRoot, "storage")
+	stackFile := filepath.Join(daemon.opts.RunRoot, "stack-dump")
+	for _, dir := range []string{staleBuildDir, staleRunDir, storageDir} {
+		assert.NilError(t, os.MkdirAll(dir, constant.DefaultRootDirMode))
+	}
+	assert.NilError(t, ioutil.WriteFile(stackFile, []byte("stack"), constant.DefaultRootFileMode))
+
+	daemon.sweepStaleSessionDirs()
+
+	for _, removed := range []string{staleBuildDir, staleRunDir} {
+		_, err := os.Stat(removed)
+		assert.Assert(t, os.IsNotExist(err))
+	}
+	// the storage dir and the stack dump files are not session data
+	for _, kept := range []string{storageDir, stackFile} {
+		_, err := os.Stat(kept)
+		assert.NilError(t, err)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is "diff" command for backend
+
+package daemon
+
+import (
+	"context"
+
+	"github.com/containers/storage/pkg/archive"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/image"
+)
+
+// Diff computes the file changes between two local images with the
+// storage layer diff, no image is mounted for it. With only one image
+// given its top layer is compared against its own base layer.
+func (b *Backend) Diff(ctx context.Context, req *pb.DiffRequest) (*pb.DiffResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"ImageA": req.GetImageA(),
+		"ImageB": req.GetImageB(),
+	}).Info("DiffRequest received")
+
+	s := b.daemon.localStore
+	_, imgA, err := image.FindImage(s, req.GetImageA())
+	if err != nil {
+		return &pb.DiffResponse{}, errors.Wrapf(err, "find local image %q failed", req.GetImageA())
+	}
+
+	from := imgA.TopLayer
+	to := imgA.TopLayer
+	if req.GetImageB() != "" {
+		_, imgB, fErr := image.FindImage(s, req.GetImageB())
+		if fErr != nil {
+			return &pb.DiffResponse{}, errors.Wrapf(fErr, "find local image %q failed", req.GetImageB())
+		}
+		to = imgB.TopLayer
+	} else {
+		// without a second image the image is compared against its
+		// own base, the changes are those of the top layer
+		layer, lErr := s.Layer(imgA.TopLayer)
+		if lErr != nil {
+			return &pb.DiffResponse{}, errors.Wrapf(lErr, "read top layer of %q failed", req.GetImageA())
+		}
+		from = layer.Parent
+	}
+	if from == to {
+		return &pb.DiffResponse{}, nil
+	}
+
+	changes, err := s.Changes(from, to)
+	if err != nil {
+		return &pb.DiffResponse{}, errors.Wrap(err, "compute layer changes failed")
+	}
+
+	resp := &pb.DiffResponse{}
+	for _, change := range changes {
+		switch change.Kind {
+		case archive.ChangeAdd:
+			resp.Added = append(resp.Added, change.Path)
+		case archive.ChangeModify:
+			resp.Changed = append(resp.Changed, change.Path)
+		case archive.ChangeDelete:
+			resp.Deleted = append(resp.Deleted, change.Path)
+		}
+	}
+
+	return resp, nil
+}
@@ -18,16 +18,20 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/containerd/containerd/sys"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 
 	constant "isula.org/isula-build"
+	"isula.org/isula-build/pkg/ratelimit"
 	"isula.org/isula-build/util"
 )
 
@@ -36,21 +40,57 @@ type GrpcServer struct {
 	server   *grpc.Server
 	listener net.Listener
 	path     string
+	// buildListener is the optional restricted build socket, nil when
+	// the daemon listens on the main socket only
+	buildListener net.Listener
+	buildPath     string
 }
 
-// NewGrpcServer creates a new GRPC socket with default GRPC socket address
+// NewGrpcServer creates a new GRPC socket with the configured socket address
 func (d *Daemon) NewGrpcServer() error {
-	socket, path, err := newSocket(d.opts.Group)
+	socket, path, err := newSocket(d.opts.Addr, d.opts.SockMode, d.opts.Group)
 	if err != nil {
 		return errors.Errorf("create new GRPC socket failed: %v", err)
 	}
 
-	server := grpc.NewServer()
+	if d.opts.RateLimit > 0 {
+		burst := d.opts.RateBurst
+		if burst <= 0 {
+			burst = d.opts.RateLimit
+		}
+		d.limiter = ratelimit.NewKeyedLimiter(d.opts.RateLimit, burst)
+	}
+
+	serverOpts := []grpc.ServerOption{
+		grpc.Creds(peerCred{}),
+		grpc.ChainUnaryInterceptor(d.rateUnaryInterceptor, d.authUnaryInterceptor, d.leaderUnaryInterceptor),
+		grpc.ChainStreamInterceptor(d.rateStreamInterceptor, d.authStreamInterceptor, d.leaderStreamInterceptor),
+	}
+	if d.opts.MaxRecvMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.MaxRecvMsgSize(d.opts.MaxRecvMsgSize))
+	}
+	keepaliveOpts, err := keepaliveServerOptions(d.opts.KeepaliveInterval, d.opts.KeepaliveTimeout)
+	if err != nil {
+		return err
+	}
+	serverOpts = append(serverOpts, keepaliveOpts...)
+	server := grpc.NewServer(serverOpts...)
 	d.grpc = &GrpcServer{
 		listener: socket,
 		path:     path,
 		server:   server,
 	}
+
+	if d.opts.BuildAddr != "" {
+		buildSocket, buildPath, bErr := newSocket(d.opts.BuildAddr, d.opts.SockMode, d.opts.Group)
+		if bErr != nil {
+			return errors.Errorf("create new GRPC build socket failed: %v", bErr)
+		}
+		d.grpc.buildListener = buildSocket
+		d.grpc.buildPath = buildPath
+		d.buildSockPath = buildPath
+	}
+
 	return nil
 }
 
@@ -83,6 +123,12 @@ func (g *GrpcServer) Run(ctx context.Context, ch chan error, cancelFunc context.
 		logrus.Infof("isula-builder is listening on %s", g.path)
 		return g.server.Serve(g.listener)
 	})
+	if g.buildListener != nil {
+		eg.Go(func() error {
+			logrus.Infof("isula-builder is listening on the build socket %s", g.buildPath)
+			return g.server.Serve(g.buildListener)
+		})
+	}
 
 	go func() {
 		ch <- eg.Wait()
@@ -91,13 +137,67 @@ func (g *GrpcServer) Run(ctx context.Context, ch chan error, cancelFunc context.
 	return nil
 }
 
-func newSocket(group string) (net.Listener, string, error) {
-	if !strings.HasPrefix(constant.DefaultGRPCAddress, constant.UnixPrefix) {
-		return nil, "", errors.Errorf("listen address %s not supported", constant.DefaultGRPCAddress)
+// minClientPingInterval is the shortest client keepalive ping interval
+// the server tolerates before closing the connection
+const minClientPingInterval = 10 * time.Second
+
+// keepaliveServerOptions builds the keepalive server options from the
+// configured interval and timeout, nil when keepalive is disabled
+func keepaliveServerOptions(interval, timeout string) ([]grpc.ServerOption, error) {
+	if interval == "" {
+		return nil, nil
+	}
+	pingInterval, err := time.ParseDuration(interval)
+	if err != nil || pingInterval <= 0 {
+		return nil, errors.Errorf("keepalive interval %q must be a positive duration like 30s", interval)
 	}
+	params := keepalive.ServerParameters{Time: pingInterval}
+	if timeout != "" {
+		ackTimeout, tErr := time.ParseDuration(timeout)
+		if tErr != nil || ackTimeout <= 0 {
+			return nil, errors.Errorf("keepalive timeout %q must be a positive duration like 20s", timeout)
+		}
+		params.Timeout = ackTimeout
+	}
+
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(params),
+		// clients behind aggressive NAT gateways ping on their own, let
+		// them do so even between requests instead of disconnecting them
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             minClientPingInterval,
+			PermitWithoutStream: true,
+		}),
+	}, nil
+}
 
-	path := strings.TrimPrefix(constant.DefaultGRPCAddress, constant.UnixPrefix)
-	err := os.Remove(path)
+// keepaliveInterval returns the parsed keepalive interval, zero when
+// keepalive is disabled, the value is validated at daemon startup
+func (d *Daemon) keepaliveInterval() time.Duration {
+	if d.opts.KeepaliveInterval == "" {
+		return 0
+	}
+	interval, err := time.ParseDuration(d.opts.KeepaliveInterval)
+	if err != nil {
+		return 0
+	}
+	return interval
+}
+
+func newSocket(addr, mode, group string) (net.Listener, string, error) {
+	if addr == "" {
+		addr = constant.DefaultGRPCAddress
+	}
+	if !strings.HasPrefix(addr, constant.UnixPrefix) {
+		return nil, "", errors.Errorf("listen address %s not supported", addr)
+	}
+	sockMode, err := parseSockMode(mode)
+	if err != nil {
+		return nil, "", err
+	}
+
+	path := strings.TrimPrefix(addr, constant.UnixPrefix)
+	err = os.Remove(path)
 	if err != nil && !os.IsNotExist(err) {
 		return nil, "", err
 	}
@@ -108,15 +208,34 @@ func newSocket(group string) (net.Listener, string, error) {
 		return nil, "", err
 	}
 
-	if err = os.Chmod(path, constant.DefaultGroupFileMode); err != nil {
+	if err = os.Chmod(path, sockMode); err != nil {
 		logrus.Errorf("Chmod for %s failed: %v", path, err)
 		return nil, "", err
 	}
 
-	if err = util.ChangeGroup(path, group); err != nil {
+	// a rootless daemon cannot chown to the service group, the socket
+	// stays private to the user who started it
+	if util.IsRootless() {
+		logrus.Debugf("Rootless mode, socket %s stays owned by the current user", path)
+	} else if err = util.ChangeGroup(path, group); err != nil {
 		logrus.Errorf("Chown for %s failed: %v", path, err)
 		return nil, "", err
 	}
 
 	return l, path, nil
 }
+
+// parseSockMode parses the octal socket mode of the --sock-mode flag,
+// empty keeps the default group read-write mode
+func parseSockMode(mode string) (os.FileMode, error) {
+	if mode == "" {
+		return constant.DefaultGroupFileMode, nil
+	}
+
+	parsed, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || parsed > 0777 {
+		return 0, errors.Errorf("invalid socket mode %q, expected an octal mode like 0660", mode)
+	}
+
+	return os.FileMode(parsed), nil
+}
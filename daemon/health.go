@@ -15,6 +15,7 @@ package daemon
 
 import (
 	"context"
+	"fmt"
 
 	gogotypes "github.com/gogo/protobuf/types"
 	"github.com/sirupsen/logrus"
@@ -22,9 +23,71 @@ import (
 	pb "isula.org/isula-build/api/services"
 )
 
-// HealthCheck returns daemon healthy condition
+// HealthCheck returns daemon healthy condition with the readiness of
+// its components
 func (b *Backend) HealthCheck(ctx context.Context, req *gogotypes.Empty) (*pb.HealthCheckResponse, error) {
 	logrus.Info("HealthCheckRequest received")
 
-	return &pb.HealthCheckResponse{Status: pb.HealthCheckResponse_SERVING}, nil
+	components := []*pb.HealthCheckResponse_ComponentStatus{
+		b.storageStatus(),
+		b.gcStatus(),
+		b.buildsStatus(),
+		b.roleStatus(),
+	}
+
+	status := pb.HealthCheckResponse_SERVING
+	for _, component := range components {
+		if !component.Ready {
+			status = pb.HealthCheckResponse_NOT_SERVING
+			break
+		}
+	}
+
+	return &pb.HealthCheckResponse{Status: status, Components: components}, nil
+}
+
+// storageStatus reports whether the local store is mounted and usable
+func (b *Backend) storageStatus() *pb.HealthCheckResponse_ComponentStatus {
+	component := &pb.HealthCheckResponse_ComponentStatus{Name: "storage"}
+	if _, err := b.daemon.localStore.Status(); err != nil {
+		component.Detail = err.Error()
+		return component
+	}
+	component.Ready = true
+	component.Detail = fmt.Sprintf("driver %s", b.daemon.localStore.GraphDriverName())
+	return component
+}
+
+// gcStatus reports whether the garbage collector is idle or busy, a
+// busy GC is still healthy and does not block serving
+func (b *Backend) gcStatus() *pb.HealthCheckResponse_ComponentStatus {
+	component := &pb.HealthCheckResponse_ComponentStatus{Name: "gc", Ready: true, Detail: "idle"}
+	if b.daemon.gc == nil {
+		component.Detail = "not started"
+		return component
+	}
+	if b.daemon.gc.Busy() {
+		component.Detail = "busy"
+	}
+	return component
+}
+
+// roleStatus reports whether the daemon is the active one of an
+// active/standby pair, a standby daemon is healthy but read-only
+func (b *Backend) roleStatus() *pb.HealthCheckResponse_ComponentStatus {
+	component := &pb.HealthCheckResponse_ComponentStatus{Name: "role", Ready: true, Detail: "active"}
+	if !b.daemon.isLeader() {
+		component.Detail = "standby"
+	}
+	return component
+}
+
+// buildsStatus reports the number of currently active builds
+func (b *Backend) buildsStatus() *pb.HealthCheckResponse_ComponentStatus {
+	active := b.daemon.reg().count()
+	return &pb.HealthCheckResponse_ComponentStatus{
+		Name:   "builds",
+		Ready:  true,
+		Detail: fmt.Sprintf("%d active", active),
+	}
 }
@@ -0,0 +1,44 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: This file tests HealthCheck interface
+
+package daemon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+	"gotest.tools/v3/assert"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+func TestHealthCheck(t *testing.T) {
+	d := prepare(t)
+	defer tmpClean(d)
+
+	resp, err := d.Daemon.backend.HealthCheck(context.Background(), &types.Empty{})
+	assert.NilError(t, err)
+	assert.Equal(t, resp.Status, pb.HealthCheckResponse_SERVING)
+
+	components := make(map[string]*pb.HealthCheckResponse_ComponentStatus, len(resp.Components))
+	for _, component := range resp.Components {
+		components[component.Name] = component
+	}
+	assert.Assert(t, components["storage"].Ready)
+	assert.Assert(t, components["builds"].Ready)
+	assert.Equal(t, components["builds"].Detail, "0 active")
+	// the GC is not started by prepare, still not blocking serving
+	assert.Assert(t, components["gc"].Ready)
+	assert.Equal(t, components["gc"].Detail, "not started")
+}
@@ -0,0 +1,226 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is image "prune" command for backend
+
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/store"
+)
+
+// ImagePrune removes the dangling images, the layers referenced by no
+// image or container and the build containers left behind by interrupted
+// sessions. With all set every unused image is removed, with a cutoff
+// only the entries older than it, and a dry run only reports the result
+func (b *Backend) ImagePrune(ctx context.Context, req *pb.ImagePruneRequest) (*pb.ImagePruneResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"All":          req.GetAll(),
+		"UntilSeconds": req.GetUntilSeconds(),
+		"DryRun":       req.GetDryRun(),
+	}).Info("ImagePruneRequest received")
+
+	var cutoff time.Time
+	if req.GetUntilSeconds() > 0 {
+		cutoff = time.Now().Add(-time.Duration(req.GetUntilSeconds()) * time.Second)
+	}
+
+	s := b.daemon.localStore
+	resp := &pb.ImagePruneResponse{}
+	layerSizes, err := getLayerSizes(s)
+	if err != nil {
+		return &pb.ImagePruneResponse{}, err
+	}
+
+	if err := pruneContainers(s, cutoff, req.GetDryRun(), layerSizes, resp); err != nil {
+		return &pb.ImagePruneResponse{}, err
+	}
+	if err := pruneImages(s, b.daemon.holds().heldIDs(), cutoff, req.GetAll(), req.GetDryRun(), layerSizes, resp); err != nil {
+		return &pb.ImagePruneResponse{}, err
+	}
+	if err := pruneLayers(s, req.GetDryRun(), layerSizes, resp); err != nil {
+		return &pb.ImagePruneResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// getLayerSizes indexes the uncompressed size of every layer in the store
+func getLayerSizes(s *store.Store) (map[string]int64, error) {
+	layers, err := s.Layers()
+	if err != nil {
+		return nil, err
+	}
+	sizes := make(map[string]int64, len(layers))
+	for _, layer := range layers {
+		if layer.UncompressedSize > 0 {
+			sizes[layer.ID] = layer.UncompressedSize
+		}
+	}
+
+	return sizes, nil
+}
+
+// pruneContainers removes the build containers left behind by finished
+// or interrupted sessions, the mounted ones belong to running builds
+// and are left alone
+func pruneContainers(s *store.Store, cutoff time.Time, dryRun bool, layerSizes map[string]int64, resp *pb.ImagePruneResponse) error {
+	containers, err := s.Containers()
+	if err != nil {
+		return err
+	}
+
+	for _, container := range containers {
+		if !cutoff.IsZero() && container.Created.After(cutoff) {
+			continue
+		}
+		if cnt, mErr := s.Mounted(container.ID); mErr == nil && cnt > 0 {
+			continue
+		}
+		size := layerSizes[container.LayerID]
+		if !dryRun {
+			if cErr := s.CleanContainer(container.ID); cErr != nil {
+				logrus.Warnf("Pruning container %q failed: %v", container.ID, cErr)
+				continue
+			}
+			logrus.Infof("Pruned container %q", container.ID)
+		}
+		resp.Removed = append(resp.Removed, "container "+container.ID)
+		resp.ReclaimedBytes += size
+	}
+
+	return nil
+}
+
+// pruneImages removes the dangling images, or with all set every image
+// not used by a remaining container, held images are being read by a
+// long-running operation and stay untouched
+func pruneImages(s *store.Store, held map[string]bool, cutoff time.Time, all, dryRun bool, layerSizes map[string]int64, resp *pb.ImagePruneResponse) error {
+	containers, err := s.Containers()
+	if err != nil {
+		return err
+	}
+	inUse := make(map[string]bool, len(containers))
+	for _, container := range containers {
+		inUse[container.ImageID] = true
+	}
+
+	images, err := s.Images()
+	if err != nil {
+		return err
+	}
+	for _, img := range images {
+		if inUse[img.ID] || held[img.ID] {
+			continue
+		}
+		if !all && len(img.Names) != 0 {
+			continue
+		}
+		if !cutoff.IsZero() && img.Created.After(cutoff) {
+			continue
+		}
+		// with commit unset the store only reports the layers to delete
+		layers, dErr := s.DeleteImage(img.ID, !dryRun)
+		if dErr != nil {
+			logrus.Warnf("Pruning image %q failed: %v", img.ID, dErr)
+			continue
+		}
+		if !dryRun {
+			logrus.Infof("Pruned image %q", img.ID)
+		}
+		resp.Removed = append(resp.Removed, "image "+img.ID)
+		for _, layerID := range layers {
+			resp.ReclaimedBytes += layerSizes[layerID]
+		}
+	}
+
+	return nil
+}
+
+// pruneLayers removes the layers referenced by no image and no container,
+// parents of kept layers are kept as well
+func pruneLayers(s *store.Store, dryRun bool, layerSizes map[string]int64, resp *pb.ImagePruneResponse) error {
+	layers, err := s.Layers()
+	if err != nil {
+		return err
+	}
+	parents := make(map[string]string, len(layers))
+	for _, layer := range layers {
+		parents[layer.ID] = layer.Parent
+	}
+
+	used := make(map[string]bool, len(layers))
+	keepChain := func(layerID string) {
+		for layerID != "" && !used[layerID] {
+			used[layerID] = true
+			layerID = parents[layerID]
+		}
+	}
+	images, err := s.Images()
+	if err != nil {
+		return err
+	}
+	for _, img := range images {
+		keepChain(img.TopLayer)
+		for _, mapped := range img.MappedTopLayers {
+			keepChain(mapped)
+		}
+	}
+	containers, err := s.Containers()
+	if err != nil {
+		return err
+	}
+	for _, container := range containers {
+		keepChain(container.LayerID)
+	}
+
+	candidates := make(map[string]bool, len(layers))
+	for _, layer := range layers {
+		if !used[layer.ID] {
+			candidates[layer.ID] = true
+		}
+	}
+
+	if dryRun {
+		for layerID := range candidates {
+			resp.Removed = append(resp.Removed, "layer "+layerID)
+			resp.ReclaimedBytes += layerSizes[layerID]
+		}
+		return nil
+	}
+
+	// children go before their parents, so retry until no layer can be
+	// deleted anymore instead of ordering the candidates up front
+	for progress := true; progress && len(candidates) != 0; {
+		progress = false
+		for layerID := range candidates {
+			if dErr := s.DeleteLayer(layerID); dErr != nil {
+				continue
+			}
+			logrus.Infof("Pruned layer %q", layerID)
+			delete(candidates, layerID)
+			resp.Removed = append(resp.Removed, "layer "+layerID)
+			resp.ReclaimedBytes += layerSizes[layerID]
+			progress = true
+		}
+	}
+	for layerID := range candidates {
+		logrus.Warnf("Pruning layer %q failed, it is left in the store", layerID)
+	}
+
+	return nil
+}
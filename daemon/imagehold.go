@@ -0,0 +1,71 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: in-use tracking of images read by long-running operations
+
+package daemon
+
+import (
+	"sync"
+)
+
+// imageHolds counts the images long-running operations like Save are
+// currently reading, Remove and ImagePrune refuse to delete a held
+// image so an archive being written cannot be corrupted
+type imageHolds struct {
+	mu   sync.Mutex
+	refs map[string]int
+}
+
+func newImageHolds() *imageHolds {
+	return &imageHolds{refs: make(map[string]int)}
+}
+
+// hold marks the given image IDs as in use and returns the matching
+// release, releasing more than once is harmless
+func (h *imageHolds) hold(ids []string) func() {
+	h.mu.Lock()
+	for _, id := range ids {
+		h.refs[id]++
+	}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.mu.Lock()
+			for _, id := range ids {
+				if h.refs[id]--; h.refs[id] <= 0 {
+					delete(h.refs, id)
+				}
+			}
+			h.mu.Unlock()
+		})
+	}
+}
+
+// inUse reports whether the image is held by a running operation
+func (h *imageHolds) inUse(id string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.refs[id] > 0
+}
+
+// heldIDs returns the IDs of every held image
+func (h *imageHolds) heldIDs() map[string]bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	held := make(map[string]bool, len(h.refs))
+	for id := range h.refs {
+		held[id] = true
+	}
+	return held
+}
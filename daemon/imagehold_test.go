@@ -0,0 +1,45 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: image hold tracking test
+
+package daemon
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestImageHolds(t *testing.T) {
+	holds := newImageHolds()
+	assert.Equal(t, holds.inUse("image1"), false)
+
+	releaseFirst := holds.hold([]string{"image1", "image2"})
+	releaseSecond := holds.hold([]string{"image1"})
+	assert.Equal(t, holds.inUse("image1"), true)
+	assert.Equal(t, holds.inUse("image2"), true)
+	assert.Equal(t, holds.inUse("image3"), false)
+	assert.Equal(t, len(holds.heldIDs()), 2)
+
+	// the image stays held until the last holder releases it
+	releaseFirst()
+	assert.Equal(t, holds.inUse("image1"), true)
+	assert.Equal(t, holds.inUse("image2"), false)
+
+	// releasing twice must not underflow the count of other holders
+	releaseFirst()
+	assert.Equal(t, holds.inUse("image1"), true)
+
+	releaseSecond()
+	assert.Equal(t, holds.inUse("image1"), false)
+	assert.Equal(t, len(holds.heldIDs()), 0)
+}
@@ -18,12 +18,13 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/containers/storage"
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
-	constant "isula.org/isula-build"
 	pb "isula.org/isula-build/api/services"
 	"isula.org/isula-build/image"
 	"isula.org/isula-build/store"
@@ -36,10 +37,24 @@ const (
 	minImageFieldLenWithTag = 2
 )
 
+// the sort orders a listing can be requested in
+const (
+	sortByCreated = "created"
+	sortByName    = "name"
+	sortBySize    = "size"
+)
+
 type listOptions struct {
 	localStore *store.Store
 	logEntry   *logrus.Entry
 	imageName  string
+	pageSize   int32
+	pageToken  int32
+	sortBy     string
+	exactTime  bool
+	// metaFilters holds the user metadata key/values a listed image
+	// must carry, empty lists every image
+	metaFilters map[string]string
 }
 
 func (b *Backend) getListOptions(req *pb.ListRequest) listOptions {
@@ -47,6 +62,10 @@ func (b *Backend) getListOptions(req *pb.ListRequest) listOptions {
 		localStore: b.daemon.localStore,
 		logEntry:   logrus.WithFields(logrus.Fields{"ImageName": req.GetImageName()}),
 		imageName:  req.GetImageName(),
+		pageSize:   req.GetPageSize(),
+		pageToken:  req.GetPageToken(),
+		sortBy:     req.GetSortBy(),
+		exactTime:  req.GetExactTime(),
 	}
 }
 
@@ -57,6 +76,19 @@ func (b *Backend) List(ctx context.Context, req *pb.ListRequest) (*pb.ListRespon
 	}).Info("ListRequest received")
 
 	opts := b.getListOptions(req)
+	switch opts.sortBy {
+	case "", sortByCreated, sortByName, sortBySize:
+	default:
+		return nil, errors.Errorf("invalid sort key %q, expected %s, %s or %s", opts.sortBy, sortByCreated, sortByName, sortBySize)
+	}
+	if opts.pageSize < 0 || opts.pageToken < 0 {
+		return nil, errors.New("page size and page token must not be negative")
+	}
+	metaFilters, err := parseMetaFilters(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	opts.metaFilters = metaFilters
 
 	slashLastIndex := strings.LastIndex(opts.imageName, "/")
 	colonLastIndex := strings.LastIndex(opts.imageName, ":")
@@ -72,9 +104,12 @@ func listOneImage(opts listOptions) (*pb.ListResponse, error) {
 		opts.logEntry.Error(err)
 		return nil, errors.Wrapf(err, "find local image %q failed", opts.imageName)
 	}
+	if !matchMetaFilters(opts.localStore, image.ID, opts.metaFilters) {
+		return &pb.ListResponse{}, nil
+	}
 
 	result := make([]*pb.ListResponse_ImageInfo, 0, len(image.Names))
-	appendImageToResult(&result, image, opts.localStore)
+	appendImageToResult(&result, image, newImageSizes(opts.localStore), opts)
 
 	for _, info := range result {
 		if opts.imageName == fmt.Sprintf("%s:%s", info.Repository, info.Tag) {
@@ -92,16 +127,26 @@ func listImages(opts listOptions) (*pb.ListResponse, error) {
 		return &pb.ListResponse{}, errors.Wrap(err, "failed list images from local storage")
 	}
 
-	sort.Slice(images, func(i, j int) bool {
-		return images[i].Created.After(images[j].Created)
-	})
+	if len(opts.metaFilters) != 0 {
+		matching := make([]storage.Image, 0, len(images))
+		for i := range images {
+			if matchMetaFilters(opts.localStore, images[i].ID, opts.metaFilters) {
+				matching = append(matching, images[i])
+			}
+		}
+		images = matching
+	}
+
+	sizes := newImageSizes(opts.localStore)
+	sortStorageImages(images, opts, sizes)
 	result := make([]*pb.ListResponse_ImageInfo, 0, len(images))
 	for i := range images {
-		appendImageToResult(&result, &images[i], opts.localStore)
+		appendImageToResult(&result, &images[i], sizes, opts)
 	}
 
 	if opts.imageName == "" {
-		return &pb.ListResponse{Images: result}, nil
+		page, next := paginate(result, opts)
+		return &pb.ListResponse{Images: page, NextPageToken: next}, nil
 	}
 
 	sameRepositoryResult := make([]*pb.ListResponse_ImageInfo, 0, len(images))
@@ -112,17 +157,80 @@ func listImages(opts listOptions) (*pb.ListResponse, error) {
 	}
 
 	if len(sameRepositoryResult) == 0 {
+		// an active filter excluding every image is an empty listing,
+		// not a lookup failure
+		if len(opts.metaFilters) != 0 {
+			return &pb.ListResponse{}, nil
+		}
 		return &pb.ListResponse{}, errors.Errorf("failed to list images with repository %q in local storage", opts.imageName)
 	}
-	return &pb.ListResponse{Images: sameRepositoryResult}, nil
+	page, next := paginate(sameRepositoryResult, opts)
+	return &pb.ListResponse{Images: page, NextPageToken: next}, nil
+}
+
+// sortStorageImages orders the images by the requested sort key, the
+// default order lists the newest image first
+func sortStorageImages(images []storage.Image, opts listOptions, sizes *imageSizes) {
+	switch opts.sortBy {
+	case sortByName:
+		sort.Slice(images, func(i, j int) bool {
+			return firstImageName(&images[i]) < firstImageName(&images[j])
+		})
+	case sortBySize:
+		sort.Slice(images, func(i, j int) bool {
+			return sizes.get(images[i].ID).virtual > sizes.get(images[j].ID).virtual
+		})
+	default:
+		sort.Slice(images, func(i, j int) bool {
+			return images[i].Created.After(images[j].Created)
+		})
+	}
+}
+
+// firstImageName sorts an untagged image to the end of a listing by name
+func firstImageName(image *storage.Image) string {
+	if len(image.Names) == 0 {
+		return "\xff"
+	}
+
+	return image.Names[0]
+}
+
+// paginate cuts the page the request asks for out of the full listing
+// and returns the token of the next page, zero when this page is the
+// last, a zero page size returns everything at once
+func paginate(result []*pb.ListResponse_ImageInfo, opts listOptions) ([]*pb.ListResponse_ImageInfo, int32) {
+	if opts.pageSize == 0 && opts.pageToken == 0 {
+		return result, 0
+	}
+	start := int(opts.pageToken)
+	if start >= len(result) {
+		return nil, 0
+	}
+	if opts.pageSize == 0 {
+		return result[start:], 0
+	}
+	end := start + int(opts.pageSize)
+	if end >= len(result) {
+		return result[start:], 0
+	}
+
+	return result[start:end], int32(end)
 }
 
-func appendImageToResult(result *[]*pb.ListResponse_ImageInfo, image *storage.Image, store *store.Store) {
+func appendImageToResult(result *[]*pb.ListResponse_ImageInfo, image *storage.Image, sizes *imageSizes, opts listOptions) {
 	names := image.Names
 	if len(names) == 0 {
 		names = []string{none}
 	}
 
+	info := sizes.get(image.ID)
+	// the storage persists the manifest digest when an image is
+	// committed or pulled, an image missing it shows <none>
+	imageDigest := none
+	if image.Digest != "" {
+		imageDigest = image.Digest.String()
+	}
 	for _, name := range names {
 		repository, tag := name, none
 		parts := strings.Split(name, ":")
@@ -134,17 +242,133 @@ func appendImageToResult(result *[]*pb.ListResponse_ImageInfo, image *storage.Im
 			Repository: repository,
 			Tag:        tag,
 			Id:         image.ID,
-			Created:    image.Created.Format(constant.LayoutTime),
-			Size_:      getImageSize(store, image.ID),
+			Created:    formatCreatedTime(image.Created, opts.exactTime),
+			Size_:      formatImageSize(info.virtual),
+			UniqueSize: formatImageSize(info.unique),
+			SharedSize: formatImageSize(info.shared),
+			Digest:     imageDigest,
 		}
 		*result = append(*result, imageInfo)
 	}
 }
 
-func getImageSize(store *store.Store, id string) string {
-	imgSize, err := store.ImageSize(id)
+// imageSizes computes the size figures of one listing in a single pass
+// over the store and caches them, the layer reference counts that split
+// an image into its unique and shared parts are only meaningful against
+// every other image in the store
+type imageSizes struct {
+	store *store.Store
+	// refs counts the images using each layer
+	refs map[string]int
+	// layers holds the known layers by ID for the chain walks
+	layers map[string]storage.Layer
+	// topLayers remembers the top layer of each image
+	topLayers map[string]string
+	cache     map[string]sizeInfo
+}
+
+// sizeInfo carries the size figures of one image, -1 stands for unknown
+type sizeInfo struct {
+	virtual int64
+	unique  int64
+	shared  int64
+}
+
+func newImageSizes(localStore *store.Store) *imageSizes {
+	s := &imageSizes{
+		store:     localStore,
+		refs:      make(map[string]int),
+		layers:    make(map[string]storage.Layer),
+		topLayers: make(map[string]string),
+		cache:     make(map[string]sizeInfo),
+	}
+
+	layers, err := localStore.Layers()
+	if err != nil {
+		return s
+	}
+	for _, layer := range layers {
+		s.layers[layer.ID] = layer
+	}
+	images, err := localStore.Images()
 	if err != nil {
-		imgSize = -1
+		return s
+	}
+	for i := range images {
+		s.topLayers[images[i].ID] = images[i].TopLayer
+		for _, id := range s.chain(images[i].TopLayer) {
+			s.refs[id]++
+		}
+	}
+
+	return s
+}
+
+// chain returns the layer IDs of an image from the top layer down to
+// the base layer
+func (s *imageSizes) chain(top string) []string {
+	ids := make([]string, 0, len(s.layers))
+	for id := top; id != ""; {
+		layer, ok := s.layers[id]
+		if !ok {
+			break
+		}
+		ids = append(ids, id)
+		id = layer.Parent
+	}
+
+	return ids
+}
+
+// get returns the cached sizes of an image and computes them on the
+// first request
+func (s *imageSizes) get(id string) sizeInfo {
+	if info, ok := s.cache[id]; ok {
+		return info
 	}
-	return util.FormatSize(float64(imgSize), decimalPrefixBase)
+
+	info := sizeInfo{virtual: -1, unique: -1, shared: -1}
+	if virtual, err := s.store.ImageSize(id); err == nil {
+		info.virtual = virtual
+	}
+	if top, ok := s.topLayers[id]; ok {
+		info.unique, info.shared = s.splitSizes(top)
+	}
+	s.cache[id] = info
+
+	return info
+}
+
+// splitSizes sums the layer chain into the part only this image uses
+// and the part other images reference as well, a layer with an unknown
+// diff size makes both figures unknown
+func (s *imageSizes) splitSizes(top string) (int64, int64) {
+	var unique, shared int64
+	for _, id := range s.chain(top) {
+		size := s.layers[id].UncompressedSize
+		if size < 0 {
+			return -1, -1
+		}
+		if s.refs[id] > 1 {
+			shared += size
+			continue
+		}
+		unique += size
+	}
+
+	return unique, shared
+}
+
+func formatImageSize(size int64) string {
+	return util.FormatSize(float64(size), decimalPrefixBase)
+}
+
+// formatCreatedTime renders the creation time a listing shows, by
+// default a relative time consistent with other container tools
+func formatCreatedTime(created time.Time, exactTime bool) string {
+	if exactTime {
+		return created.Format(time.RFC3339)
+	}
+
+	return fmt.Sprintf("%s ago", units.HumanDuration(time.Since(created)))
 }
@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/bndr/gotabulate"
 	"github.com/containers/storage"
@@ -136,6 +137,36 @@ func TestList(t *testing.T) {
 			wantErr:   true,
 			errString: "failed to list images with repository",
 		},
+		{
+			name: "normal case list all images sorted by name",
+			req: &pb.ListRequest{
+				SortBy: "name",
+			},
+			wantErr: false,
+		},
+		{
+			name: "normal case list all images sorted by size",
+			req: &pb.ListRequest{
+				SortBy: "size",
+			},
+			wantErr: false,
+		},
+		{
+			name: "abnormal case invalid sort key",
+			req: &pb.ListRequest{
+				SortBy: "color",
+			},
+			wantErr:   true,
+			errString: "invalid sort key",
+		},
+		{
+			name: "abnormal case negative page size",
+			req: &pb.ListRequest{
+				PageSize: -1,
+			},
+			wantErr:   true,
+			errString: "must not be negative",
+		},
 	}
 
 	for _, tc := range testcases {
@@ -154,6 +185,74 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestPaginate(t *testing.T) {
+	result := make([]*pb.ListResponse_ImageInfo, 0, 5)
+	for i := 0; i < 5; i++ {
+		result = append(result, &pb.ListResponse_ImageInfo{Id: fmt.Sprintf("id-%d", i)})
+	}
+
+	// a zero page size returns everything in one response
+	page, next := paginate(result, listOptions{})
+	assert.Equal(t, len(page), 5)
+	assert.Equal(t, next, int32(0))
+
+	// pages follow each other through the returned token
+	page, next = paginate(result, listOptions{pageSize: 2})
+	assert.Equal(t, len(page), 2)
+	assert.Equal(t, page[0].Id, "id-0")
+	assert.Equal(t, next, int32(2))
+	page, next = paginate(result, listOptions{pageSize: 2, pageToken: next})
+	assert.Equal(t, page[0].Id, "id-2")
+	assert.Equal(t, next, int32(4))
+
+	// the last page is short and ends the listing
+	page, next = paginate(result, listOptions{pageSize: 2, pageToken: next})
+	assert.Equal(t, len(page), 1)
+	assert.Equal(t, page[0].Id, "id-4")
+	assert.Equal(t, next, int32(0))
+
+	// a token past the end returns an empty page
+	page, next = paginate(result, listOptions{pageSize: 2, pageToken: 10})
+	assert.Equal(t, len(page), 0)
+	assert.Equal(t, next, int32(0))
+}
+
+func TestFormatCreatedTime(t *testing.T) {
+	created := time.Now().Add(-48 * time.Hour)
+
+	// default is the relative form other container tools show
+	assert.Equal(t, formatCreatedTime(created, false), "2 days ago")
+	// the exact form carries the full RFC3339 timestamp
+	assert.Equal(t, formatCreatedTime(created, true), created.Format(time.RFC3339))
+}
+
+func TestImageSizesSplit(t *testing.T) {
+	sizes := &imageSizes{
+		refs: map[string]int{"base": 2, "mid": 1, "top": 1, "other": 2},
+		layers: map[string]storage.Layer{
+			"base":  {ID: "base", UncompressedSize: 100},
+			"mid":   {ID: "mid", Parent: "base", UncompressedSize: 30},
+			"top":   {ID: "top", Parent: "mid", UncompressedSize: 5},
+			"other": {ID: "other", Parent: "base", UncompressedSize: -1},
+		},
+	}
+
+	// layers used by one image only are unique, the rest is shared
+	unique, shared := sizes.splitSizes("top")
+	assert.Equal(t, unique, int64(35))
+	assert.Equal(t, shared, int64(100))
+
+	// an unknown diff size makes both figures unknown
+	unique, shared = sizes.splitSizes("other")
+	assert.Equal(t, unique, int64(-1))
+	assert.Equal(t, shared, int64(-1))
+
+	// a chain with a missing layer stops at the gap
+	unique, shared = sizes.splitSizes("")
+	assert.Equal(t, unique, int64(0))
+	assert.Equal(t, shared, int64(0))
+}
+
 func formatAndPrint(images []*pb.ListResponse_ImageInfo) {
 	emptyStr := `-----------   ----   ---------   --------
 	REPOSITORY    TAG    IMAGE ID    CREATED
@@ -72,16 +72,17 @@ func (b *Backend) Info(ctx context.Context, req *pb.InfoRequest) (*pb.InfoRespon
 
 	// generate info response
 	infoResponse := &pb.InfoResponse{
-		MemInfo:      memInfo,
-		MemStat:      nil,
-		StorageInfo:  storageInfo,
-		RegistryInfo: registryInfo,
-		DataRoot:     b.daemon.opts.DataRoot,
-		RunRoot:      b.daemon.opts.RunRoot,
-		OCIRuntime:   b.daemon.opts.RuntimePath,
-		BuilderNum:   int64(len(b.daemon.builders)),
-		GoRoutines:   int64(runtime.NumGoroutine()),
-		Experimental: b.daemon.opts.Experimental,
+		MemInfo:        memInfo,
+		MemStat:        nil,
+		StorageInfo:    storageInfo,
+		RegistryInfo:   registryInfo,
+		DataRoot:       b.daemon.opts.DataRoot,
+		RunRoot:        b.daemon.opts.RunRoot,
+		OCIRuntime:     b.daemon.opts.RuntimePath,
+		BuilderNum:     int64(b.daemon.reg().count()),
+		GoRoutines:     int64(runtime.NumGoroutine()),
+		Experimental:   b.daemon.opts.Experimental,
+		BuildResources: b.daemon.reg().resources(),
 	}
 
 	if req.Verbose {
@@ -0,0 +1,99 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is "inspect" command for backend
+
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/image"
+)
+
+// inspectInfo is the inspect result of a local image, the keys stay
+// capitalized so format templates read like '{{.Config.Env}}'
+type inspectInfo struct {
+	Image        string                `json:"Image"`
+	ID           string                `json:"Id"`
+	Names        []string              `json:"Names,omitempty"`
+	Digest       string                `json:"Digest"`
+	Created      *time.Time            `json:"Created,omitempty"`
+	Os           string                `json:"Os"`
+	Architecture string                `json:"Architecture"`
+	ManifestType string                `json:"ManifestType"`
+	Manifest     json.RawMessage       `json:"Manifest"`
+	Config       imgspecv1.ImageConfig `json:"Config"`
+	Layers       []string              `json:"Layers"`
+}
+
+// Inspect returns the config, manifest and layer digests of a local
+// image as JSON for the client to render or format
+func (b *Backend) Inspect(ctx context.Context, req *pb.InspectRequest) (*pb.InspectResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"ImageName": req.GetImageName(),
+	}).Info("InspectRequest received")
+
+	imageName := req.GetImageName()
+	localRef, localImage, err := image.FindImage(b.daemon.localStore, imageName)
+	if err != nil {
+		return &pb.InspectResponse{}, errors.Wrapf(err, "find local image %q failed", imageName)
+	}
+	img, err := localRef.NewImage(ctx, image.GetSystemContext())
+	if err != nil {
+		return &pb.InspectResponse{}, errors.Wrapf(err, "parse local image %q failed", imageName)
+	}
+	defer func() {
+		if cErr := img.Close(); cErr != nil {
+			logrus.Warnf("Image closing error: %v", cErr)
+		}
+	}()
+
+	manifestBytes, manifestType, err := img.Manifest(ctx)
+	if err != nil {
+		return &pb.InspectResponse{}, errors.Wrapf(err, "get manifest of %q failed", imageName)
+	}
+	config, err := img.OCIConfig(ctx)
+	if err != nil {
+		return &pb.InspectResponse{}, errors.Wrapf(err, "read config of %q failed", imageName)
+	}
+
+	info := inspectInfo{
+		Image:        imageName,
+		ID:           localImage.ID,
+		Names:        localImage.Names,
+		Digest:       digest.FromBytes(manifestBytes).String(),
+		Created:      config.Created,
+		Os:           config.OS,
+		Architecture: config.Architecture,
+		ManifestType: manifestType,
+		Manifest:     json.RawMessage(manifestBytes),
+		Config:       config.Config,
+	}
+	for _, layer := range img.LayerInfos() {
+		info.Layers = append(info.Layers, layer.Digest.String())
+	}
+
+	data, err := json.MarshalIndent(info, "", "    ")
+	if err != nil {
+		return &pb.InspectResponse{}, errors.Wrap(err, "marshal inspect result failed")
+	}
+
+	return &pb.InspectResponse{Data: string(data)}, nil
+}
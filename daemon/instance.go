@@ -0,0 +1,261 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-01
+// Description: This file is "builder" command for backend
+
+package daemon
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/util"
+)
+
+// builderInstanceDirName is the dir under DataRoot storing the named builder instances
+const builderInstanceDirName = "builders"
+
+var builderInstanceNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// builderInstance is a named builder carrying defaults which are
+// applied to builds selecting it with --builder
+type builderInstance struct {
+	// Name is the name of the builder instance
+	Name string `json:"name"`
+	// BuildArgs are the default args for buildings with this builder
+	BuildArgs []string `json:"buildArgs,omitempty"`
+	// CapAddList indicates the default Linux capabilities for RUN command
+	CapAddList []string `json:"capAddList,omitempty"`
+	// Proxy marks for whether inherit proxy environments from host
+	Proxy bool `json:"proxy"`
+	// CreatedAt is the time the builder instance was created
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (b *Backend) builderInstanceDir() (string, error) {
+	return securejoin.SecureJoin(b.daemon.opts.DataRoot, builderInstanceDirName)
+}
+
+func (b *Backend) builderInstancePath(name string) (string, error) {
+	dir, err := b.builderInstanceDir()
+	if err != nil {
+		return "", err
+	}
+	return securejoin.SecureJoin(dir, name+".json")
+}
+
+func (b *Backend) loadBuilderInstance(name string) (*builderInstance, error) {
+	path, err := b.builderInstancePath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Errorf("builder instance %q not found", name)
+		}
+		return nil, errors.Wrapf(err, "read builder instance %q failed", name)
+	}
+
+	var instance builderInstance
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return nil, errors.Wrapf(err, "parse builder instance %q failed", name)
+	}
+
+	return &instance, nil
+}
+
+func (b *Backend) saveBuilderInstance(instance *builderInstance) error {
+	dir, err := b.builderInstanceDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, constant.DefaultRootDirMode); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(instance)
+	if err != nil {
+		return errors.Wrapf(err, "marshal builder instance %q failed", instance.Name)
+	}
+	path, err := b.builderInstancePath(instance.Name)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, constant.DefaultRootFileMode)
+}
+
+// BuilderCreate creates a named builder instance carrying build defaults
+func (b *Backend) BuilderCreate(ctx context.Context, req *pb.BuilderCreateRequest) (*pb.BuilderCreateResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"BuilderName": req.GetName(),
+	}).Info("BuilderCreateRequest received")
+
+	name := req.GetName()
+	if !builderInstanceNameRegexp.MatchString(name) {
+		return nil, errors.Errorf("invalid builder name %q", name)
+	}
+	for _, c := range req.GetCapAddList() {
+		if !util.CheckCap(c) {
+			return nil, errors.Errorf("cap %v is invalid", c)
+		}
+	}
+
+	if _, err := b.loadBuilderInstance(name); err == nil {
+		return nil, errors.Errorf("builder instance %q already exists", name)
+	}
+
+	instance := &builderInstance{
+		Name:       name,
+		BuildArgs:  req.GetBuildArgs(),
+		CapAddList: req.GetCapAddList(),
+		Proxy:      req.GetProxy(),
+		CreatedAt:  time.Now(),
+	}
+	if err := b.saveBuilderInstance(instance); err != nil {
+		return nil, errors.Wrapf(err, "save builder instance %q failed", name)
+	}
+
+	return &pb.BuilderCreateResponse{Name: name}, nil
+}
+
+// BuilderList lists the named builder instances
+func (b *Backend) BuilderList(ctx context.Context, req *pb.BuilderListRequest) (*pb.BuilderListResponse, error) {
+	logrus.Info("BuilderListRequest received")
+
+	dir, err := b.builderInstanceDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &pb.BuilderListResponse{}, nil
+		}
+		return nil, errors.Wrap(err, "list builder instances failed")
+	}
+
+	result := make([]*pb.BuilderListResponse_BuilderInfo, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		instance, lErr := b.loadBuilderInstance(strings.TrimSuffix(file.Name(), ".json"))
+		if lErr != nil {
+			logrus.Warnf("Loading builder instance from %q failed: %v", file.Name(), lErr)
+			continue
+		}
+		result = append(result, &pb.BuilderListResponse_BuilderInfo{
+			Name:       instance.Name,
+			BuildArgs:  instance.BuildArgs,
+			CapAddList: instance.CapAddList,
+			Proxy:      instance.Proxy,
+			Created:    instance.CreatedAt.Format(constant.LayoutTime),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return &pb.BuilderListResponse{Builders: result}, nil
+}
+
+// BuilderRemove removes a named builder instance
+func (b *Backend) BuilderRemove(ctx context.Context, req *pb.BuilderRemoveRequest) (*pb.BuilderRemoveResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"BuilderName": req.GetName(),
+	}).Info("BuilderRemoveRequest received")
+
+	name := req.GetName()
+	if _, err := b.loadBuilderInstance(name); err != nil {
+		return nil, err
+	}
+	path, err := b.builderInstancePath(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Remove(path); err != nil {
+		return nil, errors.Wrapf(err, "remove builder instance %q failed", name)
+	}
+
+	return &pb.BuilderRemoveResponse{Name: name}, nil
+}
+
+// applyBuilderInstance merges the defaults of the builder instance
+// selected by the build request into the request. Values given with the
+// request always win over the defaults of the builder instance
+func (b *Backend) applyBuilderInstance(req *pb.BuildRequest) error {
+	if req.GetBuilder() == "" {
+		return nil
+	}
+
+	instance, err := b.loadBuilderInstance(req.GetBuilder())
+	if err != nil {
+		return err
+	}
+
+	args := req.GetBuildArgs()
+	if req.GetEncrypted() {
+		// the defaults are stored in plain text, so the request args
+		// are decrypted here before merging to keep them uniform
+		decrypted := make([]string, 0, len(args))
+		for _, arg := range args {
+			plain, dErr := util.DecryptRSA(arg, b.daemon.key, crypto.SHA512)
+			if dErr != nil {
+				return errors.Wrap(dErr, "decrypt build args for builder instance failed")
+			}
+			decrypted = append(decrypted, plain)
+		}
+		args = decrypted
+		req.Encrypted = false
+	}
+
+	const kvNums = 2
+	givenKeys := make(map[string]bool, len(args))
+	for _, arg := range args {
+		givenKeys[strings.SplitN(arg, "=", kvNums)[0]] = true
+	}
+	for _, arg := range instance.BuildArgs {
+		if !givenKeys[strings.SplitN(arg, "=", kvNums)[0]] {
+			args = append(args, arg)
+		}
+	}
+	req.BuildArgs = args
+
+	givenCaps := make(map[string]bool, len(req.GetCapAddList()))
+	for _, c := range req.GetCapAddList() {
+		givenCaps[c] = true
+	}
+	for _, c := range instance.CapAddList {
+		if !givenCaps[c] {
+			req.CapAddList = append(req.CapAddList, c)
+		}
+	}
+
+	// proxy environments are only inherited when both the request and
+	// the builder instance allow it
+	req.Proxy = req.Proxy && instance.Proxy
+
+	return nil
+}
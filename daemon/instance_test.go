@@ -0,0 +1,105 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-01
+// Description: This file tests builder instance interfaces
+
+package daemon
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+func TestBuilderInstance(t *testing.T) {
+	d := prepare(t)
+	defer tmpClean(d)
+
+	ctx := context.Background()
+	backend := d.Daemon.backend
+
+	// invalid name is refused
+	_, err := backend.BuilderCreate(ctx, &pb.BuilderCreateRequest{Name: "/bad/name"})
+	assert.ErrorContains(t, err, "invalid builder name")
+
+	// invalid capability is refused
+	_, err = backend.BuilderCreate(ctx, &pb.BuilderCreateRequest{
+		Name:       "mybuilder",
+		CapAddList: []string{"CAP_NOT_EXIST"},
+	})
+	assert.ErrorContains(t, err, "is invalid")
+
+	resp, err := backend.BuilderCreate(ctx, &pb.BuilderCreateRequest{
+		Name:       "mybuilder",
+		BuildArgs:  []string{"http_proxy=http://proxy.example.com"},
+		CapAddList: []string{"CAP_SYS_ADMIN"},
+		Proxy:      true,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, resp.Name, "mybuilder")
+
+	// duplicated name is refused
+	_, err = backend.BuilderCreate(ctx, &pb.BuilderCreateRequest{Name: "mybuilder"})
+	assert.ErrorContains(t, err, "already exists")
+
+	listResp, err := backend.BuilderList(ctx, &pb.BuilderListRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(listResp.Builders), 1)
+	assert.Equal(t, listResp.Builders[0].Name, "mybuilder")
+	assert.Equal(t, listResp.Builders[0].BuildArgs[0], "http_proxy=http://proxy.example.com")
+
+	rmResp, err := backend.BuilderRemove(ctx, &pb.BuilderRemoveRequest{Name: "mybuilder"})
+	assert.NilError(t, err)
+	assert.Equal(t, rmResp.Name, "mybuilder")
+
+	_, err = backend.BuilderRemove(ctx, &pb.BuilderRemoveRequest{Name: "mybuilder"})
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestApplyBuilderInstance(t *testing.T) {
+	d := prepare(t)
+	defer tmpClean(d)
+
+	ctx := context.Background()
+	backend := d.Daemon.backend
+
+	_, err := backend.BuilderCreate(ctx, &pb.BuilderCreateRequest{
+		Name:       "mybuilder",
+		BuildArgs:  []string{"foo=default", "bar=default"},
+		CapAddList: []string{"CAP_SYS_ADMIN"},
+		Proxy:      false,
+	})
+	assert.NilError(t, err)
+
+	// no builder selected leaves the request untouched
+	req := &pb.BuildRequest{Proxy: true}
+	assert.NilError(t, backend.applyBuilderInstance(req))
+	assert.Equal(t, len(req.BuildArgs), 0)
+	assert.Equal(t, req.Proxy, true)
+
+	// unknown builder is an error
+	req = &pb.BuildRequest{Builder: "nobuilder"}
+	assert.ErrorContains(t, backend.applyBuilderInstance(req), "not found")
+
+	// given args win over the builder defaults
+	req = &pb.BuildRequest{
+		Builder:   "mybuilder",
+		BuildArgs: []string{"foo=given"},
+		Proxy:     true,
+	}
+	assert.NilError(t, backend.applyBuilderInstance(req))
+	assert.DeepEqual(t, req.BuildArgs, []string{"foo=given", "bar=default"})
+	assert.DeepEqual(t, req.CapAddList, []string{"CAP_SYS_ADMIN"})
+	assert.Equal(t, req.Proxy, false)
+}
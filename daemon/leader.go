@@ -0,0 +1,78 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-30
+// Description: standby daemon RPC gating based on the leader election
+
+package daemon
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"isula.org/isula-build/pkg/leader"
+)
+
+// readOnlyMethods lists the RPCs a standby daemon still serves. Any
+// method not listed here mutates the shared store or the build state
+// and is only served by the active daemon
+var readOnlyMethods = map[string]bool{
+	"/isula.build.v1.Control/Status":          true,
+	"/isula.build.v1.Control/List":            true,
+	"/isula.build.v1.Control/Version":         true,
+	"/isula.build.v1.Control/HealthCheck":     true,
+	"/isula.build.v1.Control/Info":            true,
+	"/isula.build.v1.Control/ManifestInspect": true,
+	"/isula.build.v1.Control/CacheList":       true,
+	"/isula.build.v1.Control/BuilderList":     true,
+	"/isula.build.v1.Control/Negotiate":       true,
+	"/isula.build.v1.Control/BuildsList":      true,
+	"/isula.build.v1.Control/RemoteInspect":   true,
+}
+
+// SetLeaderElector attaches the leader elector to the daemon. Without
+// an elector the daemon always acts as the active one
+func (d *Daemon) SetLeaderElector(e *leader.Elector) {
+	d.elector = e
+}
+
+// isLeader reports whether the daemon may mutate the shared store
+func (d *Daemon) isLeader() bool {
+	return d.elector == nil || d.elector.IsLeader()
+}
+
+// checkLeader refuses mutating methods while the daemon runs as the
+// standby of an active/standby pair
+func (d *Daemon) checkLeader(method string) error {
+	if d.isLeader() || readOnlyMethods[method] {
+		return nil
+	}
+	return errors.Errorf("the daemon is running in standby mode, %s is only served by the active daemon", method)
+}
+
+// leaderUnaryInterceptor gates unary RPCs with the leader check
+func (d *Daemon) leaderUnaryInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := d.checkLeader(info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// leaderStreamInterceptor gates streaming RPCs with the leader check
+func (d *Daemon) leaderStreamInterceptor(srv interface{}, ss grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := d.checkLeader(info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
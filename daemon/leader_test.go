@@ -0,0 +1,62 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-30
+// Description: standby daemon RPC gating related tests
+
+package daemon
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"isula.org/isula-build/pkg/leader"
+)
+
+func TestCheckLeader(t *testing.T) {
+	d := &Daemon{opts: &Options{}}
+
+	// without an elector the daemon always acts as the active one
+	assert.NilError(t, d.checkLeader("/isula.build.v1.Control/Build"))
+
+	dir := fs.NewDir(t, t.Name())
+	defer dir.Remove()
+	lockPath := filepath.Join(dir.Path(), "leader.lock")
+
+	active := leader.NewElector(lockPath, leader.DefaultCampaignInterval)
+	locked, err := active.TryAcquire()
+	assert.NilError(t, err)
+	assert.Equal(t, locked, true)
+	defer active.Resign()
+
+	standby := leader.NewElector(lockPath, leader.DefaultCampaignInterval)
+	_, err = standby.TryAcquire()
+	assert.NilError(t, err)
+	d.SetLeaderElector(standby)
+
+	err = d.checkLeader("/isula.build.v1.Control/Build")
+	assert.ErrorContains(t, err, "standby mode")
+	err = d.checkLeader("/isula.build.v1.Control/Remove")
+	assert.ErrorContains(t, err, "only served by the active daemon")
+
+	// read-only methods are still served by the standby daemon
+	assert.NilError(t, d.checkLeader("/isula.build.v1.Control/List"))
+	assert.NilError(t, d.checkLeader("/isula.build.v1.Control/HealthCheck"))
+
+	assert.NilError(t, active.Resign())
+	locked, err = standby.TryAcquire()
+	assert.NilError(t, err)
+	assert.Equal(t, locked, true)
+	assert.NilError(t, d.checkLeader("/isula.build.v1.Control/Build"))
+	assert.NilError(t, standby.Resign())
+}
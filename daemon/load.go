@@ -14,15 +14,21 @@
 package daemon
 
 import (
-	"context"
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/containers/image/v5/docker/tarfile"
-	ociarchive "github.com/containers/image/v5/oci/archive"
-	"github.com/containers/image/v5/transports/alltransports"
+	"github.com/containers/image/v5/pkg/compression"
 	"github.com/containers/image/v5/types"
 	"github.com/containers/storage"
+	"github.com/containers/storage/pkg/archive"
 	securejoin "github.com/cyphar/filepath-securejoin"
 	digest "github.com/opencontainers/go-digest"
 	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
@@ -43,25 +49,51 @@ type singleImage struct {
 	index   int
 	id      string
 	nameTag []string
+	// ref selects one image of an oci layout, empty for the other formats
+	ref string
 }
 
+// loadedImage records one image committed by the current load batch,
+// keeping what is needed to roll it back
+type loadedImage struct {
+	id            string
+	existedBefore bool
+	originalNames []string
+}
+
+// loadProgressInterval is the time between two progress reports of one blob
+const loadProgressInterval = time.Second
+
 // LoadOptions stores the options for image loading
 type LoadOptions struct {
-	LogEntry *logrus.Entry
-	path     string
-	format   string
-	sep      separator.Loader
+	LogEntry    *logrus.Entry
+	path        string
+	format      string
+	archiveSize int64
+	// nameOverride renames the loaded image, it needs an archive
+	// holding exactly one image
+	nameOverride string
+	// tmpLayoutDir is where a multi-image oci archive is unpacked,
+	// removed when the load ends
+	tmpLayoutDir string
+	sep          separator.Loader
 }
 
 func (b *Backend) getLoadOptions(req *pb.LoadRequest) (LoadOptions, error) {
 	var err error
 	var opt = LoadOptions{
-		path:     req.GetPath(),
-		LogEntry: logrus.WithFields(logrus.Fields{"LoadID": req.GetLoadID()}),
+		path:         req.GetPath(),
+		nameOverride: req.GetName(),
+		LogEntry:     logrus.WithFields(logrus.Fields{"LoadID": req.GetLoadID()}),
 	}
 
 	// normal image loading
 	if !req.GetSep().GetEnabled() {
+		// an oci layout directory is loaded in place, the size check
+		// only applies to tarballs
+		if util.IsOCILayoutDir(opt.path) {
+			return opt, nil
+		}
 		if err = util.CheckFileInfoAndSize(opt.path, constant.MaxLoadFileSize); err != nil {
 			return LoadOptions{}, err
 		}
@@ -94,6 +126,11 @@ func (b *Backend) Load(req *pb.LoadRequest, stream pb.Control_LoadServer) error
 		if tErr := os.RemoveAll(opts.sep.TmpDirRoot()); tErr != nil {
 			opts.LogEntry.Warnf("Removing load tmp directory %q failed: %v", opts.sep.TmpDirRoot(), tErr)
 		}
+		if opts.tmpLayoutDir != "" {
+			if tErr := os.RemoveAll(opts.tmpLayoutDir); tErr != nil {
+				opts.LogEntry.Warnf("Removing load layout directory %q failed: %v", opts.tmpLayoutDir, tErr)
+			}
+		}
 	}()
 
 	// construct separated images
@@ -105,11 +142,33 @@ func (b *Backend) Load(req *pb.LoadRequest, stream pb.Control_LoadServer) error
 		}
 	}
 
+	if fi, sErr := os.Stat(opts.path); sErr == nil {
+		opts.archiveSize = fi.Size()
+	}
+
 	imagesInTar, err := tryToParseImageFormatFromTarball(b.daemon.opts.DataRoot, &opts)
 	if err != nil {
 		return err
 	}
 
+	if opts.nameOverride != "" {
+		if len(imagesInTar) != 1 {
+			return errors.Errorf("name override needs an archive with exactly one image, this one holds %d", len(imagesInTar))
+		}
+		tagged, name, tErr := image.GetNamedTaggedReference(opts.nameOverride)
+		if tErr != nil || tagged == nil {
+			return errors.Errorf("invalid name override %q", opts.nameOverride)
+		}
+		imagesInTar[0].nameTag = []string{name}
+	}
+
+	// snapshot the present layers so the partial ones created by an
+	// interrupted load can be told apart and cleaned up
+	layersBefore, err := b.snapshotLayerIDs()
+	if err != nil {
+		return err
+	}
+
 	log := logger.NewCliLogger(constant.CliLogBufferLen)
 	eg, ctx := errgroup.WithContext(stream.Context())
 	eg.Go(func() error {
@@ -123,30 +182,61 @@ func (b *Backend) Load(req *pb.LoadRequest, stream pb.Control_LoadServer) error
 		return nil
 	})
 
+	progress := make(chan types.ProgressProperties, constant.CliLogBufferLen)
+	eg.Go(func() error {
+		reportLoadProgress(opts.archiveSize, progress, log, opts.LogEntry)
+		return nil
+	})
+
+	imagesBefore, err := b.snapshotImageIDs()
+	if err != nil {
+		return err
+	}
+
 	eg.Go(func() error {
 		defer log.CloseContent()
+		defer close(progress)
+
+		var loaded []loadedImage
+		cleanup := func(lErr error) {
+			b.cleanupLoadFailure(req.GetKeepPartial(), loaded, layersBefore, opts.LogEntry, lErr)
+		}
 
 		for _, singleImage := range imagesInTar {
+			fromImage := exporter.FormatTransport(opts.format, opts.path)
+			if singleImage.ref != "" {
+				fromImage = fmt.Sprintf("%s:%s", fromImage, singleImage.ref)
+			}
 			_, si, err = image.ResolveFromImage(&image.PrepareImageOptions{
-				Ctx:           ctx,
-				FromImage:     exporter.FormatTransport(opts.format, opts.path),
-				ToImage:       singleImage.id,
-				SystemContext: image.GetSystemContext(),
-				Store:         b.daemon.localStore,
-				Reporter:      log,
-				ManifestIndex: singleImage.index,
+				Ctx:              ctx,
+				FromImage:        fromImage,
+				ToImage:          singleImage.id,
+				SystemContext:    image.GetSystemContext(),
+				Store:            b.daemon.localStore,
+				Reporter:         log,
+				ManifestIndex:    singleImage.index,
+				Progress:         progress,
+				ProgressInterval: loadProgressInterval,
 			})
 			if err != nil {
+				cleanup(err)
 				return err
 			}
 
 			originalNames, err := b.daemon.localStore.Names(si.ID)
 			if err != nil {
+				cleanup(err)
 				return err
 			}
 			if err = b.daemon.localStore.SetNames(si.ID, append(originalNames, singleImage.nameTag...)); err != nil {
+				cleanup(err)
 				return err
 			}
+			loaded = append(loaded, loadedImage{
+				id:            si.ID,
+				existedBefore: imagesBefore[si.ID],
+				originalNames: originalNames,
+			})
 
 			log.Print("Loaded image as %s\n", si.ID)
 			logrus.Infof("Loaded image as %s", si.ID)
@@ -162,17 +252,177 @@ func (b *Backend) Load(req *pb.LoadRequest, stream pb.Control_LoadServer) error
 	return nil
 }
 
+// reportLoadProgress aggregates the per-blob progress events and prints
+// the loading percentage based on the archive size
+func reportLoadProgress(archiveSize int64, progress <-chan types.ProgressProperties, log *logger.Logger, entry *logrus.Entry) {
+	if archiveSize <= 0 {
+		// the size is unknown, just drain the channel
+		for range progress {
+		}
+		return
+	}
+
+	const percentFull = 100
+	offsets := make(map[digest.Digest]int64)
+	lastPercent := int64(-1)
+	for p := range progress {
+		offsets[p.Artifact.Digest] = int64(p.Offset)
+		var copied int64
+		for _, offset := range offsets {
+			copied += offset
+		}
+		percent := copied * percentFull / archiveSize
+		// the blobs in archive are compressed while offsets count the
+		// uncompressed bytes, do not report full before load finishes
+		if percent >= percentFull {
+			percent = percentFull - 1
+		}
+		if percent != lastPercent {
+			lastPercent = percent
+			log.Print("Loading %s (%d%%)\r", util.FormatSize(float64(archiveSize), decimalPrefixBase), percent)
+			entry.Debugf("Loading progress %d%%", percent)
+		}
+	}
+}
+
+// snapshotImageIDs records the IDs of all present images
+func (b *Backend) snapshotImageIDs() (map[string]bool, error) {
+	images, err := b.daemon.localStore.Images()
+	if err != nil {
+		return nil, errors.Wrap(err, "list images failed")
+	}
+	ids := make(map[string]bool, len(images))
+	for i := range images {
+		ids[images[i].ID] = true
+	}
+
+	return ids, nil
+}
+
+// cleanupLoadFailure undoes the images a failed load batch committed,
+// unless the caller asked to keep the partial result
+func (b *Backend) cleanupLoadFailure(keepPartial bool, loaded []loadedImage, layersBefore map[string]bool, entry *logrus.Entry, lErr error) {
+	if keepPartial {
+		entry.Infof("Load failed: %v, keeping %d already loaded images", lErr, len(loaded))
+		return
+	}
+	b.rollbackLoadedImages(loaded, entry)
+	b.cleanupPartialLayers(layersBefore, entry)
+}
+
+// rollbackLoadedImages undoes the images committed by a failed load
+// batch, deleting the new ones and restoring the names of the images
+// which existed before the batch
+func (b *Backend) rollbackLoadedImages(loaded []loadedImage, entry *logrus.Entry) {
+	for i := len(loaded) - 1; i >= 0; i-- {
+		img := loaded[i]
+		if img.existedBefore {
+			if err := b.daemon.localStore.SetNames(img.id, img.originalNames); err != nil {
+				entry.Warnf("Restoring names of image %s in rollback failed: %v", img.id, err)
+			}
+			continue
+		}
+		if _, err := b.daemon.localStore.DeleteImage(img.id, true); err != nil {
+			entry.Warnf("Deleting image %s in rollback failed: %v", img.id, err)
+			continue
+		}
+		entry.Infof("Rolled back image %s", img.id)
+	}
+}
+
+// snapshotLayerIDs records the IDs of all present layers
+func (b *Backend) snapshotLayerIDs() (map[string]bool, error) {
+	layers, err := b.daemon.localStore.Layers()
+	if err != nil {
+		return nil, errors.Wrap(err, "list layers failed")
+	}
+	ids := make(map[string]bool, len(layers))
+	for i := range layers {
+		ids[layers[i].ID] = true
+	}
+
+	return ids, nil
+}
+
+// cleanupPartialLayers deletes the unreferenced layers created after the
+// snapshot, which are left by an interrupted load
+func (b *Backend) cleanupPartialLayers(snapshot map[string]bool, entry *logrus.Entry) {
+	buildersNum := b.daemon.reg().count()
+	if buildersNum != 0 {
+		// layers of in-flight builds are unreferenced as well, leave
+		// the cleanup to them to avoid deleting their fresh layers
+		entry.Infof("Skip partial layer cleanup since %d builds are running", buildersNum)
+		return
+	}
+
+	store := b.daemon.localStore
+	images, err := store.Images()
+	if err != nil {
+		entry.Warnf("Listing images for partial layer cleanup failed: %v", err)
+		return
+	}
+	referenced := make(map[string]bool)
+	for i := range images {
+		referenced[images[i].TopLayer] = true
+		for _, id := range images[i].MappedTopLayers {
+			referenced[id] = true
+		}
+	}
+
+	// layers form chains, deleting children may make parents deletable,
+	// so repeat until no more layer can be deleted
+	for {
+		layers, err := store.Layers()
+		if err != nil {
+			entry.Warnf("Listing layers for partial layer cleanup failed: %v", err)
+			return
+		}
+		hasChild := make(map[string]bool)
+		for i := range layers {
+			if layers[i].Parent != "" {
+				hasChild[layers[i].Parent] = true
+			}
+		}
+		deleted := 0
+		for i := range layers {
+			id := layers[i].ID
+			if snapshot[id] || referenced[id] || hasChild[id] {
+				continue
+			}
+			if dErr := store.DeleteLayer(id); dErr != nil {
+				entry.Warnf("Deleting partial layer %s failed: %v", id, dErr)
+				continue
+			}
+			entry.Infof("Deleted partial layer %s", id)
+			deleted++
+		}
+		if deleted == 0 {
+			return
+		}
+	}
+}
+
 func tryToParseImageFormatFromTarball(dataRoot string, opts *LoadOptions) ([]singleImage, error) {
-	// tmp dir will be removed after NewSourceFromFileWithContext
+	// only a multi-image oci archive is unpacked below this tmp dir,
+	// the format probes stream the tarball without writing anything
 	tmpDir, err := securejoin.SecureJoin(dataRoot, constant.DataRootTmpDirPrefix)
 	if err != nil {
 		return nil, err
 	}
-	systemContext := image.GetSystemContext()
-	systemContext.BigFilesTemporaryDir = tmpDir
+
+	// an oci layout directory is loaded in place without any unpacking
+	if util.IsOCILayoutDir(opts.path) {
+		imagesInTar, lErr := getOCIRepoTagFromLayout(opts.path)
+		if lErr != nil {
+			return nil, errors.Wrapf(lErr, "parse oci layout %q failed", opts.path)
+		}
+		logrus.Infof("Parse image successful with oci layout format")
+		opts.format = constant.OCITransport
+		return imagesInTar, nil
+	}
 
 	// try docker format loading
-	imagesInTar, err := getDockerRepoTagFromImageTar(systemContext, opts.path)
+	imagesInTar, err := getDockerRepoTagFromImageTar(opts.path)
 	if err == nil {
 		logrus.Infof("Parse image successful with %q format", constant.DockerTransport)
 		opts.format = constant.DockerArchiveTransport
@@ -181,7 +431,7 @@ func tryToParseImageFormatFromTarball(dataRoot string, opts *LoadOptions) ([]sin
 	logrus.Warnf("Try to Parse image of docker format failed with error: %v", err)
 
 	// try oci format loading
-	imagesInTar, err = getOCIRepoTagFromImageTar(systemContext, opts.path)
+	imagesInTar, err = getOCIRepoTagFromImageTar(opts.path)
 	if err == nil {
 		logrus.Infof("Parse image successful with %q format", constant.OCITransport)
 		opts.format = constant.OCIArchiveTransport
@@ -189,24 +439,181 @@ func tryToParseImageFormatFromTarball(dataRoot string, opts *LoadOptions) ([]sin
 	}
 	logrus.Warnf("Try to parse image of oci format failed with error: %v", err)
 
+	// an oci archive holding several images cannot go through the
+	// oci-archive transport, unpack it into a layout directory and
+	// load every index entry from there
+	imagesInTar, mErr := getOCIRepoTagFromMultiImageTar(tmpDir, opts)
+	if mErr == nil {
+		logrus.Infof("Parse image successful with multi-image oci archive format")
+		opts.format = constant.OCITransport
+		return imagesInTar, nil
+	}
+	logrus.Warnf("Try to parse image of multi-image oci archive format failed with error: %v", mErr)
+
 	// record the last error
 	return nil, errors.Wrap(err, "wrong image format detected from local tarball")
 }
 
-func getDockerRepoTagFromImageTar(systemContext *types.SystemContext, path string) ([]singleImage, error) {
-	// tmp dir will be removed after NewSourceFromFileWithContext
-	tarfileSource, err := tarfile.NewSourceFromFileWithContext(systemContext, path)
+// getOCIRepoTagFromMultiImageTar unpacks an oci archive into a layout
+// directory below tmpBase and enumerates its images, the caller loads
+// them from the layout and removes the directory afterwards
+func getOCIRepoTagFromMultiImageTar(tmpBase string, opts *LoadOptions) ([]singleImage, error) {
+	if err := os.MkdirAll(tmpBase, constant.DefaultRootDirMode); err != nil {
+		return nil, err
+	}
+	layoutDir, err := ioutil.TempDir(tmpBase, "load-oci-")
+	if err != nil {
+		return nil, errors.Wrap(err, "create layout dir failed")
+	}
+	cleanup := func() {
+		if rErr := os.RemoveAll(layoutDir); rErr != nil {
+			opts.LogEntry.Warnf("Removing load layout directory %q failed: %v", layoutDir, rErr)
+		}
+	}
+	if err := util.UnpackFile(opts.path, layoutDir, archive.Uncompressed, false); err != nil {
+		cleanup()
+		return nil, errors.Wrapf(err, "unpack archive %q failed", opts.path)
+	}
+	if !util.IsOCILayoutDir(layoutDir) {
+		cleanup()
+		return nil, errors.Errorf("archive %q does not hold an oci layout", opts.path)
+	}
+	imagesInTar, err := getOCIRepoTagFromLayout(layoutDir)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	opts.path = layoutDir
+	opts.tmpLayoutDir = layoutDir
+
+	return imagesInTar, nil
+}
+
+// getOCIRepoTagFromLayout enumerates the images recorded in the index
+// of an oci layout directory
+func getOCIRepoTagFromLayout(layoutDir string) ([]singleImage, error) {
+	data, err := ioutil.ReadFile(filepath.Join(layoutDir, "index.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "read layout index failed")
+	}
+	var index imgspecv1.Index
+	if err = json.Unmarshal(data, &index); err != nil {
+		return nil, errors.Wrap(err, "parse layout index failed")
+	}
+	if len(index.Manifests) == 0 {
+		return nil, errors.New("layout index holds no image")
+	}
+
+	imagesInTar := make([]singleImage, 0, len(index.Manifests))
+	for i, desc := range index.Manifests {
+		ref := desc.Annotations[imgspecv1.AnnotationRefName]
+		if ref == "" && len(index.Manifests) > 1 {
+			return nil, errors.Errorf("image %d in the layout index has no ref annotation", i)
+		}
+		imageID, err := layoutImageID(layoutDir, desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		// a ref naming an image keeps it as tag, one recording only
+		// an ID leaves the image untagged
+		var nameTag []string
+		if tagged, name, tErr := image.GetNamedTaggedReference(ref); tErr == nil && tagged != nil {
+			nameTag = []string{name}
+		}
+		imagesInTar = append(imagesInTar, singleImage{index: i, id: imageID, nameTag: nameTag, ref: ref})
+	}
+
+	return imagesInTar, nil
+}
+
+// layoutImageID reads the manifest blob of one layout image and returns
+// the image ID recorded as its config digest
+func layoutImageID(layoutDir string, manifestDigest digest.Digest) (string, error) {
+	if err := manifestDigest.Validate(); err != nil {
+		return "", errors.Wrap(err, "invalid manifest digest in layout index")
+	}
+	blobPath := filepath.Join(layoutDir, "blobs", manifestDigest.Algorithm().String(), manifestDigest.Encoded())
+	data, err := ioutil.ReadFile(filepath.Clean(blobPath))
+	if err != nil {
+		return "", errors.Wrap(err, "read manifest blob failed")
+	}
+	var m imgspecv1.Manifest
+	if err = json.Unmarshal(data, &m); err != nil {
+		return "", errors.Wrap(err, "parse manifest blob failed")
+	}
+	if err = m.Config.Digest.Validate(); err != nil {
+		return "", errors.Wrap(err, "invalid config digest in manifest")
+	}
+
+	return "@" + m.Config.Digest.Encoded(), nil
+}
+
+// openArchiveStream opens a tarball for one sequential pass,
+// decompressing gzip or zstd input on the fly
+func openArchiveStream(path string) (*tar.Reader, func(), error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, nil, err
+	}
+	stream, _, err := compression.AutoDecompress(f)
+	if err != nil {
+		if cErr := f.Close(); cErr != nil {
+			logrus.Warnf("Closing archive %q failed: %v", path, cErr)
+		}
+		return nil, nil, errors.Wrapf(err, "detect the compression of %q failed", path)
+	}
+	closer := func() {
+		if cErr := stream.Close(); cErr != nil {
+			logrus.Warnf("Closing the stream of archive %q failed: %v", path, cErr)
+		}
+		if cErr := f.Close(); cErr != nil {
+			logrus.Warnf("Closing archive %q failed: %v", path, cErr)
+		}
+	}
+
+	return tar.NewReader(stream), closer, nil
+}
+
+// readTarEntry reads one metadata entry of the archive, refusing the
+// oversized ones so a crafted archive cannot exhaust the memory
+func readTarEntry(hdr *tar.Header, r io.Reader) ([]byte, error) {
+	if hdr.Size > constant.JSONMaxFileSize {
+		return nil, errors.Errorf("metadata entry %q is too large (%d bytes)", hdr.Name, hdr.Size)
+	}
+
+	return ioutil.ReadAll(io.LimitReader(r, hdr.Size))
+}
+
+// getDockerRepoTagFromImageTar streams the archive once looking for
+// its manifest.json, the layer blobs pass by without being unpacked or
+// spooled to a temporary file, so probing a tarball bigger than the
+// memory takes a constant footprint
+func getDockerRepoTagFromImageTar(path string) ([]singleImage, error) {
+	tr, closer, err := openArchiveStream(path)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get the source of loading tar file")
 	}
-	defer func() {
-		if cErr := tarfileSource.Close(); cErr != nil {
-			logrus.Warnf("tar file source close failed: %v", cErr)
+	defer closer()
+
+	var manifestData []byte
+	for manifestData == nil {
+		hdr, nErr := tr.Next()
+		if nErr == io.EOF {
+			return nil, errors.Errorf("no manifest.json entry found in archive %q", path)
 		}
-	}()
+		if nErr != nil {
+			return nil, errors.Wrapf(nErr, "read archive %q failed", path)
+		}
+		if filepath.Clean(hdr.Name) != "manifest.json" {
+			continue
+		}
+		if manifestData, err = readTarEntry(hdr, tr); err != nil {
+			return nil, errors.Wrapf(err, "read the top level image manifest of %q failed", path)
+		}
+	}
 
-	topLevelImageManifest, err := tarfileSource.LoadTarManifest()
-	if err != nil || len(topLevelImageManifest) == 0 {
+	var topLevelImageManifest []tarfile.ManifestItem
+	if err = json.Unmarshal(manifestData, &topLevelImageManifest); err != nil || len(topLevelImageManifest) == 0 {
 		return nil, errors.Errorf("failed to get the top level image manifest: %v", err)
 	}
 
@@ -222,26 +629,82 @@ func getDockerRepoTagFromImageTar(systemContext *types.SystemContext, path strin
 	return imagesInTar, nil
 }
 
-func getOCIRepoTagFromImageTar(systemContext *types.SystemContext, path string) ([]singleImage, error) {
-	srcRef, err := alltransports.ParseImageName(exporter.FormatTransport(constant.OCIArchiveTransport, path))
+// getOCIRepoTagFromImageTar streams the archive once, keeping only its
+// index and the small json blobs like manifests and configs, the layer
+// blobs pass by uninspected
+func getOCIRepoTagFromImageTar(path string) ([]singleImage, error) {
+	tr, closer, err := openArchiveStream(path)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse image name of oci image format")
+		return nil, errors.Wrap(err, "failed to get the source of loading tar file")
+	}
+	defer closer()
+
+	const blobPathParts = 3
+	var indexData []byte
+	metaBlobs := make(map[string][]byte)
+	for {
+		hdr, nErr := tr.Next()
+		if nErr == io.EOF {
+			break
+		}
+		if nErr != nil {
+			return nil, errors.Wrapf(nErr, "read archive %q failed", path)
+		}
+		name := filepath.Clean(hdr.Name)
+		if name == "index.json" {
+			if indexData, err = readTarEntry(hdr, tr); err != nil {
+				return nil, errors.Wrapf(err, "read the index of %q failed", path)
+			}
+			continue
+		}
+		// the blob entries worth keeping are json documents, which the
+		// index may point to as manifests, everything else streams by
+		parts := strings.Split(name, "/")
+		if len(parts) != blobPathParts || parts[0] != "blobs" || hdr.Size > constant.JSONMaxFileSize {
+			continue
+		}
+		data, rErr := ioutil.ReadAll(io.LimitReader(tr, hdr.Size))
+		if rErr != nil {
+			return nil, errors.Wrapf(rErr, "read blob entry %q of %q failed", hdr.Name, path)
+		}
+		if len(data) > 0 && data[0] == '{' {
+			metaBlobs[parts[1]+":"+parts[2]] = data
+		}
 	}
 
-	imageID, err := getLoadedImageID(srcRef, systemContext)
-	if err != nil {
-		return nil, err
+	if indexData == nil {
+		return nil, errors.Errorf("no index.json entry found in archive %q", path)
 	}
-	tarManifest, err := ociarchive.LoadManifestDescriptorWithContext(systemContext, srcRef)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to load manifest descriptor of oci image format")
+	var index imgspecv1.Index
+	if err = json.Unmarshal(indexData, &index); err != nil {
+		return nil, errors.Wrapf(err, "parse the index of %q failed", path)
+	}
+	// a single image loads straight from the archive, one holding
+	// several images is unpacked and loaded as a layout instead
+	if len(index.Manifests) != 1 {
+		return nil, errors.Errorf("archive %q holds %d images, not exactly one", path, len(index.Manifests))
 	}
+	desc := index.Manifests[0]
+	if err = desc.Digest.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid manifest digest in archive index")
+	}
+	manifestData, ok := metaBlobs[desc.Digest.String()]
+	if !ok {
+		return nil, errors.Errorf("manifest blob %s not found in archive %q", desc.Digest, path)
+	}
+	var m imgspecv1.Manifest
+	if err = json.Unmarshal(manifestData, &m); err != nil {
+		return nil, errors.Wrap(err, "parse manifest blob failed")
+	}
+	if err = m.Config.Digest.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid config digest in manifest")
+	}
+	imageID := "@" + m.Config.Digest.Encoded()
 
-	// For now, we only support loading oci-archive file with one single image
-	if _, ok := tarManifest.Annotations[imgspecv1.AnnotationRefName]; ok {
-		return []singleImage{{0, imageID, []string{tarManifest.Annotations[imgspecv1.AnnotationRefName]}}}, nil
+	if ref, ok := desc.Annotations[imgspecv1.AnnotationRefName]; ok {
+		return []singleImage{{index: 0, id: imageID, nameTag: []string{ref}}}, nil
 	}
-	return []singleImage{{0, imageID, []string{}}}, nil
+	return []singleImage{{index: 0, id: imageID, nameTag: []string{}}}, nil
 }
 
 func parseConfigID(configID string) (string, error) {
@@ -257,25 +720,3 @@ func parseConfigID(configID string) (string, error) {
 
 	return "@" + configDigest.Encoded(), nil
 }
-
-func getLoadedImageID(imageRef types.ImageReference, systemContext *types.SystemContext) (string, error) {
-	if imageRef == nil || systemContext == nil {
-		return "", errors.New("nil image reference or system context when loading image")
-	}
-
-	newImage, err := imageRef.NewImage(context.TODO(), systemContext)
-	if err != nil {
-		return "", err
-	}
-	defer func() {
-		if err = newImage.Close(); err != nil {
-			logrus.Errorf("failed to close image: %v", err)
-		}
-	}()
-	imageDigest := newImage.ConfigInfo().Digest
-	if err = imageDigest.Validate(); err != nil {
-		return "", errors.Wrap(err, "failed to get config info")
-	}
-
-	return "@" + imageDigest.Encoded(), nil
-}
@@ -15,12 +15,19 @@ package daemon
 
 import (
 	"archive/tar"
+	"compress/gzip"
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"testing"
 
+	"github.com/containers/storage"
 	"github.com/containers/storage/pkg/reexec"
+	"github.com/containers/storage/pkg/stringid"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
 	"google.golang.org/grpc"
 	"gotest.tools/v3/assert"
@@ -170,7 +177,7 @@ func TestLoadSingleImage(t *testing.T) {
 			format:    "oci",
 			withTag:   true,
 			wantErr:   true,
-			errString: "no such file or directory",
+			errString: "not found in archive",
 		},
 		{
 			name: "TC3 normal case load docker tar with no RepoTags",
@@ -202,7 +209,7 @@ func TestLoadSingleImage(t *testing.T) {
 			format:    "oci",
 			withTag:   false,
 			wantErr:   true,
-			errString: "no such file or directory",
+			errString: "not found in archive",
 		},
 		{
 			name: "TC5 abnormal case load docker tar with wrong manifestJSON",
@@ -221,7 +228,7 @@ func TestLoadSingleImage(t *testing.T) {
 			format:    "docker",
 			withTag:   true,
 			wantErr:   true,
-			errString: "no such file or directory",
+			errString: "no index.json entry found",
 		},
 		{
 			name: "TC6 abnormal case with wrong tar path",
@@ -327,3 +334,166 @@ func TestLoadMultipleImages(t *testing.T) {
 	err = daemon.backend.Load(req, stream)
 	assert.ErrorContains(t, err, "failed to get the image")
 }
+
+// writeProbeTarEntry writes one file entry into the archive under build
+func writeProbeTarEntry(t *testing.T, tw *tar.Writer, name string, data []byte) {
+	err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), Typeflag: tar.TypeReg})
+	assert.NilError(t, err)
+	_, err = tw.Write(data)
+	assert.NilError(t, err)
+}
+
+func TestGetDockerRepoTagFromImageTarStreams(t *testing.T) {
+	tmpDir := fs.NewDir(t, t.Name())
+	defer tmpDir.Remove()
+
+	manifest := `[{"Config":"76a4dd2d5d6a18323ac8d90f959c3c8562bf592e2a559bab9b462ab600e9e5fc.json","RepoTags":["hello:latest"],"Layers":["layer.tar"]}]`
+	// the layer blob before the manifest only streams through the
+	// probe, its size never lands in the memory or below the tmp dir
+	layer := make([]byte, 32*1024*1024)
+
+	plainPath := tmpDir.Join("plain.tar")
+	fi, err := os.Create(plainPath)
+	assert.NilError(t, err)
+	tw := tar.NewWriter(fi)
+	writeProbeTarEntry(t, tw, "layer.tar", layer)
+	writeProbeTarEntry(t, tw, "manifest.json", []byte(manifest))
+	assert.NilError(t, tw.Close())
+	assert.NilError(t, fi.Close())
+
+	gzPath := tmpDir.Join("compressed.tar.gz")
+	fi, err = os.Create(gzPath)
+	assert.NilError(t, err)
+	gw := gzip.NewWriter(fi)
+	tw = tar.NewWriter(gw)
+	writeProbeTarEntry(t, tw, "layer.tar", layer)
+	writeProbeTarEntry(t, tw, "manifest.json", []byte(manifest))
+	assert.NilError(t, tw.Close())
+	assert.NilError(t, gw.Close())
+	assert.NilError(t, fi.Close())
+
+	for _, path := range []string{plainPath, gzPath} {
+		imagesInTar, err := getDockerRepoTagFromImageTar(path)
+		assert.NilError(t, err)
+		assert.Equal(t, len(imagesInTar), 1)
+		assert.Equal(t, imagesInTar[0].id, "@76a4dd2d5d6a18323ac8d90f959c3c8562bf592e2a559bab9b462ab600e9e5fc")
+		assert.Equal(t, imagesInTar[0].nameTag[0], "hello:latest")
+	}
+
+	_, err = getDockerRepoTagFromImageTar(tmpDir.Join("not-exist.tar"))
+	assert.ErrorContains(t, err, "no such file or directory")
+}
+
+func TestGetOCIRepoTagFromImageTarStreams(t *testing.T) {
+	tmpDir := fs.NewDir(t, t.Name())
+	defer tmpDir.Remove()
+
+	config := []byte(`{"architecture":"amd64","os":"linux"}`)
+	configDigest := digest.FromBytes(config)
+	manifest := []byte(`{"schemaVersion":2,"config":{"digest":"` + configDigest.String() + `","size":` +
+		fmt.Sprintf("%d", len(config)) + `}}`)
+	manifestDigest := digest.FromBytes(manifest)
+	index := []byte(`{"schemaVersion":2,"manifests":[{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"` +
+		manifestDigest.String() + `","size":` + fmt.Sprintf("%d", len(manifest)) +
+		`,"annotations":{"org.opencontainers.image.ref.name":"hello:latest"}}]}`)
+
+	path := tmpDir.Join("oci.tar")
+	fi, err := os.Create(path)
+	assert.NilError(t, err)
+	tw := tar.NewWriter(fi)
+	// a binary layer blob streams by without being kept
+	writeProbeTarEntry(t, tw, "blobs/sha256/"+digest.FromString("layer").Encoded(), make([]byte, 8*1024*1024))
+	writeProbeTarEntry(t, tw, "blobs/sha256/"+configDigest.Encoded(), config)
+	writeProbeTarEntry(t, tw, "blobs/sha256/"+manifestDigest.Encoded(), manifest)
+	writeProbeTarEntry(t, tw, "oci-layout", []byte(`{"imageLayoutVersion":"1.0.0"}`))
+	writeProbeTarEntry(t, tw, "index.json", index)
+	assert.NilError(t, tw.Close())
+	assert.NilError(t, fi.Close())
+
+	imagesInTar, err := getOCIRepoTagFromImageTar(path)
+	assert.NilError(t, err)
+	assert.Equal(t, len(imagesInTar), 1)
+	assert.Equal(t, imagesInTar[0].id, "@"+configDigest.Encoded())
+	assert.Equal(t, imagesInTar[0].nameTag[0], "hello:latest")
+}
+
+// The rollback below covers load batches only. A multi-tag build going
+// wrong is undone by the builder itself through CleanResources in the
+// build path, it never reaches the load bookkeeping, so it is out of
+// scope here and exercised by the builder tests.
+
+func TestRollbackLoadedImages(t *testing.T) {
+	d := prepare(t)
+	defer tmpClean(d)
+
+	options := &storage.ImageOptions{}
+	existed, err := d.Daemon.localStore.CreateImage(stringid.GenerateRandomID(), []string{"keep:latest"}, "", "", options)
+	assert.NilError(t, err)
+	// the failed batch tagged the pre-existing image with a new name
+	assert.NilError(t, d.Daemon.localStore.SetNames(existed.ID, append(existed.Names, "hello:v2")))
+	fresh, err := d.Daemon.localStore.CreateImage(stringid.GenerateRandomID(), []string{"hello:v3"}, "", "", options)
+	assert.NilError(t, err)
+
+	loaded := []loadedImage{
+		{id: existed.ID, existedBefore: true, originalNames: []string{"keep:latest"}},
+		{id: fresh.ID, existedBefore: false},
+	}
+	entry := logrus.WithField("LoadID", t.Name())
+	d.Daemon.backend.rollbackLoadedImages(loaded, entry)
+
+	// the new image is gone, the pre-existing one got its names back
+	_, err = d.Daemon.localStore.Image(fresh.ID)
+	assert.ErrorContains(t, err, "not known")
+	names, err := d.Daemon.localStore.Names(existed.ID)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, names, []string{"keep:latest"})
+}
+
+func TestCleanupLoadFailureKeepPartial(t *testing.T) {
+	d := prepare(t)
+	defer tmpClean(d)
+
+	options := &storage.ImageOptions{}
+	fresh, err := d.Daemon.localStore.CreateImage(stringid.GenerateRandomID(), []string{"hello:kept"}, "", "", options)
+	assert.NilError(t, err)
+	loaded := []loadedImage{{id: fresh.ID, existedBefore: false}}
+	entry := logrus.WithField("LoadID", t.Name())
+	tagErr := errors.New("tag failed")
+
+	// --keep-partial leaves the already loaded image in place
+	d.Daemon.backend.cleanupLoadFailure(true, loaded, map[string]bool{}, entry, tagErr)
+	_, err = d.Daemon.localStore.Image(fresh.ID)
+	assert.NilError(t, err)
+
+	// without it the mid-batch failure rolls the image back
+	d.Daemon.backend.cleanupLoadFailure(false, loaded, map[string]bool{}, entry, tagErr)
+	_, err = d.Daemon.localStore.Image(fresh.ID)
+	assert.ErrorContains(t, err, "not known")
+}
+
+func TestCleanupPartialLayers(t *testing.T) {
+	d := prepare(t)
+	defer tmpClean(d)
+
+	snapshot, err := d.Daemon.backend.snapshotLayerIDs()
+	assert.NilError(t, err)
+
+	// a parent-child chain left by an interrupted load and one layer
+	// still referenced by an image
+	parent, err := d.Daemon.localStore.CreateLayer(stringid.GenerateRandomID(), "", nil, "", false, nil)
+	assert.NilError(t, err)
+	_, err = d.Daemon.localStore.CreateLayer(stringid.GenerateRandomID(), parent.ID, nil, "", false, nil)
+	assert.NilError(t, err)
+	kept, err := d.Daemon.localStore.CreateLayer(stringid.GenerateRandomID(), "", nil, "", false, nil)
+	assert.NilError(t, err)
+	_, err = d.Daemon.localStore.CreateImage(stringid.GenerateRandomID(), []string{"image:kept"}, kept.ID, "", &storage.ImageOptions{})
+	assert.NilError(t, err)
+
+	entry := logrus.WithField("LoadID", t.Name())
+	d.Daemon.backend.cleanupPartialLayers(snapshot, entry)
+
+	layers, err := d.Daemon.localStore.Layers()
+	assert.NilError(t, err)
+	assert.Equal(t, len(layers), 1)
+	assert.Equal(t, layers[0].ID, kept.ID)
+}
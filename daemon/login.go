@@ -16,6 +16,11 @@ package daemon
 import (
 	"context"
 	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/containers/image/v5/docker"
 	"github.com/containers/image/v5/pkg/docker/config"
@@ -126,3 +131,54 @@ func validLoginOpts(req *pb.LoginRequest) error {
 
 	return nil
 }
+
+// LoginList lists the registries with stored credentials
+func (b *Backend) LoginList(ctx context.Context, req *pb.LoginListRequest) (*pb.LoginListResponse, error) {
+	logrus.Info("LoginListRequest received")
+
+	sysCtx := image.GetSystemContext()
+	creds, err := config.GetAllCredentials(sysCtx)
+	if err != nil {
+		return nil, errors.Wrap(err, "read stored credentials failed")
+	}
+
+	authFile := sysCtx.AuthFilePath
+	if authFile == "" {
+		authFile = constant.AuthFilePath
+	}
+
+	resp := &pb.LoginListResponse{}
+	for registry, cred := range creds {
+		resp.Logins = append(resp.Logins, &pb.LoginListResponse_LoginInfo{
+			Registry:    registry,
+			Username:    cred.Username,
+			AuthFile:    authFile,
+			TokenExpiry: tokenExpiry(cred.IdentityToken),
+		})
+	}
+	sort.Slice(resp.Logins, func(i, j int) bool { return resp.Logins[i].Registry < resp.Logins[j].Registry })
+
+	return resp, nil
+}
+
+// tokenExpiry extracts the expiry of a JWT identity token, empty when
+// the credential has no token or the token carries no detectable expiry
+func tokenExpiry(token string) string {
+	const jwtParts = 3
+	parts := strings.Split(token, ".")
+	if len(parts) != jwtParts {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return ""
+	}
+
+	return time.Unix(claims.Exp, 0).UTC().Format(time.RFC3339)
+}
@@ -41,11 +41,6 @@ func (b *Backend) ManifestCreate(ctx context.Context, req *pb.ManifestCreateRequ
 		"Manifest":     req.GetManifests(),
 	}).Info("ManifestCreateRequest received")
 
-	if !b.daemon.opts.Experimental {
-		logrus.WithField(util.LogKeySessionID, req.GetManifestList()).Error("Please enable experimental to use manifest feature")
-		return &pb.ManifestCreateResponse{}, errors.New("please enable experimental to use manifest feature")
-	}
-
 	manifestName := req.GetManifestList()
 	manifests := req.GetManifests()
 
@@ -85,11 +80,6 @@ func (b *Backend) ManifestAnnotate(ctx context.Context, req *pb.ManifestAnnotate
 
 	var emptyResp = &gogotypes.Empty{}
 
-	if !b.daemon.opts.Experimental {
-		logrus.WithField(util.LogKeySessionID, req.GetManifestList()).Error("Please enable experimental to use manifest feature")
-		return emptyResp, errors.New("please enable experimental to use manifest feature")
-	}
-
 	manifestName := req.GetManifestList()
 	manifestImage := req.GetManifest()
 
@@ -132,11 +122,6 @@ func (b *Backend) ManifestInspect(ctx context.Context, req *pb.ManifestInspectRe
 		"ManifestList": req.GetManifestList(),
 	}).Info("ManifestInspectRequest received")
 
-	if !b.daemon.opts.Experimental {
-		logrus.WithField(util.LogKeySessionID, req.GetManifestList()).Error("Please enable experimental to use manifest feature")
-		return &pb.ManifestInspectResponse{}, errors.New("please enable experimental to use manifest feature")
-	}
-
 	manifestName := req.GetManifestList()
 
 	// get list image
@@ -193,11 +178,6 @@ func (b *Backend) ManifestPush(req *pb.ManifestPushRequest, stream pb.Control_Ma
 		"Destination":  req.GetDest(),
 	}).Info("ManifestPushRequest received")
 
-	if !b.daemon.opts.Experimental {
-		logrus.WithField(util.LogKeySessionID, req.GetManifestList()).Error("Please enable experimental to use manifest feature")
-		return errors.New("please enable experimental to use manifest feature")
-	}
-
 	manifestName := req.GetManifestList()
 	cliLogger := logger.NewCliLogger(constant.CliLogBufferLen)
 	opt := manifestPushOptions{
@@ -0,0 +1,216 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is "meta" command for backend
+
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	gogotypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/image"
+	"isula.org/isula-build/store"
+)
+
+const (
+	// metadataBigDataKey is the image big-data item holding the user
+	// metadata of an image as one JSON object
+	metadataBigDataKey = "isula-build.metadata"
+	// maxMetaEntries bounds how many metadata keys one image may carry
+	maxMetaEntries = 100
+	// maxMetaKeyLen bounds the length of one metadata key
+	maxMetaKeyLen = 128
+	// maxMetaValueLen bounds the length of one metadata value
+	maxMetaValueLen = 4096
+	// metaFilterPrefix marks a list filter matching the user metadata
+	metaFilterPrefix = "meta."
+)
+
+// MetaSet attaches user metadata key/values to a local image, an entry
+// with an empty value removes the key
+func (b *Backend) MetaSet(ctx context.Context, req *pb.MetaSetRequest) (*gogotypes.Empty, error) {
+	logrus.WithFields(logrus.Fields{
+		"Image": req.GetImage(),
+	}).Info("MetaSetRequest received")
+
+	var emptyResp = &gogotypes.Empty{}
+	s := b.daemon.localStore
+
+	_, img, err := image.FindImage(s, req.Image)
+	if err != nil {
+		return emptyResp, errors.Wrapf(err, "find local image %q failed", req.Image)
+	}
+
+	metadata, err := readImageMetadata(s, img.ID)
+	if err != nil {
+		return emptyResp, err
+	}
+	for _, kv := range req.GetMetadata() {
+		if vErr := checkMetaKey(kv.GetKey()); vErr != nil {
+			return emptyResp, vErr
+		}
+		if kv.GetValue() == "" {
+			delete(metadata, kv.GetKey())
+			continue
+		}
+		if len(kv.GetValue()) > maxMetaValueLen {
+			return emptyResp, errors.Errorf("metadata value of key %q is longer than %d characters", kv.GetKey(), maxMetaValueLen)
+		}
+		metadata[kv.GetKey()] = kv.GetValue()
+	}
+	if len(metadata) > maxMetaEntries {
+		return emptyResp, errors.Errorf("image %q would carry %d metadata keys, only %d are allowed", req.Image, len(metadata), maxMetaEntries)
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return emptyResp, errors.Wrap(err, "marshal image metadata failed")
+	}
+	if err := s.SetImageBigData(img.ID, metadataBigDataKey, data, nil); err != nil {
+		return emptyResp, errors.Wrapf(err, "store metadata of image %q failed", req.Image)
+	}
+
+	return emptyResp, nil
+}
+
+// MetaGet returns the user metadata of a local image ordered by key
+func (b *Backend) MetaGet(ctx context.Context, req *pb.MetaGetRequest) (*pb.MetaGetResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"Image": req.GetImage(),
+	}).Info("MetaGetRequest received")
+
+	s := b.daemon.localStore
+	_, img, err := image.FindImage(s, req.Image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "find local image %q failed", req.Image)
+	}
+
+	metadata, err := readImageMetadata(s, img.ID)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	resp := &pb.MetaGetResponse{Metadata: make([]*pb.MetaKV, 0, len(keys))}
+	for _, key := range keys {
+		resp.Metadata = append(resp.Metadata, &pb.MetaKV{Key: key, Value: metadata[key]})
+	}
+
+	return resp, nil
+}
+
+// readImageMetadata loads the user metadata of an image, an image
+// without the big-data item has none yet
+func readImageMetadata(s *store.Store, imageID string) (map[string]string, error) {
+	keys, err := s.ListImageBigData(imageID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "list big data of image %q failed", imageID)
+	}
+	found := false
+	for _, key := range keys {
+		if key == metadataBigDataKey {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return make(map[string]string), nil
+	}
+
+	data, err := s.ImageBigData(imageID, metadataBigDataKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read metadata of image %q failed", imageID)
+	}
+	metadata := make(map[string]string)
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, errors.Wrapf(err, "parse metadata of image %q failed", imageID)
+	}
+
+	return metadata, nil
+}
+
+// checkMetaKey rejects metadata keys the filter syntax could not name
+func checkMetaKey(key string) error {
+	if key == "" {
+		return errors.New("metadata key must not be empty")
+	}
+	if len(key) > maxMetaKeyLen {
+		return errors.Errorf("metadata key %q is longer than %d characters", key, maxMetaKeyLen)
+	}
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.' || r == '_' || r == '-':
+		default:
+			return errors.Errorf("invalid metadata key %q, only letters, digits, '.', '_' and '-' are allowed", key)
+		}
+	}
+
+	return nil
+}
+
+// parseMetaFilters turns the filter entries of a list request into the
+// metadata key/values a listed image must carry
+func parseMetaFilters(filters []string) (map[string]string, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	wanted := make(map[string]string, len(filters))
+	for _, filter := range filters {
+		if !strings.HasPrefix(filter, metaFilterPrefix) {
+			return nil, errors.Errorf("invalid filter %q, expected meta.<key>=<value>", filter)
+		}
+		kv := strings.SplitN(strings.TrimPrefix(filter, metaFilterPrefix), "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, errors.Errorf("invalid filter %q, expected meta.<key>=<value>", filter)
+		}
+		if err := checkMetaKey(kv[0]); err != nil {
+			return nil, err
+		}
+		wanted[kv[0]] = kv[1]
+	}
+
+	return wanted, nil
+}
+
+// matchMetaFilters reports whether the metadata of an image carries
+// every key/value a filter asks for, no filters match every image
+func matchMetaFilters(s *store.Store, imageID string, wanted map[string]string) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+
+	metadata, err := readImageMetadata(s, imageID)
+	if err != nil {
+		logrus.Warnf("Read metadata of image %q for filtering failed: %v", imageID, err)
+		return false
+	}
+	for key, value := range wanted {
+		if metadata[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
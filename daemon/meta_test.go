@@ -0,0 +1,134 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is "meta" command for backend tests
+
+package daemon
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckMetaKey(t *testing.T) {
+	var testcases = []struct {
+		name      string
+		key       string
+		wantErr   bool
+		errString string
+	}{
+		{
+			name: "TC1 - normal key",
+			key:  "team",
+		},
+		{
+			name: "TC2 - key with allowed punctuation",
+			key:  "com.example_build-stage.1",
+		},
+		{
+			name:      "TC3 - empty key",
+			key:       "",
+			wantErr:   true,
+			errString: "must not be empty",
+		},
+		{
+			name:      "TC4 - key with equals sign",
+			key:       "team=payments",
+			wantErr:   true,
+			errString: "invalid metadata key",
+		},
+		{
+			name:      "TC5 - key with space",
+			key:       "team name",
+			wantErr:   true,
+			errString: "invalid metadata key",
+		},
+		{
+			name:      "TC6 - overlong key",
+			key:       strings.Repeat("k", maxMetaKeyLen+1),
+			wantErr:   true,
+			errString: "longer than",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkMetaKey(tc.key)
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestParseMetaFilters(t *testing.T) {
+	var testcases = []struct {
+		name      string
+		filters   []string
+		want      map[string]string
+		wantErr   bool
+		errString string
+	}{
+		{
+			name:    "TC1 - no filters",
+			filters: nil,
+			want:    nil,
+		},
+		{
+			name:    "TC2 - single filter",
+			filters: []string{"meta.team=payments"},
+			want:    map[string]string{"team": "payments"},
+		},
+		{
+			name:    "TC3 - multiple filters",
+			filters: []string{"meta.team=payments", "meta.owner=alice"},
+			want:    map[string]string{"team": "payments", "owner": "alice"},
+		},
+		{
+			name:      "TC4 - missing meta prefix",
+			filters:   []string{"team=payments"},
+			wantErr:   true,
+			errString: "expected meta.<key>=<value>",
+		},
+		{
+			name:      "TC5 - missing value",
+			filters:   []string{"meta.team"},
+			wantErr:   true,
+			errString: "expected meta.<key>=<value>",
+		},
+		{
+			name:      "TC6 - empty value",
+			filters:   []string{"meta.team="},
+			wantErr:   true,
+			errString: "expected meta.<key>=<value>",
+		},
+		{
+			name:      "TC7 - invalid key",
+			filters:   []string{"meta.te am=payments"},
+			wantErr:   true,
+			errString: "invalid metadata key",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseMetaFilters(tc.filters)
+			if tc.wantErr {
+				assert.ErrorContains(t, err, tc.errString)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tc.want)
+		})
+	}
+}
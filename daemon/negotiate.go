@@ -0,0 +1,71 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: This file is "negotiate" handshake for backend
+
+package daemon
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/pkg/version"
+)
+
+// apiCapabilities are the optional API features this daemon supports,
+// clients probe them during the handshake to degrade gracefully when
+// talking to an older daemon
+var apiCapabilities = []string{
+	"build.resume",
+	"build.builder",
+	"build.session-log-level",
+	"cache.manage",
+	"builder.manage",
+}
+
+// Negotiate exchanges API version and capabilities with the client so
+// mismatched versions fail with an actionable error instead of cryptic
+// unmarshal failures later on
+func (b *Backend) Negotiate(ctx context.Context, req *pb.NegotiateRequest) (*pb.NegotiateResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"Version":    req.GetVersion(),
+		"APIVersion": req.GetApiVersion(),
+	}).Info("NegotiateRequest received")
+
+	if err := checkAPIVersionCompat(req.GetApiVersion()); err != nil {
+		return &pb.NegotiateResponse{}, err
+	}
+
+	return &pb.NegotiateResponse{
+		Version:      version.Version,
+		ApiVersion:   version.APIVersion,
+		Capabilities: apiCapabilities,
+	}, nil
+}
+
+// checkAPIVersionCompat checks whether the given client API version can
+// work with this daemon, only the major part has to match
+func checkAPIVersionCompat(clientAPIVersion string) error {
+	if clientAPIVersion == "" {
+		return errors.New("client did not send an API version, please upgrade the isula-build client")
+	}
+	clientMajor := strings.SplitN(clientAPIVersion, ".", 2)[0]
+	daemonMajor := strings.SplitN(version.APIVersion, ".", 2)[0]
+	if clientMajor != daemonMajor {
+		return errors.Errorf("incompatible API version: client speaks %s, daemon speaks %s, "+
+			"please use a matching isula-build client and isula-builder", clientAPIVersion, version.APIVersion)
+	}
+	return nil
+}
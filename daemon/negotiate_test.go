@@ -0,0 +1,54 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: This file tests Negotiate interface
+
+package daemon
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/pkg/version"
+)
+
+func TestNegotiate(t *testing.T) {
+	d := prepare(t)
+	defer tmpClean(d)
+
+	ctx := context.Background()
+	backend := d.Daemon.backend
+
+	// a client speaking the same API version gets the capability set back
+	resp, err := backend.Negotiate(ctx, &pb.NegotiateRequest{ApiVersion: version.APIVersion})
+	assert.NilError(t, err)
+	assert.Equal(t, resp.ApiVersion, version.APIVersion)
+	assert.Assert(t, len(resp.Capabilities) > 0)
+
+	// a client which does not send its API version is told to upgrade
+	_, err = backend.Negotiate(ctx, &pb.NegotiateRequest{})
+	assert.ErrorContains(t, err, "please upgrade")
+
+	// a client with a different major API version is refused
+	_, err = backend.Negotiate(ctx, &pb.NegotiateRequest{ApiVersion: "999.0"})
+	assert.ErrorContains(t, err, "incompatible API version")
+}
+
+func TestCheckAPIVersionCompat(t *testing.T) {
+	assert.NilError(t, checkAPIVersionCompat(version.APIVersion))
+	// a different minor version of the same major still works
+	assert.NilError(t, checkAPIVersionCompat("1.999"))
+	assert.ErrorContains(t, checkAPIVersionCompat("999.0"), "incompatible")
+	assert.ErrorContains(t, checkAPIVersionCompat(""), "upgrade")
+}
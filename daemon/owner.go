@@ -0,0 +1,239 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for image ownership and team quota accounting
+
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"os/user"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/peer"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/store"
+	"isula.org/isula-build/util"
+)
+
+const (
+	// ownerBigDataKey is the image big-data item recording which user
+	// and team an image was built by
+	ownerBigDataKey = "isula-build.owner"
+	// unownedEntry names the df report row collecting images without a
+	// recorded owner
+	unownedEntry = "<none>"
+)
+
+// imageOwner records the auth identity an image was built under
+type imageOwner struct {
+	// User is the username of the builder, the numeric UID when the
+	// user is not known to the host
+	User string `json:"user"`
+	// Team is the configured team the user belongs to, empty when the
+	// user is in no team
+	Team string `json:"team,omitempty"`
+}
+
+// callerOwner resolves the owner identity of the calling process from
+// its unix socket credentials, an unknown caller owns nothing
+func (d *Daemon) callerOwner(ctx context.Context) imageOwner {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return imageOwner{}
+	}
+	info, ok := p.AuthInfo.(peerCredAuthInfo)
+	if !ok || info.ucred == nil {
+		return imageOwner{}
+	}
+
+	uid := strconv.FormatUint(uint64(info.ucred.Uid), 10)
+	name := uid
+	if u, lErr := user.LookupId(uid); lErr == nil {
+		name = u.Username
+	}
+
+	return imageOwner{User: name, Team: d.teamOf(name, uid)}
+}
+
+// teamOf returns the configured team one of the names belongs to, the
+// first match in team name order wins when a user is listed twice
+func (d *Daemon) teamOf(names ...string) string {
+	teams := make([]string, 0, len(d.opts.Teams))
+	for team := range d.opts.Teams {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+
+	for _, team := range teams {
+		for _, member := range d.opts.Teams[team].Members {
+			for _, name := range names {
+				if member == name {
+					return team
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// recordImageOwner persists the owner of a freshly built image, images
+// of unknown callers stay unowned
+func (d *Daemon) recordImageOwner(imageID string, owner imageOwner) error {
+	if owner.User == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(owner)
+	if err != nil {
+		return errors.Wrap(err, "marshal image owner failed")
+	}
+	if err := d.localStore.SetImageBigData(imageID, ownerBigDataKey, data, nil); err != nil {
+		return errors.Wrapf(err, "store owner of image %q failed", imageID)
+	}
+
+	return nil
+}
+
+// readImageOwner loads the recorded owner of an image, the second
+// return reports whether the image has one
+func readImageOwner(s *store.Store, imageID string) (imageOwner, bool) {
+	keys, err := s.ListImageBigData(imageID)
+	if err != nil {
+		return imageOwner{}, false
+	}
+	found := false
+	for _, key := range keys {
+		if key == ownerBigDataKey {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return imageOwner{}, false
+	}
+
+	data, err := s.ImageBigData(imageID, ownerBigDataKey)
+	if err != nil {
+		return imageOwner{}, false
+	}
+	var owner imageOwner
+	if err := json.Unmarshal(data, &owner); err != nil {
+		logrus.Warnf("Parse owner of image %q failed: %v", imageID, err)
+		return imageOwner{}, false
+	}
+
+	return owner, owner.User != ""
+}
+
+// checkTeamQuota rejects new builds of a team already at its storage
+// quota, callers outside every team and unlimited teams build freely
+func (d *Daemon) checkTeamQuota(owner imageOwner) error {
+	if owner.Team == "" {
+		return nil
+	}
+	quota := d.opts.Teams[owner.Team].Quota
+	if quota <= 0 {
+		return nil
+	}
+
+	usage := d.teamUsage(owner.Team)
+	if usage >= quota {
+		return errors.Errorf("team %q has used %s of its %s storage quota, remove images or raise the quota before building",
+			owner.Team, util.FormatSize(float64(usage), decimalPrefixBase), util.FormatSize(float64(quota), decimalPrefixBase))
+	}
+
+	return nil
+}
+
+// teamUsage sums the virtual size of the images a team owns
+func (d *Daemon) teamUsage(team string) int64 {
+	images, err := d.localStore.Images()
+	if err != nil {
+		logrus.Warnf("List images for quota accounting failed: %v", err)
+		return 0
+	}
+
+	var usage int64
+	for i := range images {
+		owner, ok := readImageOwner(d.localStore, images[i].ID)
+		if !ok || owner.Team != team {
+			continue
+		}
+		if size, sErr := d.localStore.ImageSize(images[i].ID); sErr == nil && size > 0 {
+			usage += size
+		}
+	}
+
+	return usage
+}
+
+// Df reports the image storage usage grouped by team, or by the owning
+// user when the request asks for it
+func (b *Backend) Df(ctx context.Context, req *pb.DfRequest) (*pb.DfResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"ByOwner": req.GetByOwner(),
+	}).Info("DfRequest received")
+
+	s := b.daemon.localStore
+	images, err := s.Images()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed list images from local storage")
+	}
+
+	type usage struct {
+		images int64
+		size   int64
+	}
+	groups := make(map[string]*usage)
+	for i := range images {
+		owner, ok := readImageOwner(s, images[i].ID)
+		name := unownedEntry
+		switch {
+		case req.GetByOwner() && ok:
+			name = owner.User
+		case !req.GetByOwner() && ok && owner.Team != "":
+			name = owner.Team
+		}
+		group := groups[name]
+		if group == nil {
+			group = &usage{}
+			groups[name] = group
+		}
+		group.images++
+		if size, sErr := s.ImageSize(images[i].ID); sErr == nil && size > 0 {
+			group.size += size
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resp := &pb.DfResponse{Entries: make([]*pb.DfEntry, 0, len(names))}
+	for _, name := range names {
+		entry := &pb.DfEntry{Owner: name, Images: groups[name].images, Size_: groups[name].size}
+		if !req.GetByOwner() {
+			entry.Quota = b.daemon.opts.Teams[name].Quota
+		}
+		resp.Entries = append(resp.Entries, entry)
+	}
+
+	return resp, nil
+}
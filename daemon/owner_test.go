@@ -0,0 +1,56 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for image ownership and quota tests
+
+package daemon
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTeamOf(t *testing.T) {
+	d := &Daemon{opts: &Options{Teams: map[string]Team{
+		"payments": {Members: []string{"alice", "1001"}},
+		"platform": {Members: []string{"bob"}},
+	}}}
+
+	assert.Equal(t, d.teamOf("alice"), "payments")
+	assert.Equal(t, d.teamOf("bob"), "platform")
+	// a caller unknown to the host still matches by UID
+	assert.Equal(t, d.teamOf("1001", "1001"), "payments")
+	assert.Equal(t, d.teamOf("mallory"), "")
+
+	// no configuration puts nobody in a team
+	d = &Daemon{opts: &Options{}}
+	assert.Equal(t, d.teamOf("alice"), "")
+}
+
+func TestCheckTeamQuota(t *testing.T) {
+	d := &Daemon{opts: &Options{Teams: map[string]Team{
+		"payments": {Members: []string{"alice"}},
+	}}}
+
+	// a caller outside every team builds freely
+	assert.NilError(t, d.checkTeamQuota(imageOwner{User: "mallory"}))
+	// a team without a quota builds freely
+	assert.NilError(t, d.checkTeamQuota(imageOwner{User: "alice", Team: "payments"}))
+}
+
+func TestCallerOwnerWithoutPeer(t *testing.T) {
+	d := &Daemon{opts: &Options{}}
+	owner := d.callerOwner(context.Background())
+	assert.Equal(t, owner.User, "")
+	assert.Equal(t, owner.Team, "")
+}
@@ -0,0 +1,135 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is "promote" command for backend
+
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/exporter"
+	"isula.org/isula-build/image"
+	"isula.org/isula-build/pkg/logger"
+	"isula.org/isula-build/util"
+)
+
+// promoteRecord is the persisted audit entry of one promotion
+type promoteRecord struct {
+	PromoteID string    `json:"promoteID"`
+	Image     string    `json:"image"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Source    string    `json:"source"`
+	Target    string    `json:"target"`
+	ImageID   string    `json:"imageID"`
+	Time      time.Time `json:"time"`
+}
+
+// environmentRef resolves an image name to its reference in one
+// logical environment
+func environmentRef(env Environment, imageName string) string {
+	ref := env.Registry
+	if env.Prefix != "" {
+		ref += "/" + env.Prefix
+	}
+
+	return ref + "/" + imageName
+}
+
+// Promote retags the image of one logical environment for another one,
+// pushes the new reference there and records the promotion
+func (b *Backend) Promote(ctx context.Context, req *pb.PromoteRequest) (*pb.PromoteResponse, error) {
+	logEntry := logrus.WithFields(logrus.Fields{
+		"PromoteID": req.GetPromoteID(),
+		"Image":     req.GetImage(),
+		"From":      req.GetFrom(),
+		"To":        req.GetTo(),
+	})
+	logEntry.Info("PromoteRequest received")
+
+	envs := b.daemon.opts.Environments
+	fromEnv, ok := envs[req.GetFrom()]
+	if !ok {
+		return &pb.PromoteResponse{}, errors.Errorf("environment %q is not configured in the daemon", req.GetFrom())
+	}
+	toEnv, ok := envs[req.GetTo()]
+	if !ok {
+		return &pb.PromoteResponse{}, errors.Errorf("environment %q is not configured in the daemon", req.GetTo())
+	}
+	source := environmentRef(fromEnv, req.GetImage())
+	target := environmentRef(toEnv, req.GetImage())
+
+	s := b.daemon.localStore
+	_, img, err := image.FindImage(s, source)
+	if err != nil {
+		return &pb.PromoteResponse{}, errors.Wrapf(err, "find local image %q failed", source)
+	}
+	_, targetName, err := image.GetNamedTaggedReference(target)
+	if err != nil {
+		return &pb.PromoteResponse{}, err
+	}
+	if err := s.SetNames(img.ID, append(img.Names, targetName)); err != nil {
+		return &pb.PromoteResponse{}, errors.Wrapf(err, "set name %q to image %q failed", target, source)
+	}
+
+	cliLogger := logger.NewCliLogger(constant.CliLogBufferLen)
+	opt := pushOptions{
+		sysCtx:     image.GetSystemContext(),
+		logger:     cliLogger,
+		localStore: s,
+		pushID:     req.GetPromoteID(),
+		imageName:  target,
+		format:     constant.DockerTransport,
+	}
+	manifestType, err := exporter.GetManifestType(opt.format)
+	if err != nil {
+		return &pb.PromoteResponse{}, err
+	}
+	opt.manifestType = manifestType
+
+	ctx = context.WithValue(ctx, util.LogFieldKey(util.LogKeySessionID), req.GetPromoteID())
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(pushHandler(egCtx, opt))
+	eg.Go(func() error {
+		for content := range cliLogger.GetContent() {
+			if content == "" {
+				return nil
+			}
+			logEntry.Info(content)
+		}
+		return nil
+	})
+	if err := eg.Wait(); err != nil {
+		return &pb.PromoteResponse{}, errors.Wrapf(err, "push promoted image %q failed", target)
+	}
+
+	b.writeRecordFile("promote-"+req.GetPromoteID()+".json", promoteRecord{
+		PromoteID: req.GetPromoteID(),
+		Image:     req.GetImage(),
+		From:      req.GetFrom(),
+		To:        req.GetTo(),
+		Source:    source,
+		Target:    target,
+		ImageID:   img.ID,
+		Time:      time.Now(),
+	})
+	logEntry.Infof("Promoted %q to %q", source, target)
+
+	return &pb.PromoteResponse{Source: source, Target: target}, nil
+}
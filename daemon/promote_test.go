@@ -0,0 +1,28 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: promote backend test
+
+package daemon
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestEnvironmentRef(t *testing.T) {
+	env := Environment{Registry: "registry.example.com", Prefix: "dev"}
+	assert.Equal(t, environmentRef(env, "app:v1"), "registry.example.com/dev/app:v1")
+
+	env = Environment{Registry: "registry.example.com:5000"}
+	assert.Equal(t, environmentRef(env, "app:v1"), "registry.example.com:5000/app:v1")
+}
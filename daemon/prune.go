@@ -0,0 +1,142 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-05-10
+// Description: This file is "prune" command for backend
+
+package daemon
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/util"
+)
+
+// Prune clears the partial data accumulated by interrupted sessions and
+// the cache directories of RUN --mount cache mounts, the directories of
+// the sessions still running are left alone
+func (b *Backend) Prune(ctx context.Context, req *pb.PruneRequest) (*pb.PruneResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"Partial":     req.GetPartial(),
+		"CacheMounts": req.GetCacheMounts(),
+	}).Info("PruneRequest received")
+
+	if !req.GetPartial() && !req.GetCacheMounts() {
+		return &pb.PruneResponse{}, errors.New("nothing to prune, use the --partial or --cache-mounts flag")
+	}
+
+	resp := &pb.PruneResponse{}
+	if req.GetPartial() {
+		active := make(map[string]bool)
+		for _, build := range b.daemon.reg().list() {
+			active[build.buildID] = true
+		}
+
+		tmpDir, err := securejoin.SecureJoin(b.daemon.opts.DataRoot, constant.DataRootTmpDirPrefix)
+		if err != nil {
+			return &pb.PruneResponse{}, err
+		}
+		if err := prunePartialDir(tmpDir, active, false, resp); err != nil {
+			return &pb.PruneResponse{}, err
+		}
+		if err := prunePartialDir(b.daemon.opts.RunRoot, active, true, resp); err != nil {
+			return &pb.PruneResponse{}, err
+		}
+	}
+
+	if req.GetCacheMounts() {
+		mountsDir, err := securejoin.SecureJoin(b.daemon.opts.DataRoot,
+			filepath.Join(buildCacheDirName, util.CacheMountsDir))
+		if err != nil {
+			return &pb.PruneResponse{}, err
+		}
+		if err := pruneCacheMountsDir(mountsDir, resp); err != nil {
+			return &pb.PruneResponse{}, err
+		}
+	}
+
+	return resp, nil
+}
+
+// pruneCacheMountsDir removes all the cache mount directories under
+// root and accounts them into resp
+func pruneCacheMountsDir(root string, resp *pb.PruneResponse) error {
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "read dir %q for pruning failed", root)
+	}
+
+	for _, entry := range entries {
+		stale := filepath.Join(root, entry.Name())
+		size, sErr := util.GetDirSize(stale)
+		if sErr != nil {
+			logrus.Warnf("Sizing cache mount dir %q failed: %v", stale, sErr)
+			size = 0
+		}
+		if rErr := util.ForceRemoveAll(stale); rErr != nil {
+			logrus.Warnf("Removing cache mount dir %q failed: %v", stale, rErr)
+			continue
+		}
+		logrus.Infof("Removed cache mount dir %q", stale)
+		resp.Removed = append(resp.Removed, stale)
+		resp.ReclaimedBytes += size
+	}
+
+	return nil
+}
+
+// prunePartialDir removes the inactive session entries under root and
+// accounts them into resp. For the run root only the per-session sub
+// directories are pruned, the storage dir and stack dumps are kept
+func prunePartialDir(root string, active map[string]bool, dirOnly bool, resp *pb.PruneResponse) error {
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "read dir %q for pruning failed", root)
+	}
+
+	for _, entry := range entries {
+		if active[entry.Name()] {
+			continue
+		}
+		if dirOnly && (!entry.IsDir() || entry.Name() == "storage") {
+			continue
+		}
+		stale := filepath.Join(root, entry.Name())
+		size, sErr := util.GetDirSize(stale)
+		if sErr != nil {
+			logrus.Warnf("Sizing partial data dir %q failed: %v", stale, sErr)
+			size = 0
+		}
+		if rErr := util.ForceRemoveAll(stale); rErr != nil {
+			logrus.Warnf("Removing partial data dir %q failed: %v", stale, rErr)
+			continue
+		}
+		logrus.Infof("Removed partial data dir %q", stale)
+		resp.Removed = append(resp.Removed, stale)
+		resp.ReclaimedBytes += size
+	}
+
+	return nil
+}
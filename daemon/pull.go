@@ -15,9 +15,12 @@ package daemon
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 
 	dockerref "github.com/containers/image/v5/docker/reference"
 	"github.com/containers/image/v5/types"
+	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
@@ -60,7 +63,7 @@ func (b *Backend) Pull(req *pb.PullRequest, stream pb.Control_PullServer) error
 
 	ctx := context.WithValue(stream.Context(), util.LogFieldKey(util.LogKeySessionID), req.GetPullID())
 	eg, egCtx := errgroup.WithContext(ctx)
-	eg.Go(pullHandler(egCtx, opt))
+	eg.Go(pullHandler(egCtx, opt, b.daemon.opts.DataRoot))
 	eg.Go(pullMessageHandler(stream, opt.logger))
 
 	if err := eg.Wait(); err != nil {
@@ -71,16 +74,34 @@ func (b *Backend) Pull(req *pb.PullRequest, stream pb.Control_PullServer) error
 	return nil
 }
 
-func pullHandler(ctx context.Context, options pullOptions) func() error {
+func pullHandler(ctx context.Context, options pullOptions, dataRoot string) func() error {
 	return func() error {
 		defer func() {
 			options.logger.CloseContent()
 		}()
 
+		// partial blobs of this session are kept in an own tmp dir, so
+		// a failed or canceled pull leaves no data behind
+		tmpDir, err := securejoin.SecureJoin(dataRoot, filepath.Join(constant.DataRootTmpDirPrefix, options.pullID))
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(tmpDir, constant.DefaultRootDirMode); err != nil {
+			return err
+		}
+		defer func() {
+			if rErr := os.RemoveAll(tmpDir); rErr != nil {
+				logrus.WithField(util.LogKeySessionID, options.pullID).
+					Errorf("Failed to remove pull temporary dir %q, err: %v", tmpDir, rErr)
+			}
+		}()
+		sysCtx := *options.sysCtx
+		sysCtx.BigFilesTemporaryDir = tmpDir
+
 		if _, _, err := image.PullAndGetImageInfo(&image.PrepareImageOptions{
 			Ctx:           ctx,
 			FromImage:     options.imageName,
-			SystemContext: options.sysCtx,
+			SystemContext: &sysCtx,
 			Store:         options.localStore,
 			Reporter:      options.logger,
 		}); err != nil {
@@ -15,6 +15,7 @@ package daemon
 
 import (
 	"context"
+	"path/filepath"
 
 	dockerref "github.com/containers/image/v5/docker/reference"
 	"github.com/containers/image/v5/types"
@@ -27,6 +28,7 @@ import (
 	"isula.org/isula-build/exporter"
 	"isula.org/isula-build/image"
 	"isula.org/isula-build/pkg/logger"
+	"isula.org/isula-build/pkg/referrer"
 	"isula.org/isula-build/store"
 	"isula.org/isula-build/util"
 )
@@ -39,6 +41,8 @@ type pushOptions struct {
 	imageName    string
 	format       string
 	manifestType string
+	attach       string
+	artifactType string
 }
 
 // Push receives a push request and push the image to remote repository
@@ -52,12 +56,25 @@ func (b *Backend) Push(req *pb.PushRequest, stream pb.Control_PushServer) error
 	cliLogger := logger.NewCliLogger(constant.CliLogBufferLen)
 
 	opt := pushOptions{
-		sysCtx:     image.GetSystemContext(),
-		logger:     cliLogger,
-		localStore: b.daemon.localStore,
-		pushID:     req.GetPushID(),
-		imageName:  req.GetImageName(),
-		format:     req.GetFormat(),
+		sysCtx:       image.GetSystemContext(),
+		logger:       cliLogger,
+		localStore:   b.daemon.localStore,
+		pushID:       req.GetPushID(),
+		imageName:    req.GetImageName(),
+		format:       req.GetFormat(),
+		attach:       req.GetAttach(),
+		artifactType: req.GetArtifactType(),
+	}
+
+	if opt.attach != "" {
+		if !filepath.IsAbs(opt.attach) {
+			return errors.Errorf("attached artifact path %q is not an absolute path", opt.attach)
+		}
+		if exist, err := util.IsExist(opt.attach); err != nil {
+			return errors.Wrap(err, "check attached artifact failed")
+		} else if !exist {
+			return errors.Errorf("attached artifact %q is not exist", opt.attach)
+		}
 	}
 
 	if err := util.CheckImageFormat(opt.format); err != nil {
@@ -108,6 +125,17 @@ func pushHandler(ctx context.Context, options pushOptions) func() error {
 			return errors.Wrapf(err, "push image %q of format %q failed", options.imageName, constant.DockerTransport)
 		}
 
+		if options.attach != "" {
+			artifact := referrer.Artifact{Path: options.attach, ArtifactType: options.artifactType}
+			artifactDigest, aErr := referrer.Push(ctx, options.sysCtx, options.imageName, artifact)
+			if aErr != nil {
+				logrus.WithField(util.LogKeySessionID, options.pushID).
+					Errorf("Attach artifact %q to image %q failed with %v", options.attach, options.imageName, aErr)
+				return errors.Wrapf(aErr, "attach artifact %q to image %q failed", options.attach, options.imageName)
+			}
+			options.logger.Print("Attached artifact with digest %s\n", artifactDigest.String())
+		}
+
 		return nil
 	}
 }
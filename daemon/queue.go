@@ -0,0 +1,121 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: build concurrency queue related functions
+
+package daemon
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// buildWaiter is one build waiting for a free build slot
+type buildWaiter struct {
+	buildID string
+	// ready is closed once the slot of a finished build is handed over
+	ready chan struct{}
+	// notify reports the current queue position, 1 is the head
+	notify func(position int)
+}
+
+// buildQueue bounds the number of concurrently running builds, excess
+// builds wait in FIFO order instead of all running at once
+type buildQueue struct {
+	sync.Mutex
+	max     int
+	running int
+	waiters []*buildWaiter
+}
+
+func newBuildQueue(max int) *buildQueue {
+	return &buildQueue{max: max}
+}
+
+// acquire blocks until a build slot is free, reporting the queue
+// position through notify while waiting, a canceled context gives the
+// place in the queue up again
+func (q *buildQueue) acquire(ctx context.Context, buildID string, notify func(position int)) error {
+	q.Lock()
+	if q.running < q.max && len(q.waiters) == 0 {
+		q.running++
+		q.Unlock()
+		return nil
+	}
+	w := &buildWaiter{buildID: buildID, ready: make(chan struct{}), notify: notify}
+	q.waiters = append(q.waiters, w)
+	position := len(q.waiters)
+	q.Unlock()
+
+	if notify != nil {
+		notify(position)
+	}
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		if !q.abandon(w) {
+			// the slot was already handed over while canceling, pass
+			// it on to the next waiter
+			q.release()
+		}
+		return errors.Wrapf(ctx.Err(), "build %s gave up waiting for a free build slot", buildID)
+	}
+}
+
+// release hands the slot of a finished build to the first waiter, or
+// frees it when the queue is empty
+func (q *buildQueue) release() {
+	q.Lock()
+	if len(q.waiters) == 0 {
+		q.running--
+		q.Unlock()
+		return
+	}
+	head := q.waiters[0]
+	q.waiters = q.waiters[1:]
+	rest := append([]*buildWaiter(nil), q.waiters...)
+	q.Unlock()
+
+	// the running count is unchanged, the slot moves to the head waiter
+	close(head.ready)
+	notifyPositions(rest, 1)
+}
+
+// abandon removes a canceled waiter from the queue, it reports false
+// when the waiter was already handed a slot
+func (q *buildQueue) abandon(w *buildWaiter) bool {
+	q.Lock()
+	for i, waiter := range q.waiters {
+		if waiter == w {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			rest := append([]*buildWaiter(nil), q.waiters[i:]...)
+			q.Unlock()
+			notifyPositions(rest, i+1)
+			return true
+		}
+	}
+	q.Unlock()
+	return false
+}
+
+// notifyPositions reports the new queue positions to the waiters whose
+// place shifted, first holds the position of the first one in the slice
+func notifyPositions(waiters []*buildWaiter, first int) {
+	for i, waiter := range waiters {
+		if waiter.notify != nil {
+			waiter.notify(first + i)
+		}
+	}
+}
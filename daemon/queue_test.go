@@ -0,0 +1,114 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: build concurrency queue related functions tests
+
+package daemon
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBuildQueueFIFO(t *testing.T) {
+	q := newBuildQueue(1)
+	ctx := context.Background()
+
+	assert.NilError(t, q.acquire(ctx, "first", nil))
+
+	var mu sync.Mutex
+	positions := make(map[string][]int)
+	record := func(buildID string) func(int) {
+		return func(position int) {
+			mu.Lock()
+			positions[buildID] = append(positions[buildID], position)
+			mu.Unlock()
+		}
+	}
+
+	var order []string
+	var wg sync.WaitGroup
+	acquired := func(buildID string) {
+		mu.Lock()
+		order = append(order, buildID)
+		mu.Unlock()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		assert.NilError(t, q.acquire(ctx, "second", record("second")))
+		acquired("second")
+	}()
+	// make sure "second" queues up before "third"
+	for {
+		q.Lock()
+		waiting := len(q.waiters)
+		q.Unlock()
+		if waiting == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	go func() {
+		defer wg.Done()
+		assert.NilError(t, q.acquire(ctx, "third", record("third")))
+		acquired("third")
+		q.release()
+	}()
+	for {
+		q.Lock()
+		waiting := len(q.waiters)
+		q.Unlock()
+		if waiting == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	q.release()
+	// "second" got the slot, release it so "third" runs as well
+	for {
+		mu.Lock()
+		got := len(order)
+		mu.Unlock()
+		if got == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	q.release()
+	wg.Wait()
+
+	assert.DeepEqual(t, order, []string{"second", "third"})
+	assert.Equal(t, positions["second"][0], 1)
+	assert.Equal(t, positions["third"][0], 2)
+	// "third" moved up once "second" got the slot
+	assert.Equal(t, positions["third"][len(positions["third"])-1], 1)
+}
+
+func TestBuildQueueCanceledWaiter(t *testing.T) {
+	q := newBuildQueue(1)
+	assert.NilError(t, q.acquire(context.Background(), "running", nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := q.acquire(ctx, "canceled", nil)
+	assert.ErrorContains(t, err, "gave up waiting")
+
+	// the canceled waiter left the queue, the slot can be reacquired
+	q.release()
+	assert.NilError(t, q.acquire(context.Background(), "next", nil))
+	q.release()
+}
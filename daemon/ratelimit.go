@@ -0,0 +1,63 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-22
+// Description: This file is used for gRPC request rate limiting
+
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// checkRateLimit consumes one token of the calling client and rejects
+// the request with ResourceExhausted and a retry hint when its budget
+// is used up
+func (d *Daemon) checkRateLimit(ctx context.Context) error {
+	if d.limiter == nil {
+		return nil
+	}
+
+	key := "unknown"
+	if p, ok := peer.FromContext(ctx); ok {
+		key = p.Addr.String()
+	}
+	if allowed, retryAfter := d.limiter.Allow(key); !allowed {
+		return grpcstatus.Errorf(codes.ResourceExhausted,
+			"request rate limit exceeded, retry after %v", retryAfter.Round(time.Millisecond))
+	}
+
+	return nil
+}
+
+// rateUnaryInterceptor gates unary RPCs with the rate limit check
+func (d *Daemon) rateUnaryInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := d.checkRateLimit(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// rateStreamInterceptor gates streaming RPCs with the rate limit
+// check, one stream costs one token however long it runs
+func (d *Daemon) rateStreamInterceptor(srv interface{}, ss grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := d.checkRateLimit(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
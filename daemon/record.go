@@ -0,0 +1,366 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file persists build records and exports them as signed bundles
+
+package daemon
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containers/storage/pkg/archive"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	constant "isula.org/isula-build"
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/pkg/version"
+	"isula.org/isula-build/util"
+)
+
+const (
+	// buildRecordsDirName holds the persisted build records under the data root
+	buildRecordsDirName = "records"
+	// recordBundleVersion is bumped when the bundle layout changes
+	recordBundleVersion = 1
+	// recordBundleManifest describes the records inside a bundle
+	recordBundleManifest = "manifest.json"
+	// recordBundleSignature carries the signature over the manifest
+	recordBundleSignature = "manifest.sig"
+	// recordBundleSigner carries the public key the signature verifies with
+	recordBundleSigner = "signer.pem"
+)
+
+// buildRecord is the persisted outcome of one build
+type buildRecord struct {
+	BuildID    string    `json:"buildID"`
+	BuildType  string    `json:"buildType"`
+	ImageID    string    `json:"imageID,omitempty"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	StartTime  time.Time `json:"startTime"`
+	FinishTime time.Time `json:"finishTime"`
+}
+
+// recordBundleEntry is one record listed in a bundle manifest
+type recordBundleEntry struct {
+	File   string `json:"file"`
+	SHA256 string `json:"sha256"`
+}
+
+// recordBundleInfo is the manifest of an exported record bundle, the
+// signature of the bundle covers its serialized form
+type recordBundleInfo struct {
+	Version       int                 `json:"version"`
+	Created       time.Time           `json:"created"`
+	DaemonVersion string              `json:"daemonVersion"`
+	Records       []recordBundleEntry `json:"records"`
+}
+
+// recordsDir returns the directory holding the persisted build records
+func (b *Backend) recordsDir() (string, error) {
+	return securejoin.SecureJoin(b.daemon.opts.DataRoot, buildRecordsDirName)
+}
+
+// writeBuildRecord persists the outcome of one build and applies the
+// configured retention to the stored records
+func (b *Backend) writeBuildRecord(record buildRecord) {
+	b.writeRecordFile(record.BuildID+".json", record)
+}
+
+// writeRecordFile persists one record under the records dir and applies
+// the configured retention to the stored records
+func (b *Backend) writeRecordFile(fileName string, record interface{}) {
+	dir, err := b.recordsDir()
+	if err != nil {
+		logrus.Warnf("Resolving build records dir failed: %v", err)
+		return
+	}
+	if err = os.MkdirAll(dir, constant.DefaultRootDirMode); err != nil {
+		logrus.Warnf("Creating build records dir failed: %v", err)
+		return
+	}
+	data, err := json.MarshalIndent(record, "", "    ")
+	if err != nil {
+		logrus.Warnf("Marshal record %q failed: %v", fileName, err)
+		return
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, fileName), data, constant.DefaultRootFileMode); err != nil {
+		logrus.Warnf("Write record %q failed: %v", fileName, err)
+		return
+	}
+
+	b.pruneBuildRecords(dir)
+}
+
+// pruneBuildRecords removes the records older than the configured
+// retention, an empty retention keeps the records forever
+func (b *Backend) pruneBuildRecords(dir string) {
+	retention := b.daemon.opts.RecordRetention
+	if retention == "" {
+		return
+	}
+	keep, err := time.ParseDuration(retention)
+	if err != nil || keep <= 0 {
+		logrus.Warnf("Invalid record retention %q, keeping all records", retention)
+		return
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		logrus.Warnf("Reading build records dir failed: %v", err)
+		return
+	}
+	cutoff := time.Now().Add(-keep)
+	for _, entry := range entries {
+		if entry.IsDir() || !entry.ModTime().Before(cutoff) {
+			continue
+		}
+		stale := filepath.Join(dir, entry.Name())
+		if rErr := os.Remove(stale); rErr != nil {
+			logrus.Warnf("Removing expired build record %q failed: %v", stale, rErr)
+			continue
+		}
+		logrus.Infof("Removed expired build record %q", stale)
+	}
+}
+
+// RecordsExport bundles the persisted build records into a signed
+// archive for audit archival or transfer to another daemon
+func (b *Backend) RecordsExport(ctx context.Context, req *pb.RecordsExportRequest) (*pb.RecordsExportResponse, error) {
+	logEntry := logrus.WithFields(logrus.Fields{"ExportID": req.GetExportID()})
+	logEntry.WithField("Path", req.GetPath()).Info("RecordsExportRequest received")
+
+	recordsDir, err := b.recordsDir()
+	if err != nil {
+		return &pb.RecordsExportResponse{}, err
+	}
+	entries, err := ioutil.ReadDir(recordsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &pb.RecordsExportResponse{}, errors.New("no build record to export")
+		}
+		return &pb.RecordsExportResponse{}, err
+	}
+
+	tmpDir, err := securejoin.SecureJoin(b.daemon.opts.DataRoot,
+		filepath.Join(constant.DataRootTmpDirPrefix, req.GetExportID()))
+	if err != nil {
+		return &pb.RecordsExportResponse{}, err
+	}
+	if err = os.MkdirAll(filepath.Join(tmpDir, buildRecordsDirName), constant.DefaultRootDirMode); err != nil {
+		return &pb.RecordsExportResponse{}, err
+	}
+	defer func() {
+		if rErr := os.RemoveAll(tmpDir); rErr != nil {
+			logEntry.Warnf("Removing export tmp directory %q failed: %v", tmpDir, rErr)
+		}
+	}()
+
+	bundle := recordBundleInfo{
+		Version:       recordBundleVersion,
+		Created:       time.Now().UTC(),
+		DaemonVersion: version.Version,
+	}
+	var exported []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, rErr := ioutil.ReadFile(filepath.Clean(filepath.Join(recordsDir, entry.Name())))
+		if rErr != nil {
+			return &pb.RecordsExportResponse{}, errors.Wrapf(rErr, "read build record %q failed", entry.Name())
+		}
+		if wErr := ioutil.WriteFile(filepath.Join(tmpDir, buildRecordsDirName, entry.Name()),
+			data, constant.DefaultRootFileMode); wErr != nil {
+			return &pb.RecordsExportResponse{}, wErr
+		}
+		sum := sha256.Sum256(data)
+		bundle.Records = append(bundle.Records, recordBundleEntry{
+			File:   entry.Name(),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+		exported = append(exported, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	if len(bundle.Records) == 0 {
+		return &pb.RecordsExportResponse{}, errors.New("no build record to export")
+	}
+
+	manifest, err := json.MarshalIndent(bundle, "", "    ")
+	if err != nil {
+		return &pb.RecordsExportResponse{}, errors.Wrap(err, "marshal bundle manifest failed")
+	}
+	if err = ioutil.WriteFile(filepath.Join(tmpDir, recordBundleManifest), manifest, constant.DefaultRootFileMode); err != nil {
+		return &pb.RecordsExportResponse{}, err
+	}
+	if err = signRecordBundle(tmpDir, manifest, b.daemon.key); err != nil {
+		return &pb.RecordsExportResponse{}, err
+	}
+
+	if err = util.PackFiles(tmpDir, req.GetPath(), archive.Uncompressed, false); err != nil {
+		return &pb.RecordsExportResponse{}, errors.Wrapf(err, "pack record bundle %q failed", req.GetPath())
+	}
+	logEntry.Infof("Exported %d build records to %q", len(exported), req.GetPath())
+
+	return &pb.RecordsExportResponse{Records: exported}, nil
+}
+
+// signRecordBundle signs the manifest with the daemon key and stores
+// the signature and the public key next to it
+func signRecordBundle(tmpDir string, manifest []byte, key *rsa.PrivateKey) error {
+	digest := sha256.Sum256(manifest)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return errors.Wrap(err, "sign bundle manifest failed")
+	}
+	if err = ioutil.WriteFile(filepath.Join(tmpDir, recordBundleSignature),
+		[]byte(base64.StdEncoding.EncodeToString(sig)), constant.DefaultRootFileMode); err != nil {
+		return err
+	}
+
+	pubBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return errors.Wrap(err, "marshal bundle public key failed")
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes})
+
+	return ioutil.WriteFile(filepath.Join(tmpDir, recordBundleSigner), pemBytes, constant.DefaultRootFileMode)
+}
+
+// verifyRecordBundle checks the signature and the per-record digests
+// of an unpacked bundle and returns its manifest
+func verifyRecordBundle(tmpDir string) (recordBundleInfo, error) {
+	manifest, err := ioutil.ReadFile(filepath.Clean(filepath.Join(tmpDir, recordBundleManifest)))
+	if err != nil {
+		return recordBundleInfo{}, errors.Wrap(err, "read bundle manifest failed, not a record bundle")
+	}
+	sigData, err := ioutil.ReadFile(filepath.Clean(filepath.Join(tmpDir, recordBundleSignature)))
+	if err != nil {
+		return recordBundleInfo{}, errors.Wrap(err, "read bundle signature failed")
+	}
+	sig, err := base64.StdEncoding.DecodeString(string(sigData))
+	if err != nil {
+		return recordBundleInfo{}, errors.Wrap(err, "decode bundle signature failed")
+	}
+	pemData, err := ioutil.ReadFile(filepath.Clean(filepath.Join(tmpDir, recordBundleSigner)))
+	if err != nil {
+		return recordBundleInfo{}, errors.Wrap(err, "read bundle public key failed")
+	}
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return recordBundleInfo{}, errors.New("decode bundle public key failed")
+	}
+	pubKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return recordBundleInfo{}, errors.Wrap(err, "parse bundle public key failed")
+	}
+	rsaKey, ok := pubKey.(*rsa.PublicKey)
+	if !ok {
+		return recordBundleInfo{}, errors.New("the bundle public key is not an RSA key")
+	}
+
+	digest := sha256.Sum256(manifest)
+	if err = rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], sig); err != nil {
+		return recordBundleInfo{}, errors.New("the bundle signature does not match, the bundle was tampered with")
+	}
+
+	var bundle recordBundleInfo
+	if err = json.Unmarshal(manifest, &bundle); err != nil {
+		return recordBundleInfo{}, errors.Wrap(err, "parse bundle manifest failed")
+	}
+	if bundle.Version != recordBundleVersion {
+		return recordBundleInfo{}, errors.Errorf("unsupported record bundle version %d, expected %d",
+			bundle.Version, recordBundleVersion)
+	}
+	for _, record := range bundle.Records {
+		data, rErr := ioutil.ReadFile(filepath.Clean(filepath.Join(tmpDir, buildRecordsDirName, record.File)))
+		if rErr != nil {
+			return recordBundleInfo{}, errors.Wrapf(rErr, "read bundled record %q failed", record.File)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != record.SHA256 {
+			return recordBundleInfo{}, errors.Errorf("record %q does not match its manifest digest", record.File)
+		}
+	}
+
+	return bundle, nil
+}
+
+// RecordsImport verifies a signed record bundle and stores its build
+// records next to the locally created ones
+func (b *Backend) RecordsImport(ctx context.Context, req *pb.RecordsImportRequest) (*pb.RecordsImportResponse, error) {
+	logEntry := logrus.WithFields(logrus.Fields{"ImportID": req.GetImportID()})
+	logEntry.WithField("Path", req.GetPath()).Info("RecordsImportRequest received")
+
+	if err := util.CheckFileInfoAndSize(req.GetPath(), constant.MaxLoadFileSize); err != nil {
+		return &pb.RecordsImportResponse{}, err
+	}
+
+	tmpDir, err := securejoin.SecureJoin(b.daemon.opts.DataRoot,
+		filepath.Join(constant.DataRootTmpDirPrefix, req.GetImportID()))
+	if err != nil {
+		return &pb.RecordsImportResponse{}, err
+	}
+	if err = os.MkdirAll(tmpDir, constant.DefaultRootDirMode); err != nil {
+		return &pb.RecordsImportResponse{}, err
+	}
+	defer func() {
+		if rErr := os.RemoveAll(tmpDir); rErr != nil {
+			logEntry.Warnf("Removing import tmp directory %q failed: %v", tmpDir, rErr)
+		}
+	}()
+
+	if err = util.UnpackFile(req.GetPath(), tmpDir, archive.Uncompressed, false); err != nil {
+		return &pb.RecordsImportResponse{}, errors.Wrapf(err, "unpack record bundle %q failed", req.GetPath())
+	}
+	bundle, err := verifyRecordBundle(tmpDir)
+	if err != nil {
+		return &pb.RecordsImportResponse{}, err
+	}
+
+	recordsDir, err := b.recordsDir()
+	if err != nil {
+		return &pb.RecordsImportResponse{}, err
+	}
+	if err = os.MkdirAll(recordsDir, constant.DefaultRootDirMode); err != nil {
+		return &pb.RecordsImportResponse{}, err
+	}
+
+	var imported []string
+	for _, record := range bundle.Records {
+		data, rErr := ioutil.ReadFile(filepath.Clean(filepath.Join(tmpDir, buildRecordsDirName, record.File)))
+		if rErr != nil {
+			return &pb.RecordsImportResponse{}, rErr
+		}
+		if wErr := ioutil.WriteFile(filepath.Join(recordsDir, record.File), data, constant.DefaultRootFileMode); wErr != nil {
+			return &pb.RecordsImportResponse{}, wErr
+		}
+		imported = append(imported, strings.TrimSuffix(record.File, ".json"))
+	}
+	logEntry.Infof("Imported %d build records from %q", len(imported), req.GetPath())
+
+	return &pb.RecordsImportResponse{Records: imported}, nil
+}
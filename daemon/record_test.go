@@ -0,0 +1,82 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: build record bundle test
+
+package daemon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/util"
+)
+
+func TestRecordBundleSignAndVerify(t *testing.T) {
+	record := buildRecord{
+		BuildID:    "abc123",
+		BuildType:  "ctr-img",
+		ImageID:    "def456",
+		Status:     "success",
+		StartTime:  time.Now().Add(-time.Minute),
+		FinishTime: time.Now(),
+	}
+	recordData, err := json.Marshal(record)
+	assert.NilError(t, err)
+	sum := sha256.Sum256(recordData)
+
+	bundle := recordBundleInfo{
+		Version:       recordBundleVersion,
+		Created:       time.Now().UTC(),
+		DaemonVersion: "0.9.6",
+		Records: []recordBundleEntry{
+			{File: record.BuildID + ".json", SHA256: hex.EncodeToString(sum[:])},
+		},
+	}
+	manifest, err := json.Marshal(bundle)
+	assert.NilError(t, err)
+
+	tmpDir := fs.NewDir(t, t.Name(),
+		fs.WithDir(buildRecordsDirName, fs.WithFile(record.BuildID+".json", string(recordData))),
+		fs.WithFile(recordBundleManifest, string(manifest)))
+	defer tmpDir.Remove()
+
+	key, err := util.GenerateRSAKey(util.DefaultRSAKeySize)
+	assert.NilError(t, err)
+	assert.NilError(t, signRecordBundle(tmpDir.Path(), manifest, key))
+
+	verified, err := verifyRecordBundle(tmpDir.Path())
+	assert.NilError(t, err)
+	assert.Equal(t, len(verified.Records), 1)
+	assert.Equal(t, verified.Records[0].File, "abc123.json")
+
+	// a modified record no longer matches its manifest digest
+	recordPath := filepath.Join(tmpDir.Path(), buildRecordsDirName, record.BuildID+".json")
+	assert.NilError(t, ioutil.WriteFile(recordPath, []byte("tampered"), constant.DefaultRootFileMode))
+	_, err = verifyRecordBundle(tmpDir.Path())
+	assert.ErrorContains(t, err, "does not match its manifest digest")
+
+	// a modified manifest breaks the signature
+	assert.NilError(t, ioutil.WriteFile(recordPath, recordData, constant.DefaultRootFileMode))
+	manifestPath := filepath.Join(tmpDir.Path(), recordBundleManifest)
+	assert.NilError(t, ioutil.WriteFile(manifestPath, append(manifest, '\n'), constant.DefaultRootFileMode))
+	_, err = verifyRecordBundle(tmpDir.Path())
+	assert.ErrorContains(t, err, "signature does not match")
+}
@@ -0,0 +1,195 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: thread-safe registry managing the active builders
+
+package daemon
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/builder"
+	"isula.org/isula-build/pkg/gc"
+)
+
+const (
+	// defaultMaxBuilders is the most builds one daemon serves concurrently
+	defaultMaxBuilders = 10
+	// maxBuilderAge is how long a build may run before it is considered
+	// stuck and gets reaped
+	maxBuilderAge = 24 * time.Hour
+	// staleBuilderReapInterval is how often the stale builder reaper runs
+	staleBuilderReapInterval = 10 * time.Minute
+)
+
+// builderEntry carries one active builder and its bookkeeping data
+type builderEntry struct {
+	builder   builder.Builder
+	entityID  string
+	startTime time.Time
+}
+
+// activeBuild is a snapshot of one entry handed out by the registry
+type activeBuild struct {
+	buildID   string
+	startTime time.Time
+	builder   builder.Builder
+}
+
+// builderRegistry guards the map of active builders, it enforces the
+// max concurrent builds limit and refuses duplicated static builds
+type builderRegistry struct {
+	sync.RWMutex
+	entries     map[string]*builderEntry
+	entities    map[string]string
+	maxBuilders int
+}
+
+func newBuilderRegistry(maxBuilders int) *builderRegistry {
+	return &builderRegistry{
+		entries:     make(map[string]*builderEntry),
+		entities:    make(map[string]string),
+		maxBuilders: maxBuilders,
+	}
+}
+
+// add stores the builder for the build job, refusing to exceed the
+// concurrent builds limit or to run the same static build twice
+func (r *builderRegistry) add(buildID string, b builder.Builder) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if len(r.entries) >= r.maxBuilders {
+		return errors.Errorf("%d builds are already running, at most %d concurrent builds are allowed", len(r.entries), r.maxBuilders)
+	}
+	entityID := b.EntityID()
+	if runningID, exist := r.entities[entityID]; exist {
+		return errors.Errorf("the dockerfile is already on building with static build mode by buildID: %s", runningID)
+	}
+
+	r.entities[entityID] = buildID
+	r.entries[buildID] = &builderEntry{
+		builder:   b,
+		entityID:  entityID,
+		startTime: time.Now(),
+	}
+
+	return nil
+}
+
+// get returns the builder of the build job
+func (r *builderRegistry) get(buildID string) (builder.Builder, error) {
+	r.RLock()
+	defer r.RUnlock()
+	entry, ok := r.entries[buildID]
+	if !ok {
+		return nil, errors.Errorf("could not find builder with build job %s", buildID)
+	}
+	return entry.builder, nil
+}
+
+// remove drops the builder of the build job from the registry
+func (r *builderRegistry) remove(buildID string) {
+	r.Lock()
+	defer r.Unlock()
+	if entry, ok := r.entries[buildID]; ok {
+		delete(r.entities, entry.entityID)
+		delete(r.entries, buildID)
+	}
+}
+
+// removeAll drops all builders from the registry
+func (r *builderRegistry) removeAll() {
+	r.Lock()
+	defer r.Unlock()
+	r.entries = make(map[string]*builderEntry)
+	r.entities = make(map[string]string)
+}
+
+// count returns the number of active builds
+func (r *builderRegistry) count() int {
+	r.RLock()
+	defer r.RUnlock()
+	return len(r.entries)
+}
+
+// resources returns the resource limits of the active builds
+func (r *builderRegistry) resources() []*pb.BuildResources {
+	r.RLock()
+	defer r.RUnlock()
+	resources := make([]*pb.BuildResources, 0, len(r.entries))
+	for _, entry := range r.entries {
+		resources = append(resources, entry.builder.Resources())
+	}
+	return resources
+}
+
+// list returns a snapshot of the active builds ordered by start time
+func (r *builderRegistry) list() []activeBuild {
+	r.RLock()
+	defer r.RUnlock()
+	builds := make([]activeBuild, 0, len(r.entries))
+	for buildID, entry := range r.entries {
+		builds = append(builds, activeBuild{
+			buildID:   buildID,
+			startTime: entry.startTime,
+			builder:   entry.builder,
+		})
+	}
+	sort.Slice(builds, func(i, j int) bool { return builds[i].startTime.Before(builds[j].startTime) })
+	return builds
+}
+
+// stale returns the active builds running longer than maxAge
+func (r *builderRegistry) stale(maxAge time.Duration) []activeBuild {
+	var builds []activeBuild
+	for _, build := range r.list() {
+		if time.Since(build.startTime) > maxAge {
+			builds = append(builds, build)
+		}
+	}
+	return builds
+}
+
+// registerStaleBuilderReaper registers the recycling of builds running
+// longer than maxBuilderAge, their resources are released and further
+// status queries for them fail instead of hanging
+func (d *Daemon) registerStaleBuilderReaper(g *gc.GarbageCollector) error {
+	reapStaleBuilders := func(i interface{}) error {
+		daemonTmp := i.(*Daemon)
+		for _, build := range daemonTmp.reg().stale(maxBuilderAge) {
+			logrus.Warnf("Build %s is running longer than %v, reaping it", build.buildID, maxBuilderAge)
+			if err := build.builder.CleanResources(); err != nil {
+				logrus.Warnf("Clean resources for stale build %s failed: %v", build.buildID, err)
+			}
+			daemonTmp.reg().remove(build.buildID)
+			if daemonTmp.backend != nil {
+				daemonTmp.backend.deleteStatus(build.buildID)
+			}
+		}
+		return nil
+	}
+
+	opt := &gc.RegisterOption{
+		Name:        "staleBuilderReaper",
+		Interval:    staleBuilderReapInterval,
+		RecycleData: d,
+		RecycleFunc: reapStaleBuilders,
+	}
+
+	return g.RegisterGC(opt)
+}
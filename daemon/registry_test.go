@@ -0,0 +1,96 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: builderRegistry related unit tests
+
+package daemon
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	pb "isula.org/isula-build/api/services"
+)
+
+type fakeBuilder struct {
+	entityID string
+	cleaned  bool
+}
+
+func (f *fakeBuilder) Build() (string, error)       { return "", nil }
+func (f *fakeBuilder) StatusChan() <-chan string    { return nil }
+func (f *fakeBuilder) CleanResources() error        { f.cleaned = true; return nil }
+func (f *fakeBuilder) EntityID() string             { return f.entityID }
+func (f *fakeBuilder) Progress() (int, int)         { return 1, 2 }
+func (f *fakeBuilder) Graph(string) ([]byte, error) { return nil, nil }
+func (f *fakeBuilder) Resources() *pb.BuildResources {
+	return &pb.BuildResources{}
+}
+
+func TestBuilderRegistryAddGetRemove(t *testing.T) {
+	reg := newBuilderRegistry(defaultMaxBuilders)
+
+	err := reg.add("build1", &fakeBuilder{entityID: "entity1"})
+	assert.NilError(t, err)
+	assert.Equal(t, reg.count(), 1)
+
+	b, err := reg.get("build1")
+	assert.NilError(t, err)
+	assert.Equal(t, b.EntityID(), "entity1")
+
+	_, err = reg.get("none")
+	assert.ErrorContains(t, err, "could not find builder with build job")
+
+	err = reg.add("build2", &fakeBuilder{entityID: "entity1"})
+	assert.ErrorContains(t, err, "already on building with static build mode by buildID: build1")
+
+	reg.remove("build1")
+	assert.Equal(t, reg.count(), 0)
+	// entity released with the build, same dockerfile may build again
+	err = reg.add("build2", &fakeBuilder{entityID: "entity1"})
+	assert.NilError(t, err)
+
+	reg.removeAll()
+	assert.Equal(t, reg.count(), 0)
+}
+
+func TestBuilderRegistryMaxBuilders(t *testing.T) {
+	reg := newBuilderRegistry(2)
+
+	for i := 0; i < 2; i++ {
+		err := reg.add(fmt.Sprintf("build%d", i), &fakeBuilder{entityID: fmt.Sprintf("entity%d", i)})
+		assert.NilError(t, err)
+	}
+	err := reg.add("buildOverflow", &fakeBuilder{entityID: "entityOverflow"})
+	assert.ErrorContains(t, err, "at most 2 concurrent builds are allowed")
+}
+
+func TestBuilderRegistryListAndStale(t *testing.T) {
+	reg := newBuilderRegistry(defaultMaxBuilders)
+
+	err := reg.add("build1", &fakeBuilder{entityID: "entity1"})
+	assert.NilError(t, err)
+	err = reg.add("build2", &fakeBuilder{entityID: "entity2"})
+	assert.NilError(t, err)
+
+	builds := reg.list()
+	assert.Equal(t, len(builds), 2)
+	assert.Assert(t, !builds[0].startTime.After(builds[1].startTime))
+
+	assert.Equal(t, len(reg.stale(time.Hour)), 0)
+	reg.entries["build1"].startTime = time.Now().Add(-2 * time.Hour)
+	stale := reg.stale(time.Hour)
+	assert.Equal(t, len(stale), 1)
+	assert.Equal(t, stale[0].buildID, "build1")
+}
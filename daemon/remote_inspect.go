@@ -0,0 +1,175 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-04-24
+// Description: This file is "remote-inspect" command for backend
+
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/containers/image/v5/docker"
+	ci "github.com/containers/image/v5/image"
+	"github.com/containers/image/v5/manifest"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/image"
+	"isula.org/isula-build/util"
+)
+
+// remoteInspectPlatform is one platform listed in a remote manifest list
+type remoteInspectPlatform struct {
+	Os           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+	Digest       string `json:"digest"`
+}
+
+// remoteInspectLayer is one layer descriptor of the inspected instance
+type remoteInspectLayer struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// remoteInspectInfo is the inspect result assembled from the manifest
+// and the config only, no layer is downloaded for it
+type remoteInspectInfo struct {
+	Name         string                  `json:"name"`
+	Digest       string                  `json:"digest"`
+	ManifestType string                  `json:"manifestType"`
+	Platforms    []remoteInspectPlatform `json:"platforms,omitempty"`
+	Created      *time.Time              `json:"created,omitempty"`
+	Os           string                  `json:"os"`
+	Architecture string                  `json:"architecture"`
+	Variant      string                  `json:"variant,omitempty"`
+	Labels       map[string]string       `json:"labels,omitempty"`
+	Layers       []remoteInspectLayer    `json:"layers"`
+}
+
+// RemoteInspect fetches only the manifest and config of a remote image
+// and returns the platforms, layer digests and labels found there
+func (b *Backend) RemoteInspect(ctx context.Context, req *pb.RemoteInspectRequest) (*pb.RemoteInspectResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"ImageName": req.GetImageName(),
+	}).Info("RemoteInspectRequest received")
+
+	imageName := req.GetImageName()
+	ref, err := docker.ParseReference("//" + imageName)
+	if err != nil {
+		return &pb.RemoteInspectResponse{}, errors.Wrapf(err, "parse image name %q failed", imageName)
+	}
+
+	src, err := ref.NewImageSource(ctx, image.GetSystemContext())
+	if err != nil {
+		logrus.WithField(util.LogKeySessionID, imageName).Errorf("Create image source err: %v", err)
+		return &pb.RemoteInspectResponse{}, errors.Wrapf(err, "create image source for %q failed", imageName)
+	}
+	defer func() {
+		if cErr := src.Close(); cErr != nil {
+			logrus.Warnf("Image source closing error: %v", cErr)
+		}
+	}()
+
+	manifestBytes, manifestType, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		logrus.WithField(util.LogKeySessionID, imageName).Errorf("Get image manifest err: %v", err)
+		return &pb.RemoteInspectResponse{}, errors.Wrapf(err, "get manifest of %q failed", imageName)
+	}
+
+	info := remoteInspectInfo{
+		Name:         imageName,
+		Digest:       digest.FromBytes(manifestBytes).String(),
+		ManifestType: manifestType,
+	}
+	if manifest.MIMETypeIsMultiImage(manifestType) {
+		if info.Platforms, err = listPlatforms(manifestBytes, manifestType); err != nil {
+			return &pb.RemoteInspectResponse{}, err
+		}
+	}
+
+	// the instance matching the daemon platform is picked from a list
+	img, err := ci.FromUnparsedImage(ctx, image.GetSystemContext(), ci.UnparsedInstance(src, nil))
+	if err != nil {
+		logrus.WithField(util.LogKeySessionID, imageName).Errorf("Parse image manifest err: %v", err)
+		return &pb.RemoteInspectResponse{}, errors.Wrapf(err, "parse manifest of %q failed", imageName)
+	}
+
+	// Inspect fetches the config blob only, the layers stay remote
+	inspectInfo, err := img.Inspect(ctx)
+	if err != nil {
+		logrus.WithField(util.LogKeySessionID, imageName).Errorf("Inspect image config err: %v", err)
+		return &pb.RemoteInspectResponse{}, errors.Wrapf(err, "inspect config of %q failed", imageName)
+	}
+	info.Created = inspectInfo.Created
+	info.Os = inspectInfo.Os
+	info.Architecture = inspectInfo.Architecture
+	info.Variant = inspectInfo.Variant
+	info.Labels = inspectInfo.Labels
+	for _, layer := range img.LayerInfos() {
+		info.Layers = append(info.Layers, remoteInspectLayer{
+			MediaType: layer.MediaType,
+			Digest:    layer.Digest.String(),
+			Size:      layer.Size,
+		})
+	}
+
+	data, err := json.MarshalIndent(info, "", "    ")
+	if err != nil {
+		return &pb.RemoteInspectResponse{}, errors.Wrap(err, "marshal inspect result failed")
+	}
+
+	return &pb.RemoteInspectResponse{Data: string(data)}, nil
+}
+
+// listPlatforms collects the platforms of a manifest list
+func listPlatforms(manifestBytes []byte, manifestType string) ([]remoteInspectPlatform, error) {
+	var platforms []remoteInspectPlatform
+	switch manifestType {
+	case manifest.DockerV2ListMediaType:
+		list, err := manifest.Schema2ListFromManifest(manifestBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse manifest list failed")
+		}
+		for _, m := range list.Manifests {
+			platforms = append(platforms, remoteInspectPlatform{
+				Os:           m.Platform.OS,
+				Architecture: m.Platform.Architecture,
+				Variant:      m.Platform.Variant,
+				Digest:       m.Digest.String(),
+			})
+		}
+	case imgspecv1.MediaTypeImageIndex:
+		index, err := manifest.OCI1IndexFromManifest(manifestBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse image index failed")
+		}
+		for _, m := range index.Manifests {
+			platform := remoteInspectPlatform{Digest: m.Digest.String()}
+			if m.Platform != nil {
+				platform.Os = m.Platform.OS
+				platform.Architecture = m.Platform.Architecture
+				platform.Variant = m.Platform.Variant
+			}
+			platforms = append(platforms, platform)
+		}
+	default:
+		return nil, errors.Errorf("unknown manifest list type %q", manifestType)
+	}
+
+	return platforms, nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-04-24
+// Description: remote-inspect backend test
+
+package daemon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/containers/image/v5/manifest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestListPlatforms(t *testing.T) {
+	schema2List := map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     manifest.DockerV2ListMediaType,
+		"manifests": []map[string]interface{}{
+			{
+				"mediaType": manifest.DockerV2Schema2MediaType,
+				"digest":    "sha256:e692418e4cbaf90ca69d05a66403747baa33ee08806650b51fab815ad7fc331f",
+				"size":      7143,
+				"platform":  map[string]interface{}{"os": "linux", "architecture": "amd64"},
+			},
+			{
+				"mediaType": manifest.DockerV2Schema2MediaType,
+				"digest":    "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270",
+				"size":      7682,
+				"platform":  map[string]interface{}{"os": "linux", "architecture": "arm", "variant": "v7"},
+			},
+		},
+	}
+	listBytes, err := json.Marshal(schema2List)
+	assert.NilError(t, err)
+
+	platforms, err := listPlatforms(listBytes, manifest.DockerV2ListMediaType)
+	assert.NilError(t, err)
+	assert.Equal(t, len(platforms), 2)
+	assert.Equal(t, platforms[0].Architecture, "amd64")
+	assert.Equal(t, platforms[1].Variant, "v7")
+
+	ociIndex := map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     imgspecv1.MediaTypeImageIndex,
+		"manifests": []map[string]interface{}{
+			{
+				"mediaType": imgspecv1.MediaTypeImageManifest,
+				"digest":    "sha256:e692418e4cbaf90ca69d05a66403747baa33ee08806650b51fab815ad7fc331f",
+				"size":      7143,
+				"platform":  map[string]interface{}{"os": "linux", "architecture": "arm64"},
+			},
+		},
+	}
+	indexBytes, err := json.Marshal(ociIndex)
+	assert.NilError(t, err)
+
+	platforms, err = listPlatforms(indexBytes, imgspecv1.MediaTypeImageIndex)
+	assert.NilError(t, err)
+	assert.Equal(t, len(platforms), 1)
+	assert.Equal(t, platforms[0].Architecture, "arm64")
+
+	_, err = listPlatforms(listBytes, "application/unknown")
+	assert.ErrorContains(t, err, "unknown manifest list type")
+}
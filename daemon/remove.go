@@ -61,6 +61,18 @@ func (b *Backend) Remove(req *pb.RemoveRequest, stream pb.Control_RemoveServer)
 			continue
 		}
 
+		// a held image is being read by a long-running save, deleting
+		// or untagging it now would corrupt the archive being written
+		if b.daemon.holds().inUse(img.ID) {
+			rmFailed = true
+			errMsg := fmt.Sprintf("Image %q is in use by a running save, try again later", imageID)
+			logrus.Error(errMsg)
+			if err = stream.Send(&pb.RemoveResponse{LayerMessage: errMsg}); err != nil {
+				return err
+			}
+			continue
+		}
+
 		// just untag image name if it refers to multiple tags
 		if len(img.Names) > 1 {
 			removed, uerr := untagImage(imageID, s, img)
@@ -15,12 +15,17 @@ package daemon
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"time"
 
 	"github.com/containers/image/v5/docker/reference"
 	"github.com/containers/image/v5/types"
+	"github.com/containers/storage/pkg/archive"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
@@ -54,6 +59,15 @@ type SaveOptions struct {
 	finalImageOrdered []string
 	finalImageSet     map[string]*savedImage
 	sep               separator.Saver
+	dataDir           string
+	platform          *exporter.PlatformOverride
+	oriAnnotations    []string
+	annotations       map[string]string
+	compression       string
+	// archiveCompression is the stream compression the suffix of the
+	// output path asks for, the tarball is compressed on the fly
+	archiveCompression string
+	compressionLevel   int
 }
 
 func (b *Backend) getSaveOptions(req *pb.SaveRequest) SaveOptions {
@@ -68,9 +82,23 @@ func (b *Backend) getSaveOptions(req *pb.SaveRequest) SaveOptions {
 		outputPath:        req.GetPath(),
 		logger:            logger.NewCliLogger(constant.CliLogBufferLen),
 		logEntry:          logrus.WithFields(logrus.Fields{"SaveID": req.GetSaveID(), "Format": req.GetFormat()}),
+		dataDir:           b.daemon.opts.DataRoot,
+		platform: &exporter.PlatformOverride{
+			OS:           req.GetOs(),
+			Architecture: req.GetArch(),
+			Variant:      req.GetVariant(),
+		},
+		oriAnnotations: req.GetAnnotations(),
+		compression:    req.GetCompression(),
 	}
 	// normal save
 	if !req.GetSep().GetEnabled() {
+		// a templated path keeps its literal suffix, only a plain one
+		// asks for on the fly compression
+		if !exporter.IsDestTemplate(opt.outputPath) {
+			opt.archiveCompression = util.ArchiveCompression(opt.outputPath)
+			opt.compressionLevel = int(req.GetCompressionLevel())
+		}
 		return opt
 	}
 
@@ -91,14 +119,52 @@ func (b *Backend) Save(req *pb.SaveRequest, stream pb.Control_SaveServer) (err e
 		return errors.Wrap(err, "check save options failed")
 	}
 
+	// the export reads the image for as long as the tarball takes to
+	// write, holding the resolved IDs keeps a concurrent Remove from
+	// deleting the layers mid-save, and saving by ID keeps a concurrent
+	// re-tag from changing what ends up in the archive
+	heldIDs, err := opts.heldImageIDs()
+	if err != nil {
+		return err
+	}
+	release := b.daemon.holds().hold(heldIDs)
+	defer release()
+
 	defer func() {
-		if err != nil {
+		if err != nil && !exporter.IsDestTemplate(opts.outputPath) {
 			if rErr := os.Remove(opts.outputPath); rErr != nil && !os.IsNotExist(rErr) {
 				opts.logEntry.Warnf("Removing save output tarball %q failed: %v", opts.outputPath, rErr)
 			}
 		}
 	}()
 
+	// the tarball of a .tar.gz or .tar.zst output streams through a
+	// fifo and gets compressed on the way to the destination, so no
+	// plain intermediate archive is written
+	if opts.archiveCompression != "" {
+		if err = os.MkdirAll(filepath.Dir(opts.outputPath), constant.DefaultRootFileMode); err != nil {
+			return err
+		}
+		fifo, fErr := util.NewFifoCompressor(opts.dataDir, opts.outputPath, opts.archiveCompression, opts.compressionLevel)
+		if fErr != nil {
+			return fErr
+		}
+		opts.logEntry.Infof("Compressing save output %q with %s on the fly", opts.outputPath, opts.archiveCompression)
+		compressedPath := opts.outputPath
+		opts.outputPath = fifo.Path()
+		defer func() {
+			if err != nil {
+				fifo.Abort()
+				return
+			}
+			if err = fifo.Finish(); err != nil {
+				if rErr := os.Remove(compressedPath); rErr != nil && !os.IsNotExist(rErr) {
+					opts.logEntry.Warnf("Removing compressed output %q failed: %v", compressedPath, rErr)
+				}
+			}
+		}()
+	}
+
 	ctx := context.WithValue(stream.Context(), util.LogFieldKey(util.LogKeySessionID), opts.saveID)
 	eg, _ := errgroup.WithContext(ctx)
 
@@ -120,34 +186,85 @@ func (b *Backend) Save(req *pb.SaveRequest, stream pb.Control_SaveServer) (err e
 
 func exportHandler(ctx context.Context, opts *SaveOptions) func() error {
 	return func() error {
+		// each templated destination gets its own export ID and with
+		// it its own archive writer
+		exportIDs := []string{opts.saveID}
 		defer func() {
 			opts.logger.CloseContent()
-			if savedocker.DockerArchiveExporter.GetArchiveWriter(opts.saveID) != nil {
-				if cErr := savedocker.DockerArchiveExporter.GetArchiveWriter(opts.saveID).Close(); cErr != nil {
-					opts.logEntry.Errorf("Close archive writer failed: %v", cErr)
+			for _, exportID := range exportIDs {
+				if savedocker.DockerArchiveExporter.GetArchiveWriter(exportID) != nil {
+					if cErr := savedocker.DockerArchiveExporter.GetArchiveWriter(exportID).Close(); cErr != nil {
+						opts.logEntry.Errorf("Close archive writer failed: %v", cErr)
+					}
+					savedocker.DockerArchiveExporter.RemoveArchiveWriter(exportID)
 				}
-				savedocker.DockerArchiveExporter.RemoveArchiveWriter(opts.saveID)
 			}
 		}()
 
 		if err := os.MkdirAll(filepath.Dir(opts.outputPath), constant.DefaultRootFileMode); err != nil {
 			return err
 		}
-		for _, imageID := range opts.finalImageOrdered {
+
+		// a non-templated oci save packs every image into one shared
+		// layout, so multiple images land in a single tarball with each
+		// blob stored once, a templated output still writes one tarball
+		// per image below
+		if opts.format == constant.OCIArchiveTransport && !exporter.IsDestTemplate(opts.outputPath) {
+			return exportOCIArchive(ctx, opts)
+		}
+
+		renderedPaths := make(map[string]string, len(opts.finalImageOrdered))
+		for i, imageID := range opts.finalImageOrdered {
 			copyCtx := *opts.sysCtx
 			if opts.format == constant.DockerArchiveTransport {
 				// It's ok for DockerArchiveAdditionalTags == nil, as a result, no additional tags will be appended to the final archive file.
 				copyCtx.DockerArchiveAdditionalTags = opts.finalImageSet[imageID].tags
 			}
 
+			outputPath := opts.outputPath
+			exportID := opts.saveID
+			if exporter.IsDestTemplate(opts.outputPath) {
+				info, iErr := opts.destTemplateInfo(imageID)
+				if iErr != nil {
+					return iErr
+				}
+				rendered, rErr := exporter.RenderDest(opts.outputPath, info)
+				if rErr != nil {
+					return rErr
+				}
+				if previous, ok := renderedPaths[rendered]; ok {
+					return errors.Errorf("templated output %q resolves to %q for both %q and %q",
+						opts.outputPath, rendered, previous, imageID)
+				}
+				renderedPaths[rendered] = imageID
+				outputPath = rendered
+				exportID = fmt.Sprintf("%s-%d", opts.saveID, i)
+				exportIDs = append(exportIDs, exportID)
+				opts.logEntry.Infof("Resolved save output %q for image %q", outputPath, imageID)
+				if err := os.MkdirAll(filepath.Dir(outputPath), constant.DefaultRootFileMode); err != nil {
+					return err
+				}
+			}
+
 			exOpts := exporter.ExportOptions{
-				Ctx:           ctx,
-				SystemContext: &copyCtx,
-				ExportID:      opts.saveID,
-				ReportWriter:  opts.logger,
+				Ctx:               ctx,
+				SystemContext:     &copyCtx,
+				ExportID:          exportID,
+				ReportWriter:      opts.logger,
+				Platform:          opts.platform,
+				Annotations:       opts.annotations,
+				CompressionFormat: opts.compression,
 			}
 
-			if err := exporter.Export(imageID, exporter.FormatTransport(opts.format, opts.outputPath),
+			srcSpec := imageID
+			if opts.format == constant.OCIArchiveTransport {
+				// the first tag ends up as the ref annotation of the
+				// per-image archive
+				if tags := opts.finalImageSet[imageID].tags; len(tags) > 0 {
+					srcSpec = reference.FamiliarString(tags[0])
+				}
+			}
+			if err := exporter.Export(srcSpec, exporter.FormatTransport(opts.format, outputPath),
 				exOpts, opts.localStore); err != nil {
 				opts.logEntry.Errorf("Save image %q in format %q failed: %v", imageID, opts.format, err)
 				return errors.Wrapf(err, "save image %q in format %q failed", imageID, opts.format)
@@ -158,6 +275,113 @@ func exportHandler(ctx context.Context, opts *SaveOptions) func() error {
 	}
 }
 
+// exportOCIArchive saves every image of this request into one shared
+// oci layout and packs the layout into the output tarball, every tag
+// becomes its own index entry and blobs common to several images or
+// tags are stored only once
+func exportOCIArchive(ctx context.Context, opts *SaveOptions) error {
+	layoutDir, err := ioutil.TempDir(opts.dataDir, "save-oci-")
+	if err != nil {
+		return errors.Wrap(err, "create oci layout dir failed")
+	}
+	defer func() {
+		if rErr := os.RemoveAll(layoutDir); rErr != nil {
+			opts.logEntry.Warnf("Removing oci layout dir %q failed: %v", layoutDir, rErr)
+		}
+	}()
+
+	for i, imageID := range opts.finalImageOrdered {
+		refs := make([]string, 0, 1)
+		for _, tag := range opts.finalImageSet[imageID].tags {
+			refs = append(refs, reference.FamiliarString(tag))
+		}
+		// an image saved without a tag is recorded under its ID
+		if len(refs) == 0 {
+			refs = append(refs, imageID)
+		}
+		for j, ref := range refs {
+			exOpts := exporter.ExportOptions{
+				Ctx:               ctx,
+				SystemContext:     opts.sysCtx,
+				ExportID:          fmt.Sprintf("%s-%d-%d", opts.saveID, i, j),
+				ReportWriter:      opts.logger,
+				Platform:          opts.platform,
+				Annotations:       opts.annotations,
+				CompressionFormat: opts.compression,
+			}
+			dest := fmt.Sprintf("%s:%s:%s", constant.OCITransport, layoutDir, ref)
+			if err := exporter.Export(imageID, dest, exOpts, opts.localStore); err != nil {
+				opts.logEntry.Errorf("Save image %q in format %q failed: %v", imageID, opts.format, err)
+				return errors.Wrapf(err, "save image %q in format %q failed", imageID, opts.format)
+			}
+		}
+	}
+
+	return util.PackFiles(layoutDir, opts.outputPath, archive.Uncompressed, false)
+}
+
+// destTemplateInfo collects the template fields of one image for
+// resolving a templated save destination
+func (opts *SaveOptions) destTemplateInfo(imageID string) (exporter.DestInfo, error) {
+	const noneValue = "none"
+	info := exporter.DestInfo{
+		Name: noneValue,
+		Tag:  noneValue,
+		Arch: runtime.GOARCH,
+		Os:   runtime.GOOS,
+		Date: time.Now().Format("20060102"),
+	}
+
+	_, img, err := image.FindImage(opts.localStore, imageID)
+	if err != nil {
+		return info, errors.Wrapf(err, "find image %q for destination template failed", imageID)
+	}
+	info.ID = img.ID
+	if len(img.ID) > constant.DefaultIDLen {
+		info.ID = img.ID[:constant.DefaultIDLen]
+	}
+
+	var named reference.Named
+	if saved, ok := opts.finalImageSet[img.ID]; ok && len(saved.tags) > 0 {
+		named = saved.tags[0]
+	} else if len(img.Names) > 0 {
+		if parsed, pErr := reference.ParseNormalizedNamed(img.Names[0]); pErr == nil {
+			named = parsed
+		}
+	}
+	if named != nil {
+		info.Name = reference.FamiliarName(named)
+		if tagged, ok := named.(reference.NamedTagged); ok {
+			info.Tag = tagged.Tag()
+		}
+	}
+
+	return info, nil
+}
+
+// heldImageIDs resolves every image of this save to its ID, the hold
+// protecting the save from a concurrent Remove works on IDs, and a
+// failure here reports an image that disappeared after the options
+// were checked
+func (opts *SaveOptions) heldImageIDs() ([]string, error) {
+	names := append([]string{}, opts.finalImageOrdered...)
+	names = append(names, opts.sep.ImageNames()...)
+	ids := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		_, img, err := image.FindImage(opts.localStore, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "image %q disappeared before saving started", name)
+		}
+		if !seen[img.ID] {
+			seen[img.ID] = true
+			ids = append(ids, img.ID)
+		}
+	}
+
+	return ids, nil
+}
+
 func messageHandler(stream pb.Control_SaveServer, cliLogger *logger.Logger) func() error {
 	return func() error {
 		for content := range cliLogger.GetContent() {
@@ -182,6 +406,9 @@ func (opts *SaveOptions) manage() error {
 	if err := opts.setFormat(); err != nil {
 		return err
 	}
+	if err := opts.parseAnnotations(); err != nil {
+		return err
+	}
 	if err := opts.filterImageName(); err != nil {
 		return err
 	}
@@ -209,6 +436,9 @@ func (opts *SaveOptions) checkImageNameIsID() error {
 }
 
 func (opts *SaveOptions) setFormat() error {
+	if err := util.CheckCompressionFormat(opts.compression); err != nil {
+		return err
+	}
 	switch opts.format {
 	case constant.DockerTransport:
 		opts.format = constant.DockerArchiveTransport
@@ -221,12 +451,30 @@ func (opts *SaveOptions) setFormat() error {
 	return nil
 }
 
-func (opts *SaveOptions) filterImageName() error {
-	if opts.format == constant.OCIArchiveTransport {
-		opts.finalImageOrdered = opts.oriImgList
+// parseAnnotations parses the requested key=value annotations, they
+// are recorded in the saved manifest, which only the oci format has
+func (opts *SaveOptions) parseAnnotations() error {
+	if len(opts.oriAnnotations) == 0 {
 		return nil
 	}
+	if opts.format != constant.OCIArchiveTransport {
+		return errors.Errorf("annotations are only supported by the oci image format")
+	}
+
+	const kvNums = 2
+	opts.annotations = make(map[string]string, len(opts.oriAnnotations))
+	for _, annotation := range opts.oriAnnotations {
+		kv := strings.SplitN(annotation, "=", kvNums)
+		if len(kv) != kvNums || kv[0] == "" {
+			return errors.Errorf("invalid annotation %q, expected key=value pair", annotation)
+		}
+		opts.annotations[kv[0]] = kv[1]
+	}
 
+	return nil
+}
+
+func (opts *SaveOptions) filterImageName() error {
 	visitedImage := make(map[string]bool, 1)
 	for _, imageName := range opts.oriImgList {
 		if _, exists := visitedImage[imageName]; exists {
@@ -14,15 +14,60 @@
 package daemon
 
 import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/builder"
+	"isula.org/isula-build/pkg/logger"
 )
 
 // status store the key info related to Build action
 type status struct {
 	// if building start, we notify Status rpc
 	startBuild chan struct{}
+	mu         sync.RWMutex
+	// lines buffers the log lines of the build, so a reconnecting
+	// Status stream can replay them from the offset it left off
+	lines []string
+	// done is set once the build stops producing log lines
+	done bool
+	// updated is closed and replaced on each append, readers wait on
+	// it for new lines
+	updated chan struct{}
+}
+
+// append adds a log line to the buffer and wakes the waiting readers
+func (s *status) append(line string) {
+	s.mu.Lock()
+	s.lines = append(s.lines, line)
+	close(s.updated)
+	s.updated = make(chan struct{})
+	s.mu.Unlock()
+}
+
+// finish marks the log buffer complete and wakes the waiting readers
+func (s *status) finish() {
+	s.mu.Lock()
+	if !s.done {
+		s.done = true
+		close(s.updated)
+	}
+	s.mu.Unlock()
+}
+
+// snapshot returns the log lines from the given offset on, whether the
+// buffer is complete, and the channel signalling the next update
+func (s *status) snapshot(offset int64) ([]string, bool, chan struct{}) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if offset > int64(len(s.lines)) {
+		offset = int64(len(s.lines))
+	}
+	return s.lines[offset:], s.done, s.updated
 }
 
 // Status gets build info from backend and send it to the front end
@@ -31,38 +76,104 @@ func (b *Backend) Status(req *pb.StatusRequest, stream pb.Control_StatusServer)
 		"BuildID": req.GetBuildID(),
 	}).Info("StatusRequest received")
 
-	// waiting for Build start first so that the builder with req.BuildID will be set already
-	select {
-	case <-b.syncBuildStatus(req.BuildID):
-	case <-stream.Context().Done():
-		b.deleteStatus(req.BuildID)
-		return nil
+	if req.GetFollow() {
+		// waiting for Build start first so that the builder with req.BuildID will be set already
+		select {
+		case <-b.syncBuildStatus(req.BuildID):
+		case <-stream.Context().Done():
+			b.deleteStatus(req.BuildID)
+			return nil
+		}
 	}
 
-	builder, err := b.daemon.Builder(req.BuildID)
-	if err != nil {
-		logrus.WithFields(logrus.Fields{
-			"BuildID": req.GetBuildID(),
-		}).Error(err)
-		return err
+	st := b.getStatus(req.BuildID)
+	if st == nil {
+		if req.GetFollow() {
+			// the build already finished and its status was released
+			return nil
+		}
+		return errors.Errorf("no build with id %q found", req.GetBuildID())
 	}
-	for value := range builder.StatusChan() {
-		if err := stream.Send(&pb.StatusResponse{Content: value}); err != nil {
-			logrus.WithFields(logrus.Fields{
-				"BuildID": req.GetBuildID(),
-			}).Error(err)
-			return err
+
+	// a slow build step can keep the stream idle for a long time,
+	// periodic empty responses keep NAT and proxy idle timers from
+	// silently dropping it, the client prints nothing for them
+	var heartbeat <-chan time.Time
+	if interval := b.daemon.keepaliveInterval(); interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	offset := req.GetStartOffset()
+	for {
+		lines, done, updated := st.snapshot(offset)
+		for _, line := range lines {
+			if err := stream.Send(&pb.StatusResponse{Content: line}); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"BuildID": req.GetBuildID(),
+				}).Error(err)
+				return err
+			}
+			offset++
+		}
+		if done || !req.GetFollow() {
+			return nil
+		}
+		select {
+		case <-updated:
+		case <-heartbeat:
+			if err := stream.Send(&pb.StatusResponse{}); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"BuildID": req.GetBuildID(),
+				}).Error(err)
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
 		}
 	}
+}
 
-	return nil
+// collectStatus drains the builder status channel and the session log
+// channel into the per-build log buffer, Status streams replay it from
+// any offset so a client can reconnect without losing lines
+func (b *Backend) collectStatus(buildID string, builder builder.Builder) {
+	st := b.getStatus(buildID)
+	if st == nil {
+		return
+	}
+	// the session log channel only exists when the client requested
+	// elevated verbosity for this build, nil otherwise
+	statusChan, sessionChan := builder.StatusChan(), logger.SessionChan(buildID)
+	for statusChan != nil || sessionChan != nil {
+		var value string
+		var ok bool
+		select {
+		case value, ok = <-statusChan:
+			if !ok {
+				statusChan = nil
+				continue
+			}
+		case value, ok = <-sessionChan:
+			if !ok {
+				sessionChan = nil
+				continue
+			}
+		}
+		st.append(value)
+	}
+	st.finish()
 }
 
 // syncBuildStatus ensure that Build action and Status action can be sync so that to avoid nil point error.
 func (b *Backend) syncBuildStatus(buildID string) chan struct{} {
 	b.Lock()
 	if _, ok := b.status[buildID]; !ok {
-		statusPerID := &status{startBuild: make(chan struct{})}
+		statusPerID := &status{
+			startBuild: make(chan struct{}),
+			updated:    make(chan struct{}),
+		}
 		b.status[buildID] = statusPerID
 	}
 	statusChan := b.status[buildID].startBuild
@@ -70,6 +181,12 @@ func (b *Backend) syncBuildStatus(buildID string) chan struct{} {
 	return statusChan
 }
 
+func (b *Backend) getStatus(buildID string) *status {
+	b.RLock()
+	defer b.RUnlock()
+	return b.status[buildID]
+}
+
 func (b *Backend) closeStatusChan(buildID string) {
 	b.Lock()
 	if _, ok := b.status[buildID]; ok {
@@ -0,0 +1,66 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-04-02
+// Description: build status log buffer related tests
+
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestStatusBufferReplay(t *testing.T) {
+	st := &status{
+		startBuild: make(chan struct{}),
+		updated:    make(chan struct{}),
+	}
+
+	st.append("line1\n")
+	st.append("line2\n")
+
+	lines, done, _ := st.snapshot(0)
+	assert.Equal(t, len(lines), 2)
+	assert.Equal(t, done, false)
+
+	// a reconnecting stream resumes from its offset
+	lines, _, _ = st.snapshot(1)
+	assert.Equal(t, len(lines), 1)
+	assert.Equal(t, lines[0], "line2\n")
+
+	// an offset beyond the buffer yields no lines instead of panicking
+	lines, _, _ = st.snapshot(10)
+	assert.Equal(t, len(lines), 0)
+
+	_, _, updated := st.snapshot(2)
+	go st.append("line3\n")
+	select {
+	case <-updated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("append did not wake the waiting reader")
+	}
+	lines, _, _ = st.snapshot(2)
+	assert.Equal(t, lines[0], "line3\n")
+
+	_, _, updated = st.snapshot(3)
+	st.finish()
+	select {
+	case <-updated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("finish did not wake the waiting reader")
+	}
+	_, done, _ = st.snapshot(3)
+	assert.Equal(t, done, true)
+	// finishing twice must be harmless
+	st.finish()
+}
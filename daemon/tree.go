@@ -0,0 +1,78 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is "tree" command for backend
+
+package daemon
+
+import (
+	"context"
+	"sort"
+
+	"github.com/containers/storage"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/image"
+)
+
+// Tree reports the layer chain of each local image from the base layer
+// up, with the diff size of every layer and how many images reference
+// it, so shared base layers and the space a removal would actually free
+// are visible
+func (b *Backend) Tree(ctx context.Context, req *pb.TreeRequest) (*pb.TreeResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"ImageName": req.GetImageName(),
+	}).Info("TreeRequest received")
+
+	s := b.daemon.localStore
+	sizes := newImageSizes(s)
+
+	var images []storage.Image
+	if req.GetImageName() != "" {
+		_, img, err := image.FindImage(s, req.GetImageName())
+		if err != nil {
+			return &pb.TreeResponse{}, errors.Wrapf(err, "find local image %q failed", req.GetImageName())
+		}
+		images = []storage.Image{*img}
+	} else {
+		var err error
+		images, err = s.Images()
+		if err != nil {
+			return &pb.TreeResponse{}, errors.Wrap(err, "failed list images from local storage")
+		}
+		sort.Slice(images, func(i, j int) bool {
+			return images[i].Created.After(images[j].Created)
+		})
+	}
+
+	resp := &pb.TreeResponse{Images: make([]*pb.TreeResponse_Image, 0, len(images))}
+	for i := range images {
+		entry := &pb.TreeResponse_Image{
+			RepoTags: images[i].Names,
+			Id:       images[i].ID,
+		}
+		chain := sizes.chain(images[i].TopLayer)
+		// the chain walk runs top down, the tree shows base to top
+		for j := len(chain) - 1; j >= 0; j-- {
+			id := chain[j]
+			entry.Layers = append(entry.Layers, &pb.TreeResponse_Layer{
+				Id:       id,
+				Size_:    formatImageSize(sizes.layers[id].UncompressedSize),
+				RefCount: int32(sizes.refs[id]),
+			})
+		}
+		resp.Images = append(resp.Images, entry)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is "verify-remote" command for backend
+
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/containers/image/v5/docker"
+	ci "github.com/containers/image/v5/image"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	pb "isula.org/isula-build/api/services"
+	"isula.org/isula-build/image"
+)
+
+// verifyRemoteLayer compares one layer of the local image with the
+// layer at the same position of the registry copy
+type verifyRemoteLayer struct {
+	LocalDiffID  string `json:"localDiffID,omitempty"`
+	RemoteDiffID string `json:"remoteDiffID,omitempty"`
+	Match        bool   `json:"match"`
+}
+
+// verifyRemoteInfo is the comparison result between the local image
+// and its registry copy, assembled from the two configs only
+type verifyRemoteInfo struct {
+	LocalImage   string              `json:"localImage"`
+	RemoteRef    string              `json:"remoteRef"`
+	LocalConfig  string              `json:"localConfig"`
+	RemoteConfig string              `json:"remoteConfig"`
+	ConfigMatch  bool                `json:"configMatch"`
+	Layers       []verifyRemoteLayer `json:"layers"`
+	Match        bool                `json:"match"`
+}
+
+// VerifyRemote compares the layer diffIDs and the config of a local image
+// with its copy in a registry, only the remote manifest and config are
+// fetched for it, the remote layers stay in the registry
+func (b *Backend) VerifyRemote(ctx context.Context, req *pb.VerifyRemoteRequest) (*pb.VerifyRemoteResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"LocalImage": req.GetLocalImage(),
+		"RemoteRef":  req.GetRemoteRef(),
+	}).Info("VerifyRemoteRequest received")
+
+	localRef, _, err := image.FindImage(b.daemon.localStore, req.GetLocalImage())
+	if err != nil {
+		return &pb.VerifyRemoteResponse{}, errors.Wrapf(err, "find local image %q failed", req.GetLocalImage())
+	}
+	localImg, err := localRef.NewImage(ctx, image.GetSystemContext())
+	if err != nil {
+		return &pb.VerifyRemoteResponse{}, errors.Wrapf(err, "parse local image %q failed", req.GetLocalImage())
+	}
+	defer func() {
+		if cErr := localImg.Close(); cErr != nil {
+			logrus.Warnf("Local image closing error: %v", cErr)
+		}
+	}()
+	localConfig, err := localImg.OCIConfig(ctx)
+	if err != nil {
+		return &pb.VerifyRemoteResponse{}, errors.Wrapf(err, "read config of local image %q failed", req.GetLocalImage())
+	}
+
+	remoteRef, err := docker.ParseReference("//" + req.GetRemoteRef())
+	if err != nil {
+		return &pb.VerifyRemoteResponse{}, errors.Wrapf(err, "parse remote reference %q failed", req.GetRemoteRef())
+	}
+	src, err := remoteRef.NewImageSource(ctx, image.GetSystemContext())
+	if err != nil {
+		return &pb.VerifyRemoteResponse{}, errors.Wrapf(err, "create image source for %q failed", req.GetRemoteRef())
+	}
+	defer func() {
+		if cErr := src.Close(); cErr != nil {
+			logrus.Warnf("Image source closing error: %v", cErr)
+		}
+	}()
+
+	// the instance matching the daemon platform is picked from a list
+	remoteImg, err := ci.FromUnparsedImage(ctx, image.GetSystemContext(), ci.UnparsedInstance(src, nil))
+	if err != nil {
+		return &pb.VerifyRemoteResponse{}, errors.Wrapf(err, "parse manifest of %q failed", req.GetRemoteRef())
+	}
+	remoteConfig, err := remoteImg.OCIConfig(ctx)
+	if err != nil {
+		return &pb.VerifyRemoteResponse{}, errors.Wrapf(err, "read config of %q failed", req.GetRemoteRef())
+	}
+
+	info := compareImageConfigs(req.GetLocalImage(), req.GetRemoteRef(), localConfig, remoteConfig)
+	info.LocalConfig = localImg.ConfigInfo().Digest.String()
+	info.RemoteConfig = remoteImg.ConfigInfo().Digest.String()
+	info.ConfigMatch = info.LocalConfig != "" && info.LocalConfig == info.RemoteConfig
+	info.Match = info.Match && info.ConfigMatch
+
+	data, err := json.MarshalIndent(info, "", "    ")
+	if err != nil {
+		return &pb.VerifyRemoteResponse{}, errors.Wrap(err, "marshal verify result failed")
+	}
+
+	return &pb.VerifyRemoteResponse{Data: string(data)}, nil
+}
+
+// compareImageConfigs matches up the layer diffIDs of the two configs
+func compareImageConfigs(localImage, remoteRef string, localConfig, remoteConfig *imgspecv1.Image) verifyRemoteInfo {
+	info := verifyRemoteInfo{
+		LocalImage: localImage,
+		RemoteRef:  remoteRef,
+		Match:      true,
+	}
+	localDiffIDs := localConfig.RootFS.DiffIDs
+	remoteDiffIDs := remoteConfig.RootFS.DiffIDs
+	layerCnt := len(localDiffIDs)
+	if len(remoteDiffIDs) > layerCnt {
+		layerCnt = len(remoteDiffIDs)
+	}
+	for i := 0; i < layerCnt; i++ {
+		layer := verifyRemoteLayer{}
+		if i < len(localDiffIDs) {
+			layer.LocalDiffID = localDiffIDs[i].String()
+		}
+		if i < len(remoteDiffIDs) {
+			layer.RemoteDiffID = remoteDiffIDs[i].String()
+		}
+		layer.Match = layer.LocalDiffID != "" && layer.LocalDiffID == layer.RemoteDiffID
+		if !layer.Match {
+			info.Match = false
+		}
+		info.Layers = append(info.Layers, layer)
+	}
+
+	return info
+}
@@ -0,0 +1,60 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: verify-remote backend test
+
+package daemon
+
+import (
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestCompareImageConfigs(t *testing.T) {
+	diffA := digest.Digest("sha256:e692418e4cbaf90ca69d05a66403747baa33ee08806650b51fab815ad7fc331f")
+	diffB := digest.Digest("sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270")
+	diffC := digest.Digest("sha256:2bf01635e2a0f7ed3800c8cb3effc5ff46adc6b9b86f0e80743c956371efe553")
+
+	configWith := func(diffIDs ...digest.Digest) *imgspecv1.Image {
+		return &imgspecv1.Image{
+			RootFS: imgspecv1.RootFS{
+				Type:    "layers",
+				DiffIDs: diffIDs,
+			},
+		}
+	}
+
+	// identical layers match
+	info := compareImageConfigs("app:latest", "registry.example.com/app:latest",
+		configWith(diffA, diffB), configWith(diffA, diffB))
+	assert.Equal(t, info.Match, true)
+	assert.Equal(t, len(info.Layers), 2)
+	assert.Equal(t, info.Layers[0].Match, true)
+	assert.Equal(t, info.Layers[1].Match, true)
+
+	// one differing layer breaks the match
+	info = compareImageConfigs("app:latest", "registry.example.com/app:latest",
+		configWith(diffA, diffB), configWith(diffA, diffC))
+	assert.Equal(t, info.Match, false)
+	assert.Equal(t, info.Layers[0].Match, true)
+	assert.Equal(t, info.Layers[1].Match, false)
+
+	// a missing layer is reported with an empty diffID
+	info = compareImageConfigs("app:latest", "registry.example.com/app:latest",
+		configWith(diffA), configWith(diffA, diffB))
+	assert.Equal(t, info.Match, false)
+	assert.Equal(t, len(info.Layers), 2)
+	assert.Equal(t, info.Layers[1].LocalDiffID, "")
+	assert.Equal(t, info.Layers[1].RemoteDiffID, diffB.String())
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-07-12
+// Description: remote build cache manifest
+
+package exporter
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// CacheManifestSchemaVersion is the schema version written into new
+	// cache manifests, parsing rejects manifests with a newer version
+	CacheManifestSchemaVersion = 1
+	// CacheManifestFileName is the file name the cache manifest is
+	// written to under the build cache directory
+	CacheManifestFileName = "cache-manifest.json"
+	// cacheTagPrefix marks the registry tags holding cache entries
+	cacheTagPrefix = "cache-"
+	// cacheTagKeyLen is how much of the cache key goes into the tag
+	cacheTagKeyLen = 16
+)
+
+// CacheManifestRecord maps one cache key to the registry reference its
+// cached image was pushed to
+type CacheManifestRecord struct {
+	// CacheKey is the content key of the cached stage
+	CacheKey string `json:"cacheKey"`
+	// Reference is the registry reference the cached image was pushed to
+	Reference string `json:"reference"`
+	// CreatedAt is the time the entry was exported
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CacheManifest describes the build cache entries exported to a registry,
+// CI runners with ephemeral storage carry it between jobs to find them again
+type CacheManifest struct {
+	SchemaVersion int                   `json:"schemaVersion"`
+	Records       []CacheManifestRecord `json:"records"`
+}
+
+// CacheReference returns the registry reference a cache entry with the
+// given key is pushed to within the cache repository
+func CacheReference(repository, cacheKey string) string {
+	key := cacheKey
+	if len(key) > cacheTagKeyLen {
+		key = key[:cacheTagKeyLen]
+	}
+	return repository + ":" + cacheTagPrefix + key
+}
+
+// Marshal serializes the cache manifest with the current schema version
+func (m *CacheManifest) Marshal() ([]byte, error) {
+	m.SchemaVersion = CacheManifestSchemaVersion
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal cache manifest failed")
+	}
+	return data, nil
+}
+
+// ParseCacheManifest deserializes a cache manifest and rejects unknown
+// schema versions
+func ParseCacheManifest(data []byte) (*CacheManifest, error) {
+	var m CacheManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.Wrap(err, "parse cache manifest failed")
+	}
+	if m.SchemaVersion > CacheManifestSchemaVersion {
+		return nil, errors.Errorf("unsupported cache manifest schema version %d", m.SchemaVersion)
+	}
+	return &m, nil
+}
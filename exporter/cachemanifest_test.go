@@ -0,0 +1,49 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-07-12
+// Description: remote build cache manifest tests
+
+package exporter
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCacheReference(t *testing.T) {
+	ref := CacheReference("registry.example.com/project/cache", "0123456789abcdef0123456789abcdef")
+	assert.Equal(t, ref, "registry.example.com/project/cache:cache-0123456789abcdef")
+
+	// short keys are used as they are
+	ref = CacheReference("localhost:5000/cache", "abc")
+	assert.Equal(t, ref, "localhost:5000/cache:cache-abc")
+}
+
+func TestCacheManifestRoundTrip(t *testing.T) {
+	cacheManifest := CacheManifest{
+		Records: []CacheManifestRecord{
+			{CacheKey: "key1", Reference: "localhost:5000/cache:cache-key1", CreatedAt: time.Now()},
+		},
+	}
+	data, err := cacheManifest.Marshal()
+	assert.NilError(t, err)
+
+	parsed, err := ParseCacheManifest(data)
+	assert.NilError(t, err)
+	assert.Equal(t, parsed.SchemaVersion, CacheManifestSchemaVersion)
+	assert.Equal(t, len(parsed.Records), 1)
+	assert.Equal(t, parsed.Records[0].Reference, "localhost:5000/cache:cache-key1")
+
+	_, err = ParseCacheManifest([]byte(`{"schemaVersion": 99}`))
+	assert.ErrorContains(t, err, "unsupported cache manifest schema version")
+}
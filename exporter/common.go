@@ -25,8 +25,10 @@ import (
 
 	cp "github.com/containers/image/v5/copy"
 	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/pkg/compression"
 	"github.com/containers/image/v5/signature"
 	"github.com/containers/image/v5/types"
+	encconfig "github.com/containers/ocicrypt/config"
 	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/docker/distribution/reference"
 	"github.com/opencontainers/go-digest"
@@ -35,6 +37,8 @@ import (
 	"github.com/sirupsen/logrus"
 
 	constant "isula.org/isula-build"
+	"isula.org/isula-build/pkg/encrypt"
+	"isula.org/isula-build/pkg/signer"
 	"isula.org/isula-build/store"
 	"isula.org/isula-build/util"
 )
@@ -48,6 +52,18 @@ type ExportOptions struct {
 	ExportID           string
 	ManifestType       string
 	ImageListSelection cp.ImageListSelection
+	Platform           *PlatformOverride
+	Annotations        map[string]string
+	// CompressionFormat is the layer compression of the exported image,
+	// one of gzip, zstd, zstd:chunked or none, empty or none keeps the
+	// transport default instead of forcing a format
+	CompressionFormat string
+	// SignBy asks the copy to attach a simple-signing signature with
+	// this gpg key, empty pushes unsigned
+	SignBy string
+	// EncryptConfig encrypts every layer for its recipients during the
+	// copy, nil exports plain layers
+	EncryptConfig *encconfig.EncryptConfig
 }
 
 // parseExporter parses an exporter instance and inits it with the src and dest reference.
@@ -78,6 +94,40 @@ func Export(imageID, outputDest string, opts ExportOptions, localStore *store.St
 	if outputDest == "" {
 		return nil
 	}
+	// the disk image and squashfs outputs flatten the rootfs instead of
+	// copying the image between references, so they do not run the copy pipeline
+	if strings.HasPrefix(outputDest, constant.DiskImageTransport+":") {
+		if err := exportDiskImage(imageID, outputDest, opts, localStore); err != nil {
+			return errors.Wrapf(err, "export image from %s to %s failed", imageID, outputDest)
+		}
+		eLog.Infof("Successfully output disk image for %s", imageID)
+		return nil
+	}
+	if strings.HasPrefix(outputDest, constant.SquashfsTransport+":") {
+		if err := exportSquashfs(imageID, outputDest, opts, localStore); err != nil {
+			return errors.Wrapf(err, "export image from %s to %s failed", imageID, outputDest)
+		}
+		eLog.Infof("Successfully output squashfs image for %s", imageID)
+		return nil
+	}
+	// "local" is the buildkit-style alias of the sysroot output, both
+	// unpack the image rootfs into a host directory
+	if strings.HasPrefix(outputDest, constant.SysrootTransport+":") || strings.HasPrefix(outputDest, constant.LocalTransport+":") {
+		if err := exportSysroot(imageID, outputDest, opts, localStore); err != nil {
+			return errors.Wrapf(err, "export image from %s to %s failed", imageID, outputDest)
+		}
+		eLog.Infof("Successfully output sysroot for %s", imageID)
+		return nil
+	}
+	// the estargz output rebuilds every layer before pushing, so it runs
+	// its own copy pipeline on the converted layout
+	if strings.HasPrefix(outputDest, constant.EStargzTransport+":") {
+		if err := exportEStargz(imageID, outputDest, opts, localStore); err != nil {
+			return errors.Wrapf(err, "export image from %s to %s failed", imageID, outputDest)
+		}
+		eLog.Infof("Successfully output estargz image for %s", imageID)
+		return nil
+	}
 	epter, err := parseExporter(opts, imageID, outputDest, localStore)
 	if err != nil {
 		return err
@@ -93,6 +143,23 @@ func Export(imageID, outputDest string, opts ExportOptions, localStore *store.St
 		return err
 	}
 
+	// only pushes to a registry get signed, the signing configuration of
+	// the destination registry picks the method
+	signConf := signer.Config{}
+	if strings.HasPrefix(outputDest, constant.DockerTransport+"://") {
+		signConf = signer.For(registry)
+		if signConf.Method == constant.SignMethodGPG {
+			opts.SignBy = signConf.Key
+		}
+		// only pushes to a registry encrypt layers, the local outputs
+		// stay plain so they keep loading everywhere
+		encConf, eErr := encrypt.EncryptConfig()
+		if eErr != nil {
+			return errors.Wrapf(eErr, "export image from %s to %s failed", imageID, outputDest)
+		}
+		opts.EncryptConfig = encConf
+	}
+
 	ref, digest, err := export(epter, opts)
 	if err != nil {
 		return errors.Wrapf(err, "export image from %s to %s failed", imageID, outputDest)
@@ -100,6 +167,12 @@ func Export(imageID, outputDest string, opts ExportOptions, localStore *store.St
 	if ref != nil {
 		eLog.Debugf("Export image with reference %s", ref.Name())
 	}
+	if signConf.Method == constant.SignMethodCosign && ref != nil {
+		if sErr := signer.SignCosign(opts.Ctx, signConf.Key, ref.String()); sErr != nil {
+			return errors.Wrapf(sErr, "export image from %s to %s failed", imageID, outputDest)
+		}
+		eLog.Infof("Successfully signed %s with cosign", ref.String())
+	}
 	eLog.Infof("Successfully output image with digest %s", digest.String())
 
 	return nil
@@ -130,8 +203,13 @@ func export(e Exporter, exOpts ExportOptions) (reference.Canonical, digest.Diges
 	if destRef == nil || srcRef == nil {
 		return nil, "", errors.Errorf("get dest or src reference by export ID %v failed %v", exOpts.ExportID, err)
 	}
+	srcRef = newOverrideReference(srcRef, exOpts.Platform, exOpts.Annotations)
 
-	if manifestBytes, err = cp.Image(exOpts.Ctx, policyContext, destRef, srcRef, cpOpts); err != nil {
+	if err = util.CopyWithRetry(exOpts.Ctx, fmt.Sprintf("exporting %q", exOpts.ExportID), func() error {
+		var cErr error
+		manifestBytes, cErr = cp.Image(exOpts.Ctx, policyContext, destRef, srcRef, cpOpts)
+		return cErr
+	}); err != nil {
 		return nil, "", errors.Wrap(err, "copying layers and metadata failed")
 	}
 	if manifestDigest, err = manifest.Digest(manifestBytes); err != nil {
@@ -150,6 +228,11 @@ func export(e Exporter, exOpts ExportOptions) (reference.Canonical, digest.Diges
 			return nil, "", errors.Wrapf(err, "export to isulad failed")
 		}
 	}
+	if f, ok := e.(Finisher); ok {
+		if fErr := f.Finish(exOpts.ExportID); fErr != nil {
+			return nil, "", fErr
+		}
+	}
 
 	return ref, manifestDigest, nil
 }
@@ -159,9 +242,32 @@ func NewCopyOptions(opts ExportOptions) *cp.Options {
 	cpOpts := &cp.Options{}
 	cpOpts.SourceCtx = opts.SystemContext
 	cpOpts.DestinationCtx = opts.SystemContext
+	if algo := compressionAlgorithm(opts.CompressionFormat); algo != nil {
+		// copy the system context so the forced compression stays
+		// local to the destination side of this export
+		destCtx := *opts.SystemContext
+		destCtx.CompressionFormat = algo
+		cpOpts.DestinationCtx = &destCtx
+	}
 	cpOpts.ReportWriter = opts.ReportWriter
 	cpOpts.ForceManifestMIMEType = opts.ManifestType
+	cpOpts.SignBy = opts.SignBy
+	if opts.EncryptConfig != nil {
+		allLayers := []int{}
+		cpOpts.OciEncryptConfig = opts.EncryptConfig
+		cpOpts.OciEncryptLayers = &allLayers
+	}
 	cpOpts.ImageListSelection = opts.ImageListSelection
+	// exports move blobs towards the destination, so the upload
+	// parallelism applies, 0 keeps the library default
+	cpOpts.MaxParallelDownloads = util.MaxParallelUploads()
+
+	// content addressed with a non-canonical local digest algorithm must
+	// be converted on export, registries require the canonical algorithm
+	if util.DigestAlgorithm() != digest.Canonical && cpOpts.ForceManifestMIMEType == "" {
+		logrus.Debugf("Converting content addressed with %s to %s for export", util.DigestAlgorithm(), digest.Canonical)
+		cpOpts.ForceManifestMIMEType = manifest.DockerV2Schema2MediaType
+	}
 
 	return cpOpts
 }
@@ -200,6 +306,23 @@ func exportToIsulad(ctx context.Context, tarPath string) error {
 	return nil
 }
 
+// compressionAlgorithm maps a compression name accepted by
+// util.CheckCompressionFormat to the algorithm forced on export, nil
+// keeps the transport default
+func compressionAlgorithm(format string) *compression.Algorithm {
+	switch format {
+	case constant.CompressionGzip, constant.CompressionZstd, constant.CompressionZstdChunked:
+		algo, err := compression.AlgorithmByName(format)
+		if err != nil {
+			logrus.Warnf("Compression %q is not available, the transport default is used: %v", format, err)
+			return nil
+		}
+		return &algo
+	default:
+		return nil
+	}
+}
+
 // CheckArchiveFormat used to check if save or load image format is either docker-archive or oci-archive
 func CheckArchiveFormat(format string) error {
 	switch format {
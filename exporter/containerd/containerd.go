@@ -0,0 +1,200 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: containerd exporter related functions
+
+// Package containerd is used to export images to containerd
+package containerd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/containers/image/v5/transports/alltransports"
+	"github.com/containers/image/v5/types"
+	"github.com/containers/storage/pkg/stringid"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/exporter"
+	"isula.org/isula-build/image"
+	"isula.org/isula-build/store"
+)
+
+func init() {
+	exporter.Register(&_containerdExporter)
+}
+
+// importTask tracks the ctr process draining the import fifo, so the
+// export can wait for it after the image copy completes
+type importTask struct {
+	cmd      *exec.Cmd
+	fifoPath string
+	output   bytes.Buffer
+}
+
+type containerdExporter struct {
+	items   map[string]exporter.Bus
+	imports map[string]*importTask
+	sync.RWMutex
+}
+
+var _containerdExporter = containerdExporter{
+	items:   make(map[string]exporter.Bus),
+	imports: make(map[string]*importTask),
+}
+
+func (d *containerdExporter) Name() string {
+	return constant.ContainerdTransport
+}
+
+func (d *containerdExporter) Init(opts exporter.ExportOptions, src, destSpec string, localStore *store.Store) error {
+	// For containerd, Init is only called from build command.
+	// src is form of ImageID digest, destSpec is form of
+	// containerd:image:tag or containerd:namespace:image:tag
+	const partsNum, partsWithNamespaceNum = 3, 4
+	parts := strings.Split(destSpec, ":")
+	namespace := constant.DefaultContainerdNamespace
+	switch len(parts) {
+	case partsNum:
+	case partsWithNamespaceNum:
+		namespace = parts[1]
+		parts = append(parts[:1], parts[2:]...)
+	default:
+		return errors.Errorf(`invalid dest spec %q, expected %s:[namespace:]image:tag`, destSpec, d.Name())
+	}
+	if strings.TrimSpace(namespace) == "" {
+		return errors.Errorf(`invalid dest spec %q, namespace should not be empty`, destSpec)
+	}
+
+	srcReference, _, err := image.FindImage(localStore, src)
+	if err != nil {
+		return errors.Wrapf(err, "find src image: %q failed with transport %q", src, d.Name())
+	}
+
+	randomID := stringid.GenerateNonCryptoID()[:constant.DefaultIDLen]
+	fifoPath, err := securejoin.SecureJoin(opts.DataDir, fmt.Sprintf("containerd-import-%s.tar", randomID))
+	if err != nil {
+		return err
+	}
+	// containerd ingests the image stream while the copy produces it, so
+	// the import needs no intermediate tarball on disk
+	if err = unix.Mkfifo(fifoPath, constant.DefaultRootFileMode); err != nil {
+		return errors.Wrapf(err, "create import fifo for transport %q failed", d.Name())
+	}
+
+	// construct format: transport:path:image:tag
+	destSpec = fmt.Sprintf("docker-archive:%s:%s", fifoPath, strings.Join(parts[1:], ":"))
+	logrus.Infof("Process containerd output %s with namespace %s", destSpec, namespace)
+	destReference, err := alltransports.ParseImageName(destSpec)
+	if err != nil {
+		removeFifo(fifoPath)
+		return errors.Wrapf(err, "parse dest spec: %q failed with transport %q", destSpec, d.Name())
+	}
+
+	task := &importTask{fifoPath: fifoPath}
+	// the arguments here will not be influenced by external input, no security risk
+	task.cmd = exec.CommandContext(opts.Ctx, "ctr", "--namespace", namespace, "images", "import", fifoPath) // nolint:gosec
+	task.cmd.Stdout = &task.output
+	task.cmd.Stderr = &task.output
+	if err = task.cmd.Start(); err != nil {
+		removeFifo(fifoPath)
+		return errors.Wrap(err, "start ctr to import image failed")
+	}
+
+	d.Lock()
+	d.items[opts.ExportID] = exporter.Bus{
+		SrcRef:  srcReference,
+		DestRef: destReference,
+	}
+	d.imports[opts.ExportID] = task
+	d.Unlock()
+
+	return nil
+}
+
+func (d *containerdExporter) GetSrcRef(exportID string) types.ImageReference {
+	d.RLock()
+	defer d.RUnlock()
+
+	if _, ok := d.items[exportID]; ok {
+		return d.items[exportID].SrcRef
+	}
+
+	return nil
+}
+
+func (d *containerdExporter) GetDestRef(exportID string) types.ImageReference {
+	d.RLock()
+	defer d.RUnlock()
+
+	if _, ok := d.items[exportID]; ok {
+		return d.items[exportID].DestRef
+	}
+
+	return nil
+}
+
+// Finish waits for the ctr process which drains the fifo the image copy
+// just finished writing, so a failed import fails the export
+func (d *containerdExporter) Finish(exportID string) error {
+	d.Lock()
+	task, ok := d.imports[exportID]
+	delete(d.imports, exportID)
+	d.Unlock()
+
+	if !ok {
+		return nil
+	}
+	defer removeFifo(task.fifoPath)
+	if err := task.cmd.Wait(); err != nil {
+		logrus.Errorf("Import image to containerd failed, output: %v, err: %v", task.output.String(), err)
+		return errors.Errorf("import image to containerd failed, output: %v, err: %v", task.output.String(), err)
+	}
+
+	return nil
+}
+
+func (d *containerdExporter) Remove(exportID string) {
+	d.Lock()
+	task, ok := d.imports[exportID]
+	delete(d.items, exportID)
+	delete(d.imports, exportID)
+	d.Unlock()
+
+	if !ok {
+		return
+	}
+	// the copy failed before the import completed, stop the ctr process
+	// still blocked on the fifo
+	if task.cmd.Process != nil {
+		if kErr := task.cmd.Process.Kill(); kErr != nil {
+			logrus.Warnf("Kill blocked ctr import failed: %v", kErr)
+		}
+	}
+	if wErr := task.cmd.Wait(); wErr != nil {
+		logrus.Debugf("Blocked ctr import exited: %v", wErr)
+	}
+	removeFifo(task.fifoPath)
+}
+
+func removeFifo(fifoPath string) {
+	if rErr := os.Remove(fifoPath); rErr != nil && !os.IsNotExist(rErr) {
+		logrus.Errorf("Remove import fifo %s failed: %v", fifoPath, rErr)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-05-25
+// Description: disk image export related functions
+
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/containers/storage/pkg/stringid"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/store"
+	"isula.org/isula-build/util"
+)
+
+const (
+	// diskImageFormatExt4 writes the rootfs into a raw ext4 image
+	diskImageFormatExt4 = "ext4"
+	// diskImageFormatQcow2 writes the rootfs into a qcow2 image
+	diskImageFormatQcow2 = "qcow2"
+	// diskImageOverheadRatio reserves filesystem metadata space when
+	// the image size is derived from the rootfs size
+	diskImageOverheadRatio = 5
+	// diskImageMinPadding is additionally reserved for small images
+	diskImageMinPadding = 32 * 1024 * 1024
+)
+
+// diskImageSpec is one parsed diskimage output destination
+type diskImageSpec struct {
+	// path is the absolute path the image is written to
+	path string
+	// format is ext4 or qcow2
+	format string
+	// size is the raw image size in bytes, 0 derives it from the rootfs
+	size int64
+	// label is the filesystem label, may be empty
+	label string
+}
+
+// parseDiskImageSpec parses a destination like
+// diskimage:/path/rootfs.img:qcow2,size=2G,label=root
+func parseDiskImageSpec(destSpec string) (diskImageSpec, error) {
+	const partsNum = 3
+	parts := strings.SplitN(destSpec, ":", partsNum)
+	if len(parts) < partsNum-1 || strings.TrimSpace(parts[1]) == "" {
+		return diskImageSpec{}, errors.Errorf("invalid dest spec %q, expected %s:path[:format,key=value]",
+			destSpec, constant.DiskImageTransport)
+	}
+
+	spec := diskImageSpec{path: parts[1], format: diskImageFormatExt4}
+	if len(parts) < partsNum {
+		return spec, nil
+	}
+	for i, option := range strings.Split(parts[2], ",") {
+		switch {
+		case i == 0 && (option == diskImageFormatExt4 || option == diskImageFormatQcow2):
+			spec.format = option
+		case strings.HasPrefix(option, "size="):
+			size, err := units.RAMInBytes(strings.TrimPrefix(option, "size="))
+			if err != nil || size <= 0 {
+				return diskImageSpec{}, errors.Errorf("invalid disk image size %q", option)
+			}
+			spec.size = size
+		case strings.HasPrefix(option, "label="):
+			spec.label = strings.TrimPrefix(option, "label=")
+		default:
+			return diskImageSpec{}, errors.Errorf("unknown disk image option %q", option)
+		}
+	}
+
+	return spec, nil
+}
+
+// exportDiskImage writes the flattened rootfs of imageID into an ext4
+// or qcow2 disk image, mounting the image the same way a build
+// container would see it
+func exportDiskImage(imageID, destSpec string, opts ExportOptions, localStore *store.Store) error {
+	spec, err := parseDiskImageSpec(destSpec)
+	if err != nil {
+		return err
+	}
+	if spec.format == diskImageFormatQcow2 {
+		if _, lErr := exec.LookPath("qemu-img"); lErr != nil {
+			return errors.Wrap(lErr, "exporting a qcow2 image needs the qemu-img tool")
+		}
+	}
+	if _, lErr := exec.LookPath("mkfs.ext4"); lErr != nil {
+		return errors.Wrap(lErr, "exporting a disk image needs the mkfs.ext4 tool")
+	}
+
+	img, err := localStore.Image(imageID)
+	if err != nil {
+		return errors.Wrapf(err, "find image %q for disk image export failed", imageID)
+	}
+
+	container, err := localStore.CreateContainer("", nil, img.ID, "", "", nil)
+	if err != nil {
+		return errors.Wrap(err, "create working container for disk image export failed")
+	}
+	defer func() {
+		if cErr := localStore.CleanContainer(container.ID); cErr != nil {
+			logrus.Warnf("Clean disk image export container %q failed: %v", container.ID, cErr)
+		}
+	}()
+	mountPoint, err := localStore.Mount(container.ID, "")
+	if err != nil {
+		return errors.Wrap(err, "mount rootfs for disk image export failed")
+	}
+
+	size := spec.size
+	if size == 0 {
+		rootfsSize, sErr := util.GetDirSize(mountPoint)
+		if sErr != nil {
+			return errors.Wrap(sErr, "size rootfs for disk image export failed")
+		}
+		size = rootfsSize + rootfsSize/diskImageOverheadRatio + diskImageMinPadding
+	}
+
+	rawPath := spec.path
+	if spec.format == diskImageFormatQcow2 {
+		randomID := stringid.GenerateNonCryptoID()[:constant.DefaultIDLen]
+		if rawPath, err = securejoin.SecureJoin(opts.DataDir, fmt.Sprintf("isula-build-tmp-%s.raw", randomID)); err != nil {
+			return err
+		}
+		defer func() {
+			if rErr := os.Remove(rawPath); rErr != nil && !os.IsNotExist(rErr) {
+				logrus.Warnf("Remove temporary raw image %q failed: %v", rawPath, rErr)
+			}
+		}()
+	}
+
+	if err = makeExt4Image(opts.Ctx, mountPoint, rawPath, size, spec.label); err != nil {
+		return err
+	}
+	if spec.format == diskImageFormatQcow2 {
+		if err = convertToQcow2(opts.Ctx, rawPath, spec.path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// makeExt4Image creates a raw ext4 image at path populated with the rootfs dir
+func makeExt4Image(ctx context.Context, rootfsDir, path string, size int64, label string) error {
+	imgFile, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, constant.DefaultRootFileMode)
+	if err != nil {
+		return errors.Wrap(err, "create disk image file failed")
+	}
+	if err = imgFile.Truncate(size); err != nil {
+		imgFile.Close() // nolint:errcheck
+		return errors.Wrap(err, "allocate disk image file failed")
+	}
+	if err = imgFile.Close(); err != nil {
+		return err
+	}
+
+	args := []string{"-F", "-q", "-d", rootfsDir}
+	if label != "" {
+		args = append(args, "-L", label)
+	}
+	args = append(args, path)
+	// the arguments are built from the validated output spec, no security risk
+	cmd := exec.CommandContext(ctx, "mkfs.ext4", args...) // nolint:gosec
+	if out, mErr := cmd.CombinedOutput(); mErr != nil {
+		if rErr := os.Remove(path); rErr != nil {
+			logrus.Warnf("Remove unfinished disk image %q failed: %v", path, rErr)
+		}
+		return errors.Errorf("make ext4 image failed, output: %s, err: %v", string(out), mErr)
+	}
+
+	return nil
+}
+
+// convertToQcow2 converts the raw image at rawPath into a qcow2 image at path
+func convertToQcow2(ctx context.Context, rawPath, path string) error {
+	// the arguments are built from the validated output spec, no security risk
+	cmd := exec.CommandContext(ctx, "qemu-img", "convert", "-f", "raw", "-O", "qcow2", rawPath, path) // nolint:gosec
+	if out, cErr := cmd.CombinedOutput(); cErr != nil {
+		if rErr := os.Remove(path); rErr != nil && !os.IsNotExist(rErr) {
+			logrus.Warnf("Remove unfinished disk image %q failed: %v", path, rErr)
+		}
+		return errors.Errorf("convert raw image to qcow2 failed, output: %s, err: %v", string(out), cErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-05-25
+// Description: disk image export test
+
+package exporter
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseDiskImageSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		destSpec string
+		wantErr  string
+		want     diskImageSpec
+	}{
+		{
+			name:     "path only defaults to ext4",
+			destSpec: "diskimage:/tmp/rootfs.img",
+			want:     diskImageSpec{path: "/tmp/rootfs.img", format: "ext4"},
+		},
+		{
+			name:     "qcow2 with size and label",
+			destSpec: "diskimage:/tmp/rootfs.img:qcow2,size=2G,label=root",
+			want:     diskImageSpec{path: "/tmp/rootfs.img", format: "qcow2", size: 2 * 1024 * 1024 * 1024, label: "root"},
+		},
+		{
+			name:     "options without explicit format",
+			destSpec: "diskimage:/tmp/rootfs.img:size=512M",
+			want:     diskImageSpec{path: "/tmp/rootfs.img", format: "ext4", size: 512 * 1024 * 1024},
+		},
+		{
+			name:     "empty path",
+			destSpec: "diskimage:",
+			wantErr:  "invalid dest spec",
+		},
+		{
+			name:     "invalid size",
+			destSpec: "diskimage:/tmp/rootfs.img:size=abc",
+			wantErr:  "invalid disk image size",
+		},
+		{
+			name:     "negative size",
+			destSpec: "diskimage:/tmp/rootfs.img:size=-1G",
+			wantErr:  "invalid disk image size",
+		},
+		{
+			name:     "unknown option",
+			destSpec: "diskimage:/tmp/rootfs.img:vfat",
+			wantErr:  "unknown disk image option",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDiskImageSpec(tt.destSpec)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
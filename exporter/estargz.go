@@ -0,0 +1,305 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: eStargz export related functions
+
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/stargz-snapshotter/estargz"
+	cp "github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/pkg/compression"
+	is "github.com/containers/image/v5/storage"
+	"github.com/containers/image/v5/types"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/opencontainers/go-digest"
+	imgspec "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/store"
+	"isula.org/isula-build/util"
+)
+
+// estargzSchemaVersion is the schema version written into the rebuilt
+// OCI manifest and index
+const estargzSchemaVersion = 2
+
+// parseEStargzSpec parses a destination like
+// estargz:registry.example.com/repository:tag into the pushed reference
+func parseEStargzSpec(destSpec string) (types.ImageReference, string, error) {
+	dest := strings.TrimPrefix(destSpec, constant.EStargzTransport+":")
+	if strings.TrimSpace(dest) == "" {
+		return nil, "", errors.Errorf("invalid dest spec %q, expected %s:registry/repository[:tag]",
+			destSpec, constant.EStargzTransport)
+	}
+	destRef, err := docker.ParseReference("//" + dest)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "invalid dest spec %q", destSpec)
+	}
+
+	return destRef, dest, nil
+}
+
+// exportEStargz converts every layer of imageID into the seekable
+// stargz format and pushes the converted image to a registry, so nodes
+// running a lazy-pulling snapshotter can start containers before the
+// whole image is downloaded
+func exportEStargz(imageID, destSpec string, opts ExportOptions, localStore *store.Store) error {
+	destRef, dest, err := parseEStargzSpec(destSpec)
+	if err != nil {
+		return err
+	}
+	registry, err := util.ParseServer(dest)
+	if err != nil {
+		return err
+	}
+	if opts.SystemContext.DockerCertPath, err = securejoin.SecureJoin(constant.DefaultCertRoot, registry); err != nil {
+		return err
+	}
+
+	srcRef, err := is.Transport.ParseStoreReference(localStore, imageID)
+	if err != nil {
+		return errors.Wrapf(err, "find image %q for estargz export failed", imageID)
+	}
+
+	layoutDir, err := ioutil.TempDir(opts.DataDir, "estargz-")
+	if err != nil {
+		return errors.Wrap(err, "create working directory for estargz export failed")
+	}
+	defer func() {
+		if rErr := os.RemoveAll(layoutDir); rErr != nil {
+			logrus.Warnf("Remove estargz export directory %q failed: %v", layoutDir, rErr)
+		}
+	}()
+
+	if err = convertToEStargzLayout(srcRef, layoutDir, opts); err != nil {
+		return err
+	}
+
+	srcLayoutRef, err := layout.NewReference(layoutDir, "")
+	if err != nil {
+		return errors.Wrap(err, "parse estargz layout reference failed")
+	}
+	policyContext, err := NewPolicyContext(opts.SystemContext)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if dErr := policyContext.Destroy(); dErr != nil {
+			logrus.Warnf("Destroy policy context failed: %v", dErr)
+		}
+	}()
+	// forcing a compression here would re-compress the layers and strip
+	// the stargz structure, so the converted blobs are pushed as they are
+	cpOptsSrc := opts
+	cpOptsSrc.CompressionFormat = ""
+	cpOpts := NewCopyOptions(cpOptsSrc)
+	cpOpts.ForceManifestMIMEType = imgspecv1.MediaTypeImageManifest
+	cpOpts.ImageListSelection = cp.CopySystemImage
+
+	if err = util.CopyWithRetry(opts.Ctx, fmt.Sprintf("exporting %q", opts.ExportID), func() error {
+		_, cErr := cp.Image(opts.Ctx, policyContext, destRef, srcLayoutRef, cpOpts)
+		return cErr
+	}); err != nil {
+		return errors.Wrap(err, "copying layers and metadata failed")
+	}
+
+	return nil
+}
+
+// convertToEStargzLayout writes srcRef into an OCI layout at layoutDir
+// with every layer rebuilt as an eStargz blob and the config and
+// manifest rewritten to match the new layers
+func convertToEStargzLayout(srcRef types.ImageReference, layoutDir string, opts ExportOptions) error {
+	img, err := srcRef.NewImage(opts.Ctx, opts.SystemContext)
+	if err != nil {
+		return errors.Wrap(err, "read image for estargz export failed")
+	}
+	defer func() {
+		if cErr := img.Close(); cErr != nil {
+			logrus.Warnf("Close image for estargz export failed: %v", cErr)
+		}
+	}()
+	imgSrc, err := srcRef.NewImageSource(opts.Ctx, opts.SystemContext)
+	if err != nil {
+		return errors.Wrap(err, "read image source for estargz export failed")
+	}
+	defer func() {
+		if cErr := imgSrc.Close(); cErr != nil {
+			logrus.Warnf("Close image source for estargz export failed: %v", cErr)
+		}
+	}()
+	config, err := img.OCIConfig(opts.Ctx)
+	if err != nil {
+		return errors.Wrap(err, "read image config for estargz export failed")
+	}
+
+	blobsDir := filepath.Join(layoutDir, "blobs", digest.Canonical.String())
+	if err = os.MkdirAll(blobsDir, constant.DefaultRootDirMode); err != nil {
+		return errors.Wrap(err, "create blobs directory for estargz export failed")
+	}
+
+	var (
+		layerDescs []imgspecv1.Descriptor
+		diffIDs    []digest.Digest
+	)
+	for _, layerInfo := range img.LayerInfos() {
+		desc, diffID, cErr := convertLayerToEStargz(imgSrc, layerInfo, layoutDir, blobsDir, opts)
+		if cErr != nil {
+			return cErr
+		}
+		layerDescs = append(layerDescs, desc)
+		diffIDs = append(diffIDs, diffID)
+	}
+
+	config.RootFS.DiffIDs = diffIDs
+	configDesc, err := writeEStargzBlob(blobsDir, imgspecv1.MediaTypeImageConfig, config)
+	if err != nil {
+		return err
+	}
+	manifestDesc, err := writeEStargzBlob(blobsDir, imgspecv1.MediaTypeImageManifest, imgspecv1.Manifest{
+		Versioned: imgspec.Versioned{SchemaVersion: estargzSchemaVersion},
+		Config:    configDesc,
+		Layers:    layerDescs,
+	})
+	if err != nil {
+		return err
+	}
+
+	index, err := json.Marshal(imgspecv1.Index{
+		Versioned: imgspec.Versioned{SchemaVersion: estargzSchemaVersion},
+		Manifests: []imgspecv1.Descriptor{manifestDesc},
+	})
+	if err != nil {
+		return errors.Wrap(err, "marshal estargz image index failed")
+	}
+	if err = ioutil.WriteFile(filepath.Join(layoutDir, "index.json"), index, constant.DefaultRootFileMode); err != nil {
+		return errors.Wrap(err, "write estargz image index failed")
+	}
+	imageLayout, err := json.Marshal(imgspecv1.ImageLayout{Version: imgspecv1.ImageLayoutVersion})
+	if err != nil {
+		return errors.Wrap(err, "marshal estargz image layout failed")
+	}
+	if err = ioutil.WriteFile(filepath.Join(layoutDir, imgspecv1.ImageLayoutFile), imageLayout, constant.DefaultRootFileMode); err != nil {
+		return errors.Wrap(err, "write estargz image layout failed")
+	}
+
+	return nil
+}
+
+// convertLayerToEStargz rebuilds one layer as an eStargz blob in
+// blobsDir and returns its descriptor and new diff ID
+func convertLayerToEStargz(imgSrc types.ImageSource, layerInfo types.BlobInfo, layoutDir, blobsDir string, opts ExportOptions) (imgspecv1.Descriptor, digest.Digest, error) {
+	layer, _, err := imgSrc.GetBlob(opts.Ctx, layerInfo, none.NoCache)
+	if err != nil {
+		return imgspecv1.Descriptor{}, "", errors.Wrapf(err, "read layer %q for estargz export failed", layerInfo.Digest)
+	}
+	defer func() {
+		if cErr := layer.Close(); cErr != nil {
+			logrus.Warnf("Close layer %q failed: %v", layerInfo.Digest, cErr)
+		}
+	}()
+	decompressed, _, err := compression.AutoDecompress(layer)
+	if err != nil {
+		return imgspecv1.Descriptor{}, "", errors.Wrapf(err, "decompress layer %q failed", layerInfo.Digest)
+	}
+	defer func() {
+		if cErr := decompressed.Close(); cErr != nil {
+			logrus.Warnf("Close decompressed layer %q failed: %v", layerInfo.Digest, cErr)
+		}
+	}()
+
+	// estargz.Build needs random access to the layer tar, so the
+	// decompressed stream is spooled to a temporary file first
+	tarFile, err := ioutil.TempFile(layoutDir, "layer-")
+	if err != nil {
+		return imgspecv1.Descriptor{}, "", errors.Wrap(err, "create temporary layer file failed")
+	}
+	defer func() {
+		tarFile.Close() // nolint:errcheck
+		if rErr := os.Remove(tarFile.Name()); rErr != nil && !os.IsNotExist(rErr) {
+			logrus.Warnf("Remove temporary layer file %q failed: %v", tarFile.Name(), rErr)
+		}
+	}()
+	uncompressedSize, err := io.Copy(tarFile, decompressed)
+	if err != nil {
+		return imgspecv1.Descriptor{}, "", errors.Wrapf(err, "spool layer %q failed", layerInfo.Digest)
+	}
+
+	blob, err := estargz.Build(io.NewSectionReader(tarFile, 0, uncompressedSize))
+	if err != nil {
+		return imgspecv1.Descriptor{}, "", errors.Wrapf(err, "convert layer %q to estargz failed", layerInfo.Digest)
+	}
+	defer func() {
+		if cErr := blob.Close(); cErr != nil {
+			logrus.Warnf("Close estargz blob of layer %q failed: %v", layerInfo.Digest, cErr)
+		}
+	}()
+
+	blobFile, err := ioutil.TempFile(blobsDir, "blob-")
+	if err != nil {
+		return imgspecv1.Descriptor{}, "", errors.Wrap(err, "create estargz blob file failed")
+	}
+	defer blobFile.Close() // nolint:errcheck
+	digester := digest.Canonical.Digester()
+	size, err := io.Copy(io.MultiWriter(blobFile, digester.Hash()), blob)
+	if err != nil {
+		return imgspecv1.Descriptor{}, "", errors.Wrapf(err, "write estargz blob of layer %q failed", layerInfo.Digest)
+	}
+	blobDigest := digester.Digest()
+	if err = os.Rename(blobFile.Name(), filepath.Join(blobsDir, blobDigest.Encoded())); err != nil {
+		return imgspecv1.Descriptor{}, "", errors.Wrapf(err, "store estargz blob of layer %q failed", layerInfo.Digest)
+	}
+
+	return imgspecv1.Descriptor{
+		MediaType: imgspecv1.MediaTypeImageLayerGzip,
+		Digest:    blobDigest,
+		Size:      size,
+		Annotations: map[string]string{
+			estargz.TOCJSONDigestAnnotation:         blob.TOCDigest().String(),
+			estargz.StoreUncompressedSizeAnnotation: strconv.FormatInt(uncompressedSize, 10),
+		},
+	}, blob.DiffID(), nil
+}
+
+// writeEStargzBlob marshals content into a blob in blobsDir and
+// returns its descriptor
+func writeEStargzBlob(blobsDir, mediaType string, content interface{}) (imgspecv1.Descriptor, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return imgspecv1.Descriptor{}, errors.Wrapf(err, "marshal %s failed", mediaType)
+	}
+	blobDigest := digest.Canonical.FromBytes(data)
+	if err = ioutil.WriteFile(filepath.Join(blobsDir, blobDigest.Encoded()), data, constant.DefaultRootFileMode); err != nil {
+		return imgspecv1.Descriptor{}, errors.Wrapf(err, "write %s failed", mediaType)
+	}
+
+	return imgspecv1.Descriptor{
+		MediaType: mediaType,
+		Digest:    blobDigest,
+		Size:      int64(len(data)),
+	}, nil
+}
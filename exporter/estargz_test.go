@@ -0,0 +1,62 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: eStargz export test
+
+package exporter
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseEStargzSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		destSpec string
+		wantErr  string
+		want     string
+	}{
+		{
+			name:     "registry reference with tag",
+			destSpec: "estargz:registry.example.com/app:latest",
+			want:     "registry.example.com/app:latest",
+		},
+		{
+			name:     "registry reference without tag",
+			destSpec: "estargz:registry.example.com/app",
+			want:     "registry.example.com/app",
+		},
+		{
+			name:     "missing reference",
+			destSpec: "estargz:",
+			wantErr:  "invalid dest spec",
+		},
+		{
+			name:     "uppercase repository",
+			destSpec: "estargz:registry.example.com/APP:latest",
+			wantErr:  "invalid dest spec",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, dest, err := parseEStargzSpec(tt.destSpec)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, ref != nil)
+			assert.Equal(t, dest, tt.want)
+		})
+	}
+}
@@ -47,6 +47,13 @@ type Exporter interface {
 	Remove(exportID string)
 }
 
+// Finisher is implemented by exporters that complete the export with a
+// follow-up action after the image copy, e.g. waiting for the process
+// consuming the copied image
+type Finisher interface {
+	Finish(exportID string) error
+}
+
 // Register registers an exporter
 func Register(e Exporter) {
 	hub.Lock()
@@ -0,0 +1,212 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-04-12
+// Description: manifest annotation and platform override applied at export time
+
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// PlatformOverride carries the platform fields rewritten in the image
+// config at export time, empty fields keep the recorded value
+type PlatformOverride struct {
+	OS           string
+	Architecture string
+	Variant      string
+}
+
+// empty reports whether no platform field is overridden
+func (p *PlatformOverride) empty() bool {
+	return p == nil || (p.OS == "" && p.Architecture == "" && p.Variant == "")
+}
+
+// overrideReference wraps an image reference so the image source it
+// opens serves a rewritten config and manifest
+type overrideReference struct {
+	types.ImageReference
+	platform    *PlatformOverride
+	annotations map[string]string
+}
+
+// newOverrideReference wraps ref unless there is nothing to override
+func newOverrideReference(ref types.ImageReference, platform *PlatformOverride, annotations map[string]string) types.ImageReference {
+	if platform.empty() && len(annotations) == 0 {
+		return ref
+	}
+	return &overrideReference{ImageReference: ref, platform: platform, annotations: annotations}
+}
+
+// NewImageSource opens the wrapped source and rewrites its config and
+// manifest according to the overrides
+func (r *overrideReference) NewImageSource(ctx context.Context, sys *types.SystemContext) (types.ImageSource, error) {
+	src, err := r.ImageReference.NewImageSource(ctx, sys)
+	if err != nil {
+		return nil, err
+	}
+	overridden, err := newOverrideImageSource(ctx, src, r.platform, r.annotations)
+	if err != nil {
+		if cErr := src.Close(); cErr != nil {
+			return nil, errors.Wrapf(err, "close image source got error: %v", cErr)
+		}
+		return nil, err
+	}
+	return overridden, nil
+}
+
+// overrideImageSource serves a manifest whose config was rewritten with
+// the platform overrides and whose annotations were merged, delegating
+// the layer blobs to the wrapped source
+type overrideImageSource struct {
+	types.ImageSource
+	manifest     []byte
+	manifestType string
+	config       []byte
+	configDigest digest.Digest
+}
+
+func newOverrideImageSource(ctx context.Context, src types.ImageSource, platform *PlatformOverride, annotations map[string]string) (types.ImageSource, error) {
+	origManifest, origType, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "get manifest for override failed")
+	}
+	if manifest.MIMETypeIsMultiImage(origType) {
+		return nil, errors.Errorf("override on a manifest list of type %q is not supported", origType)
+	}
+
+	parsed, err := manifest.FromBlob(origManifest, origType)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse manifest for override failed")
+	}
+
+	ovr := &overrideImageSource{
+		ImageSource:  src,
+		manifest:     origManifest,
+		manifestType: origType,
+	}
+	if err := ovr.rewriteConfig(ctx, parsed.ConfigInfo(), platform); err != nil {
+		return nil, err
+	}
+	if err := ovr.rewriteManifest(parsed, annotations); err != nil {
+		return nil, err
+	}
+
+	return ovr, nil
+}
+
+// rewriteConfig loads the image config and applies the platform
+// overrides to it
+func (o *overrideImageSource) rewriteConfig(ctx context.Context, configInfo types.BlobInfo, platform *PlatformOverride) error {
+	if platform.empty() {
+		return nil
+	}
+
+	reader, _, err := o.ImageSource.GetBlob(ctx, configInfo, none.NoCache)
+	if err != nil {
+		return errors.Wrap(err, "get image config for override failed")
+	}
+	defer reader.Close()
+	configBytes, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.Wrap(err, "read image config for override failed")
+	}
+
+	// decode generically to keep the fields this build does not know
+	var config map[string]interface{}
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		return errors.Wrap(err, "unmarshal image config for override failed")
+	}
+	if platform.OS != "" {
+		config["os"] = platform.OS
+	}
+	if platform.Architecture != "" {
+		config["architecture"] = platform.Architecture
+	}
+	if platform.Variant != "" {
+		config["variant"] = platform.Variant
+	}
+	o.config, err = json.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "marshal overridden image config failed")
+	}
+	o.configDigest = digest.FromBytes(o.config)
+
+	return nil
+}
+
+// rewriteManifest points the manifest at the rewritten config and
+// merges the requested annotations
+func (o *overrideImageSource) rewriteManifest(parsed manifest.Manifest, annotations map[string]string) error {
+	switch m := parsed.(type) {
+	case *manifest.OCI1:
+		if o.config != nil {
+			m.Config.Digest = o.configDigest
+			m.Config.Size = int64(len(o.config))
+		}
+		if len(annotations) != 0 {
+			if m.Annotations == nil {
+				m.Annotations = make(map[string]string, len(annotations))
+			}
+			for k, v := range annotations {
+				m.Annotations[k] = v
+			}
+		}
+	case *manifest.Schema2:
+		if len(annotations) != 0 {
+			return errors.Errorf("annotations are not supported by the %q manifest, use the oci format", o.manifestType)
+		}
+		if o.config != nil {
+			m.ConfigDescriptor.Digest = o.configDigest
+			m.ConfigDescriptor.Size = int64(len(o.config))
+		}
+	default:
+		return errors.Errorf("override on manifest type %q is not supported", o.manifestType)
+	}
+
+	if o.config == nil && len(annotations) == 0 {
+		return nil
+	}
+	serialized, err := parsed.Serialize()
+	if err != nil {
+		return errors.Wrap(err, "serialize overridden manifest failed")
+	}
+	o.manifest = serialized
+
+	return nil
+}
+
+// GetManifest returns the overridden manifest for the image itself and
+// delegates the per-instance requests
+func (o *overrideImageSource) GetManifest(ctx context.Context, instanceDigest *digest.Digest) ([]byte, string, error) {
+	if instanceDigest == nil {
+		return o.manifest, o.manifestType, nil
+	}
+	return o.ImageSource.GetManifest(ctx, instanceDigest)
+}
+
+// GetBlob serves the rewritten config and delegates the layer blobs
+func (o *overrideImageSource) GetBlob(ctx context.Context, info types.BlobInfo, cache types.BlobInfoCache) (io.ReadCloser, int64, error) {
+	if o.config != nil && info.Digest == o.configDigest {
+		return ioutil.NopCloser(bytes.NewReader(o.config)), int64(len(o.config)), nil
+	}
+	return o.ImageSource.GetBlob(ctx, info, cache)
+}
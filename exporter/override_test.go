@@ -0,0 +1,152 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-04-12
+// Description: export time override test functions
+
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+)
+
+// fakeImageSource serves one manifest and its config blob from memory
+type fakeImageSource struct {
+	types.ImageSource
+	manifest     []byte
+	manifestType string
+	blobs        map[digest.Digest][]byte
+}
+
+func (f *fakeImageSource) GetManifest(ctx context.Context, instanceDigest *digest.Digest) ([]byte, string, error) {
+	return f.manifest, f.manifestType, nil
+}
+
+func (f *fakeImageSource) GetBlob(ctx context.Context, info types.BlobInfo, cache types.BlobInfoCache) (io.ReadCloser, int64, error) {
+	blob, ok := f.blobs[info.Digest]
+	if !ok {
+		return nil, -1, errors.Errorf("blob %s not found", info.Digest)
+	}
+	return ioutil.NopCloser(bytes.NewReader(blob)), int64(len(blob)), nil
+}
+
+func newFakeOCISource(t *testing.T) *fakeImageSource {
+	config := map[string]interface{}{
+		"os":           "linux",
+		"architecture": "arm",
+		"variant":      "v6",
+		"rootfs":       map[string]interface{}{"type": "layers"},
+	}
+	configBytes, err := json.Marshal(config)
+	assert.NilError(t, err)
+	configDigest := digest.FromBytes(configBytes)
+
+	m := manifest.OCI1FromComponents(imgspecv1.Descriptor{
+		MediaType: imgspecv1.MediaTypeImageConfig,
+		Digest:    configDigest,
+		Size:      int64(len(configBytes)),
+	}, nil)
+	manifestBytes, err := m.Serialize()
+	assert.NilError(t, err)
+
+	return &fakeImageSource{
+		manifest:     manifestBytes,
+		manifestType: imgspecv1.MediaTypeImageManifest,
+		blobs:        map[digest.Digest][]byte{configDigest: configBytes},
+	}
+}
+
+func TestOverrideImageSource(t *testing.T) {
+	ctx := context.Background()
+	src := newFakeOCISource(t)
+
+	platform := &PlatformOverride{Architecture: "arm", Variant: "v7"}
+	annotations := map[string]string{"org.example.key": "value"}
+	overridden, err := newOverrideImageSource(ctx, src, platform, annotations)
+	assert.NilError(t, err)
+
+	manifestBytes, manifestType, err := overridden.GetManifest(ctx, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, manifestType, imgspecv1.MediaTypeImageManifest)
+	parsed, err := manifest.OCI1FromManifest(manifestBytes)
+	assert.NilError(t, err)
+	assert.Equal(t, parsed.Annotations["org.example.key"], "value")
+
+	reader, size, err := overridden.GetBlob(ctx, types.BlobInfo{Digest: parsed.Config.Digest}, nil)
+	assert.NilError(t, err)
+	defer reader.Close()
+	configBytes, err := ioutil.ReadAll(reader)
+	assert.NilError(t, err)
+	assert.Equal(t, size, int64(len(configBytes)))
+	assert.Equal(t, parsed.Config.Digest, digest.FromBytes(configBytes))
+	assert.Equal(t, parsed.Config.Size, int64(len(configBytes)))
+
+	var config map[string]interface{}
+	assert.NilError(t, json.Unmarshal(configBytes, &config))
+	assert.Equal(t, config["os"], "linux")
+	assert.Equal(t, config["architecture"], "arm")
+	assert.Equal(t, config["variant"], "v7")
+	// untouched fields of the original config must survive the rewrite
+	_, ok := config["rootfs"]
+	assert.Assert(t, ok)
+}
+
+func TestOverrideImageSourceAnnotationsOnly(t *testing.T) {
+	ctx := context.Background()
+	src := newFakeOCISource(t)
+
+	overridden, err := newOverrideImageSource(ctx, src, nil, map[string]string{"org.example.key": "value"})
+	assert.NilError(t, err)
+
+	manifestBytes, _, err := overridden.GetManifest(ctx, nil)
+	assert.NilError(t, err)
+	parsed, err := manifest.OCI1FromManifest(manifestBytes)
+	assert.NilError(t, err)
+	assert.Equal(t, parsed.Annotations["org.example.key"], "value")
+
+	origParsed, err := manifest.OCI1FromManifest(src.manifest)
+	assert.NilError(t, err)
+	// the config must stay untouched when only annotations are added
+	assert.Equal(t, parsed.Config.Digest, origParsed.Config.Digest)
+}
+
+func TestOverrideImageSourceSchema2Annotations(t *testing.T) {
+	ctx := context.Background()
+	src := newFakeOCISource(t)
+	src.manifestType = manifest.DockerV2Schema2MediaType
+	m := manifest.Schema2FromComponents(manifest.Schema2Descriptor{
+		MediaType: manifest.DockerV2Schema2ConfigMediaType,
+		Digest:    digest.FromString("config"),
+		Size:      6,
+	}, nil)
+	manifestBytes, err := m.Serialize()
+	assert.NilError(t, err)
+	src.manifest = manifestBytes
+
+	_, err = newOverrideImageSource(ctx, src, nil, map[string]string{"org.example.key": "value"})
+	assert.ErrorContains(t, err, "annotations are not supported")
+}
+
+func TestNewOverrideReferencePassThrough(t *testing.T) {
+	ref := newOverrideReference(nil, nil, nil)
+	assert.Assert(t, ref == nil)
+}
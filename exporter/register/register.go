@@ -15,6 +15,10 @@
 package register
 
 import (
+	// register the containerd exporter
+	_ "isula.org/isula-build/exporter/containerd"
+	// register the containers-storage exporter
+	_ "isula.org/isula-build/exporter/storage"
 	// register the docker exporter
 	_ "isula.org/isula-build/exporter/docker"
 	// register the docker-archive exporter
@@ -0,0 +1,142 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-06-10
+// Description: squashfs export related functions
+
+package exporter
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/store"
+)
+
+const (
+	// squashfsMinBlockSize and squashfsMaxBlockSize are the block size
+	// bounds accepted by mksquashfs
+	squashfsMinBlockSize = 4 * 1024
+	squashfsMaxBlockSize = 1024 * 1024
+)
+
+// squashfsCompressors are the compression algorithms accepted for
+// the comp option, all of them are supported by mksquashfs
+var squashfsCompressors = map[string]bool{
+	"gzip": true,
+	"lzo":  true,
+	"lz4":  true,
+	"xz":   true,
+	"zstd": true,
+}
+
+// squashfsSpec is one parsed squashfs output destination
+type squashfsSpec struct {
+	// path is the absolute path the image is written to
+	path string
+	// comp is the compression algorithm, empty uses the mksquashfs default
+	comp string
+	// blockSize is the filesystem block size in bytes, 0 uses the default
+	blockSize int64
+}
+
+// parseSquashfsSpec parses a destination like
+// squashfs:/path/rootfs.sqfs:comp=zstd,blocksize=128K
+func parseSquashfsSpec(destSpec string) (squashfsSpec, error) {
+	const partsNum = 3
+	parts := strings.SplitN(destSpec, ":", partsNum)
+	if len(parts) < partsNum-1 || strings.TrimSpace(parts[1]) == "" {
+		return squashfsSpec{}, errors.Errorf("invalid dest spec %q, expected %s:path[:key=value]",
+			destSpec, constant.SquashfsTransport)
+	}
+
+	spec := squashfsSpec{path: parts[1]}
+	if len(parts) < partsNum {
+		return spec, nil
+	}
+	for _, option := range strings.Split(parts[2], ",") {
+		switch {
+		case strings.HasPrefix(option, "comp="):
+			comp := strings.TrimPrefix(option, "comp=")
+			if !squashfsCompressors[comp] {
+				return squashfsSpec{}, errors.Errorf("invalid squashfs compressor %q", option)
+			}
+			spec.comp = comp
+		case strings.HasPrefix(option, "blocksize="):
+			blockSize, err := units.RAMInBytes(strings.TrimPrefix(option, "blocksize="))
+			if err != nil || blockSize < squashfsMinBlockSize || blockSize > squashfsMaxBlockSize ||
+				blockSize&(blockSize-1) != 0 {
+				return squashfsSpec{}, errors.Errorf("invalid squashfs block size %q, expected a power of two between 4K and 1M", option)
+			}
+			spec.blockSize = blockSize
+		default:
+			return squashfsSpec{}, errors.Errorf("unknown squashfs option %q", option)
+		}
+	}
+
+	return spec, nil
+}
+
+// exportSquashfs writes the flattened rootfs of imageID into a compressed
+// read-only squashfs image, mounting the image the same way a build
+// container would see it
+func exportSquashfs(imageID, destSpec string, opts ExportOptions, localStore *store.Store) error {
+	spec, err := parseSquashfsSpec(destSpec)
+	if err != nil {
+		return err
+	}
+	if _, lErr := exec.LookPath("mksquashfs"); lErr != nil {
+		return errors.Wrap(lErr, "exporting a squashfs image needs the mksquashfs tool")
+	}
+
+	img, err := localStore.Image(imageID)
+	if err != nil {
+		return errors.Wrapf(err, "find image %q for squashfs export failed", imageID)
+	}
+
+	container, err := localStore.CreateContainer("", nil, img.ID, "", "", nil)
+	if err != nil {
+		return errors.Wrap(err, "create working container for squashfs export failed")
+	}
+	defer func() {
+		if cErr := localStore.CleanContainer(container.ID); cErr != nil {
+			logrus.Warnf("Clean squashfs export container %q failed: %v", container.ID, cErr)
+		}
+	}()
+	mountPoint, err := localStore.Mount(container.ID, "")
+	if err != nil {
+		return errors.Wrap(err, "mount rootfs for squashfs export failed")
+	}
+
+	args := []string{mountPoint, spec.path, "-quiet", "-noappend"}
+	if spec.comp != "" {
+		args = append(args, "-comp", spec.comp)
+	}
+	if spec.blockSize != 0 {
+		args = append(args, "-b", strconv.FormatInt(spec.blockSize, 10))
+	}
+	// the arguments are built from the validated output spec, no security risk
+	cmd := exec.CommandContext(opts.Ctx, "mksquashfs", args...) // nolint:gosec
+	if out, mErr := cmd.CombinedOutput(); mErr != nil {
+		if rErr := os.Remove(spec.path); rErr != nil && !os.IsNotExist(rErr) {
+			logrus.Warnf("Remove unfinished squashfs image %q failed: %v", spec.path, rErr)
+		}
+		return errors.Errorf("make squashfs image failed, output: %s, err: %v", string(out), mErr)
+	}
+
+	return nil
+}
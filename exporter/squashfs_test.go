@@ -0,0 +1,76 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-06-10
+// Description: squashfs export test
+
+package exporter
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseSquashfsSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		destSpec string
+		wantErr  string
+		want     squashfsSpec
+	}{
+		{
+			name:     "path only uses mksquashfs defaults",
+			destSpec: "squashfs:/tmp/rootfs.sqfs",
+			want:     squashfsSpec{path: "/tmp/rootfs.sqfs"},
+		},
+		{
+			name:     "compressor and block size",
+			destSpec: "squashfs:/tmp/rootfs.sqfs:comp=zstd,blocksize=128K",
+			want:     squashfsSpec{path: "/tmp/rootfs.sqfs", comp: "zstd", blockSize: 128 * 1024},
+		},
+		{
+			name:     "missing path",
+			destSpec: "squashfs:",
+			wantErr:  "invalid dest spec",
+		},
+		{
+			name:     "unknown compressor",
+			destSpec: "squashfs:/tmp/rootfs.sqfs:comp=bzip2",
+			wantErr:  "invalid squashfs compressor",
+		},
+		{
+			name:     "block size not a power of two",
+			destSpec: "squashfs:/tmp/rootfs.sqfs:blocksize=100K",
+			wantErr:  "invalid squashfs block size",
+		},
+		{
+			name:     "block size out of range",
+			destSpec: "squashfs:/tmp/rootfs.sqfs:blocksize=2M",
+			wantErr:  "invalid squashfs block size",
+		},
+		{
+			name:     "unknown option",
+			destSpec: "squashfs:/tmp/rootfs.sqfs:foo=bar",
+			wantErr:  "unknown squashfs option",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := parseSquashfsSpec(tt.destSpec)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, spec, tt.want)
+		})
+	}
+}
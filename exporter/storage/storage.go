@@ -0,0 +1,97 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: containers-storage exporter related functions
+
+// Package storage is used to export images into another containers/storage
+// graph root, e.g. the one CRI-O or podman reads on the same host
+package storage
+
+import (
+	"sync"
+
+	"github.com/containers/image/v5/transports/alltransports"
+	"github.com/containers/image/v5/types"
+	"github.com/pkg/errors"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/exporter"
+	"isula.org/isula-build/image"
+	"isula.org/isula-build/store"
+)
+
+func init() {
+	exporter.Register(&_storageExporter)
+}
+
+type storageExporter struct {
+	items map[string]exporter.Bus
+	sync.RWMutex
+}
+
+var _storageExporter = storageExporter{
+	items: make(map[string]exporter.Bus),
+}
+
+func (s *storageExporter) Name() string {
+	return constant.ContainersStorageTransport
+}
+
+func (s *storageExporter) Init(opts exporter.ExportOptions, src, destSpec string, localStore *store.Store) error {
+	// src is form of ImageID digest, destSpec is form of
+	// containers-storage:[driver@graphroot+runroot]image:tag, the
+	// optional specifier selects the destination graph root and the
+	// default is the storage podman and CRI-O read
+	srcReference, _, err := image.FindImage(localStore, src)
+	if err != nil {
+		return errors.Wrapf(err, "find src image: %q failed with transport %q", src, s.Name())
+	}
+	destReference, err := alltransports.ParseImageName(destSpec)
+	if err != nil {
+		return errors.Wrapf(err, "parse dest spec: %q failed with transport %q", destSpec, s.Name())
+	}
+
+	s.Lock()
+	s.items[opts.ExportID] = exporter.Bus{
+		SrcRef:  srcReference,
+		DestRef: destReference,
+	}
+	s.Unlock()
+	return nil
+}
+
+func (s *storageExporter) GetSrcRef(exportID string) types.ImageReference {
+	s.RLock()
+	defer s.RUnlock()
+
+	if _, ok := s.items[exportID]; ok {
+		return s.items[exportID].SrcRef
+	}
+
+	return nil
+}
+
+func (s *storageExporter) GetDestRef(exportID string) types.ImageReference {
+	s.RLock()
+	defer s.RUnlock()
+
+	if _, ok := s.items[exportID]; ok {
+		return s.items[exportID].DestRef
+	}
+
+	return nil
+}
+
+func (s *storageExporter) Remove(exportID string) {
+	s.Lock()
+	delete(s.items, exportID)
+	s.Unlock()
+}
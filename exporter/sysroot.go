@@ -0,0 +1,159 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-06-25
+// Description: sysroot export related functions
+
+package exporter
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/containers/storage/pkg/archive"
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/opencontainers/selinux/go-selinux/label"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/store"
+)
+
+// sysrootIDRangeSize is the size of the uid/gid range shifted into the
+// host namespace, which covers the ids commonly used inside images
+const sysrootIDRangeSize = 1 << 16
+
+// sysrootSpec is one parsed sysroot output destination
+type sysrootSpec struct {
+	// path is the host directory the rootfs is unpacked into
+	path string
+	// uidShift and gidShift are added to the file owners, 0 keeps them
+	uidShift int
+	gidShift int
+	// selinuxLabel relabels the unpacked tree when set
+	selinuxLabel string
+}
+
+// parseSysrootSpec parses a destination like
+// sysroot:/path/rootfs:uid=100000,gid=100000,label=system_u:object_r:container_file_t:s0
+func parseSysrootSpec(destSpec string) (sysrootSpec, error) {
+	const partsNum = 3
+	parts := strings.SplitN(destSpec, ":", partsNum)
+	if len(parts) < partsNum-1 || strings.TrimSpace(parts[1]) == "" {
+		return sysrootSpec{}, errors.Errorf("invalid dest spec %q, expected %s:path[:key=value]",
+			destSpec, constant.SysrootTransport)
+	}
+
+	spec := sysrootSpec{path: parts[1]}
+	if len(parts) < partsNum {
+		return spec, nil
+	}
+	for _, option := range strings.Split(parts[2], ",") {
+		switch {
+		case strings.HasPrefix(option, "uid="):
+			uid, err := strconv.Atoi(strings.TrimPrefix(option, "uid="))
+			if err != nil || uid < 0 {
+				return sysrootSpec{}, errors.Errorf("invalid sysroot uid shift %q", option)
+			}
+			spec.uidShift = uid
+		case strings.HasPrefix(option, "gid="):
+			gid, err := strconv.Atoi(strings.TrimPrefix(option, "gid="))
+			if err != nil || gid < 0 {
+				return sysrootSpec{}, errors.Errorf("invalid sysroot gid shift %q", option)
+			}
+			spec.gidShift = gid
+		case strings.HasPrefix(option, "label="):
+			spec.selinuxLabel = strings.TrimPrefix(option, "label=")
+		default:
+			return sysrootSpec{}, errors.Errorf("unknown sysroot option %q", option)
+		}
+	}
+
+	return spec, nil
+}
+
+// exportSysroot unpacks the rootfs of imageID into a host directory with
+// the requested uid/gid shift applied, replacing the manual mount and
+// copy steps otherwise needed for toolchain sysroots
+func exportSysroot(imageID, destSpec string, opts ExportOptions, localStore *store.Store) error {
+	spec, err := parseSysrootSpec(destSpec)
+	if err != nil {
+		return err
+	}
+	if _, sErr := os.Stat(spec.path); sErr == nil {
+		return errors.Errorf("sysroot dir %q already exists", spec.path)
+	}
+
+	img, err := localStore.Image(imageID)
+	if err != nil {
+		return errors.Wrapf(err, "find image %q for sysroot export failed", imageID)
+	}
+
+	container, err := localStore.CreateContainer("", nil, img.ID, "", "", nil)
+	if err != nil {
+		return errors.Wrap(err, "create working container for sysroot export failed")
+	}
+	defer func() {
+		if cErr := localStore.CleanContainer(container.ID); cErr != nil {
+			logrus.Warnf("Clean sysroot export container %q failed: %v", container.ID, cErr)
+		}
+	}()
+	mountPoint, err := localStore.Mount(container.ID, "")
+	if err != nil {
+		return errors.Wrap(err, "mount rootfs for sysroot export failed")
+	}
+
+	if err = os.MkdirAll(spec.path, constant.DefaultRootDirMode); err != nil {
+		return errors.Wrap(err, "create sysroot dir failed")
+	}
+	if err = unpackSysroot(mountPoint, spec); err != nil {
+		if rErr := os.RemoveAll(spec.path); rErr != nil {
+			logrus.Warnf("Remove unfinished sysroot %q failed: %v", spec.path, rErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// unpackSysroot copies the mounted rootfs into the sysroot dir, shifting
+// the file owners and relabeling the tree when requested
+func unpackSysroot(mountPoint string, spec sysrootSpec) error {
+	reader, err := archive.Tar(mountPoint, archive.Uncompressed)
+	if err != nil {
+		return errors.Wrap(err, "tar rootfs for sysroot export failed")
+	}
+	defer func() {
+		if cErr := reader.Close(); cErr != nil {
+			logrus.Warnf("Close rootfs reader failed: %v", cErr)
+		}
+	}()
+
+	tarOpts := &archive.TarOptions{}
+	if spec.uidShift != 0 {
+		tarOpts.UIDMaps = []idtools.IDMap{{ContainerID: 0, HostID: spec.uidShift, Size: sysrootIDRangeSize}}
+	}
+	if spec.gidShift != 0 {
+		tarOpts.GIDMaps = []idtools.IDMap{{ContainerID: 0, HostID: spec.gidShift, Size: sysrootIDRangeSize}}
+	}
+	if err = archive.Untar(reader, spec.path, tarOpts); err != nil {
+		return errors.Wrap(err, "unpack rootfs for sysroot export failed")
+	}
+
+	if spec.selinuxLabel != "" {
+		if err = label.Relabel(spec.path, spec.selinuxLabel, false); err != nil {
+			return errors.Wrap(err, "relabel sysroot failed")
+		}
+	}
+
+	return nil
+}
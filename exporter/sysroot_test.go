@@ -0,0 +1,71 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-06-25
+// Description: sysroot export test
+
+package exporter
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseSysrootSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		destSpec string
+		wantErr  string
+		want     sysrootSpec
+	}{
+		{
+			name:     "path only keeps ownership",
+			destSpec: "sysroot:/tmp/rootfs",
+			want:     sysrootSpec{path: "/tmp/rootfs"},
+		},
+		{
+			name:     "uid and gid shift",
+			destSpec: "sysroot:/tmp/rootfs:uid=100000,gid=100000",
+			want:     sysrootSpec{path: "/tmp/rootfs", uidShift: 100000, gidShift: 100000},
+		},
+		{
+			name:     "selinux label",
+			destSpec: "sysroot:/tmp/rootfs:label=system_u:object_r:container_file_t:s0",
+			want:     sysrootSpec{path: "/tmp/rootfs", selinuxLabel: "system_u:object_r:container_file_t:s0"},
+		},
+		{
+			name:     "missing path",
+			destSpec: "sysroot:",
+			wantErr:  "invalid dest spec",
+		},
+		{
+			name:     "negative uid shift",
+			destSpec: "sysroot:/tmp/rootfs:uid=-1",
+			wantErr:  "invalid sysroot uid shift",
+		},
+		{
+			name:     "unknown option",
+			destSpec: "sysroot:/tmp/rootfs:mode=shared",
+			wantErr:  "unknown sysroot option",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := parseSysrootSpec(tt.destSpec)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, spec, tt.want)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-01-20
+// Description: destination template related functions
+
+package exporter
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// DestInfo carries the per-image fields which can be referenced
+// in a templated export destination
+type DestInfo struct {
+	// Name is the familiar repository name of the image, e.g. "busybox",
+	// it may contain slashes for images like "myorg/myapp"
+	Name string
+	// Tag is the tag of the image, "none" when the image has no tag
+	Tag string
+	// ID is the truncated image ID
+	ID string
+	// Arch is the architecture of the image
+	Arch string
+	// Os is the operating system of the image
+	Os string
+	// Date is the current date in format yyyyMMdd
+	Date string
+}
+
+// IsDestTemplate returns true when dest contains template actions
+// such as {{.Name}}
+func IsDestTemplate(dest string) bool {
+	return strings.Contains(dest, "{{")
+}
+
+// RenderDest resolves the templated destination dest with the
+// fields of info
+func RenderDest(dest string, info DestInfo) (string, error) {
+	tmpl, err := template.New("dest").Parse(dest)
+	if err != nil {
+		return "", errors.Wrapf(err, "parse destination template %q failed", dest)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, info); err != nil {
+		return "", errors.Wrapf(err, "resolve destination template %q failed", dest)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-01-20
+// Description: destination template tests
+
+package exporter
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestIsDestTemplate(t *testing.T) {
+	assert.Equal(t, IsDestTemplate("/exports/{{.Name}}_{{.Tag}}.tar"), true)
+	assert.Equal(t, IsDestTemplate("/exports/app.tar"), false)
+}
+
+func TestRenderDest(t *testing.T) {
+	info := DestInfo{
+		Name: "busybox",
+		Tag:  "latest",
+		ID:   "a24bb4013296",
+		Arch: "amd64",
+		Os:   "linux",
+		Date: "20220120",
+	}
+
+	dest, err := RenderDest("/exports/{{.Name}}_{{.Tag}}_{{.Arch}}.tar", info)
+	assert.NilError(t, err)
+	assert.Equal(t, dest, "/exports/busybox_latest_amd64.tar")
+
+	dest, err = RenderDest("docker://registry.example.com/{{.Name}}:{{.Tag}}-{{.Date}}", info)
+	assert.NilError(t, err)
+	assert.Equal(t, dest, "docker://registry.example.com/busybox:latest-20220120")
+
+	_, err = RenderDest("/exports/{{.NotExist}}.tar", info)
+	assert.ErrorContains(t, err, "resolve destination template")
+
+	_, err = RenderDest("/exports/{{.Name.tar", info)
+	assert.ErrorContains(t, err, "parse destination template")
+}
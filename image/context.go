@@ -21,6 +21,7 @@ import (
 	"github.com/containers/image/v5/types"
 
 	constant "isula.org/isula-build"
+	"isula.org/isula-build/util"
 )
 
 var (
@@ -60,5 +61,7 @@ func NewImageCopyOptions(reportWriter io.Writer) *cp.Options {
 		ReportWriter:   reportWriter,
 		SourceCtx:      GetSystemContext(),
 		DestinationCtx: GetSystemContext(),
+		// 0 keeps the library default of the copy package
+		MaxParallelDownloads: util.MaxParallelDownloads(),
 	}
 }
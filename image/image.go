@@ -37,6 +37,7 @@ import (
 	"github.com/containers/image/v5/transports/alltransports"
 	"github.com/containers/image/v5/types"
 	"github.com/containers/storage"
+	"github.com/containers/storage/pkg/idtools"
 	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -45,6 +46,7 @@ import (
 	dockerfile "isula.org/isula-build/builder/dockerfile/parser"
 	"isula.org/isula-build/exporter"
 	"isula.org/isula-build/pkg/docker"
+	"isula.org/isula-build/pkg/encrypt"
 	"isula.org/isula-build/store"
 	"isula.org/isula-build/util"
 )
@@ -58,6 +60,70 @@ type PrepareImageOptions struct {
 	Store         *store.Store
 	Reporter      io.Writer
 	ManifestIndex int
+	// Progress reports the copy progress of each blob when set
+	Progress chan types.ProgressProperties
+	// ProgressInterval is the time between two reports of one blob
+	ProgressInterval time.Duration
+	// ContainerName names the ephemeral build container of the stage
+	ContainerName ContainerNameOptions
+	// PolicyStrict rejects the image when the signature policy would
+	// accept it without any signature check
+	PolicyStrict bool
+	// StorageOpt is passed to the graph driver when the RW layer is
+	// created, e.g. a project quota "size" bounding the layer
+	StorageOpt map[string]string
+	// IDMappings remaps ownership in the RW layer of the build
+	// container, nil keeps the host ids
+	IDMappings *idtools.IDMappings
+}
+
+const (
+	// DefaultContainerNameTemplate names the ephemeral build containers,
+	// the supported placeholders are {buildID}, {stage} and {random}
+	DefaultContainerNameTemplate = "isula-build-{random}"
+	// containerNameRandomPlaceholder keeps repeated creations unique
+	containerNameRandomPlaceholder = "{random}"
+)
+
+// containerNameTemplate names the ephemeral build containers, it is set
+// once from the daemon configuration before any build starts
+var containerNameTemplate = DefaultContainerNameTemplate
+
+// ContainerNameOptions carries the build attributes filled into the
+// container name template
+type ContainerNameOptions struct {
+	BuildID string
+	Stage   string
+}
+
+// SetContainerNameTemplate sets the template naming the ephemeral build
+// containers, the {random} placeholder is mandatory to keep names unique
+func SetContainerNameTemplate(template string) error {
+	if !strings.Contains(template, containerNameRandomPlaceholder) {
+		return errors.Errorf("the container name template %q must contain the %s placeholder", template, containerNameRandomPlaceholder)
+	}
+	containerNameTemplate = template
+
+	return nil
+}
+
+// pullPolicyStrict makes every pull strict, it is set once from the
+// daemon configuration before any build starts
+var pullPolicyStrict = false
+
+// SetPullPolicyStrict makes strict pulling the daemon default, so every
+// base image pull rejects policy rules without signature verification
+func SetPullPolicyStrict(strict bool) {
+	pullPolicyStrict = strict
+}
+
+// containerName renders the container name template for one creation try
+func (c ContainerNameOptions) containerName(randNum string) string {
+	return strings.NewReplacer(
+		"{buildID}", c.BuildID,
+		"{stage}", c.Stage,
+		containerNameRandomPlaceholder, randNum,
+	).Replace(containerNameTemplate)
 }
 
 // ContainerDescribe describes the contents for container
@@ -83,6 +149,51 @@ type pullOption struct {
 	srcRef  types.ImageReference
 	dstRef  types.ImageReference
 	dstName string
+
+	progress         chan types.ProgressProperties
+	progressInterval time.Duration
+	policyStrict     bool
+}
+
+// strictRequirements returns the signature policy requirements applying
+// to the image and the scope they were matched under, following the
+// same specific-to-general lookup the policy evaluation uses
+func strictRequirements(policy *signature.Policy, ref types.ImageReference) (string, signature.PolicyRequirements) {
+	scopes, ok := policy.Transports[ref.Transport().Name()]
+	if !ok {
+		return "default", policy.Default
+	}
+	identity := ref.PolicyConfigurationIdentity()
+	if reqs, ok := scopes[identity]; ok {
+		return identity, reqs
+	}
+	for _, namespace := range ref.PolicyConfigurationNamespaces() {
+		if reqs, ok := scopes[namespace]; ok {
+			return namespace, reqs
+		}
+	}
+	if reqs, ok := scopes[""]; ok {
+		return ref.Transport().Name(), reqs
+	}
+
+	return "default", policy.Default
+}
+
+// checkStrictPolicy rejects a pull when the policy rule selected for
+// the image is insecureAcceptAnything, so a permissive policy.json
+// cannot let unsigned base images through a strict pull
+func checkStrictPolicy(policy *signature.Policy, ref types.ImageReference) error {
+	scope, reqs := strictRequirements(policy, ref)
+	data, err := json.Marshal(reqs)
+	if err != nil {
+		return errors.Wrapf(err, "inspecting the policy rule %q for image %q failed", scope, transports.ImageName(ref))
+	}
+	if strings.Contains(string(data), `"insecureAcceptAnything"`) {
+		return errors.Errorf("strict pull of %q rejected: the policy rule %q accepts any image without signature verification",
+			transports.ImageName(ref), scope)
+	}
+
+	return nil
 }
 
 func pullImage(opt pullOption) (types.ImageReference, error) {
@@ -92,6 +203,12 @@ func pullImage(opt pullOption) (types.ImageReference, error) {
 		return nil, errors.Wrapf(err, "error obtaining default signature policy")
 	}
 
+	if opt.policyStrict {
+		if err := checkStrictPolicy(policy, opt.srcRef); err != nil {
+			return nil, err
+		}
+	}
+
 	policyContext, err := signature.NewPolicyContext(policy)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error creating new signature policy context")
@@ -104,12 +221,26 @@ func pullImage(opt pullOption) (types.ImageReference, error) {
 	}()
 
 	cpOpt := &copy.Options{
-		ReportWriter:   opt.reporter,
-		SourceCtx:      opt.sc,
-		DestinationCtx: GetSystemContext(),
+		ReportWriter:     opt.reporter,
+		SourceCtx:        opt.sc,
+		DestinationCtx:   GetSystemContext(),
+		Progress:         opt.progress,
+		ProgressInterval: opt.progressInterval,
+		// 0 keeps the library default of the copy package
+		MaxParallelDownloads: util.MaxParallelDownloads(),
+	}
+	// encrypted images are decrypted with the keys of the daemon, plain
+	// images pull unchanged with a nil config
+	decConf, err := encrypt.DecryptConfig()
+	if err != nil {
+		return nil, err
 	}
+	cpOpt.OciDecryptConfig = decConf
 	pLog.Debugf("Copying %q to %q", transports.ImageName(opt.srcRef), opt.dstName)
-	if _, err := copy.Image(opt.ctx, policyContext, opt.dstRef, opt.srcRef, cpOpt); err != nil {
+	if err := util.CopyWithRetry(opt.ctx, fmt.Sprintf("pulling %q", transports.ImageName(opt.srcRef)), func() error {
+		_, cErr := copy.Image(opt.ctx, policyContext, opt.dstRef, opt.srcRef, cpOpt)
+		return cErr
+	}); err != nil {
 		pLog.Debugf("Error copying src image [%q] to dest image [%q] err: %v", transports.ImageName(opt.srcRef), opt.dstName, err)
 		return nil, err
 	}
@@ -187,12 +318,15 @@ func PullAndGetImageInfo(opt *PrepareImageOptions) (types.ImageReference, *stora
 
 		// can not find image in local store, pull from registry
 		pulledRef, err := pullImage(pullOption{
-			ctx:      opt.Ctx,
-			reporter: opt.Reporter,
-			sc:       opt.SystemContext,
-			srcRef:   srcRef,
-			dstRef:   destRef,
-			dstName:  destImage,
+			ctx:              opt.Ctx,
+			reporter:         opt.Reporter,
+			sc:               opt.SystemContext,
+			srcRef:           srcRef,
+			dstRef:           destRef,
+			dstName:          destImage,
+			progress:         opt.Progress,
+			progressInterval: opt.ProgressInterval,
+			policyStrict:     opt.PolicyStrict || pullPolicyStrict,
 		})
 		if err != nil {
 			errPull = err
@@ -324,6 +458,36 @@ func createImageV2Image(ctx context.Context, fromImage types.Image, targetMIMETy
 	return &imgSpec, nil
 }
 
+// binfmtMiscDir is where the kernel exposes the registered binfmt handlers
+const binfmtMiscDir = "/proc/sys/fs/binfmt_misc"
+
+// qemuArches maps the architecture names used in image configs to the
+// names of the QEMU user-mode binfmt handlers emulating them
+var qemuArches = map[string]string{
+	"amd64":    "x86_64",
+	"386":      "i386",
+	"arm64":    "aarch64",
+	"arm":      "arm",
+	"ppc64le":  "ppc64le",
+	"s390x":    "s390x",
+	"riscv64":  "riscv64",
+	"mips64le": "mips64el",
+}
+
+// emulationSupported returns true when a QEMU user-mode emulator is
+// registered with the kernel for the given foreign architecture
+func emulationSupported(arch string) bool {
+	qemuArch, ok := qemuArches[arch]
+	if !ok {
+		return false
+	}
+	if _, err := os.Stat(path.Join(binfmtMiscDir, "qemu-"+qemuArch)); err != nil {
+		return false
+	}
+
+	return true
+}
+
 // UpdateV2Image update the image info depending on the current environment
 func UpdateV2Image(docker *docker.Image) error {
 	if docker == nil {
@@ -348,8 +512,14 @@ func UpdateV2Image(docker *docker.Image) error {
 	}
 
 	if docker.Architecture != runtime.GOARCH {
-		// NOTE:cross-architecture build is not supported currently
-		return errors.Errorf("the architecture does not match, have %q want %q", docker.Architecture, runtime.GOARCH)
+		// foreign architectures are workable when the kernel forwards
+		// their binaries to a registered QEMU user-mode emulator
+		if !emulationSupported(docker.Architecture) {
+			return errors.Errorf("the architecture does not match, have %q want %q, "+
+				"register the qemu-user-static binfmt handlers to enable cross-architecture builds",
+				docker.Architecture, runtime.GOARCH)
+		}
+		logrus.Infof("Building %q image on %q through QEMU user-mode emulation", docker.Architecture, runtime.GOARCH)
 	}
 
 	if docker.Config.Hostname == "" {
@@ -375,20 +545,40 @@ func ResolveFromImage(opt *PrepareImageOptions) (types.Image, *storage.Image, er
 }
 
 // GetRWLayerByImageID get the RW layer by image ID
-func GetRWLayerByImageID(imgID string, store *store.Store) (*ContainerDescribe, error) {
+func GetRWLayerByImageID(ctx context.Context, imgID string, store *store.Store, nameOpt ContainerNameOptions, mappings *idtools.IDMappings, storageOpt map[string]string) (*ContainerDescribe, error) {
 	var (
 		container     *storage.Container
 		err           error
 		containerName string
 	)
 
+	// a user namespace build gets its RW layer with remapped ownership,
+	// files created by RUN then belong to the subordinate ids on the host
+	var containerOpts *storage.ContainerOptions
+	if mappings != nil && !mappings.Empty() {
+		containerOpts = &storage.ContainerOptions{}
+		containerOpts.UIDMap = mappings.UIDs()
+		containerOpts.GIDMap = mappings.GIDs()
+	}
+	// a build with a size quota gets its RW layer bounded by the
+	// filesystem project quota instead of filling the graph root
+	if len(storageOpt) != 0 {
+		if containerOpts == nil {
+			containerOpts = &storage.ContainerOptions{}
+		}
+		containerOpts.StorageOpt = storageOpt
+	}
+
 	for {
+		if cerr := ctx.Err(); cerr != nil {
+			return nil, cerr
+		}
 		randNum, rerr := util.GenerateCryptoNum(constant.DefaultIDLen)
 		if rerr != nil {
 			return nil, rerr
 		}
-		containerName = fmt.Sprintf("isula-build-%s", randNum)
-		container, err = store.CreateContainer("", []string{containerName}, imgID, "", "", nil)
+		containerName = nameOpt.containerName(randNum)
+		container, err = store.CreateContainer("", []string{containerName}, imgID, "", "", containerOpts)
 		if err == nil {
 			break
 		}
@@ -404,7 +594,14 @@ func GetRWLayerByImageID(imgID string, store *store.Store) (*ContainerDescribe,
 		}
 	}()
 
-	mountpoint, err := store.Mount(container.ID, "")
+	// mounting may block for long on a loaded storage backend, bail out
+	// promptly when the build request gets canceled meanwhile
+	var mountpoint string
+	err = util.RunWithContext(ctx, func() error {
+		var merr error
+		mountpoint, merr = store.Mount(container.ID, "")
+		return merr
+	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "error mounting build container %q", container.ID)
 	}
@@ -19,6 +19,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/transports/alltransports"
 	"golang.org/x/sys/unix"
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/assert/cmp"
@@ -219,3 +221,45 @@ func TestGetNamedTaggedReference(t *testing.T) {
 
 	}
 }
+
+func TestContainerNameTemplate(t *testing.T) {
+	defer func() {
+		containerNameTemplate = DefaultContainerNameTemplate
+	}()
+
+	nameOpt := ContainerNameOptions{BuildID: "buildid1", Stage: "stage0"}
+	assert.Equal(t, nameOpt.containerName("12345"), "isula-build-12345")
+
+	err := SetContainerNameTemplate("isula-build-{buildID}-{stage}")
+	assert.ErrorContains(t, err, "must contain the {random} placeholder")
+
+	err = SetContainerNameTemplate("isula-build-{buildID}-{stage}-{random}")
+	assert.NilError(t, err)
+	assert.Equal(t, nameOpt.containerName("12345"), "isula-build-buildid1-stage0-12345")
+}
+
+func TestCheckStrictPolicy(t *testing.T) {
+	ref, err := alltransports.ParseImageName("docker://registry.example.com/app:latest")
+	assert.NilError(t, err)
+
+	// a permissive default is rejected in strict mode
+	policy := &signature.Policy{
+		Default: signature.PolicyRequirements{signature.NewPRInsecureAcceptAnything()},
+	}
+	err = checkStrictPolicy(policy, ref)
+	assert.ErrorContains(t, err, `rule "default" accepts any image without signature verification`)
+
+	// a specific rule requiring signatures passes, even with the
+	// permissive default still in place
+	policy.Transports = map[string]signature.PolicyTransportScopes{
+		"docker": {
+			"registry.example.com": signature.PolicyRequirements{signature.NewPRReject()},
+		},
+	}
+	assert.NilError(t, checkStrictPolicy(policy, ref))
+
+	// a permissive rule scoped to the registry is named in the error
+	policy.Transports["docker"]["registry.example.com"] = signature.PolicyRequirements{signature.NewPRInsecureAcceptAnything()}
+	err = checkStrictPolicy(policy, ref)
+	assert.ErrorContains(t, err, `rule "registry.example.com" accepts`)
+}
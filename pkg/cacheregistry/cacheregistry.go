@@ -0,0 +1,361 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-05-15
+// Description: This file provides a pull-through cache registry service
+
+// Package cacheregistry serves the read-only part of the registry v2
+// API as a pull-through cache, so multiple build daemons on a LAN can
+// share the blobs one isula-build instance already downloaded from the
+// upstream registries
+package cacheregistry
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/types"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	constant "isula.org/isula-build"
+)
+
+const (
+	// blobsDirName is the dir under the cache root storing cached blobs
+	blobsDirName = "blobs"
+	// manifestsDirName is the dir under the cache root storing manifests cached by digest
+	manifestsDirName = "manifests"
+	// APIVersionHeader announces the registry v2 API to the clients
+	APIVersionHeader = "Docker-Distribution-API-Version"
+	// APIVersionValue is the version announced with APIVersionHeader
+	APIVersionValue = "registry/2.0"
+	// readHeaderTimeout bounds the time waiting for the request headers
+	readHeaderTimeout = 30 * time.Second
+	// fetchTimeout bounds one upstream manifest or blob fetch
+	fetchTimeout = 10 * time.Minute
+)
+
+// Registry is a pull-through cache registry instance
+type Registry struct {
+	sync.Mutex
+	addr      string
+	cacheRoot string
+	sysCtx    *types.SystemContext
+	server    *http.Server
+}
+
+// New returns a cache registry listening on addr and storing the
+// cached content under cacheRoot. Upstream registries are accessed
+// with sysCtx, so the daemon registry configuration applies
+func New(addr, cacheRoot string, sysCtx *types.SystemContext) (*Registry, error) {
+	for _, dir := range []string{blobsDirName, manifestsDirName} {
+		subDir, err := securejoin.SecureJoin(cacheRoot, dir)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(subDir, constant.DefaultRootDirMode); err != nil {
+			return nil, errors.Wrap(err, "create cache registry dir failed")
+		}
+	}
+
+	return &Registry{
+		addr:      addr,
+		cacheRoot: cacheRoot,
+		sysCtx:    sysCtx,
+	}, nil
+}
+
+// Serve runs the registry service until ctx is canceled
+func (r *Registry) Serve(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", r.handle)
+	r.server = &http.Server{
+		Addr:              r.addr,
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+		if err := r.server.Close(); err != nil {
+			logrus.Warnf("Closing cache registry failed: %v", err)
+		}
+	}()
+
+	logrus.Infof("Cache registry is listening on %s", r.addr)
+	if err := r.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return errors.Wrap(err, "running cache registry failed")
+	}
+
+	return nil
+}
+
+// Request is one parsed registry v2 API request
+type Request struct {
+	// Name is the repository the request refers to, may contain slashes
+	Name string
+	// Resource is "manifests" or "blobs", empty for the version check
+	Resource string
+	// Reference is a tag or a digest for manifests, a digest for blobs
+	Reference string
+}
+
+// ParsePath splits a registry v2 API path into its repository name,
+// resource type and reference
+func ParsePath(path string) (Request, error) {
+	trimmed := strings.TrimPrefix(path, "/v2/")
+	if trimmed == "" {
+		return Request{}, nil
+	}
+	for _, resource := range []string{"manifests", "blobs"} {
+		sep := "/" + resource + "/"
+		idx := strings.LastIndex(trimmed, sep)
+		if idx <= 0 {
+			continue
+		}
+		req := Request{
+			Name:      trimmed[:idx],
+			Resource:  resource,
+			Reference: trimmed[idx+len(sep):],
+		}
+		if req.Reference == "" || strings.Contains(req.Reference, "/") {
+			return Request{}, errors.Errorf("invalid reference in request path %q", path)
+		}
+		return req, nil
+	}
+
+	return Request{}, errors.Errorf("unsupported request path %q", path)
+}
+
+func (r *Registry) handle(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	apiReq, err := ParsePath(req.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set(APIVersionHeader, APIVersionValue)
+	switch apiReq.Resource {
+	case "":
+		w.WriteHeader(http.StatusOK)
+	case "manifests":
+		r.serveManifest(w, req, apiReq)
+	case "blobs":
+		r.serveBlob(w, req, apiReq)
+	}
+}
+
+// serveManifest serves a manifest request, from the cache when the
+// reference is a digest, from the upstream registry when it is a tag
+// so the clients always see the current tag content
+func (r *Registry) serveManifest(w http.ResponseWriter, req *http.Request, apiReq Request) {
+	var (
+		body []byte
+		err  error
+	)
+	if dgst, dErr := digest.Parse(apiReq.Reference); dErr == nil {
+		body, err = r.cachedFetch(req.Context(), manifestsDirName, dgst, func(ctx context.Context) ([]byte, error) {
+			return r.fetchManifest(ctx, apiReq.Name, apiReq.Reference)
+		})
+	} else {
+		body, err = r.fetchManifest(req.Context(), apiReq.Name, apiReq.Reference)
+	}
+	if err != nil {
+		logrus.Warnf("Cache registry fetching manifest %s/%s failed: %v", apiReq.Name, apiReq.Reference, err)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", manifest.GuessMIMEType(body))
+	w.Header().Set("Docker-Content-Digest", digest.FromBytes(body).String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	if req.Method == http.MethodGet {
+		w.Write(body) // nolint:errcheck
+	}
+}
+
+// serveBlob serves a blob request from the cache, fetching it from the
+// upstream registry on the first use. The blob is streamed through the
+// cache file and never buffered in memory, layers may be large
+func (r *Registry) serveBlob(w http.ResponseWriter, req *http.Request, apiReq Request) {
+	dgst, err := digest.Parse(apiReq.Reference)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	blobPath, err := r.cachedBlobPath(req.Context(), apiReq.Name, dgst)
+	if err != nil {
+		logrus.Warnf("Cache registry fetching blob %s/%s failed: %v", apiReq.Name, dgst, err)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	info, err := os.Stat(blobPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Docker-Content-Digest", dgst.String())
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+	if req.Method != http.MethodGet {
+		return
+	}
+	blobFile, err := os.Open(blobPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer blobFile.Close() // nolint:errcheck
+	io.Copy(w, blobFile)   // nolint:errcheck
+}
+
+// cachedBlobPath returns the cache file holding the blob dgst of the
+// name repository, streaming it from the upstream registry into the
+// cache on the first use
+func (r *Registry) cachedBlobPath(ctx context.Context, name string, dgst digest.Digest) (string, error) {
+	cachePath, err := securejoin.SecureJoin(r.cacheRoot, filepath.Join(blobsDirName, dgst.Algorithm().String()+"-"+dgst.Hex()))
+	if err != nil {
+		return "", err
+	}
+	if _, sErr := os.Stat(cachePath); sErr == nil {
+		return cachePath, nil
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	tmpFile, err := ioutil.TempFile(filepath.Dir(cachePath), ".fetching-")
+	if err != nil {
+		return "", err
+	}
+	verifier := dgst.Verifier()
+	err = r.fetchBlob(fetchCtx, name, dgst, io.MultiWriter(tmpFile, verifier))
+	if cErr := tmpFile.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil && !verifier.Verified() {
+		err = errors.Errorf("upstream content digest mismatches the requested %s", dgst)
+	}
+	if err != nil {
+		os.Remove(tmpFile.Name()) // nolint:errcheck
+		return "", err
+	}
+	if err := os.Rename(tmpFile.Name(), cachePath); err != nil {
+		os.Remove(tmpFile.Name()) // nolint:errcheck
+		return "", err
+	}
+
+	return cachePath, nil
+}
+
+// cachedFetch returns the content addressed by dgst from the cache dir,
+// calling fetch and filling the cache when it is not there yet
+func (r *Registry) cachedFetch(ctx context.Context, dir string, dgst digest.Digest, fetch func(context.Context) ([]byte, error)) ([]byte, error) {
+	cachePath, err := securejoin.SecureJoin(r.cacheRoot, filepath.Join(dir, dgst.Algorithm().String()+"-"+dgst.Hex()))
+	if err != nil {
+		return nil, err
+	}
+	if body, rErr := ioutil.ReadFile(cachePath); rErr == nil {
+		return body, nil
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	body, err := fetch(fetchCtx)
+	if err != nil {
+		return nil, err
+	}
+	if digest.FromBytes(body) != dgst {
+		return nil, errors.Errorf("upstream content digest mismatches the requested %s", dgst)
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(cachePath), ".fetching-")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tmpFile.Write(body); err != nil {
+		tmpFile.Close()           // nolint:errcheck
+		os.Remove(tmpFile.Name()) // nolint:errcheck
+		return nil, err
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpFile.Name()) // nolint:errcheck
+		return nil, err
+	}
+	if err := os.Rename(tmpFile.Name(), cachePath); err != nil {
+		os.Remove(tmpFile.Name()) // nolint:errcheck
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// fetchManifest gets the manifest of name:reference from the upstream registry
+func (r *Registry) fetchManifest(ctx context.Context, name, reference string) ([]byte, error) {
+	sep := ":"
+	if strings.Contains(reference, ":") {
+		sep = "@"
+	}
+	ref, err := docker.ParseReference("//" + name + sep + reference)
+	if err != nil {
+		return nil, err
+	}
+	src, err := ref.NewImageSource(ctx, r.sysCtx)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close() // nolint:errcheck
+
+	body, _, err := src.GetManifest(ctx, nil)
+	return body, err
+}
+
+// fetchBlob streams one blob of the name repository from the upstream
+// registry into w
+func (r *Registry) fetchBlob(ctx context.Context, name string, dgst digest.Digest, w io.Writer) error {
+	ref, err := docker.ParseReference("//" + name)
+	if err != nil {
+		return err
+	}
+	src, err := ref.NewImageSource(ctx, r.sysCtx)
+	if err != nil {
+		return err
+	}
+	defer src.Close() // nolint:errcheck
+
+	reader, _, err := src.GetBlob(ctx, types.BlobInfo{Digest: dgst}, none.NoCache)
+	if err != nil {
+		return err
+	}
+	defer reader.Close() // nolint:errcheck
+
+	_, err = io.Copy(w, reader)
+	return err
+}
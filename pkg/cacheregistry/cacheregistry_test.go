@@ -0,0 +1,116 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-05-15
+// Description: cache registry test
+
+package cacheregistry
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"isula.org/isula-build/image"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+		want    Request
+	}{
+		{
+			name: "version check",
+			path: "/v2/",
+			want: Request{},
+		},
+		{
+			name: "manifest by tag",
+			path: "/v2/library/busybox/manifests/latest",
+			want: Request{Name: "library/busybox", Resource: "manifests", Reference: "latest"},
+		},
+		{
+			name: "blob by digest",
+			path: "/v2/busybox/blobs/sha256:b49eda688ce8ce2b8d79e1121cb5a988be2f8a4a3b03bfb50bbecb01bbe753de",
+			want: Request{Name: "busybox", Resource: "blobs", Reference: "sha256:b49eda688ce8ce2b8d79e1121cb5a988be2f8a4a3b03bfb50bbecb01bbe753de"},
+		},
+		{
+			name:    "missing reference",
+			path:    "/v2/busybox/manifests/",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported resource",
+			path:    "/v2/busybox/tags/list",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePath(tt.path)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "request path")
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestServeCachedBlob(t *testing.T) {
+	cacheRoot := fs.NewDir(t, t.Name())
+	defer cacheRoot.Remove()
+
+	registry, err := New(":0", cacheRoot.Path(), image.GetSystemContext())
+	assert.NilError(t, err)
+
+	content := []byte("cached blob content")
+	dgst := digest.FromBytes(content)
+	blobPath := filepath.Join(cacheRoot.Path(), blobsDirName, dgst.Algorithm().String()+"-"+dgst.Hex())
+	assert.NilError(t, ioutil.WriteFile(blobPath, content, 0600))
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v2/busybox/blobs/"+dgst.String(), nil)
+	registry.handle(recorder, req)
+
+	assert.Equal(t, recorder.Code, http.StatusOK)
+	assert.Equal(t, recorder.Header().Get("Docker-Content-Digest"), dgst.String())
+	assert.DeepEqual(t, recorder.Body.Bytes(), content)
+}
+
+func TestServeVersionCheckAndBadRequests(t *testing.T) {
+	cacheRoot := fs.NewDir(t, t.Name())
+	defer cacheRoot.Remove()
+
+	registry, err := New(":0", cacheRoot.Path(), image.GetSystemContext())
+	assert.NilError(t, err)
+
+	recorder := httptest.NewRecorder()
+	registry.handle(recorder, httptest.NewRequest(http.MethodGet, "/v2/", nil))
+	assert.Equal(t, recorder.Code, http.StatusOK)
+	assert.Equal(t, recorder.Header().Get(APIVersionHeader), APIVersionValue)
+
+	recorder = httptest.NewRecorder()
+	registry.handle(recorder, httptest.NewRequest(http.MethodPut, "/v2/busybox/manifests/latest", nil))
+	assert.Equal(t, recorder.Code, http.StatusMethodNotAllowed)
+
+	recorder = httptest.NewRecorder()
+	registry.handle(recorder, httptest.NewRequest(http.MethodGet, "/v2/busybox/blobs/not-a-digest", nil))
+	assert.Equal(t, recorder.Code, http.StatusBadRequest)
+}
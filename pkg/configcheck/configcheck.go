@@ -0,0 +1,216 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-07-05
+// Description: daemon configuration validation
+
+// Package configcheck validates the daemon configuration files and
+// reports every problem found instead of stopping at the first one
+package configcheck
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/containers/image/v5/pkg/sysregistriesv2"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+	"github.com/sirupsen/logrus"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/cmd/daemon/config"
+	"isula.org/isula-build/store"
+	"isula.org/isula-build/util"
+)
+
+// Problem is one issue found in a configuration file
+type Problem struct {
+	// File is the configuration file the problem was found in
+	File string
+	// Detail describes the problem, toml parse errors carry line numbers
+	Detail string
+}
+
+// String formats the problem for user output
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.File, p.Detail)
+}
+
+// supportedDigestAlgorithms mirrors the algorithms accepted by the daemon
+var supportedDigestAlgorithms = map[string]bool{"sha256": true, "sha512": true}
+
+// delegatablePrivileges mirrors the build features the daemon can
+// delegate to non-root callers
+var delegatablePrivileges = map[string]bool{"cap-add": true}
+
+// CheckAll validates the main configuration, the storage options, the
+// registries configuration and the signature policy, collecting every
+// problem found. Missing optional files are skipped, only the signature
+// policy is required to exist
+func CheckAll(mainConfigPath string) []Problem {
+	var problems []Problem
+
+	if exist, _ := util.IsExist(mainConfigPath); exist {
+		problems = append(problems, CheckMainConfig(mainConfigPath)...)
+	}
+	if exist, _ := util.IsExist(constant.StorageConfigPath); exist {
+		problems = append(problems, checkStorageConfig(constant.StorageConfigPath)...)
+	}
+	if exist, _ := util.IsExist(constant.RegistryConfigPath); exist {
+		problems = append(problems, checkRegistryConfig(constant.RegistryConfigPath)...)
+	}
+	if exist, _ := util.IsExist(constant.SignaturePolicyPath); exist {
+		problems = append(problems, checkSignaturePolicy(constant.SignaturePolicyPath)...)
+	} else {
+		problems = append(problems, Problem{File: constant.SignaturePolicyPath, Detail: "file does not exist"})
+	}
+
+	return problems
+}
+
+// CheckMainConfig validates one main configuration file, including keys
+// the daemon would silently ignore
+func CheckMainConfig(path string) []Problem {
+	var problems []Problem
+	appendProblem := func(format string, args ...interface{}) {
+		problems = append(problems, Problem{File: path, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	var conf config.TomlConfig
+	metadata, err := toml.DecodeFile(filepath.Clean(path), &conf)
+	if err != nil {
+		appendProblem("%v", err)
+		return problems
+	}
+	for _, key := range metadata.Undecoded() {
+		appendProblem("unknown key %q", key.String())
+	}
+
+	if conf.LogLevel != "" {
+		if _, lErr := logrus.ParseLevel(conf.LogLevel); lErr != nil {
+			appendProblem("invalid loglevel %q, expected one of debug, info, warn or error", conf.LogLevel)
+		}
+	}
+	if conf.RunRoot != "" && !filepath.IsAbs(conf.RunRoot) {
+		appendProblem("run_root %q must be an absolute path", conf.RunRoot)
+	}
+	if conf.DataRoot != "" && !filepath.IsAbs(conf.DataRoot) {
+		appendProblem("data_root %q must be an absolute path", conf.DataRoot)
+	}
+	if conf.DigestAlgorithm != "" && !supportedDigestAlgorithms[conf.DigestAlgorithm] {
+		appendProblem("digest_algorithm %q is not supported, expected sha256 or sha512", conf.DigestAlgorithm)
+	}
+	if conf.P2PProxy != "" {
+		if proxy, pErr := url.Parse(conf.P2PProxy); pErr != nil ||
+			(proxy.Scheme != "http" && proxy.Scheme != "https") || proxy.Host == "" {
+			appendProblem("p2p_proxy %q must be an http(s) URL with a host", conf.P2PProxy)
+		}
+	}
+	if conf.Addr != "" && !strings.HasPrefix(conf.Addr, constant.UnixPrefix) {
+		appendProblem("addr %q must be a unix:// socket address", conf.Addr)
+	}
+	if conf.BuildAddr != "" && !strings.HasPrefix(conf.BuildAddr, constant.UnixPrefix) {
+		appendProblem("build_addr %q must be a unix:// socket address", conf.BuildAddr)
+	}
+	if conf.BuildAddr != "" && conf.BuildAddr == conf.Addr {
+		appendProblem("build_addr %q must differ from addr", conf.BuildAddr)
+	}
+	if conf.SockMode != "" {
+		if mode, mErr := strconv.ParseUint(conf.SockMode, 8, 32); mErr != nil || mode > 0777 {
+			appendProblem("sock_mode %q must be an octal mode like 0660", conf.SockMode)
+		}
+	}
+	if conf.RateLimit < 0 {
+		appendProblem("rate_limit %d must not be negative", conf.RateLimit)
+	}
+	if conf.RateBurst < 0 {
+		appendProblem("rate_burst %d must not be negative", conf.RateBurst)
+	}
+	if conf.MaxRecvMsgSize < 0 {
+		appendProblem("max_recv_msg_size %d must not be negative", conf.MaxRecvMsgSize)
+	}
+	if conf.MaxParallelDownloads < 0 {
+		appendProblem("max_parallel_downloads %d must not be negative", conf.MaxParallelDownloads)
+	}
+	if conf.MaxParallelUploads < 0 {
+		appendProblem("max_parallel_uploads %d must not be negative", conf.MaxParallelUploads)
+	}
+	if conf.CopyRetries < 0 {
+		appendProblem("copy_retries %d must not be negative", conf.CopyRetries)
+	}
+	if conf.CopyRetryBackoff != "" {
+		if wait, wErr := time.ParseDuration(conf.CopyRetryBackoff); wErr != nil || wait <= 0 {
+			appendProblem("copy_retry_backoff %q must be a positive duration like 1s", conf.CopyRetryBackoff)
+		}
+	}
+	if conf.KeepaliveInterval != "" {
+		if interval, kErr := time.ParseDuration(conf.KeepaliveInterval); kErr != nil || interval <= 0 {
+			appendProblem("keepalive_interval %q must be a positive duration like 30s", conf.KeepaliveInterval)
+		}
+	}
+	if conf.KeepaliveTimeout != "" {
+		if timeout, kErr := time.ParseDuration(conf.KeepaliveTimeout); kErr != nil || timeout <= 0 {
+			appendProblem("keepalive_timeout %q must be a positive duration like 20s", conf.KeepaliveTimeout)
+		}
+	}
+	for _, privilege := range strings.Split(conf.DelegatedPrivileges, ",") {
+		if privilege = strings.TrimSpace(privilege); privilege != "" && !delegatablePrivileges[privilege] {
+			appendProblem("delegated_privileges entry %q is not a delegatable build feature", privilege)
+		}
+	}
+	if conf.RecordRetention != "" {
+		if keep, kErr := time.ParseDuration(conf.RecordRetention); kErr != nil || keep <= 0 {
+			appendProblem("record_retention %q must be a positive duration like 720h", conf.RecordRetention)
+		}
+	}
+	for name, env := range conf.Environments {
+		if env.Registry == "" {
+			appendProblem("environment %q must set a registry", name)
+		}
+	}
+
+	return problems
+}
+
+// checkStorageConfig validates the storage configuration by parsing it
+// the same way the daemon does on startup
+func checkStorageConfig(path string) []Problem {
+	store.SetStorageConfigFilePath(path)
+	if _, err := store.GetStorageConfigFileOptions(); err != nil {
+		return []Problem{{File: path, Detail: err.Error()}}
+	}
+
+	return nil
+}
+
+// checkRegistryConfig validates the registries configuration with the
+// parser used for image pull and push
+func checkRegistryConfig(path string) []Problem {
+	sc := &types.SystemContext{SystemRegistriesConfPath: path}
+	if _, err := sysregistriesv2.GetRegistries(sc); err != nil {
+		return []Problem{{File: path, Detail: err.Error()}}
+	}
+
+	return nil
+}
+
+// checkSignaturePolicy validates the signature policy file
+func checkSignaturePolicy(path string) []Problem {
+	if _, err := signature.NewPolicyFromFile(path); err != nil {
+		return []Problem{{File: path, Detail: err.Error()}}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-07-05
+// Description: daemon configuration validation tests
+
+package configcheck
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestCheckMainConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantProblems []string
+	}{
+		{
+			name:    "valid configuration",
+			content: "debug = false\nloglevel = \"info\"\nrun_root = \"/var/run/isula-build\"\ndata_root = \"/var/lib/isula-build\"\n",
+		},
+		{
+			name:         "broken toml reports the line",
+			content:      "debug = false\nloglevel = 3bad\n",
+			wantProblems: []string{"line 2"},
+		},
+		{
+			name:         "unknown key",
+			content:      "debug = false\nlog_level = \"info\"\n",
+			wantProblems: []string{`unknown key "log_level"`},
+		},
+		{
+			name:         "invalid socket address and mode",
+			content:      "addr = \"tcp://127.0.0.1:5000\"\nsock_mode = \"0999\"\n",
+			wantProblems: []string{"must be a unix:// socket address", "must be an octal mode like 0660"},
+		},
+		{
+			name:    "several problems reported at once",
+			content: "loglevel = \"chatty\"\ndata_root = \"relative/path\"\ndigest_algorithm = \"md5\"\np2p_proxy = \"unix:///run/dfdaemon.sock\"\n",
+			wantProblems: []string{
+				`invalid loglevel "chatty"`,
+				`data_root "relative/path" must be an absolute path`,
+				`digest_algorithm "md5" is not supported`,
+				"must be an http(s) URL with a host",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			confFile := fs.NewFile(t, t.Name(), fs.WithContent(tt.content))
+			defer confFile.Remove()
+
+			problems := CheckMainConfig(confFile.Path())
+			assert.Equal(t, len(problems), len(tt.wantProblems))
+			for i, want := range tt.wantProblems {
+				assert.Assert(t, strings.Contains(problems[i].Detail, want),
+					"problem %q does not contain %q", problems[i].Detail, want)
+				assert.Equal(t, problems[i].File, confFile.Path())
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-08
+// Description: exporter artifact conformance checks
+
+// Package conformance verifies that exported image artifacts are
+// structurally sound, so the tools consuming them (docker, isulad, OCI
+// tooling) can load them back
+package conformance
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+
+	constant "isula.org/isula-build"
+)
+
+// maxMetaFileSize bounds the metadata files parsed out of an archive
+const maxMetaFileSize = 1024 * 1024
+
+// squashfsMagic is the little-endian magic of a squashfs superblock
+const squashfsMagic = "hsqs"
+
+// dockerManifestEntry is one image entry of a docker-archive manifest.json
+type dockerManifestEntry struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+// CheckArtifact verifies the artifact at path produced by the exporter
+// of the given transport
+func CheckArtifact(transport, path string) error {
+	switch transport {
+	case constant.DockerArchiveTransport:
+		return checkDockerArchive(path)
+	case constant.OCIArchiveTransport:
+		return checkOCIArchive(path)
+	case constant.OCITransport:
+		return checkOCILayout(path)
+	case constant.SquashfsTransport:
+		return checkSquashfs(path)
+	case constant.DiskImageTransport:
+		return checkRegularFile(path)
+	case constant.SysrootTransport, constant.LocalTransport:
+		return checkSysroot(path)
+	default:
+		return errors.Errorf("no conformance check for transport %q", transport)
+	}
+}
+
+// readTarMeta walks the tar archive at path and returns the names of
+// all its entries along with the content of the wanted metadata files
+func readTarMeta(path string, wanted ...string) (map[string]bool, map[string][]byte, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "open archive %q failed", path)
+	}
+	defer f.Close()
+
+	wantedSet := make(map[string]bool, len(wanted))
+	for _, name := range wanted {
+		wantedSet[name] = true
+	}
+
+	names := make(map[string]bool)
+	meta := make(map[string][]byte, len(wanted))
+	tr := tar.NewReader(f)
+	for {
+		hdr, nErr := tr.Next()
+		if nErr == io.EOF {
+			break
+		}
+		if nErr != nil {
+			return nil, nil, errors.Wrapf(nErr, "read archive %q failed", path)
+		}
+		name := strings.TrimPrefix(filepath.Clean(hdr.Name), "./")
+		names[name] = true
+		if !wantedSet[name] {
+			continue
+		}
+		content, rErr := ioutil.ReadAll(io.LimitReader(tr, maxMetaFileSize))
+		if rErr != nil {
+			return nil, nil, errors.Wrapf(rErr, "read %q from archive %q failed", name, path)
+		}
+		meta[name] = content
+	}
+
+	return names, meta, nil
+}
+
+// checkDockerArchive verifies a docker-archive tarball, the format
+// docker load consumes
+func checkDockerArchive(path string) error {
+	names, meta, err := readTarMeta(path, "manifest.json")
+	if err != nil {
+		return err
+	}
+
+	manifestRaw, ok := meta["manifest.json"]
+	if !ok {
+		return errors.New("archive has no manifest.json")
+	}
+	var entries []dockerManifestEntry
+	if err := json.Unmarshal(manifestRaw, &entries); err != nil {
+		return errors.Wrap(err, "parse manifest.json failed")
+	}
+	if len(entries) == 0 {
+		return errors.New("manifest.json lists no images")
+	}
+	for i, entry := range entries {
+		if entry.Config == "" {
+			return errors.Errorf("image %d has no config", i)
+		}
+		if !names[filepath.Clean(entry.Config)] {
+			return errors.Errorf("config %q referenced by image %d is not in the archive", entry.Config, i)
+		}
+		if len(entry.Layers) == 0 {
+			return errors.Errorf("image %d has no layers", i)
+		}
+		for _, layer := range entry.Layers {
+			if !names[filepath.Clean(layer)] {
+				return errors.Errorf("layer %q referenced by image %d is not in the archive", layer, i)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkOCIIndex verifies the raw index.json of an OCI layout
+func checkOCIIndex(indexRaw []byte) error {
+	var index imgspecv1.Index
+	if err := json.Unmarshal(indexRaw, &index); err != nil {
+		return errors.Wrap(err, "parse index.json failed")
+	}
+	if len(index.Manifests) == 0 {
+		return errors.New("index.json lists no manifests")
+	}
+	for i, desc := range index.Manifests {
+		if err := desc.Digest.Validate(); err != nil {
+			return errors.Wrapf(err, "manifest %d has an invalid digest %q", i, desc.Digest)
+		}
+	}
+
+	return nil
+}
+
+// checkOCILayoutVersion verifies the raw oci-layout file
+func checkOCILayoutVersion(layoutRaw []byte) error {
+	var layout imgspecv1.ImageLayout
+	if err := json.Unmarshal(layoutRaw, &layout); err != nil {
+		return errors.Wrap(err, "parse oci-layout failed")
+	}
+	if layout.Version == "" {
+		return errors.New("oci-layout has no imageLayoutVersion")
+	}
+
+	return nil
+}
+
+// checkOCIArchive verifies an oci-archive tarball
+func checkOCIArchive(path string) error {
+	names, meta, err := readTarMeta(path, "index.json", imgspecv1.ImageLayoutFile)
+	if err != nil {
+		return err
+	}
+
+	indexRaw, ok := meta["index.json"]
+	if !ok {
+		return errors.New("archive has no index.json")
+	}
+	if err := checkOCIIndex(indexRaw); err != nil {
+		return err
+	}
+	layoutRaw, ok := meta[imgspecv1.ImageLayoutFile]
+	if !ok {
+		return errors.Errorf("archive has no %s file", imgspecv1.ImageLayoutFile)
+	}
+	if err := checkOCILayoutVersion(layoutRaw); err != nil {
+		return err
+	}
+
+	hasBlobs := false
+	for name := range names {
+		if strings.HasPrefix(name, "blobs/") {
+			hasBlobs = true
+			break
+		}
+	}
+	if !hasBlobs {
+		return errors.New("archive has no blobs directory")
+	}
+
+	return nil
+}
+
+// checkOCILayout verifies an OCI layout directory
+func checkOCILayout(path string) error {
+	indexRaw, err := ioutil.ReadFile(filepath.Join(path, "index.json"))
+	if err != nil {
+		return errors.Wrap(err, "read index.json failed")
+	}
+	if err := checkOCIIndex(indexRaw); err != nil {
+		return err
+	}
+	layoutRaw, err := ioutil.ReadFile(filepath.Join(path, imgspecv1.ImageLayoutFile))
+	if err != nil {
+		return errors.Wrapf(err, "read %s failed", imgspecv1.ImageLayoutFile)
+	}
+	if err := checkOCILayoutVersion(layoutRaw); err != nil {
+		return err
+	}
+
+	blobs, err := ioutil.ReadDir(filepath.Join(path, "blobs"))
+	if err != nil {
+		return errors.Wrap(err, "read blobs directory failed")
+	}
+	if len(blobs) == 0 {
+		return errors.New("blobs directory is empty")
+	}
+
+	return nil
+}
+
+// checkSquashfs verifies the superblock magic of a squashfs image
+func checkSquashfs(path string) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return errors.Wrapf(err, "open squashfs image %q failed", path)
+	}
+	defer f.Close()
+
+	magic := make([]byte, len(squashfsMagic))
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return errors.Wrapf(err, "read squashfs superblock of %q failed", path)
+	}
+	if string(magic) != squashfsMagic {
+		return errors.Errorf("%q is not a squashfs image", path)
+	}
+
+	return nil
+}
+
+// checkRegularFile verifies that path is a non-empty regular file
+func checkRegularFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrapf(err, "stat %q failed", path)
+	}
+	if !info.Mode().IsRegular() {
+		return errors.Errorf("%q is not a regular file", path)
+	}
+	if info.Size() == 0 {
+		return errors.Errorf("%q is empty", path)
+	}
+
+	return nil
+}
+
+// checkSysroot verifies that path is a non-empty directory
+func checkSysroot(path string) error {
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return errors.Wrapf(err, "read sysroot %q failed", path)
+	}
+	if len(entries) == 0 {
+		return errors.Errorf("sysroot %q is empty", path)
+	}
+
+	return nil
+}
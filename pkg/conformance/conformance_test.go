@@ -0,0 +1,154 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-08
+// Description: exporter artifact conformance tests
+
+package conformance
+
+import (
+	"archive/tar"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	constant "isula.org/isula-build"
+)
+
+const testIndex = `{
+  "schemaVersion": 2,
+  "manifests": [
+    {
+      "mediaType": "application/vnd.oci.image.manifest.v1+json",
+      "digest": "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+      "size": 1024
+    }
+  ]
+}`
+
+const testLayout = `{"imageLayoutVersion": "1.0.0"}`
+
+// writeTar writes a tar archive with the given files to a temp file and
+// returns its path
+func writeTar(t *testing.T, files map[string]string) string {
+	dir := fs.NewDir(t, "conformance")
+	t.Cleanup(dir.Remove)
+	f, err := os.Create(dir.Join("archive.tar"))
+	assert.NilError(t, err)
+	defer f.Close()
+
+	tw := tar.NewWriter(f)
+	for name, content := range files {
+		err = tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(content)), Mode: 0600})
+		assert.NilError(t, err)
+		_, err = tw.Write([]byte(content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+
+	return f.Name()
+}
+
+func TestCheckDockerArchive(t *testing.T) {
+	valid := writeTar(t, map[string]string{
+		"manifest.json": `[{"Config": "config.json", "RepoTags": ["app:latest"], "Layers": ["layer.tar"]}]`,
+		"config.json":   "{}",
+		"layer.tar":     "layer",
+	})
+	assert.NilError(t, CheckArtifact(constant.DockerArchiveTransport, valid))
+
+	missingLayer := writeTar(t, map[string]string{
+		"manifest.json": `[{"Config": "config.json", "Layers": ["layer.tar"]}]`,
+		"config.json":   "{}",
+	})
+	err := CheckArtifact(constant.DockerArchiveTransport, missingLayer)
+	assert.ErrorContains(t, err, "is not in the archive")
+
+	noManifest := writeTar(t, map[string]string{"config.json": "{}"})
+	err = CheckArtifact(constant.DockerArchiveTransport, noManifest)
+	assert.ErrorContains(t, err, "no manifest.json")
+}
+
+func TestCheckOCIArchive(t *testing.T) {
+	valid := writeTar(t, map[string]string{
+		"index.json": testIndex,
+		"oci-layout": testLayout,
+		"blobs/sha256/e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855": "{}",
+	})
+	assert.NilError(t, CheckArtifact(constant.OCIArchiveTransport, valid))
+
+	noBlobs := writeTar(t, map[string]string{
+		"index.json": testIndex,
+		"oci-layout": testLayout,
+	})
+	err := CheckArtifact(constant.OCIArchiveTransport, noBlobs)
+	assert.ErrorContains(t, err, "no blobs directory")
+
+	badIndex := writeTar(t, map[string]string{
+		"index.json": `{"schemaVersion": 2, "manifests": []}`,
+		"oci-layout": testLayout,
+	})
+	err = CheckArtifact(constant.OCIArchiveTransport, badIndex)
+	assert.ErrorContains(t, err, "no manifests")
+}
+
+func TestCheckOCILayout(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("index.json", testIndex),
+		fs.WithFile("oci-layout", testLayout),
+		fs.WithDir("blobs", fs.WithDir("sha256",
+			fs.WithFile("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "{}"))))
+	defer dir.Remove()
+	assert.NilError(t, CheckArtifact(constant.OCITransport, dir.Path()))
+
+	empty := fs.NewDir(t, t.Name())
+	defer empty.Remove()
+	err := CheckArtifact(constant.OCITransport, empty.Path())
+	assert.ErrorContains(t, err, "read index.json failed")
+}
+
+func TestCheckSquashfs(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	defer dir.Remove()
+	image := dir.Join("rootfs.sqfs")
+	assert.NilError(t, ioutil.WriteFile(image, []byte("hsqs"+strings.Repeat("\x00", 32)), 0600))
+	assert.NilError(t, CheckArtifact(constant.SquashfsTransport, image))
+
+	notSquashfs := dir.Join("other.sqfs")
+	assert.NilError(t, ioutil.WriteFile(notSquashfs, []byte("not a squashfs image"), 0600))
+	err := CheckArtifact(constant.SquashfsTransport, notSquashfs)
+	assert.ErrorContains(t, err, "is not a squashfs image")
+}
+
+func TestCheckSysrootAndFile(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("etc", ""))
+	defer dir.Remove()
+	assert.NilError(t, CheckArtifact(constant.SysrootTransport, dir.Path()))
+
+	empty := fs.NewDir(t, t.Name())
+	defer empty.Remove()
+	err := CheckArtifact(constant.SysrootTransport, empty.Path())
+	assert.ErrorContains(t, err, "is empty")
+
+	image := empty.Join("image.img")
+	assert.NilError(t, ioutil.WriteFile(image, []byte("data"), 0600))
+	assert.NilError(t, CheckArtifact(constant.DiskImageTransport, image))
+
+	assert.NilError(t, ioutil.WriteFile(image, nil, 0600))
+	err = CheckArtifact(constant.DiskImageTransport, image)
+	assert.ErrorContains(t, err, "is empty")
+
+	err = CheckArtifact("docker", os.DevNull)
+	assert.ErrorContains(t, err, "no conformance check")
+}
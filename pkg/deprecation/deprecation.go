@@ -0,0 +1,96 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: deprecation notices passed from daemon to client
+
+// Package deprecation lets the daemon attach deprecation notices to its
+// GRPC responses as metadata, which the client renders once per invocation.
+// This gives users advance warning when a flag or format is being sunset,
+// without breaking older clients which simply ignore the metadata
+package deprecation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataKey is the GRPC metadata key carrying the deprecation notices
+const MetadataKey = "isula-build-deprecation"
+
+var (
+	mu sync.Mutex
+	// notices maps a feature key used in a request (e.g. "build.format.docker")
+	// to the message shown to the user; empty until a feature gets sunset
+	notices = map[string]string{}
+	// printed remembers the notices already rendered in this invocation
+	printed = map[string]bool{}
+)
+
+// Register declares the feature with the given key as deprecated. It is
+// meant to be called from init code when a release starts sunsetting a
+// flag or format
+func Register(feature, message string) {
+	mu.Lock()
+	defer mu.Unlock()
+	notices[feature] = message
+}
+
+// Attach adds the notices of the given deprecated features to the
+// response header metadata of the GRPC call in ctx. Features without a
+// registered notice are ignored
+func Attach(ctx context.Context, features ...string) {
+	msgs := messagesFor(features)
+	if len(msgs) == 0 {
+		return
+	}
+	md := metadata.MD{MetadataKey: msgs}
+	if err := grpc.SetHeader(ctx, md); err != nil {
+		logrus.Debugf("Attach deprecation notices failed: %v", err)
+	}
+}
+
+// messagesFor returns the registered messages of the given features
+func messagesFor(features []string) []string {
+	mu.Lock()
+	defer mu.Unlock()
+	var msgs []string
+	for _, feature := range features {
+		if msg, ok := notices[feature]; ok {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// Extract picks the deprecation notices out of response metadata
+func Extract(md metadata.MD) []string {
+	return md.Get(MetadataKey)
+}
+
+// Print renders the given notices to w, each notice only once per
+// process even when several responses carry it
+func Print(w io.Writer, msgs []string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, msg := range msgs {
+		if printed[msg] {
+			continue
+		}
+		printed[msg] = true
+		fmt.Fprintf(w, "[Deprecated] %s\n", msg)
+	}
+}
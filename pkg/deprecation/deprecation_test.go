@@ -0,0 +1,73 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: deprecation notices related tests
+
+package deprecation
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"gotest.tools/v3/assert"
+)
+
+// fakeServerTransportStream collects the header metadata set by Attach
+type fakeServerTransportStream struct {
+	md metadata.MD
+}
+
+func (f *fakeServerTransportStream) Method() string { return "/isula.build.v1.Control/Build" }
+func (f *fakeServerTransportStream) SetHeader(md metadata.MD) error {
+	f.md = metadata.Join(f.md, md)
+	return nil
+}
+func (f *fakeServerTransportStream) SendHeader(md metadata.MD) error { return nil }
+func (f *fakeServerTransportStream) SetTrailer(md metadata.MD) error { return nil }
+
+func resetForTest() {
+	mu.Lock()
+	defer mu.Unlock()
+	notices = map[string]string{}
+	printed = map[string]bool{}
+}
+
+func TestAttachAndExtract(t *testing.T) {
+	resetForTest()
+	Register("build.flag.old-flag", "the --old-flag option will be removed in a future release")
+
+	stream := &fakeServerTransportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+
+	// features without a registered notice attach nothing
+	Attach(ctx, "build.format.docker")
+	assert.Equal(t, len(Extract(stream.md)), 0)
+
+	Attach(ctx, "build.format.docker", "build.flag.old-flag")
+	msgs := Extract(stream.md)
+	assert.Equal(t, len(msgs), 1)
+	assert.Equal(t, msgs[0], "the --old-flag option will be removed in a future release")
+}
+
+func TestPrintOnce(t *testing.T) {
+	resetForTest()
+
+	buf := &bytes.Buffer{}
+	Print(buf, []string{"notice one"})
+	assert.Equal(t, buf.String(), "[Deprecated] notice one\n")
+
+	// the same notice is not rendered twice in one invocation
+	Print(buf, []string{"notice one", "notice two"})
+	assert.Equal(t, buf.String(), "[Deprecated] notice one\n[Deprecated] notice two\n")
+}
@@ -0,0 +1,150 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: layer encryption related functions
+
+// Package encrypt holds the ocicrypt layer encryption configuration of
+// the daemon, registry pushes encrypt layers for the configured
+// recipients and pulls decrypt them with the configured keys
+package encrypt
+
+import (
+	"io/ioutil"
+	"sync"
+
+	encconfig "github.com/containers/ocicrypt/config"
+	"github.com/pkg/errors"
+
+	constant "isula.org/isula-build"
+)
+
+// Config describes the layer encryption of the daemon
+type Config struct {
+	// Method is "jwe", "pkcs7" or "keyprovider"
+	Method string
+	// PublicKeys are the recipient key files layers are encrypted for
+	// on export, with the keyprovider method the entries are the raw
+	// provider parameters instead of files
+	PublicKeys []string
+	// PrivateKeys are the key files decrypting layers on pull, with the
+	// keyprovider method the entries are the raw provider parameters
+	PrivateKeys []string
+}
+
+// crypt is the encryption configuration the daemon was started with
+var crypt struct {
+	sync.RWMutex
+	conf Config
+}
+
+// Init sets the layer encryption configuration of the daemon
+func Init(conf Config) {
+	crypt.Lock()
+	crypt.conf = conf
+	crypt.Unlock()
+}
+
+// CheckMethod validates an encryption method from the configuration
+func CheckMethod(method string) error {
+	switch method {
+	case constant.EncryptMethodJWE, constant.EncryptMethodPKCS7, constant.EncryptMethodKeyProvider:
+		return nil
+	default:
+		return errors.Errorf("invalid encryption method %q, expected %s, %s or %s",
+			method, constant.EncryptMethodJWE, constant.EncryptMethodPKCS7, constant.EncryptMethodKeyProvider)
+	}
+}
+
+// config returns a copy of the current configuration
+func config() Config {
+	crypt.RLock()
+	defer crypt.RUnlock()
+	return crypt.conf
+}
+
+// readKeys loads the configured keys, keyprovider parameters are used
+// as they are while the other methods name key files
+func readKeys(method string, entries []string) ([][]byte, error) {
+	keys := make([][]byte, 0, len(entries))
+	for _, entry := range entries {
+		if method == constant.EncryptMethodKeyProvider {
+			keys = append(keys, []byte(entry))
+			continue
+		}
+		key, err := ioutil.ReadFile(entry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read encryption key %q failed", entry)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// EncryptConfig returns the layer encryption applied to registry
+// pushes, nil when no recipients are configured
+func EncryptConfig() (*encconfig.EncryptConfig, error) {
+	conf := config()
+	if len(conf.PublicKeys) == 0 {
+		return nil, nil
+	}
+
+	keys, err := readKeys(conf.Method, conf.PublicKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	var cc encconfig.CryptoConfig
+	switch conf.Method {
+	case constant.EncryptMethodJWE:
+		cc, err = encconfig.EncryptWithJwe(keys)
+	case constant.EncryptMethodPKCS7:
+		cc, err = encconfig.EncryptWithPkcs7(keys)
+	case constant.EncryptMethodKeyProvider:
+		cc, err = encconfig.EncryptWithKeyProvider(keys)
+	default:
+		return nil, errors.Errorf("invalid encryption method %q", conf.Method)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "build %s encryption config failed", conf.Method)
+	}
+
+	return cc.EncryptConfig, nil
+}
+
+// DecryptConfig returns the layer decryption applied to pulls, nil when
+// no keys are configured
+func DecryptConfig() (*encconfig.DecryptConfig, error) {
+	conf := config()
+	if len(conf.PrivateKeys) == 0 {
+		return nil, nil
+	}
+
+	keys, err := readKeys(conf.Method, conf.PrivateKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	var cc encconfig.CryptoConfig
+	switch conf.Method {
+	case constant.EncryptMethodJWE, constant.EncryptMethodPKCS7:
+		cc, err = encconfig.DecryptWithPrivKeys(keys, make([][]byte, len(keys)))
+	case constant.EncryptMethodKeyProvider:
+		cc, err = encconfig.DecryptWithKeyProvider(keys)
+	default:
+		return nil, errors.Errorf("invalid encryption method %q", conf.Method)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "build %s decryption config failed", conf.Method)
+	}
+
+	return cc.DecryptConfig, nil
+}
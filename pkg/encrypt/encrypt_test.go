@@ -0,0 +1,71 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: layer encryption related tests
+
+package encrypt
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	constant "isula.org/isula-build"
+)
+
+func TestCheckMethod(t *testing.T) {
+	assert.NilError(t, CheckMethod(constant.EncryptMethodJWE))
+	assert.NilError(t, CheckMethod(constant.EncryptMethodPKCS7))
+	assert.NilError(t, CheckMethod(constant.EncryptMethodKeyProvider))
+	assert.ErrorContains(t, CheckMethod("aes"), "invalid encryption method")
+	assert.ErrorContains(t, CheckMethod(""), "invalid encryption method")
+}
+
+func TestEncryptAndDecryptConfig(t *testing.T) {
+	defer Init(Config{})
+
+	// nothing configured keeps layers plain
+	ec, err := EncryptConfig()
+	assert.NilError(t, err)
+	assert.Assert(t, ec == nil)
+	dc, err := DecryptConfig()
+	assert.NilError(t, err)
+	assert.Assert(t, dc == nil)
+
+	pubKey := fs.NewFile(t, "image.pub", fs.WithContent("public key"))
+	defer pubKey.Remove()
+	privKey := fs.NewFile(t, "image.pem", fs.WithContent("private key"))
+	defer privKey.Remove()
+
+	Init(Config{
+		Method:      constant.EncryptMethodJWE,
+		PublicKeys:  []string{pubKey.Path()},
+		PrivateKeys: []string{privKey.Path()},
+	})
+	ec, err = EncryptConfig()
+	assert.NilError(t, err)
+	assert.Assert(t, ec != nil)
+	dc, err = DecryptConfig()
+	assert.NilError(t, err)
+	assert.Assert(t, dc != nil)
+
+	// a missing key file is reported
+	Init(Config{Method: constant.EncryptMethodJWE, PublicKeys: []string{"/not/exist/key.pub"}})
+	_, err = EncryptConfig()
+	assert.ErrorContains(t, err, "read encryption key")
+
+	// keyprovider parameters are used as they are, not read as files
+	Init(Config{Method: constant.EncryptMethodKeyProvider, PrivateKeys: []string{"provider:attrs"}})
+	dc, err = DecryptConfig()
+	assert.NilError(t, err)
+	assert.Assert(t, dc != nil)
+}
@@ -134,6 +134,21 @@ func (g *GarbageCollector) RegisterGC(option *RegisterOption) error {
 	return nil
 }
 
+// Busy reports whether any registered recycling function is running
+func (g *GarbageCollector) Busy() bool {
+	g.RLock()
+	defer g.RUnlock()
+	for _, n := range g.nodes {
+		n.Lock()
+		running := n.running
+		n.Unlock()
+		if running {
+			return true
+		}
+	}
+	return false
+}
+
 // RemoveGCNode removes the GC function
 func (g *GarbageCollector) RemoveGCNode(name string) {
 	g.Lock()
@@ -0,0 +1,115 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-22
+// Description: git build context fetching
+
+// Package gitcontext fetches build contexts from git repositories, so a
+// build can be started from a URL like repo.git#branch:subdir instead
+// of a local directory
+package gitcontext
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/pkg/errors"
+
+	constant "isula.org/isula-build"
+)
+
+// IsGitURL returns true when the build context argument refers to a git
+// repository instead of a local directory
+func IsGitURL(s string) bool {
+	if strings.HasPrefix(s, "git://") || strings.HasPrefix(s, "git@") {
+		return true
+	}
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+		repo, _, _ := Parse(s)
+		return strings.HasSuffix(repo, ".git")
+	}
+
+	return false
+}
+
+// Parse splits a git context URL into the repository, the ref to check
+// out and the sub directory used as context. The fragment follows the
+// docker build syntax: repo.git#ref, repo.git#ref:subdir, repo.git#:subdir
+func Parse(rawURL string) (repo, ref, subdir string) {
+	repo = rawURL
+	idx := strings.Index(rawURL, "#")
+	if idx == -1 {
+		return repo, "", ""
+	}
+
+	repo, fragment := rawURL[:idx], rawURL[idx+1:]
+	if cIdx := strings.Index(fragment, ":"); cIdx != -1 {
+		return repo, fragment[:cIdx], fragment[cIdx+1:]
+	}
+
+	return repo, fragment, ""
+}
+
+// Fetch clones the repository of the given git context URL into
+// destDir, checks out its ref and returns the directory to use as
+// build context
+func Fetch(ctx context.Context, rawURL, destDir string) (string, error) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		return "", errors.Wrap(err, "git is required to build from a git context")
+	}
+
+	repo, ref, subdir := Parse(rawURL)
+	if err := os.MkdirAll(destDir, constant.DefaultRootDirMode); err != nil {
+		return "", errors.Wrapf(err, "create git context dir %q failed", destDir)
+	}
+
+	runGit := func(dir string, args ...string) error {
+		cmd := exec.CommandContext(ctx, gitPath, args...)
+		cmd.Dir = dir
+		// the daemon has no terminal to answer credential prompts
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		out, rErr := cmd.CombinedOutput()
+		if rErr != nil {
+			return errors.Errorf("git %s failed: %v: %s",
+				strings.Join(args, " "), rErr, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	if err := runGit("", "clone", repo, destDir); err != nil {
+		return "", err
+	}
+	if ref != "" {
+		if err := runGit(destDir, "checkout", ref); err != nil {
+			return "", err
+		}
+	}
+
+	contextDir := destDir
+	if subdir != "" {
+		contextDir, err = securejoin.SecureJoin(destDir, subdir)
+		if err != nil {
+			return "", err
+		}
+	}
+	info, err := os.Stat(contextDir)
+	if err != nil {
+		return "", errors.Wrapf(err, "stat git context dir %q failed", contextDir)
+	}
+	if !info.IsDir() {
+		return "", errors.Errorf("git context %q is not a directory", subdir)
+	}
+
+	return contextDir, nil
+}
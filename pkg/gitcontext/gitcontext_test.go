@@ -0,0 +1,147 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-22
+// Description: git build context tests
+
+package gitcontext
+
+import (
+	"context"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestIsGitURL(t *testing.T) {
+	type testcase struct {
+		name string
+		url  string
+		want bool
+	}
+	var testcases = []testcase{
+		{
+			name: "https git url",
+			url:  "https://github.com/org/repo.git",
+			want: true,
+		},
+		{
+			name: "https git url with fragment",
+			url:  "https://github.com/org/repo.git#branch:subdir",
+			want: true,
+		},
+		{
+			name: "git protocol",
+			url:  "git://example.com/repo",
+			want: true,
+		},
+		{
+			name: "scp like url",
+			url:  "git@example.com:org/repo.git",
+			want: true,
+		},
+		{
+			name: "plain https url",
+			url:  "https://example.com/archive.tar.gz",
+			want: false,
+		},
+		{
+			name: "local directory",
+			url:  "/home/user/context",
+			want: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, IsGitURL(tc.url), tc.want, "Failed at [%s]", tc.name)
+	}
+}
+
+func TestParse(t *testing.T) {
+	type testcase struct {
+		name   string
+		url    string
+		repo   string
+		ref    string
+		subdir string
+	}
+	var testcases = []testcase{
+		{
+			name: "no fragment",
+			url:  "https://github.com/org/repo.git",
+			repo: "https://github.com/org/repo.git",
+		},
+		{
+			name: "ref only",
+			url:  "https://github.com/org/repo.git#v1.0",
+			repo: "https://github.com/org/repo.git",
+			ref:  "v1.0",
+		},
+		{
+			name:   "ref and subdir",
+			url:    "https://github.com/org/repo.git#branch:docker/app",
+			repo:   "https://github.com/org/repo.git",
+			ref:    "branch",
+			subdir: "docker/app",
+		},
+		{
+			name:   "subdir only",
+			url:    "https://github.com/org/repo.git#:docker",
+			repo:   "https://github.com/org/repo.git",
+			subdir: "docker",
+		},
+	}
+
+	for _, tc := range testcases {
+		repo, ref, subdir := Parse(tc.url)
+		assert.Equal(t, repo, tc.repo, "Failed at [%s]", tc.name)
+		assert.Equal(t, ref, tc.ref, "Failed at [%s]", tc.name)
+		assert.Equal(t, subdir, tc.subdir, "Failed at [%s]", tc.name)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	repoDir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM scratch\n"),
+		fs.WithDir("sub", fs.WithFile("Dockerfile", "FROM scratch\nLABEL sub=true\n")))
+	defer repoDir.Remove()
+
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{
+			"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repoDir.Path()
+		out, err := cmd.CombinedOutput()
+		assert.NilError(t, err, "git %v: %s", args, out)
+	}
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	destDir := fs.NewDir(t, t.Name())
+	defer destDir.Remove()
+
+	contextDir, err := Fetch(context.Background(), repoDir.Path()+"#:sub", destDir.Join("clone"))
+	assert.NilError(t, err)
+	assert.Equal(t, contextDir, filepath.Join(destDir.Join("clone"), "sub"))
+	content, err := ioutil.ReadFile(filepath.Join(contextDir, "Dockerfile"))
+	assert.NilError(t, err)
+	assert.Assert(t, len(content) != 0)
+
+	_, err = Fetch(context.Background(), repoDir.Path()+"#:no-such-dir", destDir.Join("clone2"))
+	assert.ErrorContains(t, err, "stat git context dir")
+}
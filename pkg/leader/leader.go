@@ -0,0 +1,121 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-30
+// Description: leader election via a lock file for active/standby daemon pairs
+
+// Package leader elects the active daemon of an active/standby pair
+// through a lock file placed on the shared storage root
+package leader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultCampaignInterval is the default interval between two attempts
+// of a standby daemon to acquire the leader lock
+const DefaultCampaignInterval = 5 * time.Second
+
+// Elector campaigns for the leadership backed by a lock file. The lock
+// file must live on the storage root shared by the daemon pair, so at
+// most one daemon at a time can mutate the shared store
+type Elector struct {
+	mu       sync.RWMutex
+	lock     *flock.Flock
+	interval time.Duration
+	leader   bool
+}
+
+// NewElector creates an elector campaigning on the given lock file with
+// the given retry interval
+func NewElector(lockPath string, interval time.Duration) *Elector {
+	if interval <= 0 {
+		interval = DefaultCampaignInterval
+	}
+	return &Elector{
+		lock:     flock.New(lockPath),
+		interval: interval,
+	}
+}
+
+// TryAcquire makes a single non-blocking attempt to take the leader
+// lock and reports whether the elector is now the leader
+func (e *Elector) TryAcquire() (bool, error) {
+	locked, err := e.lock.TryLock()
+	if err != nil {
+		return false, errors.Wrapf(err, "try to acquire leader lock %s failed", e.lock.Path())
+	}
+	if locked {
+		e.mu.Lock()
+		e.leader = true
+		e.mu.Unlock()
+	}
+	return locked, nil
+}
+
+// Campaign blocks retrying to acquire the leader lock until it succeeds
+// or the context finishes. It returns nil once the leadership is taken
+func (e *Elector) Campaign(ctx context.Context) error {
+	if e.IsLeader() {
+		return nil
+	}
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			locked, err := e.TryAcquire()
+			if err != nil {
+				logrus.Warnf("Campaign for leader lock %s failed: %v", e.lock.Path(), err)
+				continue
+			}
+			if locked {
+				logrus.Infof("Leader lock %s acquired, promoting from standby to active", e.lock.Path())
+				return nil
+			}
+		}
+	}
+}
+
+// IsLeader reports whether the elector currently holds the leadership
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader
+}
+
+// Resign gives the leadership up by releasing the lock file, so the
+// standby peer can take it over. Resigning a non-leader is a no-op
+func (e *Elector) Resign() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.leader {
+		return nil
+	}
+	if err := e.lock.Unlock(); err != nil {
+		return errors.Wrapf(err, "release leader lock %s failed", e.lock.Path())
+	}
+	e.leader = false
+	return nil
+}
+
+// Path returns the path of the lock file the election is backed by
+func (e *Elector) Path() string {
+	return e.lock.Path()
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-30
+// Description: leader election related tests
+
+package leader
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestElectorTryAcquire(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	defer dir.Remove()
+	lockPath := filepath.Join(dir.Path(), "leader.lock")
+
+	active := NewElector(lockPath, DefaultCampaignInterval)
+	standby := NewElector(lockPath, DefaultCampaignInterval)
+
+	locked, err := active.TryAcquire()
+	assert.NilError(t, err)
+	assert.Equal(t, locked, true)
+	assert.Equal(t, active.IsLeader(), true)
+
+	locked, err = standby.TryAcquire()
+	assert.NilError(t, err)
+	assert.Equal(t, locked, false)
+	assert.Equal(t, standby.IsLeader(), false)
+
+	assert.NilError(t, active.Resign())
+	assert.Equal(t, active.IsLeader(), false)
+	// resigning twice must be harmless
+	assert.NilError(t, active.Resign())
+
+	locked, err = standby.TryAcquire()
+	assert.NilError(t, err)
+	assert.Equal(t, locked, true)
+	assert.NilError(t, standby.Resign())
+}
+
+func TestElectorCampaign(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	defer dir.Remove()
+	lockPath := filepath.Join(dir.Path(), "leader.lock")
+
+	active := NewElector(lockPath, time.Millisecond)
+	locked, err := active.TryAcquire()
+	assert.NilError(t, err)
+	assert.Equal(t, locked, true)
+
+	standby := NewElector(lockPath, time.Millisecond)
+	promoted := make(chan error, 1)
+	go func() {
+		promoted <- standby.Campaign(context.Background())
+	}()
+
+	// the standby must not be promoted while the active holds the lock
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, standby.IsLeader(), false)
+
+	assert.NilError(t, active.Resign())
+	select {
+	case err := <-promoted:
+		assert.NilError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("standby not promoted after the active resigned")
+	}
+	assert.Equal(t, standby.IsLeader(), true)
+	assert.NilError(t, standby.Resign())
+}
+
+func TestElectorCampaignCanceled(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	defer dir.Remove()
+	lockPath := filepath.Join(dir.Path(), "leader.lock")
+
+	active := NewElector(lockPath, time.Millisecond)
+	_, err := active.TryAcquire()
+	assert.NilError(t, err)
+	defer active.Resign()
+
+	standby := NewElector(lockPath, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = standby.Campaign(ctx)
+	assert.ErrorContains(t, err, "context canceled")
+	assert.Equal(t, standby.IsLeader(), false)
+}
@@ -0,0 +1,211 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-05-20
+// Description: This file provides a registry service over the local image store
+
+// Package localregistry serves the images of the local store over the
+// read-only part of the registry v2 API, so nearby nodes can pull the
+// built images straight from the build host
+package localregistry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	is "github.com/containers/image/v5/storage"
+	"github.com/containers/image/v5/types"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"isula.org/isula-build/pkg/cacheregistry"
+	"isula.org/isula-build/store"
+)
+
+const (
+	// readHeaderTimeout bounds the time waiting for the request headers
+	readHeaderTimeout = 30 * time.Second
+)
+
+// Registry is a read-only registry instance over the local store
+type Registry struct {
+	sync.Mutex
+	addr       string
+	localStore *store.Store
+	server     *http.Server
+	// sources keeps the image source opened for each served repository,
+	// so the blob requests following a manifest request can be answered
+	sources map[string]types.ImageSource
+}
+
+// New returns a registry listening on addr which serves the images of localStore
+func New(addr string, localStore *store.Store) *Registry {
+	return &Registry{
+		addr:       addr,
+		localStore: localStore,
+		sources:    make(map[string]types.ImageSource),
+	}
+}
+
+// Serve runs the registry service until ctx is canceled
+func (r *Registry) Serve(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", r.handle)
+	r.server = &http.Server{
+		Addr:              r.addr,
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+		if err := r.server.Close(); err != nil {
+			logrus.Warnf("Closing local registry failed: %v", err)
+		}
+	}()
+
+	logrus.Infof("Local registry is listening on %s", r.addr)
+	err := r.server.ListenAndServe()
+	r.closeSources()
+	if err != nil && err != http.ErrServerClosed {
+		return errors.Wrap(err, "running local registry failed")
+	}
+
+	return nil
+}
+
+func (r *Registry) handle(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	apiReq, err := cacheregistry.ParsePath(req.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set(cacheregistry.APIVersionHeader, cacheregistry.APIVersionValue)
+	switch apiReq.Resource {
+	case "":
+		w.WriteHeader(http.StatusOK)
+	case "manifests":
+		r.serveManifest(w, req, apiReq)
+	case "blobs":
+		r.serveBlob(w, req, apiReq)
+	}
+}
+
+// serveManifest serves the manifest of one locally stored image
+func (r *Registry) serveManifest(w http.ResponseWriter, req *http.Request, apiReq cacheregistry.Request) {
+	src, err := r.source(req.Context(), apiReq.Name, apiReq.Reference)
+	if err != nil {
+		logrus.Warnf("Local registry looking up image %s:%s failed: %v", apiReq.Name, apiReq.Reference, err)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	body, _, err := src.GetManifest(req.Context(), nil)
+	if err != nil {
+		logrus.Warnf("Local registry reading manifest of %s:%s failed: %v", apiReq.Name, apiReq.Reference, err)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", manifest.GuessMIMEType(body))
+	w.Header().Set("Docker-Content-Digest", digest.FromBytes(body).String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	if req.Method == http.MethodGet {
+		w.Write(body) // nolint:errcheck
+	}
+}
+
+// serveBlob serves one config or layer blob of a repository served before
+func (r *Registry) serveBlob(w http.ResponseWriter, req *http.Request, apiReq cacheregistry.Request) {
+	dgst, err := digest.Parse(apiReq.Reference)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.Lock()
+	src, ok := r.sources[apiReq.Name]
+	r.Unlock()
+	if !ok {
+		http.Error(w, "repository is not served yet, request its manifest first", http.StatusNotFound)
+		return
+	}
+
+	reader, size, err := src.GetBlob(req.Context(), types.BlobInfo{Digest: dgst}, none.NoCache)
+	if err != nil {
+		logrus.Warnf("Local registry reading blob %s of %s failed: %v", dgst, apiReq.Name, err)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	defer reader.Close() // nolint:errcheck
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Docker-Content-Digest", dgst.String())
+	if size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	}
+	if req.Method == http.MethodHead {
+		return
+	}
+	// layer blobs may be large, stream them instead of buffering
+	io.Copy(w, reader) // nolint:errcheck
+}
+
+// source returns the image source of name:reference in the local
+// store, keeping it open for the blob requests which follow
+func (r *Registry) source(ctx context.Context, name, reference string) (types.ImageSource, error) {
+	sep := ":"
+	if strings.Contains(reference, ":") {
+		sep = "@"
+	}
+	storeRef, err := is.Transport.ParseStoreReference(r.localStore, name+sep+reference)
+	if err != nil {
+		return nil, err
+	}
+	src, err := storeRef.NewImageSource(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	if old, ok := r.sources[name]; ok {
+		old.Close() // nolint:errcheck
+	}
+	r.sources[name] = src
+
+	return src, nil
+}
+
+// closeSources closes all the image sources kept open for serving
+func (r *Registry) closeSources() {
+	r.Lock()
+	defer r.Unlock()
+	for name, src := range r.sources {
+		if err := src.Close(); err != nil {
+			logrus.Warnf("Closing image source of %s failed: %v", name, err)
+		}
+		delete(r.sources, name)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-05-20
+// Description: local registry test
+
+package localregistry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"isula.org/isula-build/pkg/cacheregistry"
+)
+
+func TestHandleVersionCheck(t *testing.T) {
+	registry := New(":0", nil)
+
+	recorder := httptest.NewRecorder()
+	registry.handle(recorder, httptest.NewRequest(http.MethodGet, "/v2/", nil))
+	assert.Equal(t, recorder.Code, http.StatusOK)
+	assert.Equal(t, recorder.Header().Get(cacheregistry.APIVersionHeader), cacheregistry.APIVersionValue)
+}
+
+func TestHandleRejectsWriteRequests(t *testing.T) {
+	registry := New(":0", nil)
+
+	recorder := httptest.NewRecorder()
+	registry.handle(recorder, httptest.NewRequest(http.MethodPut, "/v2/app/manifests/latest", nil))
+	assert.Equal(t, recorder.Code, http.StatusMethodNotAllowed)
+}
+
+func TestServeBlobUnknownRepository(t *testing.T) {
+	registry := New(":0", nil)
+
+	recorder := httptest.NewRecorder()
+	path := "/v2/app/blobs/sha256:b49eda688ce8ce2b8d79e1121cb5a988be2f8a4a3b03bfb50bbecb01bbe753de"
+	registry.handle(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, recorder.Code, http.StatusNotFound)
+
+	recorder = httptest.NewRecorder()
+	registry.handle(recorder, httptest.NewRequest(http.MethodGet, "/v2/app/blobs/not-a-digest", nil))
+	assert.Equal(t, recorder.Code, http.StatusBadRequest)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: session scoped log forwarding hooks
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sessionLogChanSize is the buffer size of one session forwarding channel,
+// lines are dropped instead of blocking the daemon when the client is slow
+const sessionLogChanSize = 512
+
+// SessionKey is the entry field carrying the session ID
+const SessionKey = "sessionID"
+
+// levelWriterHook writes the entries which satisfy the configured daemon
+// log level to the original logger output. It takes the output duty over
+// from the logger itself, so that the logger level can be raised for the
+// session hooks without changing the verbosity of the daemon output
+type levelWriterHook struct {
+	out       io.Writer
+	formatter logrus.Formatter
+	level     logrus.Level
+}
+
+// Levels implements the logrus Hook interface
+func (h *levelWriterHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements the logrus Hook interface
+func (h *levelWriterHook) Fire(entry *logrus.Entry) error {
+	if entry.Level > h.level {
+		return nil
+	}
+	line, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	_, err = h.out.Write(line)
+	return err
+}
+
+// SessionHook forwards log entries carrying a registered session ID
+// back to the session, so clients requesting elevated verbosity get the
+// daemon log lines of their own build streamed alongside the build output
+type SessionHook struct {
+	sync.RWMutex
+	sessions map[string]*session
+}
+
+type session struct {
+	level logrus.Level
+	ch    chan string
+}
+
+var (
+	sessionHook *SessionHook
+	writerHook  *levelWriterHook
+)
+
+// SetupSessionHook replaces the output of the standard logger with the
+// level filtering writer hook and installs the session forwarding hook.
+// baseLevel is the configured daemon log level which keeps applying to
+// the daemon output
+func SetupSessionHook(baseLevel logrus.Level) {
+	if sessionHook != nil {
+		writerHook.level = baseLevel
+		return
+	}
+
+	std := logrus.StandardLogger()
+	writerHook = &levelWriterHook{
+		out:       std.Out,
+		formatter: std.Formatter,
+		level:     baseLevel,
+	}
+	std.AddHook(writerHook)
+	std.SetOutput(ioutil.Discard)
+	std.SetLevel(logrus.TraceLevel)
+
+	sessionHook = &SessionHook{sessions: make(map[string]*session)}
+	std.AddHook(sessionHook)
+}
+
+// Levels implements the logrus Hook interface
+func (s *SessionHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements the logrus Hook interface
+func (s *SessionHook) Fire(entry *logrus.Entry) error {
+	sessionID, ok := entry.Data[SessionKey].(string)
+	if !ok {
+		return nil
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+	sess, ok := s.sessions[sessionID]
+	if !ok || entry.Level > sess.level {
+		return nil
+	}
+
+	select {
+	case sess.ch <- fmt.Sprintf("[daemon] %s: %s\n", entry.Level, entry.Message):
+	default:
+		// the client does not keep up, dropping is better than blocking
+	}
+	return nil
+}
+
+// RegisterSession starts forwarding entries of the session with at most
+// the given level. It is a no-op when SetupSessionHook was not called
+func RegisterSession(sessionID string, level logrus.Level) {
+	if sessionHook == nil {
+		return
+	}
+	sessionHook.Lock()
+	defer sessionHook.Unlock()
+	if _, ok := sessionHook.sessions[sessionID]; ok {
+		return
+	}
+	sessionHook.sessions[sessionID] = &session{
+		level: level,
+		ch:    make(chan string, sessionLogChanSize),
+	}
+}
+
+// DeregisterSession stops forwarding entries of the session and closes
+// its channel
+func DeregisterSession(sessionID string) {
+	if sessionHook == nil {
+		return
+	}
+	sessionHook.Lock()
+	defer sessionHook.Unlock()
+	if sess, ok := sessionHook.sessions[sessionID]; ok {
+		delete(sessionHook.sessions, sessionID)
+		close(sess.ch)
+	}
+}
+
+// SessionChan returns the forwarding channel of the session, nil when
+// the session is not registered
+func SessionChan(sessionID string) <-chan string {
+	if sessionHook == nil {
+		return nil
+	}
+	sessionHook.RLock()
+	defer sessionHook.RUnlock()
+	if sess, ok := sessionHook.sessions[sessionID]; ok {
+		return sess.ch
+	}
+	return nil
+}
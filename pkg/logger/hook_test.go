@@ -0,0 +1,89 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-15
+// Description: session scoped log forwarding hooks tests
+
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gotest.tools/v3/assert"
+)
+
+func fireEntry(t *testing.T, sessionID string, level logrus.Level, msg string) {
+	entry := logrus.NewEntry(logrus.StandardLogger())
+	entry.Level = level
+	entry.Message = msg
+	if sessionID != "" {
+		entry.Data[SessionKey] = sessionID
+	}
+	assert.NilError(t, sessionHook.Fire(entry))
+}
+
+func TestSessionHookForward(t *testing.T) {
+	sessionHook = &SessionHook{sessions: make(map[string]*session)}
+	defer func() { sessionHook = nil }()
+
+	// firing without a registered session is a no-op
+	fireEntry(t, "sess", logrus.DebugLevel, "not registered")
+
+	RegisterSession("sess", logrus.DebugLevel)
+	ch := SessionChan("sess")
+	assert.Assert(t, ch != nil)
+
+	// entries more verbose than the session level are filtered out
+	fireEntry(t, "sess", logrus.TraceLevel, "too verbose")
+	// entries of other sessions or without a session ID are not forwarded
+	fireEntry(t, "other", logrus.DebugLevel, "other session")
+	fireEntry(t, "", logrus.DebugLevel, "no session")
+
+	fireEntry(t, "sess", logrus.DebugLevel, "hello from the daemon")
+	line, ok := <-ch
+	assert.Equal(t, ok, true)
+	assert.Assert(t, strings.Contains(line, "hello from the daemon"))
+	assert.Assert(t, strings.HasPrefix(line, "[daemon] debug:"))
+	select {
+	case line = <-ch:
+		t.Fatalf("unexpected forwarded line %q", line)
+	default:
+	}
+
+	DeregisterSession("sess")
+	_, ok = <-ch
+	assert.Equal(t, ok, false)
+	assert.Assert(t, SessionChan("sess") == nil)
+	// deregistering twice does not panic
+	DeregisterSession("sess")
+}
+
+func TestLevelWriterHook(t *testing.T) {
+	buf := &bytes.Buffer{}
+	hook := &levelWriterHook{
+		out:       buf,
+		formatter: &logrus.TextFormatter{},
+		level:     logrus.InfoLevel,
+	}
+
+	entry := logrus.NewEntry(logrus.StandardLogger())
+	entry.Level = logrus.DebugLevel
+	entry.Message = "below the daemon level"
+	assert.NilError(t, hook.Fire(entry))
+	assert.Equal(t, buf.Len(), 0)
+
+	entry.Level = logrus.InfoLevel
+	entry.Message = "daemon output line"
+	assert.NilError(t, hook.Fire(entry))
+	assert.Assert(t, strings.Contains(buf.String(), "daemon output line"))
+}
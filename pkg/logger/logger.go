@@ -16,6 +16,7 @@ package logger
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
@@ -35,11 +36,21 @@ type Timer struct {
 	startTime time.Time
 }
 
+// redactedValue replaces values of sensitive build-args in the output
+const redactedValue = "****"
+
 // Logger logs message of which we want to print to the front end
 type Logger struct {
 	rt          *RunTimer
 	content     chan string
 	currentStep int
+	// redactions holds the sensitive values hidden from every message,
+	// guarded by redactLock since the build writes from several goroutines
+	redactions []string
+	redactLock sync.RWMutex
+	// timestamps prefixes every message with the wall clock time and
+	// reports the duration of each stopped timer into the stream
+	timestamps bool
 }
 
 // NewRunTimer return an instance of RunTimer
@@ -62,12 +73,18 @@ func (l *Logger) StartTimer(str string) *Timer {
 func (l *Logger) StopTimer(t *Timer) {
 	stop := time.Now()
 	l.rt.lock.Lock()
-	defer l.rt.lock.Unlock()
 	l.rt.commands = append(l.rt.commands, fmt.Sprintf("%s: %s\n", t.command, stop.Sub(t.startTime).String()))
 	if _, ok := l.rt.cmdMap[t.command]; !ok {
 		l.rt.cmdMap[t.command] = 0
 	}
 	l.rt.cmdMap[t.command] += stop.Sub(t.startTime)
+	l.rt.lock.Unlock()
+
+	// with timestamps on, slow steps are identifiable from the raw log
+	// without the progress output
+	if l.timestamps && l.content != nil {
+		l.content <- l.stamp(fmt.Sprintf("%s finished in %s\n", strings.TrimSpace(t.command), stop.Sub(t.startTime).String()))
+	}
 }
 
 // GetCmdTime return one command consume time in the map
@@ -91,22 +108,55 @@ func (l *Logger) Summary() string {
 	return summary
 }
 
+// SetTimestamps prefixes every following message with the wall clock
+// time and reports the duration of each finished step into the stream
+func (l *Logger) SetTimestamps(enabled bool) {
+	l.timestamps = enabled
+}
+
+// stamp prefixes the message with the wall clock time when timestamps
+// are enabled
+func (l *Logger) stamp(s string) string {
+	if !l.timestamps || s == "" {
+		return s
+	}
+	return time.Now().Format(time.RFC3339) + " " + s
+}
+
+// SetRedactions registers the sensitive values hidden from every
+// message passed through the logger
+func (l *Logger) SetRedactions(values []string) {
+	l.redactLock.Lock()
+	defer l.redactLock.Unlock()
+	l.redactions = values
+}
+
+// redact replaces every registered sensitive value in the message
+func (l *Logger) redact(s string) string {
+	l.redactLock.RLock()
+	defer l.redactLock.RUnlock()
+	for _, v := range l.redactions {
+		s = strings.ReplaceAll(s, v, redactedValue)
+	}
+	return s
+}
+
 // Write is used to implement io.Writer
 func (l *Logger) Write(p []byte) (int, error) {
-	l.content <- string(p)
+	l.content <- l.stamp(l.redact(string(p)))
 	return len(p), nil
 }
 
 // StepPrint can be only used to print step info in each command line of the dockerfile
 func (l *Logger) StepPrint(str string) {
 	l.currentStep++
-	content := fmt.Sprintf("STEP %2d: %s\n", l.currentStep, str)
+	content := l.stamp(fmt.Sprintf("STEP %2d: %s\n", l.currentStep, l.redact(str)))
 	l.content <- content
 }
 
 // Print transport message to the front in the client end
 func (l *Logger) Print(format string, a ...interface{}) {
-	l.content <- fmt.Sprintf(format, a...)
+	l.content <- l.stamp(l.redact(fmt.Sprintf(format, a...)))
 }
 
 // CloseContent close channel connected with frontend
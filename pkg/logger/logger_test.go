@@ -186,3 +186,45 @@ func TestLoggerGetCmdTime(t *testing.T) {
 	emtyeResult := l.GetCmdTime(emptyTimer)
 	assert.Equal(t, emtyeResult, "")
 }
+
+func TestLoggerRedaction(t *testing.T) {
+	l := NewCliLogger(constant.CliLogBufferLen)
+	l.SetRedactions([]string{"s3cr3t"})
+
+	l.Print("the token is s3cr3t\n")
+	content, ok := <-l.GetContent()
+	assert.Equal(t, ok, true)
+	assert.Equal(t, content, "the token is ****\n")
+
+	l.StepPrint("RUN echo s3cr3t")
+	content, ok = <-l.GetContent()
+	assert.Equal(t, ok, true)
+	assert.Equal(t, content, "STEP  1: RUN echo ****\n")
+
+	_, err := l.Write([]byte("s3cr3t appears twice s3cr3t"))
+	assert.NilError(t, err)
+	content, ok = <-l.GetContent()
+	assert.Equal(t, ok, true)
+	assert.Equal(t, content, "**** appears twice ****")
+}
+
+func TestLoggerTimestamps(t *testing.T) {
+	l := NewCliLogger(8)
+
+	// without timestamps a stopped timer emits no stream record
+	timer := l.StartTimer("RUN 2-2")
+	l.StopTimer(timer)
+	assert.Equal(t, len(l.content), 0)
+
+	l.SetTimestamps(true)
+	l.StepPrint("RUN ls")
+	line := <-l.GetContent()
+	stamped := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}.* STEP  1: RUN ls\n$`)
+	assert.Equal(t, stamped.MatchString(line), true)
+
+	timer = l.StartTimer("RUN 3-3")
+	l.StopTimer(timer)
+	line = <-l.GetContent()
+	finished := regexp.MustCompile(`RUN 3-3 finished in .*s\n$`)
+	assert.Equal(t, finished.MatchString(line), true)
+}
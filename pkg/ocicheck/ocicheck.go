@@ -0,0 +1,177 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-07-18
+// Description: OCI image spec conformance checks
+
+// Package ocicheck validates produced image manifests and configs
+// against the OCI image spec, catching violations that strict
+// registries reject
+package ocicheck
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/containers/image/v5/manifest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// expectedSchemaVersion is the only manifest schema version the image
+// spec and the registry API accept
+const expectedSchemaVersion = 2
+
+// configMediaTypes are the accepted media types for the config descriptor
+var configMediaTypes = map[string]bool{
+	imgspecv1.MediaTypeImageConfig:          true,
+	manifest.DockerV2Schema2ConfigMediaType: true,
+}
+
+// manifestMediaTypes are the accepted media types for the manifest itself
+var manifestMediaTypes = map[string]bool{
+	imgspecv1.MediaTypeImageManifest:  true,
+	manifest.DockerV2Schema2MediaType: true,
+}
+
+// layerMediaTypes are the accepted media types for layer descriptors
+var layerMediaTypes = map[string]bool{
+	imgspecv1.MediaTypeImageLayer:                     true,
+	imgspecv1.MediaTypeImageLayerGzip:                 true,
+	imgspecv1.MediaTypeImageLayerZstd:                 true,
+	imgspecv1.MediaTypeImageLayerNonDistributable:     true,
+	imgspecv1.MediaTypeImageLayerNonDistributableGzip: true,
+	imgspecv1.MediaTypeImageLayerNonDistributableZstd: true,
+	manifest.DockerV2Schema2LayerMediaType:            true,
+	manifest.DockerV2SchemaLayerMediaTypeUncompressed: true,
+	manifest.DockerV2Schema2ForeignLayerMediaType:     true,
+	manifest.DockerV2Schema2ForeignLayerMediaTypeGzip: true,
+}
+
+// Violation is one spec conformance problem found in an image
+type Violation struct {
+	// Object is the part of the image the violation was found in,
+	// "manifest" or "config"
+	Object string
+	// Detail describes the violation
+	Detail string
+}
+
+// String formats the violation for user output
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Object, v.Detail)
+}
+
+// CheckImage validates the raw manifest and config of one image and
+// returns every violation found instead of stopping at the first one
+func CheckImage(manifestRaw, configRaw []byte) []Violation {
+	violations, layers := checkManifest(manifestRaw)
+	violations = append(violations, checkConfig(configRaw, layers)...)
+	return violations
+}
+
+// checkManifest validates the raw manifest and returns the number of
+// layers for the config cross checks, -1 when the manifest is unreadable
+func checkManifest(manifestRaw []byte) ([]Violation, int) {
+	var violations []Violation
+	appendViolation := func(format string, args ...interface{}) {
+		violations = append(violations, Violation{Object: "manifest", Detail: fmt.Sprintf(format, args...)})
+	}
+
+	var m struct {
+		imgspecv1.Manifest
+		// the vendored spec structs predate the manifest mediaType field
+		MediaType string `json:"mediaType,omitempty"`
+	}
+	if err := json.Unmarshal(manifestRaw, &m); err != nil {
+		appendViolation("not valid JSON: %v", err)
+		return violations, -1
+	}
+
+	if m.SchemaVersion != expectedSchemaVersion {
+		appendViolation("schemaVersion is %d, expected %d", m.SchemaVersion, expectedSchemaVersion)
+	}
+	if m.MediaType != "" && !manifestMediaTypes[m.MediaType] {
+		appendViolation("unknown manifest mediaType %q", m.MediaType)
+	}
+	if !configMediaTypes[m.Config.MediaType] {
+		appendViolation("unknown config mediaType %q", m.Config.MediaType)
+	}
+	if err := m.Config.Digest.Validate(); err != nil {
+		appendViolation("invalid config digest %q: %v", m.Config.Digest, err)
+	}
+	if m.Config.Size <= 0 {
+		appendViolation("config size %d must be positive", m.Config.Size)
+	}
+	if len(m.Layers) == 0 {
+		appendViolation("manifest has no layers")
+	}
+	for i, layer := range m.Layers {
+		if !layerMediaTypes[layer.MediaType] {
+			appendViolation("layer %d has unknown mediaType %q", i, layer.MediaType)
+		}
+		if err := layer.Digest.Validate(); err != nil {
+			appendViolation("layer %d has invalid digest %q: %v", i, layer.Digest, err)
+		}
+		if layer.Size <= 0 {
+			appendViolation("layer %d size %d must be positive", i, layer.Size)
+		}
+	}
+
+	return violations, len(m.Layers)
+}
+
+// checkConfig validates the raw image config, layerCount is the layer
+// count of the manifest, -1 skips the cross checks
+func checkConfig(configRaw []byte, layerCount int) []Violation {
+	var violations []Violation
+	appendViolation := func(format string, args ...interface{}) {
+		violations = append(violations, Violation{Object: "config", Detail: fmt.Sprintf(format, args...)})
+	}
+
+	var c imgspecv1.Image
+	if err := json.Unmarshal(configRaw, &c); err != nil {
+		appendViolation("not valid JSON: %v", err)
+		return violations
+	}
+
+	if c.Architecture == "" {
+		appendViolation("architecture must not be empty")
+	}
+	if c.OS == "" {
+		appendViolation("os must not be empty")
+	}
+	if c.RootFS.Type != "layers" {
+		appendViolation("rootfs type is %q, expected \"layers\"", c.RootFS.Type)
+	}
+	if len(c.RootFS.DiffIDs) == 0 {
+		appendViolation("rootfs has no diff_ids")
+	}
+	for i, diffID := range c.RootFS.DiffIDs {
+		if err := diffID.Validate(); err != nil {
+			appendViolation("diff_id %d is invalid: %v", i, err)
+		}
+	}
+	if layerCount >= 0 && len(c.RootFS.DiffIDs) != layerCount {
+		appendViolation("rootfs has %d diff_ids but the manifest has %d layers", len(c.RootFS.DiffIDs), layerCount)
+	}
+	if len(c.History) != 0 {
+		filled := 0
+		for _, entry := range c.History {
+			if !entry.EmptyLayer {
+				filled++
+			}
+		}
+		if filled != len(c.RootFS.DiffIDs) {
+			appendViolation("history lists %d non-empty layers but rootfs has %d diff_ids", filled, len(c.RootFS.DiffIDs))
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-07-18
+// Description: OCI image spec conformance tests
+
+package ocicheck
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const validManifest = `{
+  "schemaVersion": 2,
+  "mediaType": "application/vnd.oci.image.manifest.v1+json",
+  "config": {
+    "mediaType": "application/vnd.oci.image.config.v1+json",
+    "digest": "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+    "size": 1024
+  },
+  "layers": [
+    {
+      "mediaType": "application/vnd.oci.image.layer.v1.tar+gzip",
+      "digest": "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+      "size": 2048
+    }
+  ]
+}`
+
+const validConfig = `{
+  "architecture": "amd64",
+  "os": "linux",
+  "rootfs": {
+    "type": "layers",
+    "diff_ids": ["sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"]
+  }
+}`
+
+func TestCheckImageValid(t *testing.T) {
+	violations := CheckImage([]byte(validManifest), []byte(validConfig))
+	assert.Equal(t, len(violations), 0)
+}
+
+func TestCheckImageViolations(t *testing.T) {
+	badManifest := `{
+  "schemaVersion": 1,
+  "config": {
+    "mediaType": "application/octet-stream",
+    "digest": "not-a-digest",
+    "size": 0
+  },
+  "layers": []
+}`
+	badConfig := `{
+  "architecture": "",
+  "os": "linux",
+  "rootfs": {
+    "type": "base",
+    "diff_ids": []
+  }
+}`
+
+	violations := CheckImage([]byte(badManifest), []byte(badConfig))
+	wants := []string{
+		"schemaVersion is 1",
+		"unknown config mediaType",
+		"invalid config digest",
+		"config size 0 must be positive",
+		"manifest has no layers",
+		"architecture must not be empty",
+		`rootfs type is "base"`,
+		"rootfs has no diff_ids",
+	}
+	for _, want := range wants {
+		found := false
+		for _, violation := range violations {
+			if strings.Contains(violation.Detail, want) {
+				found = true
+				break
+			}
+		}
+		assert.Assert(t, found, "no violation contains %q, got %v", want, violations)
+	}
+}
+
+func TestCheckImageLayerMismatch(t *testing.T) {
+	mismatchedConfig := `{
+  "architecture": "amd64",
+  "os": "linux",
+  "rootfs": {
+    "type": "layers",
+    "diff_ids": [
+      "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+      "sha256:d4735e3a265e16eee03f59718b9b5d03019c07d8b6c51f90da3a666eec13ab35"
+    ]
+  }
+}`
+	violations := CheckImage([]byte(validManifest), []byte(mismatchedConfig))
+	assert.Equal(t, len(violations), 1)
+	assert.Assert(t, strings.Contains(violations[0].Detail, "2 diff_ids but the manifest has 1 layers"))
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-22
+// Description: request rate limiting
+
+// Package ratelimit provides a token bucket limiter guarding the
+// daemon's gRPC server against abusive or buggy clients
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// maxClients bounds the limiter map, beyond that stale entries
+// are dropped and their clients start with a full bucket again
+const maxClients = 128
+
+// Limiter is a token bucket, its bucket holds burst tokens and refills
+// with rate tokens per second
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a limiter allowing rate requests per second with
+// bursts up to burst requests
+func NewLimiter(rate, burst int) *Limiter {
+	return &Limiter{
+		rate:   float64(rate),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Allow reports whether one request may pass now. When it may not, the
+// returned duration tells how long the client should wait before retrying
+func (l *Limiter) Allow() (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return true, 0
+	}
+
+	return false, time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// KeyedLimiter maintains one limiter per client key
+type KeyedLimiter struct {
+	mu       sync.Mutex
+	rate     int
+	burst    int
+	limiters map[string]*Limiter
+}
+
+// NewKeyedLimiter returns a keyed limiter allowing each client rate
+// requests per second with bursts up to burst requests
+func NewKeyedLimiter(rate, burst int) *KeyedLimiter {
+	return &KeyedLimiter{
+		rate:     rate,
+		burst:    burst,
+		limiters: make(map[string]*Limiter),
+	}
+}
+
+// Allow reports whether one request of the given client may pass now,
+// together with the duration to wait before retrying when it may not
+func (k *KeyedLimiter) Allow(key string) (bool, time.Duration) {
+	k.mu.Lock()
+	if len(k.limiters) > maxClients {
+		k.limiters = make(map[string]*Limiter)
+	}
+	l, ok := k.limiters[key]
+	if !ok {
+		l = NewLimiter(k.rate, k.burst)
+		k.limiters[key] = l
+	}
+	k.mu.Unlock()
+
+	return l.Allow()
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-22
+// Description: request rate limiting tests
+
+package ratelimit
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLimiterBurst(t *testing.T) {
+	l := NewLimiter(1, 2)
+
+	allowed, _ := l.Allow()
+	assert.Assert(t, allowed)
+	allowed, _ = l.Allow()
+	assert.Assert(t, allowed)
+
+	allowed, retryAfter := l.Allow()
+	assert.Assert(t, !allowed)
+	assert.Assert(t, retryAfter > 0)
+}
+
+func TestKeyedLimiterIsolation(t *testing.T) {
+	k := NewKeyedLimiter(1, 1)
+
+	allowed, _ := k.Allow("client-a")
+	assert.Assert(t, allowed)
+	allowed, _ = k.Allow("client-a")
+	assert.Assert(t, !allowed)
+
+	// an exhausted bucket of one client leaves the others alone
+	allowed, _ = k.Allow("client-b")
+	assert.Assert(t, allowed)
+}
@@ -0,0 +1,287 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-04-18
+// Description: push OCI artifact referrers attached to an image
+
+// Package referrer attaches OCI artifacts (SBOMs, provenance,
+// signatures...) to a pushed image so policy engines can discover them
+// through the OCI referrers API, falling back to the tag schema when
+// the registry does not serve referrers
+package referrer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	dockerconfig "github.com/containers/image/v5/pkg/docker/config"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultArtifactType marks an attached artifact whose type was not given
+	DefaultArtifactType = "application/vnd.unknown.artifact.v1"
+	// emptyConfigMediaType is the config media type of an artifact manifest
+	emptyConfigMediaType = "application/vnd.oci.empty.v1+json"
+	// artifactBlobMediaType is the layer media type carrying the artifact content
+	artifactBlobMediaType = "application/octet-stream"
+	// maxArtifactSize limits the size of one attached artifact
+	maxArtifactSize = 100 * 1024 * 1024
+	// referrersCheckTimeout bounds the referrers API probe
+	referrersCheckTimeout = 30 * time.Second
+	// schemaVersion is the manifest schema version required by the registries
+	schemaVersion = 2
+)
+
+// emptyConfigBlob is the content of the empty artifact config
+var emptyConfigBlob = []byte("{}")
+
+// artifactManifest is an OCI image manifest carrying an artifact and
+// referring to the pushed image through the subject descriptor, the
+// vendored image-spec predates the subject field so it is built here
+type artifactManifest struct {
+	SchemaVersion int                    `json:"schemaVersion"`
+	MediaType     string                 `json:"mediaType"`
+	ArtifactType  string                 `json:"artifactType,omitempty"`
+	Config        imgspecv1.Descriptor   `json:"config"`
+	Layers        []imgspecv1.Descriptor `json:"layers"`
+	Subject       *imgspecv1.Descriptor  `json:"subject"`
+}
+
+// Artifact is one artifact to be attached to a pushed image
+type Artifact struct {
+	// Path is the local path of the artifact content
+	Path string
+	// ArtifactType is the OCI artifact type recorded in the manifest
+	ArtifactType string
+}
+
+// Push attaches the artifact to the already pushed image imageName and
+// returns the digest of the pushed artifact manifest
+func Push(ctx context.Context, sysCtx *types.SystemContext, imageName string, artifact Artifact) (digest.Digest, error) {
+	ref, err := docker.ParseReference("//" + imageName)
+	if err != nil {
+		return "", errors.Wrapf(err, "parse image name %q failed", imageName)
+	}
+	named := ref.DockerReference()
+
+	subject, err := subjectDescriptor(ctx, sysCtx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := readArtifact(artifact.Path)
+	if err != nil {
+		return "", err
+	}
+	artifactType := artifact.ArtifactType
+	if artifactType == "" {
+		artifactType = DefaultArtifactType
+	}
+
+	manifestBytes, manifestDigest, err := makeArtifactManifest(content, artifactType, subject)
+	if err != nil {
+		return "", err
+	}
+
+	if err := putArtifact(ctx, sysCtx, ref, content, manifestBytes, manifestDigest); err != nil {
+		return "", err
+	}
+
+	if !referrersSupported(ctx, sysCtx, named, subject.Digest) {
+		logrus.Infof("Registry of %q lacks referrers support, falling back to tag schema", imageName)
+		if err := putFallbackTag(ctx, sysCtx, named, subject.Digest, manifestBytes); err != nil {
+			return "", err
+		}
+	}
+
+	return manifestDigest, nil
+}
+
+// subjectDescriptor fetches the manifest of the pushed image and
+// builds the subject descriptor the artifact refers to
+func subjectDescriptor(ctx context.Context, sysCtx *types.SystemContext, ref types.ImageReference) (*imgspecv1.Descriptor, error) {
+	src, err := ref.NewImageSource(ctx, sysCtx)
+	if err != nil {
+		return nil, errors.Wrap(err, "create image source for subject failed")
+	}
+	defer func() {
+		if cErr := src.Close(); cErr != nil {
+			logrus.Warnf("Close image source failed: %v", cErr)
+		}
+	}()
+
+	manifestBytes, manifestType, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "get manifest of subject image failed")
+	}
+
+	return &imgspecv1.Descriptor{
+		MediaType: manifestType,
+		Digest:    digest.FromBytes(manifestBytes),
+		Size:      int64(len(manifestBytes)),
+	}, nil
+}
+
+// readArtifact loads the artifact content within the size limit
+func readArtifact(path string) ([]byte, error) {
+	content, err := ioutil.ReadFile(path) // nolint:gosec // path was checked by the caller
+	if err != nil {
+		return nil, errors.Wrapf(err, "read artifact %q failed", path)
+	}
+	if len(content) == 0 {
+		return nil, errors.Errorf("artifact %q is empty", path)
+	}
+	if len(content) > maxArtifactSize {
+		return nil, errors.Errorf("artifact %q exceeds the max size %d bytes", path, maxArtifactSize)
+	}
+
+	return content, nil
+}
+
+// makeArtifactManifest builds the artifact manifest referring to subject
+func makeArtifactManifest(content []byte, artifactType string, subject *imgspecv1.Descriptor) ([]byte, digest.Digest, error) {
+	m := artifactManifest{
+		SchemaVersion: schemaVersion,
+		MediaType:     imgspecv1.MediaTypeImageManifest,
+		ArtifactType:  artifactType,
+		Config: imgspecv1.Descriptor{
+			MediaType: emptyConfigMediaType,
+			Digest:    digest.FromBytes(emptyConfigBlob),
+			Size:      int64(len(emptyConfigBlob)),
+		},
+		Layers: []imgspecv1.Descriptor{{
+			MediaType: artifactBlobMediaType,
+			Digest:    digest.FromBytes(content),
+			Size:      int64(len(content)),
+		}},
+		Subject: subject,
+	}
+	manifestBytes, err := json.Marshal(m)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "marshal artifact manifest failed")
+	}
+
+	return manifestBytes, digest.FromBytes(manifestBytes), nil
+}
+
+// putArtifact uploads the artifact blobs and its manifest by digest
+func putArtifact(ctx context.Context, sysCtx *types.SystemContext, ref types.ImageReference,
+	content, manifestBytes []byte, manifestDigest digest.Digest) (err error) {
+	dest, err := ref.NewImageDestination(ctx, sysCtx)
+	if err != nil {
+		return errors.Wrap(err, "create image destination for artifact failed")
+	}
+	defer func() {
+		if cErr := dest.Close(); cErr != nil && err == nil {
+			err = errors.Wrap(cErr, "close image destination failed")
+		}
+	}()
+
+	if _, err := dest.PutBlob(ctx, bytes.NewReader(content),
+		types.BlobInfo{Digest: digest.FromBytes(content), Size: int64(len(content))}, none.NoCache, false); err != nil {
+		return errors.Wrap(err, "upload artifact blob failed")
+	}
+	if _, err := dest.PutBlob(ctx, bytes.NewReader(emptyConfigBlob),
+		types.BlobInfo{Digest: digest.FromBytes(emptyConfigBlob), Size: int64(len(emptyConfigBlob))}, none.NoCache, true); err != nil {
+		return errors.Wrap(err, "upload artifact config failed")
+	}
+	if err := dest.PutManifest(ctx, manifestBytes, &manifestDigest); err != nil {
+		return errors.Wrap(err, "upload artifact manifest failed")
+	}
+
+	return dest.Commit(ctx, nil)
+}
+
+// putFallbackTag tags the already uploaded artifact manifest with the
+// tag schema name derived from the subject digest
+func putFallbackTag(ctx context.Context, sysCtx *types.SystemContext, named reference.Named,
+	subjectDigest digest.Digest, manifestBytes []byte) (err error) {
+	tagged, err := reference.WithTag(reference.TrimNamed(named), FallbackTag(subjectDigest))
+	if err != nil {
+		return errors.Wrap(err, "make fallback tag failed")
+	}
+	tagRef, err := docker.NewReference(tagged)
+	if err != nil {
+		return errors.Wrap(err, "make fallback tag reference failed")
+	}
+
+	dest, err := tagRef.NewImageDestination(ctx, sysCtx)
+	if err != nil {
+		return errors.Wrap(err, "create image destination for fallback tag failed")
+	}
+	defer func() {
+		if cErr := dest.Close(); cErr != nil && err == nil {
+			err = errors.Wrap(cErr, "close image destination failed")
+		}
+	}()
+
+	if err := dest.PutManifest(ctx, manifestBytes, nil); err != nil {
+		return errors.Wrap(err, "upload fallback tag manifest failed")
+	}
+
+	return dest.Commit(ctx, nil)
+}
+
+// FallbackTag derives the tag schema name of a subject digest,
+// e.g. sha256-abcd... for sha256:abcd...
+func FallbackTag(d digest.Digest) string {
+	return strings.Replace(d.String(), ":", "-", 1)
+}
+
+// referrersSupported probes the OCI referrers API of the registry
+// serving named, any failure is taken as lack of support since the tag
+// schema fallback works with every registry
+func referrersSupported(ctx context.Context, sysCtx *types.SystemContext, named reference.Named, subjectDigest digest.Digest) bool {
+	url := fmt.Sprintf("https://%s/v2/%s/referrers/%s",
+		reference.Domain(named), reference.Path(named), subjectDigest.String())
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return false
+	}
+	req = req.WithContext(ctx)
+	if auth, aErr := dockerconfig.GetCredentials(sysCtx, reference.Domain(named)); aErr == nil && auth.Username != "" {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+
+	client := &http.Client{Timeout: referrersCheckTimeout}
+	if sysCtx != nil && sysCtx.DockerInsecureSkipTLSVerify == types.OptionalBoolTrue {
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // nolint:gosec // requested by the insecure registry setting
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer func() {
+		if cErr := resp.Body.Close(); cErr != nil {
+			logrus.Warnf("Close referrers response failed: %v", cErr)
+		}
+	}()
+
+	return resp.StatusCode == http.StatusOK &&
+		strings.Contains(resp.Header.Get("Content-Type"), imgspecv1.MediaTypeImageIndex)
+}
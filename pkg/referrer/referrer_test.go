@@ -0,0 +1,76 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-04-18
+// Description: referrer package test functions
+
+package referrer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestFallbackTag(t *testing.T) {
+	d := digest.FromString("subject")
+	tag := FallbackTag(d)
+	assert.Assert(t, strings.HasPrefix(tag, "sha256-"))
+	assert.Assert(t, !strings.Contains(tag, ":"))
+	assert.Equal(t, tag, "sha256-"+d.Encoded())
+}
+
+func TestMakeArtifactManifest(t *testing.T) {
+	content := []byte(`{"spdxVersion": "SPDX-2.2"}`)
+	subject := &imgspecv1.Descriptor{
+		MediaType: imgspecv1.MediaTypeImageManifest,
+		Digest:    digest.FromString("subject"),
+		Size:      10,
+	}
+
+	manifestBytes, manifestDigest, err := makeArtifactManifest(content, "application/spdx+json", subject)
+	assert.NilError(t, err)
+	assert.Equal(t, manifestDigest, digest.FromBytes(manifestBytes))
+
+	var m artifactManifest
+	assert.NilError(t, json.Unmarshal(manifestBytes, &m))
+	assert.Equal(t, m.SchemaVersion, schemaVersion)
+	assert.Equal(t, m.MediaType, imgspecv1.MediaTypeImageManifest)
+	assert.Equal(t, m.ArtifactType, "application/spdx+json")
+	assert.Equal(t, m.Config.MediaType, emptyConfigMediaType)
+	assert.Equal(t, m.Config.Digest, digest.FromBytes(emptyConfigBlob))
+	assert.Equal(t, len(m.Layers), 1)
+	assert.Equal(t, m.Layers[0].Digest, digest.FromBytes(content))
+	assert.Equal(t, m.Layers[0].Size, int64(len(content)))
+	assert.Assert(t, m.Subject != nil)
+	assert.Equal(t, m.Subject.Digest, subject.Digest)
+}
+
+func TestReadArtifact(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("sbom.json", `{"spdxVersion": "SPDX-2.2"}`),
+		fs.WithFile("empty", ""))
+	defer dir.Remove()
+
+	content, err := readArtifact(dir.Join("sbom.json"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), `{"spdxVersion": "SPDX-2.2"}`)
+
+	_, err = readArtifact(dir.Join("empty"))
+	assert.ErrorContains(t, err, "is empty")
+
+	_, err = readArtifact(dir.Join("none.json"))
+	assert.ErrorContains(t, err, "read artifact")
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: per-registry image signing configuration and helpers
+
+// Package signer holds the per-registry signing configuration of the
+// daemon and signs pushed images with it
+package signer
+
+import (
+	"context"
+	"os/exec"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	constant "isula.org/isula-build"
+)
+
+// Config describes how images pushed to one registry are signed
+type Config struct {
+	// Method is gpg for simple signing or cosign, empty stays unsigned
+	Method string
+	// Key is the gpg key fingerprint or the cosign private key file
+	Key string
+}
+
+var signers struct {
+	sync.RWMutex
+	items map[string]Config
+}
+
+// Init sets the per-registry signing configuration at daemon start
+func Init(items map[string]Config) {
+	signers.Lock()
+	defer signers.Unlock()
+
+	signers.items = make(map[string]Config, len(items))
+	for registry, conf := range items {
+		signers.items[registry] = conf
+	}
+}
+
+// For returns the signing configuration of a registry, the zero config
+// means pushes to the registry stay unsigned
+func For(registry string) Config {
+	signers.RLock()
+	defer signers.RUnlock()
+
+	return signers.items[registry]
+}
+
+// CheckMethod validates a signing method from the daemon config
+func CheckMethod(method string) error {
+	switch method {
+	case constant.SignMethodGPG, constant.SignMethodCosign:
+		return nil
+	default:
+		return errors.Errorf("invalid signing method %q, expected %s or %s",
+			method, constant.SignMethodGPG, constant.SignMethodCosign)
+	}
+}
+
+// SignCosign pushes a cosign signature for the exact reference next to
+// the manifest, the cosign binary handles the registry interaction
+func SignCosign(ctx context.Context, key, ref string) error {
+	// the arguments come from the daemon config and the pushed manifest,
+	// not from client input, no security risk
+	cmd := exec.CommandContext(ctx, "cosign", "sign", "--key", key, ref) // nolint:gosec
+	if bytes, sErr := cmd.CombinedOutput(); sErr != nil {
+		logrus.Errorf("Sign %s with cosign failed, output: %v, err: %v", ref, string(bytes), sErr)
+		return errors.Errorf("sign %s with cosign failed, output: %v, err: %v", ref, string(bytes), sErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: signer related tests
+
+package signer
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	constant "isula.org/isula-build"
+)
+
+func TestInitAndFor(t *testing.T) {
+	Init(map[string]Config{
+		"registry.example.com": {Method: constant.SignMethodGPG, Key: "0123456789ABCDEF"},
+	})
+	defer Init(nil)
+
+	conf := For("registry.example.com")
+	assert.Equal(t, conf.Method, constant.SignMethodGPG)
+	assert.Equal(t, conf.Key, "0123456789ABCDEF")
+
+	// an unlisted registry stays unsigned
+	assert.Equal(t, For("registry.other.com"), Config{})
+}
+
+func TestCheckMethod(t *testing.T) {
+	assert.NilError(t, CheckMethod(constant.SignMethodGPG))
+	assert.NilError(t, CheckMethod(constant.SignMethodCosign))
+	assert.ErrorContains(t, CheckMethod("pgp"), "invalid signing method")
+	assert.ErrorContains(t, CheckMethod(""), "invalid signing method")
+}
@@ -0,0 +1,251 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: indexed access to context tarballs
+
+// Package tarindex scans a context tarball once and records where every
+// entry lives, so COPY and ADD can materialize just the files they need
+// on demand instead of the whole context being unpacked up front
+package tarindex
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/pkg/errors"
+
+	constant "isula.org/isula-build"
+)
+
+// entry records one tarball member and the offset of its payload
+type entry struct {
+	header *tar.Header
+	offset int64
+}
+
+// Index is the entry table of a context tarball, it tracks which
+// entries were already materialized so each one is extracted only once
+type Index struct {
+	tarPath string
+	entries []entry
+	byName  map[string]int
+
+	mu           sync.Mutex
+	materialized map[string]bool
+}
+
+// Open scans the uncompressed tarball at tarPath and builds its index
+func Open(tarPath string) (*Index, error) {
+	f, err := os.Open(filepath.Clean(tarPath))
+	if err != nil {
+		return nil, errors.Wrapf(err, "open context tarball %q failed", tarPath)
+	}
+	defer f.Close() // nolint:errcheck
+
+	idx := &Index{
+		tarPath:      tarPath,
+		byName:       make(map[string]int),
+		materialized: make(map[string]bool),
+	}
+	counter := &countingReader{r: f}
+	tr := tar.NewReader(counter)
+	for {
+		header, rErr := tr.Next()
+		if rErr == io.EOF {
+			break
+		}
+		if rErr != nil {
+			return nil, errors.Wrapf(rErr, "scan context tarball %q failed", tarPath)
+		}
+		name := cleanEntryName(header.Name)
+		if name == "" {
+			continue
+		}
+		idx.byName[name] = len(idx.entries)
+		idx.entries = append(idx.entries, entry{header: header, offset: counter.n})
+	}
+
+	return idx, nil
+}
+
+// Materialize extracts the entries matching the COPY/ADD source pattern
+// into destDir, entries already extracted by an earlier instruction are
+// skipped, an empty pattern or "." materializes the whole context
+func (idx *Index) Materialize(pattern, destDir string) error {
+	pattern = cleanEntryName(pattern)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for i := range idx.entries {
+		name := cleanEntryName(idx.entries[i].header.Name)
+		matched, err := matchEntry(pattern, name)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+		if err := idx.materialize(i, destDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// materialize extracts a single entry, chasing symlink and hardlink
+// targets so the extracted tree can be resolved, the caller must hold
+// the index lock
+func (idx *Index) materialize(i int, destDir string) error {
+	header := idx.entries[i].header
+	name := cleanEntryName(header.Name)
+	if idx.materialized[name] {
+		return nil
+	}
+	idx.materialized[name] = true
+
+	dest, err := securejoin.SecureJoin(destDir, name)
+	if err != nil {
+		return errors.Wrapf(err, "resolve context entry %q failed", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), constant.DefaultRootDirMode); err != nil {
+		return err
+	}
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		if err := os.MkdirAll(dest, os.FileMode(header.Mode)); err != nil {
+			return err
+		}
+	case tar.TypeReg, tar.TypeRegA:
+		if err := idx.extractFile(i, dest); err != nil {
+			return err
+		}
+	case tar.TypeSymlink:
+		// the target is materialized as well so the link can be
+		// resolved, a target outside the context is left dangling
+		if err := idx.materializeTarget(filepath.Join(filepath.Dir(name), header.Linkname), destDir); err != nil {
+			return err
+		}
+		if err := os.Symlink(header.Linkname, dest); err != nil && !os.IsExist(err) {
+			return err
+		}
+	case tar.TypeLink:
+		target := cleanEntryName(header.Linkname)
+		if err := idx.materializeTarget(target, destDir); err != nil {
+			return err
+		}
+		targetPath, jErr := securejoin.SecureJoin(destDir, target)
+		if jErr != nil {
+			return jErr
+		}
+		if err := os.Link(targetPath, dest); err != nil && !os.IsExist(err) {
+			return err
+		}
+	default:
+		// sockets, devices and the like have no business in a build
+		// context, skip them the way docker does
+		return nil
+	}
+	if header.Typeflag != tar.TypeSymlink {
+		if err := os.Chown(dest, header.Uid, header.Gid); err != nil && !os.IsPermission(err) {
+			return err
+		}
+		if err := os.Chtimes(dest, time.Now(), header.ModTime); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// materializeTarget extracts the entry a link points at when the target
+// is part of the tarball
+func (idx *Index) materializeTarget(target, destDir string) error {
+	target = cleanEntryName(target)
+	if i, ok := idx.byName[target]; ok {
+		return idx.materialize(i, destDir)
+	}
+
+	return nil
+}
+
+// extractFile copies the payload of a regular file entry into dest
+func (idx *Index) extractFile(i int, dest string) error {
+	header, offset := idx.entries[i].header, idx.entries[i].offset
+	f, err := os.Open(idx.tarPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close() // nolint:errcheck
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(header.Mode))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, io.NewSectionReader(f, offset, header.Size)); err != nil {
+		out.Close() // nolint:errcheck
+		return errors.Wrapf(err, "extract context entry %q failed", header.Name)
+	}
+
+	return out.Close()
+}
+
+// matchEntry reports whether a tarball entry belongs to the files a
+// COPY/ADD source pattern selects, a match of any ancestor directory
+// selects everything below it
+func matchEntry(pattern, name string) (bool, error) {
+	if pattern == "" || pattern == "." {
+		return true, nil
+	}
+	for sub := name; sub != "." && sub != "/"; sub = filepath.Dir(sub) {
+		matched, err := filepath.Match(pattern, sub)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid source pattern %q", pattern)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// cleanEntryName normalizes a tarball entry name or source pattern to a
+// clean path relative to the context root
+func cleanEntryName(name string) string {
+	name = filepath.Clean(strings.TrimPrefix(filepath.Clean("/"+name), "/"))
+	if name == "." {
+		return ""
+	}
+
+	return name
+}
+
+// countingReader tracks how many bytes were consumed from the tarball
+// so entry payload offsets can be recorded while scanning
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+
+	return n, err
+}
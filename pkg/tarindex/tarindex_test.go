@@ -0,0 +1,115 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: indexed context tarball testing
+
+package tarindex
+
+import (
+	"archive/tar"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+// writeTestTarball writes a context tarball with regular files, a
+// directory tree and a symlink and returns its path
+func writeTestTarball(t *testing.T, dir *fs.Dir) string {
+	tarPath := dir.Join("context.tar")
+	f, err := os.Create(tarPath)
+	assert.NilError(t, err)
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{name: "Dockerfile", content: "FROM scratch\n"},
+		{name: "a.txt", content: "content a"},
+		{name: "dir/b.txt", content: "content b"},
+		{name: "dir/sub/c.bin", content: "content c"},
+	}
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755}))
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: "dir/sub/", Typeflag: tar.TypeDir, Mode: 0755}))
+	for _, file := range files {
+		hdr := &tar.Header{Name: file.name, Mode: 0644, Size: int64(len(file.content))}
+		assert.NilError(t, tw.WriteHeader(hdr))
+		_, err = tw.Write([]byte(file.content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: "link.txt", Typeflag: tar.TypeSymlink, Linkname: "a.txt", Mode: 0777}))
+	assert.NilError(t, tw.Close())
+
+	return tarPath
+}
+
+func TestMaterializeSelectsMatchingEntries(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	defer dir.Remove()
+	idx, err := Open(writeTestTarball(t, dir))
+	assert.NilError(t, err)
+
+	contextDir := fs.NewDir(t, t.Name())
+	defer contextDir.Remove()
+
+	// a directory pattern selects the whole tree below it
+	assert.NilError(t, idx.Materialize("dir", contextDir.Path()))
+	content, err := ioutil.ReadFile(contextDir.Join("dir", "sub", "c.bin"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "content c")
+	_, err = os.Stat(contextDir.Join("a.txt"))
+	assert.Assert(t, os.IsNotExist(err))
+
+	// a glob pattern matches like COPY sources do
+	assert.NilError(t, idx.Materialize("*.txt", contextDir.Path()))
+	content, err = ioutil.ReadFile(contextDir.Join("a.txt"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "content a")
+	_, err = os.Stat(contextDir.Join("Dockerfile"))
+	assert.Assert(t, os.IsNotExist(err))
+}
+
+func TestMaterializeChasesSymlinkTarget(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	defer dir.Remove()
+	idx, err := Open(writeTestTarball(t, dir))
+	assert.NilError(t, err)
+
+	contextDir := fs.NewDir(t, t.Name())
+	defer contextDir.Remove()
+	assert.NilError(t, idx.Materialize("link.txt", contextDir.Path()))
+
+	// the symlink resolves because its target came along
+	content, err := ioutil.ReadFile(contextDir.Join("link.txt"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "content a")
+}
+
+func TestMaterializeWholeContext(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	defer dir.Remove()
+	idx, err := Open(writeTestTarball(t, dir))
+	assert.NilError(t, err)
+
+	contextDir := fs.NewDir(t, t.Name())
+	defer contextDir.Remove()
+	assert.NilError(t, idx.Materialize(".", contextDir.Path()))
+	for _, name := range []string{"Dockerfile", "a.txt", "link.txt"} {
+		_, err = os.Lstat(contextDir.Join(name))
+		assert.NilError(t, err)
+	}
+	content, err := ioutil.ReadFile(contextDir.Join("dir", "b.txt"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "content b")
+}
@@ -0,0 +1,345 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-01-10
+// Description: This file provides a content-addressed cache for downloaded URL resources
+
+// Package urlcache caches resources downloaded from HTTP(S) URLs,
+// such as sources of the ADD command, so repeated builds do not
+// download the same content again
+package urlcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	constant "isula.org/isula-build"
+)
+
+const (
+	metaSuffix = ".json"
+	// DefaultUnusedThreshold is the duration after which an unused entry can be pruned
+	DefaultUnusedThreshold = 7 * 24 * time.Hour
+	// DefaultPruneInterval is the interval of periodic cache pruning
+	DefaultPruneInterval = 24 * time.Hour
+)
+
+// Entry records the metadata of one cached download
+type Entry struct {
+	// Key is the content address of the entry, derived from URL
+	Key string `json:"key"`
+	// URL is the origin of the cached data
+	URL string `json:"url"`
+	// ETag is the entity tag got from the origin server, may be empty
+	ETag string `json:"etag,omitempty"`
+	// LastModified is the Last-Modified header got from the origin server, may be empty
+	LastModified string `json:"lastModified,omitempty"`
+	// Size is the size of the cached data in bytes
+	Size int64 `json:"size"`
+	// CreatedAt is the time the data was downloaded
+	CreatedAt time.Time `json:"createdAt"`
+	// LastUsedAt is the time the entry was hit most recently
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+// Cache is a content-addressed store for downloaded URL resources
+type Cache struct {
+	sync.Mutex
+	root    string
+	keyLock map[string]*sync.Mutex
+	client  *http.Client
+}
+
+var (
+	defaultCache *Cache
+	defaultLock  sync.Mutex
+)
+
+// Init creates the default cache rooted at dir, which is
+// shared by all the builds within the daemon
+func Init(dir string) (*Cache, error) {
+	defaultLock.Lock()
+	defer defaultLock.Unlock()
+
+	c, err := NewCache(dir)
+	if err != nil {
+		return nil, err
+	}
+	defaultCache = c
+
+	return c, nil
+}
+
+// Default returns the cache created by Init, may be nil
+// if the daemon has not initialized it
+func Default() *Cache {
+	defaultLock.Lock()
+	defer defaultLock.Unlock()
+
+	return defaultCache
+}
+
+// NewCache creates a Cache rooted at dir
+func NewCache(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, constant.DefaultRootDirMode); err != nil {
+		return nil, errors.Wrapf(err, "create url cache root %q failed", dir)
+	}
+
+	return &Cache{
+		root:    dir,
+		keyLock: make(map[string]*sync.Mutex),
+		client:  &http.Client{Timeout: constant.DefaultHTTPTimeout},
+	}, nil
+}
+
+// KeyOfURL returns the content address for url
+func KeyOfURL(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) lockKey(key string) *sync.Mutex {
+	c.Lock()
+	defer c.Unlock()
+
+	l, ok := c.keyLock[key]
+	if !ok {
+		l = &sync.Mutex{}
+		c.keyLock[key] = l
+	}
+
+	return l
+}
+
+func (c *Cache) dataPath(key string) string {
+	return filepath.Join(c.root, key)
+}
+
+func (c *Cache) metaPath(key string) string {
+	return filepath.Join(c.root, key+metaSuffix)
+}
+
+func (c *Cache) loadEntry(key string) (*Entry, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(c.metaPath(key)))
+	if err != nil {
+		return nil, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (c *Cache) saveEntry(entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.metaPath(entry.Key), data, constant.DefaultRootFileMode)
+}
+
+// Fetch returns the path of the local cached copy for url, downloading
+// it first when the cache has no valid entry. The cached copy is
+// revalidated against the origin server with the recorded ETag or
+// Last-Modified when possible
+func (c *Cache) Fetch(ctx context.Context, url string) (string, error) {
+	key := KeyOfURL(url)
+	l := c.lockKey(key)
+	l.Lock()
+	defer l.Unlock()
+
+	entry, lerr := c.loadEntry(key)
+	if lerr == nil {
+		if _, serr := os.Stat(c.dataPath(key)); serr != nil {
+			entry = nil
+		}
+	} else {
+		entry = nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to new a request %q", url)
+	}
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// tolerate origin server failures when a cached copy is present
+		if entry != nil {
+			logrus.Warnf("Revalidating %q failed: %v, using cached copy", url, err)
+			c.touch(entry)
+			return c.dataPath(key), nil
+		}
+		return "", errors.Wrapf(err, "error getting %q", url)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			logrus.Warnf("Closing resp.Body failed: %v", cerr)
+		}
+	}()
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		logrus.Debugf("URL cache hit for %q with key %s", url, key)
+		c.touch(entry)
+		return c.dataPath(key), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to get %q with status %q", url, resp.Status)
+	}
+
+	size, err := c.store(key, resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	entry = &Entry{
+		Key:          key,
+		URL:          url,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Size:         size,
+		CreatedAt:    now,
+		LastUsedAt:   now,
+	}
+	if err := c.saveEntry(entry); err != nil {
+		return "", errors.Wrapf(err, "save cache metadata for %q failed", url)
+	}
+	logrus.Debugf("URL cache stores %q with key %s", url, key)
+
+	return c.dataPath(key), nil
+}
+
+func (c *Cache) store(key string, r io.Reader) (int64, error) {
+	tmp, err := ioutil.TempFile(c.root, "download-")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if rerr := os.Remove(tmp.Name()); rerr != nil && !os.IsNotExist(rerr) {
+			logrus.Warnf("Removing temporary download file failed: %v", rerr)
+		}
+	}()
+
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		if cerr := tmp.Close(); cerr != nil {
+			logrus.Warnf("Closing temporary download file failed: %v", cerr)
+		}
+		return 0, err
+	}
+	if err := tmp.Chmod(constant.DefaultRootFileMode); err != nil {
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), c.dataPath(key)); err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+func (c *Cache) touch(entry *Entry) {
+	entry.LastUsedAt = time.Now()
+	if err := c.saveEntry(entry); err != nil {
+		logrus.Warnf("Updating cache metadata for %q failed: %v", entry.URL, err)
+	}
+}
+
+// List returns all the entries in the cache
+func (c *Cache) List() ([]Entry, error) {
+	files, err := ioutil.ReadDir(c.root)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), metaSuffix) {
+			continue
+		}
+		entry, err := c.loadEntry(strings.TrimSuffix(f.Name(), metaSuffix))
+		if err != nil {
+			logrus.Warnf("Loading cache metadata %q failed: %v", f.Name(), err)
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+
+	return entries, nil
+}
+
+// Remove deletes the entry with key from the cache
+func (c *Cache) Remove(key string) error {
+	if _, err := c.loadEntry(key); err != nil {
+		return errors.Errorf("no cache entry with key %q", key)
+	}
+
+	l := c.lockKey(key)
+	l.Lock()
+	defer l.Unlock()
+
+	if err := os.Remove(c.dataPath(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return os.Remove(c.metaPath(key))
+}
+
+// Prune deletes all the entries which have not been used for
+// longer than unusedFor, and returns the number of pruned entries
+func (c *Cache) Prune(unusedFor time.Duration) (int, error) {
+	entries, err := c.List()
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	now := time.Now()
+	for i := range entries {
+		if now.Sub(entries[i].LastUsedAt) < unusedFor {
+			continue
+		}
+		if err := c.Remove(entries[i].Key); err != nil {
+			logrus.Warnf("Pruning cache entry %q failed: %v", entries[i].Key, err)
+			continue
+		}
+		pruned++
+	}
+
+	return pruned, nil
+}
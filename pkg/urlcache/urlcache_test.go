@@ -0,0 +1,132 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-01-10
+// Description: url cache tests
+
+package urlcache
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestFetchAndRevalidate(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.Header.Get("If-None-Match") == "etag-one" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", "etag-one")
+		fmt.Fprint(w, "cached content")
+	}))
+	defer server.Close()
+
+	root := fs.NewDir(t, t.Name())
+	defer root.Remove()
+	cache, err := NewCache(root.Path())
+	assert.NilError(t, err)
+
+	ctx := context.Background()
+	path, err := cache.Fetch(ctx, server.URL)
+	assert.NilError(t, err)
+	data, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "cached content")
+	assert.Equal(t, hits, 1)
+
+	// second fetch revalidates with ETag and hits the cache
+	path2, err := cache.Fetch(ctx, server.URL)
+	assert.NilError(t, err)
+	assert.Equal(t, path2, path)
+	assert.Equal(t, hits, 2)
+	data, err = ioutil.ReadFile(path2)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "cached content")
+}
+
+func TestFetchServerFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", "etag-one")
+		fmt.Fprint(w, "cached content")
+	}))
+
+	root := fs.NewDir(t, t.Name())
+	defer root.Remove()
+	cache, err := NewCache(root.Path())
+	assert.NilError(t, err)
+
+	ctx := context.Background()
+	url := server.URL
+	_, err = cache.Fetch(ctx, url)
+	assert.NilError(t, err)
+
+	// origin server down, the cached copy is still usable
+	server.Close()
+	path, err := cache.Fetch(ctx, url)
+	assert.NilError(t, err)
+	data, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "cached content")
+
+	// no cached copy for a new url, the failure is reported
+	_, err = cache.Fetch(ctx, url+"/other")
+	assert.ErrorContains(t, err, "error getting")
+}
+
+func TestListRemovePrune(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "content for ", r.URL.Path)
+	}))
+	defer server.Close()
+
+	root := fs.NewDir(t, t.Name())
+	defer root.Remove()
+	cache, err := NewCache(root.Path())
+	assert.NilError(t, err)
+
+	ctx := context.Background()
+	_, err = cache.Fetch(ctx, server.URL+"/a")
+	assert.NilError(t, err)
+	_, err = cache.Fetch(ctx, server.URL+"/b")
+	assert.NilError(t, err)
+
+	entries, err := cache.List()
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 2)
+
+	err = cache.Remove(KeyOfURL(server.URL + "/a"))
+	assert.NilError(t, err)
+	entries, err = cache.List()
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0].URL, server.URL+"/b")
+
+	err = cache.Remove("not-exist")
+	assert.ErrorContains(t, err, "no cache entry")
+
+	// nothing is fresh enough to survive a zero threshold
+	pruned, err := cache.Prune(time.Duration(0))
+	assert.NilError(t, err)
+	assert.Equal(t, pruned, 1)
+	entries, err = cache.List()
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 0)
+}
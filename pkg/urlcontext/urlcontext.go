@@ -0,0 +1,176 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: http(s) tarball build context fetching
+
+// Package urlcontext fetches build contexts from http(s) tarball URLs,
+// so a build can be started from an archive like
+// https://example.com/context.tar.gz#sha256:<hex> instead of a local
+// directory
+package urlcontext
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	constant "isula.org/isula-build"
+	"isula.org/isula-build/pkg/tarindex"
+	"isula.org/isula-build/pkg/urlcache"
+	"isula.org/isula-build/util"
+)
+
+// checksumPrefix starts the optional checksum fragment of a context URL
+const checksumPrefix = "sha256:"
+
+// tarballSuffixes are the archive endings accepted as context URL
+var tarballSuffixes = []string{".tar.gz", ".tgz", ".tar"}
+
+// IsURLContext returns true when the build context argument refers to a
+// context tarball served over http(s)
+func IsURLContext(s string) bool {
+	if !strings.HasPrefix(s, "http://") && !strings.HasPrefix(s, "https://") {
+		return false
+	}
+	rawURL, _ := Parse(s)
+	for _, suffix := range tarballSuffixes {
+		if strings.HasSuffix(rawURL, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse splits a context URL into the download URL and the optional
+// sha256 checksum given as fragment: archive.tar.gz#sha256:<hex>
+func Parse(s string) (rawURL, checksum string) {
+	idx := strings.Index(s, "#")
+	if idx == -1 {
+		return s, ""
+	}
+
+	return s[:idx], strings.TrimPrefix(s[idx+1:], checksumPrefix)
+}
+
+// Fetch downloads the context tarball of the given URL, verifies its
+// checksum when one is given and indexes it below destDir, the archive
+// is not unpacked up front, instead the returned index materializes
+// files into the context directory when COPY and ADD ask for them
+func Fetch(ctx context.Context, s, destDir string) (string, *tarindex.Index, error) {
+	rawURL, checksum := Parse(s)
+
+	archivePath, err := download(ctx, rawURL, destDir)
+	if err != nil {
+		return "", nil, err
+	}
+	if checksum != "" {
+		if err := util.CheckSum(archivePath, checksum); err != nil {
+			return "", nil, errors.Wrapf(err, "verify context archive of %q failed", rawURL)
+		}
+	}
+
+	contextDir := filepath.Join(destDir, "context")
+	if err := os.MkdirAll(contextDir, constant.DefaultRootDirMode); err != nil {
+		return "", nil, errors.Wrapf(err, "create context dir %q failed", contextDir)
+	}
+	tarPath := archivePath
+	if !strings.HasSuffix(rawURL, ".tar") {
+		// a compressed archive is not seekable, decompress it once
+		// into a flat tarball the index can read entries from
+		if tarPath, err = decompress(archivePath, destDir); err != nil {
+			return "", nil, errors.Wrapf(err, "decompress context archive of %q failed", rawURL)
+		}
+	}
+	idx, err := tarindex.Open(tarPath)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "index context archive of %q failed", rawURL)
+	}
+	// the Dockerfile and the ignore rules are read before the first
+	// instruction runs, so they are materialized right away
+	for _, name := range []string{"Dockerfile", ".dockerignore"} {
+		if err := idx.Materialize(name, contextDir); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return contextDir, idx, nil
+}
+
+// decompress writes the gzip compressed archive as a flat tarball into
+// destDir and returns its path
+func decompress(archivePath, destDir string) (string, error) {
+	in, err := os.Open(filepath.Clean(archivePath))
+	if err != nil {
+		return "", err
+	}
+	defer in.Close() // nolint:errcheck
+	gzReader, err := gzip.NewReader(in)
+	if err != nil {
+		return "", err
+	}
+	defer gzReader.Close() // nolint:errcheck
+
+	tarPath := filepath.Join(destDir, "context.tar")
+	out, err := os.OpenFile(tarPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, constant.DefaultRootFileMode)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, gzReader); err != nil {
+		out.Close() // nolint:errcheck
+		return "", err
+	}
+
+	return tarPath, out.Close()
+}
+
+// download stores the tarball of rawURL below destDir and returns its
+// path, going through the daemon's url cache when it is initialized
+func download(ctx context.Context, rawURL, destDir string) (string, error) {
+	if cache := urlcache.Default(); cache != nil {
+		return cache.Fetch(ctx, rawURL)
+	}
+
+	if err := os.MkdirAll(destDir, constant.DefaultRootDirMode); err != nil {
+		return "", errors.Wrapf(err, "create context download dir %q failed", destDir)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to new a request %q", rawURL)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "error getting %q", rawURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("invalid response status %d getting %q", resp.StatusCode, rawURL)
+	}
+
+	f, err := ioutil.TempFile(destDir, "context-")
+	if err != nil {
+		return "", errors.Wrap(err, "create context archive file failed")
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		return "", errors.Wrapf(err, "download %q failed", rawURL)
+	}
+
+	return f.Name(), nil
+}
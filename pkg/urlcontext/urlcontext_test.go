@@ -0,0 +1,120 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: http(s) tarball build context tests
+
+package urlcontext
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/storage/pkg/archive"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestIsURLContext(t *testing.T) {
+	type testcase struct {
+		name string
+		arg  string
+		want bool
+	}
+	var testcases = []testcase{
+		{
+			name: "tar.gz url",
+			arg:  "https://example.com/context.tar.gz",
+			want: true,
+		},
+		{
+			name: "tgz url with checksum",
+			arg:  "http://example.com/context.tgz#sha256:abcd",
+			want: true,
+		},
+		{
+			name: "plain tar url",
+			arg:  "https://example.com/context.tar",
+			want: true,
+		},
+		{
+			name: "git url",
+			arg:  "https://example.com/repo.git",
+			want: false,
+		},
+		{
+			name: "local directory",
+			arg:  "/home/user/context",
+			want: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, IsURLContext(tc.arg), tc.want, "Failed at [%s]", tc.name)
+	}
+}
+
+func TestParse(t *testing.T) {
+	rawURL, checksum := Parse("https://example.com/context.tar.gz#sha256:abcd")
+	assert.Equal(t, rawURL, "https://example.com/context.tar.gz")
+	assert.Equal(t, checksum, "abcd")
+
+	rawURL, checksum = Parse("https://example.com/context.tar.gz")
+	assert.Equal(t, rawURL, "https://example.com/context.tar.gz")
+	assert.Equal(t, checksum, "")
+}
+
+func TestFetch(t *testing.T) {
+	srcDir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM scratch\n"),
+		fs.WithFile("app.bin", "binary content"))
+	defer srcDir.Remove()
+	reader, err := archive.Tar(srcDir.Path(), archive.Gzip)
+	assert.NilError(t, err)
+	tarball, err := ioutil.ReadAll(reader)
+	assert.NilError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, wErr := w.Write(tarball); wErr != nil {
+			t.Errorf("write tarball failed: %v", wErr)
+		}
+	}))
+	defer server.Close()
+
+	destDir := fs.NewDir(t, t.Name())
+	defer destDir.Remove()
+	contextDir, idx, err := Fetch(context.Background(), server.URL+"/context.tar.gz", destDir.Path())
+	assert.NilError(t, err)
+	content, err := ioutil.ReadFile(filepath.Join(contextDir, "Dockerfile"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "FROM scratch\n")
+
+	// other files stay in the archive until an instruction asks for them
+	_, err = os.Stat(filepath.Join(contextDir, "app.bin"))
+	assert.Assert(t, os.IsNotExist(err))
+	assert.NilError(t, idx.Materialize("app.bin", contextDir))
+	content, err = ioutil.ReadFile(filepath.Join(contextDir, "app.bin"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "binary content")
+
+	// a wrong checksum is rejected
+	badDir := fs.NewDir(t, t.Name())
+	defer badDir.Remove()
+	wrongSum := fmt.Sprintf("%x", sha256.Sum256([]byte("bad")))
+	_, _, err = Fetch(context.Background(), server.URL+"/context.tar.gz#sha256:"+wrongSum, badDir.Path())
+	assert.ErrorContains(t, err, "verify context archive")
+}
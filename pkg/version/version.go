@@ -14,6 +14,10 @@
 // Package version defines version related variations
 package version
 
+// APIVersion represents the GRPC API version spoken by client and daemon,
+// the major part must match for the two sides to work together
+const APIVersion = "1.0"
+
 var (
 	// Version represents client/daemon version
 	Version string
@@ -16,9 +16,11 @@ package store
 
 import (
 	"sync"
+	"time"
 
 	is "github.com/containers/image/v5/storage"
 	"github.com/containers/storage"
+	"github.com/containers/storage/pkg/unshare"
 	"github.com/sirupsen/logrus"
 )
 
@@ -48,7 +50,7 @@ func SetStorageConfigFilePath(path string) {
 
 // GetStorageConfigFileOptions returns the default storage config options.
 func GetStorageConfigFileOptions() (storage.StoreOptions, error) {
-	options, err := storage.DefaultStoreOptions(false, 0)
+	options, err := storage.DefaultStoreOptions(unshare.IsRootless(), unshare.GetRootlessUID())
 	if err != nil {
 		return storage.StoreOptions{}, err
 	}
@@ -61,9 +63,11 @@ func SetDefaultStoreOptions(opt DaemonStoreOptions) {
 	storeOpts = opt
 }
 
-// GetDefaultStoreOptions returns default store options.
+// GetDefaultStoreOptions returns default store options. In rootless
+// mode the storage library resolves them from the per-user
+// configuration and ID mappings instead of the system-wide ones.
 func GetDefaultStoreOptions() (storage.StoreOptions, error) {
-	options, err := storage.DefaultStoreOptions(false, 0)
+	options, err := storage.DefaultStoreOptions(unshare.IsRootless(), unshare.GetRootlessUID())
 	if err != nil {
 		return storage.StoreOptions{}, err
 	}
@@ -109,6 +113,32 @@ func (s *Store) CleanContainers() {
 	}
 }
 
+// CleanContainersInBackground removes the residual containers of a
+// previous daemon run without delaying startup, a store holding tens
+// of thousands of layers may take minutes to unwind them. Only the
+// containers present when it is called are removed, so containers
+// created by builds starting meanwhile are never touched
+func (s *Store) CleanContainersInBackground() {
+	containers, err := s.Containers()
+	if err != nil {
+		logrus.Warn("Failed to get containers while cleaning the container store")
+		return
+	}
+	if len(containers) == 0 {
+		return
+	}
+
+	go func() {
+		start := time.Now()
+		for _, container := range containers {
+			if cerr := s.CleanContainer(container.ID); cerr != nil {
+				logrus.Warnf("Clean container %q failed", container.ID)
+			}
+		}
+		logrus.Debugf("Cleaned %d residual build containers in %v", len(containers), time.Since(start))
+	}()
+}
+
 // CleanContainer cleans the container in store
 func (s *Store) CleanContainer(id string) error {
 	s.Lock()
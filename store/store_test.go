@@ -53,3 +53,27 @@ func TestGetStore(t *testing.T) {
 	assert.Equal(t, s.RunRoot(), storeOpts.RunRoot)
 	assert.Equal(t, s.GraphRoot(), storeOpts.DataRoot)
 }
+
+func TestCleanContainersInBackground(t *testing.T) {
+	dataDir := "/tmp/lib-clean"
+	runDir := "/tmp/run-clean"
+	storeOpts.DataRoot = filepath.Join(dataDir, "containers/storage")
+	storeOpts.RunRoot = filepath.Join(runDir, "containers/storage")
+
+	s, err := GetStore()
+	assert.NilError(t, err)
+	defer func() {
+		unix.Unmount(filepath.Join(storeOpts.DataRoot, "overlay"), 0)
+		unix.Unmount(filepath.Join(storeOpts.RunRoot, "overlay"), 0)
+		os.RemoveAll(dataDir)
+		os.RemoveAll(runDir)
+	}()
+
+	// an empty store has no residual containers, the call returns
+	// without spawning the background cleaner
+	s.CleanContainersInBackground()
+
+	containers, err := s.Containers()
+	assert.NilError(t, err)
+	assert.Equal(t, len(containers), 0)
+}
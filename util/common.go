@@ -15,6 +15,7 @@
 package util
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -31,6 +32,23 @@ import (
 
 const maxServerNameLength = 255
 
+// RunWithContext runs f and waits for either its completion or the end of
+// ctx, whichever comes first. When ctx ends first the error of ctx is
+// returned promptly and the blocked f keeps running in the background
+func RunWithContext(ctx context.Context, f func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- f()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
 // CopyMapStringString copies all KVs in a map[string]string to a new map
 func CopyMapStringString(m map[string]string) map[string]string {
 	result := map[string]string{}
@@ -91,6 +109,18 @@ func SetUmask() bool {
 	return unix.Umask(wanted) == wanted
 }
 
+// IsOCILayoutDir returns true when path is a directory holding an oci
+// image layout, recognized by its oci-layout marker file
+func IsOCILayoutDir(path string) bool {
+	fi, err := os.Stat(filepath.Clean(path))
+	if err != nil || !fi.IsDir() {
+		return false
+	}
+	marker, err := os.Stat(filepath.Join(path, "oci-layout"))
+
+	return err == nil && marker.Mode().IsRegular()
+}
+
 // CheckFileInfoAndSize check whether the file exists, is regular file, and if its size exceeds limit
 func CheckFileInfoAndSize(path string, sizeLimit int64) error {
 	f, err := os.Stat(filepath.Clean(path))
@@ -14,9 +14,12 @@
 package util
 
 import (
+	"context"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/fs"
 )
@@ -248,3 +251,24 @@ func TestAnyFlagSet(t *testing.T) {
 		})
 	}
 }
+
+func TestRunWithContext(t *testing.T) {
+	ctx := context.Background()
+	err := RunWithContext(ctx, func() error { return nil })
+	assert.NilError(t, err)
+
+	err = RunWithContext(ctx, func() error { return errors.New("run failed") })
+	assert.ErrorContains(t, err, "run failed")
+
+	blocked := make(chan struct{})
+	defer close(blocked)
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	start := time.Now()
+	err = RunWithContext(cancelCtx, func() error {
+		<-blocked
+		return nil
+	})
+	assert.ErrorContains(t, err, "context canceled")
+	assert.Assert(t, time.Since(start) < time.Second)
+}
@@ -0,0 +1,214 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: on the fly archive compression related functions
+
+package util
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
+	constant "isula.org/isula-build"
+)
+
+const (
+	// maxGzipLevel is the highest gzip compression level
+	maxGzipLevel = 9
+	// maxZstdLevel is the highest zstd compression level
+	maxZstdLevel = 22
+)
+
+// ArchiveCompression returns the stream compression the suffix of an
+// archive path asks for, gzip for .tar.gz and .tgz, zstd for .tar.zst
+// and .tzst, and empty for everything else
+func ArchiveCompression(path string) string {
+	switch {
+	case strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz"):
+		return constant.CompressionGzip
+	case strings.HasSuffix(path, ".tar.zst") || strings.HasSuffix(path, ".tzst"):
+		return constant.CompressionZstd
+	default:
+		return ""
+	}
+}
+
+// CheckArchiveCompressionLevel checks the compression level against
+// the format of the archive, 0 always stands for the default level
+func CheckArchiveCompressionLevel(format string, level int) error {
+	if level == 0 {
+		return nil
+	}
+	switch format {
+	case constant.CompressionGzip:
+		if level < gzip.BestSpeed || level > maxGzipLevel {
+			return errors.Errorf("invalid gzip compression level %d, expected 1 to %d", level, maxGzipLevel)
+		}
+	case constant.CompressionZstd:
+		if level < 1 || level > maxZstdLevel {
+			return errors.Errorf("invalid zstd compression level %d, expected 1 to %d", level, maxZstdLevel)
+		}
+	default:
+		return errors.Errorf("compression level %d needs a .tar.gz or .tar.zst output", level)
+	}
+
+	return nil
+}
+
+// newCompressWriter wraps w with the requested stream compression
+func newCompressWriter(w io.Writer, format string, level int) (io.WriteCloser, error) {
+	switch format {
+	case constant.CompressionGzip:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		return gzip.NewWriterLevel(w, level)
+	case constant.CompressionZstd:
+		if level == 0 {
+			return zstd.NewWriter(w)
+		}
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	default:
+		return nil, errors.Errorf("unsupported archive compression %q", format)
+	}
+}
+
+// FifoCompressor compresses an archive on the fly, the writer streams
+// the plain archive into a fifo and the compressor drains the fifo
+// into the destination file, so the plain archive never hits the disk
+type FifoCompressor struct {
+	fifoDir  string
+	fifoPath string
+	destPath string
+	// opened is closed once the drain got past its blocking fifo open
+	opened chan struct{}
+	done   chan error
+}
+
+// NewFifoCompressor creates a fifo under tmpDir and starts draining
+// it compressed into dest, the archive should be written to Path()
+// and Finish() or Abort() must be called to release the compressor
+func NewFifoCompressor(tmpDir, dest, format string, level int) (*FifoCompressor, error) {
+	if err := CheckArchiveCompressionLevel(format, level); err != nil {
+		return nil, err
+	}
+
+	fifoDir, err := ioutil.TempDir(tmpDir, "compress-fifo-")
+	if err != nil {
+		return nil, errors.Wrap(err, "create fifo dir for compressing failed")
+	}
+	fifoPath := filepath.Join(fifoDir, "archive.fifo")
+	if err := unix.Mkfifo(fifoPath, constant.DefaultRootFileMode); err != nil {
+		if rErr := os.RemoveAll(fifoDir); rErr != nil {
+			logrus.Warnf("Removing fifo dir %q failed: %v", fifoDir, rErr)
+		}
+		return nil, errors.Wrapf(err, "create fifo for compressing %q failed", dest)
+	}
+
+	f := &FifoCompressor{
+		fifoDir:  fifoDir,
+		fifoPath: fifoPath,
+		destPath: dest,
+		opened:   make(chan struct{}),
+		done:     make(chan error, 1),
+	}
+	go f.drain(format, level)
+
+	return f, nil
+}
+
+// Path is the fifo path the plain archive should be written to
+func (f *FifoCompressor) Path() string {
+	return f.fifoPath
+}
+
+// drain compresses everything written to the fifo into the
+// destination file, the open blocks until the archive writer side
+// opens the fifo as well
+func (f *FifoCompressor) drain(format string, level int) {
+	in, err := os.OpenFile(f.fifoPath, os.O_RDONLY, 0)
+	close(f.opened)
+	if err != nil {
+		f.done <- errors.Wrap(err, "open fifo for compressing failed")
+		return
+	}
+	defer func() {
+		if cErr := in.Close(); cErr != nil {
+			logrus.Warnf("Closing fifo %q failed: %v", f.fifoPath, cErr)
+		}
+	}()
+
+	out, err := os.OpenFile(f.destPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, constant.DefaultRootFileMode)
+	if err != nil {
+		f.done <- errors.Wrapf(err, "create compressed output %q failed", f.destPath)
+		return
+	}
+
+	cw, err := newCompressWriter(out, format, level)
+	if err != nil {
+		f.done <- err
+		return
+	}
+	_, err = io.Copy(cw, in)
+	if cErr := cw.Close(); cErr != nil && err == nil {
+		err = cErr
+	}
+	if cErr := out.Close(); cErr != nil && err == nil {
+		err = cErr
+	}
+	f.done <- errors.Wrapf(err, "compress archive to %q failed", f.destPath)
+}
+
+// Finish waits until the fifo is drained and the compressed
+// destination is complete, it must only be called after the archive
+// writer side closed the fifo
+func (f *FifoCompressor) Finish() error {
+	err := <-f.done
+	f.cleanup()
+
+	return err
+}
+
+// Abort releases a compressor whose archive was never fully written,
+// the half-done destination file is removed
+func (f *FifoCompressor) Abort() {
+	// opening read-write never blocks on a fifo, it pairs with a drain
+	// still blocked in its open, and closing after the drain got past
+	// that open makes the drain see EOF at once
+	if w, err := os.OpenFile(f.fifoPath, os.O_RDWR, 0); err == nil {
+		<-f.opened
+		if cErr := w.Close(); cErr != nil {
+			logrus.Warnf("Closing fifo %q failed: %v", f.fifoPath, cErr)
+		}
+	}
+	if err := <-f.done; err != nil {
+		logrus.Warnf("Draining fifo of %q stopped with: %v", f.destPath, err)
+	}
+	f.cleanup()
+	if err := os.Remove(f.destPath); err != nil && !os.IsNotExist(err) {
+		logrus.Warnf("Removing compressed output %q failed: %v", f.destPath, err)
+	}
+}
+
+func (f *FifoCompressor) cleanup() {
+	if err := os.RemoveAll(f.fifoDir); err != nil {
+		logrus.Warnf("Removing fifo dir %q failed: %v", f.fifoDir, err)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: on the fly archive compression related tests
+
+package util
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	constant "isula.org/isula-build"
+)
+
+func TestArchiveCompression(t *testing.T) {
+	assert.Equal(t, ArchiveCompression("image.tar.gz"), constant.CompressionGzip)
+	assert.Equal(t, ArchiveCompression("image.tgz"), constant.CompressionGzip)
+	assert.Equal(t, ArchiveCompression("image.tar.zst"), constant.CompressionZstd)
+	assert.Equal(t, ArchiveCompression("image.tzst"), constant.CompressionZstd)
+	assert.Equal(t, ArchiveCompression("image.tar"), "")
+	assert.Equal(t, ArchiveCompression("image"), "")
+}
+
+func TestCheckArchiveCompressionLevel(t *testing.T) {
+	assert.NilError(t, CheckArchiveCompressionLevel("", 0))
+	assert.NilError(t, CheckArchiveCompressionLevel(constant.CompressionGzip, 9))
+	assert.NilError(t, CheckArchiveCompressionLevel(constant.CompressionZstd, 22))
+	assert.ErrorContains(t, CheckArchiveCompressionLevel(constant.CompressionGzip, 10), "invalid gzip compression level")
+	assert.ErrorContains(t, CheckArchiveCompressionLevel(constant.CompressionZstd, 23), "invalid zstd compression level")
+	assert.ErrorContains(t, CheckArchiveCompressionLevel("", 5), "needs a .tar.gz or .tar.zst output")
+}
+
+func TestFifoCompressorGzip(t *testing.T) {
+	tmpDir := fs.NewDir(t, t.Name())
+	defer tmpDir.Remove()
+
+	content := bytes.Repeat([]byte("isula-build"), 1024)
+	dest := tmpDir.Join("image.tar.gz")
+	fifo, err := NewFifoCompressor(tmpDir.Path(), dest, constant.CompressionGzip, 1)
+	assert.NilError(t, err)
+
+	w, err := os.OpenFile(fifo.Path(), os.O_WRONLY, 0)
+	assert.NilError(t, err)
+	_, err = w.Write(content)
+	assert.NilError(t, err)
+	assert.NilError(t, w.Close())
+	assert.NilError(t, fifo.Finish())
+
+	f, err := os.Open(dest)
+	assert.NilError(t, err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	assert.NilError(t, err)
+	got, err := ioutil.ReadAll(gr)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, content)
+}
+
+func TestFifoCompressorZstd(t *testing.T) {
+	tmpDir := fs.NewDir(t, t.Name())
+	defer tmpDir.Remove()
+
+	content := bytes.Repeat([]byte("isula-build"), 1024)
+	dest := tmpDir.Join("image.tar.zst")
+	fifo, err := NewFifoCompressor(tmpDir.Path(), dest, constant.CompressionZstd, 0)
+	assert.NilError(t, err)
+
+	w, err := os.OpenFile(fifo.Path(), os.O_WRONLY, 0)
+	assert.NilError(t, err)
+	_, err = w.Write(content)
+	assert.NilError(t, err)
+	assert.NilError(t, w.Close())
+	assert.NilError(t, fifo.Finish())
+
+	compressed, err := ioutil.ReadFile(dest)
+	assert.NilError(t, err)
+	zr, err := zstd.NewReader(bytes.NewReader(compressed))
+	assert.NilError(t, err)
+	defer zr.Close()
+	got, err := ioutil.ReadAll(zr)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, content)
+}
+
+func TestFifoCompressorAbort(t *testing.T) {
+	tmpDir := fs.NewDir(t, t.Name())
+	defer tmpDir.Remove()
+
+	dest := tmpDir.Join("image.tar.gz")
+	fifo, err := NewFifoCompressor(tmpDir.Path(), dest, constant.CompressionGzip, 0)
+	assert.NilError(t, err)
+
+	// the archive writer never opened the fifo
+	fifo.Abort()
+	_, err = os.Stat(dest)
+	assert.Assert(t, os.IsNotExist(err))
+
+	// an unsupported level is rejected before the fifo is created
+	_, err = NewFifoCompressor(tmpDir.Path(), dest, constant.CompressionGzip, 100)
+	assert.ErrorContains(t, err, "invalid gzip compression level")
+}
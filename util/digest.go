@@ -0,0 +1,47 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-20
+// Description: pluggable digest algorithm for local content addressing
+
+package util
+
+import (
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// supportedDigestAlgorithms lists the algorithms selectable for locally
+// computed manifests and configs; content pushed to a registry is always
+// re-addressed with the canonical algorithm
+var supportedDigestAlgorithms = []digest.Algorithm{digest.SHA256, digest.SHA512}
+
+// digestAlgorithm addresses locally computed manifests and configs, it is
+// set once from the daemon configuration before any build starts
+var digestAlgorithm = digest.Canonical
+
+// SetDigestAlgorithm selects the algorithm addressing locally computed
+// manifests and configs
+func SetDigestAlgorithm(name string) error {
+	for _, algorithm := range supportedDigestAlgorithms {
+		if string(algorithm) == name && algorithm.Available() {
+			digestAlgorithm = algorithm
+			return nil
+		}
+	}
+
+	return errors.Errorf("the digest algorithm %q is not supported, choose one of %v", name, supportedDigestAlgorithms)
+}
+
+// DigestAlgorithm returns the algorithm addressing locally computed
+// manifests and configs
+func DigestAlgorithm() digest.Algorithm {
+	return digestAlgorithm
+}
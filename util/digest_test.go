@@ -0,0 +1,41 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-03-20
+// Description: digest algorithm selection related tests
+
+package util
+
+import (
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/v3/assert"
+)
+
+func TestSetDigestAlgorithm(t *testing.T) {
+	defer func() {
+		digestAlgorithm = digest.Canonical
+	}()
+
+	assert.Equal(t, DigestAlgorithm(), digest.Canonical)
+
+	err := SetDigestAlgorithm("sha512")
+	assert.NilError(t, err)
+	assert.Equal(t, DigestAlgorithm(), digest.SHA512)
+
+	err = SetDigestAlgorithm("sm3")
+	assert.ErrorContains(t, err, `the digest algorithm "sm3" is not supported`)
+	assert.Equal(t, DigestAlgorithm(), digest.SHA512)
+
+	err = SetDigestAlgorithm("sha256")
+	assert.NilError(t, err)
+	assert.Equal(t, DigestAlgorithm(), digest.SHA256)
+}
@@ -113,6 +113,65 @@ func PackFiles(src, dest string, com archive.Compression, needModifyTime bool) (
 	return nil
 }
 
+// GetDirSize sums up the size of all regular files under dir recursively
+func GetDirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// entries removed while walking are not an error
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// CheckDirSizeLimit checks whether the size of dir exceeds the given limit
+func CheckDirSizeLimit(dir string, limit int64) error {
+	size, err := GetDirSize(dir)
+	if err != nil {
+		return err
+	}
+	if size > limit {
+		return errors.Errorf("size %d of directory %q exceeds the limit %d", size, dir, limit)
+	}
+	return nil
+}
+
+// ForceRemoveAll removes dir recursively. When the plain removal fails,
+// the write permission is added back to the sub directories and the
+// removal is retried once, so left behind read-only content does not
+// keep the directory alive
+func ForceRemoveAll(dir string) error {
+	if err := os.RemoveAll(dir); err == nil {
+		return nil
+	}
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return os.Chmod(path, constant.DefaultRootDirMode)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
 // UnpackFile will unpack "src" file to "dest" directory
 // by using different compression method defined by "com"
 // The src file will be remove if set "rm" to true
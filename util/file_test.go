@@ -479,3 +479,37 @@ func TestUnpackFile(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDirSizeAndLimit(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("a", "aaaa"),
+		fs.WithDir("sub", fs.WithFile("b", "bbbbbb")))
+	defer dir.Remove()
+
+	size, err := GetDirSize(dir.Path())
+	assert.NilError(t, err)
+	assert.Equal(t, size, int64(10))
+
+	assert.NilError(t, CheckDirSizeLimit(dir.Path(), 10))
+	err = CheckDirSizeLimit(dir.Path(), 9)
+	assert.ErrorContains(t, err, "exceeds the limit")
+
+	// a dir removed under our feet counts as empty
+	size, err = GetDirSize(dir.Join("not-exist"))
+	assert.NilError(t, err)
+	assert.Equal(t, size, int64(0))
+}
+
+func TestForceRemoveAll(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithDir("sub", fs.WithFile("content", "data")))
+	// a read only sub directory blocks the plain removal
+	assert.NilError(t, os.Chmod(dir.Join("sub"), constant.DefaultReadOnlyFileMode))
+
+	assert.NilError(t, ForceRemoveAll(dir.Path()))
+	_, err := os.Stat(dir.Path())
+	assert.Assert(t, os.IsNotExist(err))
+
+	// removing a dir which does not exist is fine
+	assert.NilError(t, ForceRemoveAll(dir.Path()))
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: configurable layer copy parallelism for pull and push
+
+package util
+
+import (
+	"github.com/pkg/errors"
+)
+
+// maxCopyParallelism caps the configurable parallelism, more in-flight
+// blobs than that only adds memory pressure without speeding anything up
+const maxCopyParallelism = 128
+
+// maxParallelDownloads and maxParallelUploads bound the layers copied
+// concurrently during pull and push, they are set once from the daemon
+// configuration before any session starts, 0 keeps the library default
+var (
+	maxParallelDownloads uint
+	maxParallelUploads   uint
+)
+
+// SetCopyParallelism configures how many layers are copied concurrently
+// during pull (downloads) and push (uploads)
+func SetCopyParallelism(downloads, uploads int) error {
+	if downloads < 0 || downloads > maxCopyParallelism {
+		return errors.Errorf("max parallel downloads %d out of range [0, %d]", downloads, maxCopyParallelism)
+	}
+	if uploads < 0 || uploads > maxCopyParallelism {
+		return errors.Errorf("max parallel uploads %d out of range [0, %d]", uploads, maxCopyParallelism)
+	}
+	maxParallelDownloads = uint(downloads)
+	maxParallelUploads = uint(uploads)
+
+	return nil
+}
+
+// MaxParallelDownloads returns the configured pull parallelism, 0 keeps
+// the library default
+func MaxParallelDownloads() uint {
+	return maxParallelDownloads
+}
+
+// MaxParallelUploads returns the configured push parallelism, 0 keeps
+// the library default
+func MaxParallelUploads() uint {
+	return maxParallelUploads
+}
@@ -0,0 +1,40 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing copy parallelism setting
+
+package util
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSetCopyParallelism(t *testing.T) {
+	defer func() {
+		assert.NilError(t, SetCopyParallelism(0, 0))
+	}()
+
+	assert.NilError(t, SetCopyParallelism(4, 2))
+	assert.Equal(t, MaxParallelDownloads(), uint(4))
+	assert.Equal(t, MaxParallelUploads(), uint(2))
+
+	// 0 keeps the library default
+	assert.NilError(t, SetCopyParallelism(0, 0))
+	assert.Equal(t, MaxParallelDownloads(), uint(0))
+	assert.Equal(t, MaxParallelUploads(), uint(0))
+
+	err := SetCopyParallelism(-1, 0)
+	assert.ErrorContains(t, err, "max parallel downloads -1 out of range")
+	err = SetCopyParallelism(0, maxCopyParallelism+1)
+	assert.ErrorContains(t, err, "max parallel uploads 129 out of range")
+}
@@ -0,0 +1,87 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: retry with backoff for registry transfers
+
+package util
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// maxCopyRetries caps the configurable retry count, a transfer still
+// failing after that many attempts will not recover by trying harder
+const maxCopyRetries = 10
+
+// defaultCopyRetryBackoff is the delay before the first retry, it is
+// doubled after every failed attempt
+const defaultCopyRetryBackoff = time.Second
+
+// copyRetries and copyRetryBackoff control the retry behavior of the
+// registry transfers, they are set once from the daemon configuration
+// before any session starts, zero retries keeps transfers single-shot
+var (
+	copyRetries      int
+	copyRetryBackoff = defaultCopyRetryBackoff
+)
+
+// SetCopyRetry configures how often a failed registry transfer is
+// retried and the backoff before the first retry, an empty backoff
+// keeps the default
+func SetCopyRetry(retries int, backoff string) error {
+	if retries < 0 || retries > maxCopyRetries {
+		return errors.Errorf("copy retries %d out of range [0, %d]", retries, maxCopyRetries)
+	}
+	copyRetries = retries
+	if backoff == "" {
+		copyRetryBackoff = defaultCopyRetryBackoff
+		return nil
+	}
+
+	wait, err := time.ParseDuration(backoff)
+	if err != nil || wait <= 0 {
+		return errors.Errorf("copy retry backoff %q must be a positive duration like 1s", backoff)
+	}
+	copyRetryBackoff = wait
+
+	return nil
+}
+
+// CopyWithRetry runs a registry transfer and retries it with
+// exponential backoff when it fails. Blobs already transferred are
+// found in the destination on the next attempt, so a retry resumes
+// the transfer instead of restarting it from scratch
+func CopyWithRetry(ctx context.Context, action string, transfer func() error) error {
+	backoff := copyRetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := transfer()
+		if err == nil {
+			return nil
+		}
+		if attempt >= copyRetries {
+			return err
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		logrus.Warnf("%s failed, will retry %d more time(s) in %v: %v", action, copyRetries-attempt, backoff, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: This file is used for testing copy retry setting
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+)
+
+func TestSetCopyRetry(t *testing.T) {
+	defer func() {
+		assert.NilError(t, SetCopyRetry(0, ""))
+	}()
+
+	assert.NilError(t, SetCopyRetry(3, "10ms"))
+	assert.Equal(t, copyRetries, 3)
+
+	err := SetCopyRetry(-1, "")
+	assert.ErrorContains(t, err, "copy retries -1 out of range")
+	err = SetCopyRetry(maxCopyRetries+1, "")
+	assert.ErrorContains(t, err, "out of range")
+	err = SetCopyRetry(1, "soon")
+	assert.ErrorContains(t, err, `copy retry backoff "soon" must be a positive duration`)
+	err = SetCopyRetry(1, "-1s")
+	assert.ErrorContains(t, err, "must be a positive duration")
+}
+
+func TestCopyWithRetry(t *testing.T) {
+	defer func() {
+		assert.NilError(t, SetCopyRetry(0, ""))
+	}()
+	assert.NilError(t, SetCopyRetry(2, "1ms"))
+
+	// succeeds after one retry
+	attempts := 0
+	err := CopyWithRetry(context.Background(), "test transfer", func() error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("connection reset")
+		}
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, attempts, 2)
+
+	// gives up after the configured retries
+	attempts = 0
+	err = CopyWithRetry(context.Background(), "test transfer", func() error {
+		attempts++
+		return errors.New("connection reset")
+	})
+	assert.ErrorContains(t, err, "connection reset")
+	assert.Equal(t, attempts, 3)
+
+	// a canceled context stops the retries
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	attempts = 0
+	err = CopyWithRetry(ctx, "test transfer", func() error {
+		attempts++
+		return errors.New("connection reset")
+	})
+	assert.ErrorContains(t, err, "connection reset")
+	assert.Equal(t, attempts, 1)
+}
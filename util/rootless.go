@@ -0,0 +1,31 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: rootless mode related functions
+
+package util
+
+import (
+	"github.com/containers/storage/pkg/unshare"
+)
+
+// IsRootless tells whether the daemon was started by a non-root user,
+// it keeps returning true after the re-exec into the user namespace
+// turned the effective UID into 0
+func IsRootless() bool {
+	return unshare.IsRootless()
+}
+
+// RootlessUID returns the UID of the user who started the daemon in
+// the parent user namespace, or the current UID when run as root
+func RootlessUID() int {
+	return unshare.GetRootlessUID()
+}
@@ -0,0 +1,30 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: rootless mode related tests
+
+package util
+
+import (
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestIsRootless(t *testing.T) {
+	if os.Getenv("_CONTAINERS_USERNS_CONFIGURED") == "" {
+		assert.Equal(t, IsRootless(), os.Geteuid() != 0)
+	}
+	if os.Getenv("_CONTAINERS_ROOTLESS_UID") == "" {
+		assert.Equal(t, RootlessUID(), os.Getuid())
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: splitting and joining of large tarballs for removable media
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+
+	constant "isula.org/isula-build"
+)
+
+// SplitManifestSuffix is appended to the original file name to name
+// the manifest describing its chunks
+const SplitManifestSuffix = ".split.json"
+
+// SplitChunk is one piece of a split tarball
+type SplitChunk struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	Digest string `json:"digest"`
+}
+
+// SplitManifest describes the chunks a tarball was split into, it
+// lives next to them so the chunks can be verified and joined again
+type SplitManifest struct {
+	Name      string       `json:"name"`
+	TotalSize int64        `json:"totalSize"`
+	ChunkSize int64        `json:"chunkSize"`
+	Chunks    []SplitChunk `json:"chunks"`
+}
+
+// SplitFile splits the file at path into chunks of at most chunkSize
+// bytes next to it and writes a manifest describing them, returning
+// the manifest path, the original file is left untouched
+func SplitFile(path string, chunkSize int64) (string, error) {
+	if chunkSize <= 0 {
+		return "", errors.Errorf("invalid split chunk size %d", chunkSize)
+	}
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return "", errors.Wrapf(err, "open file %q for splitting failed", path)
+	}
+	defer f.Close() // nolint:errcheck
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	manifest := SplitManifest{
+		Name:      filepath.Base(path),
+		TotalSize: info.Size(),
+		ChunkSize: chunkSize,
+	}
+	for i := 0; ; i++ {
+		chunkName := fmt.Sprintf("%s.part%03d", filepath.Base(path), i)
+		chunkPath := filepath.Join(filepath.Dir(path), chunkName)
+		written, digested, cErr := writeChunk(chunkPath, f, chunkSize)
+		if cErr != nil {
+			return "", cErr
+		}
+		if written == 0 {
+			break
+		}
+		manifest.Chunks = append(manifest.Chunks, SplitChunk{Name: chunkName, Size: written, Digest: digested})
+		if written < chunkSize {
+			break
+		}
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+	manifestPath := path + SplitManifestSuffix
+	if err = ioutil.WriteFile(manifestPath, data, constant.DefaultRootFileMode); err != nil {
+		return "", errors.Wrapf(err, "write split manifest %q failed", manifestPath)
+	}
+
+	return manifestPath, nil
+}
+
+// writeChunk copies at most chunkSize bytes from r into a new file at
+// path and returns the written size and its digest, an empty chunk is
+// not kept
+func writeChunk(path string, r io.Reader, chunkSize int64) (int64, string, error) {
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, constant.DefaultRootFileMode)
+	if err != nil {
+		return 0, "", errors.Wrapf(err, "create chunk %q failed", path)
+	}
+	digester := digest.Canonical.Digester()
+	written, err := io.CopyN(io.MultiWriter(out, digester.Hash()), r, chunkSize)
+	if cErr := out.Close(); cErr != nil && err == nil {
+		err = cErr
+	}
+	if err != nil && err != io.EOF {
+		return 0, "", errors.Wrapf(err, "write chunk %q failed", path)
+	}
+	if written == 0 {
+		if rErr := os.Remove(path); rErr != nil {
+			return 0, "", rErr
+		}
+		return 0, "", nil
+	}
+
+	return written, digester.Digest().String(), nil
+}
+
+// JoinSplitFile reassembles the file a split manifest describes from
+// its chunks, verifying every chunk digest, and returns the path of
+// the joined file written next to the manifest
+func JoinSplitFile(manifestPath string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(manifestPath))
+	if err != nil {
+		return "", errors.Wrapf(err, "read split manifest %q failed", manifestPath)
+	}
+	var manifest SplitManifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return "", errors.Wrapf(err, "parse split manifest %q failed", manifestPath)
+	}
+	if manifest.Name == "" || manifest.Name != filepath.Base(manifest.Name) {
+		return "", errors.Errorf("invalid file name %q in split manifest", manifest.Name)
+	}
+
+	dir := filepath.Dir(manifestPath)
+	joinedPath := filepath.Join(dir, manifest.Name)
+	out, err := os.OpenFile(joinedPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, constant.DefaultRootFileMode)
+	if err != nil {
+		return "", errors.Wrapf(err, "create joined file %q failed", joinedPath)
+	}
+	defer out.Close() // nolint:errcheck
+
+	var total int64
+	for _, chunk := range manifest.Chunks {
+		if chunk.Name != filepath.Base(chunk.Name) {
+			return "", errors.Errorf("invalid chunk name %q in split manifest", chunk.Name)
+		}
+		if err = appendChunk(out, filepath.Join(dir, chunk.Name), chunk); err != nil {
+			if rErr := os.Remove(joinedPath); rErr != nil {
+				return "", rErr
+			}
+			return "", err
+		}
+		total += chunk.Size
+	}
+	if total != manifest.TotalSize {
+		if rErr := os.Remove(joinedPath); rErr != nil {
+			return "", rErr
+		}
+		return "", errors.Errorf("joined size %d does not match the %d recorded in the split manifest", total, manifest.TotalSize)
+	}
+
+	return joinedPath, nil
+}
+
+// appendChunk copies one verified chunk into the joined file
+func appendChunk(out io.Writer, path string, chunk SplitChunk) error {
+	in, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return errors.Wrapf(err, "open chunk %q failed", path)
+	}
+	defer in.Close() // nolint:errcheck
+	digester := digest.Canonical.Digester()
+	written, err := io.Copy(io.MultiWriter(out, digester.Hash()), in)
+	if err != nil {
+		return errors.Wrapf(err, "join chunk %q failed", path)
+	}
+	if written != chunk.Size {
+		return errors.Errorf("chunk %q has size %d, the split manifest records %d", path, written, chunk.Size)
+	}
+	if digester.Digest().String() != chunk.Digest {
+		return errors.Errorf("chunk %q does not match the digest recorded in the split manifest", path)
+	}
+
+	return nil
+}
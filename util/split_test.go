@@ -0,0 +1,97 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2022. All rights reserved.
+// isula-build licensed under the Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//     http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR
+// PURPOSE.
+// See the Mulan PSL v2 for more details.
+// Author: iSula Team
+// Create: 2022-08-29
+// Description: splitting and joining of large tarballs testing
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestSplitAndJoinFile(t *testing.T) {
+	content := bytes.Repeat([]byte("isula-build split tarball testing content\n"), 100)
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("image.tar", string(content)))
+	defer dir.Remove()
+	original := dir.Join("image.tar")
+
+	manifestPath, err := SplitFile(original, 1024)
+	assert.NilError(t, err)
+	assert.Equal(t, manifestPath, original+SplitManifestSuffix)
+
+	data, err := ioutil.ReadFile(manifestPath)
+	assert.NilError(t, err)
+	var manifest SplitManifest
+	assert.NilError(t, json.Unmarshal(data, &manifest))
+	assert.Equal(t, manifest.Name, "image.tar")
+	assert.Equal(t, manifest.TotalSize, int64(len(content)))
+	assert.Equal(t, len(manifest.Chunks), (len(content)+1023)/1024)
+	for _, chunk := range manifest.Chunks {
+		_, err := os.Stat(dir.Join(chunk.Name))
+		assert.NilError(t, err)
+	}
+
+	// the original is removed after splitting in the save flow, joining
+	// writes the file back next to the manifest
+	assert.NilError(t, os.Remove(original))
+	joinedPath, err := JoinSplitFile(manifestPath)
+	assert.NilError(t, err)
+	assert.Equal(t, joinedPath, original)
+	joined, err := ioutil.ReadFile(joinedPath)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, joined, content)
+}
+
+func TestSplitFileInvalidChunkSize(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("image.tar", "content"))
+	defer dir.Remove()
+
+	_, err := SplitFile(dir.Join("image.tar"), 0)
+	assert.ErrorContains(t, err, "invalid split chunk size")
+}
+
+func TestJoinSplitFileCorruptedChunk(t *testing.T) {
+	content := bytes.Repeat([]byte("isula-build split tarball testing content\n"), 100)
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("image.tar", string(content)))
+	defer dir.Remove()
+	original := dir.Join("image.tar")
+
+	manifestPath, err := SplitFile(original, 1024)
+	assert.NilError(t, err)
+	assert.NilError(t, os.Remove(original))
+	assert.NilError(t, ioutil.WriteFile(dir.Join("image.tar.part000"), bytes.Repeat([]byte("x"), 1024), 0600))
+
+	_, err = JoinSplitFile(manifestPath)
+	assert.ErrorContains(t, err, "does not match the digest")
+	_, statErr := os.Stat(original)
+	assert.Assert(t, os.IsNotExist(statErr))
+}
+
+func TestJoinSplitFileInvalidName(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	defer dir.Remove()
+	manifest := SplitManifest{Name: filepath.Join("..", "escape.tar")}
+	data, err := json.Marshal(manifest)
+	assert.NilError(t, err)
+	manifestPath := dir.Join("escape.tar" + SplitManifestSuffix)
+	assert.NilError(t, ioutil.WriteFile(manifestPath, data, 0600))
+
+	_, err = JoinSplitFile(manifestPath)
+	assert.ErrorContains(t, err, "invalid file name")
+}
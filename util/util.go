@@ -46,12 +46,34 @@ type LogFieldKey string
 // BuildDirKey is type used for BuildDir in build context
 type BuildDirKey string
 
+// CheckpointDirKey is type used for CheckpointDir in build context
+type CheckpointDirKey string
+
+// BuildCacheDirKey is type used for BuildCacheDir in build context
+type BuildCacheDirKey string
+
+// TarContextKey is type used for TarContext in build context
+type TarContextKey string
+
 const (
 	// LogKeySessionID describes the key field with sessionID for logrus
 	LogKeySessionID = "sessionID"
 
 	// BuildDir describes the key field with BuildDir in build context
 	BuildDir = "buildDir"
+
+	// CheckpointDir describes the key field with CheckpointDir in build context
+	CheckpointDir = "checkpointDir"
+
+	// BuildCacheDir describes the key field with BuildCacheDir in build context
+	BuildCacheDir = "buildCacheDir"
+
+	// TarContext describes the key field with the indexed context
+	// tarball in build context
+	TarContext = "tarContext"
+	// CacheMountsDir is the sub directory of the build cache dir holding
+	// the host directories backing RUN --mount cache mounts
+	CacheMountsDir = "mounts"
 )
 
 var (
@@ -332,13 +354,20 @@ func GenerateNonCryptoID() string {
 // IsSupportExporter returns true when the specific exporter is supported
 func IsSupportExporter(name string) bool {
 	exporters := map[string]bool{
-		constant.DockerTransport:        true,
-		constant.DockerArchiveTransport: true,
-		constant.DockerDaemonTransport:  true,
-		constant.OCITransport:           true,
-		constant.OCIArchiveTransport:    true,
-		constant.IsuladTransport:        true,
-		constant.ManifestTransport:      true,
+		constant.DockerTransport:            true,
+		constant.DockerArchiveTransport:     true,
+		constant.DockerDaemonTransport:      true,
+		constant.OCITransport:               true,
+		constant.OCIArchiveTransport:        true,
+		constant.IsuladTransport:            true,
+		constant.ContainerdTransport:        true,
+		constant.ContainersStorageTransport: true,
+		constant.ManifestTransport:          true,
+		constant.DiskImageTransport:         true,
+		constant.SquashfsTransport:          true,
+		constant.SysrootTransport:           true,
+		constant.LocalTransport:             true,
+		constant.EStargzTransport:           true,
 	}
 	_, ok := exporters[name]
 
@@ -355,12 +384,48 @@ func CheckImageFormat(format string) error {
 	}
 }
 
+// CheckCompressionFormat used to check if the layer compression is one
+// of gzip, zstd, zstd:chunked or none, the empty format is the default
+func CheckCompressionFormat(format string) error {
+	switch format {
+	case "", constant.CompressionNone, constant.CompressionGzip, constant.CompressionZstd, constant.CompressionZstdChunked:
+		return nil
+	default:
+		return errors.Errorf("wrong compression format %q provided, expected gzip, zstd, zstd:chunked or none", format)
+	}
+}
+
+// ParsePlatform parses a platform string like "linux/arm64" or
+// "linux/arm/v7" into its os, architecture and variant parts
+func ParsePlatform(platform string) (string, string, string, error) {
+	const archIdx, variantIdx, maxParts = 2, 3, 3
+	parts := strings.Split(platform, "/")
+	if len(parts) < archIdx || len(parts) > maxParts {
+		return "", "", "", errors.Errorf("invalid platform %q, expected os/arch or os/arch/variant", platform)
+	}
+	for _, part := range parts {
+		if strings.TrimSpace(part) == "" {
+			return "", "", "", errors.Errorf("invalid platform %q, expected os/arch or os/arch/variant", platform)
+		}
+	}
+
+	variant := ""
+	if len(parts) == variantIdx {
+		variant = parts[variantIdx-1]
+	}
+	return parts[0], parts[archIdx-1], variant, nil
+}
+
 // IsClientExporter used to determinate exporter whether need to send the image to client
 func IsClientExporter(exporter string) bool {
 	clientExporters := map[string]bool{
 		constant.DockerArchiveTransport: true,
 		constant.OCIArchiveTransport:    true,
 		constant.IsuladTransport:        true,
+		constant.DiskImageTransport:     true,
+		constant.SquashfsTransport:      true,
+		constant.SysrootTransport:       true,
+		constant.LocalTransport:         true,
 	}
 	_, ok := clientExporters[exporter]
 	return ok
@@ -334,6 +334,14 @@ func TestGenerateNonCryptoID(t *testing.T) {
 	}
 }
 
+func TestCheckCompressionFormat(t *testing.T) {
+	for _, format := range []string{"", constant.CompressionNone, constant.CompressionGzip, constant.CompressionZstd, constant.CompressionZstdChunked} {
+		assert.NilError(t, CheckCompressionFormat(format))
+	}
+	err := CheckCompressionFormat("lz4")
+	assert.ErrorContains(t, err, `wrong compression format "lz4" provided`)
+}
+
 func TestCheckImageFormat(t *testing.T) {
 	testcases := []struct {
 		name      string
@@ -392,6 +400,11 @@ func TestIsClientExporter(t *testing.T) {
 			exporter:   constant.IsuladTransport,
 			wantResult: true,
 		},
+		{
+			name:       "normal local rootfs exporter",
+			exporter:   constant.LocalTransport,
+			wantResult: true,
+		},
 		{
 			name:       "abnormal unknown",
 			exporter:   "unknown",
@@ -408,3 +421,33 @@ func TestIsClientExporter(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePlatform(t *testing.T) {
+	tests := []struct {
+		name        string
+		platform    string
+		wantOS      string
+		wantArch    string
+		wantVariant string
+		wantErr     bool
+	}{
+		{name: "os and arch", platform: "linux/arm64", wantOS: "linux", wantArch: "arm64"},
+		{name: "with variant", platform: "linux/arm/v7", wantOS: "linux", wantArch: "arm", wantVariant: "v7"},
+		{name: "missing arch", platform: "linux", wantErr: true},
+		{name: "empty part", platform: "linux//v7", wantErr: true},
+		{name: "too many parts", platform: "linux/arm/v7/extra", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOS, gotArch, gotVariant, err := ParsePlatform(tt.platform)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "invalid platform")
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, gotOS, tt.wantOS)
+			assert.Equal(t, gotArch, tt.wantArch)
+			assert.Equal(t, gotVariant, tt.wantVariant)
+		})
+	}
+}